@@ -1,5 +1,9 @@
 package meshx
 
+import (
+	"math"
+)
+
 // Axis aligned bounding box.
 type AABB struct {
 	Center   Vector
@@ -97,3 +101,43 @@ func (a AABB) IntersectsAABB(query AABB) bool {
 		aMin[2] <= qMax[2] &&
 		aMax[2] >= qMin[2]
 }
+
+// Compute the closest point in the AABB to a point.
+func (a AABB) ClosestPoint(point Vector) Vector {
+	minBound := a.GetMinBound()
+	maxBound := a.GetMaxBound()
+
+	for i := 0; i < 3; i++ {
+		point[i] = max(minBound[i], min(maxBound[i], point[i]))
+	}
+
+	return point
+}
+
+// Implement the IntersectsCylinder interface.
+func (a AABB) IntersectsCylinder(query Cylinder) bool {
+	return query.IntersectsAABB(a)
+}
+
+// Implement the IntersectsCapsule interface.
+func (a AABB) IntersectsCapsule(query Capsule) bool {
+	return query.IntersectsAABB(a)
+}
+
+// Compute the distance to a point. The distance is zero inside the AABB.
+func (a AABB) DistanceTo(point Vector) float64 {
+	return a.ClosestPoint(point).Sub(point).Mag()
+}
+
+// Compute the distance to another AABB. The distance is zero if they
+// intersect.
+func (a AABB) DistanceToAABB(query AABB) float64 {
+	var distance float64
+
+	for i := 0; i < 3; i++ {
+		gap := math.Abs(a.Center[i]-query.Center[i]) - a.HalfSize[i] - query.HalfSize[i]
+		distance += max(0, gap) * max(0, gap)
+	}
+
+	return math.Sqrt(distance)
+}
@@ -0,0 +1,46 @@
+package meshx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the closest point of an AABB inside, beside faces, edges, and corners.
+func TestAABBClosestPoint(t *testing.T) {
+	aabb := NewAABBFromBounds(NewVector(0, 0, 0), NewVector(1, 2, 3))
+
+	cases := [][2]Vector{
+		{NewVector(0.5, 0.5, 0.5), NewVector(0.5, 0.5, 0.5)},
+		{NewVector(1, 2, 3), NewVector(1, 2, 3)},
+		{NewVector(-1, 1, 1), NewVector(0, 1, 1)},
+		{NewVector(0.5, 5, 1), NewVector(0.5, 2, 1)},
+		{NewVector(2, 3, 1), NewVector(1, 2, 1)},
+		{NewVector(-1, 1, -1), NewVector(0, 1, 0)},
+		{NewVector(-1, -1, -1), NewVector(0, 0, 0)},
+		{NewVector(4, 5, 6), NewVector(1, 2, 3)},
+	}
+
+	for _, c := range cases {
+		closest := aabb.ClosestPoint(c[0])
+		assert.Equal(t, c[1], closest)
+		assert.InDelta(t, c[0].Sub(c[1]).Mag(), aabb.DistanceTo(c[0]), 1e-12)
+	}
+}
+
+// Test the distance to a corner of an AABB.
+func TestAABBDistanceToCorner(t *testing.T) {
+	aabb := NewAABB(NewVector(0, 0, 0), NewVector(1, 1, 1))
+	assert.InDelta(t, math.Sqrt(3), aabb.DistanceTo(NewVector(2, 2, 2)), 1e-12)
+	assert.Equal(t, 0.0, aabb.DistanceTo(NewVector(0, 0, 0)))
+}
+
+// Test the distance between two AABBs.
+func TestAABBDistanceToAABB(t *testing.T) {
+	aabb := NewAABB(NewVector(0, 0, 0), NewVector(1, 1, 1))
+
+	assert.Equal(t, 0.0, aabb.DistanceToAABB(NewAABB(NewVector(1.5, 0, 0), NewVector(1, 1, 1))))
+	assert.InDelta(t, 1.0, aabb.DistanceToAABB(NewAABB(NewVector(3, 0.5, 0), NewVector(1, 1, 1))), 1e-12)
+	assert.InDelta(t, math.Sqrt(3), aabb.DistanceToAABB(NewAABB(NewVector(3, 3, 3), NewVector(1, 1, 1))), 1e-12)
+}
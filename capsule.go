@@ -0,0 +1,42 @@
+package meshx
+
+// Solid capsule (swept sphere) in three-dimensional Cartesian space defined
+// by the end points of its axis and its radius.
+type Capsule struct {
+	P      Vector
+	Q      Vector
+	Radius float64
+}
+
+// Construct a Capsule from the end points of its axis and its radius.
+func NewCapsule(p, q Vector, radius float64) Capsule {
+	return Capsule{p, q, radius}
+}
+
+// Compute the bounding AABB.
+func (c Capsule) GetAABB() AABB {
+	minBound := NewVector(min(c.P[0], c.Q[0]), min(c.P[1], c.Q[1]), min(c.P[2], c.Q[2]))
+	maxBound := NewVector(max(c.P[0], c.Q[0]), max(c.P[1], c.Q[1]), max(c.P[2], c.Q[2]))
+	return NewAABBFromBounds(minBound.SubScalar(c.Radius), maxBound.AddScalar(c.Radius))
+}
+
+// Implement the IntersectsAABB interface.
+func (c Capsule) IntersectsAABB(query AABB) bool {
+	return gjkIntersects(c.support, query.support)
+}
+
+// Implement the IntersectsTriangle interface.
+func (c Capsule) IntersectsTriangle(query Triangle) bool {
+	return gjkIntersects(c.support, query.support)
+}
+
+// Implement the support function of a capsule.
+func (c Capsule) support(d Vector) Vector {
+	p := NewSegment(c.P, c.Q).support(d)
+
+	if mag := d.Mag(); mag > 0 {
+		p = p.Add(d.MulScalar(c.Radius / mag))
+	}
+
+	return p
+}
@@ -0,0 +1,110 @@
+//go:build js && wasm
+
+// Command meshx-wasm exposes the mesh processing library to JavaScript when
+// compiled to WebAssembly:
+//
+//	GOOS=js GOARCH=wasm go build -o meshx.wasm ./cmd/meshx-wasm
+//
+// Once loaded with wasm_exec.js, the global meshx object has the functions:
+//
+//	meshx.convert(data, from, to) // {data: Uint8Array} or {error: string}
+//	meshx.info(data, format)      // {vertices, faces, ...} or {error: string}
+//
+// The data is a Uint8Array of a mesh file and the formats are file
+// extensions (e.g. ".obj" or ".glb", see pipeline.ReadMesh and
+// pipeline.WriteMesh). No file system is used.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"syscall/js"
+
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/ajcurley/meshx-go/pipeline"
+)
+
+var (
+	ErrInvalidArguments = errors.New("invalid arguments")
+)
+
+func main() {
+	js.Global().Set("meshx", js.ValueOf(map[string]any{
+		"convert": js.FuncOf(convert),
+		"info":    js.FuncOf(info),
+	}))
+
+	select {}
+}
+
+// Convert a mesh between file formats.
+func convert(this js.Value, args []js.Value) any {
+	if len(args) != 3 {
+		return toError(ErrInvalidArguments)
+	}
+
+	mesh, err := readMesh(args[0], args[1].String())
+	if err != nil {
+		return toError(err)
+	}
+
+	var buffer bytes.Buffer
+
+	if err := pipeline.WriteMesh(&buffer, mesh, args[2].String()); err != nil {
+		return toError(err)
+	}
+
+	data := js.Global().Get("Uint8Array").New(buffer.Len())
+	js.CopyBytesToJS(data, buffer.Bytes())
+
+	return map[string]any{"data": data}
+}
+
+// Get the statistics of a mesh.
+func info(this js.Value, args []js.Value) any {
+	if len(args) != 2 {
+		return toError(ErrInvalidArguments)
+	}
+
+	mesh, err := readMesh(args[0], args[1].String())
+	if err != nil {
+		return toError(err)
+	}
+
+	aabb := mesh.GetAABB()
+	lower, upper := aabb.GetMinBound(), aabb.GetMaxBound()
+	patches := make([]any, mesh.GetNumberOfPatches())
+
+	for i := range patches {
+		patches[i] = mesh.GetPatch(i).Name
+	}
+
+	return map[string]any{
+		"vertices":      mesh.GetNumberOfVertices(),
+		"faces":         mesh.GetNumberOfFaces(),
+		"components":    len(mesh.GetComponents()),
+		"boundaryLoops": len(mesh.GetBoundaryLoops()),
+		"closed":        mesh.IsClosed(),
+		"consistent":    mesh.IsConsistent(),
+		"minBound":      []any{lower[0], lower[1], lower[2]},
+		"maxBound":      []any{upper[0], upper[1], upper[2]},
+		"patches":       patches,
+	}
+}
+
+// Read a mesh from a Uint8Array by its format.
+func readMesh(value js.Value, format string) (*halfedge.HalfEdgeMesh, error) {
+	if value.Type() != js.TypeObject {
+		return nil, ErrInvalidArguments
+	}
+
+	data := make([]byte, value.Get("length").Int())
+	js.CopyBytesToGo(data, value)
+
+	return pipeline.ReadMesh(bytes.NewReader(data), format)
+}
+
+// Convert an error to a result.
+func toError(err error) any {
+	return map[string]any{"error": err.Error()}
+}
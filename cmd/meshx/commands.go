@@ -0,0 +1,329 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/ajcurley/meshx-go/outofcore"
+	"github.com/ajcurley/meshx-go/pipeline"
+)
+
+// Parse the flags of a command requiring a number of positional arguments.
+func parseFlags(flags *flag.FlagSet, args []string, nArgs int) ([]string, error) {
+	flags.SetOutput(io.Discard)
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if flags.NArg() != nArgs {
+		return nil, ErrInvalidArguments
+	}
+
+	return flags.Args(), nil
+}
+
+// Convert a mesh between file formats.
+func runConvert(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	outOfCore := flags.Bool("out-of-core", false, "stream the mesh through temporary files")
+	weld := flags.Float64("weld", -1, "weld tolerance (out of core only)")
+
+	paths, err := parseFlags(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	if *outOfCore {
+		return convertOutOfCore(paths[0], paths[1], *weld, stdout)
+	} else if *weld >= 0 {
+		return ErrInvalidArguments
+	}
+
+	options := halfedge.HalfEdgeMeshOptions{DeferAdjacency: true}
+
+	mesh, err := pipeline.ReadMeshFromPathWithOptions(paths[0], options)
+	if err != nil {
+		return err
+	}
+
+	return pipeline.WriteMeshToPath(mesh, paths[1])
+}
+
+// Report the diagnostics of a mesh.
+func runInfo(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("info", flag.ContinueOnError)
+	outOfCore := flags.Bool("out-of-core", false, "stream the mesh through temporary files")
+
+	paths, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if *outOfCore {
+		return infoOutOfCore(paths[0], stdout)
+	}
+
+	mesh, err := pipeline.ReadMeshFromPath(paths[0])
+	if err != nil {
+		return err
+	}
+
+	aabb := mesh.GetAABB()
+	lower := aabb.GetMinBound()
+	upper := aabb.GetMaxBound()
+
+	fmt.Fprintf(stdout, "vertices:       %d\n", mesh.GetNumberOfVertices())
+	fmt.Fprintf(stdout, "faces:          %d\n", mesh.GetNumberOfFaces())
+	fmt.Fprintf(stdout, "half edges:     %d\n", mesh.GetNumberOfHalfEdges())
+	fmt.Fprintf(stdout, "components:     %d\n", len(mesh.GetComponents()))
+	fmt.Fprintf(stdout, "boundary loops: %d\n", len(mesh.GetBoundaryLoops()))
+	fmt.Fprintf(stdout, "closed:         %t\n", mesh.IsClosed())
+	fmt.Fprintf(stdout, "consistent:     %t\n", mesh.IsConsistent())
+	fmt.Fprintf(stdout, "bounds:         [%g %g %g] [%g %g %g]\n", lower[0], lower[1], lower[2], upper[0], upper[1], upper[2])
+	fmt.Fprintf(stdout, "patches:        %d\n", mesh.GetNumberOfPatches())
+
+	for i := range mesh.GetNumberOfPatches() {
+		fmt.Fprintf(stdout, "  %s: %d faces\n", mesh.GetPatch(i).Name, len(mesh.GetPatchFaces(i)))
+	}
+
+	return nil
+}
+
+// Open an OBJ file path to read in chunks.
+func openOBJChunkReader(path string) (*outofcore.OBJChunkReader, *os.File, error) {
+	if lower := strings.ToLower(path); !strings.HasSuffix(lower, ".obj") && !strings.HasSuffix(lower, ".obj.gz") {
+		return nil, nil, pipeline.ErrUnsupportedFormat
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader, err := outofcore.NewOBJChunkReader(file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return reader, file, nil
+}
+
+// Convert (and optionally weld) an OBJ file to an OBJ or PLY file out of
+// core.
+func convertOutOfCore(input, output string, weld float64, stdout io.Writer) error {
+	lower := strings.ToLower(output)
+
+	if !strings.HasSuffix(lower, ".obj") && !strings.HasSuffix(lower, ".ply") {
+		return pipeline.ErrUnsupportedFormat
+	}
+
+	reader, in, err := openOBJChunkReader(input)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var writer outofcore.ChunkWriter = outofcore.NewOBJChunkWriter(out)
+
+	if strings.HasSuffix(lower, ".ply") {
+		writer = outofcore.NewPLYChunkWriter(out, "")
+	}
+
+	if weld < 0 {
+		return outofcore.Convert(reader, writer)
+	}
+
+	report, err := outofcore.Weld(reader, writer, weld, outofcore.Options{})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "welded vertices:  %d\n", report.WeldedVertices)
+	fmt.Fprintf(stdout, "degenerate faces: %d\n", report.DegenerateFaces)
+
+	return nil
+}
+
+// Report the diagnostics of an OBJ file computed out of core.
+func infoOutOfCore(path string, stdout io.Writer) error {
+	reader, file, err := openOBJChunkReader(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	diagnosis, err := outofcore.Diagnose(reader, outofcore.Options{})
+	if err != nil {
+		return err
+	}
+
+	lower := diagnosis.MinBound
+	upper := diagnosis.MaxBound
+
+	fmt.Fprintf(stdout, "vertices:           %d\n", diagnosis.NumberOfVertices)
+	fmt.Fprintf(stdout, "faces:              %d\n", diagnosis.NumberOfFaces)
+	fmt.Fprintf(stdout, "edges:              %d\n", diagnosis.NumberOfEdges)
+	fmt.Fprintf(stdout, "boundary edges:     %d\n", diagnosis.NumberOfBoundaryEdges)
+	fmt.Fprintf(stdout, "non-manifold edges: %d\n", diagnosis.NumberOfNonManifoldEdges)
+	fmt.Fprintf(stdout, "inconsistent edges: %d\n", diagnosis.NumberOfInconsistentEdges)
+	fmt.Fprintf(stdout, "degenerate faces:   %d\n", diagnosis.NumberOfDegenerateFaces)
+	fmt.Fprintf(stdout, "unused vertices:    %d\n", diagnosis.NumberOfUnusedVertices)
+	fmt.Fprintf(stdout, "missing vertices:   %d\n", diagnosis.NumberOfMissingVertices)
+	fmt.Fprintf(stdout, "closed:             %t\n", diagnosis.IsClosed())
+	fmt.Fprintf(stdout, "consistent:         %t\n", diagnosis.IsConsistent())
+	fmt.Fprintf(stdout, "bounds:             [%g %g %g] [%g %g %g]\n", lower[0], lower[1], lower[2], upper[0], upper[1], upper[2])
+	fmt.Fprintf(stdout, "patches:            %d\n", diagnosis.NumberOfPatches)
+
+	return nil
+}
+
+// Extract the patches of a mesh by name.
+func runExtract(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("extract", flag.ContinueOnError)
+	names := flags.String("patches", "", "comma separated patch names")
+
+	paths, err := parseFlags(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	if *names == "" {
+		return ErrInvalidArguments
+	}
+
+	mesh, err := pipeline.ReadMeshFromPath(paths[0])
+	if err != nil {
+		return err
+	}
+
+	patches := make([]int, 0)
+
+	for _, name := range strings.Split(*names, ",") {
+		found := false
+
+		for i := range mesh.GetNumberOfPatches() {
+			if mesh.GetPatch(i).Name == name {
+				patches = append(patches, i)
+				found = true
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("patch not found: %s", name)
+		}
+	}
+
+	return pipeline.WriteMeshToPath(mesh.ExtractPatches(patches), paths[1])
+}
+
+// Orient the faces of each component of a mesh consistently.
+func runOrient(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("orient", flag.ContinueOnError)
+
+	paths, err := parseFlags(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	mesh, err := pipeline.ReadMeshFromPath(paths[0])
+	if err != nil {
+		return err
+	}
+
+	mesh.Orient()
+
+	return pipeline.WriteMeshToPath(mesh, paths[1])
+}
+
+// Decimate a mesh to a target number of triangles.
+func runDecimate(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("decimate", flag.ContinueOnError)
+	faces := flags.Int("faces", 0, "target number of faces")
+	ratio := flags.Float64("ratio", 0, "target ratio of faces (0 to 1)")
+
+	paths, err := parseFlags(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	if (*faces <= 0) == (*ratio <= 0) || *ratio > 1 {
+		return ErrInvalidArguments
+	}
+
+	mesh, err := pipeline.ReadMeshFromPath(paths[0])
+	if err != nil {
+		return err
+	}
+
+	targetFaces := *faces
+
+	if *ratio > 0 {
+		targetFaces = int(math.Round(*ratio * float64(mesh.GetNumberOfFaces())))
+	}
+
+	result, err := pipeline.Decimate(mesh, targetFaces)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "faces: %d -> %d\n", mesh.GetNumberOfFaces(), result.GetNumberOfFaces())
+
+	return pipeline.WriteMeshToPath(result, paths[1])
+}
+
+// Compute the feature edges of a mesh and write them as lines to an OBJ file.
+func runFeatures(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("features", flag.ContinueOnError)
+	angle := flags.Float64("angle", 30, "feature angle threshold (degrees)")
+
+	paths, err := parseFlags(flags, args, 2)
+	if err != nil {
+		return err
+	}
+
+	if output := strings.ToLower(paths[1]); !strings.HasSuffix(output, ".obj") && !strings.HasSuffix(output, ".obj.gz") {
+		return pipeline.ErrUnsupportedFormat
+	}
+
+	mesh, err := pipeline.ReadMeshFromPath(paths[0])
+	if err != nil {
+		return err
+	}
+
+	mesh.ComputeFeatureEdges(*angle * math.Pi / 180)
+	fmt.Fprintf(stdout, "feature half edges: %d\n", len(mesh.GetFeatureEdges()))
+
+	return mesh.WriteOBJFeatureEdgesToPath(paths[1])
+}
+
+// Run a pipeline of operations from a JSON or YAML file logging each step.
+func runPipeline(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+
+	paths, err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	p, err := pipeline.ReadPipelineFromPath(paths[0])
+	if err != nil {
+		return err
+	}
+
+	_, err = p.Run(slog.New(slog.NewTextHandler(stdout, nil)))
+	return err
+}
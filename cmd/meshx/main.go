@@ -0,0 +1,117 @@
+// Command meshx exposes the mesh processing library on the command line.
+//
+// Usage:
+//
+//	meshx <command> [flags] <arguments>
+//
+// The mesh file format is inferred from the file extension: .obj (and
+// .obj.gz), .ply, .msh (Gmsh), .mxcz (compressed), .drc (Draco) and .mxpm
+// (progressive, read only). Meshes can additionally be written to .vtp files.
+// Out of core (-out-of-core), meshes larger than memory are read from .obj
+// files and written to .obj or .ply files.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+var (
+	ErrUnknownCommand   = errors.New("unknown command")
+	ErrInvalidArguments = errors.New("invalid arguments")
+)
+
+// Command of the CLI.
+type command struct {
+	usage       string
+	description string
+	run         func(args []string, stdout io.Writer) error
+}
+
+// Commands of the CLI by name.
+var commands = map[string]command{
+	"convert": {
+		usage:       "convert [-out-of-core [-weld <tolerance>]] <input> <output>",
+		description: "Convert a mesh between file formats.",
+		run:         runConvert,
+	},
+	"info": {
+		usage:       "info [-out-of-core] <input>",
+		description: "Report the diagnostics of a mesh.",
+		run:         runInfo,
+	},
+	"extract": {
+		usage:       "extract -patches <name,...> <input> <output>",
+		description: "Extract the patches of a mesh by name.",
+		run:         runExtract,
+	},
+	"orient": {
+		usage:       "orient <input> <output>",
+		description: "Orient the faces of each component consistently.",
+		run:         runOrient,
+	},
+	"decimate": {
+		usage:       "decimate (-faces <count> | -ratio <ratio>) <input> <output>",
+		description: "Decimate a mesh to a target number of triangles.",
+		run:         runDecimate,
+	},
+	"run": {
+		usage:       "run <pipeline>",
+		description: "Run a pipeline of operations from a JSON or YAML file.",
+		run:         runPipeline,
+	},
+	"features": {
+		usage:       "features [-angle <degrees>] <input> <output>",
+		description: "Compute the feature edges of a mesh and write them as lines.",
+		run:         runFeatures,
+	},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "meshx: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Run the command named by the first argument.
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		writeUsage(stderr)
+		return nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		writeUsage(stderr)
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, args[0])
+	}
+
+	if err := cmd.run(args[1:], stdout); err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	return nil
+}
+
+// Write the usage of every command.
+func writeUsage(writer io.Writer) {
+	names := make([]string, 0, len(commands))
+
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fmt.Fprintln(writer, "Usage: meshx <command> [flags] <arguments>")
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "Commands:")
+
+	for _, name := range names {
+		fmt.Fprintf(writer, "  %-60s %s\n", commands[name].usage, commands[name].description)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ajcurley/meshx-go/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test converting a mesh between each supported file format.
+func TestRunConvert(t *testing.T) {
+	dir := t.TempDir()
+	input := "../../testdata/box.patches.obj"
+
+	for _, name := range []string{"box.ply", "box.msh", "box.mxcz", "box.obj.gz", "box.drc"} {
+		path := filepath.Join(dir, name)
+		assert.Empty(t, run([]string{"convert", input, path}, &bytes.Buffer{}, &bytes.Buffer{}))
+
+		mesh, err := pipeline.ReadMeshFromPath(path)
+		assert.Empty(t, err)
+		assert.Equal(t, 8, mesh.GetNumberOfVertices())
+	}
+
+	err := run([]string{"convert", input, filepath.Join(dir, "box.stl")}, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, pipeline.ErrUnsupportedFormat)
+}
+
+// Test reporting the diagnostics of a mesh.
+func TestRunInfo(t *testing.T) {
+	var stdout bytes.Buffer
+	assert.Empty(t, run([]string{"info", "../../testdata/box.patches.obj"}, &stdout, &bytes.Buffer{}))
+	assert.Contains(t, stdout.String(), "faces:          7\n")
+	assert.Contains(t, stdout.String(), "closed:         true\n")
+	assert.Contains(t, stdout.String(), "  back: 2 faces\n")
+}
+
+// Test converting, welding and diagnosing a mesh out of core.
+func TestRunOutOfCore(t *testing.T) {
+	dir := t.TempDir()
+	input := "../../testdata/box.patches.obj"
+
+	for _, name := range []string{"box.obj", "box.ply"} {
+		path := filepath.Join(dir, name)
+		assert.Empty(t, run([]string{"convert", "-out-of-core", input, path}, &bytes.Buffer{}, &bytes.Buffer{}))
+
+		mesh, err := pipeline.ReadMeshFromPath(path)
+		assert.Empty(t, err)
+		assert.Equal(t, 8, mesh.GetNumberOfVertices())
+		assert.Equal(t, 6, mesh.GetNumberOfPatches())
+	}
+
+	var stdout bytes.Buffer
+	args := []string{"convert", "-out-of-core", "-weld", "0.5", input, filepath.Join(dir, "welded.obj")}
+	assert.Empty(t, run(args, &stdout, &bytes.Buffer{}))
+	assert.Contains(t, stdout.String(), "welded vertices:  0\n")
+
+	stdout.Reset()
+	assert.Empty(t, run([]string{"info", "-out-of-core", input}, &stdout, &bytes.Buffer{}))
+	assert.Contains(t, stdout.String(), "faces:              7\n")
+	assert.Contains(t, stdout.String(), "closed:             true\n")
+
+	err := run([]string{"convert", "-weld", "0.5", input, filepath.Join(dir, "box.obj")}, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrInvalidArguments)
+
+	err = run([]string{"convert", "-out-of-core", input, filepath.Join(dir, "box.msh")}, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, pipeline.ErrUnsupportedFormat)
+}
+
+// Test extracting patches by name.
+func TestRunExtract(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "extract.obj")
+	args := []string{"extract", "-patches", "back,top", "../../testdata/box.patches.obj", path}
+	assert.Empty(t, run(args, &bytes.Buffer{}, &bytes.Buffer{}))
+
+	mesh, err := pipeline.ReadMeshFromPath(path)
+	assert.Empty(t, err)
+	assert.Equal(t, 3, mesh.GetNumberOfFaces())
+	assert.Equal(t, 2, mesh.GetNumberOfPatches())
+
+	args = []string{"extract", "-patches", "missing", "../../testdata/box.patches.obj", path}
+	assert.Error(t, run(args, &bytes.Buffer{}, &bytes.Buffer{}))
+}
+
+// Test decimating a mesh.
+func TestRunDecimate(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "box.obj")
+	output := filepath.Join(dir, "decimated.obj")
+
+	data, err := os.ReadFile("../../testdata/box.obj")
+	assert.Empty(t, err)
+	assert.Empty(t, os.WriteFile(input, data, 0644))
+
+	var stdout bytes.Buffer
+	assert.Empty(t, run([]string{"decimate", "-faces", "8", input, output}, &stdout, &bytes.Buffer{}))
+	assert.Contains(t, stdout.String(), "faces: 12 -> ")
+
+	mesh, err := pipeline.ReadMeshFromPath(output)
+	assert.Empty(t, err)
+	assert.LessOrEqual(t, mesh.GetNumberOfFaces(), 12)
+
+	err = run([]string{"decimate", input, output}, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrInvalidArguments)
+}
+
+// Test an unknown command.
+func TestRunUnknownCommand(t *testing.T) {
+	var stderr bytes.Buffer
+	assert.ErrorIs(t, run([]string{"boolean"}, &bytes.Buffer{}, &stderr), ErrUnknownCommand)
+	assert.Contains(t, stderr.String(), "Usage: meshx")
+}
+
+// Test computing the feature edges of an oriented mesh.
+func TestRunFeatures(t *testing.T) {
+	dir := t.TempDir()
+	oriented := filepath.Join(dir, "oriented.obj")
+	args := []string{"orient", "../../testdata/box.patches.obj", oriented}
+	assert.Empty(t, run(args, &bytes.Buffer{}, &bytes.Buffer{}))
+
+	var stdout bytes.Buffer
+	args = []string{"features", "-angle", "45", oriented, filepath.Join(dir, "features.obj")}
+	assert.Empty(t, run(args, &stdout, &bytes.Buffer{}))
+	assert.Contains(t, stdout.String(), "feature half edges: 24\n")
+}
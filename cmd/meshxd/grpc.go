@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/ajcurley/meshx-go/outofcore"
+	"github.com/ajcurley/meshx-go/pipeline"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	// Size of the chunks of a streamed download in bytes.
+	grpcChunkSize = 1 << 16
+)
+
+// Streams of the gRPC service implemented by the server (see meshxd.proto).
+// The service is described by hand since it only uses well-known messages,
+// and its unary methods are served as streams of a single message each way.
+type meshesServer interface {
+	uploadStream(stream grpc.ServerStream) error
+	downloadStream(stream grpc.ServerStream) error
+	diagnosticsStream(stream grpc.ServerStream) error
+	extractStream(stream grpc.ServerStream) error
+	decimateStream(stream grpc.ServerStream) error
+	booleanStream(stream grpc.ServerStream) error
+	convertStream(stream grpc.ServerStream) error
+	diagnoseStream(stream grpc.ServerStream) error
+}
+
+// Description of the gRPC service.
+var meshesServiceDesc = grpc.ServiceDesc{
+	ServiceName: "meshx.Meshes",
+	HandlerType: (*meshesServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Upload",
+			Handler:       func(srv any, stream grpc.ServerStream) error { return srv.(meshesServer).uploadStream(stream) },
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Download",
+			Handler:       func(srv any, stream grpc.ServerStream) error { return srv.(meshesServer).downloadStream(stream) },
+			ServerStreams: true,
+		},
+		{
+			StreamName: "Diagnostics",
+			Handler:    func(srv any, stream grpc.ServerStream) error { return srv.(meshesServer).diagnosticsStream(stream) },
+		},
+		{
+			StreamName: "Extract",
+			Handler:    func(srv any, stream grpc.ServerStream) error { return srv.(meshesServer).extractStream(stream) },
+		},
+		{
+			StreamName: "Decimate",
+			Handler:    func(srv any, stream grpc.ServerStream) error { return srv.(meshesServer).decimateStream(stream) },
+		},
+		{
+			StreamName: "Boolean",
+			Handler:    func(srv any, stream grpc.ServerStream) error { return srv.(meshesServer).booleanStream(stream) },
+		},
+		{
+			StreamName:    "Convert",
+			Handler:       func(srv any, stream grpc.ServerStream) error { return srv.(meshesServer).convertStream(stream) },
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Diagnose",
+			Handler:       func(srv any, stream grpc.ServerStream) error { return srv.(meshesServer).diagnoseStream(stream) },
+			ClientStreams: true,
+		},
+	},
+	Metadata: "meshxd.proto",
+}
+
+// Construct a gRPC server of the meshes of a server.
+func newGRPCServer(s *server) *grpc.Server {
+	g := grpc.NewServer()
+	g.RegisterService(&meshesServiceDesc, s)
+	return g
+}
+
+// Upload a mesh streamed in chunks and send its summary.
+func (s *server) uploadStream(stream grpc.ServerStream) error {
+	reader := &grpcChunkReader{stream: stream, remaining: s.options.MaxUpload}
+
+	mesh, err := pipeline.ReadMesh(reader, getStreamMetadata(stream, "format", ""))
+	if err != nil {
+		return getGRPCError(err)
+	}
+
+	return sendStruct(stream, s.addMesh(mesh))
+}
+
+// Download a mesh streamed in chunks.
+func (s *server) downloadStream(stream grpc.ServerStream) error {
+	var id wrapperspb.StringValue
+
+	if err := stream.RecvMsg(&id); err != nil {
+		return err
+	}
+
+	mesh, release, err := s.readMesh(id.Value)
+	if err != nil {
+		return getGRPCError(err)
+	}
+	defer release()
+
+	return writeStreamMesh(stream, mesh, getStreamMetadata(stream, "format", ".obj"))
+}
+
+// Diagnose a stored mesh by its identifier.
+func (s *server) diagnosticsStream(stream grpc.ServerStream) error {
+	var id wrapperspb.StringValue
+
+	if err := stream.RecvMsg(&id); err != nil {
+		return err
+	}
+
+	mesh, release, err := s.readMesh(id.Value)
+	if err != nil {
+		return getGRPCError(err)
+	}
+	defer release()
+
+	return sendStruct(stream, diagnoseMesh(mesh))
+}
+
+// Extract the patches of a stored mesh by name (id and patches) as a new
+// mesh and send its summary.
+func (s *server) extractStream(stream grpc.ServerStream) error {
+	var request structpb.Struct
+
+	if err := stream.RecvMsg(&request); err != nil {
+		return err
+	}
+
+	names := make([]string, 0)
+
+	for _, value := range request.Fields["patches"].GetListValue().GetValues() {
+		names = append(names, value.GetStringValue())
+	}
+
+	if len(names) == 0 {
+		return getGRPCError(fmt.Errorf("%w: patches", ErrInvalidParameter))
+	}
+
+	summary, err := s.extractMesh(request.Fields["id"].GetStringValue(), names)
+	if err != nil {
+		return getGRPCError(err)
+	}
+
+	return sendStruct(stream, summary)
+}
+
+// Decimate a stored mesh to a target number (faces) or ratio (ratio) of
+// triangles as a new mesh and send its summary.
+func (s *server) decimateStream(stream grpc.ServerStream) error {
+	var request structpb.Struct
+
+	if err := stream.RecvMsg(&request); err != nil {
+		return err
+	}
+
+	faces := request.Fields["faces"].GetNumberValue()
+
+	if faces != math.Trunc(faces) || faces < 0 {
+		return getGRPCError(fmt.Errorf("%w: faces", ErrInvalidParameter))
+	}
+
+	summary, err := s.decimateMesh(request.Fields["id"].GetStringValue(), int(faces), request.Fields["ratio"].GetNumberValue())
+	if err != nil {
+		return getGRPCError(err)
+	}
+
+	return sendStruct(stream, summary)
+}
+
+// Compute a Boolean operation (operation) of two stored meshes (a and b)
+// with a relative tolerance (tolerance) as a new mesh and send its summary.
+func (s *server) booleanStream(stream grpc.ServerStream) error {
+	var request structpb.Struct
+
+	if err := stream.RecvMsg(&request); err != nil {
+		return err
+	}
+
+	summary, err := s.booleanMeshes(
+		request.Fields["a"].GetStringValue(),
+		request.Fields["b"].GetStringValue(),
+		request.Fields["operation"].GetStringValue(),
+		request.Fields["tolerance"].GetNumberValue(),
+	)
+	if err != nil {
+		return getGRPCError(err)
+	}
+
+	return sendStruct(stream, summary)
+}
+
+// Convert a mesh streamed in chunks between formats (from and to) and send
+// it back in chunks. An OBJ mesh is streamed out of core with out-of-core
+// true.
+func (s *server) convertStream(stream grpc.ServerStream) error {
+	reader := &grpcChunkReader{stream: stream, remaining: s.options.MaxUpload}
+	from := getStreamMetadata(stream, "from", "")
+	to := getStreamMetadata(stream, "to", "")
+
+	if getStreamMetadata(stream, "out-of-core", "") != "true" {
+		mesh, err := pipeline.ReadMesh(reader, from)
+		if err != nil {
+			return getGRPCError(err)
+		}
+
+		return writeStreamMesh(stream, mesh, to)
+	}
+
+	chunkReader, err := newOBJChunkReader(reader, from)
+	if err != nil {
+		return getGRPCError(err)
+	}
+
+	writer := bufio.NewWriterSize(grpcChunkWriter{stream}, grpcChunkSize)
+
+	chunkWriter, err := s.newChunkWriter(writer, to)
+	if err != nil {
+		return getGRPCError(err)
+	}
+
+	if err := outofcore.Convert(chunkReader, chunkWriter); err != nil {
+		return getGRPCError(err)
+	}
+
+	return getGRPCError(writer.Flush())
+}
+
+// Diagnose a mesh streamed in chunks without storing it. An OBJ mesh is
+// diagnosed out of core with out-of-core true.
+func (s *server) diagnoseStream(stream grpc.ServerStream) error {
+	reader := &grpcChunkReader{stream: stream, remaining: s.options.MaxUpload}
+	outOfCore := getStreamMetadata(stream, "out-of-core", "") == "true"
+
+	diagnosis, err := s.diagnose(reader, getStreamMetadata(stream, "format", ""), outOfCore)
+	if err != nil {
+		return getGRPCError(err)
+	}
+
+	return sendStruct(stream, diagnosis)
+}
+
+// Write a mesh in a format streamed in chunks.
+func writeStreamMesh(stream grpc.ServerStream, mesh *halfedge.HalfEdgeMesh, format string) error {
+	writer := bufio.NewWriterSize(grpcChunkWriter{stream}, grpcChunkSize)
+
+	if err := pipeline.WriteMesh(writer, mesh, format); err != nil {
+		return getGRPCError(err)
+	}
+
+	return getGRPCError(writer.Flush())
+}
+
+// Send a value as a Struct of its JSON fields.
+func sendStruct(stream grpc.ServerStream, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return getGRPCError(err)
+	}
+
+	var result structpb.Struct
+
+	if err := result.UnmarshalJSON(data); err != nil {
+		return getGRPCError(err)
+	}
+
+	return stream.SendMsg(&result)
+}
+
+// Get a value of the metadata of a stream, or the default if missing.
+func getStreamMetadata(stream grpc.ServerStream, key, value string) string {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+
+	if values := md.Get(key); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+
+	return value
+}
+
+// Get the gRPC status error of an error by its cause.
+func getGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := codes.InvalidArgument
+
+	switch {
+	case errors.Is(err, ErrMeshNotFound):
+		code = codes.NotFound
+	case errors.Is(err, ErrUploadTooLarge):
+		code = codes.ResourceExhausted
+	}
+
+	return status.Error(code, err.Error())
+}
+
+// Reader of the chunks of a client stream limited to a number of bytes.
+type grpcChunkReader struct {
+	stream    grpc.ServerStream
+	chunk     []byte
+	remaining int64
+}
+
+// Read the chunks in order until the end of the stream.
+func (r *grpcChunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		var message wrapperspb.BytesValue
+
+		if err := r.stream.RecvMsg(&message); err != nil {
+			return 0, err
+		}
+
+		if r.remaining -= int64(len(message.Value)); r.remaining < 0 {
+			return 0, ErrUploadTooLarge
+		}
+
+		r.chunk = message.Value
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
+
+// Writer of the chunks of a server stream.
+type grpcChunkWriter struct {
+	stream grpc.ServerStream
+}
+
+// Send the bytes as a chunk.
+func (w grpcChunkWriter) Write(p []byte) (int, error) {
+	if err := w.stream.SendMsg(wrapperspb.Bytes(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/ajcurley/meshx-go/pipeline"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Serve the gRPC service of a server in memory and connect a client to it.
+func newTestGRPCClient(t *testing.T, s *server) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	g := newGRPCServer(s)
+	go g.Serve(listener)
+	t.Cleanup(g.Stop)
+
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}
+
+	conn, err := grpc.NewClient("passthrough:///meshxd", grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Empty(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// Send a mesh in chunks of a size to a client streaming method over gRPC
+// with metadata (key and value pairs).
+func sendTestChunks(conn *grpc.ClientConn, method string, data []byte, size int, kv ...string) (grpc.ClientStream, error) {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), kv...)
+	index := slices.IndexFunc(meshesServiceDesc.Streams, func(desc grpc.StreamDesc) bool { return desc.StreamName == method })
+
+	stream, err := conn.NewStream(ctx, &meshesServiceDesc.Streams[index], "/meshx.Meshes/"+method)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(data); i += size {
+		if err := stream.SendMsg(wrapperspb.Bytes(data[i:min(i+size, len(data))])); err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+
+	return stream, stream.CloseSend()
+}
+
+// Receive the chunks of a mesh streamed over gRPC.
+func receiveTestChunks(stream grpc.ClientStream) ([]byte, error) {
+	var data bytes.Buffer
+
+	for {
+		var chunk wrapperspb.BytesValue
+
+		if err := stream.RecvMsg(&chunk); err == io.EOF {
+			return data.Bytes(), nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		data.Write(chunk.Value)
+	}
+}
+
+// Upload a mesh in chunks of a size over gRPC.
+func uploadTestMesh(conn *grpc.ClientConn, data []byte, format string, size int) (*structpb.Struct, error) {
+	stream, err := sendTestChunks(conn, "Upload", data, size, "format", format)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary structpb.Struct
+	return &summary, stream.RecvMsg(&summary)
+}
+
+// Download a mesh over gRPC.
+func downloadTestMesh(conn *grpc.ClientConn, id, format string) ([]byte, error) {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "format", format)
+
+	stream, err := conn.NewStream(ctx, &meshesServiceDesc.Streams[1], "/meshx.Meshes/Download")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.SendMsg(wrapperspb.String(id)); err != nil {
+		return nil, err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	return receiveTestChunks(stream)
+}
+
+// Call a method with a single request and response over gRPC.
+func callTestMethod(conn *grpc.ClientConn, method string, request proto.Message) (*structpb.Struct, error) {
+	var response structpb.Struct
+	return &response, conn.Invoke(context.Background(), "/meshx.Meshes/"+method, request, &response)
+}
+
+// Test uploading and downloading a mesh streamed over gRPC.
+func TestGRPCMeshes(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	s := newServer(serverOptions{})
+	conn := newTestGRPCClient(t, s)
+
+	summary, err := uploadTestMesh(conn, data, ".obj", 16)
+	assert.Empty(t, err)
+	assert.Equal(t, "1", summary.Fields["id"].GetStringValue())
+	assert.Equal(t, 8.0, summary.Fields["numberOfVertices"].GetNumberValue())
+	assert.Equal(t, 7.0, summary.Fields["numberOfFaces"].GetNumberValue())
+
+	// The mesh is shared with the REST endpoints.
+	var diagnosis meshDiagnosis
+	response := sendRequest(t, s, http.MethodGet, "/meshes/1/diagnostics", nil, &diagnosis)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, 7, diagnosis.NumberOfFaces)
+
+	download, err := downloadTestMesh(conn, "1", ".ply")
+	assert.Empty(t, err)
+
+	mesh, err := pipeline.ReadMesh(bytes.NewReader(download), ".ply")
+	assert.Empty(t, err)
+	assert.Equal(t, 7, mesh.GetNumberOfFaces())
+
+	_, err = downloadTestMesh(conn, "2", "")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = downloadTestMesh(conn, "1", ".stl")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = uploadTestMesh(conn, data, ".stl", 16)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	conn = newTestGRPCClient(t, newServer(serverOptions{MaxUpload: 16}))
+	_, err = uploadTestMesh(conn, data, ".obj", 8)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+// Test serving a stored mesh to concurrent requests.
+func TestGRPCMeshesConcurrent(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	s := newServer(serverOptions{})
+	conn := newTestGRPCClient(t, s)
+
+	_, err = uploadTestMesh(conn, data, ".obj", 1<<10)
+	assert.Empty(t, err)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			download, err := downloadTestMesh(conn, "1", ".obj")
+			assert.Empty(t, err)
+			assert.NotEmpty(t, download)
+		}()
+
+		go func() {
+			defer wg.Done()
+			var diagnosis meshDiagnosis
+			sendRequest(t, s, http.MethodGet, "/meshes/1/diagnostics", nil, &diagnosis)
+			assert.True(t, diagnosis.Closed)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// Test processing stored meshes over gRPC.
+func TestGRPCOperations(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	s := newServer(serverOptions{})
+	conn := newTestGRPCClient(t, s)
+
+	_, err = uploadTestMesh(conn, data, ".obj", 1<<10)
+	assert.Empty(t, err)
+
+	diagnosis, err := callTestMethod(conn, "Diagnostics", wrapperspb.String("1"))
+	assert.Empty(t, err)
+	assert.Equal(t, 7.0, diagnosis.Fields["numberOfFaces"].GetNumberValue())
+	assert.Equal(t, 6, len(diagnosis.Fields["patches"].GetListValue().GetValues()))
+
+	_, err = callTestMethod(conn, "Diagnostics", wrapperspb.String("2"))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	request, _ := structpb.NewStruct(map[string]any{"id": "1", "patches": []any{"back", "front"}})
+	summary, err := callTestMethod(conn, "Extract", request)
+	assert.Empty(t, err)
+	assert.Equal(t, "2", summary.Fields["id"].GetStringValue())
+	assert.Equal(t, 2.0, summary.Fields["numberOfPatches"].GetNumberValue())
+
+	request, _ = structpb.NewStruct(map[string]any{"id": "1", "patches": []any{"missing"}})
+	_, err = callTestMethod(conn, "Extract", request)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	request, _ = structpb.NewStruct(map[string]any{"id": "1", "faces": 6})
+	summary, err = callTestMethod(conn, "Decimate", request)
+	assert.Empty(t, err)
+	assert.LessOrEqual(t, summary.Fields["numberOfFaces"].GetNumberValue(), 6.0)
+
+	request, _ = structpb.NewStruct(map[string]any{"id": "1", "ratio": 2})
+	_, err = callTestMethod(conn, "Decimate", request)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// A Boolean of two cubes, one uploaded over REST.
+	_, err = uploadTestMesh(conn, newTestCubeOBJ(0, 0, 0), ".obj", 1<<10)
+	assert.Empty(t, err)
+	sendRequest(t, s, http.MethodPost, "/meshes?format=.obj", newTestCubeOBJ(0.5, 0, 0), nil)
+
+	request, _ = structpb.NewStruct(map[string]any{"a": "4", "b": "5", "operation": "intersection"})
+	summary, err = callTestMethod(conn, "Boolean", request)
+	assert.Empty(t, err)
+
+	diagnosis, err = callTestMethod(conn, "Diagnostics", wrapperspb.String(summary.Fields["id"].GetStringValue()))
+	assert.Empty(t, err)
+	assert.True(t, diagnosis.Fields["closed"].GetBoolValue())
+	assert.Equal(t, 0.5, diagnosis.Fields["minBound"].GetListValue().GetValues()[0].GetNumberValue())
+
+	request, _ = structpb.NewStruct(map[string]any{"a": "4", "b": "5", "operation": "xor"})
+	_, err = callTestMethod(conn, "Boolean", request)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// Test converting and diagnosing meshes streamed over gRPC.
+func TestGRPCStream(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	conn := newTestGRPCClient(t, newServer(serverOptions{TempDir: t.TempDir()}))
+
+	for _, outOfCore := range []string{"false", "true"} {
+		stream, err := sendTestChunks(conn, "Convert", data, 16, "from", ".obj", "to", ".ply", "out-of-core", outOfCore)
+		assert.Empty(t, err)
+
+		converted, err := receiveTestChunks(stream)
+		assert.Empty(t, err)
+
+		mesh, err := pipeline.ReadMesh(bytes.NewReader(converted), ".ply")
+		assert.Empty(t, err)
+		assert.Equal(t, 8, mesh.GetNumberOfVertices())
+	}
+
+	stream, err := sendTestChunks(conn, "Convert", data, 16, "from", ".ply", "to", ".obj", "out-of-core", "true")
+	assert.Empty(t, err)
+	_, err = receiveTestChunks(stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	stream, err = sendTestChunks(conn, "Diagnose", data, 16, "format", ".obj")
+	assert.Empty(t, err)
+
+	var diagnosis structpb.Struct
+	assert.Empty(t, stream.RecvMsg(&diagnosis))
+	assert.Equal(t, 7.0, diagnosis.Fields["numberOfFaces"].GetNumberValue())
+
+	stream, err = sendTestChunks(conn, "Diagnose", data, 16, "format", ".obj", "out-of-core", "true")
+	assert.Empty(t, err)
+	assert.Empty(t, stream.RecvMsg(&diagnosis))
+	assert.Equal(t, 7.0, diagnosis.Fields["numberOfFaces"].GetNumberValue())
+	assert.Equal(t, 0.0, diagnosis.Fields["numberOfBoundaryEdges"].GetNumberValue())
+}
@@ -0,0 +1,81 @@
+// Command meshxd serves the mesh processing library over HTTP (REST) and
+// optionally gRPC. It is a module of its own so that the library does not
+// depend on gRPC (build it from its directory).
+//
+// Usage:
+//
+//	meshxd [-addr <address>] [-grpc-addr <address>] [-max-upload <bytes>] [-temp-dir <dir>]
+//
+// Meshes are uploaded once and processed by their identifier, or streamed
+// through a single request. The mesh file format of a request or response
+// is a file extension (e.g. ".obj" or ".ply", see pipeline.ReadMesh and
+// pipeline.WriteMesh). The endpoints are:
+//
+//	POST   /meshes?format=<format>                 upload a mesh
+//	GET    /meshes/{id}?format=<format>            download a mesh
+//	DELETE /meshes/{id}                            delete a mesh
+//	GET    /meshes/{id}/diagnostics                diagnose a mesh
+//	POST   /meshes/{id}/extract?patches=<a,b>      extract patches by name
+//	POST   /meshes/{id}/decimate?faces=<n>         decimate (or ratio=<r>)
+//	POST   /meshes/{id}/boolean?with=<id>&operation=<op>
+//	                                               Boolean with another mesh
+//	POST   /convert?from=<format>&to=<format>      convert a streamed mesh
+//	POST   /diagnose?format=<format>               diagnose a streamed mesh
+//
+// Conversion and diagnostics of an OBJ body with out-of-core=true are
+// streamed through temporary files (see package outofcore) so the payload
+// may be larger than memory. Errors are returned as JSON objects with an
+// error message.
+//
+// The meshes created by extraction, decimation or a Boolean operation are
+// stored as new meshes. A Boolean operation is a union, intersection or
+// difference of closed meshes and its tolerance (tolerance=<t>) is relative
+// to the diagonal of the bounding box of both meshes.
+//
+// With -grpc-addr the same operations are also served by the gRPC service
+// Meshes of meshxd.proto, which streams the mesh files in chunks. The formats
+// are request metadata. The meshes are shared with the REST endpoints.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+)
+
+func main() {
+	flags := flag.NewFlagSet("meshxd", flag.ExitOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	grpcAddr := flags.String("grpc-addr", "", "address to serve gRPC on (disabled if empty)")
+	maxUpload := flags.Int64("max-upload", defaultMaxUpload, "maximum size of a request body in bytes")
+	tempDir := flags.String("temp-dir", "", "directory of the temporary files streamed out of core")
+	flags.Parse(os.Args[1:])
+
+	server := newServer(serverOptions{MaxUpload: *maxUpload, TempDir: *tempDir})
+
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "meshxd: %v\n", err)
+			os.Exit(1)
+		}
+
+		log.Printf("serving gRPC on %s", *grpcAddr)
+		go func() {
+			if err := newGRPCServer(server).Serve(listener); err != nil {
+				fmt.Fprintf(os.Stderr, "meshxd: %v\n", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	log.Printf("listening on %s", *addr)
+
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		fmt.Fprintf(os.Stderr, "meshxd: %v\n", err)
+		os.Exit(1)
+	}
+}
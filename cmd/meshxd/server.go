@@ -0,0 +1,551 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/ajcurley/meshx-go/outofcore"
+	"github.com/ajcurley/meshx-go/pipeline"
+)
+
+const (
+	// Default maximum size of a request body in bytes.
+	defaultMaxUpload = 1 << 30
+
+	// Default tolerance of a Boolean operation relative to the diagonal of
+	// the bounding box of the meshes.
+	defaultBooleanTolerance = 1e-9
+)
+
+var (
+	ErrMeshNotFound     = errors.New("mesh not found")
+	ErrPatchNotFound    = errors.New("patch not found")
+	ErrInvalidParameter = errors.New("invalid parameter")
+	ErrUploadTooLarge   = errors.New("upload too large")
+)
+
+// Boolean operations by name.
+var booleanOperations = map[string]halfedge.BooleanOperation{
+	"union":        halfedge.BooleanUnion,
+	"intersection": halfedge.BooleanIntersection,
+	"difference":   halfedge.BooleanDifference,
+}
+
+// Options of the server. Zero values use the defaults.
+type serverOptions struct {
+	// Maximum size of a request body in bytes.
+	MaxUpload int64
+
+	// Directory of the temporary files streamed out of core (os.TempDir if
+	// empty).
+	TempDir string
+}
+
+// Server of the meshes uploaded by their identifier.
+type server struct {
+	options serverOptions
+	mux     *http.ServeMux
+	mutex   sync.RWMutex
+	meshes  map[string]*storedMesh
+	nextID  int
+}
+
+// Mesh stored by its identifier. The requests only reading the mesh share
+// its lock and a request modifying it must hold it exclusively.
+type storedMesh struct {
+	mutex sync.RWMutex
+	mesh  *halfedge.HalfEdgeMesh
+}
+
+// Summary of a mesh returned once stored.
+type meshSummary struct {
+	ID               string `json:"id"`
+	NumberOfVertices int    `json:"numberOfVertices"`
+	NumberOfFaces    int    `json:"numberOfFaces"`
+	NumberOfPatches  int    `json:"numberOfPatches"`
+}
+
+// Diagnostics of a mesh held in memory.
+type meshDiagnosis struct {
+	NumberOfVertices      int           `json:"numberOfVertices"`
+	NumberOfFaces         int           `json:"numberOfFaces"`
+	NumberOfHalfEdges     int           `json:"numberOfHalfEdges"`
+	NumberOfComponents    int           `json:"numberOfComponents"`
+	NumberOfBoundaryLoops int           `json:"numberOfBoundaryLoops"`
+	Closed                bool          `json:"closed"`
+	Consistent            bool          `json:"consistent"`
+	MinBound              meshx.Vector  `json:"minBound"`
+	MaxBound              meshx.Vector  `json:"maxBound"`
+	Patches               []patchDetail `json:"patches"`
+}
+
+// Name and number of faces of a patch.
+type patchDetail struct {
+	Name          string `json:"name"`
+	NumberOfFaces int    `json:"numberOfFaces"`
+}
+
+// Construct a server with its routes.
+func newServer(options serverOptions) *server {
+	if options.MaxUpload <= 0 {
+		options.MaxUpload = defaultMaxUpload
+	}
+
+	s := &server{
+		options: options,
+		mux:     http.NewServeMux(),
+		meshes:  make(map[string]*storedMesh),
+	}
+
+	s.mux.HandleFunc("POST /meshes", s.handleUpload)
+	s.mux.HandleFunc("GET /meshes/{id}", s.handleDownload)
+	s.mux.HandleFunc("DELETE /meshes/{id}", s.handleDelete)
+	s.mux.HandleFunc("GET /meshes/{id}/diagnostics", s.handleDiagnostics)
+	s.mux.HandleFunc("POST /meshes/{id}/extract", s.handleExtract)
+	s.mux.HandleFunc("POST /meshes/{id}/decimate", s.handleDecimate)
+	s.mux.HandleFunc("POST /meshes/{id}/boolean", s.handleBoolean)
+	s.mux.HandleFunc("POST /convert", s.handleConvert)
+	s.mux.HandleFunc("POST /diagnose", s.handleDiagnose)
+
+	return s
+}
+
+// Serve a request limiting the size of its body.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.options.MaxUpload)
+	s.mux.ServeHTTP(w, r)
+}
+
+// Store a mesh and write its summary.
+func (s *server) storeMesh(w http.ResponseWriter, mesh *halfedge.HalfEdgeMesh) {
+	writeJSON(w, http.StatusCreated, s.addMesh(mesh))
+}
+
+// Store a mesh and get its summary.
+func (s *server) addMesh(mesh *halfedge.HalfEdgeMesh) meshSummary {
+	summary := meshSummary{
+		NumberOfVertices: mesh.GetNumberOfVertices(),
+		NumberOfFaces:    mesh.GetNumberOfFaces(),
+		NumberOfPatches:  mesh.GetNumberOfPatches(),
+	}
+
+	s.mutex.Lock()
+	s.nextID++
+	summary.ID = strconv.Itoa(s.nextID)
+	s.meshes[summary.ID] = &storedMesh{mesh: mesh}
+	s.mutex.Unlock()
+
+	return summary
+}
+
+// Get the mesh of the identifier of a request locked for reading. The
+// release function unlocks the mesh once the request is done with it.
+func (s *server) getMesh(r *http.Request) (*halfedge.HalfEdgeMesh, func(), error) {
+	return s.readMesh(r.PathValue("id"))
+}
+
+// Get a mesh by its identifier locked for reading (see getMesh).
+func (s *server) readMesh(id string) (*halfedge.HalfEdgeMesh, func(), error) {
+	s.mutex.RLock()
+	stored, ok := s.meshes[id]
+	s.mutex.RUnlock()
+
+	if !ok {
+		return nil, nil, ErrMeshNotFound
+	}
+
+	stored.mutex.RLock()
+
+	return stored.mesh, stored.mutex.RUnlock, nil
+}
+
+// Upload a mesh of a format.
+func (s *server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	mesh, err := pipeline.ReadMesh(r.Body, r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.storeMesh(w, mesh)
+}
+
+// Download a mesh in a format (.obj by default).
+func (s *server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	mesh, release, err := s.getMesh(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer release()
+
+	format := r.URL.Query().Get("format")
+
+	if format == "" {
+		format = ".obj"
+	}
+
+	writeMesh(w, mesh, format)
+}
+
+// Delete a mesh. The requests already using the mesh are unaffected.
+func (s *server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	s.mutex.Lock()
+	_, ok := s.meshes[id]
+	delete(s.meshes, id)
+	s.mutex.Unlock()
+
+	if !ok {
+		writeError(w, ErrMeshNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Diagnose a stored mesh.
+func (s *server) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	mesh, release, err := s.getMesh(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer release()
+
+	writeJSON(w, http.StatusOK, diagnoseMesh(mesh))
+}
+
+// Diagnose a mesh held in memory.
+func diagnoseMesh(mesh *halfedge.HalfEdgeMesh) meshDiagnosis {
+	diagnosis := meshDiagnosis{
+		NumberOfVertices:      mesh.GetNumberOfVertices(),
+		NumberOfFaces:         mesh.GetNumberOfFaces(),
+		NumberOfHalfEdges:     mesh.GetNumberOfHalfEdges(),
+		NumberOfComponents:    len(mesh.GetComponents()),
+		NumberOfBoundaryLoops: len(mesh.GetBoundaryLoops()),
+		Closed:                mesh.IsClosed(),
+		Consistent:            mesh.IsConsistent(),
+		Patches:               make([]patchDetail, mesh.GetNumberOfPatches()),
+	}
+
+	for i := range mesh.GetNumberOfPatches() {
+		diagnosis.Patches[i] = patchDetail{mesh.GetPatch(i).Name, len(mesh.GetPatchFaces(i))}
+	}
+
+	if mesh.GetNumberOfVertices() != 0 {
+		aabb := mesh.GetAABB()
+		diagnosis.MinBound, diagnosis.MaxBound = aabb.GetMinBound(), aabb.GetMaxBound()
+	}
+
+	return diagnosis
+}
+
+// Extract the patches of a mesh by name as a new mesh.
+func (s *server) handleExtract(w http.ResponseWriter, r *http.Request) {
+	names := r.URL.Query().Get("patches")
+
+	if names == "" {
+		writeError(w, fmt.Errorf("%w: patches", ErrInvalidParameter))
+		return
+	}
+
+	summary, err := s.extractMesh(r.PathValue("id"), strings.Split(names, ","))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, summary)
+}
+
+// Extract the patches of a stored mesh by name and store them as a new mesh.
+func (s *server) extractMesh(id string, names []string) (meshSummary, error) {
+	mesh, release, err := s.readMesh(id)
+	if err != nil {
+		return meshSummary{}, err
+	}
+	defer release()
+
+	patches := make([]int, 0)
+
+	for _, name := range names {
+		found := false
+
+		for i := range mesh.GetNumberOfPatches() {
+			if mesh.GetPatch(i).Name == name {
+				patches = append(patches, i)
+				found = true
+			}
+		}
+
+		if !found {
+			return meshSummary{}, fmt.Errorf("%w: %s", ErrPatchNotFound, name)
+		}
+	}
+
+	return s.addMesh(mesh.ExtractPatches(patches)), nil
+}
+
+// Decimate a mesh to a target number (faces) or ratio (ratio) of triangles
+// as a new mesh.
+func (s *server) handleDecimate(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var faces int
+	var ratio float64
+	var err error
+
+	if value := query.Get("faces"); value != "" {
+		if faces, err = strconv.Atoi(value); err != nil || faces <= 0 {
+			writeError(w, fmt.Errorf("%w: faces", ErrInvalidParameter))
+			return
+		}
+	} else if ratio, err = strconv.ParseFloat(query.Get("ratio"), 64); err != nil {
+		writeError(w, fmt.Errorf("%w: ratio", ErrInvalidParameter))
+		return
+	}
+
+	summary, err := s.decimateMesh(r.PathValue("id"), faces, ratio)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, summary)
+}
+
+// Decimate a stored mesh to a target number of triangles, or a ratio of its
+// faces if zero, and store the result as a new mesh.
+func (s *server) decimateMesh(id string, faces int, ratio float64) (meshSummary, error) {
+	mesh, release, err := s.readMesh(id)
+	if err != nil {
+		return meshSummary{}, err
+	}
+	defer release()
+
+	if faces == 0 {
+		if !(ratio > 0 && ratio <= 1) {
+			return meshSummary{}, fmt.Errorf("%w: ratio", ErrInvalidParameter)
+		}
+
+		faces = int(math.Round(ratio * float64(mesh.GetNumberOfFaces())))
+	} else if faces < 0 {
+		return meshSummary{}, fmt.Errorf("%w: faces", ErrInvalidParameter)
+	}
+
+	result, err := pipeline.Decimate(mesh, faces)
+	if err != nil {
+		return meshSummary{}, err
+	}
+
+	return s.addMesh(result), nil
+}
+
+// Compute a Boolean operation (union, intersection or difference) of a mesh
+// and another (with) as a new mesh. The tolerance is relative to the
+// diagonal of the bounding box of both meshes (1e-9 by default).
+func (s *server) handleBoolean(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var tolerance float64
+
+	if value := query.Get("tolerance"); value != "" {
+		var err error
+
+		if tolerance, err = strconv.ParseFloat(value, 64); err != nil || !(tolerance > 0) {
+			writeError(w, fmt.Errorf("%w: tolerance", ErrInvalidParameter))
+			return
+		}
+	}
+
+	summary, err := s.booleanMeshes(r.PathValue("id"), query.Get("with"), query.Get("operation"), tolerance)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, summary)
+}
+
+// Compute a Boolean operation of two stored meshes by name and store the
+// result as a new mesh. The tolerance is relative to the diagonal of the
+// bounding box of both meshes (defaultBooleanTolerance if zero).
+func (s *server) booleanMeshes(a, b, name string, tolerance float64) (meshSummary, error) {
+	operation, ok := booleanOperations[name]
+
+	if !ok {
+		return meshSummary{}, fmt.Errorf("%w: operation", ErrInvalidParameter)
+	}
+
+	if tolerance == 0 {
+		tolerance = defaultBooleanTolerance
+	} else if !(tolerance > 0) {
+		return meshSummary{}, fmt.Errorf("%w: tolerance", ErrInvalidParameter)
+	}
+
+	meshA, releaseA, err := s.readMesh(a)
+	if err != nil {
+		return meshSummary{}, err
+	}
+	defer releaseA()
+
+	// The same mesh is only locked once.
+	meshB := meshA
+
+	if b != a {
+		var releaseB func()
+
+		if meshB, releaseB, err = s.readMesh(b); err != nil {
+			return meshSummary{}, err
+		}
+		defer releaseB()
+	}
+
+	aabbA, aabbB := meshA.GetAABB(), meshB.GetAABB()
+	aabb := meshx.NewAABBFromVectors([]meshx.Vector{aabbA.GetMinBound(), aabbA.GetMaxBound(), aabbB.GetMinBound(), aabbB.GetMaxBound()})
+
+	result, err := halfedge.Boolean(meshA, meshB, operation, tolerance*2*aabb.HalfSize.Mag())
+	if err != nil {
+		return meshSummary{}, err
+	}
+
+	return s.addMesh(result), nil
+}
+
+// Convert a mesh between formats without storing it.
+func (s *server) handleConvert(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, to := query.Get("from"), query.Get("to")
+
+	if query.Get("out-of-core") == "true" {
+		s.convertOutOfCore(w, r.Body, from, to)
+		return
+	}
+
+	mesh, err := pipeline.ReadMesh(r.Body, from)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeMesh(w, mesh, to)
+}
+
+// Convert an OBJ mesh to an OBJ or PLY mesh streamed out of core.
+func (s *server) convertOutOfCore(w http.ResponseWriter, body io.Reader, from, to string) {
+	reader, err := newOBJChunkReader(body, from)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writer, err := s.newChunkWriter(w, to)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+
+	if err := outofcore.Convert(reader, writer); err != nil {
+		log.Printf("convert: %v", err)
+	}
+}
+
+// Construct the ChunkWriter of an OBJ or PLY mesh streamed out of core.
+func (s *server) newChunkWriter(w io.Writer, format string) (outofcore.ChunkWriter, error) {
+	switch strings.ToLower(format) {
+	case ".obj":
+		return outofcore.NewOBJChunkWriter(w), nil
+	case ".ply":
+		return outofcore.NewPLYChunkWriter(w, s.options.TempDir), nil
+	}
+
+	return nil, pipeline.ErrUnsupportedFormat
+}
+
+// Diagnose a mesh without storing it (out of core for an OBJ mesh with
+// out-of-core=true).
+func (s *server) handleDiagnose(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	diagnosis, err := s.diagnose(r.Body, query.Get("format"), query.Get("out-of-core") == "true")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, diagnosis)
+}
+
+// Diagnose a mesh of a format read in memory or streamed out of core (see
+// outofcore.Diagnose).
+func (s *server) diagnose(body io.Reader, format string, outOfCore bool) (any, error) {
+	if !outOfCore {
+		mesh, err := pipeline.ReadMesh(body, format)
+		if err != nil {
+			return nil, err
+		}
+
+		return diagnoseMesh(mesh), nil
+	}
+
+	reader, err := newOBJChunkReader(body, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return outofcore.Diagnose(reader, outofcore.Options{TempDir: s.options.TempDir})
+}
+
+// Construct an OBJChunkReader of a request body of a format.
+func newOBJChunkReader(body io.Reader, format string) (*outofcore.OBJChunkReader, error) {
+	if format = strings.ToLower(format); format != ".obj" && format != ".obj.gz" {
+		return nil, pipeline.ErrUnsupportedFormat
+	}
+
+	return outofcore.NewOBJChunkReader(body)
+}
+
+// Write a mesh in a format streamed to the response.
+func writeMesh(w http.ResponseWriter, mesh *halfedge.HalfEdgeMesh, format string) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+
+	if err := pipeline.WriteMesh(w, mesh, format); errors.Is(err, pipeline.ErrUnsupportedFormat) {
+		writeError(w, err)
+	} else if err != nil {
+		log.Printf("write mesh: %v", err)
+	}
+}
+
+// Write a value as JSON with a status code.
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// Write an error as JSON with the status code of its cause.
+func writeError(w http.ResponseWriter, err error) {
+	var maxBytesError *http.MaxBytesError
+	status := http.StatusBadRequest
+
+	switch {
+	case errors.Is(err, ErrMeshNotFound):
+		status = http.StatusNotFound
+	case errors.As(err, &maxBytesError):
+		status = http.StatusRequestEntityTooLarge
+	}
+
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/outofcore"
+	"github.com/ajcurley/meshx-go/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+// Send a request to a server and decode its JSON response if any.
+func sendRequest(t *testing.T, s *server, method, target string, body []byte, result any) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(method, target, bytes.NewReader(body)))
+
+	if result != nil {
+		assert.Empty(t, json.Unmarshal(recorder.Body.Bytes(), result))
+	}
+
+	return recorder
+}
+
+// Get the OBJ file of a unit cube oriented outward offset by a vector.
+func newTestCubeOBJ(x, y, z float64) []byte {
+	var data bytes.Buffer
+
+	for i := range 8 {
+		fmt.Fprintf(&data, "v %g %g %g\n", x+float64(i&1), y+float64(i>>1&1), z+float64(i>>2&1))
+	}
+
+	data.WriteString("f 1 3 4 2\nf 5 6 8 7\nf 1 2 6 5\nf 3 7 8 4\nf 1 5 7 3\nf 2 4 8 6\n")
+
+	return data.Bytes()
+}
+
+// Test uploading, processing, downloading and deleting a mesh.
+func TestServerMeshes(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	s := newServer(serverOptions{})
+
+	var summary meshSummary
+	response := sendRequest(t, s, http.MethodPost, "/meshes?format=.obj", data, &summary)
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Equal(t, meshSummary{"1", 8, 7, 6}, summary)
+
+	var diagnosis meshDiagnosis
+	response = sendRequest(t, s, http.MethodGet, "/meshes/1/diagnostics", nil, &diagnosis)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, diagnosis.Closed)
+	assert.Equal(t, 6, len(diagnosis.Patches))
+
+	response = sendRequest(t, s, http.MethodPost, "/meshes/1/extract?patches=back,front", nil, &summary)
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Equal(t, "2", summary.ID)
+	assert.Equal(t, 2, summary.NumberOfPatches)
+
+	response = sendRequest(t, s, http.MethodPost, "/meshes/1/extract?patches=missing", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = sendRequest(t, s, http.MethodPost, "/meshes/1/decimate?faces=6", nil, &summary)
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.LessOrEqual(t, summary.NumberOfFaces, 6)
+
+	response = sendRequest(t, s, http.MethodPost, "/meshes/1/decimate?ratio=2", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = sendRequest(t, s, http.MethodGet, "/meshes/1?format=.ply", nil, nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	mesh, err := pipeline.ReadMesh(response.Body, ".ply")
+	assert.Empty(t, err)
+	assert.Equal(t, 7, mesh.GetNumberOfFaces())
+
+	response = sendRequest(t, s, http.MethodGet, "/meshes/1?format=.stl", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = sendRequest(t, s, http.MethodDelete, "/meshes/1", nil, nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	response = sendRequest(t, s, http.MethodGet, "/meshes/1/diagnostics", nil, nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// Test the Boolean operations of two stored meshes.
+func TestServerBoolean(t *testing.T) {
+	s := newServer(serverOptions{})
+	sendRequest(t, s, http.MethodPost, "/meshes?format=.obj", newTestCubeOBJ(0, 0, 0), nil)
+	sendRequest(t, s, http.MethodPost, "/meshes?format=.obj", newTestCubeOBJ(0.5, 0.5, 0.5), nil)
+
+	var summary meshSummary
+	response := sendRequest(t, s, http.MethodPost, "/meshes/1/boolean?with=2&operation=union", nil, &summary)
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Equal(t, "3", summary.ID)
+
+	var diagnosis meshDiagnosis
+	sendRequest(t, s, http.MethodGet, "/meshes/3/diagnostics", nil, &diagnosis)
+	assert.True(t, diagnosis.Closed)
+	assert.Equal(t, meshx.NewVector(1.5, 1.5, 1.5), diagnosis.MaxBound)
+
+	response = sendRequest(t, s, http.MethodPost, "/meshes/1/boolean?with=2&operation=difference&tolerance=1e-6", nil, &summary)
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Equal(t, "4", summary.ID)
+
+	response = sendRequest(t, s, http.MethodPost, "/meshes/1/boolean?with=2&operation=xor", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = sendRequest(t, s, http.MethodPost, "/meshes/1/boolean?with=2&operation=union&tolerance=0", nil, nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = sendRequest(t, s, http.MethodPost, "/meshes/1/boolean?with=5&operation=union", nil, nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+// Test converting and diagnosing streamed meshes.
+func TestServerStream(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	s := newServer(serverOptions{TempDir: t.TempDir()})
+
+	for _, target := range []string{"/convert?from=.obj&to=.ply", "/convert?from=.obj&to=.ply&out-of-core=true"} {
+		response := sendRequest(t, s, http.MethodPost, target, data, nil)
+		assert.Equal(t, http.StatusOK, response.Code)
+
+		mesh, err := pipeline.ReadMesh(response.Body, ".ply")
+		assert.Empty(t, err)
+		assert.Equal(t, 8, mesh.GetNumberOfVertices())
+	}
+
+	response := sendRequest(t, s, http.MethodPost, "/convert?from=.ply&to=.obj&out-of-core=true", data, nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	var diagnosis meshDiagnosis
+	response = sendRequest(t, s, http.MethodPost, "/diagnose?format=.obj", data, &diagnosis)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, 7, diagnosis.NumberOfFaces)
+
+	var outOfCore outofcore.Diagnosis
+	response = sendRequest(t, s, http.MethodPost, "/diagnose?format=.obj&out-of-core=true", data, &outOfCore)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, outOfCore.IsClosed())
+
+	s = newServer(serverOptions{MaxUpload: 16})
+	response = sendRequest(t, s, http.MethodPost, "/meshes?format=.obj", data, nil)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+}
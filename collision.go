@@ -11,3 +11,31 @@ type IntersectsRay interface {
 type IntersectsTriangle interface {
 	IntersectsTriangle(Triangle) bool
 }
+
+type ClosestPoint interface {
+	ClosestPoint(Vector) Vector
+}
+
+type IntersectsSegment interface {
+	IntersectsSegment(Segment) bool
+}
+
+type IntersectsCylinder interface {
+	IntersectsCylinder(Cylinder) bool
+}
+
+type IntersectsCapsule interface {
+	IntersectsCapsule(Capsule) bool
+}
+
+type IntersectsSphere interface {
+	IntersectsSphere(Sphere) bool
+}
+
+type IntersectsPolytope interface {
+	IntersectsPolytope(Polytope) bool
+}
+
+type Raycast interface {
+	Raycast(Ray) []Hit
+}
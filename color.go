@@ -0,0 +1,53 @@
+package meshx
+
+import (
+	"math"
+)
+
+// RGBA color with 8-bit channels.
+type Color [4]uint8
+
+var (
+	ColorWhite = Color{255, 255, 255, 255}
+	ColorBlack = Color{0, 0, 0, 255}
+)
+
+// Construct a Color from its channels.
+func NewColor(r, g, b, a uint8) Color {
+	return Color{r, g, b, a}
+}
+
+// Construct a Color from floating point channels in [0, 1]. Values outside
+// the range are clamped.
+func NewColorFromFloats(r, g, b, a float64) Color {
+	return Color{
+		colorChannel(r),
+		colorChannel(g),
+		colorChannel(b),
+		colorChannel(a),
+	}
+}
+
+// Get the floating point channels in [0, 1].
+func (c Color) Floats() [4]float64 {
+	return [4]float64{
+		float64(c[0]) / 255,
+		float64(c[1]) / 255,
+		float64(c[2]) / 255,
+		float64(c[3]) / 255,
+	}
+}
+
+// Return true if the color is fully opaque.
+func (c Color) IsOpaque() bool {
+	return c[3] == 255
+}
+
+// Convert a floating point channel in [0, 1] to an 8-bit channel.
+func colorChannel(value float64) uint8 {
+	if math.IsNaN(value) {
+		return 0
+	}
+
+	return uint8(math.Round(255 * min(max(value, 0), 1)))
+}
@@ -0,0 +1,17 @@
+package meshx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test constructing a color from floating point channels.
+func TestNewColorFromFloats(t *testing.T) {
+	color := NewColorFromFloats(1, 0.5, -1, 2)
+	assert.Equal(t, NewColor(255, 128, 0, 255), color)
+	assert.True(t, color.IsOpaque())
+
+	floats := NewColor(255, 0, 51, 0).Floats()
+	assert.Equal(t, [4]float64{1, 0, 0.2, 0}, floats)
+}
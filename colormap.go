@@ -0,0 +1,110 @@
+package meshx
+
+import (
+	"math"
+)
+
+// Colormap of evenly spaced colors interpolated linearly from the first
+// color (at 0) to the last color (at 1).
+type Colormap []Color
+
+var (
+	ColormapViridis = Colormap{
+		{68, 1, 84, 255},
+		{71, 44, 122, 255},
+		{59, 81, 139, 255},
+		{44, 113, 142, 255},
+		{33, 144, 141, 255},
+		{39, 173, 129, 255},
+		{92, 200, 99, 255},
+		{170, 220, 50, 255},
+		{253, 231, 37, 255},
+	}
+
+	ColormapCoolWarm = Colormap{
+		{59, 76, 192, 255},
+		{221, 221, 221, 255},
+		{180, 4, 38, 255},
+	}
+
+	ColormapJet = Colormap{
+		{0, 0, 128, 255},
+		{0, 0, 255, 255},
+		{0, 255, 255, 255},
+		{255, 255, 0, 255},
+		{255, 0, 0, 255},
+		{128, 0, 0, 255},
+	}
+
+	ColormapGray = Colormap{
+		{0, 0, 0, 255},
+		{255, 255, 255, 255},
+	}
+)
+
+// Map a value in [0, 1] to a color. Values outside the range are clamped and
+// NaN is mapped to black.
+func (c Colormap) Map(value float64) Color {
+	if math.IsNaN(value) || len(c) == 0 {
+		return ColorBlack
+	}
+
+	if len(c) == 1 {
+		return c[0]
+	}
+
+	t := min(max(value, 0), 1) * float64(len(c)-1)
+	i := min(int(t), len(c)-2)
+	t -= float64(i)
+
+	var color Color
+
+	for j := range color {
+		p := float64(c[i][j])
+		q := float64(c[i+1][j])
+		color[j] = uint8(math.Round(p + t*(q-p)))
+	}
+
+	return color
+}
+
+// Map a value in [lower, upper] to a color. If the range is empty, every
+// value is mapped to the middle of the colormap.
+func (c Colormap) MapRange(value, lower, upper float64) Color {
+	if upper <= lower {
+		return c.Map(0.5)
+	}
+
+	return c.Map((value - lower) / (upper - lower))
+}
+
+// Map the values in [lower, upper] to colors.
+func (c Colormap) MapValues(values []float64, lower, upper float64) []Color {
+	colors := make([]Color, len(values))
+
+	for i, value := range values {
+		colors[i] = c.MapRange(value, lower, upper)
+	}
+
+	return colors
+}
+
+// Compute the range of the finite values. The range is (0, 0) if there are
+// no finite values.
+func ComputeScalarRange(values []float64) (float64, float64) {
+	lower := math.Inf(1)
+	upper := math.Inf(-1)
+
+	for _, value := range values {
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			lower = min(lower, value)
+			upper = max(upper, value)
+		}
+	}
+
+	if lower > upper {
+		return 0, 0
+	}
+
+	return lower, upper
+}
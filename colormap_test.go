@@ -0,0 +1,39 @@
+package meshx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test mapping values through a colormap.
+func TestColormapMap(t *testing.T) {
+	colormap := Colormap{
+		NewColor(0, 0, 0, 255),
+		NewColor(100, 200, 0, 255),
+		NewColor(200, 200, 200, 255),
+	}
+
+	assert.Equal(t, NewColor(0, 0, 0, 255), colormap.Map(-1))
+	assert.Equal(t, NewColor(0, 0, 0, 255), colormap.Map(0))
+	assert.Equal(t, NewColor(50, 100, 0, 255), colormap.Map(0.25))
+	assert.Equal(t, NewColor(100, 200, 0, 255), colormap.Map(0.5))
+	assert.Equal(t, NewColor(200, 200, 200, 255), colormap.Map(1))
+	assert.Equal(t, NewColor(200, 200, 200, 255), colormap.Map(2))
+	assert.Equal(t, ColorBlack, colormap.Map(math.NaN()))
+
+	assert.Equal(t, NewColor(50, 100, 0, 255), colormap.MapRange(15, 10, 30))
+	assert.Equal(t, NewColor(100, 200, 0, 255), colormap.MapRange(15, 10, 10))
+}
+
+// Test computing the range of the finite values.
+func TestComputeScalarRange(t *testing.T) {
+	lower, upper := ComputeScalarRange([]float64{3, math.NaN(), -2, math.Inf(1), 5})
+	assert.Equal(t, -2.0, lower)
+	assert.Equal(t, 5.0, upper)
+
+	lower, upper = ComputeScalarRange([]float64{math.NaN()})
+	assert.Equal(t, 0.0, lower)
+	assert.Equal(t, 0.0, upper)
+}
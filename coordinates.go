@@ -0,0 +1,177 @@
+package meshx
+
+// Unit of length of the coordinates of a mesh.
+type Unit int
+
+const (
+	UnitMeter Unit = iota
+	UnitMillimeter
+	UnitCentimeter
+	UnitInch
+	UnitFoot
+)
+
+// Length of each unit in meters.
+var unitMeters = map[Unit]float64{
+	UnitMeter:      1,
+	UnitMillimeter: 0.001,
+	UnitCentimeter: 0.01,
+	UnitInch:       0.0254,
+	UnitFoot:       0.3048,
+}
+
+// Get the length of the unit in meters.
+func (u Unit) GetMeters() float64 {
+	if meters, ok := unitMeters[u]; ok {
+		return meters
+	}
+
+	return 1
+}
+
+// Vertical axis of a coordinate system.
+type UpAxis int
+
+const (
+	UpAxisZ UpAxis = iota
+	UpAxisY
+)
+
+// Convention of the coordinates of a mesh. The zero value is a right-handed
+// Z-up system in meters.
+type CoordinateSystem struct {
+	Unit       Unit
+	UpAxis     UpAxis
+	LeftHanded bool
+}
+
+// Get the transformation from the coordinate system to a right-handed Z-up
+// system in meters. A left-handed system is mirrored along the horizontal
+// axis other than X (Y for Z-up and Z for Y-up).
+func (c CoordinateSystem) getMatrix4() Matrix4 {
+	meters := c.Unit.GetMeters()
+	m := NewScaleMatrix4(NewVector(meters, meters, meters))
+
+	if c.LeftHanded {
+		mirror := NewVector(1, -1, 1)
+
+		if c.UpAxis == UpAxisY {
+			mirror = NewVector(1, 1, -1)
+		}
+
+		m = NewScaleMatrix4(mirror).Mul(m)
+	}
+
+	if c.UpAxis == UpAxisY {
+		// Rotate the Y axis onto the Z axis: (x, y, z) to (x, -z, y).
+		rotation := Matrix4{
+			{1, 0, 0, 0},
+			{0, 0, -1, 0},
+			{0, 1, 0, 0},
+			{0, 0, 0, 1},
+		}
+
+		m = rotation.Mul(m)
+	}
+
+	return m
+}
+
+// Conversion of coordinates between two coordinate systems.
+type Conversion struct {
+	From CoordinateSystem
+	To   CoordinateSystem
+}
+
+// Construct a Conversion between two coordinate systems.
+func NewConversion(from, to CoordinateSystem) Conversion {
+	return Conversion{from, to}
+}
+
+// Check if the conversion leaves the coordinates unchanged.
+func (c Conversion) IsIdentity() bool {
+	return c.From == c.To
+}
+
+// Check if the conversion mirrors the coordinates (the handedness changes),
+// so the faces must be reversed to keep their orientation.
+func (c Conversion) IsMirrored() bool {
+	return c.From.LeftHanded != c.To.LeftHanded
+}
+
+// Get the transformation matrix of the conversion.
+func (c Conversion) GetMatrix4() Matrix4 {
+	if c.IsIdentity() {
+		return NewIdentityMatrix4()
+	}
+
+	// The linear part is orthogonal times the scale, so the inverse is its
+	// transpose divided by the square of the scale.
+	inverse := c.To.getMatrix4().Transpose()
+	scale := c.To.Unit.GetMeters()
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			inverse[i][j] /= scale * scale
+		}
+	}
+
+	return inverse.Mul(c.From.getMatrix4())
+}
+
+// Convert a point.
+func (c Conversion) ConvertPoint(point Vector) Vector {
+	if c.IsIdentity() {
+		return point
+	}
+
+	return c.GetMatrix4().MulPoint(point)
+}
+
+// Convert the points. The points are returned as is for the identity.
+func (c Conversion) ConvertPoints(points []Vector) []Vector {
+	if c.IsIdentity() {
+		return points
+	}
+
+	matrix := c.GetMatrix4()
+	converted := make([]Vector, len(points))
+
+	for i, point := range points {
+		converted[i] = matrix.MulPoint(point)
+	}
+
+	return converted
+}
+
+// Convert a face by reversing its vertices (keeping the first) if the
+// conversion is mirrored. The face is returned as is otherwise.
+func (c Conversion) ConvertFace(face []int) []int {
+	if !c.IsMirrored() || len(face) == 0 {
+		return face
+	}
+
+	reversed := make([]int, len(face))
+	reversed[0] = face[0]
+
+	for i := 1; i < len(face); i++ {
+		reversed[i] = face[len(face)-i]
+	}
+
+	return reversed
+}
+
+// Convert the faces. See ConvertFace.
+func (c Conversion) ConvertFaces(faces [][]int) [][]int {
+	if !c.IsMirrored() {
+		return faces
+	}
+
+	converted := make([][]int, len(faces))
+
+	for i, face := range faces {
+		converted[i] = c.ConvertFace(face)
+	}
+
+	return converted
+}
@@ -0,0 +1,48 @@
+package meshx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the length of the units in meters.
+func TestUnitGetMeters(t *testing.T) {
+	assert.Equal(t, 1.0, UnitMeter.GetMeters())
+	assert.Equal(t, 0.001, UnitMillimeter.GetMeters())
+	assert.Equal(t, 0.0254, UnitInch.GetMeters())
+}
+
+// Test converting millimeters in a Y-up system to meters in a Z-up system.
+func TestConversionUnitsAndAxes(t *testing.T) {
+	from := CoordinateSystem{Unit: UnitMillimeter, UpAxis: UpAxisY}
+	conversion := NewConversion(from, CoordinateSystem{})
+	assert.False(t, conversion.IsIdentity())
+	assert.False(t, conversion.IsMirrored())
+
+	point := conversion.ConvertPoint(NewVector(1000, 2000, 3000))
+	assert.InDelta(t, 0, point.Sub(NewVector(1, -3, 2)).Mag(), 1e-12)
+
+	// The inverse conversion returns the original point.
+	inverse := NewConversion(CoordinateSystem{}, from)
+	point = inverse.ConvertPoint(point)
+	assert.InDelta(t, 0, point.Sub(NewVector(1000, 2000, 3000)).Mag(), 1e-9)
+
+	assert.InDelta(t, 1e-9, conversion.GetMatrix4().Determinant(), 1e-21)
+}
+
+// Test converting between right- and left-handed systems.
+func TestConversionMirrored(t *testing.T) {
+	conversion := NewConversion(CoordinateSystem{LeftHanded: true}, CoordinateSystem{})
+	assert.True(t, conversion.IsMirrored())
+	assert.Equal(t, NewVector(1, -2, 3), conversion.ConvertPoint(NewVector(1, 2, 3)))
+	assert.Less(t, conversion.GetMatrix4().Determinant(), 0.0)
+
+	assert.Equal(t, []int{0, 3, 2, 1}, conversion.ConvertFace([]int{0, 1, 2, 3}))
+	assert.Equal(t, [][]int{{4, 6, 5}}, conversion.ConvertFaces([][]int{{4, 5, 6}}))
+
+	identity := NewConversion(CoordinateSystem{}, CoordinateSystem{})
+	assert.True(t, identity.IsIdentity())
+	assert.Equal(t, []int{0, 1, 2}, identity.ConvertFace([]int{0, 1, 2}))
+	assert.Equal(t, NewIdentityMatrix4(), identity.GetMatrix4())
+}
@@ -0,0 +1,236 @@
+package curve
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrInvalidPolyline = errors.New("invalid polyline")
+)
+
+// Network of polylines sharing indexed vertices. Each polyline is a sequence
+// of vertex indices. A closed polyline repeats its first vertex at the end
+// (as in an OBJ l record).
+type CurveNetwork struct {
+	vertices  []meshx.Vector
+	polylines [][]int
+}
+
+// Construct a CurveNetwork from its vertices and polylines.
+func NewCurveNetwork(vertices []meshx.Vector, polylines [][]int) (*CurveNetwork, error) {
+	for _, polyline := range polylines {
+		if len(polyline) < 2 {
+			return nil, ErrInvalidPolyline
+		}
+
+		for _, vertex := range polyline {
+			if vertex < 0 || vertex >= len(vertices) {
+				return nil, ErrInvalidPolyline
+			}
+		}
+	}
+
+	return &CurveNetwork{vertices, polylines}, nil
+}
+
+// Construct a CurveNetwork by chaining edges into polylines. The polylines
+// break at the vertices not shared by exactly two edges. Cycles of edges
+// become closed polylines.
+func NewCurveNetworkFromEdges(vertices []meshx.Vector, edges [][2]int) (*CurveNetwork, error) {
+	vertexEdges := make(map[int][]int)
+
+	for i, edge := range edges {
+		for _, vertex := range edge {
+			if vertex < 0 || vertex >= len(vertices) {
+				return nil, ErrInvalidPolyline
+			}
+
+			vertexEdges[vertex] = append(vertexEdges[vertex], i)
+		}
+	}
+
+	visited := make([]bool, len(edges))
+	polylines := make([][]int, 0)
+
+	// Walk the unvisited edges from a vertex until reaching a vertex not
+	// shared by exactly two edges or closing a cycle.
+	walk := func(start, edge int) []int {
+		polyline := []int{start}
+		vertex := start
+
+		for {
+			visited[edge] = true
+			vertex = edges[edge][0] + edges[edge][1] - vertex
+			polyline = append(polyline, vertex)
+
+			if vertex == start || len(vertexEdges[vertex]) != 2 {
+				return polyline
+			}
+
+			next := vertexEdges[vertex][0]
+
+			if next == edge {
+				next = vertexEdges[vertex][1]
+			}
+
+			if visited[next] {
+				return polyline
+			}
+
+			edge = next
+		}
+	}
+
+	for i, edge := range edges {
+		for _, vertex := range edge {
+			if !visited[i] && len(vertexEdges[vertex]) != 2 {
+				polylines = append(polylines, walk(vertex, i))
+			}
+		}
+	}
+
+	for i, edge := range edges {
+		if !visited[i] {
+			polylines = append(polylines, walk(edge[0], i))
+		}
+	}
+
+	return &CurveNetwork{vertices, polylines}, nil
+}
+
+// Construct a CurveNetwork from the l records of an OBJ reader.
+func NewCurveNetworkFromOBJReader(reader *meshx.OBJReader) (*CurveNetwork, error) {
+	vertices := make([]meshx.Vector, reader.GetNumberOfVertices())
+	polylines := make([][]int, reader.GetNumberOfLines())
+
+	for i := range vertices {
+		vertices[i] = reader.GetVertex(i)
+	}
+
+	for i := range polylines {
+		polylines[i] = append([]int(nil), reader.GetLine(i)...)
+	}
+
+	return NewCurveNetwork(vertices, polylines)
+}
+
+// Read a CurveNetwork from the l records of an OBJ file.
+func ReadOBJ(reader io.Reader) (*CurveNetwork, error) {
+	objReader := meshx.NewOBJReader(reader)
+
+	if err := objReader.Read(); err != nil {
+		return nil, err
+	}
+
+	return NewCurveNetworkFromOBJReader(objReader)
+}
+
+// Read a CurveNetwork from the l records of an OBJ file path.
+func ReadOBJFromPath(path string) (*CurveNetwork, error) {
+	objReader, err := meshx.ReadOBJFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewCurveNetworkFromOBJReader(objReader)
+}
+
+// Write the CurveNetwork to an OBJ file as l records.
+func (c *CurveNetwork) WriteOBJ(writer io.Writer) error {
+	objWriter := meshx.NewOBJWriter(writer)
+	objWriter.SetVertices(c.vertices)
+	objWriter.SetLines(c.polylines)
+	return objWriter.Write()
+}
+
+// Write the CurveNetwork to an OBJ file path as l records.
+func (c *CurveNetwork) WriteOBJToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return c.WriteOBJ(file)
+}
+
+// Get the number of vertices.
+func (c *CurveNetwork) GetNumberOfVertices() int {
+	return len(c.vertices)
+}
+
+// Get a vertex by index.
+func (c *CurveNetwork) GetVertex(index int) meshx.Vector {
+	return c.vertices[index]
+}
+
+// Get the number of polylines.
+func (c *CurveNetwork) GetNumberOfPolylines() int {
+	return len(c.polylines)
+}
+
+// Get the vertex indices of a polyline by index.
+func (c *CurveNetwork) GetPolylineVertices(index int) []int {
+	return c.polylines[index]
+}
+
+// Get a polyline by index.
+func (c *CurveNetwork) GetPolyline(index int) Polyline {
+	indices := c.polylines[index]
+	closed := len(indices) > 2 && indices[0] == indices[len(indices)-1]
+
+	if closed {
+		indices = indices[:len(indices)-1]
+	}
+
+	points := make([]meshx.Vector, len(indices))
+
+	for i, vertex := range indices {
+		points[i] = c.vertices[vertex]
+	}
+
+	return NewPolyline(points, closed)
+}
+
+// Get the polylines.
+func (c *CurveNetwork) GetPolylines() []Polyline {
+	polylines := make([]Polyline, len(c.polylines))
+
+	for i := range polylines {
+		polylines[i] = c.GetPolyline(i)
+	}
+
+	return polylines
+}
+
+// Get the edges of the polylines.
+func (c *CurveNetwork) GetEdges() [][2]int {
+	edges := make([][2]int, 0)
+
+	for _, polyline := range c.polylines {
+		for i := 1; i < len(polyline); i++ {
+			edges = append(edges, [2]int{polyline[i-1], polyline[i]})
+		}
+	}
+
+	return edges
+}
+
+// Compute the total length of the polylines.
+func (c *CurveNetwork) Length() float64 {
+	var length float64
+
+	for i := range c.polylines {
+		length += c.GetPolyline(i).Length()
+	}
+
+	return length
+}
+
+// Get the axis-aligned bounding box.
+func (c *CurveNetwork) GetAABB() meshx.AABB {
+	return meshx.NewAABBFromVectors(c.vertices)
+}
@@ -0,0 +1,58 @@
+package curve
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test chaining edges into polylines.
+func TestNewCurveNetworkFromEdges(t *testing.T) {
+	vertices := make([]meshx.Vector, 8)
+
+	for i := range vertices {
+		vertices[i] = meshx.NewVector(float64(i), 0, 0)
+	}
+
+	// A path 0-1-2 branching at 2 to 3 and 4-5, and a cycle 6-7.
+	edges := [][2]int{{1, 2}, {0, 1}, {2, 3}, {4, 2}, {5, 4}, {6, 7}, {7, 6}}
+
+	network, err := NewCurveNetworkFromEdges(vertices, edges)
+	assert.Empty(t, err)
+	assert.Equal(t, 4, network.GetNumberOfPolylines())
+	assert.Equal(t, []int{2, 1, 0}, network.GetPolylineVertices(0))
+	assert.Equal(t, []int{2, 3}, network.GetPolylineVertices(1))
+	assert.Equal(t, []int{2, 4, 5}, network.GetPolylineVertices(2))
+	assert.Equal(t, []int{6, 7, 6}, network.GetPolylineVertices(3))
+	assert.True(t, network.GetPolyline(3).Closed)
+	assert.Equal(t, 1+1+1+2+1+2.0, network.Length())
+	assert.Equal(t, len(edges), len(network.GetEdges()))
+
+	_, err = NewCurveNetworkFromEdges(vertices, [][2]int{{0, 8}})
+	assert.ErrorIs(t, err, ErrInvalidPolyline)
+}
+
+// Test writing and reading a curve network as OBJ l records.
+func TestCurveNetworkOBJ(t *testing.T) {
+	vertices := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(1, 1, 0),
+	}
+
+	network, err := NewCurveNetwork(vertices, [][]int{{0, 1, 2, 0}, {1, 2}})
+	assert.Empty(t, err)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, network.WriteOBJ(&buffer))
+	assert.Contains(t, buffer.String(), "l 1 2 3 1\nl 2 3\n")
+
+	result, err := ReadOBJ(&buffer)
+	assert.Empty(t, err)
+	assert.Equal(t, network, result)
+
+	_, err = NewCurveNetwork(vertices, [][]int{{0}})
+	assert.ErrorIs(t, err, ErrInvalidPolyline)
+}
@@ -0,0 +1,187 @@
+// Package curve implements polylines and networks of polylines (e.g. feature
+// curves, slice contours and boundary loops).
+package curve
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Piecewise linear curve through a sequence of points. A closed polyline
+// has a segment from the last point back to the first (the first point is
+// not repeated).
+type Polyline struct {
+	Points []meshx.Vector
+	Closed bool
+}
+
+// Construct a Polyline from its points.
+func NewPolyline(points []meshx.Vector, closed bool) Polyline {
+	return Polyline{points, closed}
+}
+
+// Get the number of segments.
+func (p Polyline) GetNumberOfSegments() int {
+	if len(p.Points) < 2 {
+		return 0
+	}
+
+	if p.Closed {
+		return len(p.Points)
+	}
+
+	return len(p.Points) - 1
+}
+
+// Get a segment by index.
+func (p Polyline) GetSegment(index int) meshx.Segment {
+	return meshx.NewSegment(p.Points[index], p.Points[(index+1)%len(p.Points)])
+}
+
+// Compute the length.
+func (p Polyline) Length() float64 {
+	var length float64
+
+	for i := range p.GetNumberOfSegments() {
+		length += p.GetSegment(i).Length()
+	}
+
+	return length
+}
+
+// Compute the cumulative length at the start of each segment followed by the
+// total length.
+func (p Polyline) getArcLengths() []float64 {
+	lengths := make([]float64, p.GetNumberOfSegments()+1)
+
+	for i := range p.GetNumberOfSegments() {
+		lengths[i+1] = lengths[i] + p.GetSegment(i).Length()
+	}
+
+	return lengths
+}
+
+// Compute the point at an arc length from the start, clamped to the curve.
+func (p Polyline) PointAt(length float64) meshx.Vector {
+	lengths := p.getArcLengths()
+
+	if len(lengths) == 1 {
+		return p.Points[0]
+	}
+
+	return p.pointAt(lengths, length)
+}
+
+// Compute the point at an arc length from the cumulative lengths.
+func (p Polyline) pointAt(lengths []float64, length float64) meshx.Vector {
+	n := len(lengths) - 1
+	length = max(0, min(lengths[n], length))
+	index := sort.SearchFloat64s(lengths, length) - 1
+	index = max(0, min(n-1, index))
+	segment := p.GetSegment(index)
+
+	if size := lengths[index+1] - lengths[index]; size > 0 {
+		return segment.PointAt((length - lengths[index]) / size)
+	}
+
+	return segment.P
+}
+
+// Resample the polyline with a number of points uniformly spaced by arc
+// length. The end points of an open polyline are retained.
+func (p Polyline) Resample(count int) Polyline {
+	if count <= 0 || len(p.Points) == 0 {
+		return Polyline{Points: make([]meshx.Vector, 0), Closed: p.Closed}
+	}
+
+	lengths := p.getArcLengths()
+	points := make([]meshx.Vector, count)
+
+	if len(lengths) == 1 {
+		for i := range points {
+			points[i] = p.Points[0]
+		}
+
+		return Polyline{points, p.Closed}
+	}
+
+	total := lengths[len(lengths)-1]
+	spacing := total / float64(max(1, count-1))
+
+	if p.Closed {
+		spacing = total / float64(count)
+	}
+
+	for i := range points {
+		points[i] = p.pointAt(lengths, float64(i)*spacing)
+	}
+
+	return Polyline{points, p.Closed}
+}
+
+// Resample the polyline with points spaced by at most a length.
+func (p Polyline) ResampleBySpacing(spacing float64) Polyline {
+	segments := max(1, int(math.Ceil(p.Length()/spacing)))
+
+	if p.Closed {
+		return p.Resample(segments)
+	}
+
+	return p.Resample(segments + 1)
+}
+
+// Smooth the polyline by Laplacian smoothing, moving each point by a factor
+// in (0, 1] toward the midpoint of its neighbors for a number of
+// iterations. The end points of an open polyline are fixed.
+func (p Polyline) Smooth(iterations int, factor float64) Polyline {
+	points := append([]meshx.Vector(nil), p.Points...)
+	n := len(points)
+
+	if n < 3 {
+		return Polyline{points, p.Closed}
+	}
+
+	for range iterations {
+		previous := append([]meshx.Vector(nil), points...)
+
+		for i := range points {
+			if !p.Closed && (i == 0 || i == n-1) {
+				continue
+			}
+
+			midpoint := previous[(i+n-1)%n].Add(previous[(i+1)%n]).MulScalar(0.5)
+			points[i] = previous[i].Add(midpoint.Sub(previous[i]).MulScalar(factor))
+		}
+	}
+
+	return Polyline{points, p.Closed}
+}
+
+// Compute the closest point on the polyline to a point.
+func (p Polyline) ClosestPoint(point meshx.Vector) meshx.Vector {
+	if p.GetNumberOfSegments() == 0 {
+		return p.Points[0]
+	}
+
+	var closest meshx.Vector
+
+	distance := math.Inf(1)
+
+	for i := range p.GetNumberOfSegments() {
+		candidate := p.GetSegment(i).ClosestPoint(point)
+
+		if d := candidate.Sub(point).Mag(); d < distance {
+			closest = candidate
+			distance = d
+		}
+	}
+
+	return closest
+}
+
+// Get the axis-aligned bounding box.
+func (p Polyline) GetAABB() meshx.AABB {
+	return meshx.NewAABBFromVectors(p.Points)
+}
@@ -0,0 +1,80 @@
+package curve
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate the unit square as a closed polyline.
+func newTestSquare() Polyline {
+	points := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(1, 1, 0),
+		meshx.NewVector(0, 1, 0),
+	}
+
+	return NewPolyline(points, true)
+}
+
+// Test the length of open and closed polylines.
+func TestPolylineLength(t *testing.T) {
+	square := newTestSquare()
+	assert.Equal(t, 4, square.GetNumberOfSegments())
+	assert.Equal(t, 4.0, square.Length())
+
+	square.Closed = false
+	assert.Equal(t, 3, square.GetNumberOfSegments())
+	assert.Equal(t, 3.0, square.Length())
+	assert.Equal(t, meshx.NewVector(1, 0.5, 0), square.PointAt(1.5))
+	assert.Equal(t, meshx.NewVector(0, 1, 0), square.PointAt(10))
+}
+
+// Test resampling a polyline uniformly by arc length.
+func TestPolylineResample(t *testing.T) {
+	square := newTestSquare()
+
+	result := square.Resample(8)
+	assert.True(t, result.Closed)
+	assert.Equal(t, 8, len(result.Points))
+	assert.Equal(t, meshx.NewVector(0.5, 0, 0), result.Points[1])
+	assert.InDelta(t, 4, result.Length(), 1e-12)
+
+	square.Closed = false
+	result = square.ResampleBySpacing(0.25)
+	assert.Equal(t, 13, len(result.Points))
+	assert.Equal(t, square.Points[0], result.Points[0])
+	assert.Equal(t, square.Points[3], result.Points[12])
+	assert.InDelta(t, 3, result.Length(), 1e-12)
+}
+
+// Test smoothing a polyline.
+func TestPolylineSmooth(t *testing.T) {
+	square := newTestSquare()
+
+	result := square.Resample(64).Smooth(100, 0.5)
+	assert.Less(t, result.Length(), 4.0)
+
+	center := meshx.NewVector(0.5, 0.5, 0)
+	radius := result.Points[0].Sub(center).Mag()
+
+	for _, point := range result.Points {
+		assert.InDelta(t, radius, point.Sub(center).Mag(), 0.05*radius)
+	}
+
+	square.Closed = false
+	result = square.Smooth(10, 0.5)
+	assert.Equal(t, square.Points[0], result.Points[0])
+	assert.Equal(t, square.Points[3], result.Points[3])
+	assert.Less(t, result.Length(), math.Sqrt(2)+1)
+}
+
+// Test the closest point on a polyline.
+func TestPolylineClosestPoint(t *testing.T) {
+	square := newTestSquare()
+	assert.Equal(t, meshx.NewVector(0.5, 0, 0), square.ClosestPoint(meshx.NewVector(0.5, -1, 0)))
+	assert.Equal(t, meshx.NewVector(0, 0.5, 0), square.ClosestPoint(meshx.NewVector(-1, 0.5, 0)))
+}
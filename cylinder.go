@@ -0,0 +1,88 @@
+package meshx
+
+import (
+	"math"
+)
+
+// Solid cylinder in three-dimensional Cartesian space defined by the end
+// points of its axis and its radius. A cylinder with coincident end points
+// is the sphere of the radius about them.
+type Cylinder struct {
+	P      Vector
+	Q      Vector
+	Radius float64
+}
+
+// Construct a Cylinder from the end points of its axis and its radius.
+func NewCylinder(p, q Vector, radius float64) Cylinder {
+	return Cylinder{p, q, radius}
+}
+
+// Compute the bounding AABB.
+func (c Cylinder) GetAABB() AABB {
+	axis := c.Q.Sub(c.P)
+	extent := NewVector(c.Radius, c.Radius, c.Radius)
+
+	if length := axis.Mag(); length > 0 {
+		u := axis.DivScalar(length)
+
+		for i := 0; i < 3; i++ {
+			extent[i] = c.Radius * math.Sqrt(max(0, 1-u[i]*u[i]))
+		}
+	}
+
+	minBound := NewVector(min(c.P[0], c.Q[0]), min(c.P[1], c.Q[1]), min(c.P[2], c.Q[2]))
+	maxBound := NewVector(max(c.P[0], c.Q[0]), max(c.P[1], c.Q[1]), max(c.P[2], c.Q[2]))
+
+	return NewAABBFromBounds(minBound.Sub(extent), maxBound.Add(extent))
+}
+
+// Compute the distance to a point. The distance is zero inside the cylinder.
+func (c Cylinder) DistanceTo(point Vector) float64 {
+	axis := c.Q.Sub(c.P)
+	length := axis.Mag()
+	d := point.Sub(c.P)
+
+	if length == 0 {
+		return max(0, d.Mag()-c.Radius)
+	}
+
+	u := axis.DivScalar(length)
+	t := d.Dot(u)
+	axial := max(0, -t, t-length)
+	radial := max(0, d.Sub(u.MulScalar(t)).Mag()-c.Radius)
+	return math.Sqrt(axial*axial + radial*radial)
+}
+
+// Implement the IntersectsAABB interface.
+func (c Cylinder) IntersectsAABB(query AABB) bool {
+	return gjkIntersects(c.support, query.support)
+}
+
+// Implement the IntersectsTriangle interface.
+func (c Cylinder) IntersectsTriangle(query Triangle) bool {
+	return gjkIntersects(c.support, query.support)
+}
+
+// Implement the support function of a cylinder.
+func (c Cylinder) support(d Vector) Vector {
+	axis := c.Q.Sub(c.P)
+	p := c.P
+
+	if axis.Dot(d) > 0 {
+		p = c.Q
+	}
+
+	perp := d
+
+	if length := axis.Mag(); length > 0 {
+		u := axis.DivScalar(length)
+		perp = d.Sub(u.MulScalar(u.Dot(d)))
+	}
+
+	if mag := perp.Mag(); mag > 0 {
+		p = p.Add(perp.MulScalar(c.Radius / mag))
+	}
+
+	return p
+}
@@ -0,0 +1,111 @@
+package meshx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test a cylinder/triangle intersection through the triangle.
+func TestCylinderIntersectsTriangleThrough(t *testing.T) {
+	cylinder := NewCylinder(NewVector(0.25, 0.25, -1), NewVector(0.25, 0.25, 1), 0.1)
+	triangle := NewTriangle(NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(0, 1, 0))
+
+	assert.True(t, cylinder.IntersectsTriangle(triangle))
+	assert.True(t, triangle.IntersectsCylinder(cylinder))
+}
+
+// Test a cylinder/triangle intersection beside the triangle edge.
+func TestCylinderIntersectsTriangleBeside(t *testing.T) {
+	triangle := NewTriangle(NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(0, 1, 0))
+
+	hit := NewCylinder(NewVector(0.5, -0.05, -1), NewVector(0.5, -0.05, 1), 0.1)
+	assert.True(t, hit.IntersectsTriangle(triangle))
+
+	miss := NewCylinder(NewVector(0.5, -0.15, -1), NewVector(0.5, -0.15, 1), 0.1)
+	assert.False(t, miss.IntersectsTriangle(triangle))
+}
+
+// Test a cylinder/triangle intersection beyond the cylinder cap.
+func TestCylinderIntersectsTriangleCap(t *testing.T) {
+	triangle := NewTriangle(NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(0, 1, 0))
+
+	miss := NewCylinder(NewVector(0.25, 0.25, 0.1), NewVector(0.25, 0.25, 1), 0.5)
+	assert.False(t, miss.IntersectsTriangle(triangle))
+
+	hit := NewCylinder(NewVector(0.25, 0.25, -0.1), NewVector(0.25, 0.25, 1), 0.5)
+	assert.True(t, hit.IntersectsTriangle(triangle))
+}
+
+// Test a cylinder/AABB intersection near a corner.
+func TestCylinderIntersectsAABBCorner(t *testing.T) {
+	aabb := NewAABB(NewVector(0.5, 0.5, 0.5), NewVector(0.5, 0.5, 0.5))
+
+	hit := NewCylinder(NewVector(1.05, 1.05, -1), NewVector(1.05, 1.05, 2), 0.1)
+	assert.True(t, hit.IntersectsAABB(aabb))
+	assert.True(t, aabb.IntersectsCylinder(hit))
+
+	miss := NewCylinder(NewVector(1.1, 1.1, -1), NewVector(1.1, 1.1, 2), 0.1)
+	assert.False(t, miss.IntersectsAABB(aabb))
+}
+
+// Test the bounding AABB of a cylinder.
+func TestCylinderGetAABB(t *testing.T) {
+	cylinder := NewCylinder(NewVector(0, 0, 0), NewVector(0, 0, 2), 0.5)
+	aabb := cylinder.GetAABB()
+
+	assert.Equal(t, NewVector(-0.5, -0.5, 0), aabb.GetMinBound())
+	assert.Equal(t, NewVector(0.5, 0.5, 2), aabb.GetMaxBound())
+}
+
+// Test a capsule/triangle intersection near the capsule end.
+func TestCapsuleIntersectsTriangle(t *testing.T) {
+	triangle := NewTriangle(NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(0, 1, 0))
+
+	hit := NewCapsule(NewVector(0.25, 0.25, 0.45), NewVector(0.25, 0.25, 1), 0.5)
+	assert.True(t, hit.IntersectsTriangle(triangle))
+	assert.True(t, triangle.IntersectsCapsule(hit))
+
+	miss := NewCapsule(NewVector(0.25, 0.25, 0.55), NewVector(0.25, 0.25, 1), 0.5)
+	assert.False(t, miss.IntersectsTriangle(triangle))
+}
+
+// Test a capsule/AABB intersection near a corner (rounded).
+func TestCapsuleIntersectsAABBCorner(t *testing.T) {
+	aabb := NewAABB(NewVector(0.5, 0.5, 0.5), NewVector(0.5, 0.5, 0.5))
+
+	miss := NewCapsule(NewVector(1.2, 1.2, 1.2), NewVector(2, 2, 2), 0.3)
+	assert.False(t, miss.IntersectsAABB(aabb))
+
+	hit := NewCapsule(NewVector(1.15, 1.15, 1.15), NewVector(2, 2, 2), 0.3)
+	assert.True(t, hit.IntersectsAABB(aabb))
+}
+
+// Test the distance from a cylinder to points inside, beside and beyond it.
+func TestCylinderDistanceTo(t *testing.T) {
+	cylinder := NewCylinder(NewVector(0, 0, 0), NewVector(0, 0, 2), 1)
+
+	assert.Equal(t, 0.0, cylinder.DistanceTo(NewVector(0.5, 0, 1)))
+	assert.InDelta(t, 1.0, cylinder.DistanceTo(NewVector(0, 2, 1)), 1e-12)
+	assert.InDelta(t, 0.5, cylinder.DistanceTo(NewVector(0.5, 0, -0.5)), 1e-12)
+	assert.InDelta(t, 5.0, cylinder.DistanceTo(NewVector(4, 0, 6)), 1e-12)
+}
+
+// Test a cylinder with coincident end points is a sphere.
+func TestCylinderZeroLength(t *testing.T) {
+	center := NewVector(0.25, 0.25, 0.5)
+	cylinder := NewCylinder(center, center, 0.4)
+
+	aabb := cylinder.GetAABB()
+	assert.Equal(t, center, aabb.Center)
+	assert.InDelta(t, 0, aabb.HalfSize.Sub(NewVector(0.4, 0.4, 0.4)).Mag(), 1e-12)
+
+	triangle := NewTriangle(NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(0, 1, 0))
+	assert.False(t, cylinder.IntersectsTriangle(triangle))
+	assert.InDelta(t, 0.1, cylinder.DistanceTo(NewVector(0.25, 0.25, 0)), 1e-12)
+
+	cylinder.Radius = 0.6
+	assert.True(t, cylinder.IntersectsTriangle(triangle))
+	assert.True(t, cylinder.IntersectsAABB(NewAABB(NewVector(0.25, 0.25, -0.5), NewVector(1, 1, 0.45))))
+	assert.False(t, cylinder.IntersectsAABB(NewAABB(NewVector(0.25, 0.25, -0.5), NewVector(1, 1, 0.35))))
+}
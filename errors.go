@@ -5,5 +5,6 @@ import (
 )
 
 var (
-	ErrNonManifold = errors.New("non-manifold mesh")
+	ErrNonManifold       = errors.New("non-manifold mesh")
+	ErrSingularTransform = errors.New("singular transform")
 )
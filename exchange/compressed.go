@@ -0,0 +1,860 @@
+package exchange
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	compressedMagic   = "MXCZ"
+	compressedVersion = 3
+
+	// Versions without the metadata block or the named sets (still read).
+	compressedVersionNoMetadata = 1
+	compressedVersionNoSets     = 2
+
+	// Maximum size of the metadata block in bytes.
+	compressedMaxMetadata = 1 << 24
+
+	// Default number of bits per quantized vertex coordinate.
+	CompressedPositionBits = 16
+)
+
+var (
+	ErrCompressedInvalidFormat = errors.New("invalid compressed mesh format")
+	ErrCompressedInvalidBits   = errors.New("invalid number of position bits")
+	ErrCompressedInvalidSet    = errors.New("invalid set to compress")
+)
+
+// Options for writing a compressed mesh.
+type CompressedOptions struct {
+	// Number of bits per quantized vertex coordinate (1 to 32). The default
+	// is CompressedPositionBits.
+	PositionBits int
+
+	// Conversion of the coordinates written (e.g. to millimeters in a Y-up
+	// system). The faces are reversed if the conversion is mirrored. The
+	// coordinate system of the metadata is the target of the conversion
+	// unless the conversion is the identity.
+	Conversion meshx.Conversion
+
+	// Metadata written ahead of the mesh.
+	Metadata CompressedMetadata
+}
+
+// Metadata of a compressed mesh. The metadata is written uncompressed ahead
+// of the mesh so it may be read without reading the mesh (see
+// ReadCompressedMetadata).
+type CompressedMetadata struct {
+	// Units and convention of the coordinates.
+	CoordinateSystem meshx.CoordinateSystem
+
+	// Provenance of the mesh (e.g. the tool or source it was created from).
+	Provenance string
+
+	// Custom key/value pairs.
+	Values map[string]string
+}
+
+// CompressedWriter manages writing a compressed binary mesh. The vertex
+// positions are quantized to a uniform grid over the bounding box and delta
+// encoded, the connectivity is delta encoded, and the result is entropy
+// coded with DEFLATE.
+type CompressedWriter struct {
+	writer      io.Writer
+	options     CompressedOptions
+	vertices    []meshx.Vector
+	faces       [][]int
+	facePatches []int
+	patches     []string
+	sets        []meshx.Set
+}
+
+// Construct a CompressedWriter from an io.Writer interface.
+func NewCompressedWriter(writer io.Writer, options CompressedOptions) *CompressedWriter {
+	return &CompressedWriter{
+		writer:      writer,
+		options:     options,
+		vertices:    make([]meshx.Vector, 0),
+		faces:       make([][]int, 0),
+		facePatches: make([]int, 0),
+		patches:     make([]string, 0),
+	}
+}
+
+// Write a mesh read from a MeshReader in the compressed format. The metadata
+// of a CompressedReader source is written unless set by the options and the
+// named sets of a SetReader source are written.
+func WriteCompressed(writer io.Writer, source meshx.MeshReader, options CompressedOptions) error {
+	w := NewCompressedWriter(writer, options)
+	vertices := make([]meshx.Vector, source.GetNumberOfVertices())
+	faces := make([][]int, source.GetNumberOfFaces())
+	facePatches := make([]int, source.GetNumberOfFaces())
+	patches := make([]string, source.GetNumberOfPatches())
+
+	for i := range vertices {
+		vertices[i] = source.GetVertex(i)
+	}
+
+	for i := range faces {
+		faces[i] = source.GetFace(i)
+		facePatches[i] = source.GetFacePatch(i)
+	}
+
+	for i := range patches {
+		patches[i] = source.GetPatch(i)
+	}
+
+	w.SetVertices(vertices)
+	w.SetFaces(faces)
+	w.SetFacePatches(facePatches)
+	w.SetPatches(patches)
+
+	if reader, ok := source.(meshx.SetReader); ok {
+		w.SetSets(reader.GetSets())
+	}
+
+	if reader, ok := source.(*CompressedReader); ok && options.Metadata.isZero() {
+		w.options.Metadata = reader.metadata
+	}
+
+	return w.Write()
+}
+
+// Set the vertices to write.
+func (w *CompressedWriter) SetVertices(vertices []meshx.Vector) {
+	w.vertices = vertices
+}
+
+// Set the faces to write.
+func (w *CompressedWriter) SetFaces(faces [][]int) {
+	w.faces = faces
+}
+
+// Set the face patches to write.
+func (w *CompressedWriter) SetFacePatches(facePatches []int) {
+	w.facePatches = facePatches
+}
+
+// Set the patches to write.
+func (w *CompressedWriter) SetPatches(patches []string) {
+	w.patches = patches
+}
+
+// Set the named sets of faces or vertices to write.
+func (w *CompressedWriter) SetSets(sets []meshx.Set) {
+	w.sets = sets
+}
+
+// Write the data to the io.Writer interface.
+func (w *CompressedWriter) Write() error {
+	converted := *w
+	converted.vertices = w.options.Conversion.ConvertPoints(w.vertices)
+	converted.faces = w.options.Conversion.ConvertFaces(w.faces)
+	return converted.write()
+}
+
+// Write the converted data.
+func (w *CompressedWriter) write() error {
+	bits := w.options.PositionBits
+
+	if bits == 0 {
+		bits = CompressedPositionBits
+	}
+
+	if bits < 1 || bits > 32 {
+		return ErrCompressedInvalidBits
+	}
+
+	var minBound, maxBound meshx.Vector
+
+	if len(w.vertices) > 0 {
+		aabb := meshx.NewAABBFromVectors(w.vertices)
+		minBound = aabb.GetMinBound()
+		maxBound = aabb.GetMaxBound()
+	}
+
+	header := bytes.NewBuffer(nil)
+	header.WriteString(compressedMagic)
+	binary.Write(header, binary.LittleEndian, uint32(compressedVersion))
+	binary.Write(header, binary.LittleEndian, uint32(bits))
+	binary.Write(header, binary.LittleEndian, [3]float64(minBound))
+	binary.Write(header, binary.LittleEndian, [3]float64(maxBound))
+
+	metadata := w.options.Metadata
+
+	if !w.options.Conversion.IsIdentity() {
+		metadata.CoordinateSystem = w.options.Conversion.To
+	}
+
+	block := metadata.encode()
+	binary.Write(header, binary.LittleEndian, uint32(len(block)))
+	header.Write(block)
+
+	if _, err := w.writer.Write(header.Bytes()); err != nil {
+		return err
+	}
+
+	compressor, err := flate.NewWriter(w.writer, flate.BestCompression)
+
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(compressor)
+	e := varintEncoder{writer: writer}
+
+	e.writeUvarint(uint64(len(w.patches)))
+
+	for _, patch := range w.patches {
+		e.writeUvarint(uint64(len(patch)))
+		e.writeBytes([]byte(patch))
+	}
+
+	// Quantized positions as deltas from the previous vertex.
+	e.writeUvarint(uint64(len(w.vertices)))
+	scale := float64(uint64(1)<<bits - 1)
+	var previous [3]int64
+
+	for _, vertex := range w.vertices {
+		for i := 0; i < 3; i++ {
+			var q int64
+
+			if extent := maxBound[i] - minBound[i]; extent > 0 {
+				q = int64(math.Round((vertex[i] - minBound[i]) / extent * scale))
+			}
+
+			e.writeVarint(q - previous[i])
+			previous[i] = q
+		}
+	}
+
+	// Face sizes and vertex indices as deltas from the previous index.
+	e.writeUvarint(uint64(len(w.faces)))
+	var last int
+
+	for _, face := range w.faces {
+		e.writeUvarint(uint64(len(face)))
+
+		for _, vertex := range face {
+			if vertex < 0 || vertex >= len(w.vertices) {
+				return meshx.ErrInvalidFace
+			}
+
+			e.writeVarint(int64(vertex - last))
+			last = vertex
+		}
+	}
+
+	// Face patches as runs of (patch, count).
+	for i := 0; i < len(w.faces); {
+		patch := w.getFacePatch(i)
+		j := i + 1
+
+		for j < len(w.faces) && w.getFacePatch(j) == patch {
+			j++
+		}
+
+		e.writeVarint(int64(patch))
+		e.writeUvarint(uint64(j - i))
+		i = j
+	}
+
+	// Named sets with their sorted indices as deltas from the previous index.
+	e.writeUvarint(uint64(len(w.sets)))
+
+	for _, set := range w.sets {
+		e.writeUvarint(uint64(len(set.Name)))
+		e.writeBytes([]byte(set.Name))
+		e.writeUvarint(uint64(set.Kind))
+		e.writeUvarint(uint64(len(set.Indices)))
+		previous := 0
+
+		for _, index := range set.Indices {
+			if index < previous {
+				return ErrCompressedInvalidSet
+			}
+
+			e.writeUvarint(uint64(index - previous))
+			previous = index
+		}
+	}
+
+	if e.err != nil {
+		return e.err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	return compressor.Close()
+}
+
+// Get the patch of a face or -1 if not set.
+func (w *CompressedWriter) getFacePatch(index int) int {
+	if index < len(w.facePatches) {
+		return w.facePatches[index]
+	}
+	return -1
+}
+
+// Write a mesh read from a MeshReader to a compressed file.
+func WriteCompressedToPath(path string, source meshx.MeshReader, options CompressedOptions) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	if err := WriteCompressed(file, source, options); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Check if the metadata is the zero value (no values or an empty map).
+func (m CompressedMetadata) isZero() bool {
+	return m.CoordinateSystem == meshx.CoordinateSystem{} && m.Provenance == "" && len(m.Values) == 0
+}
+
+// Encode the metadata block. The values are sorted by key so the encoding
+// is reproducible.
+func (m CompressedMetadata) encode() []byte {
+	block := make([]byte, 0)
+	appendString := func(value string) {
+		block = binary.AppendUvarint(block, uint64(len(value)))
+		block = append(block, value...)
+	}
+
+	var leftHanded uint64
+
+	if m.CoordinateSystem.LeftHanded {
+		leftHanded = 1
+	}
+
+	block = binary.AppendUvarint(block, uint64(m.CoordinateSystem.Unit))
+	block = binary.AppendUvarint(block, uint64(m.CoordinateSystem.UpAxis))
+	block = binary.AppendUvarint(block, leftHanded)
+	appendString(m.Provenance)
+
+	keys := make([]string, 0, len(m.Values))
+
+	for key := range m.Values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+	block = binary.AppendUvarint(block, uint64(len(keys)))
+
+	for _, key := range keys {
+		appendString(key)
+		appendString(m.Values[key])
+	}
+
+	return block
+}
+
+// Decode the metadata block.
+func decodeCompressedMetadata(block []byte) (CompressedMetadata, error) {
+	var metadata CompressedMetadata
+	reader := bytes.NewReader(block)
+
+	readUvarint := func() (int, error) {
+		value, err := binary.ReadUvarint(reader)
+
+		if err != nil || value > uint64(len(block)) {
+			return 0, ErrCompressedInvalidFormat
+		}
+
+		return int(value), nil
+	}
+
+	readString := func() (string, error) {
+		length, err := readUvarint()
+
+		if err != nil {
+			return "", err
+		}
+
+		value := make([]byte, length)
+
+		if _, err := io.ReadFull(reader, value); err != nil {
+			return "", ErrCompressedInvalidFormat
+		}
+
+		return string(value), nil
+	}
+
+	values := make([]int, 3)
+
+	for i := range values {
+		value, err := readUvarint()
+
+		if err != nil {
+			return metadata, err
+		}
+
+		values[i] = value
+	}
+
+	if values[2] > 1 {
+		return metadata, ErrCompressedInvalidFormat
+	}
+
+	metadata.CoordinateSystem = meshx.CoordinateSystem{
+		Unit:       meshx.Unit(values[0]),
+		UpAxis:     meshx.UpAxis(values[1]),
+		LeftHanded: values[2] == 1,
+	}
+
+	provenance, err := readString()
+
+	if err != nil {
+		return metadata, err
+	}
+
+	metadata.Provenance = provenance
+
+	count, err := readUvarint()
+
+	if err != nil {
+		return metadata, err
+	}
+
+	metadata.Values = make(map[string]string)
+
+	for i := 0; i < count; i++ {
+		key, err := readString()
+
+		if err != nil {
+			return metadata, err
+		}
+
+		value, err := readString()
+
+		if err != nil {
+			return metadata, err
+		}
+
+		metadata.Values[key] = value
+	}
+
+	return metadata, nil
+}
+
+// Varint writer retaining the first error.
+type varintEncoder struct {
+	writer *bufio.Writer
+	buffer [binary.MaxVarintLen64]byte
+	err    error
+}
+
+// Write an unsigned varint.
+func (e *varintEncoder) writeUvarint(value uint64) {
+	n := binary.PutUvarint(e.buffer[:], value)
+	e.writeBytes(e.buffer[:n])
+}
+
+// Write a signed (zigzag) varint.
+func (e *varintEncoder) writeVarint(value int64) {
+	n := binary.PutVarint(e.buffer[:], value)
+	e.writeBytes(e.buffer[:n])
+}
+
+// Write raw bytes.
+func (e *varintEncoder) writeBytes(data []byte) {
+	if e.err == nil {
+		_, e.err = e.writer.Write(data)
+	}
+}
+
+// CompressedReader manages parsing a compressed binary mesh.
+type CompressedReader struct {
+	reader      io.Reader
+	vertices    []meshx.Vector
+	faces       [][]int
+	facePatches []int
+	patches     []string
+	sets        []meshx.Set
+	metadata    CompressedMetadata
+	conversion  meshx.Conversion
+}
+
+// Construct a CompressedReader from an io.Reader interface.
+func NewCompressedReader(reader io.Reader) *CompressedReader {
+	return &CompressedReader{
+		reader:      reader,
+		vertices:    make([]meshx.Vector, 0),
+		faces:       make([][]int, 0),
+		facePatches: make([]int, 0),
+		patches:     make([]string, 0),
+		sets:        make([]meshx.Set, 0),
+		metadata:    CompressedMetadata{Values: make(map[string]string)},
+	}
+}
+
+// Read a compressed mesh from a file path.
+func ReadCompressedFromPath(path string) (*CompressedReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	compressedReader := NewCompressedReader(file)
+
+	if err := compressedReader.Read(); err != nil {
+		return nil, err
+	}
+
+	return compressedReader, nil
+}
+
+// Header of a compressed mesh.
+type compressedHeader struct {
+	version  uint32
+	bits     uint32
+	minBound [3]float64
+	maxBound [3]float64
+	metadata CompressedMetadata
+}
+
+// Read the header (and metadata) of a compressed mesh.
+func readCompressedHeader(reader io.Reader) (compressedHeader, error) {
+	var magic [4]byte
+	var header compressedHeader
+
+	for _, value := range []any{&magic, &header.version, &header.bits, &header.minBound, &header.maxBound} {
+		if err := binary.Read(reader, binary.LittleEndian, value); err != nil {
+			return header, ErrCompressedInvalidFormat
+		}
+	}
+
+	if string(magic[:]) != compressedMagic || header.bits < 1 || header.bits > 32 {
+		return header, ErrCompressedInvalidFormat
+	}
+
+	switch header.version {
+	case compressedVersionNoMetadata:
+		header.metadata.Values = make(map[string]string)
+		return header, nil
+	case compressedVersionNoSets, compressedVersion:
+	default:
+		return header, ErrCompressedInvalidFormat
+	}
+
+	var length uint32
+
+	if err := binary.Read(reader, binary.LittleEndian, &length); err != nil || length > compressedMaxMetadata {
+		return header, ErrCompressedInvalidFormat
+	}
+
+	block := make([]byte, length)
+
+	if _, err := io.ReadFull(reader, block); err != nil {
+		return header, ErrCompressedInvalidFormat
+	}
+
+	metadata, err := decodeCompressedMetadata(block)
+	header.metadata = metadata
+
+	return header, err
+}
+
+// Read the metadata of a compressed mesh without reading the mesh.
+func ReadCompressedMetadata(reader io.Reader) (CompressedMetadata, error) {
+	header, err := readCompressedHeader(reader)
+	return header.metadata, err
+}
+
+// Read the metadata of a compressed mesh from a file path without reading
+// the mesh.
+func ReadCompressedMetadataFromPath(path string) (CompressedMetadata, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return CompressedMetadata{}, err
+	}
+	defer file.Close()
+
+	return ReadCompressedMetadata(file)
+}
+
+// Read the compressed mesh.
+func (r *CompressedReader) Read() error {
+	header, err := readCompressedHeader(r.reader)
+
+	if err != nil {
+		return err
+	}
+
+	bits, minBound, maxBound := header.bits, header.minBound, header.maxBound
+	r.metadata = header.metadata
+
+	decompressor := flate.NewReader(r.reader)
+	defer decompressor.Close()
+
+	reader := bufio.NewReader(decompressor)
+
+	readUvarint := func(limit uint64) (int, error) {
+		value, err := binary.ReadUvarint(reader)
+
+		if err != nil || value > limit {
+			return 0, ErrCompressedInvalidFormat
+		}
+
+		return int(value), nil
+	}
+
+	readVarint := func() (int64, error) {
+		value, err := binary.ReadVarint(reader)
+
+		if err != nil {
+			return 0, ErrCompressedInvalidFormat
+		}
+
+		return value, nil
+	}
+
+	nPatches, err := readUvarint(math.MaxInt32)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < nPatches; i++ {
+		length, err := readUvarint(math.MaxUint16)
+
+		if err != nil {
+			return err
+		}
+
+		name := make([]byte, length)
+
+		if _, err := io.ReadFull(reader, name); err != nil {
+			return ErrCompressedInvalidFormat
+		}
+
+		r.patches = append(r.patches, string(name))
+	}
+
+	nVertices, err := readUvarint(math.MaxInt32)
+
+	if err != nil {
+		return err
+	}
+
+	scale := float64(uint64(1)<<bits - 1)
+	var q [3]int64
+
+	for i := 0; i < nVertices; i++ {
+		var vertex meshx.Vector
+
+		for j := 0; j < 3; j++ {
+			delta, err := readVarint()
+
+			if err != nil {
+				return err
+			}
+
+			q[j] += delta
+			vertex[j] = minBound[j] + float64(q[j])/scale*(maxBound[j]-minBound[j])
+		}
+
+		r.vertices = append(r.vertices, vertex)
+	}
+
+	nFaces, err := readUvarint(math.MaxInt32)
+
+	if err != nil {
+		return err
+	}
+
+	var last int64
+
+	for i := 0; i < nFaces; i++ {
+		size, err := readUvarint(math.MaxUint16)
+
+		if err != nil {
+			return err
+		}
+
+		if size < 3 {
+			return ErrCompressedInvalidFormat
+		}
+
+		face := make([]int, size)
+
+		for j := range face {
+			delta, err := readVarint()
+
+			if err != nil {
+				return err
+			}
+
+			last += delta
+
+			if last < 0 || last >= int64(nVertices) {
+				return ErrCompressedInvalidFormat
+			}
+
+			face[j] = int(last)
+		}
+
+		r.faces = append(r.faces, face)
+	}
+
+	for len(r.facePatches) < nFaces {
+		patch, err := readVarint()
+
+		if err != nil {
+			return err
+		}
+
+		count, err := readUvarint(uint64(nFaces - len(r.facePatches)))
+
+		if err != nil || count == 0 || patch < -1 || patch >= int64(nPatches) {
+			return ErrCompressedInvalidFormat
+		}
+
+		for j := 0; j < count; j++ {
+			r.facePatches = append(r.facePatches, int(patch))
+		}
+	}
+
+	if header.version < compressedVersion {
+		return nil
+	}
+
+	nSets, err := readUvarint(math.MaxInt32)
+
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < nSets; i++ {
+		length, err := readUvarint(math.MaxUint16)
+
+		if err != nil {
+			return err
+		}
+
+		name := make([]byte, length)
+
+		if _, err := io.ReadFull(reader, name); err != nil {
+			return ErrCompressedInvalidFormat
+		}
+
+		kind, err := readUvarint(uint64(meshx.VertexSet))
+
+		if err != nil {
+			return err
+		}
+
+		limit := nFaces
+
+		if meshx.SetKind(kind) == meshx.VertexSet {
+			limit = nVertices
+		}
+
+		count, err := readUvarint(uint64(limit))
+
+		if err != nil {
+			return err
+		}
+
+		set := meshx.Set{Name: string(name), Kind: meshx.SetKind(kind), Indices: make([]int, count)}
+		index := 0
+
+		for j := range set.Indices {
+			delta, err := readUvarint(uint64(limit))
+
+			if err != nil {
+				return err
+			}
+
+			if index += delta; index >= limit {
+				return ErrCompressedInvalidFormat
+			}
+
+			set.Indices[j] = index
+		}
+
+		r.sets = append(r.sets, set)
+	}
+
+	return nil
+}
+
+// Implement the SetReader interface.
+func (r *CompressedReader) GetSets() []meshx.Set {
+	return r.sets
+}
+
+// Get the metadata read.
+func (r *CompressedReader) GetMetadata() CompressedMetadata {
+	return r.metadata
+}
+
+// Set the conversion of the coordinates read (e.g. from millimeters in a
+// Y-up system). The faces are reversed if the conversion is mirrored.
+func (r *CompressedReader) SetConversion(conversion meshx.Conversion) {
+	r.conversion = conversion
+}
+
+// Implement the MeshReader interface.
+func (r *CompressedReader) GetVertex(index int) meshx.Vector {
+	return r.conversion.ConvertPoint(r.vertices[index])
+}
+
+// Implement the MeshReader interface.
+func (r *CompressedReader) GetNumberOfVertices() int {
+	return len(r.vertices)
+}
+
+// Implement the MeshReader interface.
+func (r *CompressedReader) GetFace(index int) []int {
+	return r.conversion.ConvertFace(r.faces[index])
+}
+
+// Implement the MeshReader interface.
+func (r *CompressedReader) GetFacePatch(index int) int {
+	return r.facePatches[index]
+}
+
+// Implement the MeshReader interface.
+func (r *CompressedReader) GetNumberOfFaces() int {
+	return len(r.faces)
+}
+
+// Implement the MeshReader interface.
+func (r *CompressedReader) GetNumberOfFaceEdges() int {
+	var count int
+
+	for _, face := range r.faces {
+		count += len(face)
+	}
+
+	return count
+}
+
+// Implement the MeshReader interface.
+func (r *CompressedReader) GetPatch(index int) string {
+	return r.patches[index]
+}
+
+// Implement the MeshReader interface.
+func (r *CompressedReader) GetNumberOfPatches() int {
+	return len(r.patches)
+}
@@ -0,0 +1,224 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test writing and reading a compressed mesh.
+func TestCompressedWriteRead(t *testing.T) {
+	source := newTestSphere(64, 32)
+
+	for _, bits := range []int{8, 16, 24} {
+		var buffer bytes.Buffer
+		options := CompressedOptions{PositionBits: bits}
+		assert.Empty(t, WriteCompressed(&buffer, source, options))
+
+		reader := NewCompressedReader(&buffer)
+		assert.Empty(t, reader.Read())
+
+		assert.Equal(t, source.GetNumberOfVertices(), reader.GetNumberOfVertices())
+		assert.Equal(t, source.faces, reader.faces)
+		assert.Equal(t, source.GetNumberOfFaceEdges(), reader.GetNumberOfFaceEdges())
+		assert.Equal(t, []string{"sphere"}, reader.patches)
+
+		// The quantization error is at most half a step of the 2x2x2 bounds
+		// along each axis.
+		tolerance := math.Sqrt(3) / float64(uint64(1)<<bits-1)
+
+		for i := 0; i < source.GetNumberOfVertices(); i++ {
+			assert.InDelta(t, 0, source.GetVertex(i).Sub(reader.GetVertex(i)).Mag(), tolerance)
+		}
+
+		for i := 0; i < reader.GetNumberOfFaces(); i++ {
+			assert.Equal(t, 0, reader.GetFacePatch(i))
+		}
+	}
+}
+
+// Test the compressed mesh is smaller than a gzip OBJ.
+func TestCompressedSize(t *testing.T) {
+	source := newTestSphere(128, 64)
+
+	var compressed, obj bytes.Buffer
+	assert.Empty(t, WriteCompressed(&compressed, source, CompressedOptions{}))
+
+	writer := meshx.NewOBJWriter(&obj)
+	writer.SetVertices(source.vertices)
+	writer.SetFaces(source.faces)
+	assert.Empty(t, writer.Write())
+
+	assert.Less(t, compressed.Len(), obj.Len()/4)
+}
+
+// Test writing and reading a compressed mesh from a file with mixed patches.
+func TestCompressedWriteReadPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mesh.mxcz")
+	source, err := meshx.ReadOBJFromPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	assert.Empty(t, WriteCompressedToPath(path, source, CompressedOptions{}))
+
+	reader, err := ReadCompressedFromPath(path)
+	assert.Empty(t, err)
+	assert.Equal(t, source.GetNumberOfFaces(), reader.GetNumberOfFaces())
+	assert.Equal(t, source.GetNumberOfPatches(), reader.GetNumberOfPatches())
+
+	for i := 0; i < source.GetNumberOfFaces(); i++ {
+		assert.Equal(t, source.GetFace(i), reader.GetFace(i))
+		assert.Equal(t, source.GetFacePatch(i), reader.GetFacePatch(i))
+	}
+
+	for i := 0; i < source.GetNumberOfVertices(); i++ {
+		assert.Equal(t, source.GetVertex(i), reader.GetVertex(i))
+	}
+}
+
+// Test writing and reading invalid compressed meshes.
+func TestCompressedInvalid(t *testing.T) {
+	source := newTestSphere(8, 4)
+
+	var buffer bytes.Buffer
+	err := WriteCompressed(&buffer, source, CompressedOptions{PositionBits: 40})
+	assert.ErrorIs(t, err, ErrCompressedInvalidBits)
+
+	buffer.Reset()
+	assert.Empty(t, WriteCompressed(&buffer, source, CompressedOptions{}))
+	data := buffer.Bytes()
+
+	reader := NewCompressedReader(bytes.NewReader(data[:len(data)/2]))
+	assert.ErrorIs(t, reader.Read(), ErrCompressedInvalidFormat)
+
+	reader = NewCompressedReader(bytes.NewReader([]byte("MXPM")))
+	assert.ErrorIs(t, reader.Read(), ErrCompressedInvalidFormat)
+
+	// Faces with fewer than three vertices.
+	for _, face := range [][]int{{}, {0}, {0, 1}} {
+		buffer.Reset()
+		writer := NewCompressedWriter(&buffer, CompressedOptions{})
+		writer.SetVertices(source.vertices)
+		writer.SetFaces([][]int{{0, 1, 2}, face})
+		assert.Empty(t, writer.Write())
+
+		reader = NewCompressedReader(&buffer)
+		assert.ErrorIs(t, reader.Read(), ErrCompressedInvalidFormat)
+	}
+}
+
+// Test writing a compressed mesh in millimeters of a left-handed system and
+// reading it back in meters.
+func TestCompressedConversion(t *testing.T) {
+	source := newTestSphere(16, 8)
+	system := meshx.CoordinateSystem{Unit: meshx.UnitMillimeter, LeftHanded: true}
+
+	var buffer bytes.Buffer
+	options := CompressedOptions{Conversion: meshx.NewConversion(meshx.CoordinateSystem{}, system)}
+	assert.Empty(t, WriteCompressed(&buffer, source, options))
+
+	reader := NewCompressedReader(&buffer)
+	assert.Empty(t, reader.Read())
+	assert.InDelta(t, 1000, reader.vertices[0].Mag(), 0.1)
+	assert.Equal(t, source.GetFace(0)[0], reader.faces[0][0])
+	assert.NotEqual(t, source.GetFace(0), reader.faces[0])
+
+	reader.SetConversion(meshx.NewConversion(system, meshx.CoordinateSystem{}))
+
+	for i := 0; i < source.GetNumberOfVertices(); i++ {
+		assert.InDelta(t, 0, source.GetVertex(i).Sub(reader.GetVertex(i)).Mag(), 1e-4)
+	}
+
+	for i := 0; i < source.GetNumberOfFaces(); i++ {
+		assert.Equal(t, source.GetFace(i), reader.GetFace(i))
+	}
+}
+
+// Test writing and reading the metadata of a compressed mesh.
+func TestCompressedMetadata(t *testing.T) {
+	source := newTestSphere(16, 8)
+	metadata := CompressedMetadata{
+		CoordinateSystem: meshx.CoordinateSystem{Unit: meshx.UnitMillimeter, UpAxis: meshx.UpAxisY},
+		Provenance:       "test",
+		Values:           map[string]string{"part": "sphere", "revision": "2"},
+	}
+
+	var buffer bytes.Buffer
+	assert.Empty(t, WriteCompressed(&buffer, source, CompressedOptions{Metadata: metadata}))
+	data := buffer.Bytes()
+
+	read, err := ReadCompressedMetadata(bytes.NewReader(data))
+	assert.Empty(t, err)
+	assert.Equal(t, metadata, read)
+
+	reader := NewCompressedReader(bytes.NewReader(data))
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, metadata, reader.GetMetadata())
+
+	// The metadata of a compressed source survives a round trip.
+	var copied bytes.Buffer
+	assert.Empty(t, WriteCompressed(&copied, reader, CompressedOptions{}))
+
+	read, err = ReadCompressedMetadata(&copied)
+	assert.Empty(t, err)
+	assert.Equal(t, metadata, read)
+
+	// The metadata of a version 1 mesh (without the block) is empty.
+	offset := 60
+	length := int(binary.LittleEndian.Uint32(data[offset:]))
+	legacy := append([]byte{}, data[:offset]...)
+	legacy = append(legacy, data[offset+4+length:]...)
+	binary.LittleEndian.PutUint32(legacy[4:], compressedVersionNoMetadata)
+
+	reader = NewCompressedReader(bytes.NewReader(legacy))
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, source.GetNumberOfFaces(), reader.GetNumberOfFaces())
+	assert.Equal(t, CompressedMetadata{Values: map[string]string{}}, reader.GetMetadata())
+
+	// The coordinate system is the target of a conversion.
+	system := meshx.CoordinateSystem{Unit: meshx.UnitMillimeter, UpAxis: meshx.UpAxisY, LeftHanded: true}
+	options := CompressedOptions{Conversion: meshx.NewConversion(meshx.CoordinateSystem{}, system)}
+	buffer.Reset()
+	assert.Empty(t, WriteCompressed(&buffer, source, options))
+
+	read, err = ReadCompressedMetadata(&buffer)
+	assert.Empty(t, err)
+	assert.Equal(t, system, read.CoordinateSystem)
+}
+
+// Test writing and reading the named sets of a mesh.
+func TestCompressedSets(t *testing.T) {
+	source, err := halfedge.NewHalfEdgeMesh(newTestSphere(16, 8))
+	assert.Empty(t, err)
+	assert.Empty(t, source.AddSet("refinement_zone_A", meshx.FaceSet, []int{40, 3, 7, 3}))
+	assert.Empty(t, source.AddSet("probes", meshx.VertexSet, []int{0, 100}))
+
+	var buffer bytes.Buffer
+	assert.Empty(t, WriteCompressed(&buffer, source.GetMeshReader(), CompressedOptions{}))
+	data := buffer.Bytes()
+
+	reader := NewCompressedReader(bytes.NewReader(data))
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, source.GetSets(), reader.GetSets())
+
+	mesh, err := halfedge.NewHalfEdgeMesh(reader)
+	assert.Empty(t, err)
+
+	set, ok := mesh.GetSet("refinement_zone_A", meshx.FaceSet)
+	assert.True(t, ok)
+	assert.Equal(t, []int{3, 7, 40}, set.Indices)
+
+	// A version 2 mesh has no sets.
+	legacy := append([]byte{}, data...)
+	binary.LittleEndian.PutUint32(legacy[4:], compressedVersionNoSets)
+
+	reader = NewCompressedReader(bytes.NewReader(legacy))
+	assert.Empty(t, reader.Read())
+	assert.Empty(t, reader.GetSets())
+	assert.Equal(t, source.GetNumberOfFaces(), reader.GetNumberOfFaces())
+}
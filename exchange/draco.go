@@ -0,0 +1,860 @@
+package exchange
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/planar"
+)
+
+// Subset of the Draco (https://google.github.io/draco/) bitstream supported
+// for decoding: triangular meshes with the sequential encoding method (raw
+// or rANS compressed connectivity) or the edgebreaker method (2.2 with the
+// standard or valence traversal), and generic, integer, or quantized
+// attributes with raw or rANS compressed values and the difference or
+// parallelogram prediction schemes (with the delta or wrap transform). Only
+// the attributes up to the positions are decoded, and the edgebreaker ones
+// must be per vertex in depth-first order. The entries of the file metadata
+// are kept and the rest is skipped. Other features are rejected with
+// ErrDracoUnsupported. The encoder writes the raw sequential subset only.
+
+const (
+	dracoMagic        = "DRACO"
+	dracoMajorVersion = 2
+	dracoMinorVersion = 2
+
+	dracoEncoderTypeMesh     = 1
+	dracoMethodSequential    = 0
+	dracoMethodEdgebreaker   = 1
+	dracoMetadataFlag        = 0x8000
+	dracoConnectivityRANS    = 0
+	dracoConnectivityRaw     = 1
+	dracoAttributePosition   = 0
+	dracoDecoderGeneric      = 0
+	dracoDecoderInteger      = 1
+	dracoDecoderQuantization = 2
+)
+
+// Draco attribute data types.
+const (
+	dracoInt8    = 1
+	dracoUint8   = 2
+	dracoInt16   = 3
+	dracoUint16  = 4
+	dracoInt32   = 5
+	dracoUint32  = 6
+	dracoInt64   = 7
+	dracoUint64  = 8
+	dracoFloat32 = 9
+	dracoFloat64 = 10
+	dracoBool    = 11
+)
+
+var (
+	ErrDracoInvalidFormat = errors.New("invalid draco format")
+	ErrDracoUnsupported   = errors.New("unsupported draco feature")
+	ErrDracoInvalidBits   = errors.New("invalid number of position bits")
+)
+
+// Options for writing a Draco mesh.
+type DracoOptions struct {
+	// Number of bits per quantized position component (1 to 30). Zero
+	// writes the positions as 32-bit floats.
+	PositionBits int
+
+	// Conversion of the coordinates written (e.g. to millimeters in a Y-up
+	// system). The faces are reversed if the conversion is mirrored.
+	Conversion meshx.Conversion
+}
+
+// DracoReader manages parsing a Draco compressed mesh.
+type DracoReader struct {
+	reader     io.Reader
+	vertices   []meshx.Vector
+	faces      [][]int
+	metadata   map[string]string
+	conversion meshx.Conversion
+}
+
+// Construct a DracoReader from an io.Reader interface.
+func NewDracoReader(reader io.Reader) *DracoReader {
+	return &DracoReader{
+		reader:   reader,
+		vertices: make([]meshx.Vector, 0),
+		faces:    make([][]int, 0),
+	}
+}
+
+// Read a Draco mesh from a file path.
+func ReadDracoFromPath(path string) (*DracoReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dracoReader := NewDracoReader(file)
+
+	if err := dracoReader.Read(); err != nil {
+		return nil, err
+	}
+
+	return dracoReader, nil
+}
+
+// Attribute declared in a Draco attribute decoder.
+type dracoAttribute struct {
+	attributeType int
+	dataType      int
+	components    int
+	decoderType   int
+	values        [][]float64
+}
+
+// Read the Draco mesh.
+func (r *DracoReader) Read() error {
+	d := dracoDecoder{reader: bufio.NewReader(r.reader)}
+
+	var magic [5]byte
+	var major, minor, encoderType, method uint8
+	var flags uint16
+
+	d.read(&magic, &major, &minor, &encoderType, &method, &flags)
+
+	if d.err != nil || string(magic[:]) != dracoMagic {
+		return ErrDracoInvalidFormat
+	}
+
+	if major != dracoMajorVersion || encoderType != dracoEncoderTypeMesh {
+		return ErrDracoUnsupported
+	}
+
+	if method != dracoMethodSequential && (method != dracoMethodEdgebreaker || minor < 2) {
+		return ErrDracoUnsupported
+	}
+
+	r.metadata = make(map[string]string)
+
+	if flags&dracoMetadataFlag != 0 {
+		if err := d.readMetadata(r.metadata); err != nil {
+			return err
+		}
+	}
+
+	var mesh *dracoMeshData
+	var nValues int
+	var err error
+
+	if method == dracoMethodSequential {
+		nValues, err = r.readSequentialConnectivity(&d, minor)
+		mesh = &dracoMeshData{faces: r.faces}
+	} else {
+		mesh, err = r.readEdgebreakerConnectivity(&d)
+
+		if mesh != nil {
+			nValues = len(mesh.valueCorners)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var nDecoders uint8
+	d.read(&nDecoders)
+
+	// The edgebreaker attribute decoders are sequenced by the traversal of
+	// the faces, where only per vertex values in depth-first order are
+	// supported.
+	isSupported := make([]bool, nDecoders)
+
+	for i := range isSupported {
+		isSupported[i] = true
+
+		if method == dracoMethodEdgebreaker {
+			var dataID int8
+			var decoderType, sequence uint8
+			d.read(&dataID, &decoderType, &sequence)
+			isSupported[i] = decoderType == dracoVertexAttribute && sequence == dracoSequenceDepthFirst
+		}
+	}
+
+	if d.err != nil {
+		return ErrDracoInvalidFormat
+	}
+
+	decoders := make([][]*dracoAttribute, nDecoders)
+
+	for i := range decoders {
+		nAttributes := d.readVarint()
+
+		if d.err != nil || nAttributes == 0 || nAttributes > 256 {
+			return ErrDracoInvalidFormat
+		}
+
+		for j := uint64(0); j < nAttributes; j++ {
+			var attributeType, dataType, components, normalized uint8
+			d.read(&attributeType, &dataType, &components, &normalized)
+			d.readVarint()
+
+			if d.err != nil || components == 0 || dataType == 0 || dataType > dracoBool {
+				return ErrDracoInvalidFormat
+			}
+
+			decoders[i] = append(decoders[i], &dracoAttribute{
+				attributeType: int(attributeType),
+				dataType:      int(dataType),
+				components:    int(components),
+			})
+		}
+
+		for _, attribute := range decoders[i] {
+			var decoderType uint8
+			d.read(&decoderType)
+			attribute.decoderType = int(decoderType)
+
+			if decoderType > dracoDecoderQuantization {
+				return ErrDracoUnsupported
+			}
+		}
+	}
+
+	// Decode the attributes up to the positions.
+	var positions *dracoAttribute
+
+	for i, attributes := range decoders {
+		if positions != nil {
+			break
+		}
+
+		if !isSupported[i] {
+			return ErrDracoUnsupported
+		}
+
+		for _, attribute := range attributes {
+			if err := d.readPortableValues(attribute, nValues, mesh); err != nil {
+				return err
+			}
+		}
+
+		for _, attribute := range attributes {
+			if attribute.decoderType == dracoDecoderQuantization {
+				if err := d.readQuantization(attribute); err != nil {
+					return err
+				}
+			}
+
+			if attribute.attributeType == dracoAttributePosition && positions == nil {
+				positions = attribute
+			}
+		}
+	}
+
+	if d.err != nil || positions == nil {
+		return ErrDracoInvalidFormat
+	}
+
+	if method == dracoMethodSequential {
+		for _, value := range positions.values {
+			var vertex meshx.Vector
+			copy(vertex[:], value)
+			r.vertices = append(r.vertices, vertex)
+		}
+	} else {
+		r.setEdgebreakerVertices(positions.values, mesh)
+	}
+
+	return nil
+}
+
+// Read the connectivity of the sequential method and get the number of
+// points.
+func (r *DracoReader) readSequentialConnectivity(d *dracoDecoder, minor uint8) (int, error) {
+	var nFaces, nPoints uint64
+
+	if minor < 2 {
+		var f, p uint32
+		d.read(&f, &p)
+		nFaces, nPoints = uint64(f), uint64(p)
+	} else {
+		nFaces = d.readVarint()
+		nPoints = d.readVarint()
+	}
+
+	var connectivity uint8
+	d.read(&connectivity)
+
+	if d.err != nil || nFaces > math.MaxInt32 || nPoints > math.MaxInt32 {
+		return 0, ErrDracoInvalidFormat
+	}
+
+	switch connectivity {
+	case dracoConnectivityRaw:
+		return int(nPoints), r.readRawIndices(d, nFaces, nPoints, minor)
+	case dracoConnectivityRANS:
+		return int(nPoints), r.readCompressedIndices(d, nFaces, nPoints)
+	default:
+		return 0, ErrDracoUnsupported
+	}
+}
+
+// Read the connectivity of the edgebreaker method and get the connectivity
+// of the values of the attributes in the order of the traversal.
+func (r *DracoReader) readEdgebreakerConnectivity(d *dracoDecoder) (*dracoMeshData, error) {
+	table, err := d.readEdgebreaker()
+	if err != nil {
+		return nil, err
+	}
+
+	order, corners, ok := table.getDepthFirstOrder()
+
+	if !ok {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	mesh := dracoMeshData{
+		table:        table,
+		valueCorners: corners,
+		vertexValues: make([]int, len(table.corners)),
+	}
+
+	for i := range mesh.vertexValues {
+		mesh.vertexValues[i] = -1
+	}
+
+	for i, vertex := range order {
+		mesh.vertexValues[vertex] = i
+	}
+
+	for i := 0; i < len(table.vertices); i += 3 {
+		r.faces = append(r.faces, []int{table.vertices[i], table.vertices[i+1], table.vertices[i+2]})
+	}
+
+	return &mesh, nil
+}
+
+// Set the vertices from the values of the positions in traversal order. The
+// vertices keep their decoded indices, less those not in a face.
+func (r *DracoReader) setEdgebreakerVertices(values [][]float64, mesh *dracoMeshData) {
+	indices := make([]int, len(mesh.vertexValues))
+
+	for i, value := range mesh.vertexValues {
+		indices[i] = -1
+
+		if value >= 0 {
+			indices[i] = len(r.vertices)
+
+			var vertex meshx.Vector
+			copy(vertex[:], values[value])
+			r.vertices = append(r.vertices, vertex)
+		}
+	}
+
+	for _, face := range r.faces {
+		for j, vertex := range face {
+			face[j] = indices[vertex]
+		}
+	}
+}
+
+// Read the faces with their point indices stored directly.
+func (r *DracoReader) readRawIndices(d *dracoDecoder, nFaces, nPoints uint64, minor uint8) error {
+	for i := uint64(0); i < nFaces && d.err == nil; i++ {
+		face := make([]int, 3)
+
+		for j := range face {
+			var index uint64
+
+			switch {
+			case nPoints < 1<<8:
+				var value uint8
+				d.read(&value)
+				index = uint64(value)
+			case nPoints < 1<<16:
+				var value uint16
+				d.read(&value)
+				index = uint64(value)
+			case nPoints < 1<<21 && minor >= 2:
+				index = d.readVarint()
+			default:
+				var value uint32
+				d.read(&value)
+				index = uint64(value)
+			}
+
+			if index >= nPoints {
+				return ErrDracoInvalidFormat
+			}
+
+			face[j] = int(index)
+		}
+
+		r.faces = append(r.faces, face)
+	}
+
+	return nil
+}
+
+// Read the faces with their point indices coded as the rANS compressed
+// differences from the previous index (the sign in the least significant
+// bit).
+func (r *DracoReader) readCompressedIndices(d *dracoDecoder, nFaces, nPoints uint64) error {
+	symbols, err := d.readSymbols(int(3*nFaces), 1)
+	if err != nil {
+		return err
+	}
+
+	var last int64
+
+	for i := 0; i < len(symbols); i += 3 {
+		face := make([]int, 3)
+
+		for j := range face {
+			symbol := symbols[i+j]
+			difference := int64(symbol >> 1)
+
+			if symbol&1 == 1 {
+				difference = -difference
+			}
+
+			if last += difference; last < 0 || last >= int64(nPoints) {
+				return ErrDracoInvalidFormat
+			}
+
+			face[j] = int(last)
+		}
+
+		r.faces = append(r.faces, face)
+	}
+
+	return nil
+}
+
+// Get the entries of the file metadata (the values are raw bytes, such as
+// a name or a little-endian number).
+func (r *DracoReader) GetMetadata() map[string]string {
+	return r.metadata
+}
+
+// Set the conversion of the coordinates read (e.g. from millimeters in a
+// Y-up system). The faces are reversed if the conversion is mirrored.
+func (r *DracoReader) SetConversion(conversion meshx.Conversion) {
+	r.conversion = conversion
+}
+
+// Implement the MeshReader interface.
+func (r *DracoReader) GetVertex(index int) meshx.Vector {
+	return r.conversion.ConvertPoint(r.vertices[index])
+}
+
+// Implement the MeshReader interface.
+func (r *DracoReader) GetNumberOfVertices() int {
+	return len(r.vertices)
+}
+
+// Implement the MeshReader interface.
+func (r *DracoReader) GetFace(index int) []int {
+	return r.conversion.ConvertFace(r.faces[index])
+}
+
+// Implement the MeshReader interface. Draco meshes do not have patches.
+func (r *DracoReader) GetFacePatch(index int) int {
+	return -1
+}
+
+// Implement the MeshReader interface.
+func (r *DracoReader) GetNumberOfFaces() int {
+	return len(r.faces)
+}
+
+// Implement the MeshReader interface.
+func (r *DracoReader) GetNumberOfFaceEdges() int {
+	return 3 * len(r.faces)
+}
+
+// Implement the MeshReader interface. Draco meshes do not have patches.
+func (r *DracoReader) GetPatch(index int) string {
+	return ""
+}
+
+// Implement the MeshReader interface.
+func (r *DracoReader) GetNumberOfPatches() int {
+	return 0
+}
+
+// Little-endian Draco buffer decoder retaining the first error.
+type dracoDecoder struct {
+	reader *bufio.Reader
+	err    error
+}
+
+// Read fixed size values.
+func (d *dracoDecoder) read(values ...any) {
+	for _, value := range values {
+		if d.err == nil {
+			d.err = binary.Read(d.reader, binary.LittleEndian, value)
+		}
+	}
+}
+
+// Read an unsigned LEB128 varint.
+func (d *dracoDecoder) readVarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	value, err := binary.ReadUvarint(d.reader)
+	d.err = err
+
+	return value
+}
+
+// Read the values of an attribute in its portable format (raw values for
+// generic attributes, integers otherwise). The integers are decoded from the
+// corrections of their prediction scheme, if any, where the parallelogram
+// prediction follows the connectivity of the values.
+func (d *dracoDecoder) readPortableValues(attribute *dracoAttribute, nValues int, mesh *dracoMeshData) error {
+	attribute.values = make([][]float64, nValues)
+
+	if attribute.decoderType == dracoDecoderGeneric {
+		size := dracoDataTypeLength(attribute.dataType)
+		data := make([]byte, size)
+
+		for i := range attribute.values {
+			attribute.values[i] = make([]float64, attribute.components)
+
+			for j := range attribute.values[i] {
+				if _, err := io.ReadFull(d.reader, data); err != nil {
+					return ErrDracoInvalidFormat
+				}
+
+				attribute.values[i][j] = dracoDecodeValue(attribute.dataType, data)
+			}
+		}
+
+		return nil
+	}
+
+	var prediction, transformType int8
+	d.read(&prediction)
+
+	if prediction != dracoPredictionNone {
+		d.read(&transformType)
+	}
+
+	var compressed uint8
+	d.read(&compressed)
+
+	if d.err != nil {
+		return ErrDracoInvalidFormat
+	}
+
+	if prediction != dracoPredictionNone && prediction != dracoPredictionDifference && prediction != dracoPredictionParallelogram {
+		return ErrDracoUnsupported
+	}
+
+	if prediction != dracoPredictionNone && transformType != dracoTransformDelta && transformType != dracoTransformWrap {
+		return ErrDracoUnsupported
+	}
+
+	n := nValues * attribute.components
+	var symbols []uint32
+
+	if compressed != 0 {
+		var err error
+
+		if symbols, err = d.readSymbols(n, attribute.components); err != nil {
+			return err
+		}
+	} else {
+		var nBytes uint8
+		d.read(&nBytes)
+
+		if d.err != nil || nBytes == 0 || nBytes > 4 {
+			return ErrDracoInvalidFormat
+		}
+
+		symbols = make([]uint32, n)
+		data := make([]byte, 4)
+
+		for i := range symbols {
+			if _, err := io.ReadFull(d.reader, data[:nBytes]); err != nil {
+				return ErrDracoInvalidFormat
+			}
+
+			symbols[i] = binary.LittleEndian.Uint32(data)
+		}
+	}
+
+	// Convert the symbols back to signed integers.
+	values := make([]int32, n)
+
+	for i, symbol := range symbols {
+		values[i] = int32(symbol >> 1)
+
+		if symbol&1 == 1 {
+			values[i] = -values[i] - 1
+		}
+	}
+
+	if prediction != dracoPredictionNone {
+		transform := dracoTransform{wrap: transformType == dracoTransformWrap}
+
+		if err := d.readTransform(&transform); err != nil {
+			return err
+		}
+
+		if prediction == dracoPredictionParallelogram {
+			decodeDracoParallelogram(values, attribute.components, mesh, &transform)
+		} else {
+			decodeDracoDifference(values, attribute.components, &transform)
+		}
+	}
+
+	for i := range attribute.values {
+		attribute.values[i] = make([]float64, attribute.components)
+
+		for j := range attribute.values[i] {
+			attribute.values[i][j] = float64(values[i*attribute.components+j])
+		}
+	}
+
+	return nil
+}
+
+// Read the quantization parameters of an attribute and dequantize its
+// values.
+func (d *dracoDecoder) readQuantization(attribute *dracoAttribute) error {
+	minValues := make([]float32, attribute.components)
+	var valueRange float32
+	var bits uint8
+
+	d.read(minValues, &valueRange, &bits)
+
+	if d.err != nil || bits < 1 || bits > 30 {
+		return ErrDracoInvalidFormat
+	}
+
+	delta := float64(valueRange) / float64(uint32(1)<<bits-1)
+
+	for _, value := range attribute.values {
+		for j := range value {
+			value[j] = float64(minValues[j]) + float64(float32(value[j]*delta))
+		}
+	}
+
+	return nil
+}
+
+// Get the length in bytes of a Draco data type.
+func dracoDataTypeLength(dataType int) int {
+	switch dataType {
+	case dracoInt8, dracoUint8, dracoBool:
+		return 1
+	case dracoInt16, dracoUint16:
+		return 2
+	case dracoInt32, dracoUint32, dracoFloat32:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// Decode a little-endian value of a Draco data type.
+func dracoDecodeValue(dataType int, data []byte) float64 {
+	switch dataType {
+	case dracoInt8:
+		return float64(int8(data[0]))
+	case dracoUint8, dracoBool:
+		return float64(data[0])
+	case dracoInt16:
+		return float64(int16(binary.LittleEndian.Uint16(data)))
+	case dracoUint16:
+		return float64(binary.LittleEndian.Uint16(data))
+	case dracoInt32:
+		return float64(int32(binary.LittleEndian.Uint32(data)))
+	case dracoUint32:
+		return float64(binary.LittleEndian.Uint32(data))
+	case dracoInt64:
+		return float64(int64(binary.LittleEndian.Uint64(data)))
+	case dracoUint64:
+		return float64(binary.LittleEndian.Uint64(data))
+	case dracoFloat32:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(data)))
+	default:
+		return math.Float64frombits(binary.LittleEndian.Uint64(data))
+	}
+}
+
+// DracoWriter manages writing a Draco mesh using the sequential encoding
+// method. Faces are triangulated and patches are not written.
+type DracoWriter struct {
+	writer   io.Writer
+	options  DracoOptions
+	vertices []meshx.Vector
+	faces    [][]int
+}
+
+// Construct a DracoWriter from an io.Writer interface.
+func NewDracoWriter(writer io.Writer, options DracoOptions) *DracoWriter {
+	return &DracoWriter{
+		writer:   writer,
+		options:  options,
+		vertices: make([]meshx.Vector, 0),
+		faces:    make([][]int, 0),
+	}
+}
+
+// Set the vertices to write.
+func (w *DracoWriter) SetVertices(vertices []meshx.Vector) {
+	w.vertices = vertices
+}
+
+// Set the faces to write.
+func (w *DracoWriter) SetFaces(faces [][]int) {
+	w.faces = faces
+}
+
+// Set the face patches to write. Draco meshes do not have patches.
+func (w *DracoWriter) SetFacePatches(facePatches []int) {}
+
+// Set the patches to write. Draco meshes do not have patches.
+func (w *DracoWriter) SetPatches(patches []string) {}
+
+// Write the data to the io.Writer interface.
+func (w *DracoWriter) Write() error {
+	converted := *w
+	converted.vertices = w.options.Conversion.ConvertPoints(w.vertices)
+	converted.faces = w.options.Conversion.ConvertFaces(w.faces)
+	return converted.write()
+}
+
+// Write the converted data.
+func (w *DracoWriter) write() error {
+	bits := w.options.PositionBits
+
+	if bits < 0 || bits > 30 {
+		return ErrDracoInvalidBits
+	}
+
+	triangles := make([][3]int, 0, len(w.faces))
+
+	for _, face := range w.faces {
+		for _, vertex := range face {
+			if vertex < 0 || vertex >= len(w.vertices) {
+				return meshx.ErrInvalidFace
+			}
+		}
+
+		triangles = append(triangles, planar.TriangulateIndexedFace(w.vertices, face)...)
+	}
+
+	var buffer bytes.Buffer
+	write := func(values ...any) {
+		for _, value := range values {
+			binary.Write(&buffer, binary.LittleEndian, value)
+		}
+	}
+	writeVarint := func(value uint64) {
+		buffer.Write(binary.AppendUvarint(nil, value))
+	}
+
+	nPoints := len(w.vertices)
+
+	buffer.WriteString(dracoMagic)
+	write(uint8(dracoMajorVersion), uint8(dracoMinorVersion), uint8(dracoEncoderTypeMesh), uint8(dracoMethodSequential), uint16(0))
+	writeVarint(uint64(len(triangles)))
+	writeVarint(uint64(nPoints))
+	write(uint8(dracoConnectivityRaw))
+
+	for _, triangle := range triangles {
+		for _, index := range triangle {
+			switch {
+			case nPoints < 1<<8:
+				write(uint8(index))
+			case nPoints < 1<<16:
+				write(uint16(index))
+			case nPoints < 1<<21:
+				writeVarint(uint64(index))
+			default:
+				write(uint32(index))
+			}
+		}
+	}
+
+	// Single attribute decoder with the position attribute.
+	write(uint8(1))
+	writeVarint(1)
+
+	if bits == 0 {
+		write(uint8(dracoAttributePosition), uint8(dracoFloat32), uint8(3), uint8(0))
+		writeVarint(0)
+		write(uint8(dracoDecoderGeneric))
+
+		for _, vertex := range w.vertices {
+			write(float32(vertex[0]), float32(vertex[1]), float32(vertex[2]))
+		}
+	} else {
+		write(uint8(dracoAttributePosition), uint8(dracoFloat32), uint8(3), uint8(0))
+		writeVarint(0)
+		write(uint8(dracoDecoderQuantization))
+
+		var minValues [3]float32
+		var valueRange float32
+
+		if nPoints > 0 {
+			aabb := meshx.NewAABBFromVectors(w.vertices)
+			minBound := aabb.GetMinBound()
+			minValues = [3]float32{float32(minBound[0]), float32(minBound[1]), float32(minBound[2])}
+			valueRange = float32(2 * max(aabb.HalfSize[0], aabb.HalfSize[1], aabb.HalfSize[2]))
+		}
+
+		scale := float64(uint32(1)<<bits - 1)
+
+		write(int8(dracoPredictionNone), uint8(0), uint8(4))
+
+		for _, vertex := range w.vertices {
+			for j := 0; j < 3; j++ {
+				var q uint32
+
+				if valueRange > 0 {
+					q = uint32(min(scale, max(0, math.Round((vertex[j]-float64(minValues[j]))/float64(valueRange)*scale))))
+				}
+
+				// Symbol of the non-negative integer.
+				write(q << 1)
+			}
+		}
+
+		write(minValues, valueRange, uint8(bits))
+	}
+
+	_, err := w.writer.Write(buffer.Bytes())
+	return err
+}
+
+// Write a mesh read from a MeshReader in the Draco format.
+func WriteDraco(writer io.Writer, source meshx.MeshReader, options DracoOptions) error {
+	w := NewDracoWriter(writer, options)
+	vertices := make([]meshx.Vector, source.GetNumberOfVertices())
+	faces := make([][]int, source.GetNumberOfFaces())
+
+	for i := range vertices {
+		vertices[i] = source.GetVertex(i)
+	}
+
+	for i := range faces {
+		faces[i] = source.GetFace(i)
+	}
+
+	w.SetVertices(vertices)
+	w.SetFaces(faces)
+
+	return w.Write()
+}
@@ -0,0 +1,722 @@
+package exchange
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+)
+
+// Draco edgebreaker traversals, symbols, and attribute sequencing (see
+// mesh_edgebreaker_decoder_impl.cc of the reference implementation).
+const (
+	dracoTraversalStandard = 0
+	dracoTraversalValence  = 2
+
+	dracoSymbolC = 0
+	dracoSymbolS = 1
+	dracoSymbolL = 3
+	dracoSymbolR = 5
+	dracoSymbolE = 7
+
+	dracoVertexAttribute     = 0
+	dracoSequenceDepthFirst  = 0
+	dracoValenceModeMin      = 2
+	dracoValenceModeMax      = 7
+	dracoMaxMetadataDepth    = 64
+	dracoMaxEdgebreakerFaces = math.MaxInt32 / 3
+)
+
+// Symbols of the valence traversal by their index in the contexts.
+var dracoValenceSymbols = [5]int{dracoSymbolC, dracoSymbolS, dracoSymbolL, dracoSymbolR, dracoSymbolE}
+
+// Topology split event of the edgebreaker connectivity: the face of the
+// source symbol (by encoder symbol id) is joined to the face of the split
+// symbol across its left or right edge.
+type dracoTopologySplit struct {
+	source int
+	split  int
+	right  bool
+}
+
+// Decoder of the edgebreaker symbols, either read directly as bits
+// (standard traversal) or from the contexts of the valence of the vertex
+// of the active edge (valence traversal).
+type dracoTraversal struct {
+	bits     *dracoBitReader
+	valence  bool
+	valences []int
+	contexts [][]uint32
+	context  int
+	last     int
+}
+
+// Read the next symbol.
+func (t *dracoTraversal) read() (int, error) {
+	if !t.valence {
+		if t.bits.read(1) == 0 {
+			t.last = dracoSymbolC
+		} else {
+			t.last = dracoSymbolS | int(t.bits.read(2))<<1
+		}
+
+		if t.bits.reader.err != nil {
+			return 0, ErrDracoInvalidFormat
+		}
+
+		return t.last, nil
+	}
+
+	// The first symbol of the valence traversal is always E.
+	if t.context < 0 {
+		t.last = dracoSymbolE
+		return t.last, nil
+	}
+
+	symbols := t.contexts[t.context]
+
+	if len(symbols) == 0 || symbols[len(symbols)-1] >= uint32(len(dracoValenceSymbols)) {
+		return 0, ErrDracoInvalidFormat
+	}
+
+	t.last = dracoValenceSymbols[symbols[len(symbols)-1]]
+	t.contexts[t.context] = symbols[:len(symbols)-1]
+
+	return t.last, nil
+}
+
+// Update the valences of the vertices of the face of the last symbol and
+// select the context of the next symbol by the valence of the vertex next
+// to the active corner.
+func (t *dracoTraversal) reached(table *dracoCornerTable, corner int) {
+	if !t.valence {
+		return
+	}
+
+	vertices := [3]int{
+		table.getVertex(corner),
+		table.getVertex(getDracoNextCorner(corner)),
+		table.getVertex(getDracoPreviousCorner(corner)),
+	}
+
+	var increments [3]int
+
+	switch t.last {
+	case dracoSymbolC, dracoSymbolS:
+		increments = [3]int{0, 1, 1}
+	case dracoSymbolR:
+		increments = [3]int{1, 1, 2}
+	case dracoSymbolL:
+		increments = [3]int{1, 2, 1}
+	case dracoSymbolE:
+		increments = [3]int{2, 2, 2}
+	}
+
+	for i, vertex := range vertices {
+		t.valences[vertex] += increments[i]
+	}
+
+	valence := min(max(t.valences[vertices[1]], dracoValenceModeMin), dracoValenceModeMax)
+	t.context = valence - dracoValenceModeMin
+}
+
+// Merge the valence of a vertex into another.
+func (t *dracoTraversal) merge(destination, source int) {
+	if t.valence {
+		t.valences[destination] += t.valences[source]
+	}
+}
+
+// Read the edgebreaker connectivity as a corner table, where the vertices
+// are numbered by the decoder (removing the vertices merged by the split
+// symbols unless the mesh has attribute seams).
+func (d *dracoDecoder) readEdgebreaker() (*dracoCornerTable, error) {
+	var traversalType, nAttributeData uint8
+	d.read(&traversalType)
+	nVertices := d.readVarint()
+	nFaces := d.readVarint()
+	d.read(&nAttributeData)
+	nSymbols := d.readVarint()
+	nSplitSymbols := d.readVarint()
+
+	if d.err != nil || nFaces > dracoMaxEdgebreakerFaces || nVertices > math.MaxInt32 {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	if nSymbols > nFaces || nFaces > nSymbols+nSymbols/3 || nSplitSymbols > nSymbols {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	if traversalType != dracoTraversalStandard && traversalType != dracoTraversalValence {
+		return nil, ErrDracoUnsupported
+	}
+
+	splits, err := d.readTopologySplits(nFaces)
+	if err != nil {
+		return nil, err
+	}
+
+	maxVertices := int(nVertices + nSplitSymbols)
+	traversal := dracoTraversal{valence: traversalType == dracoTraversalValence, context: -1}
+
+	if !traversal.valence {
+		size := d.readVarint()
+
+		if d.err != nil || size > 1<<31 {
+			return nil, ErrDracoInvalidFormat
+		}
+
+		data := make([]byte, size)
+
+		if _, err := io.ReadFull(d.reader, data); err != nil {
+			return nil, ErrDracoInvalidFormat
+		}
+
+		symbols := dracoDecoder{reader: bufio.NewReader(bytes.NewReader(data))}
+		traversal.bits = &dracoBitReader{reader: &symbols}
+	}
+
+	startFaces, err := d.readRabsDecoder()
+	if err != nil {
+		return nil, err
+	}
+
+	// The attribute seams are not needed for the positions.
+	for range nAttributeData {
+		if _, err := d.readRabsDecoder(); err != nil {
+			return nil, err
+		}
+	}
+
+	if traversal.valence {
+		var mode int8
+		nSplits := d.readVarint()
+		d.read(&mode)
+
+		if d.err != nil || nSplits >= uint64(maxVertices) || mode != 0 {
+			return nil, ErrDracoInvalidFormat
+		}
+
+		traversal.valences = make([]int, maxVertices)
+		traversal.contexts = make([][]uint32, dracoValenceModeMax-dracoValenceModeMin+1)
+
+		for i := range traversal.contexts {
+			n := d.readVarint()
+
+			if d.err != nil || n > nFaces {
+				return nil, ErrDracoInvalidFormat
+			}
+
+			if traversal.contexts[i], err = d.readSymbols(int(n), 1); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	e := dracoEdgebreaker{
+		table:       &dracoCornerTable{},
+		nFaces:      int(nFaces),
+		maxVertices: maxVertices,
+		splits:      splits,
+		traversal:   &traversal,
+		startFaces:  startFaces,
+		removed:     nAttributeData == 0,
+	}
+
+	if err := e.decode(int(nSymbols)); err != nil {
+		return nil, err
+	}
+
+	return e.table, nil
+}
+
+// Read the topology split events, ordered by their source symbol.
+func (d *dracoDecoder) readTopologySplits(nFaces uint64) ([]dracoTopologySplit, error) {
+	n := d.readVarint()
+
+	if d.err != nil || n > nFaces {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	splits := make([]dracoTopologySplit, n)
+	var source uint64
+
+	for i := range splits {
+		source += d.readVarint()
+		delta := d.readVarint()
+
+		if d.err != nil || delta > source || source > nFaces {
+			return nil, ErrDracoInvalidFormat
+		}
+
+		splits[i] = dracoTopologySplit{source: int(source), split: int(source - delta)}
+	}
+
+	bits := dracoBitReader{reader: d}
+
+	for i := range splits {
+		splits[i].right = bits.read(1) == 1
+	}
+
+	if d.err != nil {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	return splits, nil
+}
+
+// State of the decoding of the edgebreaker symbols into a corner table.
+type dracoEdgebreaker struct {
+	table       *dracoCornerTable
+	nFaces      int
+	maxVertices int
+	splits      []dracoTopologySplit
+	traversal   *dracoTraversal
+	startFaces  *dracoRabsDecoder
+	removed     bool
+}
+
+// Add a face of unset corners and get its first corner.
+func (e *dracoEdgebreaker) addFace() int {
+	t := e.table
+	t.vertices = append(t.vertices, -1, -1, -1)
+	t.opposites = append(t.opposites, -1, -1, -1)
+	return len(t.vertices) - 3
+}
+
+// Add a vertex without a corner. False is returned if the mesh has more
+// vertices than encoded.
+func (e *dracoEdgebreaker) addVertex() (int, bool) {
+	t := e.table
+	t.corners = append(t.corners, -1)
+	return len(t.corners) - 1, len(t.corners) <= e.maxVertices
+}
+
+// Decode the faces of the symbols in reverse order of their encoding. The
+// faces are attached to the active edges (by their opposite corner) on a
+// stack, where the edges of the topology splits are pushed when their split
+// symbol is reached. The remaining active edges are closed by the start
+// faces.
+func (e *dracoEdgebreaker) decode(nSymbols int) error {
+	t := e.table
+	active := make([]int, 0)
+	splitCorners := make(map[int]int)
+	merged := make([]int, 0)
+
+	for id := range nSymbols {
+		symbol, err := e.traversal.read()
+		if err != nil {
+			return err
+		}
+
+		corner := e.addFace()
+		isSplit := false
+
+		switch symbol {
+		case dracoSymbolC:
+			// Close the active edge and the edge next to it around the vertex
+			// next to the active corner.
+			if len(active) == 0 {
+				return ErrDracoInvalidFormat
+			}
+
+			a := active[len(active)-1]
+			x := t.getVertex(getDracoNextCorner(a))
+			b := t.getNext(t.corners[x])
+
+			if b < 0 || a == b || t.getOpposite(a) >= 0 || t.getOpposite(b) >= 0 {
+				return ErrDracoInvalidFormat
+			}
+
+			t.setOpposite(a, corner+1)
+			t.setOpposite(b, corner+2)
+
+			previous := t.getVertex(getDracoPreviousCorner(a))
+			next := t.getVertex(getDracoNextCorner(b))
+
+			if x == previous || x == next {
+				return ErrDracoInvalidFormat
+			}
+
+			t.vertices[corner], t.vertices[corner+1], t.vertices[corner+2] = x, next, previous
+			t.corners[previous] = corner + 2
+			active[len(active)-1] = corner
+		case dracoSymbolR, dracoSymbolL:
+			// Extend the active edge by a face with a new vertex. The active
+			// edge is then its right (R) or left (L) edge.
+			if len(active) == 0 {
+				return ErrDracoInvalidFormat
+			}
+
+			a := active[len(active)-1]
+
+			if t.getOpposite(a) >= 0 {
+				return ErrDracoInvalidFormat
+			}
+
+			opposite, left, right := corner+1, corner, corner+2
+
+			if symbol == dracoSymbolR {
+				opposite, left, right = corner+2, corner+1, corner
+			}
+
+			t.setOpposite(opposite, a)
+			vertex, ok := e.addVertex()
+
+			if !ok {
+				return ErrDracoInvalidFormat
+			}
+
+			t.vertices[opposite] = vertex
+			t.corners[vertex] = opposite
+
+			previous := t.getVertex(getDracoPreviousCorner(a))
+			t.vertices[right] = previous
+			t.corners[previous] = right
+			t.vertices[left] = t.getVertex(getDracoNextCorner(a))
+			active[len(active)-1] = corner
+			isSplit = true
+		case dracoSymbolS:
+			// Join the two last active edges (or the active edge and the edge
+			// of a topology split) by a face, merging their vertices.
+			if len(active) == 0 {
+				return ErrDracoInvalidFormat
+			}
+
+			b := active[len(active)-1]
+			active = active[:len(active)-1]
+
+			if c, ok := splitCorners[id]; ok {
+				active = append(active, c)
+			}
+
+			if len(active) == 0 {
+				return ErrDracoInvalidFormat
+			}
+
+			a := active[len(active)-1]
+
+			if a == b || t.getOpposite(a) >= 0 || t.getOpposite(b) >= 0 {
+				return ErrDracoInvalidFormat
+			}
+
+			t.setOpposite(a, corner+2)
+			t.setOpposite(b, corner+1)
+
+			p := t.getVertex(getDracoPreviousCorner(a))
+			previous := t.getVertex(getDracoPreviousCorner(b))
+			t.vertices[corner] = p
+			t.vertices[corner+1] = t.getVertex(getDracoNextCorner(a))
+			t.vertices[corner+2] = previous
+			t.corners[previous] = corner + 2
+
+			// Merge the vertex next to corner b into p.
+			n := getDracoNextCorner(b)
+			vertex := t.getVertex(n)
+			e.traversal.merge(p, vertex)
+			t.corners[p] = t.corners[vertex]
+
+			for first, count := n, 0; n >= 0; count++ {
+				t.vertices[n] = p
+
+				if n = t.getSwingLeft(n); n == first || count > len(t.vertices) {
+					return ErrDracoInvalidFormat
+				}
+			}
+
+			t.corners[vertex] = -1
+			merged = append(merged, vertex)
+			active[len(active)-1] = corner
+		case dracoSymbolE:
+			// Start a new active edge on a face of three new vertices.
+			for i := range 3 {
+				vertex, ok := e.addVertex()
+
+				if !ok {
+					return ErrDracoInvalidFormat
+				}
+
+				t.vertices[corner+i] = vertex
+				t.corners[vertex] = corner + i
+			}
+
+			active = append(active, corner)
+			isSplit = true
+		default:
+			return ErrDracoInvalidFormat
+		}
+
+		e.traversal.reached(t, active[len(active)-1])
+
+		// Add the edges of the face joined to the faces of later split
+		// symbols (by encoder symbol id, in reverse order).
+		for source := nSymbols - id - 1; isSplit && len(e.splits) > 0; {
+			split := e.splits[len(e.splits)-1]
+
+			if split.source > source {
+				return ErrDracoInvalidFormat
+			}
+
+			if split.source != source {
+				break
+			}
+
+			e.splits = e.splits[:len(e.splits)-1]
+			top := active[len(active)-1]
+
+			if split.right {
+				splitCorners[nSymbols-split.split-1] = getDracoNextCorner(top)
+			} else {
+				splitCorners[nSymbols-split.split-1] = getDracoPreviousCorner(top)
+			}
+		}
+	}
+
+	if err := e.decodeStartFaces(active); err != nil {
+		return err
+	}
+
+	if e.removed {
+		return e.removeVertices(merged)
+	}
+
+	return nil
+}
+
+// Close the active edges left by the start faces interior to the mesh. The
+// other start faces are on a boundary and have no face.
+func (e *dracoEdgebreaker) decodeStartFaces(active []int) error {
+	t := e.table
+
+	for i := len(active) - 1; i >= 0; i-- {
+		a := active[i]
+
+		if !e.startFaces.read() {
+			continue
+		}
+
+		if len(t.vertices) >= 3*e.nFaces {
+			return ErrDracoInvalidFormat
+		}
+
+		n := t.getVertex(getDracoNextCorner(a))
+		b := t.getNext(t.corners[n])
+
+		if b < 0 {
+			return ErrDracoInvalidFormat
+		}
+
+		x := t.getVertex(getDracoNextCorner(b))
+		c := t.getNext(t.corners[x])
+
+		if c < 0 || a == b || a == c || b == c {
+			return ErrDracoInvalidFormat
+		}
+
+		if t.getOpposite(a) >= 0 || t.getOpposite(b) >= 0 || t.getOpposite(c) >= 0 {
+			return ErrDracoInvalidFormat
+		}
+
+		p := t.getVertex(getDracoNextCorner(c))
+		corner := e.addFace()
+
+		t.setOpposite(corner, a)
+		t.setOpposite(corner+1, b)
+		t.setOpposite(corner+2, c)
+		t.vertices[corner], t.vertices[corner+1], t.vertices[corner+2] = x, p, n
+	}
+
+	if len(t.vertices) != 3*e.nFaces {
+		return ErrDracoInvalidFormat
+	}
+
+	return nil
+}
+
+// Remove the vertices merged by the split symbols by moving the last valid
+// vertex into each.
+func (e *dracoEdgebreaker) removeVertices(merged []int) error {
+	t := e.table
+	n := len(t.corners)
+
+	for _, vertex := range merged {
+		for n > 0 && t.corners[n-1] < 0 {
+			n--
+		}
+
+		if n == 0 || n-1 < vertex {
+			continue
+		}
+
+		source := n - 1
+		corners, ok := t.getVertexCorners(source)
+
+		if !ok {
+			return ErrDracoInvalidFormat
+		}
+
+		for _, corner := range corners {
+			if t.vertices[corner] != source {
+				return ErrDracoInvalidFormat
+			}
+
+			t.vertices[corner] = vertex
+		}
+
+		t.corners[vertex] = t.corners[source]
+		t.corners[source] = -1
+		n--
+	}
+
+	t.corners = t.corners[:n]
+
+	return nil
+}
+
+// Get the vertices of a corner table in the order of their values (by the
+// depth-first traversal of the faces of the reference encoder) with the
+// corner each is reached from. False is returned if a face has no vertex.
+func (t *dracoCornerTable) getDepthFirstOrder() ([]int, []int, bool) {
+	order := make([]int, 0, len(t.corners))
+	corners := make([]int, 0, len(t.corners))
+	isFaceVisited := make([]bool, len(t.vertices)/3)
+	isVertexVisited := make([]bool, len(t.corners))
+	stack := make([]int, 0)
+
+	visit := func(vertex, corner int) {
+		isVertexVisited[vertex] = true
+		order = append(order, vertex)
+		corners = append(corners, corner)
+	}
+
+	isVisited := func(corner int) bool {
+		return corner < 0 || isFaceVisited[corner/3]
+	}
+
+	for start := 0; start < len(t.vertices); start += 3 {
+		if isFaceVisited[start/3] {
+			continue
+		}
+
+		for _, corner := range [2]int{getDracoNextCorner(start), getDracoPreviousCorner(start)} {
+			if vertex := t.getVertex(corner); vertex < 0 {
+				return nil, nil, false
+			} else if !isVertexVisited[vertex] {
+				visit(vertex, corner)
+			}
+		}
+
+		stack = append(stack[:0], start)
+
+		for len(stack) > 0 {
+			corner := stack[len(stack)-1]
+
+			if isVisited(corner) {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			for {
+				isFaceVisited[corner/3] = true
+				vertex := t.getVertex(corner)
+
+				if vertex < 0 {
+					return nil, nil, false
+				}
+
+				// Continue to the right face around an interior vertex.
+				if !isVertexVisited[vertex] {
+					isBoundary := t.getSwingLeft(t.corners[vertex]) < 0
+					visit(vertex, corner)
+
+					if !isBoundary {
+						if corner = t.getOpposite(getDracoNextCorner(corner)); corner < 0 {
+							return nil, nil, false
+						}
+
+						continue
+					}
+				}
+
+				right := t.getOpposite(getDracoNextCorner(corner))
+				left := t.getOpposite(getDracoPreviousCorner(corner))
+
+				if isVisited(right) && isVisited(left) {
+					stack = stack[:len(stack)-1]
+					break
+				} else if isVisited(right) {
+					corner = left
+				} else if isVisited(left) {
+					corner = right
+				} else {
+					// Traverse the right face first and then the left.
+					stack[len(stack)-1] = left
+					stack = append(stack, right)
+					break
+				}
+			}
+		}
+	}
+
+	return order, corners, true
+}
+
+// Read the metadata of the attributes and the file, keeping the entries of
+// the file metadata.
+func (d *dracoDecoder) readMetadata(entries map[string]string) error {
+	n := d.readVarint()
+
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		d.readVarint()
+
+		if err := d.readMetadataElement(nil, 0); err != nil {
+			return err
+		}
+	}
+
+	return d.readMetadataElement(entries, 0)
+}
+
+// Read a metadata element: its entries (if kept) and its nested elements
+// (skipped) by name.
+func (d *dracoDecoder) readMetadataElement(entries map[string]string, depth int) error {
+	if depth > dracoMaxMetadataDepth {
+		return ErrDracoInvalidFormat
+	}
+
+	n := d.readVarint()
+
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		key := d.readString()
+		value := d.readString()
+
+		if entries != nil && d.err == nil {
+			entries[key] = value
+		}
+	}
+
+	n = d.readVarint()
+
+	for i := uint64(0); i < n && d.err == nil; i++ {
+		d.readString()
+
+		if err := d.readMetadataElement(nil, depth+1); err != nil {
+			return err
+		}
+	}
+
+	if d.err != nil {
+		return ErrDracoInvalidFormat
+	}
+
+	return nil
+}
+
+// Read a string of at most 255 bytes prefixed by its length.
+func (d *dracoDecoder) readString() string {
+	var length uint8
+	d.read(&length)
+	data := make([]byte, length)
+	d.read(data)
+	return string(data)
+}
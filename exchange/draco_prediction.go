@@ -0,0 +1,309 @@
+package exchange
+
+import (
+	"math"
+)
+
+// Draco prediction schemes and transforms.
+const (
+	dracoPredictionNone          = -2
+	dracoPredictionDifference    = 0
+	dracoPredictionParallelogram = 1
+	dracoTransformDelta          = 0
+	dracoTransformWrap           = 1
+)
+
+// Transform of the corrections of a Draco prediction scheme. The wrap
+// transform clamps the predictions to the range of the values and wraps the
+// corrected values around it.
+type dracoTransform struct {
+	wrap     bool
+	minValue int32
+	maxValue int32
+	maxDif   int32
+}
+
+// Read the data of a transform.
+func (d *dracoDecoder) readTransform(transform *dracoTransform) error {
+	if !transform.wrap {
+		return nil
+	}
+
+	d.read(&transform.minValue, &transform.maxValue)
+
+	if d.err != nil {
+		return ErrDracoInvalidFormat
+	}
+
+	dif := int64(transform.maxValue) - int64(transform.minValue)
+
+	if dif < 0 || dif >= math.MaxInt32 {
+		return ErrDracoInvalidFormat
+	}
+
+	transform.maxDif = int32(1 + dif)
+
+	return nil
+}
+
+// Compute the original values from the predicted values and corrections.
+func (t *dracoTransform) original(predicted, corrections, values []int32) {
+	for i, correction := range corrections {
+		value := predicted[i]
+
+		if t.wrap {
+			value = min(max(value, t.minValue), t.maxValue)
+		}
+
+		value += correction
+
+		if t.wrap {
+			if value > t.maxValue {
+				value -= t.maxDif
+			} else if value < t.minValue {
+				value += t.maxDif
+			}
+		}
+
+		values[i] = value
+	}
+}
+
+// Decode the values predicted by the previous value (difference prediction)
+// in place from their corrections.
+func decodeDracoDifference(values []int32, components int, transform *dracoTransform) {
+	if len(values) == 0 {
+		return
+	}
+
+	transform.original(make([]int32, components), values[:components], values[:components])
+
+	for i := components; i < len(values); i += components {
+		transform.original(values[i-components:i], values[i:i+components], values[i:i+components])
+	}
+}
+
+// Connectivity of the values of an attribute for the parallelogram
+// prediction: the corner table of the mesh, the corner each value is
+// predicted from, and the value of each vertex.
+type dracoMeshData struct {
+	faces        [][]int
+	table        *dracoCornerTable
+	valueCorners []int
+	vertexValues []int
+}
+
+// Build the connectivity of the values of the points of the faces on first
+// use (sequential method), where each value is predicted from the first
+// corner of its point.
+func (m *dracoMeshData) build(nPoints int) {
+	if m.table != nil {
+		return
+	}
+
+	m.table = newDracoCornerTable(m.faces, nPoints)
+	m.valueCorners = m.table.corners
+	m.vertexValues = make([]int, nPoints)
+
+	for i := range m.vertexValues {
+		m.vertexValues[i] = i
+	}
+}
+
+// Decode the values predicted by the parallelogram of the triangle opposite
+// a corner of each value (parallelogram prediction) in place from their
+// corrections. Each value is predicted from the values of the triangle
+// across the edge opposite its corner if they precede it, or else from the
+// previous value.
+func decodeDracoParallelogram(values []int32, components int, mesh *dracoMeshData, transform *dracoTransform) {
+	if len(values) == 0 {
+		return
+	}
+
+	mesh.build(len(values) / components)
+	table := mesh.table
+	predicted := make([]int32, components)
+
+	transform.original(predicted, values[:components], values[:components])
+
+	for p := 1; p < len(values)/components; p++ {
+		i := p * components
+		reference := values[i-components : i]
+
+		if corner := mesh.valueCorners[p]; corner >= 0 {
+			if opposite := table.getOpposite(corner); opposite >= 0 {
+				o := mesh.vertexValues[table.getVertex(opposite)]
+				n := mesh.vertexValues[table.getVertex(getDracoNextCorner(opposite))]
+				q := mesh.vertexValues[table.getVertex(getDracoPreviousCorner(opposite))]
+
+				if min(o, n, q) >= 0 && max(o, n, q) < p {
+					for c := range components {
+						predicted[c] = values[n*components+c] + values[q*components+c] - values[o*components+c]
+					}
+
+					reference = predicted
+				}
+			}
+		}
+
+		transform.original(reference, values[i:i+components], values[i:i+components])
+	}
+}
+
+// Corner table of a triangle mesh, where corner c is vertex c % 3 of face
+// c / 3, with a corner of each vertex (the first of its faces, or the
+// left-most of a decoded edgebreaker connectivity).
+type dracoCornerTable struct {
+	vertices  []int
+	opposites []int
+	corners   []int
+}
+
+// Construct the corner table of the faces. The opposite corners are found
+// by the edges, where each edge is matched with the first face with the
+// reversed edge.
+func newDracoCornerTable(faces [][]int, nPoints int) *dracoCornerTable {
+	table := dracoCornerTable{
+		vertices:  make([]int, 3*len(faces)),
+		opposites: make([]int, 3*len(faces)),
+		corners:   make([]int, nPoints),
+	}
+
+	for i := range table.corners {
+		table.corners[i] = -1
+	}
+
+	for i, face := range faces {
+		copy(table.vertices[3*i:], face)
+	}
+
+	// Corner opposite each directed edge (from the next to the previous
+	// vertex of the corner).
+	edges := make(map[[2]int]int)
+
+	for c, vertex := range table.vertices {
+		if vertex < nPoints && table.corners[vertex] < 0 {
+			table.corners[vertex] = c
+		}
+
+		edge := [2]int{table.getVertex(getDracoNextCorner(c)), table.getVertex(getDracoPreviousCorner(c))}
+
+		if _, ok := edges[edge]; !ok {
+			edges[edge] = c
+		}
+	}
+
+	for c := range table.opposites {
+		edge := [2]int{table.getVertex(getDracoPreviousCorner(c)), table.getVertex(getDracoNextCorner(c))}
+
+		if opposite, ok := edges[edge]; ok {
+			table.opposites[c] = opposite
+		} else {
+			table.opposites[c] = -1
+		}
+	}
+
+	return &table
+}
+
+// Get the vertex of a corner.
+func (t *dracoCornerTable) getVertex(corner int) int {
+	return t.vertices[corner]
+}
+
+// Get the corner opposite a corner, or -1 at a boundary.
+func (t *dracoCornerTable) getOpposite(corner int) int {
+	return t.opposites[corner]
+}
+
+// Get the first corner of a point, or -1 if it is not in a face.
+func (t *dracoCornerTable) getPointCorner(point int) int {
+	return t.corners[point]
+}
+
+// Set two corners opposite each other.
+func (t *dracoCornerTable) setOpposite(a, b int) {
+	t.opposites[a] = b
+	t.opposites[b] = a
+}
+
+// Get the next corner of a corner, or -1 if the corner is -1.
+func (t *dracoCornerTable) getNext(corner int) int {
+	if corner < 0 {
+		return -1
+	}
+
+	return getDracoNextCorner(corner)
+}
+
+// Get the corner of the same vertex on the face to the left (swinging about
+// the vertex), or -1 at a boundary.
+func (t *dracoCornerTable) getSwingLeft(corner int) int {
+	if corner < 0 {
+		return -1
+	}
+
+	return t.getNext(t.getOpposite(getDracoNextCorner(corner)))
+}
+
+// Get the corner of the same vertex on the face to the right (swinging
+// about the vertex), or -1 at a boundary.
+func (t *dracoCornerTable) getSwingRight(corner int) int {
+	if corner < 0 {
+		return -1
+	}
+
+	if opposite := t.getOpposite(getDracoPreviousCorner(corner)); opposite >= 0 {
+		return getDracoPreviousCorner(opposite)
+	}
+
+	return -1
+}
+
+// Get the corners of a vertex by swinging left from its corner and then
+// right if a boundary is reached. False is returned if the corners do not
+// form a fan.
+func (t *dracoCornerTable) getVertexCorners(vertex int) ([]int, bool) {
+	start := t.corners[vertex]
+	corners := make([]int, 0)
+
+	for corner := start; corner >= 0; corner = t.getSwingLeft(corner) {
+		if len(corners) > len(t.vertices) {
+			return nil, false
+		}
+
+		corners = append(corners, corner)
+
+		if t.getSwingLeft(corner) == start {
+			return corners, true
+		}
+	}
+
+	for corner := t.getSwingRight(start); corner >= 0; corner = t.getSwingRight(corner) {
+		if len(corners) > len(t.vertices) {
+			return nil, false
+		}
+
+		corners = append(corners, corner)
+	}
+
+	return corners, true
+}
+
+// Get the next corner of the face of a corner.
+func getDracoNextCorner(corner int) int {
+	if corner%3 == 2 {
+		return corner - 2
+	}
+
+	return corner + 1
+}
+
+// Get the previous corner of the face of a corner.
+func getDracoPreviousCorner(corner int) int {
+	if corner%3 == 0 {
+		return corner + 2
+	}
+
+	return corner - 1
+}
@@ -0,0 +1,379 @@
+package exchange
+
+import (
+	"io"
+)
+
+// Draco symbol coding schemes.
+const (
+	dracoSymbolsTagged = 0
+	dracoSymbolsRaw    = 1
+)
+
+// Maximum bit length of the symbols coded with the raw scheme.
+const dracoMaxRawBitLength = 18
+
+// Maximum number of symbols of an rANS probability table.
+const dracoMaxRansSymbols = 1 << 20
+
+// Lower bound of the state of an rABS bit decoder.
+const dracoRabsBase = 4096
+
+// rANS decoder of symbols with a probability table (see the ans.h and
+// rans_symbol_decoder.h of the reference implementation).
+type dracoRansDecoder struct {
+	precision uint32
+	base      uint32
+	probs     []uint32
+	cumProbs  []uint32
+	lookup    []uint32
+	data      []byte
+	offset    int
+	state     uint32
+}
+
+// Read the symbols coded with the tagged or raw scheme. The values are read
+// in groups of components sharing a bit length (tagged scheme only).
+func (d *dracoDecoder) readSymbols(n, components int) ([]uint32, error) {
+	values := make([]uint32, n)
+
+	if n == 0 {
+		return values, nil
+	}
+
+	var scheme uint8
+	d.read(&scheme)
+
+	if d.err != nil {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	switch scheme {
+	case dracoSymbolsTagged:
+		return values, d.readTaggedSymbols(values, components)
+	case dracoSymbolsRaw:
+		return values, d.readRawSymbols(values)
+	default:
+		return nil, ErrDracoInvalidFormat
+	}
+}
+
+// Read the symbols coded directly with rANS.
+func (d *dracoDecoder) readRawSymbols(values []uint32) error {
+	var bitLength uint8
+	d.read(&bitLength)
+
+	if d.err != nil || bitLength == 0 || bitLength > dracoMaxRawBitLength {
+		return ErrDracoInvalidFormat
+	}
+
+	decoder, err := d.readRansDecoder(getDracoRansPrecision(int(bitLength)))
+	if err != nil {
+		return err
+	}
+
+	if len(decoder.probs) == 0 {
+		return nil
+	}
+
+	if err := d.startRansDecoder(decoder); err != nil {
+		return err
+	}
+
+	for i := range values {
+		values[i] = decoder.read()
+	}
+
+	return nil
+}
+
+// Read the symbols coded as their bit length (tag) with rANS followed by
+// their bits.
+func (d *dracoDecoder) readTaggedSymbols(values []uint32, components int) error {
+	if components <= 0 {
+		return ErrDracoInvalidFormat
+	}
+
+	decoder, err := d.readRansDecoder(getDracoRansPrecision(5))
+	if err != nil {
+		return err
+	}
+
+	if len(decoder.probs) == 0 {
+		return ErrDracoInvalidFormat
+	}
+
+	if err := d.startRansDecoder(decoder); err != nil {
+		return err
+	}
+
+	bits := dracoBitReader{reader: d}
+
+	for i := 0; i < len(values); i += components {
+		length := decoder.read()
+
+		if length > 32 {
+			return ErrDracoInvalidFormat
+		}
+
+		for j := i; j < min(i+components, len(values)); j++ {
+			values[j] = bits.read(int(length))
+		}
+	}
+
+	if d.err != nil {
+		return ErrDracoInvalidFormat
+	}
+
+	return nil
+}
+
+// Get the precision (bits) of the rANS coding of symbols of a bit length.
+func getDracoRansPrecision(bitLength int) int {
+	return min(max(3*bitLength/2, 12), 20)
+}
+
+// Read the probability table of an rANS decoder. The probabilities are
+// coded with a variable number of bytes, or as a run of zeros.
+func (d *dracoDecoder) readRansDecoder(precisionBits int) (*dracoRansDecoder, error) {
+	n := d.readVarint()
+
+	if d.err != nil || n > dracoMaxRansSymbols {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	decoder := dracoRansDecoder{
+		precision: 1 << precisionBits,
+		base:      4 << precisionBits,
+		probs:     make([]uint32, n),
+		cumProbs:  make([]uint32, n),
+	}
+
+	for i := 0; i < int(n); i++ {
+		var data uint8
+		d.read(&data)
+
+		if token := data & 3; token == 3 {
+			// Run of zero probabilities.
+			i += int(data >> 2)
+
+			if i >= int(n) {
+				return nil, ErrDracoInvalidFormat
+			}
+		} else {
+			prob := uint32(data >> 2)
+
+			for b := range int(token) {
+				var extra uint8
+				d.read(&extra)
+				prob |= uint32(extra) << (8*(b+1) - 2)
+			}
+
+			decoder.probs[i] = prob
+		}
+	}
+
+	if d.err != nil {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	if n == 0 {
+		return &decoder, nil
+	}
+
+	decoder.lookup = make([]uint32, 0, decoder.precision)
+	var cumProb uint32
+
+	for i, prob := range decoder.probs {
+		decoder.cumProbs[i] = cumProb
+
+		if cumProb += prob; cumProb > decoder.precision {
+			return nil, ErrDracoInvalidFormat
+		}
+
+		for range prob {
+			decoder.lookup = append(decoder.lookup, uint32(i))
+		}
+	}
+
+	if cumProb != decoder.precision {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	return &decoder, nil
+}
+
+// Read the data coded by an rANS decoder and its initial state, stored in
+// its last bytes.
+func (d *dracoDecoder) startRansDecoder(decoder *dracoRansDecoder) error {
+	size := d.readVarint()
+
+	if d.err != nil || size < 1 || size > 1<<31 {
+		return ErrDracoInvalidFormat
+	}
+
+	data := make([]byte, size)
+
+	if _, err := io.ReadFull(d.reader, data); err != nil {
+		return ErrDracoInvalidFormat
+	}
+
+	offset, state, err := getDracoAnsState(data)
+	if err != nil {
+		return err
+	}
+
+	decoder.data = data
+	decoder.offset = offset
+	decoder.state = state + decoder.base
+
+	if decoder.state >= 256*decoder.base {
+		return ErrDracoInvalidFormat
+	}
+
+	return nil
+}
+
+// Get the initial state of an ANS decoder stored in the last one to four
+// bytes of its data (by the top two bits of the last byte), and the offset
+// of the data before it.
+func getDracoAnsState(data []byte) (int, uint32, error) {
+	offset := len(data)
+	last := data[offset-1]
+
+	switch last >> 6 {
+	case 0:
+		return offset - 1, uint32(last) & 0x3f, nil
+	case 1:
+		if offset < 2 {
+			return 0, 0, ErrDracoInvalidFormat
+		}
+
+		offset -= 2
+		return offset, (uint32(data[offset]) | uint32(data[offset+1])<<8) & 0x3fff, nil
+	case 2:
+		if offset < 3 {
+			return 0, 0, ErrDracoInvalidFormat
+		}
+
+		offset -= 3
+		return offset, (uint32(data[offset]) | uint32(data[offset+1])<<8 | uint32(data[offset+2])<<16) & 0x3fffff, nil
+	default:
+		if offset < 4 {
+			return 0, 0, ErrDracoInvalidFormat
+		}
+
+		offset -= 4
+		return offset, (uint32(data[offset]) | uint32(data[offset+1])<<8 | uint32(data[offset+2])<<16 | uint32(data[offset+3])<<24) & 0x3fffffff, nil
+	}
+}
+
+// Read the next symbol.
+func (r *dracoRansDecoder) read() uint32 {
+	for r.state < r.base && r.offset > 0 {
+		r.offset--
+		r.state = r.state<<8 | uint32(r.data[r.offset])
+	}
+
+	quotient := r.state / r.precision
+	remainder := r.state % r.precision
+	symbol := r.lookup[remainder]
+	r.state = quotient*r.probs[symbol] + remainder - r.cumProbs[symbol]
+
+	return symbol
+}
+
+// rABS decoder of bits with a fixed probability of zero (see ans.h and
+// rans_bit_decoder.cc of the reference implementation).
+type dracoRabsDecoder struct {
+	probZero uint32
+	data     []byte
+	offset   int
+	state    uint32
+}
+
+// Read the probability of zero and the data of an rABS bit decoder.
+func (d *dracoDecoder) readRabsDecoder() (*dracoRabsDecoder, error) {
+	var probZero uint8
+	d.read(&probZero)
+	size := d.readVarint()
+
+	if d.err != nil || size < 1 || size > 1<<31 {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	data := make([]byte, size)
+
+	if _, err := io.ReadFull(d.reader, data); err != nil {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	// The state is stored in at most three bytes.
+	if data[size-1]>>6 == 3 {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	offset, state, err := getDracoAnsState(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := dracoRabsDecoder{
+		probZero: uint32(probZero),
+		data:     data,
+		offset:   offset,
+		state:    state + dracoRabsBase,
+	}
+
+	if decoder.state >= 256*dracoRabsBase {
+		return nil, ErrDracoInvalidFormat
+	}
+
+	return &decoder, nil
+}
+
+// Read the next bit.
+func (r *dracoRabsDecoder) read() bool {
+	if r.state < dracoRabsBase && r.offset > 0 {
+		r.offset--
+		r.state = r.state<<8 | uint32(r.data[r.offset])
+	}
+
+	p := 256 - r.probZero
+	quotient := r.state / 256
+	remainder := r.state % 256
+
+	if remainder < p {
+		r.state = quotient*p + remainder
+		return true
+	}
+
+	r.state -= quotient*p + p
+	return false
+}
+
+// Reader of the bits of a Draco buffer from the least significant bit of
+// each byte. Only the bytes holding bits read are consumed.
+type dracoBitReader struct {
+	reader  *dracoDecoder
+	current uint8
+	bits    int
+}
+
+// Read a number of bits (at most 32) from the least significant bit.
+func (b *dracoBitReader) read(n int) uint32 {
+	var value uint32
+
+	for i := range n {
+		if b.bits == 0 {
+			b.reader.read(&b.current)
+			b.bits = 8
+		}
+
+		value |= uint32(b.current&1) << i
+		b.current >>= 1
+		b.bits--
+	}
+
+	return value
+}
@@ -0,0 +1,677 @@
+package exchange
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Build a Draco 2.2 sequential mesh of a single triangle by hand.
+func newDracoTriangle(method uint8) []byte {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("DRACO")
+	buffer.Write([]byte{2, 2, 1, method, 0, 0})
+
+	// Connectivity: one face, three points, raw uint8 indices.
+	buffer.Write([]byte{1, 3, 1, 0, 1, 2})
+
+	// One attribute decoder with a float32 position attribute (unique id 0)
+	// using the generic decoder.
+	buffer.Write([]byte{1, 1, 0, 9, 3, 0, 0, 0})
+
+	for _, value := range []float32{0, 0, 0, 1, 0, 0, 0, 1, 0.5} {
+		binary.Write(&buffer, binary.LittleEndian, value)
+	}
+
+	return buffer.Bytes()
+}
+
+// Test reading a hand built Draco mesh.
+func TestDracoRead(t *testing.T) {
+	reader := NewDracoReader(bytes.NewReader(newDracoTriangle(0)))
+	assert.Empty(t, reader.Read())
+
+	assert.Equal(t, 3, reader.GetNumberOfVertices())
+	assert.Equal(t, 1, reader.GetNumberOfFaces())
+	assert.Equal(t, []int{0, 1, 2}, reader.GetFace(0))
+	assert.Equal(t, meshx.NewVector(0, 1, 0.5), reader.GetVertex(2))
+	assert.Equal(t, -1, reader.GetFacePatch(0))
+	assert.Equal(t, 0, reader.GetNumberOfPatches())
+}
+
+// Test reading unsupported and invalid Draco meshes.
+func TestDracoReadInvalid(t *testing.T) {
+	reader := NewDracoReader(bytes.NewReader(newDracoTriangle(2)))
+	assert.ErrorIs(t, reader.Read(), ErrDracoUnsupported)
+
+	data := newDracoTriangle(0)
+	reader = NewDracoReader(bytes.NewReader(data[:len(data)-1]))
+	assert.ErrorIs(t, reader.Read(), ErrDracoInvalidFormat)
+
+	reader = NewDracoReader(bytes.NewReader([]byte("DRACX")))
+	assert.ErrorIs(t, reader.Read(), ErrDracoInvalidFormat)
+}
+
+// Test writing and reading Draco meshes with float and quantized positions.
+func TestDracoWriteRead(t *testing.T) {
+	source := newTestSphere(24, 12)
+	full, err := NewProgressiveMesh(source, 1<<30)
+	assert.Empty(t, err)
+
+	for _, bits := range []int{0, 11, 20} {
+		var buffer bytes.Buffer
+		assert.Empty(t, WriteDraco(&buffer, source, DracoOptions{PositionBits: bits}))
+
+		reader := NewDracoReader(&buffer)
+		assert.Empty(t, reader.Read())
+		assert.Equal(t, full.GetNumberOfFaces(), reader.GetNumberOfFaces())
+		assert.Equal(t, source.GetNumberOfVertices(), reader.GetNumberOfVertices())
+
+		for i := 0; i < full.GetNumberOfFaces(); i++ {
+			assert.Equal(t, full.GetFace(i), reader.GetFace(i))
+		}
+
+		tolerance := 1e-6
+
+		if bits > 0 {
+			tolerance = math.Sqrt(3) / float64(uint32(1)<<bits-1)
+		}
+
+		for i := 0; i < source.GetNumberOfVertices(); i++ {
+			assert.InDelta(t, 0, source.GetVertex(i).Sub(reader.GetVertex(i)).Mag(), tolerance)
+		}
+	}
+
+	var buffer bytes.Buffer
+	err = WriteDraco(&buffer, source, DracoOptions{PositionBits: 31})
+	assert.ErrorIs(t, err, ErrDracoInvalidBits)
+}
+
+// Encode symbols with rANS as the reference encoder (in reverse order with
+// the state in the last bytes) with the probabilities of their counts.
+func appendDracoRans(buffer *bytes.Buffer, symbols []uint32, precisionBits int) {
+	n := 0
+
+	for _, symbol := range symbols {
+		n = max(n, int(symbol)+1)
+	}
+
+	precision := uint32(1) << precisionBits
+	counts := make([]uint32, n)
+
+	for _, symbol := range symbols {
+		counts[symbol]++
+	}
+
+	// Quantize the probabilities to sum to the precision.
+	probs := make([]uint32, n)
+	var sum uint32
+	largest := 0
+
+	for i, count := range counts {
+		if count > 0 {
+			probs[i] = max(1, uint32(uint64(count)*uint64(precision)/uint64(len(symbols))))
+			sum += probs[i]
+
+			if probs[i] > probs[largest] {
+				largest = i
+			}
+		}
+	}
+
+	probs[largest] += precision - sum
+
+	buffer.Write(binary.AppendUvarint(nil, uint64(n)))
+
+	for i := 0; i < n; i++ {
+		prob := probs[i]
+
+		if prob == 0 {
+			offset := 0
+
+			for offset < 63 && i+offset+1 < n && probs[i+offset+1] == 0 {
+				offset++
+			}
+
+			buffer.WriteByte(byte(offset<<2 | 3))
+			i += offset
+		} else if prob < 1<<6 {
+			buffer.WriteByte(byte(prob << 2))
+		} else if prob < 1<<14 {
+			buffer.Write([]byte{byte(prob<<2 | 1), byte(prob >> 6)})
+		} else {
+			buffer.Write([]byte{byte(prob<<2 | 2), byte(prob >> 6), byte(prob >> 14)})
+		}
+	}
+
+	cumProbs := make([]uint32, n)
+
+	for i := 1; i < n; i++ {
+		cumProbs[i] = cumProbs[i-1] + probs[i-1]
+	}
+
+	base := 4 * precision
+	state := base
+	data := make([]byte, 0)
+
+	for i := len(symbols) - 1; i >= 0; i-- {
+		prob := probs[symbols[i]]
+
+		for state >= base/precision*256*prob {
+			data = append(data, byte(state))
+			state >>= 8
+		}
+
+		state = state/prob*precision + state%prob + cumProbs[symbols[i]]
+	}
+
+	state -= base
+
+	switch {
+	case state < 1<<6:
+		data = append(data, byte(state))
+	case state < 1<<14:
+		data = binary.LittleEndian.AppendUint16(data, uint16(1<<14+state))
+	case state < 1<<22:
+		data = append(data, byte(state), byte(state>>8), byte(2<<6+state>>16))
+	default:
+		data = binary.LittleEndian.AppendUint32(data, 3<<30+state)
+	}
+
+	buffer.Write(binary.AppendUvarint(nil, uint64(len(data))))
+	buffer.Write(data)
+}
+
+// Encode symbols with the raw or tagged scheme as the reference encoder.
+func appendDracoSymbols(buffer *bytes.Buffer, symbols []uint32, components int, tagged bool) {
+	if !tagged {
+		var maxSymbol uint32
+
+		for _, symbol := range symbols {
+			maxSymbol = max(maxSymbol, symbol)
+		}
+
+		bitLength := max(1, bits.Len32(maxSymbol))
+		buffer.Write([]byte{dracoSymbolsRaw, byte(bitLength)})
+		appendDracoRans(buffer, symbols, getDracoRansPrecision(bitLength))
+		return
+	}
+
+	tags := make([]uint32, 0)
+	var value, nBits uint64
+	data := make([]byte, 0)
+
+	for i := 0; i < len(symbols); i += components {
+		var maxSymbol uint32
+
+		for _, symbol := range symbols[i : i+components] {
+			maxSymbol = max(maxSymbol, symbol)
+		}
+
+		length := bits.Len32(maxSymbol)
+		tags = append(tags, uint32(length))
+
+		for _, symbol := range symbols[i : i+components] {
+			value |= uint64(symbol) << nBits
+
+			for nBits += uint64(length); nBits >= 8; nBits -= 8 {
+				data = append(data, byte(value))
+				value >>= 8
+			}
+		}
+	}
+
+	if nBits > 0 {
+		data = append(data, byte(value))
+	}
+
+	buffer.WriteByte(dracoSymbolsTagged)
+	appendDracoRans(buffer, tags, getDracoRansPrecision(5))
+	buffer.Write(data)
+}
+
+// Test decoding symbols coded with rANS.
+func TestDracoReadSymbols(t *testing.T) {
+	symbols := make([]uint32, 3000)
+
+	for i := range symbols {
+		symbols[i] = uint32(i*i%97) % uint32(1+i%40)
+	}
+
+	symbols[7] = 1 << 17
+
+	for _, tagged := range []bool{false, true} {
+		var buffer bytes.Buffer
+		appendDracoSymbols(&buffer, symbols, 3, tagged)
+		buffer.WriteString("end")
+
+		d := dracoDecoder{reader: bufio.NewReader(&buffer)}
+		values, err := d.readSymbols(len(symbols), 3)
+		assert.Empty(t, err)
+		assert.Equal(t, symbols, values)
+
+		// The decoder is positioned after the symbols.
+		rest, _ := io.ReadAll(d.reader)
+		assert.Equal(t, "end", string(rest))
+	}
+
+	d := dracoDecoder{reader: bufio.NewReader(bytes.NewReader([]byte{dracoSymbolsRaw, 4, 1, 0}))}
+	_, err := d.readSymbols(1, 1)
+	assert.ErrorIs(t, err, ErrDracoInvalidFormat)
+}
+
+// Build a Draco 2.2 sequential mesh with rANS compressed indices and
+// quantized positions predicted by a scheme with the wrap transform.
+func newDracoCompressedMesh(faces [][]int, quantized [][3]int32, prediction int8, tagged bool) []byte {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("DRACO")
+	buffer.Write([]byte{2, 2, 1, 0, 0, 0})
+	buffer.Write(binary.AppendUvarint(nil, uint64(len(faces))))
+	buffer.Write(binary.AppendUvarint(nil, uint64(len(quantized))))
+	buffer.WriteByte(dracoConnectivityRANS)
+
+	indices := make([]uint32, 0, 3*len(faces))
+	last := 0
+
+	for _, face := range faces {
+		for _, index := range face {
+			difference := index - last
+			last = index
+
+			if difference < 0 {
+				indices = append(indices, uint32(-difference)<<1|1)
+			} else {
+				indices = append(indices, uint32(difference)<<1)
+			}
+		}
+	}
+
+	appendDracoSymbols(&buffer, indices, 1, false)
+
+	// One attribute decoder with a quantized float32 position attribute.
+	buffer.Write([]byte{1, 1, 0, 9, 3, 0, 0, dracoDecoderQuantization})
+	buffer.Write([]byte{byte(prediction), dracoTransformWrap, 1})
+
+	values := make([]int32, 0, 3*len(quantized))
+	minValue, maxValue := int32(math.MaxInt32), int32(math.MinInt32)
+
+	for _, value := range quantized {
+		values = append(values, value[:]...)
+
+		for _, component := range value {
+			minValue, maxValue = min(minValue, component), max(maxValue, component)
+		}
+	}
+
+	// Compute the corrections of the predictions (as the decoder would).
+	transform := dracoTransform{wrap: true, minValue: minValue, maxValue: maxValue, maxDif: maxValue - minValue + 1}
+	predictions := make([]int32, len(values))
+
+	if prediction == dracoPredictionParallelogram {
+		table := newDracoCornerTable(faces, len(quantized))
+
+		for p := 1; p < len(quantized); p++ {
+			copy(predictions[3*p:], values[3*p-3:3*p])
+
+			if corner := table.getPointCorner(p); corner >= 0 {
+				if opposite := table.getOpposite(corner); opposite >= 0 {
+					o := table.getVertex(opposite)
+					n := table.getVertex(getDracoNextCorner(opposite))
+					q := table.getVertex(getDracoPreviousCorner(opposite))
+
+					if o < p && n < p && q < p {
+						for c := range 3 {
+							predictions[3*p+c] = values[3*n+c] + values[3*q+c] - values[3*o+c]
+						}
+					}
+				}
+			}
+		}
+	} else {
+		copy(predictions[3:], values)
+	}
+
+	symbols := make([]uint32, len(values))
+	maxCorrection := transform.maxDif / 2
+	minCorrection := -maxCorrection
+
+	if transform.maxDif%2 == 0 {
+		maxCorrection--
+	}
+
+	for i, value := range values {
+		predicted := min(max(predictions[i], minValue), maxValue)
+		correction := value - predicted
+
+		if correction < minCorrection {
+			correction += transform.maxDif
+		} else if correction > maxCorrection {
+			correction -= transform.maxDif
+		}
+
+		if correction < 0 {
+			symbols[i] = uint32(-correction)<<1 - 1
+		} else {
+			symbols[i] = uint32(correction) << 1
+		}
+	}
+
+	appendDracoSymbols(&buffer, symbols, 3, tagged)
+	binary.Write(&buffer, binary.LittleEndian, [2]int32{minValue, maxValue})
+
+	// Quantization of [0, 1] with 10 bits.
+	binary.Write(&buffer, binary.LittleEndian, [4]float32{0, 0, 0, 1})
+	buffer.WriteByte(10)
+
+	return buffer.Bytes()
+}
+
+// Test reading Draco meshes with compressed indices and predicted values.
+func TestDracoReadCompressed(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.Empty(t, WriteDraco(&buffer, newTestSphere(24, 12), DracoOptions{}))
+
+	sphere := NewDracoReader(&buffer)
+	assert.Empty(t, sphere.Read())
+
+	faces := make([][]int, sphere.GetNumberOfFaces())
+	quantized := make([][3]int32, sphere.GetNumberOfVertices())
+
+	for i := range faces {
+		faces[i] = sphere.GetFace(i)
+	}
+
+	for i := range quantized {
+		vertex := sphere.GetVertex(i)
+
+		for j := range 3 {
+			quantized[i][j] = int32(math.Round((vertex[j] + 1) / 2 * 1023))
+		}
+	}
+
+	for _, prediction := range []int8{dracoPredictionDifference, dracoPredictionParallelogram} {
+		for _, tagged := range []bool{false, true} {
+			data := newDracoCompressedMesh(faces, quantized, prediction, tagged)
+			reader := NewDracoReader(bytes.NewReader(data))
+			assert.Empty(t, reader.Read())
+			assert.Equal(t, faces, reader.faces)
+			assert.Equal(t, len(quantized), reader.GetNumberOfVertices())
+
+			for i, value := range quantized {
+				for j := range 3 {
+					expected := float64(float32(float64(value[j]) * float64(float32(1)) / 1023))
+					assert.InDelta(t, expected, reader.GetVertex(i)[j], 1e-12)
+				}
+			}
+
+			reader = NewDracoReader(bytes.NewReader(data[:len(data)-20]))
+			assert.ErrorIs(t, reader.Read(), ErrDracoInvalidFormat)
+		}
+	}
+}
+
+// Encode bits with rABS as the reference encoder (in reverse order with the
+// state in the last bytes) with the probability of zero of their counts.
+func appendDracoRabs(buffer *bytes.Buffer, values []bool) {
+	zeros := 0
+
+	for _, value := range values {
+		if !value {
+			zeros++
+		}
+	}
+
+	probZero := uint32(255)
+
+	if raw := uint32(float64(zeros)/float64(max(1, len(values)))*256 + 0.5); raw < 255 {
+		probZero = max(1, raw)
+	}
+
+	state := uint32(dracoRabsBase)
+	data := make([]byte, 0)
+
+	for i := len(values) - 1; i >= 0; i-- {
+		prob, offset := probZero, 256-probZero
+
+		if values[i] {
+			prob, offset = 256-probZero, 0
+		}
+
+		if state >= dracoRabsBase*prob {
+			data = append(data, byte(state))
+			state >>= 8
+		}
+
+		state = state/prob*256 + state%prob + offset
+	}
+
+	state -= dracoRabsBase
+
+	switch {
+	case state < 1<<6:
+		data = append(data, byte(state))
+	case state < 1<<14:
+		data = binary.LittleEndian.AppendUint16(data, uint16(1<<14+state))
+	default:
+		data = append(data, byte(state), byte(state>>8), byte(2<<6+state>>16))
+	}
+
+	buffer.WriteByte(byte(probZero))
+	buffer.Write(binary.AppendUvarint(nil, uint64(len(data))))
+	buffer.Write(data)
+}
+
+// Test decoding bits coded with rABS.
+func TestDracoReadRabs(t *testing.T) {
+	values := make([]bool, 5000)
+
+	for i := range values {
+		values[i] = i*i%7 == 3
+	}
+
+	var buffer bytes.Buffer
+	appendDracoRabs(&buffer, values)
+	buffer.WriteString("end")
+
+	d := dracoDecoder{reader: bufio.NewReader(&buffer)}
+	decoder, err := d.readRabsDecoder()
+	assert.Empty(t, err)
+
+	for i, value := range values {
+		assert.Equal(t, value, decoder.read(), i)
+	}
+
+	rest, _ := io.ReadAll(d.reader)
+	assert.Equal(t, "end", string(rest))
+}
+
+// Build a Draco 2.2 edgebreaker mesh by hand: the header with the metadata,
+// the connectivity, and one attribute decoder with the float32 positions of
+// the vertices in traversal order.
+func newDracoEdgebreakerMesh(metadata, connectivity []byte, positions [][3]float32) []byte {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("DRACO")
+	buffer.Write([]byte{2, 2, 1, dracoMethodEdgebreaker})
+
+	if metadata != nil {
+		binary.Write(&buffer, binary.LittleEndian, uint16(dracoMetadataFlag))
+		buffer.Write(metadata)
+	} else {
+		binary.Write(&buffer, binary.LittleEndian, uint16(0))
+	}
+
+	buffer.Write(connectivity)
+
+	// One per vertex decoder in depth-first order (position data id -1) with
+	// a float32 position attribute using the generic decoder.
+	buffer.Write([]byte{1, 0xff, dracoVertexAttribute, dracoSequenceDepthFirst})
+	buffer.Write([]byte{1, 0, 9, 3, 0, 0, 0})
+	binary.Write(&buffer, binary.LittleEndian, positions)
+
+	return buffer.Bytes()
+}
+
+// Build the edgebreaker connectivity of a closed tetrahedron decoded from
+// the symbols E, R, C and an interior start face with the standard or
+// valence traversal.
+func newDracoEdgebreakerTetrahedron(valence bool) []byte {
+	var buffer bytes.Buffer
+
+	if valence {
+		buffer.WriteByte(dracoTraversalValence)
+	} else {
+		buffer.WriteByte(dracoTraversalStandard)
+	}
+
+	// Vertices, faces, attribute data, symbols, split symbols, and no
+	// topology splits.
+	buffer.Write([]byte{4, 4, 0, 3, 0, 0})
+
+	if !valence {
+		// Symbol bits from the least significant: E (111), R (101), C (0).
+		buffer.Write([]byte{1, 0x2f})
+	}
+
+	appendDracoRabs(&buffer, []bool{true})
+
+	if valence {
+		// No split symbols, mode 2 to 7, and the symbols by the context of
+		// the valence of the active vertex: R (3) at valence 2 and C (0) at
+		// valence 3.
+		buffer.Write([]byte{0, 0})
+
+		for _, symbols := range [][]uint32{{3}, {0}, nil, nil, nil, nil} {
+			buffer.Write(binary.AppendUvarint(nil, uint64(len(symbols))))
+
+			if len(symbols) > 0 {
+				appendDracoSymbols(&buffer, symbols, 1, false)
+			}
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+// Test reading hand built Draco meshes with the edgebreaker method.
+func TestDracoReadEdgebreaker(t *testing.T) {
+	tetrahedron := [][]int{{0, 1, 2}, {2, 1, 3}, {1, 0, 3}, {2, 3, 0}}
+	vertices := []meshx.Vector{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+
+	for _, valence := range []bool{false, true} {
+		// The depth-first traversal reaches the vertices 1, 2, 0, and 3.
+		positions := [][3]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 0}, {0, 0, 1}}
+		data := newDracoEdgebreakerMesh(nil, newDracoEdgebreakerTetrahedron(valence), positions)
+
+		reader := NewDracoReader(bytes.NewReader(data))
+		assert.Empty(t, reader.Read())
+		assert.Equal(t, tetrahedron, reader.faces)
+		assert.Equal(t, vertices, reader.vertices)
+		assert.Empty(t, reader.GetMetadata())
+
+		reader = NewDracoReader(bytes.NewReader(data[:len(data)-1]))
+		assert.ErrorIs(t, reader.Read(), ErrDracoInvalidFormat)
+	}
+
+	// A fan of three faces decoded from the symbols E, E, S (merging a vertex
+	// of each E face) and a start face on the boundary. The merged vertex is
+	// removed, or kept out of the faces with attribute seams.
+	for _, nSeams := range []byte{0, 1} {
+		var connectivity bytes.Buffer
+		connectivity.Write([]byte{dracoTraversalStandard, 5, 3, nSeams, 3, 1, 0})
+
+		// Symbol bits from the least significant: E (111), E (111), S (100).
+		connectivity.Write([]byte{2, 0x7f, 0})
+		appendDracoRabs(&connectivity, []bool{false})
+
+		if nSeams > 0 {
+			appendDracoRabs(&connectivity, []bool{false, true})
+		}
+
+		positions := [][3]float32{{1, 0, 0}, {0, 0, 0}, {1, 1, 0}, {-1, 1, 0}, {-1, 0, 0}}
+		reader := NewDracoReader(bytes.NewReader(newDracoEdgebreakerMesh(nil, connectivity.Bytes(), positions)))
+		assert.Empty(t, reader.Read())
+		assert.Equal(t, [][]int{{0, 1, 2}, {3, 2, 4}, {2, 1, 4}}, reader.faces)
+		assert.Equal(t, []meshx.Vector{{1, 1, 0}, {1, 0, 0}, {0, 0, 0}, {-1, 0, 0}, {-1, 1, 0}}, reader.vertices)
+	}
+
+	// The metadata of the attributes and nested elements are skipped.
+	var metadata bytes.Buffer
+	metadata.Write([]byte{1, 0, 1, 4})
+	metadata.WriteString("name")
+	metadata.WriteByte(8)
+	metadata.WriteString("position")
+	metadata.Write([]byte{0, 2, 4})
+	metadata.WriteString("name")
+	metadata.WriteByte(5)
+	metadata.WriteString("tetra")
+	metadata.Write([]byte{5})
+	metadata.WriteString("scale")
+	metadata.Write([]byte{4, 0, 0, 0x80, 0x3f, 1, 3})
+	metadata.WriteString("sub")
+	metadata.Write([]byte{0, 0})
+
+	positions := [][3]float32{{1, 0, 0}, {0, 1, 0}, {0, 0, 0}, {0, 0, 1}}
+	data := newDracoEdgebreakerMesh(metadata.Bytes(), newDracoEdgebreakerTetrahedron(false), positions)
+	reader := NewDracoReader(bytes.NewReader(data))
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, map[string]string{"name": "tetra", "scale": "\x00\x00\x80\x3f"}, reader.GetMetadata())
+	assert.Equal(t, tetrahedron, reader.faces)
+
+	// The symbols must build the declared number of faces.
+	connectivity := newDracoEdgebreakerTetrahedron(false)
+	connectivity[2] = 3
+	reader = NewDracoReader(bytes.NewReader(newDracoEdgebreakerMesh(nil, connectivity, positions)))
+	assert.ErrorIs(t, reader.Read(), ErrDracoInvalidFormat)
+
+	// Per corner attributes are not supported.
+	data = newDracoEdgebreakerMesh(nil, newDracoEdgebreakerTetrahedron(false), positions)
+	data[bytes.Index(data, []byte{1, 0xff})+2] = 1
+	reader = NewDracoReader(bytes.NewReader(data))
+	assert.ErrorIs(t, reader.Read(), ErrDracoUnsupported)
+}
+
+// Test reading an edgebreaker mesh with quantized positions predicted by the
+// parallelogram of the traversal.
+func TestDracoReadEdgebreakerParallelogram(t *testing.T) {
+	var buffer bytes.Buffer
+
+	buffer.WriteString("DRACO")
+	buffer.Write([]byte{2, 2, 1, dracoMethodEdgebreaker, 0, 0})
+	buffer.Write(newDracoEdgebreakerTetrahedron(true))
+	buffer.Write([]byte{1, 0xff, dracoVertexAttribute, dracoSequenceDepthFirst})
+	buffer.Write([]byte{1, 0, 9, 3, 0, 0, dracoDecoderQuantization})
+	buffer.Write([]byte{dracoPredictionParallelogram, dracoTransformWrap, 1})
+
+	// The vertices 1, 2, and 0 are predicted by the previous value and the
+	// vertex 3 by the parallelogram across the edge of vertices 2 and 0
+	// (clamped to the range of the values).
+	corrections := []int32{1023, 0, 0, -1023, 1023, 0, 0, -1023, 0, 0, -1023, 1023}
+	symbols := make([]uint32, len(corrections))
+
+	for i, correction := range corrections {
+		if correction < 0 {
+			symbols[i] = uint32(-correction)<<1 - 1
+		} else {
+			symbols[i] = uint32(correction) << 1
+		}
+	}
+
+	appendDracoSymbols(&buffer, symbols, 3, true)
+	binary.Write(&buffer, binary.LittleEndian, [2]int32{0, 1023})
+	binary.Write(&buffer, binary.LittleEndian, [4]float32{0, 0, 0, 1})
+	buffer.WriteByte(10)
+
+	reader := NewDracoReader(&buffer)
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, []meshx.Vector{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, reader.vertices)
+}
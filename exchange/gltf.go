@@ -0,0 +1,356 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/planar"
+)
+
+// Subset of glTF 2.0 (https://registry.khronos.org/glTF/) supported for
+// writing: scenes of triangular meshes with positions only, placed by nodes
+// with a transformation matrix. A mesh referenced by more than one node is
+// written once, so repeated parts are instanced rather than duplicated.
+
+const (
+	gltfVersion        = "2.0"
+	gltfMagic          = 0x46546c67
+	gltfChunkJSON      = 0x4e4f534a
+	gltfChunkBinary    = 0x004e4942
+	gltfFloat          = 5126
+	gltfUnsignedInt    = 5125
+	gltfArrayBuffer    = 34962
+	gltfElementBuffer  = 34963
+	gltfModeTriangles  = 4
+	gltfDataURIPrefix  = "data:application/octet-stream;base64,"
+	gltfGeneratorName  = "meshx-go"
+	gltfBinaryVersion  = 2
+	gltfBinaryHeader   = 12
+	gltfChunkHeader    = 8
+	gltfPositionStride = 12
+)
+
+var (
+	ErrGLTFInvalidMesh = errors.New("invalid glTF mesh")
+	ErrGLTFInvalidNode = errors.New("invalid glTF node")
+)
+
+// Options for writing a glTF scene. Zero values use the defaults.
+type GLTFOptions struct {
+	// Write the binary container (.glb) rather than JSON with the buffer
+	// embedded as a data URI (.gltf).
+	Binary bool
+
+	// Conversion of the coordinates written (e.g. from a Z-up system to the
+	// Y-up system of glTF). The conversion is the transformation of a root
+	// node of the other nodes.
+	Conversion meshx.Conversion
+}
+
+// GLTFWriter manages writing a glTF scene of meshes placed by nodes. Faces
+// are triangulated and patches are not written.
+type GLTFWriter struct {
+	writer  io.Writer
+	options GLTFOptions
+	meshes  []gltfMeshData
+	nodes   []gltfNodeData
+}
+
+// Mesh to write.
+type gltfMeshData struct {
+	name     string
+	vertices []meshx.Vector
+	faces    [][]int
+}
+
+// Node to write.
+type gltfNodeData struct {
+	name      string
+	mesh      int
+	transform meshx.Matrix4
+}
+
+// JSON document of a glTF scene.
+type gltfDocument struct {
+	Asset       gltfAsset        `json:"asset"`
+	Scene       int              `json:"scene"`
+	Scenes      []gltfScene      `json:"scenes"`
+	Nodes       []gltfNode       `json:"nodes"`
+	Meshes      []gltfMesh       `json:"meshes"`
+	Accessors   []gltfAccessor   `json:"accessors"`
+	BufferViews []gltfBufferView `json:"bufferViews"`
+	Buffers     []gltfBuffer     `json:"buffers"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator,omitempty"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name     string    `json:"name,omitempty"`
+	Mesh     *int      `json:"mesh,omitempty"`
+	Children []int     `json:"children,omitempty"`
+	Matrix   []float64 `json:"matrix,omitempty"`
+}
+
+type gltfMesh struct {
+	Name       string          `json:"name,omitempty"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Mode       int            `json:"mode"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride,omitempty"`
+	Target     int `json:"target"`
+}
+
+type gltfBuffer struct {
+	ByteLength int    `json:"byteLength"`
+	URI        string `json:"uri,omitempty"`
+}
+
+// Construct a GLTFWriter from an io.Writer interface.
+func NewGLTFWriter(writer io.Writer, options GLTFOptions) *GLTFWriter {
+	return &GLTFWriter{
+		writer:  writer,
+		options: options,
+		meshes:  make([]gltfMeshData, 0),
+		nodes:   make([]gltfNodeData, 0),
+	}
+}
+
+// Add a mesh read from a MeshReader and get its index.
+func (w *GLTFWriter) AddMesh(name string, source meshx.MeshReader) int {
+	mesh := gltfMeshData{
+		name:     name,
+		vertices: make([]meshx.Vector, source.GetNumberOfVertices()),
+		faces:    make([][]int, source.GetNumberOfFaces()),
+	}
+
+	for i := range mesh.vertices {
+		mesh.vertices[i] = source.GetVertex(i)
+	}
+
+	for i := range mesh.faces {
+		mesh.faces[i] = source.GetFace(i)
+	}
+
+	w.meshes = append(w.meshes, mesh)
+
+	return len(w.meshes) - 1
+}
+
+// Add a node placing a mesh (by index) by a transformation.
+func (w *GLTFWriter) AddNode(name string, mesh int, transform meshx.Matrix4) {
+	w.nodes = append(w.nodes, gltfNodeData{name, mesh, transform})
+}
+
+// Write the data to the io.Writer interface. A mesh must have at least one
+// face.
+func (w *GLTFWriter) Write() error {
+	var buffer bytes.Buffer
+
+	document := gltfDocument{
+		Asset:       gltfAsset{Version: gltfVersion, Generator: gltfGeneratorName},
+		Scenes:      []gltfScene{{Nodes: make([]int, 0)}},
+		Nodes:       make([]gltfNode, 0, len(w.nodes)+1),
+		Meshes:      make([]gltfMesh, 0, len(w.meshes)),
+		Accessors:   make([]gltfAccessor, 0, 2*len(w.meshes)),
+		BufferViews: make([]gltfBufferView, 0, 2*len(w.meshes)),
+	}
+
+	for _, mesh := range w.meshes {
+		if err := w.writeMesh(&document, &buffer, mesh); err != nil {
+			return err
+		}
+	}
+
+	for i, node := range w.nodes {
+		if node.mesh < 0 || node.mesh >= len(w.meshes) {
+			return ErrGLTFInvalidNode
+		}
+
+		document.Nodes = append(document.Nodes, gltfNode{
+			Name:   node.name,
+			Mesh:   &w.nodes[i].mesh,
+			Matrix: getGLTFMatrix(node.transform),
+		})
+
+		document.Scenes[0].Nodes = append(document.Scenes[0].Nodes, i)
+	}
+
+	if !w.options.Conversion.IsIdentity() {
+		document.Nodes = append(document.Nodes, gltfNode{
+			Children: document.Scenes[0].Nodes,
+			Matrix:   getGLTFMatrix(w.options.Conversion.GetMatrix4()),
+		})
+
+		document.Scenes[0].Nodes = []int{len(document.Nodes) - 1}
+	}
+
+	document.Buffers = []gltfBuffer{{ByteLength: buffer.Len()}}
+
+	if !w.options.Binary {
+		document.Buffers[0].URI = gltfDataURIPrefix + base64.StdEncoding.EncodeToString(buffer.Bytes())
+
+		encoder := json.NewEncoder(w.writer)
+		return encoder.Encode(document)
+	}
+
+	return w.writeBinary(document, buffer.Bytes())
+}
+
+// Write the positions and triangles of a mesh to the buffer.
+func (w *GLTFWriter) writeMesh(document *gltfDocument, buffer *bytes.Buffer, mesh gltfMeshData) error {
+	if len(mesh.faces) == 0 {
+		return ErrGLTFInvalidMesh
+	}
+
+	triangles := make([][3]int, 0, len(mesh.faces))
+
+	for _, face := range mesh.faces {
+		for _, vertex := range face {
+			if vertex < 0 || vertex >= len(mesh.vertices) {
+				return ErrGLTFInvalidMesh
+			}
+		}
+
+		triangles = append(triangles, planar.TriangulateIndexedFace(mesh.vertices, face)...)
+	}
+
+	minBound := []float32{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32}
+	maxBound := []float32{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32}
+	offset := buffer.Len()
+
+	for _, vertex := range mesh.vertices {
+		for i := 0; i < 3; i++ {
+			value := float32(vertex[i])
+			minBound[i] = min(minBound[i], value)
+			maxBound[i] = max(maxBound[i], value)
+			binary.Write(buffer, binary.LittleEndian, value)
+		}
+	}
+
+	document.BufferViews = append(document.BufferViews, gltfBufferView{
+		ByteOffset: offset,
+		ByteLength: buffer.Len() - offset,
+		ByteStride: gltfPositionStride,
+		Target:     gltfArrayBuffer,
+	})
+
+	document.Accessors = append(document.Accessors, gltfAccessor{
+		BufferView:    len(document.BufferViews) - 1,
+		ComponentType: gltfFloat,
+		Count:         len(mesh.vertices),
+		Type:          "VEC3",
+		Min:           minBound,
+		Max:           maxBound,
+	})
+
+	offset = buffer.Len()
+
+	for _, triangle := range triangles {
+		for _, vertex := range triangle {
+			binary.Write(buffer, binary.LittleEndian, uint32(vertex))
+		}
+	}
+
+	document.BufferViews = append(document.BufferViews, gltfBufferView{
+		ByteOffset: offset,
+		ByteLength: buffer.Len() - offset,
+		Target:     gltfElementBuffer,
+	})
+
+	document.Accessors = append(document.Accessors, gltfAccessor{
+		BufferView:    len(document.BufferViews) - 1,
+		ComponentType: gltfUnsignedInt,
+		Count:         3 * len(triangles),
+		Type:          "SCALAR",
+	})
+
+	document.Meshes = append(document.Meshes, gltfMesh{
+		Name: mesh.name,
+		Primitives: []gltfPrimitive{{
+			Attributes: map[string]int{"POSITION": len(document.Accessors) - 2},
+			Indices:    len(document.Accessors) - 1,
+			Mode:       gltfModeTriangles,
+		}},
+	})
+
+	return nil
+}
+
+// Write the document and buffer in the binary container. The chunks are
+// padded to four bytes (the JSON with spaces).
+func (w *GLTFWriter) writeBinary(document gltfDocument, data []byte) error {
+	content, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	content = append(content, bytes.Repeat([]byte(" "), (4-len(content)%4)%4)...)
+	data = append(data, make([]byte, (4-len(data)%4)%4)...)
+	length := gltfBinaryHeader + 2*gltfChunkHeader + len(content) + len(data)
+
+	var buffer bytes.Buffer
+	binary.Write(&buffer, binary.LittleEndian, []uint32{gltfMagic, gltfBinaryVersion, uint32(length)})
+	binary.Write(&buffer, binary.LittleEndian, []uint32{uint32(len(content)), gltfChunkJSON})
+	buffer.Write(content)
+	binary.Write(&buffer, binary.LittleEndian, []uint32{uint32(len(data)), gltfChunkBinary})
+	buffer.Write(data)
+
+	_, err = w.writer.Write(buffer.Bytes())
+	return err
+}
+
+// Get the column-major matrix of a node. The identity is omitted (nil).
+func getGLTFMatrix(transform meshx.Matrix4) []float64 {
+	if transform == meshx.NewIdentityMatrix4() {
+		return nil
+	}
+
+	matrix := make([]float64, 16)
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			matrix[4*j+i] = transform[i][j]
+		}
+	}
+
+	return matrix
+}
+
+// Write a mesh read from a MeshReader as a glTF scene of a single node.
+func WriteGLTF(writer io.Writer, source meshx.MeshReader, options GLTFOptions) error {
+	w := NewGLTFWriter(writer, options)
+	w.AddNode("", w.AddMesh("", source), meshx.NewIdentityMatrix4())
+	return w.Write()
+}
@@ -0,0 +1,91 @@
+package exchange
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test writing a glTF scene with a mesh instanced by two nodes.
+func TestGLTFWriteInstances(t *testing.T) {
+	source, err := meshx.ReadOBJFromPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	var buffer bytes.Buffer
+	writer := NewGLTFWriter(&buffer, GLTFOptions{})
+	mesh := writer.AddMesh("box", source)
+	writer.AddNode("a", mesh, meshx.NewIdentityMatrix4())
+	writer.AddNode("b", mesh, meshx.NewTranslationMatrix4(meshx.NewVector(2, 3, 4)))
+	assert.Empty(t, writer.Write())
+
+	var document gltfDocument
+	assert.Empty(t, json.Unmarshal(buffer.Bytes(), &document))
+	assert.Equal(t, "2.0", document.Asset.Version)
+	assert.Equal(t, 1, len(document.Meshes))
+	assert.Equal(t, []int{0, 1}, document.Scenes[0].Nodes)
+	assert.Equal(t, 0, *document.Nodes[1].Mesh)
+	assert.Empty(t, document.Nodes[0].Matrix)
+	assert.Equal(t, []float64{2, 3, 4, 1}, document.Nodes[1].Matrix[12:])
+
+	positions := document.Accessors[document.Meshes[0].Primitives[0].Attributes["POSITION"]]
+	indices := document.Accessors[document.Meshes[0].Primitives[0].Indices]
+	assert.Equal(t, source.GetNumberOfVertices(), positions.Count)
+	assert.Equal(t, []float32{-0.5, -0.5, -0.5}, positions.Min)
+	assert.Equal(t, 3*source.GetNumberOfFaces(), indices.Count)
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(document.Buffers[0].URI, gltfDataURIPrefix))
+	assert.Empty(t, err)
+	assert.Equal(t, document.Buffers[0].ByteLength, len(data))
+	assert.Equal(t, 12*positions.Count+4*indices.Count, len(data))
+}
+
+// Test writing a glTF scene in the binary container with a conversion.
+func TestGLTFWriteBinary(t *testing.T) {
+	source, err := meshx.ReadOBJFromPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	var buffer bytes.Buffer
+	options := GLTFOptions{
+		Binary:     true,
+		Conversion: meshx.NewConversion(meshx.CoordinateSystem{}, meshx.CoordinateSystem{UpAxis: meshx.UpAxisY}),
+	}
+
+	assert.Empty(t, WriteGLTF(&buffer, source, options))
+
+	data := buffer.Bytes()
+	assert.Equal(t, uint32(0x46546c67), binary.LittleEndian.Uint32(data[0:]))
+	assert.Equal(t, uint32(len(data)), binary.LittleEndian.Uint32(data[8:]))
+
+	length := binary.LittleEndian.Uint32(data[12:])
+	assert.Equal(t, uint32(0), length%4)
+
+	var document gltfDocument
+	assert.Empty(t, json.Unmarshal(data[20:20+length], &document))
+	assert.Equal(t, 2, len(document.Nodes))
+	assert.Equal(t, []int{1}, document.Scenes[0].Nodes)
+	assert.Equal(t, []int{0}, document.Nodes[1].Children)
+	assert.Empty(t, document.Buffers[0].URI)
+
+	binaryLength := binary.LittleEndian.Uint32(data[20+length:])
+	assert.Equal(t, len(data), int(28+length+binaryLength))
+}
+
+// Test writing invalid glTF meshes and nodes.
+func TestGLTFWriteInvalid(t *testing.T) {
+	source, err := meshx.ReadOBJFromPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	writer := NewGLTFWriter(&bytes.Buffer{}, GLTFOptions{})
+	writer.AddNode("a", writer.AddMesh("box", source)+1, meshx.NewIdentityMatrix4())
+	assert.ErrorIs(t, writer.Write(), ErrGLTFInvalidNode)
+
+	writer = NewGLTFWriter(&bytes.Buffer{}, GLTFOptions{})
+	writer.AddMesh("empty", meshx.NewOBJReader(strings.NewReader("")))
+	assert.ErrorIs(t, writer.Write(), ErrGLTFInvalidMesh)
+}
@@ -0,0 +1,724 @@
+package exchange
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/planar"
+)
+
+var (
+	ErrProgressiveInvalidTarget = errors.New("invalid target number of faces")
+	ErrProgressiveInvalidSplit  = errors.New("invalid vertex split")
+)
+
+// Triangle face of a progressive mesh.
+type ProgressiveFace struct {
+	Vertices [3]int
+	Patch    int
+}
+
+// Refinement record of a progressive mesh. Applying the split appends a new
+// vertex, replaces the parent vertex with the new vertex in the listed faces,
+// and appends the new faces.
+type VertexSplit struct {
+	// Index of the vertex being split.
+	Parent int
+
+	// Position of the new vertex.
+	Point meshx.Vector
+
+	// Faces in which the parent vertex is replaced by the new vertex.
+	Faces []int
+
+	// Faces appended by the split.
+	NewFaces []ProgressiveFace
+}
+
+// Progressive mesh of triangles as a base mesh and a sequence of vertex
+// splits recorded by decimation. The mesh can be refined and coarsened
+// between the base mesh and the full resolution mesh.
+type ProgressiveMesh struct {
+	vertices []meshx.Vector
+	faces    []ProgressiveFace
+	patches  []string
+	splits   []VertexSplit
+	level    int
+}
+
+// Construct a progressive mesh by decimating the mesh to (at most) the
+// target number of faces. Faces with more than three vertices are
+// triangulated. Vertices on open boundaries or non-manifold edges are not
+// removed. The full resolution mesh is active.
+func NewProgressiveMesh(source meshx.MeshReader, targetFaces int) (*ProgressiveMesh, error) {
+	return NewProgressiveMeshContext(context.Background(), source, targetFaces, nil)
+}
+
+// Construct a progressive mesh by decimation with cancellation and progress
+// reported as the number of faces removed out of the number to remove.
+func NewProgressiveMeshContext(ctx context.Context, source meshx.MeshReader, targetFaces int, progress meshx.ProgressFunc) (*ProgressiveMesh, error) {
+	if targetFaces < 0 {
+		return nil, ErrProgressiveInvalidTarget
+	}
+
+	decimator := newDecimator(source)
+
+	if err := decimator.decimate(ctx, targetFaces, progress); err != nil {
+		return nil, err
+	}
+
+	return decimator.build(), nil
+}
+
+// Construct a progressive mesh from its base mesh without any splits.
+func NewProgressiveMeshFromBase(vertices []meshx.Vector, faces []ProgressiveFace, patches []string) *ProgressiveMesh {
+	return &ProgressiveMesh{
+		vertices: append([]meshx.Vector(nil), vertices...),
+		faces:    append([]ProgressiveFace(nil), faces...),
+		patches:  append([]string(nil), patches...),
+		splits:   make([]VertexSplit, 0),
+	}
+}
+
+// Append a vertex split. The split is applied if the mesh is at full
+// resolution.
+func (p *ProgressiveMesh) AddSplit(split VertexSplit) error {
+	isRefined := p.level == len(p.splits)
+	p.splits = append(p.splits, split)
+
+	if isRefined {
+		if err := p.applySplit(split); err != nil {
+			p.splits = p.splits[:len(p.splits)-1]
+			return err
+		}
+
+		p.level++
+	}
+
+	return nil
+}
+
+// Get the number of vertex splits.
+func (p *ProgressiveMesh) GetNumberOfSplits() int {
+	return len(p.splits)
+}
+
+// Get a vertex split by index.
+func (p *ProgressiveMesh) GetSplit(index int) VertexSplit {
+	return p.splits[index]
+}
+
+// Get the number of applied vertex splits.
+func (p *ProgressiveMesh) GetLevel() int {
+	return p.level
+}
+
+// Apply up to n vertex splits. The number of splits applied is returned.
+func (p *ProgressiveMesh) Refine(n int) int {
+	var count int
+
+	for count < n && p.level < len(p.splits) {
+		if p.applySplit(p.splits[p.level]) != nil {
+			break
+		}
+
+		p.level++
+		count++
+	}
+
+	return count
+}
+
+// Undo up to n vertex splits. The number of splits undone is returned.
+func (p *ProgressiveMesh) Coarsen(n int) int {
+	var count int
+
+	for count < n && p.level > 0 {
+		p.level--
+		p.undoSplit(p.splits[p.level])
+		count++
+	}
+
+	return count
+}
+
+// Refine or coarsen the mesh to a level.
+func (p *ProgressiveMesh) SetLevel(level int) {
+	if level > p.level {
+		p.Refine(level - p.level)
+	} else {
+		p.Coarsen(p.level - level)
+	}
+}
+
+// Apply a vertex split to the active mesh.
+func (p *ProgressiveMesh) applySplit(split VertexSplit) error {
+	vertex := len(p.vertices)
+
+	if split.Parent < 0 || split.Parent >= vertex {
+		return ErrProgressiveInvalidSplit
+	}
+
+	for _, face := range split.Faces {
+		if face < 0 || face >= len(p.faces) || indexOf(p.faces[face].Vertices, split.Parent) < 0 {
+			return ErrProgressiveInvalidSplit
+		}
+	}
+
+	for _, face := range split.NewFaces {
+		for _, v := range face.Vertices {
+			if v < 0 || v > vertex {
+				return ErrProgressiveInvalidSplit
+			}
+		}
+	}
+
+	p.vertices = append(p.vertices, split.Point)
+
+	for _, face := range split.Faces {
+		p.faces[face].Vertices[indexOf(p.faces[face].Vertices, split.Parent)] = vertex
+	}
+
+	p.faces = append(p.faces, split.NewFaces...)
+
+	return nil
+}
+
+// Undo a vertex split applied to the active mesh.
+func (p *ProgressiveMesh) undoSplit(split VertexSplit) {
+	vertex := len(p.vertices) - 1
+	p.faces = p.faces[:len(p.faces)-len(split.NewFaces)]
+
+	for _, face := range split.Faces {
+		p.faces[face].Vertices[indexOf(p.faces[face].Vertices, vertex)] = split.Parent
+	}
+
+	p.vertices = p.vertices[:vertex]
+}
+
+// Get the index of a vertex in a face or -1 if not found.
+func indexOf(vertices [3]int, vertex int) int {
+	for i, v := range vertices {
+		if v == vertex {
+			return i
+		}
+	}
+	return -1
+}
+
+// Implement the MeshReader interface.
+func (p *ProgressiveMesh) Read() error {
+	return nil
+}
+
+// Implement the MeshReader interface.
+func (p *ProgressiveMesh) GetNumberOfVertices() int {
+	return len(p.vertices)
+}
+
+// Implement the MeshReader interface.
+func (p *ProgressiveMesh) GetNumberOfFaces() int {
+	return len(p.faces)
+}
+
+// Implement the MeshReader interface.
+func (p *ProgressiveMesh) GetNumberOfFaceEdges() int {
+	return 3 * len(p.faces)
+}
+
+// Implement the MeshReader interface.
+func (p *ProgressiveMesh) GetNumberOfPatches() int {
+	return len(p.patches)
+}
+
+// Implement the MeshReader interface.
+func (p *ProgressiveMesh) GetVertex(index int) meshx.Vector {
+	return p.vertices[index]
+}
+
+// Implement the MeshReader interface.
+func (p *ProgressiveMesh) GetFace(index int) []int {
+	face := p.faces[index].Vertices
+	return []int{face[0], face[1], face[2]}
+}
+
+// Implement the MeshReader interface.
+func (p *ProgressiveMesh) GetFacePatch(index int) int {
+	return p.faces[index].Patch
+}
+
+// Implement the MeshReader interface.
+func (p *ProgressiveMesh) GetPatch(index int) string {
+	return p.patches[index]
+}
+
+// Decimation by quadric error half-edge collapses recording the collapses
+// as vertex splits.
+type decimator struct {
+	points      []meshx.Vector
+	faces       []ProgressiveFace
+	patches     []string
+	faceAlive   []bool
+	vertexFaces []map[int]bool
+	isLocked    []bool
+	quadrics    [][4][4]float64
+	stamps      []int
+	queue       collapseQueue
+	collapses   []collapse
+	nFaces      int
+}
+
+// Half-edge collapse of a vertex into its parent.
+type collapse struct {
+	vertex  int
+	parent  int
+	updated []int
+	removed []int
+}
+
+// Construct a decimator of the triangulated faces of a mesh.
+func newDecimator(source meshx.MeshReader) *decimator {
+	d := decimator{
+		points:      make([]meshx.Vector, source.GetNumberOfVertices()),
+		patches:     make([]string, source.GetNumberOfPatches()),
+		vertexFaces: make([]map[int]bool, source.GetNumberOfVertices()),
+		isLocked:    make([]bool, source.GetNumberOfVertices()),
+		quadrics:    make([][4][4]float64, source.GetNumberOfVertices()),
+		stamps:      make([]int, source.GetNumberOfVertices()),
+	}
+
+	for i := range d.points {
+		d.points[i] = source.GetVertex(i)
+		d.vertexFaces[i] = make(map[int]bool)
+	}
+
+	for i := range d.patches {
+		d.patches[i] = source.GetPatch(i)
+	}
+
+	for i := 0; i < source.GetNumberOfFaces(); i++ {
+		face := source.GetFace(i)
+		patch := source.GetFacePatch(i)
+
+		for _, triangle := range planar.TriangulateIndexedFace(d.points, face) {
+			index := len(d.faces)
+			d.faces = append(d.faces, ProgressiveFace{triangle, patch})
+			d.faceAlive = append(d.faceAlive, true)
+
+			for _, vertex := range d.faces[index].Vertices {
+				d.vertexFaces[vertex][index] = true
+			}
+		}
+	}
+
+	d.nFaces = len(d.faces)
+
+	// Lock the vertices of boundary and non-manifold edges.
+	edges := make(map[[2]int]int)
+
+	for _, face := range d.faces {
+		for j := 0; j < 3; j++ {
+			p, q := face.Vertices[j], face.Vertices[(j+1)%3]
+			edges[[2]int{min(p, q), max(p, q)}]++
+		}
+	}
+
+	for edge, count := range edges {
+		if count != 2 {
+			d.isLocked[edge[0]] = true
+			d.isLocked[edge[1]] = true
+		}
+	}
+
+	for i, face := range d.faces {
+		plane := d.getFacePlane(i)
+
+		for _, vertex := range face.Vertices {
+			for r := 0; r < 4; r++ {
+				for c := 0; c < 4; c++ {
+					d.quadrics[vertex][r][c] += plane[r] * plane[c]
+				}
+			}
+		}
+	}
+
+	for vertex := range d.points {
+		d.pushCandidates(vertex)
+	}
+
+	return &d
+}
+
+// Compute the area-weighted plane coefficients of a face.
+func (d *decimator) getFacePlane(index int) [4]float64 {
+	face := d.faces[index].Vertices
+	triangle := meshx.NewTriangle(d.points[face[0]], d.points[face[1]], d.points[face[2]])
+	normal := triangle.Normal()
+	mag := normal.Mag()
+
+	if mag == 0 {
+		return [4]float64{}
+	}
+
+	// Scale the plane by the square root of the area so the quadric is
+	// area-weighted.
+	n := normal.MulScalar(1 / mag)
+	scale := math.Sqrt(mag / 2)
+
+	return [4]float64{n[0] * scale, n[1] * scale, n[2] * scale, -n.Dot(triangle.P) * scale}
+}
+
+// Get the neighboring vertices of a vertex.
+func (d *decimator) getNeighbors(vertex int) map[int]bool {
+	neighbors := make(map[int]bool)
+
+	for face := range d.vertexFaces[vertex] {
+		for _, v := range d.faces[face].Vertices {
+			if v != vertex {
+				neighbors[v] = true
+			}
+		}
+	}
+
+	return neighbors
+}
+
+// Compute the cost of collapsing a vertex into its parent.
+func (d *decimator) getCost(vertex, parent int) float64 {
+	var cost float64
+
+	p := d.points[parent]
+	x := [4]float64{p[0], p[1], p[2], 1}
+
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			cost += x[r] * (d.quadrics[vertex][r][c] + d.quadrics[parent][r][c]) * x[c]
+		}
+	}
+
+	return cost
+}
+
+// Get the neighboring vertices of a vertex in ascending order (so the
+// decimation does not depend on the order of iterating a map).
+func (d *decimator) getSortedNeighbors(vertex int) []int {
+	neighbors := d.getNeighbors(vertex)
+	sorted := make([]int, 0, len(neighbors))
+
+	for v := range neighbors {
+		sorted = append(sorted, v)
+	}
+
+	sort.Ints(sorted)
+
+	return sorted
+}
+
+// Push the candidate collapses of the edges incident to a vertex.
+func (d *decimator) pushCandidates(vertex int) {
+	for _, neighbor := range d.getSortedNeighbors(vertex) {
+		for _, edge := range [][2]int{{vertex, neighbor}, {neighbor, vertex}} {
+			if !d.isLocked[edge[0]] {
+				heap.Push(&d.queue, collapseCandidate{
+					vertex:      edge[0],
+					parent:      edge[1],
+					cost:        d.getCost(edge[0], edge[1]),
+					vertexStamp: d.stamps[edge[0]],
+					parentStamp: d.stamps[edge[1]],
+				})
+			}
+		}
+	}
+}
+
+// Check if collapsing a vertex into its parent preserves the manifold and
+// does not flip any faces.
+func (d *decimator) isValidCollapse(vertex, parent int) bool {
+	var shared []int
+
+	for face := range d.vertexFaces[vertex] {
+		if d.vertexFaces[parent][face] {
+			shared = append(shared, face)
+		}
+	}
+
+	if len(shared) != 2 {
+		return false
+	}
+
+	// Link condition: the common neighbors are the opposite vertices of the
+	// two faces sharing the edge.
+	opposite := make(map[int]bool)
+
+	for _, face := range shared {
+		for _, v := range d.faces[face].Vertices {
+			if v != vertex && v != parent {
+				opposite[v] = true
+			}
+		}
+	}
+
+	neighbors := d.getNeighbors(parent)
+	var common int
+
+	for v := range d.getNeighbors(vertex) {
+		if neighbors[v] {
+			if !opposite[v] {
+				return false
+			}
+			common++
+		}
+	}
+
+	if common != 2 {
+		return false
+	}
+
+	// Each opposite vertex loses a neighbor and must keep at least three to
+	// avoid folding faces onto each other.
+	for v := range opposite {
+		if len(d.getNeighbors(v)) <= 3 {
+			return false
+		}
+	}
+
+	for face := range d.vertexFaces[vertex] {
+		if d.vertexFaces[parent][face] {
+			continue
+		}
+
+		vertices := d.faces[face].Vertices
+		before := meshx.NewTriangle(d.points[vertices[0]], d.points[vertices[1]], d.points[vertices[2]])
+		vertices[indexOf(vertices, vertex)] = parent
+		after := meshx.NewTriangle(d.points[vertices[0]], d.points[vertices[1]], d.points[vertices[2]])
+
+		if after.Normal().Dot(before.Normal()) <= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Collapse vertices until the number of faces is at most the target.
+func (d *decimator) decimate(ctx context.Context, targetFaces int, progress meshx.ProgressFunc) error {
+	initialFaces := d.nFaces
+	total := int64(max(initialFaces-targetFaces, 0))
+
+	for count := 0; d.nFaces > targetFaces && d.queue.Len() > 0; count++ {
+		if count%meshx.ProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			progress.Report(int64(initialFaces-d.nFaces), total)
+		}
+
+		candidate := heap.Pop(&d.queue).(collapseCandidate)
+
+		if candidate.vertexStamp != d.stamps[candidate.vertex] || candidate.parentStamp != d.stamps[candidate.parent] {
+			continue
+		}
+
+		if !d.isValidCollapse(candidate.vertex, candidate.parent) {
+			continue
+		}
+
+		d.collapse(candidate.vertex, candidate.parent)
+	}
+
+	progress.Report(int64(initialFaces-d.nFaces), total)
+
+	return nil
+}
+
+// Collapse a vertex into its parent.
+func (d *decimator) collapse(vertex, parent int) {
+	record := collapse{vertex: vertex, parent: parent}
+	faces := make([]int, 0, len(d.vertexFaces[vertex]))
+
+	for face := range d.vertexFaces[vertex] {
+		faces = append(faces, face)
+	}
+
+	sort.Ints(faces)
+
+	for _, face := range faces {
+		vertices := d.faces[face].Vertices
+
+		if d.vertexFaces[parent][face] {
+			record.removed = append(record.removed, face)
+			d.faceAlive[face] = false
+			d.nFaces--
+
+			for _, v := range vertices {
+				delete(d.vertexFaces[v], face)
+			}
+		} else {
+			record.updated = append(record.updated, face)
+			d.faces[face].Vertices[indexOf(vertices, vertex)] = parent
+			d.vertexFaces[parent][face] = true
+		}
+	}
+
+	d.vertexFaces[vertex] = nil
+
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			d.quadrics[parent][r][c] += d.quadrics[vertex][r][c]
+		}
+	}
+
+	d.collapses = append(d.collapses, record)
+	d.stamps[vertex]++
+	d.stamps[parent]++
+
+	affected := d.getSortedNeighbors(parent)
+
+	for _, v := range affected {
+		d.stamps[v]++
+	}
+
+	d.pushCandidates(parent)
+
+	for _, v := range affected {
+		d.pushCandidates(v)
+	}
+}
+
+// Build the progressive mesh from the decimated base mesh and the collapses
+// in reverse order.
+func (d *decimator) build() *ProgressiveMesh {
+	vertexMap := make([]int, len(d.points))
+	faceMap := make([]int, len(d.faces))
+
+	removed := make([]bool, len(d.points))
+
+	for _, record := range d.collapses {
+		removed[record.vertex] = true
+	}
+
+	var nVertices, nFaces int
+
+	for i := range d.points {
+		if !removed[i] {
+			vertexMap[i] = nVertices
+			nVertices++
+		}
+	}
+
+	for i := range d.faces {
+		if d.faceAlive[i] {
+			faceMap[i] = nFaces
+			nFaces++
+		}
+	}
+
+	for i := len(d.collapses) - 1; i >= 0; i-- {
+		vertexMap[d.collapses[i].vertex] = nVertices
+		nVertices++
+
+		for _, face := range d.collapses[i].removed {
+			faceMap[face] = nFaces
+			nFaces++
+		}
+	}
+
+	mapFace := func(face ProgressiveFace) ProgressiveFace {
+		for j, v := range face.Vertices {
+			face.Vertices[j] = vertexMap[v]
+		}
+		return face
+	}
+
+	vertices := make([]meshx.Vector, 0, nVertices)
+	faces := make([]ProgressiveFace, 0, nFaces)
+
+	for i, point := range d.points {
+		if !removed[i] {
+			vertices = append(vertices, point)
+		}
+	}
+
+	for i, face := range d.faces {
+		if d.faceAlive[i] {
+			faces = append(faces, mapFace(face))
+		}
+	}
+
+	p := NewProgressiveMeshFromBase(vertices, faces, d.patches)
+
+	for i := len(d.collapses) - 1; i >= 0; i-- {
+		record := d.collapses[i]
+
+		split := VertexSplit{
+			Parent:   vertexMap[record.parent],
+			Point:    d.points[record.vertex],
+			Faces:    make([]int, len(record.updated)),
+			NewFaces: make([]ProgressiveFace, len(record.removed)),
+		}
+
+		for j, face := range record.updated {
+			split.Faces[j] = faceMap[face]
+		}
+
+		for j, face := range record.removed {
+			split.NewFaces[j] = mapFace(d.faces[face])
+		}
+
+		p.AddSplit(split)
+	}
+
+	return p
+}
+
+// Candidate half-edge collapse with the stamps of its vertices when queued.
+type collapseCandidate struct {
+	vertex      int
+	parent      int
+	cost        float64
+	vertexStamp int
+	parentStamp int
+}
+
+// Priority queue of candidate collapses ordered by cost.
+type collapseQueue []collapseCandidate
+
+// Implement the heap.Interface interface.
+func (q collapseQueue) Len() int {
+	return len(q)
+}
+
+// Implement the heap.Interface interface. Ties of the cost are broken by the
+// vertex and parent so the collapses are reproducible.
+func (q collapseQueue) Less(i, j int) bool {
+	if q[i].cost == q[j].cost {
+		if q[i].vertex == q[j].vertex {
+			return q[i].parent < q[j].parent
+		}
+		return q[i].vertex < q[j].vertex
+	}
+	return q[i].cost < q[j].cost
+}
+
+// Implement the heap.Interface interface.
+func (q collapseQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Implement the heap.Interface interface.
+func (q *collapseQueue) Push(x any) {
+	*q = append(*q, x.(collapseCandidate))
+}
+
+// Implement the heap.Interface interface.
+func (q *collapseQueue) Pop() any {
+	n := len(*q)
+	candidate := (*q)[n-1]
+	*q = (*q)[:n-1]
+	return candidate
+}
@@ -0,0 +1,306 @@
+package exchange
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	progressiveMagic   = "MXPM"
+	progressiveVersion = 1
+)
+
+var (
+	ErrProgressiveInvalidFormat = errors.New("invalid progressive mesh format")
+)
+
+// Write a progressive mesh as its base mesh followed by its vertex splits so
+// it can be streamed and refined incrementally. The level of the mesh is
+// unchanged.
+func WriteProgressiveMesh(writer io.Writer, p *ProgressiveMesh) error {
+	level := p.GetLevel()
+	p.SetLevel(0)
+	defer p.SetLevel(level)
+
+	w := progressiveWriter{writer: bufio.NewWriter(writer)}
+
+	w.write([4]byte([]byte(progressiveMagic)))
+	w.write(uint32(progressiveVersion))
+	w.write(uint32(len(p.patches)))
+
+	for _, patch := range p.patches {
+		w.write(uint32(len(patch)))
+		w.write([]byte(patch))
+	}
+
+	w.write(uint32(len(p.vertices)))
+	w.write(uint32(len(p.faces)))
+	w.write(uint32(len(p.splits)))
+
+	for _, vertex := range p.vertices {
+		w.write([3]float64(vertex))
+	}
+
+	for _, face := range p.faces {
+		w.writeFace(face)
+	}
+
+	for _, split := range p.splits {
+		w.write(uint32(split.Parent))
+		w.write([3]float64(split.Point))
+		w.write(uint32(len(split.Faces)))
+
+		for _, face := range split.Faces {
+			w.write(uint32(face))
+		}
+
+		w.write(uint32(len(split.NewFaces)))
+
+		for _, face := range split.NewFaces {
+			w.writeFace(face)
+		}
+	}
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.writer.Flush()
+}
+
+// Write a progressive mesh to a file.
+func WriteProgressiveMeshToPath(path string, p *ProgressiveMesh) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	if err := WriteProgressiveMesh(file, p); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Binary writer retaining the first error.
+type progressiveWriter struct {
+	writer *bufio.Writer
+	err    error
+}
+
+// Write a value in little-endian order.
+func (w *progressiveWriter) write(value any) {
+	if w.err == nil {
+		w.err = binary.Write(w.writer, binary.LittleEndian, value)
+	}
+}
+
+// Write a face.
+func (w *progressiveWriter) writeFace(face ProgressiveFace) {
+	for _, vertex := range face.Vertices {
+		w.write(uint32(vertex))
+	}
+
+	w.write(int32(face.Patch))
+}
+
+// Streaming reader of a progressive mesh. The base mesh is read on
+// construction and the vertex splits are read (and applied) on demand.
+type ProgressiveMeshReader struct {
+	reader    *bufio.Reader
+	mesh      *ProgressiveMesh
+	remaining int
+}
+
+// Construct a progressive mesh reader and read the base mesh.
+func NewProgressiveMeshReader(reader io.Reader) (*ProgressiveMeshReader, error) {
+	var magic [4]byte
+	var version, nPatches, nVertices, nFaces, nSplits uint32
+
+	r := ProgressiveMeshReader{reader: bufio.NewReader(reader)}
+
+	if err := r.read(&magic, &version, &nPatches); err != nil {
+		return nil, err
+	}
+
+	if string(magic[:]) != progressiveMagic || version != progressiveVersion {
+		return nil, ErrProgressiveInvalidFormat
+	}
+
+	patches := make([]string, 0, min(nPatches, 1024))
+
+	for i := uint32(0); i < nPatches; i++ {
+		var length uint32
+
+		if err := r.read(&length); err != nil {
+			return nil, err
+		}
+
+		name := make([]byte, length)
+
+		if _, err := io.ReadFull(r.reader, name); err != nil {
+			return nil, ErrProgressiveInvalidFormat
+		}
+
+		patches = append(patches, string(name))
+	}
+
+	if err := r.read(&nVertices, &nFaces, &nSplits); err != nil {
+		return nil, err
+	}
+
+	vertices := make([]meshx.Vector, 0, min(nVertices, 1<<16))
+	faces := make([]ProgressiveFace, 0, min(nFaces, 1<<16))
+
+	for i := uint32(0); i < nVertices; i++ {
+		var vertex [3]float64
+
+		if err := r.read(&vertex); err != nil {
+			return nil, err
+		}
+
+		vertices = append(vertices, vertex)
+	}
+
+	for i := uint32(0); i < nFaces; i++ {
+		face, err := r.readFace(len(vertices), len(patches))
+
+		if err != nil {
+			return nil, err
+		}
+
+		faces = append(faces, face)
+	}
+
+	r.mesh = NewProgressiveMeshFromBase(vertices, faces, patches)
+	r.remaining = int(nSplits)
+
+	return &r, nil
+}
+
+// Read a progressive mesh in full.
+func ReadProgressiveMesh(reader io.Reader) (*ProgressiveMesh, error) {
+	r, err := NewProgressiveMeshReader(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.ReadSplits(r.GetNumberOfRemainingSplits()); err != nil {
+		return nil, err
+	}
+
+	return r.GetMesh(), nil
+}
+
+// Get the progressive mesh refined by the vertex splits read so far.
+func (r *ProgressiveMeshReader) GetMesh() *ProgressiveMesh {
+	return r.mesh
+}
+
+// Get the number of vertex splits not yet read.
+func (r *ProgressiveMeshReader) GetNumberOfRemainingSplits() int {
+	return r.remaining
+}
+
+// Read and apply up to n vertex splits. The number of splits read is
+// returned. The error is io.EOF if no splits remain.
+func (r *ProgressiveMeshReader) ReadSplits(n int) (int, error) {
+	var count int
+
+	if r.remaining == 0 && n > 0 {
+		return 0, io.EOF
+	}
+
+	for count < n && r.remaining > 0 {
+		var parent, nFaces, nNewFaces uint32
+		var point [3]float64
+
+		if err := r.read(&parent, &point, &nFaces); err != nil {
+			return count, err
+		}
+
+		split := VertexSplit{
+			Parent: int(parent),
+			Point:  point,
+			Faces:  make([]int, 0, min(nFaces, 64)),
+		}
+
+		for i := uint32(0); i < nFaces; i++ {
+			var face uint32
+
+			if err := r.read(&face); err != nil {
+				return count, err
+			}
+
+			split.Faces = append(split.Faces, int(face))
+		}
+
+		if err := r.read(&nNewFaces); err != nil {
+			return count, err
+		}
+
+		for i := uint32(0); i < nNewFaces; i++ {
+			face, err := r.readFace(r.mesh.GetNumberOfVertices()+1, len(r.mesh.patches))
+
+			if err != nil {
+				return count, err
+			}
+
+			split.NewFaces = append(split.NewFaces, face)
+		}
+
+		if err := r.mesh.AddSplit(split); err != nil {
+			return count, err
+		}
+
+		r.remaining--
+		count++
+	}
+
+	return count, nil
+}
+
+// Read values in little-endian order.
+func (r *ProgressiveMeshReader) read(values ...any) error {
+	for _, value := range values {
+		if err := binary.Read(r.reader, binary.LittleEndian, value); err != nil {
+			return ErrProgressiveInvalidFormat
+		}
+	}
+
+	return nil
+}
+
+// Read a face with vertex and patch indices in range.
+func (r *ProgressiveMeshReader) readFace(nVertices, nPatches int) (ProgressiveFace, error) {
+	var vertices [3]uint32
+	var patch int32
+
+	if err := r.read(&vertices, &patch); err != nil {
+		return ProgressiveFace{}, err
+	}
+
+	face := ProgressiveFace{Patch: int(patch)}
+
+	for i, vertex := range vertices {
+		if int(vertex) >= nVertices {
+			return ProgressiveFace{}, ErrProgressiveInvalidFormat
+		}
+
+		face.Vertices[i] = int(vertex)
+	}
+
+	if face.Patch < -1 || face.Patch >= nPatches {
+		return ProgressiveFace{}, ErrProgressiveInvalidFormat
+	}
+
+	return face, nil
+}
@@ -0,0 +1,249 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/stretchr/testify/assert"
+)
+
+// In-memory mesh source for testing.
+type testSource struct {
+	vertices []meshx.Vector
+	faces    [][]int
+}
+
+func (s *testSource) Read() error                      { return nil }
+func (s *testSource) GetNumberOfVertices() int         { return len(s.vertices) }
+func (s *testSource) GetNumberOfFaces() int            { return len(s.faces) }
+func (s *testSource) GetNumberOfPatches() int          { return 1 }
+func (s *testSource) GetVertex(index int) meshx.Vector { return s.vertices[index] }
+func (s *testSource) GetFace(index int) []int          { return s.faces[index] }
+func (s *testSource) GetFacePatch(index int) int       { return 0 }
+func (s *testSource) GetPatch(index int) string        { return "sphere" }
+
+func (s *testSource) GetNumberOfFaceEdges() int {
+	var count int
+	for _, face := range s.faces {
+		count += len(face)
+	}
+	return count
+}
+
+// Generate a closed UV sphere with quadrilaterals and pole triangles.
+func newTestSphere(nu, nv int) *testSource {
+	source := testSource{}
+	source.vertices = append(source.vertices, meshx.NewVector(0, 0, -1))
+
+	for j := 1; j < nv; j++ {
+		phi := math.Pi * (float64(j)/float64(nv) - 0.5)
+
+		for i := 0; i < nu; i++ {
+			theta := 2 * math.Pi * float64(i) / float64(nu)
+			source.vertices = append(source.vertices, meshx.NewVector(
+				math.Cos(phi)*math.Cos(theta),
+				math.Cos(phi)*math.Sin(theta),
+				math.Sin(phi),
+			))
+		}
+	}
+
+	source.vertices = append(source.vertices, meshx.NewVector(0, 0, 1))
+	top := len(source.vertices) - 1
+	ring := func(j, i int) int { return 1 + (j-1)*nu + i%nu }
+
+	for i := 0; i < nu; i++ {
+		source.faces = append(source.faces, []int{0, ring(1, i+1), ring(1, i)})
+		source.faces = append(source.faces, []int{top, ring(nv-1, i), ring(nv-1, i+1)})
+
+		for j := 1; j < nv-1; j++ {
+			source.faces = append(source.faces, []int{ring(j, i), ring(j, i+1), ring(j+1, i+1), ring(j+1, i)})
+		}
+	}
+
+	return &source
+}
+
+// Get the faces of a mesh as sorted strings of their vertex coordinates so
+// meshes can be compared independent of the vertex order.
+func getFaceKeys(source meshx.MeshReader) []string {
+	keys := make([]string, source.GetNumberOfFaces())
+
+	for i := range keys {
+		face := source.GetFace(i)
+		points := make([]string, len(face))
+
+		for j, vertex := range face {
+			points[j] = fmt.Sprint(source.GetVertex(vertex))
+		}
+
+		// Rotate the face to start with the smallest point.
+		first := 0
+
+		for j := range points {
+			if points[j] < points[first] {
+				first = j
+			}
+		}
+
+		keys[i] = fmt.Sprint(append(points[first:], points[:first]...))
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Test decimating a mesh into a progressive mesh and refining it back to the
+// full resolution mesh.
+func TestNewProgressiveMesh(t *testing.T) {
+	source := newTestSphere(16, 8)
+	full, err := NewProgressiveMesh(source, 1<<30)
+	assert.Empty(t, err)
+	assert.Equal(t, 0, full.GetNumberOfSplits())
+	assert.Equal(t, 2*16+2*16*6, full.GetNumberOfFaces())
+
+	p, err := NewProgressiveMesh(source, 40)
+	assert.Empty(t, err)
+	assert.Greater(t, p.GetNumberOfSplits(), 0)
+	assert.Equal(t, p.GetNumberOfSplits(), p.GetLevel())
+	assert.Equal(t, getFaceKeys(full), getFaceKeys(p))
+
+	assert.Equal(t, p.GetNumberOfSplits(), p.Coarsen(1<<30))
+	assert.Equal(t, 0, p.GetLevel())
+	assert.LessOrEqual(t, p.GetNumberOfFaces(), 40)
+
+	base, err := halfedge.NewHalfEdgeMesh(p)
+	assert.Empty(t, err)
+	assert.True(t, base.IsClosed())
+	assert.True(t, base.IsConsistent())
+
+	p.SetLevel(p.GetNumberOfSplits() / 2)
+	mid, err := halfedge.NewHalfEdgeMesh(p)
+	assert.Empty(t, err)
+	assert.True(t, mid.IsClosed())
+	assert.Greater(t, mid.GetNumberOfFaces(), base.GetNumberOfFaces())
+
+	p.SetLevel(p.GetNumberOfSplits())
+	assert.Equal(t, getFaceKeys(full), getFaceKeys(p))
+
+	_, err = NewProgressiveMesh(source, -1)
+	assert.ErrorIs(t, err, ErrProgressiveInvalidTarget)
+}
+
+// Test decimating a mesh is reproducible, including a planar grid where
+// every collapse has the same (zero) cost.
+func TestNewProgressiveMeshReproducible(t *testing.T) {
+	grid := testSource{}
+
+	for j := range 9 {
+		for i := range 9 {
+			grid.vertices = append(grid.vertices, meshx.NewVector(float64(i), float64(j), 0))
+		}
+	}
+
+	for j := range 8 {
+		for i := range 8 {
+			v := 9*j + i
+			grid.faces = append(grid.faces, []int{v, v + 1, v + 10, v + 9})
+		}
+	}
+
+	for _, source := range []*testSource{newTestSphere(16, 8), &grid} {
+		var expected []byte
+
+		for range 5 {
+			p, err := NewProgressiveMesh(source, 40)
+			assert.Empty(t, err)
+
+			var buffer bytes.Buffer
+			assert.Empty(t, WriteProgressiveMesh(&buffer, p))
+
+			if expected == nil {
+				expected = buffer.Bytes()
+			}
+
+			assert.Equal(t, expected, buffer.Bytes())
+		}
+	}
+}
+
+// Test decimating a mesh into a progressive mesh with progress and
+// cancellation.
+func TestNewProgressiveMeshContext(t *testing.T) {
+	source := newTestSphere(16, 8)
+	initial := int64(2*16 + 2*16*6)
+
+	var completed, total int64
+	progress := func(c, t int64) { completed, total = c, t }
+
+	p, err := NewProgressiveMeshContext(context.Background(), source, 40, progress)
+	assert.Empty(t, err)
+	assert.Equal(t, initial-40, total)
+
+	p.Coarsen(p.GetNumberOfSplits())
+	assert.Equal(t, initial-int64(p.GetNumberOfFaces()), completed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = NewProgressiveMeshContext(ctx, source, 40, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// Test streaming a progressive mesh and refining it incrementally.
+func TestProgressiveMeshReader(t *testing.T) {
+	source := newTestSphere(12, 6)
+	p, err := NewProgressiveMesh(source, 20)
+	assert.Empty(t, err)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, WriteProgressiveMesh(&buffer, p))
+	assert.Equal(t, p.GetNumberOfSplits(), p.GetLevel())
+
+	reader, err := NewProgressiveMeshReader(&buffer)
+	assert.Empty(t, err)
+	assert.Equal(t, p.GetNumberOfSplits(), reader.GetNumberOfRemainingSplits())
+	assert.Equal(t, "sphere", reader.GetMesh().GetPatch(0))
+
+	nFaces := reader.GetMesh().GetNumberOfFaces()
+
+	for reader.GetNumberOfRemainingSplits() > 0 {
+		count, err := reader.ReadSplits(10)
+		assert.Empty(t, err)
+		assert.Greater(t, count, 0)
+		assert.Greater(t, reader.GetMesh().GetNumberOfFaces(), nFaces)
+		nFaces = reader.GetMesh().GetNumberOfFaces()
+	}
+
+	_, err = reader.ReadSplits(1)
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, getFaceKeys(p), getFaceKeys(reader.GetMesh()))
+}
+
+// Test reading an invalid progressive mesh.
+func TestReadProgressiveMeshInvalid(t *testing.T) {
+	p, err := NewProgressiveMesh(newTestSphere(8, 4), 10)
+	assert.Empty(t, err)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, WriteProgressiveMesh(&buffer, p))
+	data := buffer.Bytes()
+
+	_, err = ReadProgressiveMesh(bytes.NewReader(data[:len(data)-2]))
+	assert.ErrorIs(t, err, ErrProgressiveInvalidFormat)
+
+	_, err = ReadProgressiveMesh(bytes.NewReader([]byte("MXOT")))
+	assert.ErrorIs(t, err, ErrProgressiveInvalidFormat)
+
+	result, err := ReadProgressiveMesh(bytes.NewReader(data))
+	assert.Empty(t, err)
+	assert.Equal(t, getFaceKeys(p), getFaceKeys(result))
+}
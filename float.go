@@ -0,0 +1,58 @@
+package meshx
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Formatting of the floating point numbers written to text files.
+type FloatFormat int
+
+const (
+	// Fixed six decimal places (e.g. 0.333333).
+	FloatFixed FloatFormat = iota
+
+	// Shortest representation read back as the same float64 (e.g.
+	// 0.3333333333333333) such that the coordinates round trip bit-exactly.
+	FloatRoundTrip
+)
+
+// Format a float64.
+func (f FloatFormat) Format(value float64) string {
+	if f == FloatRoundTrip {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
+
+// Format the components of a vector separated by spaces.
+func (f FloatFormat) FormatVector(vector Vector) string {
+	return f.Format(vector[0]) + " " + f.Format(vector[1]) + " " + f.Format(vector[2])
+}
+
+// Check if the text of a decimal number has more significant digits than
+// the shortest representation of the float64 parsed from it, in which case
+// the digits are lost reading it.
+func isFloatPrecisionLost(text string, value float64) bool {
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return false
+	}
+
+	return getSignificantDigits(text) > getSignificantDigits(strconv.FormatFloat(value, 'e', -1, 64))
+}
+
+// Count the significant digits of the mantissa of a decimal number, ignoring
+// the leading and trailing zeros.
+func getSignificantDigits(text string) int {
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		text = text[:i]
+	}
+
+	text = strings.TrimLeft(text, "+-")
+	text = strings.Replace(text, ".", "", 1)
+	text = strings.Trim(text, "0")
+
+	return len(text)
+}
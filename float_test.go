@@ -0,0 +1,41 @@
+package meshx
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test formatting floats with fixed decimals or the shortest round trip.
+func TestFloatFormat(t *testing.T) {
+	assert.Equal(t, "0.333333", FloatFixed.Format(1.0/3))
+	assert.Equal(t, "0.3333333333333333", FloatRoundTrip.Format(1.0/3))
+	assert.Equal(t, "1e-09", FloatRoundTrip.Format(1e-9))
+	assert.Equal(t, "1 -2.5 0", FloatRoundTrip.FormatVector(NewVector(1, -2.5, 0)))
+
+	for _, value := range []float64{math.Pi, 1e-300, -123456.789, math.Nextafter(1, 2)} {
+		parsed, err := strconv.ParseFloat(FloatRoundTrip.Format(value), 64)
+		assert.Empty(t, err)
+		assert.Equal(t, value, parsed)
+	}
+}
+
+// Test detecting digits lost parsing a float.
+func TestIsFloatPrecisionLost(t *testing.T) {
+	for text, expected := range map[string]bool{
+		"1.000000":               false,
+		"-0.000100":              false,
+		"100":                    false,
+		"0.3333333333333333":     false,
+		"1.5e+10":                false,
+		"0.12345678901234567890": true,
+		"1.00000000000000000001": true,
+		"inf":                    false,
+	} {
+		value, err := strconv.ParseFloat(text, 64)
+		assert.Empty(t, err)
+		assert.Equal(t, expected, isFloatPrecisionLost(text, value), text)
+	}
+}
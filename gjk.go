@@ -0,0 +1,183 @@
+package meshx
+
+// Support function of a convex shape: the point of the shape furthest along
+// a direction.
+type supportFunc func(Vector) Vector
+
+// Maximum number of iterations of the GJK algorithm.
+const gjkMaxIterations = 64
+
+// Return true if two convex shapes intersect (or touch) using the
+// Gilbert-Johnson-Keerthi algorithm on their Minkowski difference.
+func gjkIntersects(a, b supportFunc) bool {
+	return gjkIntersectsWithIterations(a, b, gjkMaxIterations)
+}
+
+// Return true if two convex shapes intersect (or touch) within a maximum
+// number of iterations. If the iterations run out, the shapes intersect only
+// if the last simplex (a subset of the Minkowski difference) touches the
+// origin within the tolerance relative to its size.
+func gjkIntersectsWithIterations(a, b supportFunc, iterations int) bool {
+	support := func(d Vector) Vector {
+		return a(d).Sub(b(d.MulScalar(-1)))
+	}
+
+	d := NewVector(1, 0, 0)
+	simplex := make([]Vector, 1, 4)
+	simplex[0] = support(d)
+	d = simplex[0].MulScalar(-1)
+
+	for i := 0; i < iterations; i++ {
+		if d.Dot(d) < 1e-24 {
+			return true
+		}
+
+		p := support(d)
+
+		if p.Dot(d) < 0 {
+			return false
+		}
+
+		simplex = append(simplex, p)
+
+		var contains bool
+		simplex, d, contains = gjkSimplex(simplex)
+
+		if contains {
+			return true
+		}
+	}
+
+	// The search direction is normal to the feature of the simplex nearest
+	// the origin, so the distance to the origin is along it.
+	var size float64
+
+	for _, p := range simplex {
+		size = max(size, p.Mag())
+	}
+
+	mag := d.Mag()
+
+	if mag == 0 {
+		return true
+	}
+
+	return -simplex[0].Dot(d)/mag <= 1e-9*size
+}
+
+// Reduce the simplex to the feature nearest the origin and compute the next
+// search direction. The last point of the simplex is the newest. Returns true
+// if the simplex contains the origin.
+func gjkSimplex(simplex []Vector) ([]Vector, Vector, bool) {
+	switch len(simplex) {
+	case 2:
+		return gjkLine(simplex[1], simplex[0])
+	case 3:
+		return gjkTriangle(simplex[2], simplex[1], simplex[0])
+	default:
+		return gjkTetrahedron(simplex[3], simplex[2], simplex[1], simplex[0])
+	}
+}
+
+// Handle the line simplex (a is newest).
+func gjkLine(a, b Vector) ([]Vector, Vector, bool) {
+	ab := b.Sub(a)
+	ao := a.MulScalar(-1)
+
+	if ab.Dot(ao) > 0 {
+		d := ab.Cross(ao).Cross(ab)
+
+		if d.Dot(d) < 1e-24*ab.Dot(ab)*ab.Dot(ab) {
+			return nil, d, true
+		}
+
+		return []Vector{b, a}, d, false
+	}
+
+	return []Vector{a}, ao, false
+}
+
+// Handle the triangle simplex (a is newest).
+func gjkTriangle(a, b, c Vector) ([]Vector, Vector, bool) {
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	ao := a.MulScalar(-1)
+	abc := ab.Cross(ac)
+
+	if abc.Cross(ac).Dot(ao) > 0 {
+		if ac.Dot(ao) > 0 {
+			return []Vector{c, a}, ac.Cross(ao).Cross(ac), false
+		}
+
+		return gjkLine(a, b)
+	}
+
+	if ab.Cross(abc).Dot(ao) > 0 {
+		return gjkLine(a, b)
+	}
+
+	side := abc.Dot(ao)
+
+	if side*side < 1e-24*abc.Dot(abc) {
+		return nil, abc, true
+	}
+
+	if side > 0 {
+		return []Vector{c, b, a}, abc, false
+	}
+
+	return []Vector{b, c, a}, abc.MulScalar(-1), false
+}
+
+// Handle the tetrahedron simplex (a is newest).
+func gjkTetrahedron(a, b, c, d Vector) ([]Vector, Vector, bool) {
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	ad := d.Sub(a)
+	ao := a.MulScalar(-1)
+
+	if ab.Cross(ac).Dot(ao) > 0 {
+		return gjkTriangle(a, b, c)
+	}
+
+	if ac.Cross(ad).Dot(ao) > 0 {
+		return gjkTriangle(a, c, d)
+	}
+
+	if ad.Cross(ab).Dot(ao) > 0 {
+		return gjkTriangle(a, d, b)
+	}
+
+	return nil, Vector{}, true
+}
+
+// Implement the support function of a triangle.
+func (t Triangle) support(d Vector) Vector {
+	p := t.P
+	value := t.P.Dot(d)
+
+	if v := t.Q.Dot(d); v > value {
+		p, value = t.Q, v
+	}
+
+	if v := t.R.Dot(d); v > value {
+		p = t.R
+	}
+
+	return p
+}
+
+// Implement the support function of an AABB.
+func (a AABB) support(d Vector) Vector {
+	p := a.Center
+
+	for i := 0; i < 3; i++ {
+		if d[i] >= 0 {
+			p[i] += a.HalfSize[i]
+		} else {
+			p[i] -= a.HalfSize[i]
+		}
+	}
+
+	return p
+}
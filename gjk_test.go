@@ -0,0 +1,24 @@
+package meshx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the GJK result when the iterations run out before converging.
+func TestGJKIntersectsIterations(t *testing.T) {
+	aabb := NewAABB(NewVector(0, 0, 0), NewVector(0.5, 0.5, 0.5))
+
+	separated := NewCapsule(NewVector(1.5, 1.5, -1), NewVector(1.5, 1.5, 1), 0.1)
+	touching := NewCapsule(NewVector(0.6, 0, -1), NewVector(0.6, 0, 1), 0.1)
+	overlapping := NewCapsule(NewVector(0.5, 0, -1), NewVector(0.5, 0, 1), 0.2)
+
+	for i := range gjkMaxIterations {
+		assert.False(t, gjkIntersectsWithIterations(separated.support, aabb.support, i))
+	}
+
+	assert.True(t, gjkIntersects(touching.support, aabb.support))
+	assert.True(t, gjkIntersects(overlapping.support, aabb.support))
+	assert.False(t, gjkIntersects(separated.support, aabb.support))
+}
@@ -0,0 +1,127 @@
+package halfedge
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Lazily built adjacency and edges of a mesh. The flags are set once built
+// so that the queries skip the lock.
+type lazyState struct {
+	mutex    sync.Mutex
+	adjacent atomic.Bool
+	indexed  atomic.Bool
+}
+
+// Return true if the twins of the half edges are matched. The adjacency is
+// only pending for a mesh constructed with deferred adjacency.
+func (m *HalfEdgeMesh) HasAdjacency() bool {
+	if m.lazy != nil && m.lazy.adjacent.Load() {
+		return true
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	return !m.deferAdjacency
+}
+
+// Match the twins of the half edges if deferred. ErrNonManifold is returned
+// if the mesh is non-manifold, in which case the half edges of the
+// non-manifold edges are left unmatched (as boundaries).
+func (m *HalfEdgeMesh) BuildAdjacency() error {
+	m.lock()
+	defer m.unlock()
+
+	m.buildAdjacency()
+
+	return m.adjacencyErr
+}
+
+// Build the adjacency before a topological query. The non-manifold edges of
+// a deferred mesh are boundaries to the query.
+func (m *HalfEdgeMesh) ensureAdjacency() {
+	if m.lazy != nil && m.lazy.adjacent.Load() {
+		return
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	m.buildAdjacency()
+}
+
+// Match the twins of the half edges if deferred (the lock must be held).
+func (m *HalfEdgeMesh) buildAdjacency() {
+	if m.deferAdjacency {
+		m.adjacencyErr = matchTwins(m.halfEdges, m.getBaffleFaces(), false)
+		m.deferAdjacency = false
+	}
+
+	if m.lazy != nil {
+		m.lazy.adjacent.Store(true)
+	}
+}
+
+// Index the edges before an edge query. Each edge is indexed by the first of
+// its half edges, so the edges are in the order of their first half edges.
+func (m *HalfEdgeMesh) ensureEdges() {
+	if m.lazy != nil && m.lazy.indexed.Load() {
+		return
+	}
+
+	m.lock()
+	defer m.unlock()
+
+	if m.edges != nil {
+		return
+	}
+
+	m.buildAdjacency()
+
+	edges := make([]Edge, 0, len(m.halfEdges)/2+1)
+	halfEdgeEdges := make([]int, len(m.halfEdges))
+
+	for index, halfEdge := range m.halfEdges {
+		if halfEdge.IsBoundary() || index < halfEdge.Twin {
+			halfEdgeEdges[index] = len(edges)
+			edges = append(edges, Edge{index, halfEdge.Twin})
+		}
+	}
+
+	for index, halfEdge := range m.halfEdges {
+		if !halfEdge.IsBoundary() && index > halfEdge.Twin {
+			halfEdgeEdges[index] = halfEdgeEdges[halfEdge.Twin]
+		}
+	}
+
+	m.edges = edges
+	m.halfEdgeEdges = halfEdgeEdges
+
+	if m.lazy != nil {
+		m.lazy.indexed.Store(true)
+	}
+}
+
+// Invalidate the indexed edges after an edit.
+func (m *HalfEdgeMesh) resetEdges() {
+	m.edges, m.halfEdgeEdges = nil, nil
+
+	if m.lazy != nil {
+		m.lazy.indexed.Store(false)
+	}
+}
+
+// Lock the lazy adjacency and edges.
+func (m *HalfEdgeMesh) lock() {
+	if m.lazy != nil {
+		m.lazy.mutex.Lock()
+	}
+}
+
+// Unlock the lazy adjacency and edges.
+func (m *HalfEdgeMesh) unlock() {
+	if m.lazy != nil {
+		m.lazy.mutex.Unlock()
+	}
+}
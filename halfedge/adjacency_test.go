@@ -0,0 +1,92 @@
+package halfedge
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test constructing a mesh with deferred adjacency.
+func TestDeferAdjacency(t *testing.T) {
+	reader, err := meshx.ReadOBJFromPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	options := HalfEdgeMeshOptions{DeferAdjacency: true}
+	mesh, err := NewHalfEdgeMeshWithOptions(reader, options)
+	assert.Empty(t, err)
+	assert.False(t, mesh.HasAdjacency())
+
+	for _, halfEdge := range mesh.halfEdges {
+		assert.True(t, halfEdge.IsBoundary())
+	}
+
+	var buffer bytes.Buffer
+	assert.Empty(t, mesh.WriteOBJ(&buffer))
+	assert.False(t, mesh.HasAdjacency())
+
+	assert.True(t, mesh.IsClosed())
+	assert.True(t, mesh.HasAdjacency())
+
+	expected, err := NewHalfEdgeMesh(reader)
+	assert.Empty(t, err)
+	assert.Equal(t, expected, mesh)
+}
+
+// Test building the deferred adjacency of a non-manifold mesh.
+func TestDeferAdjacencyNonManifold(t *testing.T) {
+	source := newTwinTestSource(4)
+	source.vertices = append(source.vertices, meshx.NewVector(0, 0, 1))
+	source.addFace([]int{1, len(source.vertices) - 1, 5}, Face{Patch: -1, Material: -1})
+
+	mesh, err := NewHalfEdgeMeshWithOptions(source, HalfEdgeMeshOptions{DeferAdjacency: true})
+	assert.Empty(t, err)
+
+	assert.False(t, mesh.IsClosed())
+	assert.True(t, mesh.HasAdjacency())
+	assert.ErrorIs(t, mesh.BuildAdjacency(), meshx.ErrNonManifold)
+
+	// The three half edges of the non-manifold edge are left unmatched in
+	// addition to the 12 of the grid and 2 of the added face.
+	var boundary int
+
+	for _, halfEdge := range mesh.halfEdges {
+		if halfEdge.IsBoundary() {
+			boundary++
+		}
+	}
+
+	assert.Equal(t, 17, boundary)
+}
+
+// Test building the deferred adjacency and edges from concurrent readers.
+func TestDeferAdjacencyConcurrent(t *testing.T) {
+	reader, err := meshx.ReadOBJFromPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh, err := NewHalfEdgeMeshWithOptions(reader, HalfEdgeMeshOptions{DeferAdjacency: true})
+	assert.Empty(t, err)
+
+	var wg sync.WaitGroup
+	closed := make([]bool, 8)
+	edges := make([]int, 8)
+
+	for i := range closed {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			closed[i] = mesh.IsClosed()
+			edges[i] = mesh.GetNumberOfEdges()
+		}()
+	}
+
+	wg.Wait()
+
+	for i := range closed {
+		assert.True(t, closed[i])
+		assert.Equal(t, mesh.GetNumberOfHalfEdges()/2, edges[i])
+	}
+}
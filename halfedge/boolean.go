@@ -0,0 +1,254 @@
+package halfedge
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+var (
+	ErrInvalidBooleanOperation = errors.New("invalid boolean operation")
+)
+
+// Boolean operation of two closed solids.
+type BooleanOperation int
+
+const (
+	// Solid inside either mesh.
+	BooleanUnion BooleanOperation = iota
+
+	// Solid inside both meshes.
+	BooleanIntersection
+
+	// Solid inside the first mesh and outside the second.
+	BooleanDifference
+)
+
+// Side of a piece of a face relative to the solid of the other mesh.
+type booleanSide int
+
+const (
+	booleanOutside booleanSide = iota
+	booleanInside
+
+	// On a face of the other mesh of the same orientation.
+	booleanSame
+
+	// On a face of the other mesh of the opposite orientation.
+	booleanOpposite
+)
+
+// Compute a Boolean operation of two closed solids as a new mesh. The faces
+// of each mesh are split along the surface of the other and the pieces are
+// kept by their side of the other solid. Coplanar faces in contact are kept
+// once if the result is on the same side of both. The pieces are welded and
+// stitched within the tolerance, so the faces along the intersection are
+// convex polygons which may have vertices along their edges. The faces keep
+// their patches (merged by name). An error is returned if either mesh is not
+// closed or the stitched result is non-manifold.
+func Boolean(a, b *HalfEdgeMesh, operation BooleanOperation, tolerance float64) (*HalfEdgeMesh, error) {
+	if operation < BooleanUnion || operation > BooleanDifference || !(tolerance > 0) {
+		return nil, ErrInvalidBooleanOperation
+	}
+
+	if !a.IsClosed() || !b.IsClosed() {
+		return nil, ErrOpenSurface
+	}
+
+	// Sides of the other solid kept of the pieces of each mesh. The pieces of
+	// a shared face are only kept from the first mesh.
+	var keepA, keepB []booleanSide
+
+	switch operation {
+	case BooleanUnion:
+		keepA, keepB = []booleanSide{booleanOutside, booleanSame}, []booleanSide{booleanOutside}
+	case BooleanIntersection:
+		keepA, keepB = []booleanSide{booleanInside, booleanSame}, []booleanSide{booleanInside}
+	case BooleanDifference:
+		keepA, keepB = []booleanSide{booleanOutside, booleanOpposite}, []booleanSide{booleanInside}
+	}
+
+	piecesA := a.getBooleanPieces(b, keepA, tolerance)
+	piecesB := b.getBooleanPieces(a, keepB, tolerance)
+	points := make([]meshx.Vector, 0)
+
+	for _, pieces := range []map[int][][]meshx.Vector{piecesA, piecesB} {
+		for _, polygons := range pieces {
+			for _, polygon := range polygons {
+				points = append(points, polygon...)
+			}
+		}
+	}
+
+	sourceB := b.getImprintSource(piecesB, points, tolerance)
+
+	// The faces of the second mesh inside the first bound the difference
+	// from the other side.
+	if operation == BooleanDifference {
+		for _, face := range sourceB.faces {
+			slices.Reverse(face)
+		}
+	}
+
+	result, err := NewHalfEdgeMesh(a.getImprintSource(piecesA, points, tolerance))
+
+	if err != nil {
+		return nil, err
+	}
+
+	meshB, err := NewHalfEdgeMesh(sourceB)
+
+	if err != nil {
+		return nil, err
+	}
+
+	options := MergeOptions{MergePatches: true, WeldVertices: true, WeldTolerance: tolerance}
+
+	if err := result.MergeWithOptions(meshB, options); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Get the pieces kept of the faces of the mesh by their side of the solid of
+// another mesh (see getImprintSource). Each face intersecting the other mesh
+// is split along the planes of the faces it intersects. The faces kept whole
+// are omitted and the faces dropped have no pieces.
+func (m *HalfEdgeMesh) getBooleanPieces(other *HalfEdgeMesh, keep []booleanSide, tolerance float64) map[int][][]meshx.Vector {
+	octree := other.BuildOctree()
+	winding := spatial.NewWindingNumberTree(other.GetTriangles())
+	halfSize := meshx.NewVector(tolerance, tolerance, tolerance)
+	pieces := make(map[int][][]meshx.Vector)
+
+	for i := range m.GetNumberOfFaces() {
+		aabb := meshx.NewAABBFromVectors(m.getFacePoints(i))
+		aabb.HalfSize = aabb.HalfSize.Add(halfSize)
+		candidates := octree.Query(aabb)
+		polygons := make([][]meshx.Vector, 0)
+		isSplit := false
+
+		for _, triangle := range getSolidTriangles(m.GetFaceTriangles(i)) {
+			normal := triangle.UnitNormal()
+			split := [][]meshx.Vector{{triangle.P, triangle.Q, triangle.R}}
+
+			for _, j := range candidates {
+				for _, face := range getSolidTriangles(other.GetFaceTriangles(j)) {
+					if isCoplanar(triangle, face, tolerance) {
+						if _, ok := getTriangleOverlap(triangle, face, tolerance); ok {
+							for _, plane := range getProjectedEdgePlanes(face, triangle.P, normal) {
+								split = splitPolygons(split, plane[0], plane[1], tolerance)
+							}
+
+							isSplit = true
+						}
+					} else if isTriangleIntersection(triangle, face) {
+						split = splitPolygons(split, face.P, face.UnitNormal(), tolerance)
+						isSplit = true
+					}
+				}
+			}
+
+			polygons = append(polygons, split...)
+		}
+
+		if !isSplit {
+			side := getBooleanSide(winding, m.GetFaceCentroid(i), m.GetFaceNormal(i), tolerance)
+
+			if !slices.Contains(keep, side) {
+				pieces[i] = nil
+			}
+
+			continue
+		}
+
+		kept := make([][]meshx.Vector, 0, len(polygons))
+
+		for _, polygon := range polygons {
+			var centroid meshx.Vector
+
+			for _, point := range polygon {
+				centroid = centroid.Add(point)
+			}
+
+			centroid = centroid.DivScalar(float64(len(polygon)))
+
+			if side := getBooleanSide(winding, centroid, getPolygonNormal(polygon).Unit(), tolerance); slices.Contains(keep, side) {
+				kept = append(kept, polygon)
+			}
+		}
+
+		pieces[i] = kept
+	}
+
+	return pieces
+}
+
+// Get the side of a solid (by its winding number) of a point on a surface
+// with a unit normal from the points just in front of and behind it.
+func getBooleanSide(winding *spatial.WindingNumberTree, point, normal meshx.Vector, tolerance float64) booleanSide {
+	front := winding.Evaluate(point.Add(normal.MulScalar(tolerance))) > 0.5
+	back := winding.Evaluate(point.Sub(normal.MulScalar(tolerance))) > 0.5
+
+	switch {
+	case front && back:
+		return booleanInside
+	case front:
+		return booleanOpposite
+	case back:
+		return booleanSame
+	default:
+		return booleanOutside
+	}
+}
+
+// Split convex polygons by a plane (origin and normal). A polygon is kept
+// whole if either side is narrower than the tolerance.
+func splitPolygons(polygons [][]meshx.Vector, origin, normal meshx.Vector, tolerance float64) [][]meshx.Vector {
+	split := make([][]meshx.Vector, 0, len(polygons))
+
+	for _, polygon := range polygons {
+		behind := clipPolygon(polygon, origin, normal)
+		front := clipPolygon(polygon, origin, normal.MulScalar(-1))
+
+		if isSliver(behind, tolerance) || isSliver(front, tolerance) {
+			split = append(split, polygon)
+		} else {
+			split = append(split, behind, front)
+		}
+	}
+
+	return split
+}
+
+// Return true if the vertices of a triangle are within the tolerance of the
+// plane of another.
+func isCoplanar(triangle, other meshx.Triangle, tolerance float64) bool {
+	normal := triangle.UnitNormal()
+
+	for _, point := range []meshx.Vector{other.P, other.Q, other.R} {
+		if math.Abs(point.Sub(triangle.P).Dot(normal)) > tolerance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Return true if two triangles intersect (including touching).
+func isTriangleIntersection(p, q meshx.Triangle) bool {
+	for _, pair := range [2][2]meshx.Triangle{{p, q}, {q, p}} {
+		points := []meshx.Vector{pair[0].P, pair[0].Q, pair[0].R}
+
+		for k, point := range points {
+			if meshx.NewSegment(point, points[(k+1)%3]).IntersectsTriangle(pair[1]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Construct a box of quads oriented outward.
+func newTestBox(t *testing.T, minBound, maxBound meshx.Vector) *HalfEdgeMesh {
+	source := meshSource{patches: []string{"box"}}
+	source.addBox(minBound, maxBound)
+
+	for i := range source.facePatches {
+		source.facePatches[i] = 0
+	}
+
+	mesh, err := NewHalfEdgeMesh(&source)
+	assert.Empty(t, err)
+	return mesh
+}
+
+// Test the Boolean operations of overlapping boxes are closed solids of the
+// expected volumes.
+func TestBoolean(t *testing.T) {
+	cases := []struct {
+		name    string
+		b       meshx.Vector
+		volumes [3]float64
+	}{
+		{"corner", meshx.NewVector(0.5, 0.5, 0.5), [3]float64{1.875, 0.125, 0.875}},
+		{"coplanar", meshx.NewVector(0.5, 0, 0), [3]float64{1.5, 0.5, 0.5}},
+		{"offset", meshx.NewVector(0.3, 0.4, 0.2), [3]float64{1.664, 0.336, 0.664}},
+	}
+
+	for _, c := range cases {
+		a := newTestBox(t, meshx.NewVector(0, 0, 0), meshx.NewVector(1, 1, 1))
+		b := newTestBox(t, c.b, c.b.Add(meshx.NewVector(1, 1, 1)))
+
+		for operation, volume := range c.volumes {
+			result, err := Boolean(a, b, BooleanOperation(operation), 1e-9)
+			assert.Empty(t, err, c.name)
+			assert.True(t, result.IsClosed(), c.name)
+			assert.True(t, result.IsConsistent(), c.name)
+			assert.Equal(t, 1, result.GetNumberOfPatches(), c.name)
+
+			properties, err := result.ComputeMassProperties(1)
+			assert.Empty(t, err, c.name)
+			assert.InDelta(t, volume, properties.Volume, 1e-9, c.name)
+		}
+	}
+
+	// A box through the side of a cylinder: the volumes of the union and the
+	// intersection sum to those of the solids.
+	a := newTestCylinder(t, 1, 0, 1)
+	b := newTestBox(t, meshx.NewVector(0.5, -0.25, 0.25), meshx.NewVector(1.5, 0.3, 0.75))
+	var volumes [3]float64
+
+	for operation := range volumes {
+		result, err := Boolean(a, b, BooleanOperation(operation), 1e-9)
+		assert.Empty(t, err)
+		assert.True(t, result.IsClosed())
+		assert.True(t, result.IsConsistent())
+
+		properties, err := result.ComputeMassProperties(1)
+		assert.Empty(t, err)
+		volumes[operation] = properties.Volume
+	}
+
+	propertiesA, _ := a.ComputeMassProperties(1)
+	propertiesB, _ := b.ComputeMassProperties(1)
+	assert.InDelta(t, propertiesA.Volume+propertiesB.Volume, volumes[0]+volumes[1], 1e-9)
+	assert.InDelta(t, propertiesA.Volume, volumes[1]+volumes[2], 1e-9)
+
+	// Disjoint boxes have no intersection and are unchanged.
+	a = newTestBox(t, meshx.NewVector(0, 0, 0), meshx.NewVector(1, 1, 1))
+	b = newTestBox(t, meshx.NewVector(2, 0, 0), meshx.NewVector(3, 1, 1))
+	result, err := Boolean(a, b, BooleanIntersection, 1e-9)
+	assert.Empty(t, err)
+	assert.Equal(t, 0, result.GetNumberOfFaces())
+	assert.Equal(t, 6, a.GetNumberOfFaces())
+
+	_, err = Boolean(a, b, BooleanOperation(3), 1e-9)
+	assert.ErrorIs(t, err, ErrInvalidBooleanOperation)
+
+	_, err = Boolean(a, newTestTriangleGrid(t, 2), BooleanUnion, 1e-9)
+	assert.ErrorIs(t, err, ErrOpenSurface)
+}
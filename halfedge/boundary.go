@@ -0,0 +1,167 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/planar"
+)
+
+// Get the open boundary loops. Each loop is the ordered list of boundary
+// half edges (half edges without a twin).
+func (m *HalfEdgeMesh) GetBoundaryLoops() [][]int {
+	m.ensureAdjacency()
+
+	loops := make([][]int, 0)
+	visited := make([]bool, m.GetNumberOfHalfEdges())
+
+	for i, halfEdge := range m.halfEdges {
+		if !halfEdge.IsBoundary() || visited[i] {
+			continue
+		}
+
+		loop := make([]int, 0)
+
+		for current := i; !visited[current]; current = m.getNextBoundaryHalfEdge(current) {
+			visited[current] = true
+			loop = append(loop, current)
+		}
+
+		loops = append(loops, loop)
+	}
+
+	return loops
+}
+
+// Get the boundary half edge originating at the end of a boundary half edge.
+func (m *HalfEdgeMesh) getNextBoundaryHalfEdge(index int) int {
+	next := m.halfEdges[index].Next
+
+	for !m.halfEdges[next].IsBoundary() {
+		next = m.halfEdges[m.halfEdges[next].Twin].Next
+	}
+
+	return next
+}
+
+// Options to fill the open boundary loops. The zero value fills every loop
+// with a fan about its centroid.
+type FillHolesOptions struct {
+	// Maximum number of edges of a filled loop (zero for no limit).
+	MaxEdges int
+
+	// Fill each loop with the constrained Delaunay triangulation of the loop
+	// projected onto its plane (no vertices are added). A loop that does not
+	// project to a simple polygon is filled with a fan.
+	Triangulate bool
+}
+
+// Fill the open boundary loops (in place) with at most the maximum number of
+// edges (zero fills every loop). See FillHolesWithOptions.
+func (m *HalfEdgeMesh) FillHoles(maxEdges int) (int, error) {
+	return m.FillHolesWithOptions(FillHolesOptions{MaxEdges: maxEdges})
+}
+
+// Fill the open boundary loops (in place). A loop of three edges is filled
+// with a single triangle and larger loops with a fan of triangles about the
+// centroid of the loop (or triangulated). Each fill face is assigned to the
+// patch of the face adjacent to its boundary edge. The number of filled
+// loops is returned.
+func (m *HalfEdgeMesh) FillHolesWithOptions(options FillHolesOptions) (int, error) {
+	m.ensureAdjacency()
+
+	source := newMeshSource(m)
+	var count int
+
+	for _, loop := range m.GetBoundaryLoops() {
+		if options.MaxEdges > 0 && len(loop) > options.MaxEdges {
+			continue
+		}
+
+		if len(loop) == 3 {
+			face := make([]int, 3)
+
+			for i, id := range loop {
+				face[2-i] = m.halfEdges[id].Origin
+			}
+
+			source.addFace(face, m.faces[m.halfEdges[loop[0]].Face])
+		} else if !options.Triangulate || !m.triangulateLoop(loop, source) {
+			var centroid meshx.Vector
+			var color [4]float64
+
+			for _, id := range loop {
+				vertex := m.vertices[m.halfEdges[id].Origin]
+				centroid = centroid.Add(vertex.Point)
+
+				for j, value := range vertex.Color.Floats() {
+					color[j] += value / float64(len(loop))
+				}
+			}
+
+			center := len(source.vertices)
+			source.vertices = append(source.vertices, centroid.DivScalar(float64(len(loop))))
+			source.vertexColors = append(source.vertexColors, meshx.NewColorFromFloats(color[0], color[1], color[2], color[3]))
+
+			for _, id := range loop {
+				p := m.halfEdges[id].Origin
+				q := m.halfEdges[m.halfEdges[id].Next].Origin
+				source.addFace([]int{q, p, center}, m.faces[m.halfEdges[id].Face])
+			}
+		}
+
+		count++
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+
+	return count, m.rebuild(source)
+}
+
+// Fill a boundary loop with the constrained Delaunay triangulation of the
+// loop projected onto its plane. Each triangle is assigned to the patch of
+// the face adjacent to one of its boundary edges (or to the first edge). The
+// boolean is false if the projected loop is not a simple polygon.
+func (m *HalfEdgeMesh) triangulateLoop(loop []int, source *meshSource) bool {
+	points := make([]meshx.Vector, len(loop))
+	indices := make([]int, len(loop))
+
+	for i, id := range loop {
+		points[i] = m.vertices[m.halfEdges[id].Origin].Point
+		indices[i] = i
+	}
+
+	normal := planar.ComputeLoopNormal(points)
+
+	if normal.Mag() == 0 {
+		return false
+	}
+
+	projected := planar.ProjectToPlane(points, normal)
+	triangles, err := planar.TriangulatePolygon(projected, [][]int{indices})
+
+	if err != nil || len(triangles) != len(loop)-2 {
+		return false
+	}
+
+	for _, triangle := range triangles {
+		id := loop[0]
+
+		for i := range 3 {
+			if next := triangle[(i+1)%3]; next == (triangle[i]+1)%len(loop) {
+				id = loop[triangle[i]]
+			}
+		}
+
+		// The loop runs opposite the orientation of the fill faces.
+		face := []int{
+			m.halfEdges[loop[triangle[2]]].Origin,
+			m.halfEdges[loop[triangle[1]]].Origin,
+			m.halfEdges[loop[triangle[0]]].Origin,
+		}
+
+		source.addFace(face, m.faces[m.halfEdges[id].Face])
+	}
+
+	return true
+}
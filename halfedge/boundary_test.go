@@ -0,0 +1,83 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test filling the hole left by removing a patch of a closed mesh.
+func TestFillHoles(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh.Orient()
+	mesh = mesh.ExtractPatches([]int{0, 1, 2, 3, 5})
+	assert.False(t, mesh.IsClosed())
+
+	count, err := mesh.FillHoles(3)
+	assert.Empty(t, err)
+	assert.Equal(t, 0, count)
+	assert.False(t, mesh.IsClosed())
+
+	count, err = mesh.FillHoles(0)
+	assert.Empty(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, mesh.IsClosed())
+	assert.True(t, mesh.IsConsistent())
+	assert.Equal(t, 9, mesh.GetNumberOfVertices())
+	assert.Equal(t, 10, mesh.GetNumberOfFaces())
+	assert.Equal(t, meshx.NewVector(0.5, 0.5, 1), mesh.GetVertex(8).Point)
+}
+
+// Test filling a hole by triangulating its loop without adding vertices.
+func TestFillHolesWithOptions(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh.Orient()
+	mesh = mesh.ExtractPatches([]int{0, 1, 2, 3, 5})
+	nFaces := mesh.GetNumberOfFaces()
+
+	count, err := mesh.FillHolesWithOptions(FillHolesOptions{Triangulate: true})
+	assert.Empty(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, mesh.IsClosed())
+	assert.True(t, mesh.IsConsistent())
+	assert.Equal(t, 8, mesh.GetNumberOfVertices())
+	assert.Equal(t, nFaces+2, mesh.GetNumberOfFaces())
+
+	for i := nFaces; i < mesh.GetNumberOfFaces(); i++ {
+		assert.Equal(t, meshx.NewVector(0, 0, 1), mesh.GetFaceNormal(i).Unit())
+	}
+}
+
+// Test filling a triangular hole with a single face.
+func TestFillHolesTriangle(t *testing.T) {
+	source := meshSource{
+		vertices: []meshx.Vector{
+			meshx.NewVector(0, 0, 0),
+			meshx.NewVector(1, 0, 0),
+			meshx.NewVector(0, 1, 0),
+			meshx.NewVector(0, 0, 1),
+		},
+		vertexColors:    make([]meshx.Color, 4),
+		faces:           [][]int{{0, 2, 1}, {0, 1, 3}, {1, 2, 3}},
+		facePatches:     []int{-1, -1, -1},
+		faceMaterials:   []int{-1, -1, -1},
+		faceColors:      make([]meshx.Color, 3),
+		smoothingGroups: make([]int, 3),
+	}
+
+	mesh, err := NewHalfEdgeMesh(&source)
+	assert.Empty(t, err)
+
+	count, err := mesh.FillHoles(0)
+	assert.Empty(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 4, mesh.GetNumberOfVertices())
+	assert.Equal(t, 4, mesh.GetNumberOfFaces())
+	assert.True(t, mesh.IsClosed())
+	assert.True(t, mesh.IsConsistent())
+}
@@ -0,0 +1,77 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Differences between two meshes.
+type Comparison struct {
+	// Maximum (symmetric) distance between the sampled surfaces, infinite if
+	// exactly one of the meshes has no faces.
+	HausdorffDistance float64
+
+	// Mean distance between the sampled surfaces weighted by area.
+	MeanDeviation float64
+
+	// Differences in the number of elements (b - a).
+	VertexDelta int
+	FaceDelta   int
+
+	// Patches (by name) only in b, only in a, and in both with a different
+	// number of faces.
+	AddedPatches   []string
+	RemovedPatches []string
+	ChangedPatches []string
+}
+
+// Compare two meshes. The surface deviation is sampled at the vertices and
+// centroids of the face triangles of each mesh relative to the other.
+func Compare(a, b *HalfEdgeMesh) Comparison {
+	comparison := Comparison{
+		VertexDelta:    b.GetNumberOfVertices() - a.GetNumberOfVertices(),
+		FaceDelta:      b.GetNumberOfFaces() - a.GetNumberOfFaces(),
+		AddedPatches:   make([]string, 0),
+		RemovedPatches: make([]string, 0),
+		ChangedPatches: make([]string, 0),
+	}
+
+	deviation := spatial.HausdorffDistance(a.GetTriangles(), b.GetTriangles(), spatial.HausdorffOptions{})
+	comparison.HausdorffDistance = deviation.Max
+	comparison.MeanDeviation = deviation.Mean
+
+	patchesA := a.getPatchFaceCounts()
+	patchesB := b.getPatchFaceCounts()
+
+	for _, patch := range a.patches {
+		if _, ok := patchesB[patch.Name]; !ok {
+			comparison.RemovedPatches = append(comparison.RemovedPatches, patch.Name)
+		} else if patchesA[patch.Name] != patchesB[patch.Name] {
+			comparison.ChangedPatches = append(comparison.ChangedPatches, patch.Name)
+		}
+	}
+
+	for _, patch := range b.patches {
+		if _, ok := patchesA[patch.Name]; !ok {
+			comparison.AddedPatches = append(comparison.AddedPatches, patch.Name)
+		}
+	}
+
+	return comparison
+}
+
+// Get the number of faces of each patch by name.
+func (m *HalfEdgeMesh) getPatchFaceCounts() map[string]int {
+	counts := make(map[string]int)
+
+	for _, patch := range m.patches {
+		counts[patch.Name] = 0
+	}
+
+	for _, face := range m.faces {
+		if face.Patch != -1 {
+			counts[m.patches[face.Patch].Name]++
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,69 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test comparing a mesh with itself.
+func TestCompareIdentical(t *testing.T) {
+	a, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	comparison := Compare(a, a)
+	assert.InDelta(t, 0.0, comparison.HausdorffDistance, 1e-12)
+	assert.InDelta(t, 0.0, comparison.MeanDeviation, 1e-12)
+	assert.Equal(t, 0, comparison.VertexDelta)
+	assert.Empty(t, comparison.ChangedPatches)
+}
+
+// Test comparing a mesh with a translated copy.
+func TestCompareTranslated(t *testing.T) {
+	a, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	b, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+	b.Translate(meshx.NewVector(0.1, 0, 0))
+
+	comparison := Compare(a, b)
+	assert.InDelta(t, 0.1, comparison.HausdorffDistance, 1e-12)
+	assert.Greater(t, comparison.MeanDeviation, 0.0)
+	assert.Less(t, comparison.MeanDeviation, 0.1)
+}
+
+// Test comparing a mesh with an empty mesh.
+func TestCompareEmpty(t *testing.T) {
+	a, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	b := a.Extract([]int{})
+	assert.Equal(t, 0, b.GetNumberOfFaces())
+
+	for _, comparison := range []Comparison{Compare(a, b), Compare(b, a)} {
+		assert.True(t, math.IsInf(comparison.HausdorffDistance, 1))
+		assert.True(t, math.IsInf(comparison.MeanDeviation, 1))
+	}
+
+	comparison := Compare(b, b)
+	assert.Equal(t, 0.0, comparison.HausdorffDistance)
+}
+
+// Test comparing the patches of two meshes.
+func TestComparePatches(t *testing.T) {
+	a, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	b := a.ExtractPatches([]int{0, 2, 3, 4, 5})
+	b.Merge(a.ExtractPatches([]int{1}).Extract([]int{0}))
+
+	comparison := Compare(a, b)
+	assert.Equal(t, 3, comparison.VertexDelta)
+	assert.Equal(t, -1, comparison.FaceDelta)
+	assert.Empty(t, comparison.AddedPatches)
+	assert.Empty(t, comparison.RemovedPatches)
+	assert.Equal(t, []string{"back"}, comparison.ChangedPatches)
+}
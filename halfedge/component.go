@@ -0,0 +1,213 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+)
+
+// Isolated component (connected set of faces) of a HalfEdgeMesh.
+type Component struct {
+	Faces    []int
+	Area     float64
+	IsClosed bool
+	AABB     meshx.AABB
+}
+
+// Get the number of faces.
+func (c Component) GetNumberOfFaces() int {
+	return len(c.Faces)
+}
+
+// Get the isolated components with their statistics.
+func (m *HalfEdgeMesh) Components() []Component {
+	faceComponents := m.GetComponents()
+	components := make([]Component, len(faceComponents))
+
+	for i, faces := range faceComponents {
+		components[i] = m.newComponent(faces)
+	}
+
+	return components
+}
+
+// Compute the statistics of a component from its faces.
+func (m *HalfEdgeMesh) newComponent(faces []int) Component {
+	component := Component{
+		Faces:    faces,
+		IsClosed: true,
+	}
+
+	points := make([]meshx.Vector, 0, len(faces)*3)
+
+	for _, face := range faces {
+		component.Area += m.GetFaceArea(face)
+
+		for _, id := range m.GetFaceHalfEdges(face) {
+			halfEdge := m.GetHalfEdge(id)
+			points = append(points, m.vertices[halfEdge.Origin].Point)
+
+			if halfEdge.IsBoundary() {
+				component.IsClosed = false
+			}
+		}
+	}
+
+	component.AABB = meshx.NewAABBFromVectors(points)
+
+	return component
+}
+
+// Remove the components with fewer than the minimum number of faces (in place).
+func (m *HalfEdgeMesh) RemoveSmallComponents(minFaces int) {
+	m.ensureAdjacency()
+
+	faces := make([]int, 0, m.GetNumberOfFaces())
+
+	for _, component := range m.GetComponents() {
+		if len(component) >= minFaces {
+			faces = append(faces, component...)
+		}
+	}
+
+	if len(faces) != m.GetNumberOfFaces() {
+		m.replace(m.Extract(faces))
+	}
+}
+
+// Remove all but the component with the most faces (in place).
+func (m *HalfEdgeMesh) KeepLargestComponent() {
+	m.ensureAdjacency()
+
+	var largest []int
+
+	for _, component := range m.GetComponents() {
+		if len(component) > len(largest) {
+			largest = component
+		}
+	}
+
+	if len(largest) != m.GetNumberOfFaces() {
+		m.replace(m.Extract(largest))
+	}
+}
+
+// Get the components (faces) entirely enclosed by another closed component
+// (e.g. the internals of an engine inside the outer surface of a vehicle).
+// A component is enclosed if all of its vertices are inside the closed
+// component, so a component crossing or touching the enclosing surface is
+// kept. Baffles are not considered enclosing surfaces.
+func (m *HalfEdgeMesh) GetInternalComponents() [][]int {
+	m.ensureAdjacency()
+
+	faceComponents := m.GetComponents()
+	components := make([]Component, len(faceComponents))
+	points := make([][]meshx.Vector, len(faceComponents))
+
+	for i, faces := range faceComponents {
+		components[i] = m.newComponent(faces)
+		vertices := make(map[int]bool)
+
+		for _, face := range faces {
+			for _, vertex := range m.GetFaceVertices(face) {
+				if !vertices[vertex] {
+					vertices[vertex] = true
+					points[i] = append(points[i], m.vertices[vertex].Point)
+				}
+			}
+		}
+	}
+
+	internal := make([]bool, len(components))
+
+	for i, enclosing := range components {
+		if !enclosing.IsClosed || m.isBaffleComponent(enclosing.Faces) {
+			continue
+		}
+
+		var mesh *HalfEdgeMesh
+
+		for j, component := range components {
+			if i == j || internal[j] || !isAABBInside(component.AABB, enclosing.AABB) {
+				continue
+			}
+
+			if mesh == nil {
+				mesh = m.Extract(enclosing.Faces)
+			}
+
+			isInside := true
+
+			for _, classification := range mesh.ClassifyPoints(points[j]) {
+				if classification != ClassificationInside {
+					isInside = false
+					break
+				}
+			}
+
+			internal[j] = isInside
+		}
+	}
+
+	internalComponents := make([][]int, 0)
+
+	for i, isInternal := range internal {
+		if isInternal {
+			internalComponents = append(internalComponents, faceComponents[i])
+		}
+	}
+
+	return internalComponents
+}
+
+// Remove the components entirely enclosed by another closed component (in
+// place). See GetInternalComponents. The number of components removed is
+// returned.
+func (m *HalfEdgeMesh) RemoveInternalComponents() int {
+	internal := m.GetInternalComponents()
+
+	if len(internal) == 0 {
+		return 0
+	}
+
+	isInternal := make([]bool, m.GetNumberOfFaces())
+
+	for _, component := range internal {
+		for _, face := range component {
+			isInternal[face] = true
+		}
+	}
+
+	faces := make([]int, 0, m.GetNumberOfFaces())
+
+	for i, remove := range isInternal {
+		if !remove {
+			faces = append(faces, i)
+		}
+	}
+
+	m.replace(m.Extract(faces))
+	return len(internal)
+}
+
+// Return true if all faces of a component are baffles.
+func (m *HalfEdgeMesh) isBaffleComponent(faces []int) bool {
+	for _, face := range faces {
+		if !m.IsBaffleFace(face) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return true if an AABB is inside (or on the boundary of) another AABB.
+func isAABBInside(inner, outer meshx.AABB) bool {
+	innerMin, innerMax := inner.GetMinBound(), inner.GetMaxBound()
+	outerMin, outerMax := outer.GetMinBound(), outer.GetMaxBound()
+
+	for i := 0; i < 3; i++ {
+		if innerMin[i] < outerMin[i] || innerMax[i] > outerMax[i] {
+			return false
+		}
+	}
+
+	return true
+}
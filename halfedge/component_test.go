@@ -0,0 +1,153 @@
+package halfedge
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the components of a mesh with disconnected faces.
+func TestComponents(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	components := mesh.Components()
+	assert.Equal(t, 6, len(components))
+
+	for _, component := range components {
+		assert.Equal(t, 2, component.GetNumberOfFaces())
+		assert.InDelta(t, 1.0, component.Area, 1e-12)
+		assert.False(t, component.IsClosed)
+	}
+}
+
+// Test the components of a closed mesh.
+func TestComponentsClosed(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	components := mesh.Components()
+	assert.Equal(t, 1, len(components))
+	assert.True(t, components[0].IsClosed)
+	assert.InDelta(t, 6.0, components[0].Area, 1e-12)
+	assert.Equal(t, 0.5, components[0].AABB.Center[0])
+}
+
+// Revolve a profile into a closed consistently oriented cylinder.
+func newTestRevolve(t *testing.T) *HalfEdgeMesh {
+	profile := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(1, 0, 1),
+		meshx.NewVector(0, 0, 1),
+	}
+
+	mesh, err := NewRevolve(profile, meshx.Vector{}, meshx.NewVector(0, 0, 1), 16)
+	assert.Empty(t, err)
+	assert.True(t, mesh.IsConsistent())
+
+	return mesh
+}
+
+// Test flipping the orientation of a face.
+func TestFlipFace(t *testing.T) {
+	mesh := newTestRevolve(t)
+	halfEdges := append([]HalfEdge(nil), mesh.halfEdges...)
+
+	// The half edges of the flipped face start at the origins of their twins.
+	mesh.flipFace(0)
+
+	for _, id := range mesh.GetFaceHalfEdges(0) {
+		halfEdge := mesh.GetHalfEdge(id)
+		assert.Equal(t, id, mesh.GetHalfEdge(halfEdge.Next).Prev)
+		assert.Equal(t, mesh.GetHalfEdge(halfEdge.Twin).Origin, halfEdge.Origin)
+	}
+
+	mesh.flipFace(0)
+	assert.Equal(t, halfEdges, mesh.halfEdges)
+}
+
+// Test orienting a closed mesh with faces flipped in several places.
+func TestOrient(t *testing.T) {
+	mesh := newTestRevolve(t)
+
+	for i := 0; i < mesh.GetNumberOfFaces(); i += 3 {
+		mesh.flipFace(i)
+	}
+
+	assert.False(t, mesh.IsConsistent())
+
+	mesh.Orient()
+	assert.True(t, mesh.IsConsistent())
+
+	point := meshx.NewVector(0, 0, 0.5)
+	assert.InDelta(t, 1.0, math.Abs(meshx.WindingNumber(mesh.GetTriangles(), point)), 1e-9)
+
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+	assert.False(t, mesh.IsConsistent())
+
+	mesh.Orient()
+	assert.True(t, mesh.IsConsistent())
+}
+
+// Test keeping the largest component.
+func TestKeepLargestComponent(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	mesh.KeepLargestComponent()
+	assert.Equal(t, 2, mesh.GetNumberOfFaces())
+	assert.Equal(t, 4, mesh.GetNumberOfVertices())
+	assert.Equal(t, 6, mesh.GetNumberOfHalfEdges())
+}
+
+// Test removing the small components.
+func TestRemoveSmallComponents(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	mesh.RemoveSmallComponents(2)
+	assert.Equal(t, 12, mesh.GetNumberOfFaces())
+
+	mesh.RemoveSmallComponents(3)
+	assert.Equal(t, 0, mesh.GetNumberOfFaces())
+}
+
+// Test the components enclosed by a closed component.
+func TestGetInternalComponents(t *testing.T) {
+	mesh := newTestNestedCubes(t)
+
+	// A cube crossing the outer cube is not internal.
+	crossing := newTestCube(t)
+	crossing.Transform(meshx.NewTranslationMatrix4(meshx.NewVector(0.9, 0.1, 0.1)).Mul(meshx.NewScaleMatrix4(meshx.NewVector(0.2, 0.2, 0.2))))
+	mesh.Merge(crossing)
+
+	// An open component is internal.
+	inner := newTestCube(t)
+	inner.Transform(meshx.NewTranslationMatrix4(meshx.NewVector(0.1, 0.1, 0.1)).Mul(meshx.NewScaleMatrix4(meshx.NewVector(0.2, 0.2, 0.2))))
+	mesh.Merge(inner.Extract([]int{0, 2}))
+
+	assert.Equal(t, [][]int{{6, 7, 8, 9, 10, 11}, {18, 19}}, sortComponents(mesh.GetInternalComponents()))
+}
+
+// Test removing the components enclosed by a closed component.
+func TestRemoveInternalComponents(t *testing.T) {
+	mesh := newTestNestedCubes(t)
+
+	assert.Equal(t, 1, mesh.RemoveInternalComponents())
+	assert.Equal(t, 6, mesh.GetNumberOfFaces())
+	assert.Equal(t, 8, mesh.GetNumberOfVertices())
+	assert.Equal(t, 0, mesh.RemoveInternalComponents())
+}
+
+// Sort the faces of each component.
+func sortComponents(components [][]int) [][]int {
+	for _, component := range components {
+		slices.Sort(component)
+	}
+	return components
+}
@@ -0,0 +1,234 @@
+package halfedge
+
+import (
+	"math"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Minimum area of the overlap of two faces in contact relative to the
+// smaller of their triangles. Smaller overlaps (e.g. of faces only touching
+// at an edge) are not in contact regardless of the tolerance.
+const ContactMinOverlap = 1e-6
+
+// Matched regions of two meshes within a distance of each other (e.g. an
+// interface of two parts to merge or label as a coupled boundary).
+type ContactPatch struct {
+	// Faces of each mesh in the patch (sorted).
+	FacesA []int
+	FacesB []int
+}
+
+// Find the contact patches of two meshes: the faces of each overlapping a
+// face of the other within the tolerance, grouped into the connected regions
+// of both meshes in contact with each other. Faces only touching the other
+// mesh at an edge (e.g. the sides of stacked boxes) are not in contact.
+func FindContactPatches(a, b *HalfEdgeMesh, tolerance float64) []ContactPatch {
+	n := a.GetNumberOfFaces()
+
+	if n == 0 || b.GetNumberOfFaces() == 0 {
+		return []ContactPatch{}
+	}
+
+	a.ensureAdjacency()
+	b.ensureAdjacency()
+
+	pairs := getContactPairs(a, b, tolerance)
+
+	patches := make([]ContactPatch, 0)
+	visited := make(map[int]bool)
+
+	for i := range n {
+		if visited[i] || len(pairs[i]) == 0 {
+			continue
+		}
+
+		patch := ContactPatch{FacesA: make([]int, 0), FacesB: make([]int, 0)}
+		queue := []int{i}
+		visited[i] = true
+
+		for len(queue) > 0 {
+			var current int
+			current, queue = queue[0], queue[1:]
+			neighbors := slices.Clone(pairs[current])
+
+			if current < n {
+				patch.FacesA = append(patch.FacesA, current)
+				neighbors = append(neighbors, a.GetFaceNeighbors(current)...)
+			} else {
+				patch.FacesB = append(patch.FacesB, current-n)
+
+				for _, neighbor := range b.GetFaceNeighbors(current - n) {
+					neighbors = append(neighbors, n+neighbor)
+				}
+			}
+
+			for _, neighbor := range neighbors {
+				if !visited[neighbor] && len(pairs[neighbor]) != 0 {
+					visited[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+
+		slices.Sort(patch.FacesA)
+		slices.Sort(patch.FacesB)
+		patches = append(patches, patch)
+	}
+
+	return patches
+}
+
+// Get the faces in contact of two meshes (see FindContactPatches) with the
+// faces of the other mesh each is in contact with, where the faces of b
+// follow those of a.
+func getContactPairs(a, b *HalfEdgeMesh, tolerance float64) map[int][]int {
+	n := a.GetNumberOfFaces()
+	octree := b.BuildOctree()
+	pairs := make(map[int][]int)
+	halfSize := meshx.NewVector(tolerance, tolerance, tolerance)
+
+	for i := range n {
+		triangles := a.GetFaceTriangles(i)
+		aabb := meshx.NewAABBFromVectors(a.getFacePoints(i))
+		aabb.HalfSize = aabb.HalfSize.Add(halfSize)
+
+		for _, j := range octree.Query(aabb) {
+			if isFaceOverlap(triangles, b.GetFaceTriangles(j), tolerance) {
+				pairs[i] = append(pairs[i], n+j)
+				pairs[n+j] = append(pairs[n+j], i)
+			}
+		}
+	}
+
+	return pairs
+}
+
+// Return true if two faces (as triangles) overlap within the tolerance.
+func isFaceOverlap(a, b []meshx.Triangle, tolerance float64) bool {
+	for _, p := range getSolidTriangles(a) {
+		for _, q := range getSolidTriangles(b) {
+			if _, ok := getTriangleOverlap(p, q, tolerance); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Get the overlap of a triangle with another triangle projected onto its
+// plane. The overlap is a convex polygon and is only valid if its area is
+// at least ContactMinOverlap of the smaller triangle and it is within the
+// tolerance of the other triangle.
+func getTriangleOverlap(triangle, other meshx.Triangle, tolerance float64) ([]meshx.Vector, bool) {
+	overlap := []meshx.Vector{triangle.P, triangle.Q, triangle.R}
+
+	for _, plane := range getProjectedEdgePlanes(other, triangle.P, triangle.UnitNormal()) {
+		overlap = clipPolygon(overlap, plane[0], plane[1])
+	}
+
+	if len(overlap) < 3 || getPolygonNormal(overlap).Mag()/2 <= ContactMinOverlap*min(triangle.Area(), other.Area()) {
+		return nil, false
+	}
+
+	for _, point := range overlap {
+		if other.DistanceTo(point) > tolerance {
+			return nil, false
+		}
+	}
+
+	return overlap, true
+}
+
+// Get the triangles that are not degenerate (e.g. the triangles of a face
+// with vertices along its edges).
+func getSolidTriangles(triangles []meshx.Triangle) []meshx.Triangle {
+	return slices.DeleteFunc(triangles, func(triangle meshx.Triangle) bool {
+		return isDegenerate([]meshx.Vector{triangle.P, triangle.Q, triangle.R})
+	})
+}
+
+// Get the planes (origin and outward normal) of the edges of a triangle
+// projected onto the plane through a point with a unit normal.
+func getProjectedEdgePlanes(triangle meshx.Triangle, origin, normal meshx.Vector) [3][2]meshx.Vector {
+	var planes [3][2]meshx.Vector
+
+	points := []meshx.Vector{triangle.P, triangle.Q, triangle.R}
+
+	for k, point := range points {
+		points[k] = point.Sub(normal.MulScalar(point.Sub(origin).Dot(normal)))
+	}
+
+	centroid := points[0].Add(points[1]).Add(points[2]).DivScalar(3)
+
+	for k := range points {
+		p, q := points[k], points[(k+1)%3]
+		outward := q.Sub(p).Cross(normal)
+
+		if outward.Dot(centroid.Sub(p)) > 0 {
+			outward = outward.MulScalar(-1)
+		}
+
+		planes[k] = [2]meshx.Vector{p, outward}
+	}
+
+	return planes
+}
+
+// Clip a convex polygon to the half space behind a plane (origin and
+// normal) with the Sutherland-Hodgman algorithm.
+func clipPolygon(polygon []meshx.Vector, origin, normal meshx.Vector) []meshx.Vector {
+	clipped := make([]meshx.Vector, 0, len(polygon)+1)
+
+	for k, p := range polygon {
+		q := polygon[(k+1)%len(polygon)]
+		dp := p.Sub(origin).Dot(normal)
+		dq := q.Sub(origin).Dot(normal)
+
+		if dp <= 0 {
+			clipped = append(clipped, p)
+		}
+
+		if (dp < 0 && dq > 0) || (dp > 0 && dq < 0) {
+			clipped = append(clipped, p.Add(q.Sub(p).MulScalar(dp/(dp-dq))))
+		}
+	}
+
+	return clipped
+}
+
+// Return true if a polygon is degenerate (its area is negligible relative to
+// the square of its perimeter).
+func isDegenerate(polygon []meshx.Vector) bool {
+	return len(polygon) < 3 || getPolygonNormal(polygon).Mag()/2 <= 1e-12*math.Pow(getPerimeter(polygon), 2)
+}
+
+// Return true if a polygon is degenerate or narrower than the tolerance
+// (its area is less than the tolerance times its perimeter).
+func isSliver(polygon []meshx.Vector, tolerance float64) bool {
+	return len(polygon) < 3 || getPolygonNormal(polygon).Mag()/2 <= tolerance*getPerimeter(polygon)
+}
+
+// Compute the perimeter of a polygon.
+func getPerimeter(polygon []meshx.Vector) float64 {
+	var perimeter float64
+
+	for k, p := range polygon {
+		perimeter += polygon[(k+1)%len(polygon)].Sub(p).Mag()
+	}
+
+	return perimeter
+}
+
+// Compute the normal of a polygon scaled by twice its area (Newell).
+func getPolygonNormal(polygon []meshx.Vector) meshx.Vector {
+	var normal meshx.Vector
+
+	for k, p := range polygon {
+		normal = normal.Add(p.Cross(polygon[(k+1)%len(polygon)]))
+	}
+
+	return normal
+}
@@ -0,0 +1,76 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the contact patches of a grid resting on a cube.
+func TestFindContactPatches(t *testing.T) {
+	a := newTestCube(t)
+	b := newTestTriangleGrid(t, 4)
+	b.Transform(meshx.NewTranslationMatrix4(meshx.NewVector(-0.5, -0.5, 1)).Mul(meshx.NewScaleMatrix4(meshx.NewVector(0.5, 0.5, 1))))
+
+	patches := FindContactPatches(a, b, 1e-6)
+	assert.Equal(t, 1, len(patches))
+
+	for _, face := range patches[0].FacesA {
+		assert.InDelta(t, 1.0, a.GetFaceNormal(face).Unit()[2], 1e-12)
+	}
+
+	// The faces of the grid over the top of the cube (x, y > 0).
+	assert.Equal(t, []int{1}, patches[0].FacesA)
+	assert.Equal(t, 8, len(patches[0].FacesB))
+
+	for _, face := range patches[0].FacesB {
+		centroid := b.GetFaceCentroid(face)
+		assert.True(t, centroid[0] > 0 && centroid[1] > 0)
+	}
+
+	b.Translate(meshx.NewVector(0, 0, 0.1))
+	assert.Empty(t, FindContactPatches(a, b, 1e-6))
+	assert.Equal(t, 1, len(FindContactPatches(a, b, 0.2)))
+}
+
+// Test the faces in contact only grow with the tolerance.
+func TestFindContactPatchesTolerance(t *testing.T) {
+	a := newTestCube(t)
+	b := newTestCube(t)
+	b.Translate(meshx.NewVector(0, 0, 1))
+
+	// Only the top of a and the bottom of b are in contact.
+	for _, tolerance := range []float64{1e-6, 0.1, 0.3, 0.9} {
+		patches := FindContactPatches(a, b, tolerance)
+		assert.Equal(t, 1, len(patches))
+		assert.Equal(t, []int{1}, patches[0].FacesA)
+		assert.Equal(t, []int{0}, patches[0].FacesB)
+	}
+
+	b = newTestTriangleGrid(t, 4)
+	b.Transform(meshx.NewTranslationMatrix4(meshx.NewVector(-0.5, -0.5, 1.1)).Mul(meshx.NewScaleMatrix4(meshx.NewVector(0.5, 0.5, 1))))
+	previous := make(map[int]bool)
+
+	for _, tolerance := range []float64{0.05, 0.1, 0.2, 0.5, 1, 2} {
+		current := make(map[int]bool)
+
+		for _, patch := range FindContactPatches(a, b, tolerance) {
+			for _, face := range patch.FacesA {
+				current[face] = true
+			}
+
+			for _, face := range patch.FacesB {
+				current[a.GetNumberOfFaces()+face] = true
+			}
+		}
+
+		for face := range previous {
+			assert.True(t, current[face])
+		}
+
+		previous = current
+	}
+
+	assert.NotEmpty(t, previous)
+}
@@ -0,0 +1,214 @@
+package halfedge
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Classification of a point relative to a closed mesh.
+type Classification int
+
+const (
+	ClassificationOutside Classification = iota
+	ClassificationInside
+	ClassificationOnSurface
+)
+
+// Relative tolerance (to the AABB diagonal) for a point on the surface.
+const ContainmentTolerance = 1e-9
+
+// Ray directions used for the parity vote. These are deliberately not
+// aligned with any axis to avoid grazing axis-aligned geometry.
+var containmentDirections = []meshx.Vector{
+	{0.5773502691896258, 0.5773502691896257, 0.5773502691896259},
+	{-0.2672612419124244, 0.8017837257372732, 0.5345224838248488},
+	{0.3244428422615251, -0.4866642633922876, 0.8111071056538127},
+}
+
+// Return true if the point is inside (or on the surface of) the mesh. The
+// mesh is assumed to be closed. Use ClassifyPoints for many points since the
+// spatial index is built on each call.
+func (m *HalfEdgeMesh) ContainsPoint(point meshx.Vector) bool {
+	classification := m.ClassifyPoints([]meshx.Vector{point})[0]
+	return classification != ClassificationOutside
+}
+
+// Options for classifying points relative to a closed mesh.
+type ContainmentOptions struct {
+	// Use exact predicates for the ray parity. Rays grazing an edge, vertex,
+	// or face plane are detected exactly and discarded rather than voting.
+	Robust bool
+}
+
+// Classify the points as inside, outside, or on the surface of the mesh. The
+// mesh is assumed to be closed. Baffles are ignored, so a point on a baffle
+// is classified by the surface enclosing it.
+func (m *HalfEdgeMesh) ClassifyPoints(points []meshx.Vector) []Classification {
+	return m.ClassifyPointsWithOptions(points, ContainmentOptions{})
+}
+
+// Classify the points as inside, outside, or on the surface of the mesh with
+// options. The mesh is assumed to be closed.
+func (m *HalfEdgeMesh) ClassifyPointsWithOptions(points []meshx.Vector, options ContainmentOptions) []Classification {
+	classifications := make([]Classification, len(points))
+	mesh := m.getEnclosingMesh()
+
+	if mesh.GetNumberOfFaces() == 0 {
+		return classifications
+	}
+
+	octree := mesh.BuildOctree()
+	aabb := mesh.GetAABB()
+	tolerance := ContainmentTolerance * aabb.HalfSize.Mag() * 2
+
+	for i, point := range points {
+		if options.Robust {
+			classifications[i] = classifyPointRobust(octree, aabb, point, tolerance)
+		} else {
+			classifications[i] = classifyPoint(octree, aabb, point, tolerance)
+		}
+	}
+
+	return classifications
+}
+
+// Classify a point against an octree of the faces of a closed mesh.
+func classifyPoint(octree *spatial.Octree, aabb meshx.AABB, point meshx.Vector, tolerance float64) Classification {
+	halfSize := meshx.NewVector(tolerance, tolerance, tolerance)
+
+	if !point.IntersectsAABB(meshx.NewAABB(aabb.Center, aabb.HalfSize.Add(halfSize))) {
+		return ClassificationOutside
+	}
+
+	if len(octree.Query(meshx.NewAABB(point, halfSize))) > 0 {
+		return ClassificationOnSurface
+	}
+
+	var votes int
+
+	length := 4 * (aabb.HalfSize.Mag() + point.Sub(aabb.Center).Mag())
+
+	for _, direction := range containmentDirections {
+		var crossings int
+
+		ray := meshx.NewRay(point, direction)
+		segment := meshx.NewSegment(point, point.Add(direction.MulScalar(length)))
+
+		for _, index := range octree.Query(segment) {
+			for _, triangle := range octree.GetItem(index).(faceItem).triangles {
+				if ray.IntersectsTriangleWatertight(triangle) {
+					crossings++
+				}
+			}
+		}
+
+		if crossings%2 == 1 {
+			votes++
+		}
+	}
+
+	if 2*votes > len(containmentDirections) {
+		return ClassificationInside
+	}
+
+	return ClassificationOutside
+}
+
+// Classify a point against an octree of the faces of a closed mesh using
+// exact predicates. The parity of the first segment (ray) without degenerate
+// crossings is used. If every segment is degenerate, the approximate
+// classification is used.
+func classifyPointRobust(octree *spatial.Octree, aabb meshx.AABB, point meshx.Vector, tolerance float64) Classification {
+	halfSize := meshx.NewVector(tolerance, tolerance, tolerance)
+
+	if !point.IntersectsAABB(meshx.NewAABB(aabb.Center, aabb.HalfSize.Add(halfSize))) {
+		return ClassificationOutside
+	}
+
+	if len(octree.Query(meshx.NewAABB(point, halfSize))) > 0 {
+		return ClassificationOnSurface
+	}
+
+	length := 4 * (aabb.HalfSize.Mag() + point.Sub(aabb.Center).Mag())
+
+	for _, direction := range robustContainmentDirections {
+		var crossings int
+		var degenerate bool
+
+		segment := meshx.NewSegment(point, point.Add(direction.MulScalar(length)))
+
+		for _, index := range octree.Query(segment) {
+			for _, triangle := range octree.GetItem(index).(faceItem).triangles {
+				crosses, touches := segment.CrossesTriangle(triangle)
+
+				if crosses {
+					crossings++
+				}
+
+				degenerate = degenerate || touches
+			}
+		}
+
+		if !degenerate {
+			if crossings%2 == 1 {
+				return ClassificationInside
+			}
+			return ClassificationOutside
+		}
+	}
+
+	return classifyPoint(octree, aabb, point, tolerance)
+}
+
+// Ray directions used for the robust parity, distributed on the unit sphere
+// by a golden angle spiral.
+var robustContainmentDirections = func() []meshx.Vector {
+	const n = 16
+	directions := make([]meshx.Vector, n)
+	golden := math.Pi * (3 - math.Sqrt(5))
+
+	for i := range directions {
+		z := 1 - (2*float64(i)+1)/n
+		r := math.Sqrt(1 - z*z)
+		theta := golden * float64(i)
+		directions[i] = meshx.NewVector(r*math.Cos(theta), r*math.Sin(theta), z)
+	}
+
+	return directions
+}()
+
+// Compute the generalized winding numbers of the points using the fast
+// hierarchical approximation. Unlike the ray parity used by ClassifyPoints,
+// this is robust for open or self-intersecting meshes: points with a winding
+// number above one half may be considered inside. Baffles are ignored.
+func (m *HalfEdgeMesh) WindingNumbers(points []meshx.Vector) []float64 {
+	tree := spatial.NewWindingNumberTree(m.getEnclosingMesh().GetTriangles())
+	windingNumbers := make([]float64, len(points))
+
+	for i, point := range points {
+		windingNumbers[i] = tree.Evaluate(point)
+	}
+
+	return windingNumbers
+}
+
+// Get the mesh without the baffles (the mesh itself if it has none).
+func (m *HalfEdgeMesh) getEnclosingMesh() *HalfEdgeMesh {
+	baffles := m.getBaffleFaces()
+
+	if baffles == nil {
+		return m
+	}
+
+	faces := make([]int, 0, len(baffles))
+
+	for i, isBaffle := range baffles {
+		if !isBaffle {
+			faces = append(faces, i)
+		}
+	}
+
+	return m.Extract(faces)
+}
@@ -0,0 +1,118 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test classifying points against a closed mesh.
+func TestClassifyPoints(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	points := []meshx.Vector{
+		meshx.NewVector(0.5, 0.5, 0.5),
+		meshx.NewVector(0.1, 0.9, 0.2),
+		meshx.NewVector(1.5, 0.5, 0.5),
+		meshx.NewVector(-0.5, -0.5, -0.5),
+		meshx.NewVector(0.5, 0.5, 1),
+		meshx.NewVector(0, 0, 0),
+	}
+
+	expected := []Classification{
+		ClassificationInside,
+		ClassificationInside,
+		ClassificationOutside,
+		ClassificationOutside,
+		ClassificationOnSurface,
+		ClassificationOnSurface,
+	}
+
+	assert.Equal(t, expected, mesh.ClassifyPoints(points))
+}
+
+// Test classifying points against a closed mesh with a baffle.
+func TestClassifyPointsBaffles(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshWithOptions(newBaffleTestSource(), HalfEdgeMeshOptions{Baffles: []string{"baffle"}})
+	assert.Empty(t, err)
+
+	points := []meshx.Vector{
+		meshx.NewVector(0.5, 0.5, 0.5),
+		meshx.NewVector(1.5, 0.5, 0.5),
+		meshx.NewVector(1, 0.5, 0.5),
+		meshx.NewVector(2.5, 0.5, 0.5),
+	}
+
+	expected := []Classification{
+		ClassificationInside,
+		ClassificationInside,
+		ClassificationInside,
+		ClassificationOutside,
+	}
+
+	assert.Equal(t, expected, mesh.ClassifyPoints(points))
+
+	for i, windingNumber := range mesh.WindingNumbers(points[:2]) {
+		assert.InDelta(t, 1, windingNumber, 1e-6, i)
+	}
+}
+
+// Test a point inside a closed mesh.
+func TestContainsPoint(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	assert.True(t, mesh.ContainsPoint(meshx.NewVector(0.25, 0.75, 0.5)))
+	assert.False(t, mesh.ContainsPoint(meshx.NewVector(0.25, 1.75, 0.5)))
+}
+
+// Test classifying points with exact predicates, including points aligned
+// with the edges and vertices of the mesh.
+func TestClassifyPointsRobust(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	points := []meshx.Vector{
+		meshx.NewVector(0.5, 0.5, 0.5),
+		meshx.NewVector(0.25, 0.25, 0.25),
+		meshx.NewVector(1.5, 1.5, 1.5),
+		meshx.NewVector(-0.5, 0.5, 0.5),
+		meshx.NewVector(0.5, 0.5, 0),
+	}
+
+	expected := []Classification{
+		ClassificationInside,
+		ClassificationInside,
+		ClassificationOutside,
+		ClassificationOutside,
+		ClassificationOnSurface,
+	}
+
+	options := ContainmentOptions{Robust: true}
+	assert.Equal(t, expected, mesh.ClassifyPointsWithOptions(points, options))
+}
+
+// Test casting a ray through a closed mesh with entry and exit hits.
+func TestRaycastAll(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh.Orient()
+	assert.True(t, mesh.IsConsistent())
+
+	octree := mesh.BuildOctree()
+	ray := meshx.NewRay(meshx.NewVector(-1, 0.3, 0.6), meshx.NewVector(1, 0.1, -0.1))
+	hits := octree.RaycastAll(ray)
+
+	assert.Equal(t, 2, len(hits))
+	assert.InDelta(t, 1, hits[0].Distance, 1e-12)
+	assert.InDelta(t, 2, hits[1].Distance, 1e-12)
+	assert.NotEqual(t, hits[0].Entering, hits[1].Entering)
+	assert.InDelta(t, 1, math.Abs(hits[0].Normal[0]), 1e-12)
+	assert.InDelta(t, 0, hits[0].Normal.Add(hits[1].Normal).Mag(), 1e-12)
+	assert.InDelta(t, 0, hits[0].Point[0], 1e-12)
+	assert.InDelta(t, 1, hits[1].Point[0], 1e-12)
+}
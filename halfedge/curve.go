@@ -0,0 +1,60 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/curve"
+)
+
+// Get the vertex points of the mesh.
+func (m *HalfEdgeMesh) getVertexPoints() []meshx.Vector {
+	points := make([]meshx.Vector, len(m.vertices))
+
+	for i, vertex := range m.vertices {
+		points[i] = vertex.Point
+	}
+
+	return points
+}
+
+// Get the feature edges chained into curves. The vertices of the network
+// are the vertices of the mesh (by index).
+func (m *HalfEdgeMesh) GetFeatureCurves() *curve.CurveNetwork {
+	visited := make(map[[2]int]bool)
+	edges := make([][2]int, 0)
+
+	for _, halfEdge := range m.halfEdges {
+		if halfEdge.IsFeature {
+			next := m.halfEdges[halfEdge.Next]
+			edge := [2]int{halfEdge.Origin, next.Origin}
+			key := [2]int{min(edge[0], edge[1]), max(edge[0], edge[1])}
+
+			if !visited[key] {
+				visited[key] = true
+				edges = append(edges, edge)
+			}
+		}
+	}
+
+	network, _ := curve.NewCurveNetworkFromEdges(m.getVertexPoints(), edges)
+	return network
+}
+
+// Get the boundary loops as closed curves. The vertices of the network are
+// the vertices of the mesh (by index).
+func (m *HalfEdgeMesh) GetBoundaryCurves() *curve.CurveNetwork {
+	loops := m.GetBoundaryLoops()
+	polylines := make([][]int, len(loops))
+
+	for i, loop := range loops {
+		polylines[i] = make([]int, len(loop)+1)
+
+		for j, id := range loop {
+			polylines[i][j] = m.halfEdges[id].Origin
+		}
+
+		polylines[i][len(loop)] = polylines[i][0]
+	}
+
+	network, _ := curve.NewCurveNetwork(m.getVertexPoints(), polylines)
+	return network
+}
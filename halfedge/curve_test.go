@@ -0,0 +1,33 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the feature edges of a mesh as curves.
+func TestGetFeatureCurves(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh.Orient()
+	mesh.ComputeFeatureEdges(math.Pi / 6)
+	network := mesh.GetFeatureCurves()
+	assert.Equal(t, 12, network.GetNumberOfPolylines())
+	assert.InDelta(t, 12, network.Length(), 1e-12)
+}
+
+// Test the boundary loops of a mesh as curves.
+func TestGetBoundaryCurves(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+	assert.Equal(t, 0, mesh.GetBoundaryCurves().GetNumberOfPolylines())
+
+	patch := mesh.ExtractPatches([]int{0})
+	network := patch.GetBoundaryCurves()
+	assert.Equal(t, 1, network.GetNumberOfPolylines())
+	assert.True(t, network.GetPolyline(0).Closed)
+	assert.InDelta(t, 4, network.Length(), 1e-12)
+}
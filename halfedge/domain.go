@@ -0,0 +1,236 @@
+package halfedge
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	// Default padding of a far-field domain upstream (-x), downstream (+x)
+	// and in the other directions in multiples of the largest extent of the
+	// bounding box it encloses.
+	DomainUpstreamPadding   = 5
+	DomainDownstreamPadding = 10
+	DomainPadding           = 5
+
+	// Default number of segments of the circumference of a hemisphere.
+	DomainSegments = 32
+
+	// Default names of the patches of a far-field domain.
+	DomainInletPatch    = "inlet"
+	DomainOutletPatch   = "outlet"
+	DomainSymmetryPatch = "symmetry"
+)
+
+var (
+	ErrInvalidDomain = errors.New("invalid domain")
+)
+
+// Shape of a far-field domain.
+type DomainShape int
+
+const (
+	DomainBox DomainShape = iota
+	DomainHemisphere
+)
+
+// Options for constructing a far-field domain. Zero values use the defaults.
+type DomainOptions struct {
+	Shape DomainShape
+
+	// Padding in the -x, +x, -y, +y, -z and +z directions in multiples of the
+	// largest extent of the bounding box. The flow is in the +x direction.
+	Padding [6]float64
+
+	// Place the bottom of the domain at the bottom of the bounding box (e.g.
+	// a vehicle on the ground) rather than padding it.
+	Ground bool
+
+	// Number of segments of the circumference of a hemisphere.
+	Segments int
+
+	// Names of the patches.
+	InletName    string
+	OutletName   string
+	SymmetryName string
+}
+
+// Construct a far-field domain around a bounding box for external flow in
+// the +x direction. A box has an inlet (-x), an outlet (+x) and symmetry
+// planes (the other sides) and encloses the padded bounding box. A
+// hemisphere is centered on the bottom of the padded bounding box and
+// encloses it with the upstream half of its dome an inlet, the downstream
+// half an outlet and its base a symmetry plane. The symmetry patch has the
+// "symmetryPlane" type. The faces are oriented outward.
+func NewDomain(aabb meshx.AABB, options DomainOptions) (*HalfEdgeMesh, error) {
+	defaults := [6]float64{
+		DomainUpstreamPadding,
+		DomainDownstreamPadding,
+		DomainPadding,
+		DomainPadding,
+		DomainPadding,
+		DomainPadding,
+	}
+
+	for i, padding := range options.Padding {
+		if padding < 0 {
+			return nil, ErrInvalidDomain
+		}
+
+		if padding == 0 {
+			options.Padding[i] = defaults[i]
+		}
+	}
+
+	if options.Ground {
+		options.Padding[4] = 0
+	}
+
+	if options.Segments <= 0 {
+		options.Segments = DomainSegments
+	}
+
+	if options.Segments < 4 {
+		return nil, ErrInvalidDomain
+	}
+
+	if options.InletName == "" {
+		options.InletName = DomainInletPatch
+	}
+
+	if options.OutletName == "" {
+		options.OutletName = DomainOutletPatch
+	}
+
+	if options.SymmetryName == "" {
+		options.SymmetryName = DomainSymmetryPatch
+	}
+
+	length := 2 * max(aabb.HalfSize[0], aabb.HalfSize[1], aabb.HalfSize[2])
+
+	if length <= 0 {
+		return nil, ErrInvalidDomain
+	}
+
+	minBound := aabb.GetMinBound()
+	maxBound := aabb.GetMaxBound()
+
+	for i := range 3 {
+		minBound[i] -= options.Padding[2*i] * length
+		maxBound[i] += options.Padding[2*i+1] * length
+	}
+
+	source := meshSource{
+		vertices:        make([]meshx.Vector, 0),
+		vertexColors:    make([]meshx.Color, 0),
+		faces:           make([][]int, 0),
+		facePatches:     make([]int, 0),
+		patches:         []string{options.InletName, options.OutletName, options.SymmetryName},
+		patchMetadata:   []PatchMetadata{{}, {}, {Type: "symmetryPlane"}},
+		faceMaterials:   make([]int, 0),
+		materials:       make([]meshx.Material, 0),
+		faceColors:      make([]meshx.Color, 0),
+		smoothingGroups: make([]int, 0),
+	}
+
+	if options.Shape == DomainHemisphere {
+		source.addHemisphere(minBound, maxBound, options.Segments)
+	} else {
+		source.addBox(minBound, maxBound)
+	}
+
+	for range source.vertices {
+		source.vertexColors = append(source.vertexColors, meshx.ColorWhite)
+	}
+
+	return NewHalfEdgeMesh(&source)
+}
+
+// Add a far-field domain around the bounding box of the mesh (see
+// NewDomain) to the mesh. Patches with the names of the patches of the domain
+// are merged with them.
+func (m *HalfEdgeMesh) AddDomain(options DomainOptions) error {
+	domain, err := NewDomain(m.GetAABB(), options)
+	if err != nil {
+		return err
+	}
+
+	return m.MergeWithOptions(domain, MergeOptions{MergePatches: true})
+}
+
+// Add the outward faces of a box with the inlet (patch 0), outlet (patch 1)
+// and symmetry (patch 2) sides.
+func (s *meshSource) addBox(minBound, maxBound meshx.Vector) {
+	for i := range 8 {
+		vertex := minBound
+
+		for j := range 3 {
+			if i&(1<<j) != 0 {
+				vertex[j] = maxBound[j]
+			}
+		}
+
+		s.vertices = append(s.vertices, vertex)
+	}
+
+	sides := [][]int{
+		{0, 4, 6, 2},
+		{1, 3, 7, 5},
+		{0, 1, 5, 4},
+		{2, 6, 7, 3},
+		{0, 2, 3, 1},
+		{4, 5, 7, 6},
+	}
+
+	for i, side := range sides {
+		s.addFace(side, Face{Patch: min(i, 2), Material: -1, Color: meshx.ColorWhite})
+	}
+}
+
+// Add the outward faces of a hemisphere centered on the bottom of a box and
+// enclosing it with the upstream (patch 0) and downstream (patch 1) halves of
+// the dome and the base (patch 2).
+func (s *meshSource) addHemisphere(minBound, maxBound meshx.Vector, segments int) {
+	center := minBound.Add(maxBound).MulScalar(0.5)
+	center[2] = minBound[2]
+	radius := maxBound.Sub(center).Mag()
+	rings := max(2, segments/4)
+
+	// Rings of the dome from the base to (but not including) the pole.
+	for k := range rings {
+		phi := 0.5 * math.Pi * float64(k) / float64(rings)
+
+		for j := range segments {
+			theta := 2 * math.Pi * float64(j) / float64(segments)
+			offset := meshx.NewVector(math.Cos(phi)*math.Cos(theta), math.Cos(phi)*math.Sin(theta), math.Sin(phi))
+			s.vertices = append(s.vertices, center.Add(offset.MulScalar(radius)))
+		}
+	}
+
+	pole := len(s.vertices)
+	base := pole + 1
+	s.vertices = append(s.vertices, center.Add(meshx.NewVector(0, 0, radius)), center)
+
+	for j := range segments {
+		k := (j + 1) % segments
+		theta := 2 * math.Pi * (float64(j) + 0.5) / float64(segments)
+		patch := 1
+
+		if math.Cos(theta) < 0 {
+			patch = 0
+		}
+
+		attributes := Face{Patch: patch, Material: -1, Color: meshx.ColorWhite}
+
+		for i := range rings - 1 {
+			face := []int{i*segments + j, i*segments + k, (i+1)*segments + k, (i+1)*segments + j}
+			s.addFace(face, attributes)
+		}
+
+		i := (rings - 1) * segments
+		s.addFace([]int{i + j, i + k, pole}, attributes)
+		s.addFace([]int{base, k, j}, Face{Patch: 2, Material: -1, Color: meshx.ColorWhite})
+	}
+}
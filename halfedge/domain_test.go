@@ -0,0 +1,103 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the faces of a domain are oriented away from its center.
+func assertDomainOutward(t *testing.T, domain *HalfEdgeMesh, center meshx.Vector) {
+	for i := range domain.GetNumberOfFaces() {
+		normal := domain.GetFaceNormal(i)
+		assert.Greater(t, normal.Dot(domain.GetFaceCentroid(i).Sub(center)), 0.0)
+	}
+}
+
+// Test constructing a box domain.
+func TestNewDomainBox(t *testing.T) {
+	aabb := meshx.NewAABBFromBounds(meshx.NewVector(0, 0, 0), meshx.NewVector(2, 1, 1))
+	domain, err := NewDomain(aabb, DomainOptions{Padding: [6]float64{1, 2}, Ground: true})
+
+	assert.Nil(t, err)
+	assert.True(t, domain.IsClosed())
+	assert.Equal(t, 6, domain.GetNumberOfFaces())
+	assert.Equal(t, 3, domain.GetNumberOfPatches())
+	assert.Equal(t, "inlet", domain.GetPatch(0).Name)
+	assert.Equal(t, "outlet", domain.GetPatch(1).Name)
+	assert.Equal(t, "symmetry", domain.GetPatch(2).Name)
+	assert.Equal(t, "symmetryPlane", domain.GetPatch(2).Metadata.Type)
+	assert.Equal(t, []int{0}, domain.GetPatchFaces(0))
+	assert.Equal(t, []int{1}, domain.GetPatchFaces(1))
+	assert.Len(t, domain.GetPatchFaces(2), 4)
+
+	bounds := domain.GetAABB()
+	assert.InDelta(t, 0, bounds.GetMinBound().Sub(meshx.NewVector(-2, -10, 0)).Mag(), 1e-12)
+	assert.InDelta(t, 0, bounds.GetMaxBound().Sub(meshx.NewVector(6, 11, 11)).Mag(), 1e-12)
+	assertDomainOutward(t, domain, bounds.Center)
+}
+
+// Test constructing a hemisphere domain.
+func TestNewDomainHemisphere(t *testing.T) {
+	aabb := meshx.NewAABBFromBounds(meshx.NewVector(0, 0, 0), meshx.NewVector(1, 1, 1))
+	options := DomainOptions{Shape: DomainHemisphere, Ground: true, Segments: 8, InletName: "farfield"}
+	domain, err := NewDomain(aabb, options)
+
+	assert.Nil(t, err)
+	assert.True(t, domain.IsClosed())
+	assert.True(t, domain.IsConsistent())
+	assert.Equal(t, "farfield", domain.GetPatch(0).Name)
+	assert.Len(t, domain.GetPatchFaces(2), 8)
+	assert.Equal(t, len(domain.GetPatchFaces(0)), len(domain.GetPatchFaces(1)))
+
+	for _, face := range domain.GetPatchFaces(0) {
+		assert.Less(t, domain.GetFaceCentroid(face)[0], 3.0)
+	}
+
+	for _, face := range domain.GetPatchFaces(2) {
+		assert.InDelta(t, 0, domain.GetFaceCentroid(face)[2], 1e-12)
+	}
+
+	// The hemisphere encloses the padded box.
+	center := meshx.NewVector(3, 0.5, 0)
+	radius := meshx.NewVector(11, 6, 6).Sub(center).Mag()
+
+	for i := range domain.GetNumberOfVertices() {
+		if point := domain.GetVertex(i).Point; point[2] > 0 {
+			assert.InDelta(t, radius, point.Sub(center).Mag(), 1e-12)
+		}
+	}
+
+	assertDomainOutward(t, domain, center.Add(meshx.NewVector(0, 0, 1)))
+}
+
+// Test constructing a domain with invalid options.
+func TestNewDomainInvalid(t *testing.T) {
+	aabb := meshx.NewAABBFromBounds(meshx.NewVector(0, 0, 0), meshx.NewVector(1, 1, 1))
+
+	_, err := NewDomain(aabb, DomainOptions{Padding: [6]float64{-1}})
+	assert.ErrorIs(t, err, ErrInvalidDomain)
+
+	_, err = NewDomain(meshx.AABB{}, DomainOptions{})
+	assert.ErrorIs(t, err, ErrInvalidDomain)
+}
+
+// Test adding a domain around a mesh.
+func TestAddDomain(t *testing.T) {
+	mesh := newTestCube(t)
+	mesh.AddPatch("symmetry")
+
+	err := mesh.AddDomain(DomainOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 12, mesh.GetNumberOfFaces())
+	assert.Equal(t, 3, mesh.GetNumberOfPatches())
+	assert.Equal(t, "symmetry", mesh.GetPatch(0).Name)
+	assert.Len(t, mesh.GetPatchFaces(0), 4)
+	assert.True(t, mesh.IsClosed())
+
+	bounds := mesh.GetAABB()
+	assert.InDelta(t, 0, bounds.GetMinBound().Sub(meshx.NewVector(-5, -5, -5)).Mag(), 1e-12)
+	assert.InDelta(t, 0, bounds.GetMaxBound().Sub(meshx.NewVector(11, 6, 6)).Mag(), 1e-12)
+}
@@ -0,0 +1,12 @@
+package halfedge
+
+// Undirected edge of a HalfEdgeMesh as the pair of its half edges.
+type Edge struct {
+	HalfEdge int
+	Twin     int
+}
+
+// Return true if the edge is on the boundary (a single half edge).
+func (e Edge) IsBoundary() bool {
+	return e.Twin < 0
+}
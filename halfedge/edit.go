@@ -0,0 +1,424 @@
+package halfedge
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrInvalidEdit = errors.New("invalid edit")
+)
+
+// Collapse the edge of a half edge into its origin moved to a point. The
+// target vertex and the faces of the edge are removed, so the elements after
+// them are renumbered. The faces of the edge must be triangles and the
+// collapse must keep the mesh manifold (the vertices of the edge share no
+// neighbors other than the opposite vertices of its faces).
+func (m *HalfEdgeMesh) CollapseEdge(index int, point meshx.Vector) error {
+	m.ensureAdjacency()
+
+	halfEdge := m.halfEdges[index]
+	h1, h2 := halfEdge.Next, halfEdge.Prev
+	a, b := halfEdge.Origin, m.halfEdges[h1].Origin
+	opposite := []int{m.halfEdges[h2].Origin}
+
+	if !m.isTriangle(halfEdge.Face) {
+		return fmt.Errorf("%w: face %d is not a triangle", ErrInvalidEdit, halfEdge.Face)
+	}
+
+	if m.halfEdges[h1].IsBoundary() && m.halfEdges[h2].IsBoundary() {
+		return fmt.Errorf("%w: face %d would be left dangling", ErrInvalidEdit, halfEdge.Face)
+	}
+
+	removedFaces := []int{halfEdge.Face}
+	removedHalfEdges := []int{index, h1, h2}
+	twin := halfEdge.Twin
+
+	if twin >= 0 {
+		t1, t2 := m.halfEdges[twin].Next, m.halfEdges[twin].Prev
+		face := m.halfEdges[twin].Face
+
+		if !m.isTriangle(face) {
+			return fmt.Errorf("%w: face %d is not a triangle", ErrInvalidEdit, face)
+		}
+
+		if m.halfEdges[t1].IsBoundary() && m.halfEdges[t2].IsBoundary() {
+			return fmt.Errorf("%w: face %d would be left dangling", ErrInvalidEdit, face)
+		}
+
+		opposite = append(opposite, m.halfEdges[t2].Origin)
+		removedFaces = append(removedFaces, face)
+		removedHalfEdges = append(removedHalfEdges, twin, t1, t2)
+	}
+
+	if len(opposite) == 2 && opposite[0] == opposite[1] {
+		return fmt.Errorf("%w: faces of the edge share all vertices", ErrInvalidEdit)
+	}
+
+	neighborsA, boundaryA := m.getVertexRing(a)
+	neighborsB, boundaryB := m.getVertexRing(b)
+
+	if twin >= 0 && boundaryA && boundaryB {
+		return fmt.Errorf("%w: edge joins two boundary vertices", ErrInvalidEdit)
+	}
+
+	for vertex := range neighborsA {
+		if neighborsB[vertex] && !slices.Contains(opposite, vertex) {
+			return fmt.Errorf("%w: vertices %d and %d share neighbor %d", ErrInvalidEdit, a, b, vertex)
+		}
+	}
+
+	m.beginEdit("collapse")
+
+	m.joinTwins(m.halfEdges[h1].Twin, m.halfEdges[h2].Twin)
+
+	if twin >= 0 {
+		m.joinTwins(m.halfEdges[m.halfEdges[twin].Next].Twin, m.halfEdges[m.halfEdges[twin].Prev].Twin)
+	}
+
+	for i, halfEdge := range m.halfEdges {
+		if halfEdge.Origin == b {
+			halfEdge.Origin = a
+			m.setHalfEdge(i, halfEdge)
+		}
+	}
+
+	vertex := m.vertices[a]
+	vertex.Point = point
+	m.setVertex(a, vertex)
+
+	m.removeElements([]int{b}, removedFaces, removedHalfEdges)
+	m.endEdit()
+
+	if a == len(m.vertices) {
+		a = b
+	}
+
+	changed := make([]int, 0)
+
+	for _, halfEdge := range m.halfEdges {
+		if halfEdge.Origin == a && halfEdge.Face >= 0 {
+			changed = append(changed, halfEdge.Face)
+		}
+	}
+
+	m.notifyDeleted(FacesDeleted, removedFaces)
+	m.notifyDeleted(VerticesDeleted, []int{b})
+	m.notify(VerticesMoved, []int{a})
+	m.notify(FacesChanged, changed)
+
+	return nil
+}
+
+// Split the edge of a half edge at a point. The faces of the edge must be
+// triangles and are each split in two by the new vertex. The index of the
+// new vertex (the last) is returned.
+func (m *HalfEdgeMesh) SplitEdge(index int, point meshx.Vector) (int, error) {
+	m.ensureAdjacency()
+
+	halfEdge := m.halfEdges[index]
+	twin := halfEdge.Twin
+
+	if !m.isTriangle(halfEdge.Face) {
+		return -1, fmt.Errorf("%w: face %d is not a triangle", ErrInvalidEdit, halfEdge.Face)
+	}
+
+	if twin >= 0 && !m.isTriangle(m.halfEdges[twin].Face) {
+		return -1, fmt.Errorf("%w: face %d is not a triangle", ErrInvalidEdit, m.halfEdges[twin].Face)
+	}
+
+	m.beginEdit("split")
+
+	h1, h2 := halfEdge.Next, halfEdge.Prev
+	a, b, c := halfEdge.Origin, m.halfEdges[h1].Origin, m.halfEdges[h2].Origin
+	v := len(m.vertices)
+	m.vertices = append(m.vertices, Vertex{point, -1, m.vertices[a].Color})
+
+	// Face (a, b, c) is split into (a, v, c) and (v, b, c).
+	e1, e2, e3 := m.addHalfEdges()
+	g1 := m.addFace(halfEdge.Face)
+
+	m.linkFace(halfEdge.Face, []int{index, e1, h2}, []int{a, v, c})
+	m.linkFace(g1, []int{e2, h1, e3}, []int{v, b, c})
+	m.joinTwins(e1, e3)
+	m.setFeature(e2, halfEdge.IsFeature)
+
+	changed := []int{halfEdge.Face}
+	added := []int{g1}
+
+	if twin >= 0 {
+		// Face (b, a, d) is split into (v, a, d) and (b, v, d).
+		t1, t2 := m.halfEdges[twin].Next, m.halfEdges[twin].Prev
+		d := m.halfEdges[t2].Origin
+		e4, e5, e6 := m.addHalfEdges()
+		g2 := m.addFace(m.halfEdges[twin].Face)
+
+		m.linkFace(m.halfEdges[twin].Face, []int{twin, t1, e4}, []int{v, a, d})
+		m.linkFace(g2, []int{e5, e6, t2}, []int{b, v, d})
+		m.joinTwins(e4, e6)
+		m.joinTwins(e2, e5)
+		m.setFeature(e5, m.halfEdges[twin].IsFeature)
+
+		changed = append(changed, m.halfEdges[twin].Face)
+		added = append(added, g2)
+	}
+
+	m.endEdit()
+	m.notify(VerticesAdded, []int{v})
+	m.notify(FacesChanged, changed)
+	m.notify(FacesAdded, added)
+
+	return v, nil
+}
+
+// Flip the edge of a half edge shared by two triangles to join their
+// opposite vertices instead. The half edges and faces keep their indices.
+func (m *HalfEdgeMesh) FlipEdge(index int) error {
+	m.ensureAdjacency()
+
+	halfEdge := m.halfEdges[index]
+	twin := halfEdge.Twin
+
+	if halfEdge.IsBoundary() {
+		return fmt.Errorf("%w: half edge %d is on the boundary", ErrInvalidEdit, index)
+	}
+
+	f1, f2 := halfEdge.Face, m.halfEdges[twin].Face
+
+	if !m.isTriangle(f1) || !m.isTriangle(f2) {
+		return fmt.Errorf("%w: faces %d and %d are not triangles", ErrInvalidEdit, f1, f2)
+	}
+
+	h1, h2 := halfEdge.Next, halfEdge.Prev
+	t1, t2 := m.halfEdges[twin].Next, m.halfEdges[twin].Prev
+	a, b := halfEdge.Origin, m.halfEdges[h1].Origin
+	c, d := m.halfEdges[h2].Origin, m.halfEdges[t2].Origin
+
+	if neighbors, _ := m.getVertexRing(c); c == d || neighbors[d] {
+		return fmt.Errorf("%w: vertices %d and %d are already joined", ErrInvalidEdit, c, d)
+	}
+
+	m.beginEdit("flip")
+
+	// Faces (a, b, c) and (b, a, d) become (d, b, c) and (c, a, d).
+	m.linkFace(f1, []int{t2, h1, index}, []int{d, b, c})
+	m.linkFace(f2, []int{h2, t1, twin}, []int{c, a, d})
+	m.setFeature(index, false)
+	m.setFeature(twin, false)
+
+	m.endEdit()
+	m.notify(FacesChanged, []int{f1, f2})
+
+	return nil
+}
+
+// Delete a face. The half edges of its neighbors become boundary half edges
+// and its vertices used by no other face are removed, so the elements after
+// them are renumbered.
+func (m *HalfEdgeMesh) DeleteFace(index int) error {
+	m.ensureAdjacency()
+
+	if index < 0 || index >= len(m.faces) {
+		return fmt.Errorf("%w: face %d does not exist", ErrInvalidEdit, index)
+	}
+
+	halfEdges := m.GetFaceHalfEdges(index)
+	vertices := m.GetFaceVertices(index)
+	used := make(map[int]bool)
+
+	for _, halfEdge := range m.halfEdges {
+		if halfEdge.Face != index && slices.Contains(vertices, halfEdge.Origin) {
+			used[halfEdge.Origin] = true
+		}
+	}
+
+	m.beginEdit("delete")
+
+	for _, halfEdge := range halfEdges {
+		m.joinTwins(m.halfEdges[halfEdge].Twin, -1)
+	}
+
+	unused := make([]int, 0)
+
+	for _, vertex := range vertices {
+		if !used[vertex] && !slices.Contains(unused, vertex) {
+			unused = append(unused, vertex)
+		}
+	}
+
+	m.removeElements(unused, []int{index}, halfEdges)
+	m.endEdit()
+
+	m.notifyDeleted(FacesDeleted, []int{index})
+	m.notifyDeleted(VerticesDeleted, unused)
+
+	return nil
+}
+
+// Return true if a face is a triangle.
+func (m *HalfEdgeMesh) isTriangle(index int) bool {
+	start := m.faces[index].HalfEdge
+	return m.halfEdges[m.halfEdges[m.halfEdges[start].Next].Next].Next == start
+}
+
+// Get the neighbors of a vertex and whether it is on the boundary by
+// scanning the half edges (so the ring of a non-manifold vertex is
+// complete).
+func (m *HalfEdgeMesh) getVertexRing(index int) (map[int]bool, bool) {
+	neighbors := make(map[int]bool)
+	boundary := false
+
+	for _, halfEdge := range m.halfEdges {
+		target := m.halfEdges[halfEdge.Next].Origin
+
+		if halfEdge.Origin == index {
+			neighbors[target] = true
+		} else if target == index {
+			neighbors[halfEdge.Origin] = true
+		} else {
+			continue
+		}
+
+		boundary = boundary || halfEdge.IsBoundary()
+	}
+
+	return neighbors, boundary
+}
+
+// Append three half edges (unlinked) and get their indices.
+func (m *HalfEdgeMesh) addHalfEdges() (int, int, int) {
+	index := len(m.halfEdges)
+
+	for range 3 {
+		m.halfEdges = append(m.halfEdges, HalfEdge{-1, -1, -1, -1, -1, false})
+	}
+
+	return index, index + 1, index + 2
+}
+
+// Append a copy of a face (its patch, material, color and smoothing group)
+// and get its index.
+func (m *HalfEdgeMesh) addFace(index int) int {
+	m.faces = append(m.faces, m.faces[index])
+	return len(m.faces) - 1
+}
+
+// Link the half edges of a face in order from their origins.
+func (m *HalfEdgeMesh) linkFace(index int, halfEdges, origins []int) {
+	for i, id := range halfEdges {
+		halfEdge := m.halfEdges[id]
+		halfEdge.Origin = origins[i]
+		halfEdge.Face = index
+		halfEdge.Next = halfEdges[(i+1)%len(halfEdges)]
+		halfEdge.Prev = halfEdges[(i+len(halfEdges)-1)%len(halfEdges)]
+		m.setHalfEdge(id, halfEdge)
+	}
+
+	face := m.faces[index]
+	face.HalfEdge = halfEdges[0]
+	m.setFace(index, face)
+}
+
+// Make two half edges twins. Either may be -1 to leave the other on the
+// boundary.
+func (m *HalfEdgeMesh) joinTwins(i, j int) {
+	if i >= 0 {
+		halfEdge := m.halfEdges[i]
+		halfEdge.Twin = j
+		m.setHalfEdge(i, halfEdge)
+	}
+
+	if j >= 0 {
+		halfEdge := m.halfEdges[j]
+		halfEdge.Twin = i
+		m.setHalfEdge(j, halfEdge)
+	}
+}
+
+// Set a half edge as a feature (or not).
+func (m *HalfEdgeMesh) setFeature(index int, isFeature bool) {
+	halfEdge := m.halfEdges[index]
+	halfEdge.IsFeature = isFeature
+	m.setHalfEdge(index, halfEdge)
+}
+
+// Remove vertices, faces and half edges no longer referenced by the others.
+// Each is replaced by the last element, so the references to the last are
+// renumbered.
+func (m *HalfEdgeMesh) removeElements(vertices, faces, halfEdges []int) {
+	slices.Sort(halfEdges)
+
+	for i := len(halfEdges) - 1; i >= 0; i-- {
+		index := halfEdges[i]
+		last := len(m.halfEdges) - 1
+
+		if index != last {
+			halfEdge := m.halfEdges[last]
+			m.setHalfEdge(index, halfEdge)
+
+			next := m.halfEdges[halfEdge.Next]
+			next.Prev = index
+			m.setHalfEdge(halfEdge.Next, next)
+
+			prev := m.halfEdges[halfEdge.Prev]
+			prev.Next = index
+			m.setHalfEdge(halfEdge.Prev, prev)
+
+			if halfEdge.Twin >= 0 {
+				m.joinTwins(index, halfEdge.Twin)
+			}
+
+			if face := m.faces[halfEdge.Face]; face.HalfEdge == last {
+				face.HalfEdge = index
+				m.setFace(halfEdge.Face, face)
+			}
+		}
+
+		m.saveHalfEdge(last)
+		m.halfEdges = m.halfEdges[:last]
+	}
+
+	slices.Sort(faces)
+
+	for i := len(faces) - 1; i >= 0; i-- {
+		index := faces[i]
+		last := len(m.faces) - 1
+
+		if index != last {
+			m.setFace(index, m.faces[last])
+
+			for _, id := range m.GetFaceHalfEdges(index) {
+				halfEdge := m.halfEdges[id]
+				halfEdge.Face = index
+				m.setHalfEdge(id, halfEdge)
+			}
+		}
+
+		m.saveFace(last)
+		m.faces = m.faces[:last]
+	}
+
+	slices.Sort(vertices)
+
+	for i := len(vertices) - 1; i >= 0; i-- {
+		index := vertices[i]
+		last := len(m.vertices) - 1
+
+		if index != last {
+			m.setVertex(index, m.vertices[last])
+
+			for id, halfEdge := range m.halfEdges {
+				if halfEdge.Origin == last {
+					halfEdge.Origin = index
+					m.setHalfEdge(id, halfEdge)
+				}
+			}
+		}
+
+		m.saveVertex(last)
+		m.vertices = m.vertices[:last]
+	}
+}
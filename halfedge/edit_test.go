@@ -0,0 +1,145 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Construct a flat grid of n x n squares each split into two triangles.
+func newTestTriangleGrid(t *testing.T, n int) *HalfEdgeMesh {
+	source := meshSource{}
+
+	for j := range n + 1 {
+		for i := range n + 1 {
+			source.vertices = append(source.vertices, meshx.NewVector(float64(i), float64(j), 0))
+		}
+	}
+
+	for j := range n {
+		for i := range n {
+			a := j*(n+1) + i
+			b, c, d := a+1, a+n+2, a+n+1
+			source.addFace([]int{a, b, c}, Face{Patch: -1, Material: -1})
+			source.addFace([]int{a, c, d}, Face{Patch: -1, Material: -1})
+		}
+	}
+
+	mesh, err := NewHalfEdgeMesh(&source)
+	assert.Empty(t, err)
+	return mesh
+}
+
+// Find the half edge from one vertex to another.
+func findHalfEdge(mesh *HalfEdgeMesh, origin, target int) int {
+	for i := range mesh.GetNumberOfHalfEdges() {
+		halfEdge := mesh.GetHalfEdge(i)
+
+		if halfEdge.Origin == origin && mesh.GetHalfEdge(halfEdge.Next).Origin == target {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Assert the connectivity of a mesh is valid and matches that of a mesh
+// rebuilt from its faces.
+func assertValidTopology(t *testing.T, mesh *HalfEdgeMesh) {
+	for i := range mesh.GetNumberOfHalfEdges() {
+		halfEdge := mesh.GetHalfEdge(i)
+		assert.Equal(t, i, mesh.GetHalfEdge(halfEdge.Next).Prev)
+		assert.Equal(t, i, mesh.GetHalfEdge(halfEdge.Prev).Next)
+		assert.Equal(t, halfEdge.Face, mesh.GetHalfEdge(halfEdge.Next).Face)
+		assert.Less(t, halfEdge.Origin, mesh.GetNumberOfVertices())
+
+		if !halfEdge.IsBoundary() {
+			twin := mesh.GetHalfEdge(halfEdge.Twin)
+			assert.Equal(t, i, twin.Twin)
+			assert.Equal(t, halfEdge.Origin, mesh.GetHalfEdge(twin.Next).Origin)
+		}
+	}
+
+	for i := range mesh.GetNumberOfFaces() {
+		assert.Equal(t, i, mesh.GetHalfEdge(mesh.GetFace(i).HalfEdge).Face)
+	}
+
+	rebuilt, err := NewHalfEdgeMesh(mesh.GetMeshReader())
+	assert.Empty(t, err)
+	assert.Equal(t, rebuilt.GetNumberOfEdges(), mesh.GetNumberOfEdges())
+	assert.Equal(t, len(rebuilt.GetBoundaryLoops()), len(mesh.GetBoundaryLoops()))
+}
+
+// Test flipping an edge shared by two triangles.
+func TestFlipEdge(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 1)
+	index := findHalfEdge(mesh, 0, 3)
+
+	assert.Empty(t, mesh.FlipEdge(index))
+	assertValidTopology(t, mesh)
+	assert.Equal(t, -1, findHalfEdge(mesh, 0, 3))
+	assert.NotEqual(t, -1, findHalfEdge(mesh, 1, 2))
+	assert.InDelta(t, 1, mesh.GetFaceArea(0)+mesh.GetFaceArea(1), 1e-12)
+
+	assert.ErrorIs(t, mesh.FlipEdge(findHalfEdge(mesh, 0, 1)), ErrInvalidEdit)
+}
+
+// Test splitting an interior and a boundary edge.
+func TestSplitEdge(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+
+	vertex, err := mesh.SplitEdge(findHalfEdge(mesh, 0, 4), meshx.NewVector(0.5, 0.5, 0))
+	assert.Empty(t, err)
+	assert.Equal(t, 9, vertex)
+	assert.Equal(t, 10, mesh.GetNumberOfFaces())
+	assertValidTopology(t, mesh)
+
+	_, err = mesh.SplitEdge(findHalfEdge(mesh, 0, 1), meshx.NewVector(0.5, 0, 0))
+	assert.Empty(t, err)
+	assert.Equal(t, 11, mesh.GetNumberOfFaces())
+	assertValidTopology(t, mesh)
+	assert.Equal(t, 9, len(mesh.GetBoundaryLoops()[0]))
+}
+
+// Test collapsing an interior and a boundary edge.
+func TestCollapseEdge(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+	point := meshx.NewVector(1.1, 1.1, 0)
+
+	assert.Empty(t, mesh.CollapseEdge(findHalfEdge(mesh, 4, 8), point))
+	assert.Equal(t, 8, mesh.GetNumberOfVertices())
+	assert.Equal(t, 6, mesh.GetNumberOfFaces())
+	assert.Equal(t, point, mesh.GetVertex(4).Point)
+	assertValidTopology(t, mesh)
+
+	mesh = newTestTriangleGrid(t, 2)
+	assert.Empty(t, mesh.CollapseEdge(findHalfEdge(mesh, 0, 1), meshx.NewVector(0, 0, 0)))
+	assert.Equal(t, 7, mesh.GetNumberOfFaces())
+	assertValidTopology(t, mesh)
+
+	// Both vertices of an interior edge are on the boundary.
+	mesh = newTestTriangleGrid(t, 2)
+	assert.ErrorIs(t, mesh.CollapseEdge(findHalfEdge(mesh, 1, 5), meshx.Vector{}), ErrInvalidEdit)
+
+	// The last triangle of a corner would be left dangling.
+	mesh = newTestTriangleGrid(t, 1)
+	assert.ErrorIs(t, mesh.CollapseEdge(findHalfEdge(mesh, 0, 3), meshx.Vector{}), ErrInvalidEdit)
+}
+
+// Test deleting faces and the vertices left unused.
+func TestDeleteFace(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+
+	assert.Empty(t, mesh.DeleteFace(3))
+	assert.Equal(t, 7, mesh.GetNumberOfFaces())
+	assert.Equal(t, 9, mesh.GetNumberOfVertices())
+	assertValidTopology(t, mesh)
+
+	mesh = newTestTriangleGrid(t, 1)
+	assert.Empty(t, mesh.DeleteFace(0))
+	assert.Equal(t, 3, mesh.GetNumberOfVertices())
+	assertValidTopology(t, mesh)
+
+	assert.ErrorIs(t, mesh.DeleteFace(1), ErrInvalidEdit)
+}
@@ -0,0 +1,116 @@
+package halfedge
+
+import (
+	"errors"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrInvalidBoundaryLoop = errors.New("invalid boundary loop")
+)
+
+// Extrude the faces of a patch (in place) along a direction. The faces of
+// the patch are moved by the distance along the direction and connected to
+// the rest of the mesh by side walls. The side walls are assigned to the
+// patch of the extruded faces.
+func (m *HalfEdgeMesh) ExtrudePatch(patch int, direction meshx.Vector, distance float64) error {
+	m.ensureAdjacency()
+
+	offset := direction.Unit().MulScalar(distance)
+	source := newMeshSource(m)
+	faces := m.GetPatchFaces(patch)
+	inPatch := make([]bool, m.GetNumberOfFaces())
+	vertexMap := make(map[int]int)
+
+	for _, face := range faces {
+		inPatch[face] = true
+
+		for _, vertex := range m.GetFaceVertices(face) {
+			if _, ok := vertexMap[vertex]; !ok {
+				vertexMap[vertex] = len(source.vertices)
+				source.vertices = append(source.vertices, m.vertices[vertex].Point.Add(offset))
+				source.vertexColors = append(source.vertexColors, m.vertices[vertex].Color)
+			}
+		}
+	}
+
+	for _, face := range faces {
+		for i, vertex := range source.faces[face] {
+			source.faces[face][i] = vertexMap[vertex]
+		}
+
+		for _, id := range m.GetFaceHalfEdges(face) {
+			halfEdge := m.halfEdges[id]
+
+			if halfEdge.IsBoundary() || !inPatch[m.halfEdges[halfEdge.Twin].Face] {
+				p := halfEdge.Origin
+				q := m.halfEdges[halfEdge.Next].Origin
+				wall := []int{p, q, vertexMap[q], vertexMap[p]}
+				source.faces = append(source.faces, wall)
+				source.facePatches = append(source.facePatches, patch)
+				source.faceMaterials = append(source.faceMaterials, m.faces[face].Material)
+				source.faceColors = append(source.faceColors, m.faces[face].Color)
+				source.smoothingGroups = append(source.smoothingGroups, m.faces[face].SmoothingGroup)
+			}
+		}
+	}
+
+	source.removeUnusedVertices()
+
+	return m.rebuild(source)
+}
+
+// Extrude an open boundary loop (in place) along a direction. Side walls are
+// created between the boundary loop and its copy moved by the distance along
+// the direction. Each side wall is assigned to the patch of the face
+// adjacent to its boundary edge. An error is returned if the loop is not an
+// index of GetBoundaryLoops.
+func (m *HalfEdgeMesh) ExtrudeBoundary(loop int, direction meshx.Vector, distance float64) error {
+	m.ensureAdjacency()
+
+	loops := m.GetBoundaryLoops()
+
+	if loop < 0 || loop >= len(loops) {
+		return ErrInvalidBoundaryLoop
+	}
+
+	offset := direction.Unit().MulScalar(distance)
+	source := newMeshSource(m)
+	vertexMap := make(map[int]int)
+	halfEdges := loops[loop]
+
+	for _, id := range halfEdges {
+		vertex := m.halfEdges[id].Origin
+		vertexMap[vertex] = len(source.vertices)
+		source.vertices = append(source.vertices, m.vertices[vertex].Point.Add(offset))
+		source.vertexColors = append(source.vertexColors, m.vertices[vertex].Color)
+	}
+
+	for _, id := range halfEdges {
+		halfEdge := m.halfEdges[id]
+		p := halfEdge.Origin
+		q := m.halfEdges[halfEdge.Next].Origin
+		wall := []int{q, p, vertexMap[p], vertexMap[q]}
+		source.faces = append(source.faces, wall)
+		source.facePatches = append(source.facePatches, m.faces[halfEdge.Face].Patch)
+		source.faceMaterials = append(source.faceMaterials, m.faces[halfEdge.Face].Material)
+		source.faceColors = append(source.faceColors, m.faces[halfEdge.Face].Color)
+		source.smoothingGroups = append(source.smoothingGroups, m.faces[halfEdge.Face].SmoothingGroup)
+	}
+
+	return m.rebuild(source)
+}
+
+// Rebuild the mesh (in place) from a mesh source. The mesh is unchanged if
+// an error is returned.
+func (m *HalfEdgeMesh) rebuild(source *meshSource) error {
+	mesh, err := NewHalfEdgeMesh(source)
+	if err != nil {
+		return err
+	}
+
+	m.replace(mesh)
+
+	return nil
+}
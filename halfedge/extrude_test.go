@@ -0,0 +1,56 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test extruding a patch of a closed mesh.
+func TestExtrudePatch(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	err = mesh.ExtrudePatch(0, meshx.NewVector(-1, 0, 0), 1)
+	assert.Empty(t, err)
+	assert.True(t, mesh.IsClosed())
+	assert.Equal(t, 12, mesh.GetNumberOfVertices())
+	assert.Equal(t, 11, mesh.GetNumberOfFaces())
+	assert.Equal(t, 5, len(mesh.GetPatchFaces(0)))
+
+	aabb := mesh.GetAABB()
+	assert.Equal(t, -1.0, aabb.GetMinBound()[0])
+}
+
+// Test extruding the boundary loop of an open mesh.
+func TestExtrudeBoundary(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh = mesh.ExtractPatches([]int{0})
+	assert.Equal(t, 1, len(mesh.GetBoundaryLoops()))
+	assert.Equal(t, 4, len(mesh.GetBoundaryLoops()[0]))
+
+	err = mesh.ExtrudeBoundary(0, meshx.NewVector(1, 0, 0), 2)
+	assert.Empty(t, err)
+	assert.Equal(t, 8, mesh.GetNumberOfVertices())
+	assert.Equal(t, 5, mesh.GetNumberOfFaces())
+	assert.Equal(t, 1, len(mesh.GetBoundaryLoops()))
+	assert.True(t, mesh.IsConsistent())
+}
+
+// Test extruding a boundary loop out of range.
+func TestExtrudeBoundaryInvalid(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh = mesh.ExtractPatches([]int{0})
+
+	for _, loop := range []int{-1, 1} {
+		err = mesh.ExtrudeBoundary(loop, meshx.NewVector(1, 0, 0), 2)
+		assert.ErrorIs(t, err, ErrInvalidBoundaryLoop)
+		assert.Equal(t, 4, mesh.GetNumberOfVertices())
+		assert.Equal(t, 1, mesh.GetNumberOfFaces())
+	}
+}
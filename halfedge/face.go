@@ -1,6 +1,13 @@
 package halfedge
 
+import (
+	"github.com/ajcurley/meshx-go"
+)
+
 type Face struct {
-	HalfEdge int
-	Patch    int
+	HalfEdge       int
+	Patch          int
+	Material       int
+	Color          meshx.Color
+	SmoothingGroup int
 }
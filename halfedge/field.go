@@ -0,0 +1,171 @@
+package halfedge
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrInvalidFieldSize  = errors.New("invalid field size")
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+)
+
+// Options for mapping a scalar field to colors. Zero values use the
+// defaults.
+type FieldOptions struct {
+	// Name of the field in the exported file. The default is "scalar".
+	Name string
+
+	// Colormap of the field. The default is meshx.ColormapViridis.
+	Colormap meshx.Colormap
+
+	// Range of the field mapped to the colormap. The default (Lower equal to
+	// Upper) is the range of the finite values.
+	Lower float64
+	Upper float64
+}
+
+// Apply the defaults of the options for the values.
+func (o FieldOptions) withDefaults(values []float64) FieldOptions {
+	if o.Name == "" {
+		o.Name = "scalar"
+	}
+
+	if len(o.Colormap) == 0 {
+		o.Colormap = meshx.ColormapViridis
+	}
+
+	if o.Lower == o.Upper {
+		o.Lower, o.Upper = meshx.ComputeScalarRange(values)
+	}
+
+	return o
+}
+
+// Color the vertices (in place) by a per-vertex scalar field.
+func (m *HalfEdgeMesh) ColorizeVertices(values []float64, options FieldOptions) error {
+	if len(values) != m.GetNumberOfVertices() {
+		return ErrInvalidFieldSize
+	}
+
+	options = options.withDefaults(values)
+	m.SetVertexColors(options.Colormap.MapValues(values, options.Lower, options.Upper))
+
+	return nil
+}
+
+// Color the faces (in place) by a per-face scalar field.
+func (m *HalfEdgeMesh) ColorizeFaces(values []float64, options FieldOptions) error {
+	if len(values) != m.GetNumberOfFaces() {
+		return ErrInvalidFieldSize
+	}
+
+	options = options.withDefaults(values)
+	m.SetFaceColors(options.Colormap.MapValues(values, options.Lower, options.Upper))
+
+	return nil
+}
+
+// Write a per-vertex scalar field mapped through a colormap to a PLY or VTP
+// file by its format (".ply" or ".vtp"). The VTP file retains the values of
+// the field. The mesh is unchanged.
+func (m *HalfEdgeMesh) WriteVertexField(writer io.Writer, format string, values []float64, options FieldOptions) error {
+	return m.writeField(writer, format, values, options, false)
+}
+
+// Write a per-face scalar field mapped through a colormap to a PLY or VTP
+// file by its format (".ply" or ".vtp"). The VTP file retains the values of
+// the field. The mesh is unchanged.
+func (m *HalfEdgeMesh) WriteFaceField(writer io.Writer, format string, values []float64, options FieldOptions) error {
+	return m.writeField(writer, format, values, options, true)
+}
+
+// Write a per-vertex scalar field mapped through a colormap to a PLY or VTP
+// file path (by extension). The VTP file retains the values of the field.
+// The mesh is unchanged.
+func (m *HalfEdgeMesh) WriteVertexFieldToPath(path string, values []float64, options FieldOptions) error {
+	return m.writeFieldToPath(path, values, options, false)
+}
+
+// Write a per-face scalar field mapped through a colormap to a PLY or VTP
+// file path (by extension). The VTP file retains the values of the field.
+// The mesh is unchanged.
+func (m *HalfEdgeMesh) WriteFaceFieldToPath(path string, values []float64, options FieldOptions) error {
+	return m.writeFieldToPath(path, values, options, true)
+}
+
+// Write a scalar field mapped through a colormap to a PLY or VTP file path.
+func (m *HalfEdgeMesh) writeFieldToPath(path string, values []float64, options FieldOptions, onFaces bool) error {
+	format := filepath.Ext(path)
+
+	if !isFieldFormat(format) {
+		return ErrUnsupportedFormat
+	}
+
+	if size := m.getFieldSize(onFaces); len(values) != size {
+		return ErrInvalidFieldSize
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.writeField(file, format, values, options, onFaces)
+}
+
+// Get the size of a per-vertex or per-face field.
+func (m *HalfEdgeMesh) getFieldSize(onFaces bool) int {
+	if onFaces {
+		return m.GetNumberOfFaces()
+	}
+
+	return m.GetNumberOfVertices()
+}
+
+// Check if a field can be written to a format.
+func isFieldFormat(format string) bool {
+	format = strings.ToLower(format)
+	return format == ".ply" || format == ".vtp"
+}
+
+// Write a scalar field mapped through a colormap to a PLY or VTP file.
+func (m *HalfEdgeMesh) writeField(writer io.Writer, format string, values []float64, options FieldOptions, onFaces bool) error {
+	if !isFieldFormat(format) {
+		return ErrUnsupportedFormat
+	}
+
+	mesh := *m
+	mesh.vertices = append([]Vertex(nil), m.vertices...)
+	mesh.faces = append([]Face(nil), m.faces...)
+
+	var err error
+
+	if onFaces {
+		err = mesh.ColorizeFaces(values, options)
+	} else {
+		err = mesh.ColorizeVertices(values, options)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if strings.ToLower(format) == ".ply" {
+		return mesh.WritePLY(writer, meshx.PLYFormatBinaryLittleEndian)
+	}
+
+	field := meshx.ScalarField{Name: options.withDefaults(values).Name, Values: values}
+
+	if onFaces {
+		return mesh.writeVTP(writer, nil, []meshx.ScalarField{field})
+	}
+
+	return mesh.writeVTP(writer, []meshx.ScalarField{field}, nil)
+}
@@ -0,0 +1,93 @@
+package halfedge
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test coloring the faces of a mesh by a scalar field.
+func TestColorizeFaces(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	values := make([]float64, mesh.GetNumberOfFaces())
+
+	for i := range values {
+		values[i] = mesh.GetFaceArea(i)
+	}
+
+	assert.ErrorIs(t, mesh.ColorizeFaces(values[1:], FieldOptions{}), ErrInvalidFieldSize)
+	assert.Empty(t, mesh.ColorizeFaces(values, FieldOptions{Colormap: meshx.ColormapGray}))
+	assert.True(t, mesh.HasFaceColors())
+
+	for i := range values {
+		if values[i] == 1 {
+			assert.Equal(t, meshx.ColorWhite, mesh.GetFace(i).Color)
+		} else {
+			assert.Equal(t, meshx.ColorBlack, mesh.GetFace(i).Color)
+		}
+	}
+}
+
+// Test writing a scalar field to PLY and VTP files.
+func TestWriteVertexFieldToPath(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	values := make([]float64, mesh.GetNumberOfVertices())
+
+	for i := range values {
+		values[i] = mesh.GetVertex(i).Point[2]
+	}
+
+	dir := t.TempDir()
+	options := FieldOptions{Colormap: meshx.ColormapCoolWarm}
+
+	assert.ErrorIs(t, mesh.WriteVertexFieldToPath(filepath.Join(dir, "field.obj"), values, options), ErrUnsupportedFormat)
+	assert.Empty(t, mesh.WriteVertexFieldToPath(filepath.Join(dir, "field.vtp"), values, options))
+	assert.Empty(t, mesh.WriteVertexFieldToPath(filepath.Join(dir, "field.ply"), values, options))
+	assert.False(t, mesh.HasVertexColors())
+
+	result, err := NewHalfEdgeMeshFromPLYPath(filepath.Join(dir, "field.ply"))
+	assert.Empty(t, err)
+	assert.True(t, result.HasVertexColors())
+
+	for i, value := range values {
+		assert.Equal(t, meshx.ColormapCoolWarm.Map(value), result.GetVertex(i).Color)
+	}
+}
+
+// Test writing a per-face scalar field to a writer.
+func TestWriteFaceField(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	values := make([]float64, mesh.GetNumberOfFaces())
+
+	for i := range values {
+		values[i] = float64(i)
+	}
+
+	var buffer bytes.Buffer
+	options := FieldOptions{Colormap: meshx.ColormapCoolWarm}
+
+	assert.ErrorIs(t, mesh.WriteFaceField(&buffer, ".obj", values, options), ErrUnsupportedFormat)
+	assert.ErrorIs(t, mesh.WriteFaceField(&buffer, ".ply", values[1:], options), ErrInvalidFieldSize)
+	assert.Empty(t, mesh.WriteFaceField(&buffer, ".PLY", values, options))
+	assert.False(t, mesh.HasFaceColors())
+
+	result, err := NewHalfEdgeMeshFromPLY(&buffer)
+	assert.Empty(t, err)
+	assert.True(t, result.HasFaceColors())
+
+	lower, upper := meshx.ComputeScalarRange(values)
+	colors := meshx.ColormapCoolWarm.MapValues(values, lower, upper)
+
+	for i, color := range colors {
+		assert.Equal(t, color, result.GetFace(i).Color)
+	}
+}
@@ -0,0 +1,159 @@
+package halfedge
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrInvalidGrid    = errors.New("invalid structured grid")
+	ErrInvalidProfile = errors.New("invalid profile")
+)
+
+// Construct a HalfEdgeMesh of quadrilaterals from a structured grid of
+// points indexed by row and column. If periodic, the last column is
+// connected to the first column. All rows must have the same length.
+func NewStructuredGrid(grid [][]meshx.Vector, periodic bool) (*HalfEdgeMesh, error) {
+	source, err := newStructuredGridSource(grid, periodic)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHalfEdgeMesh(source)
+}
+
+// Construct a HalfEdgeMesh by revolving a profile (polyline) about an axis
+// through the origin. Profile points on the axis are shared by all segments
+// such that the resulting faces are triangles. The faces are oriented
+// outward for a profile ordered along the axis direction on the positive
+// side of the axis.
+func NewRevolve(profile []meshx.Vector, origin, axis meshx.Vector, segments int) (*HalfEdgeMesh, error) {
+	if len(profile) < 2 || segments < 3 {
+		return nil, ErrInvalidProfile
+	}
+
+	axis = axis.Unit()
+	grid := make([][]meshx.Vector, len(profile))
+
+	for i, point := range profile {
+		grid[i] = make([]meshx.Vector, segments)
+
+		for j := range segments {
+			angle := 2 * math.Pi * float64(j) / float64(segments)
+			grid[i][j] = rotateAboutAxis(point, origin, axis, angle)
+		}
+	}
+
+	source, err := newStructuredGridSource(grid, true)
+	if err != nil {
+		return nil, err
+	}
+
+	vertexMap := make([]int, len(source.vertices))
+
+	for i, point := range profile {
+		r := point.Sub(origin)
+		onAxis := r.Sub(axis.MulScalar(r.Dot(axis))).Mag() <= 1e-12*max(1, r.Mag())
+
+		for j := range segments {
+			if onAxis {
+				vertexMap[i*segments+j] = i * segments
+			} else {
+				vertexMap[i*segments+j] = i*segments + j
+			}
+		}
+	}
+
+	source.remapVertices(vertexMap)
+
+	return NewHalfEdgeMesh(source)
+}
+
+// Construct a HalfEdgeMesh by lofting between two cross sections (polylines)
+// with the same number of points. The intermediate sections are linearly
+// interpolated. If closed, the cross sections are closed polylines.
+func NewLoft(a, b []meshx.Vector, sections int, closed bool) (*HalfEdgeMesh, error) {
+	if len(a) != len(b) || len(a) < 2 || sections < 1 {
+		return nil, ErrInvalidProfile
+	}
+
+	grid := make([][]meshx.Vector, sections+1)
+
+	for i := range grid {
+		t := float64(i) / float64(sections)
+		grid[i] = make([]meshx.Vector, len(a))
+
+		for j := range a {
+			grid[i][j] = a[j].MulScalar(1 - t).Add(b[j].MulScalar(t))
+		}
+	}
+
+	return NewStructuredGrid(grid, closed)
+}
+
+// Construct the mesh source of a structured grid of points.
+func newStructuredGridSource(grid [][]meshx.Vector, periodic bool) (*meshSource, error) {
+	if len(grid) < 2 || len(grid[0]) < 2 || (periodic && len(grid[0]) < 3) {
+		return nil, ErrInvalidGrid
+	}
+
+	n := len(grid[0])
+	columns := n - 1
+
+	if periodic {
+		columns = n
+	}
+
+	source := meshSource{
+		vertices:        make([]meshx.Vector, 0, len(grid)*n),
+		faces:           make([][]int, 0, (len(grid)-1)*columns),
+		facePatches:     make([]int, 0, (len(grid)-1)*columns),
+		patches:         make([]string, 0),
+		faceMaterials:   make([]int, 0, (len(grid)-1)*columns),
+		materials:       make([]meshx.Material, 0),
+		faceColors:      make([]meshx.Color, 0, (len(grid)-1)*columns),
+		smoothingGroups: make([]int, 0, (len(grid)-1)*columns),
+	}
+
+	for _, row := range grid {
+		if len(row) != n {
+			return nil, ErrInvalidGrid
+		}
+
+		source.vertices = append(source.vertices, row...)
+
+		for range row {
+			source.vertexColors = append(source.vertexColors, meshx.ColorWhite)
+		}
+	}
+
+	for i := 0; i < len(grid)-1; i++ {
+		for j := 0; j < columns; j++ {
+			k := (j + 1) % n
+			face := []int{i*n + j, i*n + k, (i+1)*n + k, (i+1)*n + j}
+			source.faces = append(source.faces, face)
+			source.facePatches = append(source.facePatches, -1)
+			source.faceMaterials = append(source.faceMaterials, -1)
+			source.faceColors = append(source.faceColors, meshx.ColorWhite)
+			source.smoothingGroups = append(source.smoothingGroups, 0)
+		}
+	}
+
+	return &source, nil
+}
+
+// Rotate a point about an axis (unit vector) through the origin by an angle
+// in radians (Rodrigues' rotation formula).
+func rotateAboutAxis(point, origin, axis meshx.Vector, angle float64) meshx.Vector {
+	v := point.Sub(origin)
+	cos := math.Cos(angle)
+	sin := math.Sin(angle)
+
+	rotated := v.MulScalar(cos).
+		Add(axis.Cross(v).MulScalar(sin)).
+		Add(axis.MulScalar(axis.Dot(v) * (1 - cos)))
+
+	return rotated.Add(origin)
+}
@@ -0,0 +1,54 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test revolving a profile into a closed cylinder.
+func TestNewRevolve(t *testing.T) {
+	profile := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(1, 0, 1),
+		meshx.NewVector(0, 0, 1),
+	}
+
+	mesh, err := NewRevolve(profile, meshx.Vector{}, meshx.NewVector(0, 0, 1), 16)
+	assert.Empty(t, err)
+	assert.Equal(t, 34, mesh.GetNumberOfVertices())
+	assert.Equal(t, 48, mesh.GetNumberOfFaces())
+	assert.True(t, mesh.IsClosed())
+	assert.True(t, mesh.IsConsistent())
+
+	point := meshx.NewVector(0, 0, 0.5)
+	assert.InDelta(t, 1.0, meshx.WindingNumber(mesh.GetTriangles(), point), 1e-9)
+}
+
+// Test lofting between two closed cross sections.
+func TestNewLoft(t *testing.T) {
+	a := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(1, 1, 0),
+		meshx.NewVector(0, 1, 0),
+	}
+
+	b := []meshx.Vector{
+		meshx.NewVector(0, 0, 2),
+		meshx.NewVector(2, 0, 2),
+		meshx.NewVector(2, 2, 2),
+		meshx.NewVector(0, 2, 2),
+	}
+
+	mesh, err := NewLoft(a, b, 4, true)
+	assert.Empty(t, err)
+	assert.Equal(t, 20, mesh.GetNumberOfVertices())
+	assert.Equal(t, 16, mesh.GetNumberOfFaces())
+	assert.Equal(t, 2, len(mesh.GetBoundaryLoops()))
+
+	_, err = NewLoft(a, b[:3], 4, true)
+	assert.ErrorIs(t, err, ErrInvalidProfile)
+}
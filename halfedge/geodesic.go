@@ -0,0 +1,146 @@
+package halfedge
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+)
+
+// Select the faces within a geodesic radius of the centroid of a seed face
+// (e.g. a brush stroke). The selection grows from the seed across shared
+// edges, so it does not bleed across gaps between nearby surfaces, and a face
+// is selected if the geodesic distance to its centroid is within the radius.
+// The geodesic distance is approximated by the shortest path along the
+// edges. The seed face is always selected and the faces are sorted.
+func (m *HalfEdgeMesh) SelectGeodesicDisk(seedFace int, radius float64) []int {
+	m.ensureAdjacency()
+
+	distances := m.getGeodesicDistances(seedFace, radius)
+	selected := map[int]bool{seedFace: true}
+	queue := []int{seedFace}
+	vertices := make([]int, 0, 4)
+	neighbors := make([]int, 0, 4)
+
+	for len(queue) > 0 {
+		face := queue[0]
+		queue = queue[1:]
+
+		for _, neighbor := range m.GetFaceNeighborsInto(face, neighbors) {
+			if selected[neighbor] {
+				continue
+			}
+
+			centroid := m.GetFaceCentroid(neighbor)
+			distance := math.Inf(1)
+			vertices = m.GetFaceVerticesInto(neighbor, vertices)
+
+			for _, vertex := range vertices {
+				distance = min(distance, distances[vertex]+m.vertices[vertex].Point.Sub(centroid).Mag())
+			}
+
+			if distance <= radius {
+				selected[neighbor] = true
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	faces := make([]int, 0, len(selected))
+
+	for face := range selected {
+		faces = append(faces, face)
+	}
+
+	slices.Sort(faces)
+	return faces
+}
+
+// Compute the shortest distances along the edges from the centroid of a
+// seed face to the vertices (Dijkstra). The vertices beyond the maximum
+// distance are not settled, so their distances are only upper bounds (or
+// infinite if not reached).
+func (m *HalfEdgeMesh) getGeodesicDistances(seedFace int, maxDistance float64) []float64 {
+	distances := make([]float64, m.GetNumberOfVertices())
+
+	for i := range distances {
+		distances[i] = math.Inf(1)
+	}
+
+	outgoing := make([][]int, m.GetNumberOfVertices())
+
+	for i, halfEdge := range m.halfEdges {
+		outgoing[halfEdge.Origin] = append(outgoing[halfEdge.Origin], i)
+	}
+
+	var queue geodesicQueue
+	centroid := m.GetFaceCentroid(seedFace)
+
+	for _, vertex := range m.GetFaceVertices(seedFace) {
+		distances[vertex] = m.vertices[vertex].Point.Sub(centroid).Mag()
+		heap.Push(&queue, geodesicCandidate{vertex, distances[vertex]})
+	}
+
+	for queue.Len() > 0 {
+		candidate := heap.Pop(&queue).(geodesicCandidate)
+
+		if candidate.distance > distances[candidate.vertex] || candidate.distance > maxDistance {
+			continue
+		}
+
+		point := m.vertices[candidate.vertex].Point
+
+		for _, id := range outgoing[candidate.vertex] {
+			halfEdge := m.halfEdges[id]
+
+			// The origin of the previous half edge is a neighbor across an
+			// edge without a twin on the boundary.
+			for _, neighbor := range []int{m.halfEdges[halfEdge.Next].Origin, m.halfEdges[halfEdge.Prev].Origin} {
+				distance := candidate.distance + m.vertices[neighbor].Point.Sub(point).Mag()
+
+				if distance < distances[neighbor] {
+					distances[neighbor] = distance
+					heap.Push(&queue, geodesicCandidate{neighbor, distance})
+				}
+			}
+		}
+	}
+
+	return distances
+}
+
+// Vertex queued with its tentative geodesic distance.
+type geodesicCandidate struct {
+	vertex   int
+	distance float64
+}
+
+// Priority queue of vertices ordered by their geodesic distance.
+type geodesicQueue []geodesicCandidate
+
+// Implement the heap.Interface interface.
+func (q geodesicQueue) Len() int {
+	return len(q)
+}
+
+// Implement the heap.Interface interface.
+func (q geodesicQueue) Less(i, j int) bool {
+	return q[i].distance < q[j].distance
+}
+
+// Implement the heap.Interface interface.
+func (q geodesicQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Implement the heap.Interface interface.
+func (q *geodesicQueue) Push(x any) {
+	*q = append(*q, x.(geodesicCandidate))
+}
+
+// Implement the heap.Interface interface.
+func (q *geodesicQueue) Pop() any {
+	n := len(*q)
+	candidate := (*q)[n-1]
+	*q = (*q)[:n-1]
+	return candidate
+}
@@ -0,0 +1,40 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test selecting the faces within a geodesic radius.
+func TestSelectGeodesicDisk(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 4)
+
+	assert.Equal(t, []int{0}, mesh.SelectGeodesicDisk(0, 0))
+	assert.Equal(t, []int{0}, mesh.SelectGeodesicDisk(0, 1))
+	assert.Equal(t, []int{0, 1, 2, 3, 8, 10, 11}, mesh.SelectGeodesicDisk(0, 1.5))
+	assert.Len(t, mesh.SelectGeodesicDisk(0, 100), mesh.GetNumberOfFaces())
+}
+
+// Test the selection does not bleed across a gap to a nearby surface.
+func TestSelectGeodesicDiskGap(t *testing.T) {
+	source := meshSource{}
+
+	for _, z := range []float64{0, 0.01} {
+		n := len(source.vertices)
+		source.vertices = append(source.vertices,
+			meshx.NewVector(0, 0, z),
+			meshx.NewVector(1, 0, z),
+			meshx.NewVector(1, 1, z),
+			meshx.NewVector(0, 1, z),
+		)
+		source.addFace([]int{n, n + 1, n + 2}, Face{Patch: -1, Material: -1})
+		source.addFace([]int{n, n + 2, n + 3}, Face{Patch: -1, Material: -1})
+	}
+
+	mesh, err := NewHalfEdgeMesh(&source)
+	assert.Empty(t, err)
+	assert.Equal(t, []int{0, 1}, mesh.SelectGeodesicDisk(0, 10))
+	assert.Equal(t, []int{2, 3}, mesh.SelectGeodesicDisk(3, 10))
+}
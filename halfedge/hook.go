@@ -0,0 +1,124 @@
+package halfedge
+
+import (
+	"slices"
+)
+
+// Kind of mutation of a HalfEdgeMesh notified to its hooks.
+type MeshEvent int
+
+const (
+	// Vertices moved to new points (nil indices for all vertices).
+	VerticesMoved MeshEvent = iota
+
+	// Vertices appended to the mesh.
+	VerticesAdded
+
+	// Vertices removed in descending order, each replaced by the last vertex
+	// (so a cache can replay the removals).
+	VerticesDeleted
+
+	// Faces appended to the mesh.
+	FacesAdded
+
+	// Faces whose vertices changed (nil indices for all faces).
+	FacesChanged
+
+	// Faces removed in descending order, each replaced by the last face.
+	FacesDeleted
+
+	// Faces whose patch changed.
+	PatchesChanged
+
+	// The whole mesh changed (e.g. rebuilt or an edit undone), so every
+	// index may refer to a different element.
+	MeshReset
+)
+
+// Callback of the mutations of a HalfEdgeMesh with the indices of the
+// elements mutated. The indices of the events of an edit refer to the mesh
+// once the removals notified before them are applied.
+type MeshHook func(event MeshEvent, indices []int)
+
+// Hook registered on a HalfEdgeMesh by its identifier.
+type meshHook struct {
+	id   int
+	hook MeshHook
+}
+
+// Add a hook called on each mutation of the mesh (e.g. to keep an external
+// cache synchronized without rebuilding it). The identifier of the hook to
+// remove it is returned.
+func (m *HalfEdgeMesh) AddHook(hook MeshHook) int {
+	id := 0
+
+	if n := len(m.hooks); n != 0 {
+		id = m.hooks[n-1].id + 1
+	}
+
+	m.hooks = append(m.hooks, meshHook{id, hook})
+
+	return id
+}
+
+// Remove a hook by its identifier.
+func (m *HalfEdgeMesh) RemoveHook(id int) {
+	for i, hook := range m.hooks {
+		if hook.id == id {
+			m.hooks = append(m.hooks[:i:i], m.hooks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Call the hooks of a mutation.
+func (m *HalfEdgeMesh) notify(event MeshEvent, indices []int) {
+	m.updateSets(event, indices)
+
+	for _, hook := range m.hooks {
+		hook.hook(event, indices)
+	}
+}
+
+// Call the hooks of a mutation of a range of elements if not empty.
+func (m *HalfEdgeMesh) notifyRange(event MeshEvent, start, end int) {
+	if start >= end || len(m.hooks) == 0 {
+		return
+	}
+
+	indices := make([]int, end-start)
+
+	for i := range indices {
+		indices[i] = start + i
+	}
+
+	m.notify(event, indices)
+}
+
+// Call the hooks of the removal of elements (in descending order) if any.
+func (m *HalfEdgeMesh) notifyDeleted(event MeshEvent, indices []int) {
+	if len(indices) == 0 || (len(m.hooks) == 0 && len(m.sets) == 0) {
+		return
+	}
+
+	sorted := slices.Clone(indices)
+	slices.Sort(sorted)
+	slices.Reverse(sorted)
+
+	m.notify(event, sorted)
+}
+
+// Replace the mesh (in place) by another keeping its hooks and journal
+// (which is cleared) and notify the hooks. The sets are those of the other
+// mesh, remapped to its faces and vertices by newMeshSource or Extract.
+func (m *HalfEdgeMesh) replace(mesh *HalfEdgeMesh) {
+	hooks, journal := m.hooks, m.journal
+	*m = *mesh
+	m.hooks = hooks
+
+	if journal != nil {
+		m.StartJournal(journal.limit)
+	}
+
+	m.notify(MeshReset, nil)
+}
@@ -0,0 +1,101 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Event notified to a hook.
+type testEvent struct {
+	event   MeshEvent
+	indices []int
+}
+
+// Add a hook to a mesh recording the events.
+func addTestHook(mesh *HalfEdgeMesh) (int, *[]testEvent) {
+	events := make([]testEvent, 0)
+
+	id := mesh.AddHook(func(event MeshEvent, indices []int) {
+		events = append(events, testEvent{event, indices})
+	})
+
+	return id, &events
+}
+
+// Test adding and removing hooks.
+func TestAddRemoveHook(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 1)
+	first, events := addTestHook(mesh)
+	second, _ := addTestHook(mesh)
+	assert.NotEqual(t, first, second)
+
+	mesh.Translate(meshx.NewVector(1, 0, 0))
+	assert.Equal(t, []testEvent{{VerticesMoved, nil}}, *events)
+
+	mesh.RemoveHook(first)
+	mesh.Translate(meshx.NewVector(1, 0, 0))
+	assert.Equal(t, 1, len(*events))
+	assert.Equal(t, 1, len(mesh.hooks))
+}
+
+// Test the events of the edits.
+func TestHookEdits(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+	_, events := addTestHook(mesh)
+
+	index := findHalfEdge(mesh, 0, 4)
+	f1, f2 := mesh.GetHalfEdge(index).Face, mesh.GetHalfEdge(mesh.GetHalfEdge(index).Twin).Face
+	vertex, err := mesh.SplitEdge(index, meshx.NewVector(0.5, 0.5, 0))
+	assert.Empty(t, err)
+	assert.Equal(t, []testEvent{
+		{VerticesAdded, []int{vertex}},
+		{FacesChanged, []int{f1, f2}},
+		{FacesAdded, []int{8, 9}},
+	}, *events)
+
+	*events = (*events)[:0]
+	assert.Empty(t, mesh.DeleteFace(0))
+	assert.Equal(t, []testEvent{{FacesDeleted, []int{0}}}, *events)
+
+	*events = (*events)[:0]
+	mesh.SetFacePatch(1, 0)
+	assert.Equal(t, []testEvent{{PatchesChanged, []int{1}}}, *events)
+}
+
+// Test the events of collapsing an edge.
+func TestHookCollapseEdge(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+	_, events := addTestHook(mesh)
+
+	assert.Empty(t, mesh.CollapseEdge(findHalfEdge(mesh, 4, 8), meshx.NewVector(1.1, 1.1, 0)))
+	assert.Equal(t, 4, len(*events))
+	assert.Equal(t, testEvent{FacesDeleted, []int{7, 6}}, (*events)[0])
+	assert.Equal(t, testEvent{VerticesDeleted, []int{8}}, (*events)[1])
+	assert.Equal(t, testEvent{VerticesMoved, []int{4}}, (*events)[2])
+	assert.Equal(t, FacesChanged, (*events)[3].event)
+
+	for _, face := range (*events)[3].indices {
+		assert.Contains(t, mesh.GetFaceVertices(face), 4)
+	}
+}
+
+// Test the hooks are kept when the mesh is rebuilt and notified of undoing.
+func TestHookReset(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+	_, events := addTestHook(mesh)
+	mesh.StartJournal(0)
+
+	assert.Empty(t, mesh.FlipEdge(findHalfEdge(mesh, 0, 4)))
+	assert.Empty(t, mesh.Undo())
+	assert.Equal(t, testEvent{MeshReset, nil}, (*events)[1])
+
+	assert.Empty(t, mesh.DeleteFace(0))
+	_, err := mesh.FillHoles(0)
+	assert.Empty(t, err)
+	assert.Equal(t, testEvent{MeshReset, nil}, (*events)[len(*events)-1])
+	assert.Equal(t, 1, len(mesh.hooks))
+	assert.True(t, mesh.HasJournal())
+	assert.False(t, mesh.CanUndo())
+}
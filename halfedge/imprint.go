@@ -0,0 +1,283 @@
+package halfedge
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Imprint the interface of two abutting meshes (in place) so that it has
+// the same faces on both: each face in contact with the other mesh (see
+// FindContactPatches) is split into its overlaps with the faces of the other
+// mesh and the remainder. The vertices within the tolerance of each other
+// are merged and the vertices of the interface are inserted into the edges
+// they lie on, so both meshes remain watertight. The faces in contact are
+// assumed to be (nearly) coplanar. The interface faces are convex polygons,
+// which may have vertices along their edges. Neither mesh is changed if an
+// error is returned.
+func Imprint(a, b *HalfEdgeMesh, tolerance float64) error {
+	n := a.GetNumberOfFaces()
+
+	if n == 0 || b.GetNumberOfFaces() == 0 {
+		return nil
+	}
+
+	pairs := getContactPairs(a, b, tolerance)
+
+	// Skip the faces already imprinted (only in contact with an identical face).
+	for i := range n {
+		if len(pairs[i]) == 1 {
+			j := pairs[i][0]
+
+			if len(pairs[j]) == 1 && isSamePolygon(a.getFacePoints(i), b.getFacePoints(j-n), tolerance) {
+				delete(pairs, i)
+				delete(pairs, j)
+			}
+		}
+	}
+
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	pieces := make(map[int][][]meshx.Vector)
+
+	// Split the faces of a into the overlaps (shared with b) and remainders.
+	for i := range n {
+		if len(pairs[i]) == 0 {
+			continue
+		}
+
+		for _, triangle := range getSolidTriangles(a.GetFaceTriangles(i)) {
+			normal := triangle.UnitNormal()
+			remainder := [][]meshx.Vector{{triangle.P, triangle.Q, triangle.R}}
+
+			for _, j := range pairs[i] {
+				for _, other := range getSolidTriangles(b.GetFaceTriangles(j - n)) {
+					if overlap, ok := getTriangleOverlap(triangle, other, tolerance); ok {
+						pieces[i] = append(pieces[i], overlap)
+						pieces[j] = append(pieces[j], overlap)
+					}
+
+					remainder = subtractPolygons(remainder, getProjectedEdgePlanes(other, triangle.P, normal), tolerance)
+				}
+			}
+
+			pieces[i] = append(pieces[i], remainder...)
+		}
+	}
+
+	// Add the remainders of the faces of b.
+	for j := n; j < n+b.GetNumberOfFaces(); j++ {
+		if len(pairs[j]) == 0 {
+			continue
+		}
+
+		for _, triangle := range getSolidTriangles(b.GetFaceTriangles(j - n)) {
+			normal := triangle.UnitNormal()
+			remainder := [][]meshx.Vector{{triangle.P, triangle.Q, triangle.R}}
+
+			for _, i := range pairs[j] {
+				for _, other := range getSolidTriangles(a.GetFaceTriangles(i)) {
+					remainder = subtractPolygons(remainder, getProjectedEdgePlanes(other, triangle.P, normal), tolerance)
+				}
+			}
+
+			pieces[j] = append(pieces[j], remainder...)
+		}
+	}
+
+	points := make([]meshx.Vector, 0)
+	piecesA := make(map[int][][]meshx.Vector)
+	piecesB := make(map[int][][]meshx.Vector)
+
+	for face, polygons := range pieces {
+		if face < n {
+			piecesA[face] = polygons
+		} else {
+			piecesB[face-n] = polygons
+		}
+
+		for _, polygon := range polygons {
+			points = append(points, polygon...)
+		}
+	}
+
+	meshA, err := NewHalfEdgeMesh(a.getImprintSource(piecesA, points, tolerance))
+
+	if err != nil {
+		return err
+	}
+
+	meshB, err := NewHalfEdgeMesh(b.getImprintSource(piecesB, points, tolerance))
+
+	if err != nil {
+		return err
+	}
+
+	a.replace(meshA)
+	b.replace(meshB)
+
+	return nil
+}
+
+// Get the mesh source with the faces replaced by their pieces (oriented as
+// the faces). The vertices within the tolerance of each other are merged and
+// the points of the interface are inserted into the edges they lie on.
+func (m *HalfEdgeMesh) getImprintSource(pieces map[int][][]meshx.Vector, points []meshx.Vector, tolerance float64) *meshSource {
+	source := newMeshSource(m)
+	faces := source.faces
+	aabb := m.GetAABB()
+	cellSize := 2 * aabb.HalfSize.Mag() / math.Sqrt(float64(len(source.vertices)+len(points)))
+	cellSize = max(tolerance, cellSize, spatial.GetHashGridMinCellSize(aabb))
+	vertexGrid, _ := spatial.NewHashGrid(cellSize)
+	pointGrid, _ := spatial.NewHashGrid(cellSize)
+	halfSize := meshx.NewVector(tolerance, tolerance, tolerance)
+
+	for _, vertex := range source.vertices {
+		vertexGrid.InsertPoint(vertex)
+	}
+
+	for _, point := range points {
+		pointGrid.InsertPoint(point)
+	}
+
+	// Get the vertex at a point, adding it if there is none.
+	weld := func(point meshx.Vector, color meshx.Color) int {
+		for _, index := range vertexGrid.Query(meshx.NewAABB(point, halfSize)) {
+			if source.vertices[index].Sub(point).Mag() <= tolerance {
+				return index
+			}
+		}
+
+		vertexGrid.InsertPoint(point)
+		source.vertices = append(source.vertices, point)
+		source.vertexColors = append(source.vertexColors, color)
+
+		return len(source.vertices) - 1
+	}
+
+	source.faces = make([][]int, 0, len(faces))
+	source.facePatches = source.facePatches[:0]
+	source.faceMaterials = source.faceMaterials[:0]
+	source.faceColors = source.faceColors[:0]
+	source.smoothingGroups = source.smoothingGroups[:0]
+
+	for i, face := range faces {
+		polygons, ok := pieces[i]
+
+		if !ok {
+			source.addFace(face, m.faces[i])
+			continue
+		}
+
+		normal := m.GetFaceNormal(i)
+		color := m.vertices[face[0]].Color
+
+		for _, polygon := range polygons {
+			piece := make([]int, len(polygon))
+
+			for k, point := range polygon {
+				piece[k] = weld(point, color)
+			}
+
+			if getPolygonNormal(polygon).Dot(normal) < 0 {
+				slices.Reverse(piece)
+			}
+
+			source.addFace(piece, m.faces[i])
+		}
+	}
+
+	// Insert the points of the interface into the edges they lie on.
+	for i, face := range source.faces {
+		inserted := make([]int, 0, len(face))
+
+		for k, vertex := range face {
+			p := source.vertices[vertex]
+			q := source.vertices[face[(k+1)%len(face)]]
+			segment := meshx.NewSegment(p, q)
+			aabb := meshx.NewAABBFromVectors([]meshx.Vector{p, q})
+			aabb.HalfSize = aabb.HalfSize.Add(halfSize)
+			inserted = append(inserted, vertex)
+
+			onEdge := make([]meshx.Vector, 0)
+
+			for _, index := range pointGrid.Query(aabb) {
+				point := points[index]
+
+				if point.Sub(p).Mag() > tolerance && point.Sub(q).Mag() > tolerance && segment.DistanceTo(point) <= tolerance {
+					onEdge = append(onEdge, point)
+				}
+			}
+
+			slices.SortFunc(onEdge, func(u, v meshx.Vector) int {
+				return cmp.Compare(u.Sub(p).Mag(), v.Sub(p).Mag())
+			})
+
+			for _, point := range onEdge {
+				if index := weld(point, source.vertexColors[vertex]); index != inserted[len(inserted)-1] {
+					inserted = append(inserted, index)
+				}
+			}
+		}
+
+		source.faces[i] = inserted
+	}
+
+	source.removeUnusedVertices()
+
+	return source
+}
+
+// Return true if each point of two polygons is within the tolerance of a
+// point of the other.
+func isSamePolygon(p, q []meshx.Vector, tolerance float64) bool {
+	contains := func(points []meshx.Vector, point meshx.Vector) bool {
+		return slices.ContainsFunc(points, func(other meshx.Vector) bool {
+			return other.Sub(point).Mag() <= tolerance
+		})
+	}
+
+	for _, point := range p {
+		if !contains(q, point) {
+			return false
+		}
+	}
+
+	for _, point := range q {
+		if !contains(p, point) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Subtract the convex polygon bounded by planes (origin and outward normal)
+// from convex polygons. The difference is split into convex polygons and
+// slivers narrower than the tolerance are dropped.
+func subtractPolygons(polygons [][]meshx.Vector, planes [3][2]meshx.Vector, tolerance float64) [][]meshx.Vector {
+	difference := make([][]meshx.Vector, 0, len(polygons))
+
+	for _, polygon := range polygons {
+		inside := polygon
+
+		for _, plane := range planes {
+			outside := clipPolygon(inside, plane[0], plane[1].MulScalar(-1))
+
+			if !isSliver(outside, tolerance) {
+				difference = append(difference, outside)
+			}
+
+			if inside = clipPolygon(inside, plane[0], plane[1]); len(inside) < 3 {
+				break
+			}
+		}
+	}
+
+	return difference
+}
@@ -0,0 +1,76 @@
+package halfedge
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Get the faces of a mesh in a box as sorted rounded points of each face.
+func getImprintFaces(mesh *HalfEdgeMesh, aabb meshx.AABB) [][]meshx.Vector {
+	faces := make([][]meshx.Vector, 0)
+
+	for i := range mesh.GetNumberOfFaces() {
+		if aabb.DistanceTo(mesh.GetFaceCentroid(i)) > 0 {
+			continue
+		}
+
+		points := mesh.getFacePoints(i)
+
+		for k, point := range points {
+			for l := range 3 {
+				points[k][l] = math.Round(point[l]*1e9) / 1e9
+			}
+		}
+
+		slices.SortFunc(points, func(u, v meshx.Vector) int {
+			return slices.Compare(u[:], v[:])
+		})
+
+		faces = append(faces, points)
+	}
+
+	slices.SortFunc(faces, func(u, v []meshx.Vector) int {
+		return slices.CompareFunc(u, v, func(p, q meshx.Vector) int {
+			return slices.Compare(p[:], q[:])
+		})
+	})
+
+	return faces
+}
+
+// Test imprinting a cube on another cube resting on part of its top.
+func TestImprint(t *testing.T) {
+	for _, offset := range []meshx.Vector{{0.5, 0.5, 1}, {0.3, 0.6, 1}} {
+		a := newTestCube(t)
+		b := newTestCube(t)
+		b.Translate(offset)
+		areaA, areaB := a.Stats().Area, b.Stats().Area
+
+		assert.Empty(t, Imprint(a, b, 1e-9))
+
+		for _, mesh := range []*HalfEdgeMesh{a, b} {
+			assert.Empty(t, mesh.GetBoundaryLoops())
+			assert.True(t, mesh.IsConsistent())
+		}
+
+		assert.InDelta(t, areaA, a.Stats().Area, 1e-12)
+		assert.InDelta(t, areaB, b.Stats().Area, 1e-12)
+
+		// The faces of the interface are identical.
+		center := meshx.NewVector(1+offset[0], 1+offset[1], 2).MulScalar(0.5)
+		halfSize := meshx.NewVector(1-offset[0], 1-offset[1], 1e-6).MulScalar(0.5)
+		interfaceAABB := meshx.NewAABB(center, halfSize)
+		facesA := getImprintFaces(a, interfaceAABB)
+		assert.NotEmpty(t, facesA)
+		assert.Equal(t, facesA, getImprintFaces(b, interfaceAABB))
+
+		// Imprinting again changes nothing.
+		n := a.GetNumberOfFaces()
+		assert.Empty(t, Imprint(a, b, 1e-9))
+		assert.Equal(t, n, a.GetNumberOfFaces())
+	}
+}
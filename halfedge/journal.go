@@ -0,0 +1,321 @@
+package halfedge
+
+import (
+	"errors"
+)
+
+var (
+	ErrNothingToUndo      = errors.New("nothing to undo")
+	ErrNothingToRedo      = errors.New("nothing to redo")
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+	ErrJournalMismatch    = errors.New("mesh changed outside the journal")
+)
+
+// Journal of the edits of a HalfEdgeMesh (see CollapseEdge, SplitEdge,
+// FlipEdge and DeleteFace) to undo and redo them. Each edit records the
+// elements it changes, so undoing an edit is proportional to its size rather
+// than that of the mesh.
+type journal struct {
+	limit       int
+	undo        []*meshEdit
+	redo        []*meshEdit
+	checkpoints map[string]int
+}
+
+// Elements of a mesh changed by an edit: the number of vertices, faces and
+// half edges and the values of the changed elements before (0) and after (1)
+// the edit.
+type meshEdit struct {
+	name      string
+	lengths   [2][3]int
+	vertices  [2]map[int]Vertex
+	faces     [2]map[int]Face
+	halfEdges [2]map[int]HalfEdge
+}
+
+// Start recording the edits to undo up to a limit of edits (unlimited if
+// zero). A journal already started is cleared. Changes to the mesh other
+// than the edits are not recorded and must not be made while recording.
+func (m *HalfEdgeMesh) StartJournal(limit int) {
+	m.journal = &journal{
+		limit:       max(0, limit),
+		undo:        make([]*meshEdit, 0),
+		redo:        make([]*meshEdit, 0),
+		checkpoints: make(map[string]int),
+	}
+}
+
+// Stop recording the edits and discard the journal.
+func (m *HalfEdgeMesh) StopJournal() {
+	m.journal = nil
+}
+
+// Return true if the edits are recorded.
+func (m *HalfEdgeMesh) HasJournal() bool {
+	return m.journal != nil
+}
+
+// Return true if there is an edit to undo.
+func (m *HalfEdgeMesh) CanUndo() bool {
+	return m.journal != nil && len(m.journal.undo) != 0
+}
+
+// Return true if there is an edit to redo.
+func (m *HalfEdgeMesh) CanRedo() bool {
+	return m.journal != nil && len(m.journal.redo) != 0
+}
+
+// Get the names of the edits to undo from the first to the last.
+func (m *HalfEdgeMesh) GetUndoHistory() []string {
+	if m.journal == nil {
+		return []string{}
+	}
+
+	names := make([]string, len(m.journal.undo))
+
+	for i, edit := range m.journal.undo {
+		names[i] = edit.name
+	}
+
+	return names
+}
+
+// Undo the last edit.
+func (m *HalfEdgeMesh) Undo() error {
+	if !m.CanUndo() {
+		return ErrNothingToUndo
+	}
+
+	n := len(m.journal.undo)
+	edit := m.journal.undo[n-1]
+
+	if err := edit.apply(m, 0); err != nil {
+		return err
+	}
+
+	m.journal.undo = m.journal.undo[:n-1]
+	m.journal.redo = append(m.journal.redo, edit)
+	m.notify(MeshReset, nil)
+
+	return nil
+}
+
+// Redo the last edit undone. The edits undone are discarded by a new edit.
+func (m *HalfEdgeMesh) Redo() error {
+	if !m.CanRedo() {
+		return ErrNothingToRedo
+	}
+
+	n := len(m.journal.redo)
+	edit := m.journal.redo[n-1]
+
+	if err := edit.apply(m, 1); err != nil {
+		return err
+	}
+
+	m.journal.redo = m.journal.redo[:n-1]
+	m.journal.undo = append(m.journal.undo, edit)
+	m.notify(MeshReset, nil)
+
+	return nil
+}
+
+// Name the current state of the mesh to roll back to. A checkpoint of the
+// same name is replaced.
+func (m *HalfEdgeMesh) Checkpoint(name string) {
+	if m.journal == nil {
+		m.StartJournal(0)
+	}
+
+	m.journal.checkpoints[name] = len(m.journal.undo)
+}
+
+// Undo the edits since a checkpoint. The edits undone can be redone.
+func (m *HalfEdgeMesh) RollbackToCheckpoint(name string) error {
+	if m.journal == nil {
+		return ErrCheckpointNotFound
+	}
+
+	position, ok := m.journal.checkpoints[name]
+	if !ok || position > len(m.journal.undo) {
+		return ErrCheckpointNotFound
+	}
+
+	for len(m.journal.undo) > position {
+		if err := m.Undo(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start recording an edit if journaling. The elements must be saved before
+// they are changed.
+func (m *HalfEdgeMesh) beginEdit(name string) {
+	if m.journal == nil {
+		return
+	}
+
+	m.edit = &meshEdit{name: name}
+	m.edit.lengths[0] = m.getLengths()
+
+	for i := range 2 {
+		m.edit.vertices[i] = make(map[int]Vertex)
+		m.edit.faces[i] = make(map[int]Face)
+		m.edit.halfEdges[i] = make(map[int]HalfEdge)
+	}
+}
+
+// Complete an edit and push it to the journal. The indexed edges are
+// invalidated by the edit.
+func (m *HalfEdgeMesh) endEdit() {
+	m.resetEdges()
+
+	if m.edit == nil {
+		return
+	}
+
+	edit := m.edit
+	m.edit = nil
+	edit.lengths[1] = m.getLengths()
+
+	for index := range edit.vertices[0] {
+		if index < len(m.vertices) {
+			edit.vertices[1][index] = m.vertices[index]
+		}
+	}
+
+	for index := edit.lengths[0][0]; index < len(m.vertices); index++ {
+		edit.vertices[1][index] = m.vertices[index]
+	}
+
+	for index := range edit.faces[0] {
+		if index < len(m.faces) {
+			edit.faces[1][index] = m.faces[index]
+		}
+	}
+
+	for index := edit.lengths[0][1]; index < len(m.faces); index++ {
+		edit.faces[1][index] = m.faces[index]
+	}
+
+	for index := range edit.halfEdges[0] {
+		if index < len(m.halfEdges) {
+			edit.halfEdges[1][index] = m.halfEdges[index]
+		}
+	}
+
+	for index := edit.lengths[0][2]; index < len(m.halfEdges); index++ {
+		edit.halfEdges[1][index] = m.halfEdges[index]
+	}
+
+	m.journal.undo = append(m.journal.undo, edit)
+	m.journal.redo = m.journal.redo[:0]
+
+	if m.journal.limit > 0 && len(m.journal.undo) > m.journal.limit {
+		m.journal.undo = m.journal.undo[1:]
+
+		for name, position := range m.journal.checkpoints {
+			if position == 0 {
+				delete(m.journal.checkpoints, name)
+			} else {
+				m.journal.checkpoints[name] = position - 1
+			}
+		}
+	}
+}
+
+// Get the number of vertices, faces and half edges.
+func (m *HalfEdgeMesh) getLengths() [3]int {
+	return [3]int{len(m.vertices), len(m.faces), len(m.halfEdges)}
+}
+
+// Set a vertex saving its value if recording an edit.
+func (m *HalfEdgeMesh) setVertex(index int, vertex Vertex) {
+	m.saveVertex(index)
+	m.vertices[index] = vertex
+}
+
+// Set a face saving its value if recording an edit.
+func (m *HalfEdgeMesh) setFace(index int, face Face) {
+	m.saveFace(index)
+	m.faces[index] = face
+}
+
+// Set a half edge saving its value if recording an edit.
+func (m *HalfEdgeMesh) setHalfEdge(index int, halfEdge HalfEdge) {
+	m.saveHalfEdge(index)
+	m.halfEdges[index] = halfEdge
+}
+
+// Save the value of a vertex before it is changed by an edit.
+func (m *HalfEdgeMesh) saveVertex(index int) {
+	if m.edit == nil || index >= m.edit.lengths[0][0] {
+		return
+	}
+
+	if _, ok := m.edit.vertices[0][index]; !ok {
+		m.edit.vertices[0][index] = m.vertices[index]
+	}
+}
+
+// Save the value of a face before it is changed by an edit.
+func (m *HalfEdgeMesh) saveFace(index int) {
+	if m.edit == nil || index >= m.edit.lengths[0][1] {
+		return
+	}
+
+	if _, ok := m.edit.faces[0][index]; !ok {
+		m.edit.faces[0][index] = m.faces[index]
+	}
+}
+
+// Save the value of a half edge before it is changed by an edit.
+func (m *HalfEdgeMesh) saveHalfEdge(index int) {
+	if m.edit == nil || index >= m.edit.lengths[0][2] {
+		return
+	}
+
+	if _, ok := m.edit.halfEdges[0][index]; !ok {
+		m.edit.halfEdges[0][index] = m.halfEdges[index]
+	}
+}
+
+// Restore the state of the mesh before (0) or after (1) an edit. An error is
+// returned if the mesh is not in the opposite state.
+func (e *meshEdit) apply(m *HalfEdgeMesh, state int) error {
+	if m.getLengths() != e.lengths[1-state] {
+		return ErrJournalMismatch
+	}
+
+	lengths := e.lengths[state]
+	m.vertices = resizeSlice(m.vertices, lengths[0])
+	m.faces = resizeSlice(m.faces, lengths[1])
+	m.halfEdges = resizeSlice(m.halfEdges, lengths[2])
+
+	for index, vertex := range e.vertices[state] {
+		m.vertices[index] = vertex
+	}
+
+	for index, face := range e.faces[state] {
+		m.faces[index] = face
+	}
+
+	for index, halfEdge := range e.halfEdges[state] {
+		m.halfEdges[index] = halfEdge
+	}
+
+	m.resetEdges()
+
+	return nil
+}
+
+// Resize a slice truncating it or extending it with zero values.
+func resizeSlice[T any](values []T, n int) []T {
+	if n <= len(values) {
+		return values[:n]
+	}
+
+	return append(values, make([]T, n-len(values))...)
+}
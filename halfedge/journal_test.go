@@ -0,0 +1,98 @@
+package halfedge
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Copy the elements of a mesh to compare.
+func copyElements(mesh *HalfEdgeMesh) ([]Vertex, []Face, []HalfEdge) {
+	return slices.Clone(mesh.vertices), slices.Clone(mesh.faces), slices.Clone(mesh.halfEdges)
+}
+
+// Test undoing and redoing a sequence of edits.
+func TestJournalUndoRedo(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 3)
+	mesh.StartJournal(0)
+	vertices, faces, halfEdges := copyElements(mesh)
+
+	_, err := mesh.SplitEdge(findHalfEdge(mesh, 5, 10), meshx.NewVector(1.5, 1.5, 0))
+	assert.Empty(t, err)
+	assert.Empty(t, mesh.FlipEdge(findHalfEdge(mesh, 0, 5)))
+	assert.Empty(t, mesh.CollapseEdge(findHalfEdge(mesh, 6, 16), meshx.NewVector(2, 1.5, 0)))
+	assert.Empty(t, mesh.DeleteFace(0))
+	assertValidTopology(t, mesh)
+
+	assert.Equal(t, []string{"split", "flip", "collapse", "delete"}, mesh.GetUndoHistory())
+	edited := mesh.GetNumberOfEdges()
+	editedVertices, editedFaces, editedHalfEdges := copyElements(mesh)
+
+	for mesh.CanUndo() {
+		assert.Empty(t, mesh.Undo())
+		assertValidTopology(t, mesh)
+	}
+
+	assert.ErrorIs(t, mesh.Undo(), ErrNothingToUndo)
+	assert.Equal(t, vertices, mesh.vertices)
+	assert.Equal(t, faces, mesh.faces)
+	assert.Equal(t, halfEdges, mesh.halfEdges)
+
+	for mesh.CanRedo() {
+		assert.Empty(t, mesh.Redo())
+	}
+
+	assert.ErrorIs(t, mesh.Redo(), ErrNothingToRedo)
+	assert.Equal(t, editedVertices, mesh.vertices)
+	assert.Equal(t, editedFaces, mesh.faces)
+	assert.Equal(t, editedHalfEdges, mesh.halfEdges)
+	assert.Equal(t, edited, mesh.GetNumberOfEdges())
+
+	assert.Empty(t, mesh.Undo())
+	assert.Empty(t, mesh.FlipEdge(findHalfEdge(mesh, 10, 15)))
+	assert.False(t, mesh.CanRedo())
+}
+
+// Test rolling back to a checkpoint and limiting the edits to undo.
+func TestJournalCheckpoint(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+	mesh.StartJournal(3)
+
+	assert.Empty(t, mesh.FlipEdge(findHalfEdge(mesh, 0, 4)))
+	mesh.Checkpoint("flipped")
+	_, faces, _ := copyElements(mesh)
+
+	_, err := mesh.SplitEdge(findHalfEdge(mesh, 4, 8), meshx.NewVector(1.5, 1.5, 0))
+	assert.Empty(t, err)
+	assert.Empty(t, mesh.DeleteFace(0))
+
+	assert.Empty(t, mesh.RollbackToCheckpoint("flipped"))
+	assert.Equal(t, faces, mesh.faces)
+	assert.Equal(t, []string{"flip"}, mesh.GetUndoHistory())
+	assert.ErrorIs(t, mesh.RollbackToCheckpoint("missing"), ErrCheckpointNotFound)
+
+	// The flip is dropped beyond the limit but the checkpoint is reachable.
+	for range 3 {
+		_, err := mesh.SplitEdge(0, meshx.NewVector(0, 0, 0))
+		assert.Empty(t, err)
+	}
+
+	assert.Equal(t, []string{"split", "split", "split"}, mesh.GetUndoHistory())
+	assert.Empty(t, mesh.RollbackToCheckpoint("flipped"))
+	assert.Equal(t, faces, mesh.faces)
+
+	for range 4 {
+		_, err := mesh.SplitEdge(0, meshx.NewVector(0, 0, 0))
+		assert.Empty(t, err)
+	}
+
+	assert.ErrorIs(t, mesh.RollbackToCheckpoint("flipped"), ErrCheckpointNotFound)
+
+	mesh.vertices = mesh.vertices[:1]
+	assert.ErrorIs(t, mesh.Undo(), ErrJournalMismatch)
+
+	mesh.StopJournal()
+	assert.False(t, mesh.CanUndo())
+}
@@ -0,0 +1,370 @@
+package halfedge
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/curve"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+var (
+	ErrInvalidLeakPoint = errors.New("leak point is on the surface")
+)
+
+// Default number of voxels along the longest side of the AABB.
+const DefaultLeakResolution = 128
+
+// Default maximum number of leaks to find.
+const DefaultMaxLeaks = 16
+
+// Options to find the leaks of a nominally enclosed mesh.
+type LeakOptions struct {
+	// Edge length of the voxels. Gaps narrower than about a voxel are
+	// considered closed. The default is the longest side of the AABB divided
+	// by DefaultLeakResolution.
+	VoxelSize float64
+
+	// Maximum number of leaks to find. The default is DefaultMaxLeaks.
+	MaxLeaks int
+
+	// Regions with a local voxel size. The voxels are fine only where the
+	// regions require it and the gaps narrower than about the local size are
+	// considered closed.
+	Refinements []RefinementRegion
+
+	// Maximum number of voxels. ErrTooManyVoxels is returned if the voxel
+	// size is too small for the mesh and points. The default is
+	// DefaultMaxVoxels.
+	MaxVoxels int
+}
+
+// Leak from the interior to the exterior of a nominally enclosed mesh.
+type Leak struct {
+	// Location of the gap (near the center of the opening).
+	Point meshx.Vector
+
+	// Approximate width of the gap.
+	Width float64
+
+	// Path from the interior point through the gap to the exterior point.
+	Path curve.Polyline
+}
+
+// Find the leaks between an interior and an exterior point. See
+// FindLeaksWithOptions.
+func (m *HalfEdgeMesh) FindLeaks(interior, exterior meshx.Vector) ([]Leak, error) {
+	return m.FindLeaksWithOptions(interior, exterior, LeakOptions{})
+}
+
+// Find the gaps through which a path exists from an interior point to an
+// exterior point of a nominally enclosed mesh (e.g. multiple components
+// that should form a watertight region). The faces are voxelized on an
+// adaptive octree, fine only where the refinement regions require it, and
+// the clearance of each empty voxel is the distance from its center to the
+// closest occupied voxel. The widest path of empty voxels to the exterior
+// point is found and the gap is located where the path leaves the mesh (the
+// generalized winding number falls below one half), moved to the center of
+// the opening. The gap is reported as a leak and plugged with a cube of
+// voxels, and the search repeats until no path remains (a long slit may be
+// reported more than once). No leaks are returned for a watertight mesh.
+func (m *HalfEdgeMesh) FindLeaksWithOptions(interior, exterior meshx.Vector, options LeakOptions) ([]Leak, error) {
+	leaks := make([]Leak, 0)
+
+	if m.GetNumberOfFaces() == 0 {
+		return leaks, nil
+	}
+
+	aabb := m.GetAABB()
+	size := options.VoxelSize
+	maxLeaks := options.MaxLeaks
+
+	if size <= 0 {
+		size = 2 * max(aabb.HalfSize[0], aabb.HalfSize[1], aabb.HalfSize[2]) / DefaultLeakResolution
+	}
+
+	if maxLeaks <= 0 {
+		maxLeaks = DefaultMaxLeaks
+	}
+
+	aabb = meshx.NewAABBFromVectors([]meshx.Vector{
+		aabb.Center.Sub(aabb.HalfSize),
+		aabb.Center.Add(aabb.HalfSize),
+		interior,
+		exterior,
+	})
+
+	// Pad the octree so paths may pass around the mesh.
+	coarse := getMaxRefinementSize(options.Refinements, size)
+	padding := meshx.NewVector(2*coarse, 2*coarse, 2*coarse)
+	aabb = meshx.NewAABB(aabb.Center, aabb.HalfSize.Add(padding))
+
+	triangles := m.GetTriangles()
+	tree, err := newVoxelTree(aabb, triangles, options.Refinements, size, options.MaxVoxels)
+	if err != nil {
+		return nil, err
+	}
+
+	source, _ := tree.findPoint(interior)
+	target, _ := tree.findPoint(exterior)
+
+	if tree.occupied[source] || tree.occupied[target] {
+		return nil, ErrInvalidLeakPoint
+	}
+
+	windingNumbers := spatial.NewWindingNumberTree(triangles)
+	occupied := tree.getOccupiedOctree()
+	clearances := tree.getClearances(occupied)
+
+	for len(leaks) < maxLeaks {
+		path := tree.getWidestPath(clearances, source, target)
+
+		if path == nil {
+			break
+		}
+
+		point, clearance, size := tree.getGap(windingNumbers, occupied, clearances, path)
+		points := make([]meshx.Vector, len(path))
+
+		for i, index := range path {
+			points[i] = tree.getCenter(index)
+		}
+
+		points[0], points[len(points)-1] = interior, exterior
+
+		// The faces are on average half a voxel inside the occupied voxels.
+		leaks = append(leaks, Leak{
+			Point: point,
+			Width: 2*clearance + size,
+			Path:  curve.NewPolyline(points, false),
+		})
+
+		// Plug the gap with a cube of voxels wider than the gap.
+		extent := 2*clearance + size
+		plug := meshx.NewAABB(point, meshx.NewVector(extent, extent, extent))
+
+		for _, index := range tree.query(plug) {
+			if index != source && index != target {
+				tree.occupied[index] = true
+			}
+		}
+	}
+
+	return leaks, nil
+}
+
+// Get the gap along a path from the interior to the exterior: the point
+// where the path leaves the mesh (the winding number falls below one half),
+// moved within the plane across the path to the point of locally largest
+// clearance (the center of the opening) by steps of the smallest voxel of
+// the path. The center of the narrowest voxel of the path is used if the
+// path never leaves the mesh. The point, its clearance and the step are
+// returned.
+func (t *voxelTree) getGap(windingNumbers *spatial.WindingNumberTree, occupied *spatial.Octree, clearances []float64, path []int) (meshx.Vector, float64, float64) {
+	getWindingNumber := func(point meshx.Vector) float64 {
+		return math.Abs(windingNumbers.Evaluate(point))
+	}
+
+	crossing := -1
+	step := math.Inf(1)
+
+	for i, index := range path {
+		step = min(step, t.getLeafSize(index))
+
+		if crossing == -1 && i > 0 && getWindingNumber(t.getCenter(index)) < 0.5 {
+			crossing = i
+		}
+	}
+
+	if crossing == -1 {
+		narrowest := path[0]
+
+		for _, index := range path {
+			if clearances[index] < clearances[narrowest] {
+				narrowest = index
+			}
+		}
+
+		return t.getCenter(narrowest), clearances[narrowest], step
+	}
+
+	// Interpolate the point where the winding number is one half between
+	// the centers of the voxels before and after the crossing.
+	a, b := t.getCenter(path[crossing-1]), t.getCenter(path[crossing])
+	wa, wb := getWindingNumber(a), getWindingNumber(b)
+	point := a.Add(b.Sub(a).MulScalar(max(0, min(1, (wa-0.5)/(wa-wb)))))
+
+	// Directions within the plane across the path.
+	direction := t.getCenter(path[min(crossing+2, len(path)-1)]).Sub(t.getCenter(path[max(crossing-2, 0)])).Unit()
+	u := direction.Cross(meshx.NewVector(1, 0, 0))
+
+	if u.Mag() < 0.5 {
+		u = direction.Cross(meshx.NewVector(0, 1, 0))
+	}
+
+	u = u.Unit()
+	v := direction.Cross(u)
+	offsets := make([]meshx.Vector, 0, 8)
+
+	for _, i := range [3]float64{-1, 0, 1} {
+		for _, j := range [3]float64{-1, 0, 1} {
+			if i != 0 || j != 0 {
+				offsets = append(offsets, u.MulScalar(i*step).Add(v.MulScalar(j*step)))
+			}
+		}
+	}
+
+	clearance := getClearance(occupied, point)
+
+	for {
+		next, nextClearance := point, clearance
+
+		for _, offset := range offsets {
+			candidate := point.Add(offset)
+
+			if c := getClearance(occupied, candidate); c > nextClearance {
+				if w := getWindingNumber(candidate); w > 0.25 && w < 0.75 {
+					next, nextClearance = candidate, c
+				}
+			}
+		}
+
+		if next == point {
+			return point, clearance, step
+		}
+
+		point, clearance = next, nextClearance
+	}
+}
+
+// Build an octree of the occupied voxels.
+func (t *voxelTree) getOccupiedOctree() *spatial.Octree {
+	root := t.getAABB(voxelKey{})
+	octree := spatial.NewOctree(root.Buffer(0.01))
+
+	for index, key := range t.leaves {
+		if t.occupied[index] {
+			octree.Insert(t.getAABB(key))
+		}
+	}
+
+	return octree
+}
+
+// Get the clearance of a point: the distance to the closest occupied voxel
+// of the octree. Without occupied voxels, the clearance is infinite.
+func getClearance(occupied *spatial.Octree, point meshx.Vector) float64 {
+	if nearest, closest := occupied.QueryNearest(point); nearest != -1 {
+		return closest.Sub(point).Mag()
+	}
+
+	return math.Inf(1)
+}
+
+// Get the clearance of each voxel: the clearance of its center (zero for an
+// occupied voxel).
+func (t *voxelTree) getClearances(occupied *spatial.Octree) []float64 {
+	clearances := make([]float64, len(t.leaves))
+
+	for index := range clearances {
+		if !t.occupied[index] {
+			clearances[index] = getClearance(occupied, t.getCenter(index))
+		}
+	}
+
+	return clearances
+}
+
+// Get the path of face-connected empty voxels from the source to the target
+// that maximizes the smallest clearance along it. Nil is returned if the
+// target is not reachable.
+func (t *voxelTree) getWidestPath(clearances []float64, source, target int) []int {
+	widths := make([]float64, len(t.leaves))
+	parents := make([]int, len(t.leaves))
+	visited := make([]bool, len(t.leaves))
+
+	widths[source] = clearances[source]
+	parents[source] = -1
+	queue := voxelQueue{{source, widths[source]}}
+
+	for queue.Len() > 0 {
+		candidate := heap.Pop(&queue).(voxelCandidate)
+		index := candidate.index
+
+		if visited[index] || candidate.width != widths[index] {
+			continue
+		}
+
+		visited[index] = true
+
+		if index == target {
+			break
+		}
+
+		for _, neighbor := range t.getNeighbors(index) {
+			width := min(candidate.width, clearances[neighbor])
+
+			if !visited[neighbor] && !t.occupied[neighbor] && width > widths[neighbor] {
+				widths[neighbor] = width
+				parents[neighbor] = index
+				heap.Push(&queue, voxelCandidate{neighbor, width})
+			}
+		}
+	}
+
+	if !visited[target] {
+		return nil
+	}
+
+	path := make([]int, 0)
+
+	for index := target; index != -1; index = parents[index] {
+		path = append(path, index)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// Voxel queued with the width of the widest path to it.
+type voxelCandidate struct {
+	index int
+	width float64
+}
+
+// Priority queue of voxels ordered by the width of the path to them
+// (widest first).
+type voxelQueue []voxelCandidate
+
+// Implement the heap.Interface interface.
+func (q voxelQueue) Len() int {
+	return len(q)
+}
+
+// Implement the heap.Interface interface.
+func (q voxelQueue) Less(i, j int) bool {
+	return q[i].width > q[j].width
+}
+
+// Implement the heap.Interface interface.
+func (q voxelQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Implement the heap.Interface interface.
+func (q *voxelQueue) Push(x any) {
+	*q = append(*q, x.(voxelCandidate))
+}
+
+// Implement the heap.Interface interface.
+func (q *voxelQueue) Pop() any {
+	n := len(*q)
+	candidate := (*q)[n-1]
+	*q = (*q)[:n-1]
+	return candidate
+}
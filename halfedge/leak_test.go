@@ -0,0 +1,69 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Get the centroid of the vertices of a face.
+func getTestFaceCenter(mesh *HalfEdgeMesh, face int) meshx.Vector {
+	var center meshx.Vector
+
+	points := mesh.getFacePoints(face)
+
+	for _, point := range points {
+		center = center.Add(point)
+	}
+
+	return center.DivScalar(float64(len(points)))
+}
+
+// Test finding the gaps in a cylinder missing side faces.
+func TestFindLeaks(t *testing.T) {
+	mesh := newTestCylinder(t, 1, 0, 0.4)
+	interior := meshx.NewVector(0, 0, 0.2)
+	exterior := meshx.NewVector(2, 2, 0.2)
+	options := LeakOptions{VoxelSize: 0.02}
+
+	leaks, err := mesh.FindLeaksWithOptions(interior, exterior, options)
+	assert.Empty(t, err)
+	assert.Equal(t, 0, len(leaks))
+
+	// Remove two side faces on opposite sides.
+	faces := make([]int, 0)
+	removed := make([]meshx.Vector, 0)
+
+	for i := range mesh.GetNumberOfFaces() {
+		if i == 16 || i == 24 {
+			removed = append(removed, getTestFaceCenter(mesh, i))
+		} else {
+			faces = append(faces, i)
+		}
+	}
+
+	leaks, err = mesh.Extract(faces).FindLeaksWithOptions(interior, exterior, options)
+	assert.Empty(t, err)
+	assert.Equal(t, 2, len(leaks))
+
+	for _, leak := range leaks {
+		distance := min(leak.Point.Sub(removed[0]).Mag(), leak.Point.Sub(removed[1]).Mag())
+		assert.Less(t, distance, 0.1)
+		assert.Greater(t, leak.Width, 0.2)
+		assert.Less(t, leak.Width, 0.5)
+		assert.Equal(t, interior, leak.Path.Points[0])
+		assert.Equal(t, exterior, leak.Path.Points[len(leak.Path.Points)-1])
+	}
+
+	options.MaxLeaks = 1
+	leaks, err = mesh.Extract(faces).FindLeaksWithOptions(interior, exterior, options)
+	assert.Empty(t, err)
+	assert.Equal(t, 1, len(leaks))
+
+	_, err = mesh.FindLeaks(meshx.NewVector(0, 0, 0), exterior)
+	assert.ErrorIs(t, err, ErrInvalidLeakPoint)
+
+	_, err = mesh.FindLeaksWithOptions(interior, exterior, LeakOptions{VoxelSize: 1e-6})
+	assert.ErrorIs(t, err, ErrTooManyVoxels)
+}
@@ -0,0 +1,169 @@
+package halfedge
+
+import (
+	"errors"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrOpenSurface    = errors.New("surface is not closed")
+	ErrInvalidDensity = errors.New("invalid density")
+)
+
+// Mass properties of a closed solid of uniform density.
+type MassProperties struct {
+	Volume       float64
+	Mass         float64
+	CenterOfMass meshx.Vector
+
+	// Inertia tensor about the center of mass (the products of inertia are
+	// negated off the diagonal).
+	Inertia [3][3]float64
+}
+
+// Volume integrals of 1, x, y, z, x^2, y^2, z^2, xy, yz and zx.
+type volumeIntegrals [10]float64
+
+// Compute the mass properties of the solid enclosed by the faces with a
+// uniform density. Each component encloses a solid, or a cavity if it is
+// nested inside an odd number of other components (e.g. a hollow part), and
+// is counted with the sign of its nesting regardless of its orientation. An
+// error is returned if the mesh is not closed or the density is not
+// positive. Baffles are ignored.
+func (m *HalfEdgeMesh) ComputeMassProperties(density float64) (MassProperties, error) {
+	var properties MassProperties
+
+	if !(density > 0) {
+		return properties, ErrInvalidDensity
+	}
+
+	if !m.IsClosed() {
+		return properties, ErrOpenSurface
+	}
+
+	if m.GetNumberOfFaces() == 0 {
+		return properties, nil
+	}
+
+	// The integrals are computed relative to the center of the AABB to limit
+	// the cancellation far from the origin.
+	solid := m.getEnclosingMesh()
+	reference := solid.GetAABB().Center
+	components := solid.GetComponents()
+	depths := solid.getNestingDepths(components)
+	var integrals volumeIntegrals
+
+	for i, faces := range components {
+		component := solid.getVolumeIntegrals(faces, reference)
+		sign := 1.0
+
+		if (component[0] < 0) == (depths[i]%2 == 0) {
+			sign = -1
+		}
+
+		for j := range integrals {
+			integrals[j] += sign * component[j]
+		}
+	}
+
+	volume := integrals[0]
+
+	if volume <= 0 {
+		return properties, nil
+	}
+
+	center := meshx.NewVector(integrals[1], integrals[2], integrals[3]).DivScalar(volume)
+	xx := integrals[4]/volume - center[0]*center[0]
+	yy := integrals[5]/volume - center[1]*center[1]
+	zz := integrals[6]/volume - center[2]*center[2]
+	xy := integrals[7]/volume - center[0]*center[1]
+	yz := integrals[8]/volume - center[1]*center[2]
+	zx := integrals[9]/volume - center[2]*center[0]
+	mass := density * volume
+
+	properties.Volume = volume
+	properties.Mass = mass
+	properties.CenterOfMass = center.Add(reference)
+	properties.Inertia = [3][3]float64{
+		{mass * (yy + zz), -mass * xy, -mass * zx},
+		{-mass * xy, mass * (zz + xx), -mass * yz},
+		{-mass * zx, -mass * yz, mass * (xx + yy)},
+	}
+
+	return properties, nil
+}
+
+// Compute the signed volume integrals of the faces relative to a reference
+// point by the divergence theorem (the sum over the tetrahedra of each
+// triangle and the reference point).
+func (m *HalfEdgeMesh) getVolumeIntegrals(faces []int, reference meshx.Vector) volumeIntegrals {
+	var integrals volumeIntegrals
+
+	for _, face := range faces {
+		for _, triangle := range m.GetFaceTriangles(face) {
+			a := triangle.P.Sub(reference)
+			b := triangle.Q.Sub(reference)
+			c := triangle.R.Sub(reference)
+			det := a.Dot(b.Cross(c))
+			sum := a.Add(b).Add(c)
+
+			// The integral of x_i x_j over the tetrahedron of the origin and
+			// a, b, c is det / 120 (a_i a_j + b_i b_j + c_i c_j + s_i s_j)
+			// with s = a + b + c.
+			second := func(i, j int) float64 {
+				return det / 120 * (a[i]*a[j] + b[i]*b[j] + c[i]*c[j] + sum[i]*sum[j])
+			}
+
+			integrals[0] += det / 6
+			integrals[1] += det / 24 * sum[0]
+			integrals[2] += det / 24 * sum[1]
+			integrals[3] += det / 24 * sum[2]
+			integrals[4] += second(0, 0)
+			integrals[5] += second(1, 1)
+			integrals[6] += second(2, 2)
+			integrals[7] += second(0, 1)
+			integrals[8] += second(1, 2)
+			integrals[9] += second(2, 0)
+		}
+	}
+
+	return integrals
+}
+
+// Get the number of other components enclosing each component.
+func (m *HalfEdgeMesh) getNestingDepths(components [][]int) []int {
+	depths := make([]int, len(components))
+
+	if len(components) < 2 {
+		return depths
+	}
+
+	aabbs := make([]meshx.AABB, len(components))
+	points := make([]meshx.Vector, len(components))
+
+	for i, faces := range components {
+		aabbs[i] = m.newComponent(faces).AABB
+		points[i] = m.vertices[m.halfEdges[m.faces[faces[0]].HalfEdge].Origin].Point
+	}
+
+	for i, faces := range components {
+		var mesh *HalfEdgeMesh
+
+		for j := range components {
+			if i == j || !isAABBInside(aabbs[j], aabbs[i]) {
+				continue
+			}
+
+			if mesh == nil {
+				mesh = m.Extract(faces)
+			}
+
+			if mesh.ClassifyPoints(points[j : j+1])[0] == ClassificationInside {
+				depths[j]++
+			}
+		}
+	}
+
+	return depths
+}
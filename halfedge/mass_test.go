@@ -0,0 +1,93 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Check the mass properties of a solid.
+func assertMassProperties(t *testing.T, expected, actual MassProperties) {
+	assert.InDelta(t, expected.Volume, actual.Volume, 1e-9)
+	assert.InDelta(t, expected.Mass, actual.Mass, 1e-9)
+	assert.InDelta(t, 0, expected.CenterOfMass.Sub(actual.CenterOfMass).Mag(), 1e-9)
+
+	for i := range 3 {
+		for j := range 3 {
+			assert.InDelta(t, expected.Inertia[i][j], actual.Inertia[i][j], 1e-9)
+		}
+	}
+}
+
+// Test the mass properties of a box regardless of its orientation.
+func TestComputeMassProperties(t *testing.T) {
+	mesh := newTestCube(t)
+	mesh.Transform(meshx.NewTranslationMatrix4(meshx.NewVector(1000, 0, 0)).Mul(meshx.NewScaleMatrix4(meshx.NewVector(1, 2, 3))))
+
+	expected := MassProperties{
+		Volume:       6,
+		Mass:         12,
+		CenterOfMass: meshx.NewVector(1000.5, 1, 1.5),
+		Inertia: [3][3]float64{
+			{12 * (4 + 9) / 12.0, 0, 0},
+			{0, 12 * (1 + 9) / 12.0, 0},
+			{0, 0, 12 * (1 + 4) / 12.0},
+		},
+	}
+
+	properties, err := mesh.ComputeMassProperties(2)
+	assert.Empty(t, err)
+	assertMassProperties(t, expected, properties)
+
+	for i := range mesh.GetNumberOfFaces() {
+		mesh.flipFace(i)
+	}
+
+	properties, err = mesh.ComputeMassProperties(2)
+	assert.Empty(t, err)
+	assertMassProperties(t, expected, properties)
+}
+
+// Test the mass properties of separate and nested components.
+func TestComputeMassPropertiesComponents(t *testing.T) {
+	mesh := newTestCube(t)
+	other := newTestCube(t)
+	other.Translate(meshx.NewVector(2, 0, 0))
+	mesh.Merge(other)
+
+	properties, err := mesh.ComputeMassProperties(1)
+	assert.Empty(t, err)
+	assert.InDelta(t, 2, properties.Volume, 1e-12)
+	assert.InDelta(t, 0, properties.CenterOfMass.Sub(meshx.NewVector(1.5, 0.5, 0.5)).Mag(), 1e-12)
+	assert.InDelta(t, 2/6.0+2*1*1, properties.Inertia[1][1], 1e-12)
+
+	// The inner cube is a cavity whichever way it is oriented.
+	mesh = newTestCube(t)
+	cavity := newTestCube(t)
+	cavity.Transform(meshx.NewTranslationMatrix4(meshx.NewVector(0.25, 0.25, 0.25)).Mul(meshx.NewScaleMatrix4(meshx.NewVector(0.5, 0.5, 0.5))))
+	mesh.Merge(cavity)
+
+	for _, invert := range []bool{false, true} {
+		if invert {
+			for i := 6; i < 12; i++ {
+				mesh.flipFace(i)
+			}
+		}
+
+		properties, err = mesh.ComputeMassProperties(1)
+		assert.Empty(t, err)
+		assert.InDelta(t, 0.875, properties.Volume, 1e-12)
+		assert.InDelta(t, 0, properties.CenterOfMass.Sub(meshx.NewVector(0.5, 0.5, 0.5)).Mag(), 1e-12)
+		assert.InDelta(t, 1/6.0-0.125*0.5/12, properties.Inertia[0][0], 1e-12)
+	}
+}
+
+// Test the mass properties of an open mesh and an invalid density.
+func TestComputeMassPropertiesInvalid(t *testing.T) {
+	_, err := newTestTriangleGrid(t, 1).ComputeMassProperties(1)
+	assert.ErrorIs(t, err, ErrOpenSurface)
+
+	_, err = newTestCube(t).ComputeMassProperties(0)
+	assert.ErrorIs(t, err, ErrInvalidDensity)
+}
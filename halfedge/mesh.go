@@ -2,12 +2,17 @@ package halfedge
 
 import (
 	"compress/gzip"
+	"context"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/morph"
+	"github.com/ajcurley/meshx-go/planar"
 )
 
 // Index-based half edge mesh data structure for manifold polygonal meshes.
@@ -16,32 +21,163 @@ type HalfEdgeMesh struct {
 	faces     []Face
 	halfEdges []HalfEdge
 	patches   []Patch
+	materials []meshx.Material
+
+	// Named sets of faces or vertices.
+	sets []meshx.Set
+
+	hasVertexColors bool
+	hasFaceColors   bool
+
+	// Twin matching is deferred until the first topological query and the
+	// error of the deferred matching (the non-manifold edges are unmatched).
+	deferAdjacency bool
+	adjacencyErr   error
+
+	// Guards the lazy adjacency and edges for concurrent readers (nil for a
+	// zero mesh).
+	lazy *lazyState
+
+	// Edges are indexed on the first edge query (nil until then).
+	edges         []Edge
+	halfEdgeEdges []int
+
+	// Edits recorded to undo (nil unless started) and the edit in progress.
+	journal *journal
+	edit    *meshEdit
+
+	// Callbacks of the mutations.
+	hooks []meshHook
+}
+
+// Options for constructing a HalfEdgeMesh. Zero values use the defaults.
+type HalfEdgeMeshOptions struct {
+	// Defer matching the twins of the half edges until the first query that
+	// requires them (e.g. for a conversion between formats that only uses
+	// the indexed faces). A non-manifold source is then not detected at
+	// construction: the half edges of its non-manifold edges are left
+	// unmatched (as boundaries) and BuildAdjacency returns ErrNonManifold.
+	DeferAdjacency bool
+
+	// Names of the patches tagged as baffles in addition to those tagged by
+	// a source implementing the BaffleReader interface.
+	Baffles []string
 }
 
-// Construct a HalfEdgeMesh from a MeshReader.
+// Construct a HalfEdgeMesh from a MeshReader. The face materials are retained
+// if the source implements the MaterialReader interface, the vertex and face
+// colors are retained if it implements the ColorReader interface, the face
+// smoothing groups are retained if it implements the SmoothingGroupReader
+// interface, the baffles are retained if it implements the BaffleReader
+// interface, the metadata of the patches is retained if it implements the
+// PatchMetadataReader interface and the named sets are retained if it
+// implements the SetReader interface. The mesh may be open: the half edge of
+// an edge of a single face is a boundary. An edge shared by more than two
+// faces is non-manifold (ErrNonManifold) unless at most two of the faces are
+// baffles and at most two are not: the half edges of the baffles are then
+// matched with each other and those of the other faces with each other.
 func NewHalfEdgeMesh(source meshx.MeshReader) (*HalfEdgeMesh, error) {
+	return NewHalfEdgeMeshContext(context.Background(), source, nil)
+}
+
+// Construct a HalfEdgeMesh from a MeshReader with cancellation and progress
+// reported as the number of faces constructed.
+func NewHalfEdgeMeshContext(ctx context.Context, source meshx.MeshReader, progress meshx.ProgressFunc) (*HalfEdgeMesh, error) {
+	return newHalfEdgeMesh(ctx, source, progress, HalfEdgeMeshOptions{})
+}
+
+// Construct a HalfEdgeMesh from a MeshReader with options.
+func NewHalfEdgeMeshWithOptions(source meshx.MeshReader, options HalfEdgeMeshOptions) (*HalfEdgeMesh, error) {
+	return newHalfEdgeMesh(context.Background(), source, nil, options)
+}
+
+// Construct a HalfEdgeMesh from a MeshReader with cancellation, progress and
+// options.
+func newHalfEdgeMesh(ctx context.Context, source meshx.MeshReader, progress meshx.ProgressFunc, options HalfEdgeMeshOptions) (*HalfEdgeMesh, error) {
 	mesh := HalfEdgeMesh{
 		vertices:  make([]Vertex, source.GetNumberOfVertices()),
 		faces:     make([]Face, source.GetNumberOfFaces()),
 		halfEdges: make([]HalfEdge, source.GetNumberOfFaceEdges()),
 		patches:   make([]Patch, source.GetNumberOfPatches()),
+		materials: make([]meshx.Material, 0),
+		lazy:      &lazyState{},
 	}
 
+	baffleSource, hasBaffles := source.(meshx.BaffleReader)
+	metadataSource, hasMetadata := source.(PatchMetadataReader)
+
 	for i := range source.GetNumberOfPatches() {
-		mesh.patches[i] = Patch{source.GetPatch(i)}
+		name := source.GetPatch(i)
+		isBaffle := slices.Contains(options.Baffles, name)
+
+		if hasBaffles {
+			isBaffle = isBaffle || baffleSource.IsPatchBaffle(i)
+		}
+
+		mesh.patches[i] = Patch{Name: name, IsBaffle: isBaffle}
+
+		if hasMetadata {
+			mesh.patches[i].Metadata = metadataSource.GetPatchMetadata(i).Clone()
+		}
 	}
 
+	materialSource, hasMaterials := source.(meshx.MaterialReader)
+
+	if hasMaterials {
+		for i := range materialSource.GetNumberOfMaterials() {
+			mesh.materials = append(mesh.materials, materialSource.GetMaterial(i))
+		}
+	}
+
+	colorSource, hasColors := source.(meshx.ColorReader)
+
+	if hasColors {
+		mesh.hasVertexColors = colorSource.HasVertexColors()
+		mesh.hasFaceColors = colorSource.HasFaceColors()
+	}
+
+	smoothingSource, hasSmoothingGroups := source.(meshx.SmoothingGroupReader)
+
 	for i := range source.GetNumberOfVertices() {
-		mesh.vertices[i] = Vertex{source.GetVertex(i), -1}
+		vertexColor := meshx.ColorWhite
+
+		if mesh.hasVertexColors {
+			vertexColor = colorSource.GetVertexColor(i)
+		}
+
+		mesh.vertices[i] = Vertex{source.GetVertex(i), -1, vertexColor}
 	}
 
 	var nHalfEdges int
-	sharedEdges := make(map[[2]int]int)
+	nFaces := int64(source.GetNumberOfFaces())
 
 	for i := range source.GetNumberOfFaces() {
+		if i%meshx.ProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			progress.Report(int64(i), nFaces)
+		}
+
 		face := source.GetFace(i)
 		facePatch := source.GetFacePatch(i)
-		mesh.faces[i] = Face{nHalfEdges, facePatch}
+		faceMaterial := -1
+		faceColor := meshx.ColorWhite
+
+		if hasMaterials {
+			faceMaterial = materialSource.GetFaceMaterial(i)
+		}
+
+		if mesh.hasFaceColors {
+			faceColor = colorSource.GetFaceColor(i)
+		}
+
+		mesh.faces[i] = Face{nHalfEdges, facePatch, faceMaterial, faceColor, 0}
+
+		if hasSmoothingGroups {
+			mesh.faces[i].SmoothingGroup = smoothingSource.GetFaceSmoothingGroup(i)
+		}
 
 		for j, vertex := range face {
 			k := nHalfEdges + j
@@ -57,27 +193,29 @@ func NewHalfEdgeMesh(source meshx.MeshReader) (*HalfEdgeMesh, error) {
 				Twin:      -1,
 				IsFeature: false,
 			}
-
-			p := min(vertex, face[next])
-			q := max(vertex, face[next])
-			edge := [2]int{p, q}
-
-			if twin, ok := sharedEdges[edge]; ok {
-				mesh.halfEdges[k].Twin = twin
-				mesh.halfEdges[twin].Twin = k
-				delete(sharedEdges, edge)
-			} else {
-				sharedEdges[edge] = k
-			}
 		}
 
 		nHalfEdges += len(face)
 	}
 
-	if len(sharedEdges) != 0 {
-		return nil, meshx.ErrNonManifold
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if options.DeferAdjacency {
+		mesh.deferAdjacency = true
+	} else if err := matchTwins(mesh.halfEdges, mesh.getBaffleFaces(), true); err != nil {
+		return nil, err
+	} else {
+		mesh.lazy.adjacent.Store(true)
+	}
+
+	if setSource, ok := source.(meshx.SetReader); ok {
+		mesh.readSets(setSource)
 	}
 
+	progress.Report(nFaces, nFaces)
+
 	return &mesh, nil
 }
 
@@ -101,17 +239,30 @@ func NewHalfEdgeMeshFromOBJPath(path string) (*HalfEdgeMesh, error) {
 	return NewHalfEdgeMesh(source)
 }
 
-// Write the HalfEdgeMesh to an OBJ file.
+// Write the HalfEdgeMesh to an OBJ file. The face materials are written
+// without a material library.
 func (m *HalfEdgeMesh) WriteOBJ(writer io.Writer) error {
+	return m.writeOBJ(writer, "")
+}
+
+// Write the HalfEdgeMesh to an OBJ file referencing a material library.
+func (m *HalfEdgeMesh) writeOBJ(writer io.Writer, library string) error {
 	vertices := make([]meshx.Vector, m.GetNumberOfVertices())
 	faces := make([][]int, m.GetNumberOfFaces())
 	facePatches := make([]int, m.GetNumberOfFaces())
+	faceMaterials := make([]int, m.GetNumberOfFaces())
+	smoothingGroups := make([]int, m.GetNumberOfFaces())
 	patches := make([]string, m.GetNumberOfPatches())
+	materials := make([]string, m.GetNumberOfMaterials())
 
 	for i := range m.GetNumberOfPatches() {
 		patches[i] = m.patches[i].Name
 	}
 
+	for i := range m.GetNumberOfMaterials() {
+		materials[i] = m.materials[i].Name
+	}
+
 	for i := range m.GetNumberOfVertices() {
 		vertices[i] = m.vertices[i].Point
 	}
@@ -119,6 +270,8 @@ func (m *HalfEdgeMesh) WriteOBJ(writer io.Writer) error {
 	for i := range m.GetNumberOfFaces() {
 		faces[i] = m.GetFaceVertices(i)
 		facePatches[i] = m.faces[i].Patch
+		faceMaterials[i] = m.faces[i].Material
+		smoothingGroups[i] = m.faces[i].SmoothingGroup
 	}
 
 	objWriter := meshx.NewOBJWriter(writer)
@@ -126,10 +279,165 @@ func (m *HalfEdgeMesh) WriteOBJ(writer io.Writer) error {
 	objWriter.SetFaces(faces)
 	objWriter.SetFacePatches(facePatches)
 	objWriter.SetPatches(patches)
+	objWriter.SetFaceMaterials(faceMaterials)
+	objWriter.SetMaterials(materials)
+	objWriter.SetMaterialLibrary(library)
+	objWriter.SetFaceSmoothingGroups(smoothingGroups)
+
+	if m.hasVertexColors {
+		objWriter.SetVertexColors(m.getVertexColors())
+	}
 
 	return objWriter.Write()
 }
 
+// Write the HalfEdgeMesh to a PLY file. The vertex and face colors are
+// written if the mesh has them.
+func (m *HalfEdgeMesh) WritePLY(writer io.Writer, format meshx.PLYFormat) error {
+	vertices := make([]meshx.Vector, m.GetNumberOfVertices())
+	faces := make([][]int, m.GetNumberOfFaces())
+	facePatches := make([]int, m.GetNumberOfFaces())
+	patches := make([]string, m.GetNumberOfPatches())
+
+	for i := range m.GetNumberOfPatches() {
+		patches[i] = m.patches[i].Name
+	}
+
+	for i := range m.GetNumberOfVertices() {
+		vertices[i] = m.vertices[i].Point
+	}
+
+	for i := range m.GetNumberOfFaces() {
+		faces[i] = m.GetFaceVertices(i)
+		facePatches[i] = m.faces[i].Patch
+	}
+
+	plyWriter := meshx.NewPLYWriter(writer, format)
+	plyWriter.SetVertices(vertices)
+	plyWriter.SetFaces(faces)
+	plyWriter.SetFacePatches(facePatches)
+	plyWriter.SetPatches(patches)
+
+	if m.hasVertexColors {
+		plyWriter.SetVertexColors(m.getVertexColors())
+	}
+
+	if m.hasFaceColors {
+		plyWriter.SetFaceColors(m.getFaceColors())
+	}
+
+	return plyWriter.Write()
+}
+
+// Write the HalfEdgeMesh to a binary (little endian) PLY file path.
+func (m *HalfEdgeMesh) WritePLYToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.WritePLY(file, meshx.PLYFormatBinaryLittleEndian)
+}
+
+// Write the HalfEdgeMesh to a Gmsh MSH file. An error is returned if a face
+// is neither a triangle nor a quadrilateral.
+func (m *HalfEdgeMesh) WriteMSH(writer io.Writer, version meshx.MSHVersion) error {
+	vertices := make([]meshx.Vector, m.GetNumberOfVertices())
+	faces := make([][]int, m.GetNumberOfFaces())
+	facePatches := make([]int, m.GetNumberOfFaces())
+	patches := make([]string, m.GetNumberOfPatches())
+
+	for i := range m.GetNumberOfPatches() {
+		patches[i] = m.patches[i].Name
+	}
+
+	for i := range m.GetNumberOfVertices() {
+		vertices[i] = m.vertices[i].Point
+	}
+
+	for i := range m.GetNumberOfFaces() {
+		faces[i] = m.GetFaceVertices(i)
+		facePatches[i] = m.faces[i].Patch
+	}
+
+	mshWriter := meshx.NewMSHWriter(writer, version)
+	mshWriter.SetVertices(vertices)
+	mshWriter.SetFaces(faces)
+	mshWriter.SetFacePatches(facePatches)
+	mshWriter.SetPatches(patches)
+
+	return mshWriter.Write()
+}
+
+// Write the HalfEdgeMesh to a Gmsh MSH 4.1 file path.
+func (m *HalfEdgeMesh) WriteMSHToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.WriteMSH(file, meshx.MSHVersion4)
+}
+
+// Write the HalfEdgeMesh to a VTP file. The vertex and face colors are
+// written if the mesh has them.
+func (m *HalfEdgeMesh) WriteVTP(writer io.Writer) error {
+	return m.writeVTP(writer, nil, nil)
+}
+
+// Write the HalfEdgeMesh to a VTP file with scalar fields on the vertices
+// and faces.
+func (m *HalfEdgeMesh) writeVTP(writer io.Writer, vertexFields, faceFields []meshx.ScalarField) error {
+	vertices := make([]meshx.Vector, m.GetNumberOfVertices())
+	faces := make([][]int, m.GetNumberOfFaces())
+	facePatches := make([]int, m.GetNumberOfFaces())
+
+	for i := range m.GetNumberOfVertices() {
+		vertices[i] = m.vertices[i].Point
+	}
+
+	for i := range m.GetNumberOfFaces() {
+		faces[i] = m.GetFaceVertices(i)
+		facePatches[i] = m.faces[i].Patch
+	}
+
+	vtpWriter := meshx.NewVTPWriter(writer)
+	vtpWriter.SetVertices(vertices)
+	vtpWriter.SetFaces(faces)
+	vtpWriter.SetFacePatches(facePatches)
+
+	if m.hasVertexColors {
+		vtpWriter.SetVertexColors(m.getVertexColors())
+	}
+
+	if m.hasFaceColors {
+		vtpWriter.SetFaceColors(m.getFaceColors())
+	}
+
+	for _, field := range vertexFields {
+		vtpWriter.AddVertexField(field)
+	}
+
+	for _, field := range faceFields {
+		vtpWriter.AddFaceField(field)
+	}
+
+	return vtpWriter.Write()
+}
+
+// Write the HalfEdgeMesh to a VTP file path.
+func (m *HalfEdgeMesh) WriteVTPToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.WriteVTP(file)
+}
+
 // Write the HalfEdgeMesh feature edges to an OBJ file.
 func (m *HalfEdgeMesh) WriteOBJFeatureEdges(writer io.Writer) error {
 	indexEdges := make(map[[2]int]bool)
@@ -172,8 +480,67 @@ func (m *HalfEdgeMesh) WriteOBJFeatureEdges(writer io.Writer) error {
 	return objWriter.Write()
 }
 
-// Write the HalfEdgeMesh to an OBJ file path.
+// Construct a HalfEdgeMesh from a PLY file reader.
+func NewHalfEdgeMeshFromPLY(reader io.Reader) (*HalfEdgeMesh, error) {
+	source := meshx.NewPLYReader(reader)
+
+	if err := source.Read(); err != nil {
+		return nil, err
+	}
+
+	return NewHalfEdgeMesh(source)
+}
+
+// Construct a HalfEdgeMesh from a PLY file path.
+func NewHalfEdgeMeshFromPLYPath(path string) (*HalfEdgeMesh, error) {
+	source, err := meshx.ReadPLYFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewHalfEdgeMesh(source)
+}
+
+// Construct a HalfEdgeMesh from a Gmsh MSH file reader. The tetrahedra are
+// ignored.
+func NewHalfEdgeMeshFromMSH(reader io.Reader) (*HalfEdgeMesh, error) {
+	source := meshx.NewMSHReader(reader)
+
+	if err := source.Read(); err != nil {
+		return nil, err
+	}
+
+	return NewHalfEdgeMesh(source)
+}
+
+// Construct a HalfEdgeMesh from a Gmsh MSH file path.
+func NewHalfEdgeMeshFromMSHPath(path string) (*HalfEdgeMesh, error) {
+	source, err := meshx.ReadMSHFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewHalfEdgeMesh(source)
+}
+
+// Write the HalfEdgeMesh to an OBJ file path. If the mesh has materials, a
+// matching MTL file is written alongside it (e.g. mesh.mtl for mesh.obj).
 func (m *HalfEdgeMesh) WriteOBJToPath(path string) error {
+	var library string
+
+	if m.GetNumberOfMaterials() != 0 {
+		stem := path
+
+		if strings.ToLower(filepath.Ext(stem)) == ".gz" {
+			stem = strings.TrimSuffix(stem, filepath.Ext(stem))
+		}
+
+		stem = strings.TrimSuffix(stem, filepath.Ext(stem))
+		library = filepath.Base(stem) + ".mtl"
+
+		if err := meshx.WriteMTLToPath(stem+".mtl", m.materials); err != nil {
+			return err
+		}
+	}
+
 	file, err := os.Create(path)
 	if err != nil {
 		return err
@@ -190,7 +557,7 @@ func (m *HalfEdgeMesh) WriteOBJToPath(path string) error {
 		writer = file
 	}
 
-	return m.WriteOBJ(writer)
+	return m.writeOBJ(writer, library)
 }
 
 // Write the HalfEdgeMesh feature edges to an OBJ file path.
@@ -239,6 +606,40 @@ func (m *HalfEdgeMesh) GetVertexIncomingHalfEdges(index int) []int {
 	panic("not implemented")
 }
 
+// Return true if the vertices have colors.
+func (m *HalfEdgeMesh) HasVertexColors() bool {
+	return m.hasVertexColors
+}
+
+// Set the color of each vertex.
+func (m *HalfEdgeMesh) SetVertexColors(colors []meshx.Color) {
+	for i := range m.vertices {
+		m.vertices[i].Color = colors[i]
+	}
+
+	m.hasVertexColors = true
+}
+
+// Remove the vertex colors.
+func (m *HalfEdgeMesh) ClearVertexColors() {
+	for i := range m.vertices {
+		m.vertices[i].Color = meshx.ColorWhite
+	}
+
+	m.hasVertexColors = false
+}
+
+// Get the color of each vertex.
+func (m *HalfEdgeMesh) getVertexColors() []meshx.Color {
+	colors := make([]meshx.Color, m.GetNumberOfVertices())
+
+	for i, vertex := range m.vertices {
+		colors[i] = vertex.Color
+	}
+
+	return colors
+}
+
 // Get the number of faces.
 func (m *HalfEdgeMesh) GetNumberOfFaces() int {
 	return len(m.faces)
@@ -251,89 +652,210 @@ func (m *HalfEdgeMesh) GetFace(index int) Face {
 
 // Get the vertices of a face.
 func (m *HalfEdgeMesh) GetFaceVertices(index int) []int {
-	halfEdges := m.GetFaceHalfEdges(index)
-	vertices := make([]int, len(halfEdges))
+	return m.GetFaceVerticesInto(index, make([]int, 0, 3))
+}
 
-	for i, id := range halfEdges {
-		vertices[i] = m.GetHalfEdge(id).Origin
-	}
+// Get the vertices of a face into a buffer. The buffer is truncated and
+// grown as needed, and the result is returned (like append), so reusing it
+// across calls in a loop avoids allocating a slice per face.
+func (m *HalfEdgeMesh) GetFaceVerticesInto(index int, buf []int) []int {
+	start := m.faces[index].HalfEdge
+	next := start
+	buf = buf[:0]
+
+	for {
+		buf = append(buf, m.halfEdges[next].Origin)
+		next = m.halfEdges[next].Next
 
-	return vertices
+		if next == start {
+			return buf
+		}
+	}
 }
 
 // Get the half edges of a face.
 func (m *HalfEdgeMesh) GetFaceHalfEdges(index int) []int {
-	face := m.GetFace(index)
-	next := face.HalfEdge
-	halfEdges := make([]int, 0, 3)
+	return m.GetFaceHalfEdgesInto(index, make([]int, 0, 3))
+}
+
+// Get the half edges of a face into a buffer. See GetFaceVerticesInto.
+func (m *HalfEdgeMesh) GetFaceHalfEdgesInto(index int, buf []int) []int {
+	start := m.faces[index].HalfEdge
+	next := start
+	buf = buf[:0]
 
 	for {
-		halfEdges = append(halfEdges, next)
-		next = m.GetHalfEdge(next).Next
+		buf = append(buf, next)
+		next = m.halfEdges[next].Next
 
-		if next == face.HalfEdge {
-			break
+		if next == start {
+			return buf
 		}
 	}
-
-	return halfEdges
 }
 
 // Get the neighboring faces of a face.
 func (m *HalfEdgeMesh) GetFaceNeighbors(index int) []int {
-	halfEdges := m.GetFaceHalfEdges(index)
-	faces := make([]int, 0, len(halfEdges))
+	return m.GetFaceNeighborsInto(index, make([]int, 0, 3))
+}
+
+// Get the neighboring faces of a face into a buffer. See
+// GetFaceVerticesInto.
+func (m *HalfEdgeMesh) GetFaceNeighborsInto(index int, buf []int) []int {
+	m.ensureAdjacency()
 
-	for _, id := range halfEdges {
-		if halfEdge := m.GetHalfEdge(id); !halfEdge.IsBoundary() {
-			twin := m.GetHalfEdge(halfEdge.Twin)
-			faces = append(faces, twin.Face)
+	start := m.faces[index].HalfEdge
+	next := start
+	buf = buf[:0]
+
+	for {
+		if halfEdge := m.halfEdges[next]; !halfEdge.IsBoundary() {
+			buf = append(buf, m.halfEdges[halfEdge.Twin].Face)
 		}
-	}
 
-	return faces
+		next = m.halfEdges[next].Next
+
+		if next == start {
+			return buf
+		}
+	}
 }
 
-// Get the normal vector of a face.
+// Get the unit normal vector of a face (the Newell normal, which is robust
+// for concave and non-planar faces). The normal is zero for a degenerate
+// face.
 func (m *HalfEdgeMesh) GetFaceNormal(index int) meshx.Vector {
-	var normal meshx.Vector
-	var totalArea float64
+	normal := planar.ComputeLoopNormal(m.getFacePoints(index))
+
+	if normal.Mag() == 0 {
+		return normal
+	}
+
+	return normal.Unit()
+}
 
+// Get the points of the vertices of a face.
+func (m *HalfEdgeMesh) getFacePoints(index int) []meshx.Vector {
 	vertices := m.GetFaceVertices(index)
+	points := make([]meshx.Vector, len(vertices))
+
+	for i, vertex := range vertices {
+		points[i] = m.vertices[vertex].Point
+	}
+
+	return points
+}
+
+// Get the triangles of a face. Faces with more than three vertices are
+// triangulated without adding vertices (see planar.TriangulateFace).
+func (m *HalfEdgeMesh) GetFaceTriangles(index int) []meshx.Triangle {
+	points := m.getFacePoints(index)
+	indices := planar.TriangulateFace(points)
+	triangles := make([]meshx.Triangle, len(indices))
+
+	for i, triangle := range indices {
+		triangles[i] = meshx.NewTriangle(points[triangle[0]], points[triangle[1]], points[triangle[2]])
+	}
+
+	return triangles
+}
+
+// Get the triangles of all faces. See GetFaceTriangles.
+func (m *HalfEdgeMesh) GetTriangles() []meshx.Triangle {
+	triangles := make([]meshx.Triangle, 0, m.GetNumberOfFaces())
+
+	for i := range m.GetNumberOfFaces() {
+		triangles = append(triangles, m.GetFaceTriangles(i)...)
+	}
+
+	return triangles
+}
+
+// Get the area of a face.
+func (m *HalfEdgeMesh) GetFaceArea(index int) float64 {
+	var area float64
 
-	for i := 0; i < len(vertices); i++ {
-		j := (i + 1) % len(vertices)
-		k := (i + 2) % len(vertices)
+	for _, triangle := range m.GetFaceTriangles(index) {
+		area += triangle.Area()
+	}
+
+	return area
+}
+
+// Get the centroid (center of area) of a face. The centroid of a degenerate
+// face is the mean of its vertices.
+func (m *HalfEdgeMesh) GetFaceCentroid(index int) meshx.Vector {
+	var area float64
+	var centroid meshx.Vector
 
-		p := m.vertices[vertices[i]].Point
-		q := m.vertices[vertices[j]].Point
-		r := m.vertices[vertices[k]].Point
-		triangle := meshx.NewTriangle(p, q, r)
+	for _, triangle := range m.GetFaceTriangles(index) {
+		a := triangle.Area()
+		area += a
+		centroid = centroid.Add(triangle.Centroid().MulScalar(a))
+	}
+
+	if area > 0 {
+		return centroid.DivScalar(area)
+	}
 
-		area := triangle.Area()
-		totalArea += area
+	points := m.getFacePoints(index)
 
-		weightedNormal := triangle.UnitNormal().MulScalar(area)
-		normal = normal.Add(weightedNormal)
+	for _, point := range points {
+		centroid = centroid.Add(point)
 	}
 
-	return normal.DivScalar(totalArea)
+	return centroid.DivScalar(float64(len(points)))
 }
 
 // Flip the orientation of a face.
 func (m *HalfEdgeMesh) flipFace(index int) {
-	for _, id := range m.GetFaceHalfEdges(index) {
+	ids := m.GetFaceHalfEdges(index)
+	origins := make([]int, len(ids))
+
+	for i, id := range ids {
+		origins[i] = m.GetHalfEdge(m.GetHalfEdge(id).Next).Origin
+	}
+
+	for i, id := range ids {
 		halfEdge := m.GetHalfEdge(id)
-		origin := m.GetHalfEdge(halfEdge.Next).Origin
+		halfEdge.Origin = origins[i]
+		halfEdge.Next, halfEdge.Prev = halfEdge.Prev, halfEdge.Next
+		m.halfEdges[id] = halfEdge
+	}
+}
 
-		m.halfEdges[id] = HalfEdge{
-			Origin: origin,
-			Face:   halfEdge.Face,
-			Next:   halfEdge.Prev,
-			Prev:   halfEdge.Next,
-			Twin:   halfEdge.Twin,
-		}
+// Return true if the faces have colors.
+func (m *HalfEdgeMesh) HasFaceColors() bool {
+	return m.hasFaceColors
+}
+
+// Set the color of each face.
+func (m *HalfEdgeMesh) SetFaceColors(colors []meshx.Color) {
+	for i := range m.faces {
+		m.faces[i].Color = colors[i]
 	}
+
+	m.hasFaceColors = true
+}
+
+// Remove the face colors.
+func (m *HalfEdgeMesh) ClearFaceColors() {
+	for i := range m.faces {
+		m.faces[i].Color = meshx.ColorWhite
+	}
+
+	m.hasFaceColors = false
+}
+
+// Get the color of each face.
+func (m *HalfEdgeMesh) getFaceColors() []meshx.Color {
+	colors := make([]meshx.Color, m.GetNumberOfFaces())
+
+	for i, face := range m.faces {
+		colors[i] = face.Color
+	}
+
+	return colors
 }
 
 // Get the number of half edges.
@@ -343,9 +865,50 @@ func (m *HalfEdgeMesh) GetNumberOfHalfEdges() int {
 
 // Get a half edge by index.
 func (m *HalfEdgeMesh) GetHalfEdge(index int) HalfEdge {
+	m.ensureAdjacency()
+
 	return m.halfEdges[index]
 }
 
+// Get the number of (undirected) edges.
+func (m *HalfEdgeMesh) GetNumberOfEdges() int {
+	m.ensureEdges()
+
+	return len(m.edges)
+}
+
+// Get an edge by index.
+func (m *HalfEdgeMesh) GetEdge(index int) Edge {
+	m.ensureEdges()
+
+	return m.edges[index]
+}
+
+// Get the edge of a half edge.
+func (m *HalfEdgeMesh) GetHalfEdgeEdge(index int) int {
+	m.ensureEdges()
+
+	return m.halfEdgeEdges[index]
+}
+
+// Get the vertices of an edge (the origin and target of its first half
+// edge).
+func (m *HalfEdgeMesh) GetEdgeVertices(index int) [2]int {
+	halfEdge := m.GetHalfEdge(m.GetEdge(index).HalfEdge)
+	return [2]int{halfEdge.Origin, m.halfEdges[halfEdge.Next].Origin}
+}
+
+// Get the faces of an edge (one on the boundary, two otherwise).
+func (m *HalfEdgeMesh) GetEdgeFaces(index int) []int {
+	edge := m.GetEdge(index)
+
+	if edge.IsBoundary() {
+		return []int{m.halfEdges[edge.HalfEdge].Face}
+	}
+
+	return []int{m.halfEdges[edge.HalfEdge].Face, m.halfEdges[edge.Twin].Face}
+}
+
 // Get the face angle between two faces sharing a half edge.
 func (m *HalfEdgeMesh) GetHalfEdgeFaceAngle(index int) float64 {
 	halfEdge := m.GetHalfEdge(index)
@@ -356,6 +919,40 @@ func (m *HalfEdgeMesh) GetHalfEdgeFaceAngle(index int) float64 {
 	return u.AngleTo(v)
 }
 
+// Get the signed dihedral angle between two faces sharing a half edge: the
+// angle between the face normals, positive if the edge is convex (a ridge)
+// and negative if it is concave (a valley). The angle is zero on the
+// boundary.
+func (m *HalfEdgeMesh) GetHalfEdgeDihedralAngle(index int) float64 {
+	halfEdge := m.GetHalfEdge(index)
+
+	if halfEdge.IsBoundary() {
+		return 0
+	}
+
+	twin := m.GetHalfEdge(halfEdge.Twin)
+	u := m.GetFaceNormal(halfEdge.Face)
+	v := m.GetFaceNormal(twin.Face)
+
+	// The faces turn away from each other (convex) if the normals rotate
+	// about the half edge in the direction of the face.
+	p := m.vertices[halfEdge.Origin].Point
+	q := m.vertices[twin.Origin].Point
+	angle := math.Atan2(u.Cross(v).Mag(), u.Dot(v))
+
+	if u.Cross(v).Dot(q.Sub(p)) < 0 {
+		return -angle
+	}
+
+	return angle
+}
+
+// Get the signed dihedral angle of an edge (see GetHalfEdgeDihedralAngle).
+// The angle is zero on the boundary.
+func (m *HalfEdgeMesh) GetEdgeDihedralAngle(index int) float64 {
+	return m.GetHalfEdgeDihedralAngle(m.GetEdge(index).HalfEdge)
+}
+
 // Get the number of patches.
 func (m *HalfEdgeMesh) GetNumberOfPatches() int {
 	return len(m.patches)
@@ -366,6 +963,35 @@ func (m *HalfEdgeMesh) GetPatch(index int) Patch {
 	return m.patches[index]
 }
 
+// Add a patch by name and get its index.
+func (m *HalfEdgeMesh) AddPatch(name string) int {
+	m.patches = append(m.patches, Patch{Name: name})
+	return len(m.patches) - 1
+}
+
+// Set the name of a patch.
+func (m *HalfEdgeMesh) SetPatchName(index int, name string) {
+	m.patches[index].Name = name
+}
+
+// Set the boundary condition metadata of a patch (copied).
+func (m *HalfEdgeMesh) SetPatchMetadata(index int, metadata PatchMetadata) {
+	m.patches[index].Metadata = metadata.Clone()
+}
+
+// Get the index of the first patch with a name or alias (-1 if none).
+func (m *HalfEdgeMesh) GetPatchIndex(name string) int {
+	return slices.IndexFunc(m.patches, func(patch Patch) bool {
+		return patch.HasName(name)
+	})
+}
+
+// Set the patch of a face. A face without a patch is -1.
+func (m *HalfEdgeMesh) SetFacePatch(index, patch int) {
+	m.faces[index].Patch = patch
+	m.notify(PatchesChanged, []int{index})
+}
+
 // Get the faces of a patch.
 func (m *HalfEdgeMesh) GetPatchFaces(index int) []int {
 	faces := make([]int, 0)
@@ -379,10 +1005,73 @@ func (m *HalfEdgeMesh) GetPatchFaces(index int) []int {
 	return faces
 }
 
-// Return true if there are no open edges.
+// Tag a patch as a baffle (or not) and rematch the twins of the half edges.
+// An error is returned (and the mesh is unchanged) if the mesh would be
+// non-manifold (see NewHalfEdgeMesh).
+func (m *HalfEdgeMesh) SetPatchBaffle(index int, isBaffle bool) error {
+	source := newMeshSource(m)
+	source.baffles[index] = isBaffle
+	mesh, err := NewHalfEdgeMesh(source)
+
+	if err != nil {
+		return err
+	}
+
+	m.replace(mesh)
+
+	return nil
+}
+
+// Return true if a face is in a baffle patch.
+func (m *HalfEdgeMesh) IsBaffleFace(index int) bool {
+	patch := m.faces[index].Patch
+	return patch >= 0 && m.patches[patch].IsBaffle
+}
+
+// Get whether each face is in a baffle patch (nil if there are no baffles).
+func (m *HalfEdgeMesh) getBaffleFaces() []bool {
+	if !slices.ContainsFunc(m.patches, func(patch Patch) bool { return patch.IsBaffle }) {
+		return nil
+	}
+
+	baffles := make([]bool, len(m.faces))
+
+	for i := range m.faces {
+		baffles[i] = m.IsBaffleFace(i)
+	}
+
+	return baffles
+}
+
+// Get the number of materials.
+func (m *HalfEdgeMesh) GetNumberOfMaterials() int {
+	return len(m.materials)
+}
+
+// Get a material by index.
+func (m *HalfEdgeMesh) GetMaterial(index int) meshx.Material {
+	return m.materials[index]
+}
+
+// Get the faces of a material.
+func (m *HalfEdgeMesh) GetMaterialFaces(index int) []int {
+	faces := make([]int, 0)
+
+	for id, face := range m.faces {
+		if face.Material == index {
+			faces = append(faces, id)
+		}
+	}
+
+	return faces
+}
+
+// Return true if there are no open edges. The edges of baffles are ignored.
 func (m *HalfEdgeMesh) IsClosed() bool {
+	m.ensureAdjacency()
+
 	for _, halfEdge := range m.halfEdges {
-		if halfEdge.IsBoundary() {
+		if halfEdge.IsBoundary() && !m.IsBaffleFace(halfEdge.Face) {
 			return false
 		}
 	}
@@ -409,6 +1098,65 @@ func (m *HalfEdgeMesh) GetAABB() meshx.AABB {
 	return meshx.NewAABBFromBounds(minBound, maxBound)
 }
 
+// Get the minimal bounding sphere.
+func (m *HalfEdgeMesh) GetBoundingSphere() meshx.Sphere {
+	points := make([]meshx.Vector, len(m.vertices))
+
+	for i, vertex := range m.vertices {
+		points[i] = vertex.Point
+	}
+
+	return meshx.NewSphereFromVectors(points)
+}
+
+// Get the axis-aligned bounding box of a patch.
+func (m *HalfEdgeMesh) GetPatchAABB(index int) meshx.AABB {
+	return m.GetPatchAABBs()[index]
+}
+
+// Get the axis-aligned bounding box of each patch. A patch without faces
+// has an empty bounding box.
+func (m *HalfEdgeMesh) GetPatchAABBs() []meshx.AABB {
+	aabbs := make([]meshx.AABB, len(m.patches))
+	minBounds := make([]meshx.Vector, len(m.patches))
+	maxBounds := make([]meshx.Vector, len(m.patches))
+	isEmpty := make([]bool, len(m.patches))
+
+	for i := range isEmpty {
+		isEmpty[i] = true
+	}
+
+	for i, face := range m.faces {
+		if face.Patch < 0 {
+			continue
+		}
+
+		for _, vertex := range m.GetFaceVertices(i) {
+			point := m.vertices[vertex].Point
+
+			if isEmpty[face.Patch] {
+				minBounds[face.Patch] = point
+				maxBounds[face.Patch] = point
+				isEmpty[face.Patch] = false
+				continue
+			}
+
+			for j := 0; j < 3; j++ {
+				minBounds[face.Patch][j] = min(minBounds[face.Patch][j], point[j])
+				maxBounds[face.Patch][j] = max(maxBounds[face.Patch][j], point[j])
+			}
+		}
+	}
+
+	for i := range aabbs {
+		if !isEmpty[i] {
+			aabbs[i] = meshx.NewAABBFromBounds(minBounds[i], maxBounds[i])
+		}
+	}
+
+	return aabbs
+}
+
 // Get the the half edges marked as a feature.
 func (m *HalfEdgeMesh) GetFeatureEdges() []int {
 	featureEdges := make([]int, 0)
@@ -424,6 +1172,8 @@ func (m *HalfEdgeMesh) GetFeatureEdges() []int {
 
 // Set a half edge as a feature (or not) manually.
 func (m *HalfEdgeMesh) SetFeatureEdge(index int, isFeature bool) {
+	m.ensureAdjacency()
+
 	m.halfEdges[index].IsFeature = isFeature
 }
 
@@ -437,18 +1187,22 @@ func (m *HalfEdgeMesh) ClearFeatureEdges() {
 // Mark the half edges exceeding the angle threshold between faces. The angle
 // threshold is specified in radians.
 func (m *HalfEdgeMesh) ComputeFeatureEdges(threshold float64) {
-	for index, halfEdge := range m.halfEdges {
-		if !halfEdge.IsBoundary() && !halfEdge.IsFeature {
-			if m.GetHalfEdgeFaceAngle(index) > threshold {
-				halfEdge.IsFeature = true
-				m.halfEdges[halfEdge.Twin].IsFeature = true
-			}
+	m.ensureAdjacency()
+
+	for i := range m.GetNumberOfEdges() {
+		edge := m.edges[i]
+
+		if !edge.IsBoundary() && m.GetHalfEdgeFaceAngle(edge.HalfEdge) > threshold {
+			m.halfEdges[edge.HalfEdge].IsFeature = true
+			m.halfEdges[edge.Twin].IsFeature = true
 		}
 	}
 }
 
 // Get the isolated components (faces).
 func (m *HalfEdgeMesh) GetComponents() [][]int {
+	m.ensureAdjacency()
+
 	components := make([][]int, 0)
 	visited := make([]bool, m.GetNumberOfFaces())
 
@@ -483,6 +1237,8 @@ func (m *HalfEdgeMesh) GetComponents() [][]int {
 
 // Return true if all neighboring faces share the same orientation.
 func (m *HalfEdgeMesh) IsConsistent() bool {
+	m.ensureAdjacency()
+
 	for _, halfEdge := range m.halfEdges {
 		if !halfEdge.IsBoundary() {
 			if m.GetHalfEdge(halfEdge.Twin).Origin == halfEdge.Origin {
@@ -501,11 +1257,14 @@ func (m *HalfEdgeMesh) IsConsistentWithReference(reference meshx.Vector) bool {
 
 // Orient the mesh such that the faces of each component are consistent.
 func (m *HalfEdgeMesh) Orient() {
+	m.ensureAdjacency()
+
 	if m.IsConsistent() {
 		return
 	}
 
 	visited := make([]bool, m.GetNumberOfFaces())
+	flipped := make([]bool, m.GetNumberOfFaces())
 
 	for i := 0; i < m.GetNumberOfFaces(); i++ {
 		if !visited[i] {
@@ -519,10 +1278,11 @@ func (m *HalfEdgeMesh) Orient() {
 					visited[current] = true
 
 					for _, neighbor := range m.GetFaceNeighbors(current) {
-						if !m.checkFaceOrientation(current, neighbor) {
-							visited[current] = true
-							m.flipFace(neighbor)
-						} else {
+						if !visited[neighbor] {
+							if !m.checkFaceOrientation(current, neighbor) {
+								m.flipFace(neighbor)
+								flipped[neighbor] = true
+							}
 							queue = append(queue, neighbor)
 						}
 					}
@@ -530,6 +1290,16 @@ func (m *HalfEdgeMesh) Orient() {
 			}
 		}
 	}
+
+	changed := make([]int, 0)
+
+	for i, isFlipped := range flipped {
+		if isFlipped {
+			changed = append(changed, i)
+		}
+	}
+
+	m.notify(FacesChanged, changed)
 }
 
 // Orient the mesh such that all the faces are consistently oriented relative
@@ -552,12 +1322,82 @@ func (m *HalfEdgeMesh) checkFaceOrientation(source, target int) bool {
 	return false
 }
 
-// Merge two meshes together (in place).
+// Merge two meshes together (in place). The named sets of the same name and
+// kind are combined.
 func (m *HalfEdgeMesh) Merge(n *HalfEdgeMesh) {
+	patchMap := make([]int, n.GetNumberOfPatches())
+
+	for i, patch := range n.patches {
+		patch.Metadata = patch.Metadata.Clone()
+		patchMap[i] = len(m.patches)
+		m.patches = append(m.patches, patch)
+	}
+
+	m.merge(n, patchMap)
+}
+
+// Options for merging two meshes.
+type MergeOptions struct {
+	// Merge patches with identical names rather than appending them. The
+	// metadata of the patches of this mesh is kept.
+	MergePatches bool
+
+	// Weld the open boundary vertices within the tolerance of each other to
+	// close the interface between the meshes.
+	WeldVertices  bool
+	WeldTolerance float64
+}
+
+// Merge two meshes together (in place) with options. An error is returned
+// if welding the vertices produces a non-manifold mesh, in which case the
+// meshes are merged without welding.
+func (m *HalfEdgeMesh) MergeWithOptions(n *HalfEdgeMesh, options MergeOptions) error {
+	if !options.MergePatches {
+		m.Merge(n)
+	} else {
+		indexPatches := make(map[string]int)
+		patchMap := make([]int, n.GetNumberOfPatches())
+
+		for i, patch := range m.patches {
+			if _, ok := indexPatches[patch.Name]; !ok {
+				indexPatches[patch.Name] = i
+			}
+		}
+
+		for i, patch := range n.patches {
+			if index, ok := indexPatches[patch.Name]; ok {
+				patchMap[i] = index
+			} else {
+				patch.Metadata = patch.Metadata.Clone()
+				indexPatches[patch.Name] = len(m.patches)
+				patchMap[i] = len(m.patches)
+				m.patches = append(m.patches, patch)
+			}
+		}
+
+		m.merge(n, patchMap)
+	}
+
+	if options.WeldVertices {
+		return m.Stitch(options.WeldTolerance)
+	}
+
+	return nil
+}
+
+// Merge the elements of a mesh (in place) with the patches of the merged
+// mesh mapped to the patches of this mesh.
+func (m *HalfEdgeMesh) merge(n *HalfEdgeMesh, patchMap []int) {
+	m.ensureAdjacency()
+	n.ensureAdjacency()
+
 	offsetVertex := m.GetNumberOfVertices()
 	offsetFace := m.GetNumberOfFaces()
 	offsetHalfEdge := m.GetNumberOfHalfEdges()
-	offsetPatch := m.GetNumberOfPatches()
+	materialMap := m.mergeMaterials(n)
+
+	m.hasVertexColors = m.hasVertexColors || n.hasVertexColors
+	m.hasFaceColors = m.hasFaceColors || n.hasFaceColors
 
 	for _, vertex := range n.vertices {
 		m.vertices = append(m.vertices, vertex)
@@ -565,10 +1405,20 @@ func (m *HalfEdgeMesh) Merge(n *HalfEdgeMesh) {
 
 	for _, face := range n.faces {
 		face.HalfEdge += offsetHalfEdge
-		face.Patch += offsetPatch
+
+		if face.Patch != -1 {
+			face.Patch = patchMap[face.Patch]
+		}
+
+		if face.Material != -1 {
+			face.Material = materialMap[face.Material]
+		}
+
 		m.faces = append(m.faces, face)
 	}
 
+	m.resetEdges()
+
 	for _, halfEdge := range n.halfEdges {
 		halfEdge.Origin += offsetVertex
 		halfEdge.Face += offsetFace
@@ -582,17 +1432,84 @@ func (m *HalfEdgeMesh) Merge(n *HalfEdgeMesh) {
 		m.halfEdges = append(m.halfEdges, halfEdge)
 	}
 
-	for _, patch := range n.patches {
-		m.patches = append(m.patches, patch)
+	for _, set := range n.sets {
+		offset := offsetFace
+
+		if set.Kind == meshx.VertexSet {
+			offset = offsetVertex
+		}
+
+		indices := make([]int, len(set.Indices))
+
+		for i, index := range set.Indices {
+			indices[i] = index + offset
+		}
+
+		if existing, ok := m.GetSet(set.Name, set.Kind); ok {
+			indices = append(existing.Indices, indices...)
+		}
+
+		m.AddSet(set.Name, set.Kind, indices)
+	}
+
+	m.notifyRange(VerticesAdded, offsetVertex, len(m.vertices))
+	m.notifyRange(FacesAdded, offsetFace, len(m.faces))
+}
+
+// Merge the materials of a mesh with the materials of this mesh by name. The
+// map from the materials of the merged mesh to this mesh is returned.
+func (m *HalfEdgeMesh) mergeMaterials(n *HalfEdgeMesh) []int {
+	indexMaterials := make(map[string]int)
+	materialMap := make([]int, n.GetNumberOfMaterials())
+
+	for i, material := range m.materials {
+		if _, ok := indexMaterials[material.Name]; !ok {
+			indexMaterials[material.Name] = i
+		}
+	}
+
+	for i, material := range n.materials {
+		if index, ok := indexMaterials[material.Name]; ok {
+			materialMap[i] = index
+		} else {
+			indexMaterials[material.Name] = len(m.materials)
+			materialMap[i] = len(m.materials)
+			m.materials = append(m.materials, material)
+		}
 	}
+
+	return materialMap
+}
+
+// Index in the original mesh of each entity of an extracted mesh, to
+// transfer per-entity data onto the extracted mesh.
+type ExtractMap struct {
+	Vertices  []int
+	Faces     []int
+	Patches   []int
+	Materials []int
 }
 
-// Extract the faces into a new mesh.
+// Extract the faces into a new mesh. The half edges across the boundary of
+// the faces are boundaries of the new mesh and the faces without a patch or
+// material keep none.
 func (m *HalfEdgeMesh) Extract(faces []int) *HalfEdgeMesh {
+	mesh, _ := m.ExtractWithMap(faces)
+	return mesh
+}
+
+// Extract the faces into a new mesh and map its entities to those of the
+// mesh. The faces are in the order given and the vertices, patches and
+// materials in the order first referenced by the faces. The named sets keep
+// the elements extracted.
+func (m *HalfEdgeMesh) ExtractWithMap(faces []int) (*HalfEdgeMesh, ExtractMap) {
+	m.ensureAdjacency()
+
 	indexVertices := make(map[int]int)
 	indexFaces := make(map[int]int)
 	indexHalfEdges := make(map[int]int)
 	indexPatches := make(map[int]int)
+	indexMaterials := make(map[int]int)
 
 	for newIndex, oldIndex := range faces {
 		indexFaces[oldIndex] = newIndex
@@ -609,11 +1526,19 @@ func (m *HalfEdgeMesh) Extract(faces []int) *HalfEdgeMesh {
 			}
 		}
 
-		if face := m.GetFace(oldIndex); face.Patch != -1 {
+		face := m.GetFace(oldIndex)
+
+		if face.Patch != -1 {
 			if _, ok := indexPatches[face.Patch]; !ok {
 				indexPatches[face.Patch] = len(indexPatches)
 			}
 		}
+
+		if face.Material != -1 {
+			if _, ok := indexMaterials[face.Material]; !ok {
+				indexMaterials[face.Material] = len(indexMaterials)
+			}
+		}
 	}
 
 	mesh := HalfEdgeMesh{
@@ -621,10 +1546,23 @@ func (m *HalfEdgeMesh) Extract(faces []int) *HalfEdgeMesh {
 		faces:     make([]Face, len(faces)),
 		halfEdges: make([]HalfEdge, len(indexHalfEdges)),
 		patches:   make([]Patch, len(indexPatches)),
+		materials: make([]meshx.Material, len(indexMaterials)),
+
+		hasVertexColors: m.hasVertexColors,
+		hasFaceColors:   m.hasFaceColors,
+
+		lazy: &lazyState{},
 	}
 
+	mesh.lazy.adjacent.Store(true)
+
 	for oldIndex, newIndex := range indexPatches {
 		mesh.patches[newIndex] = m.patches[oldIndex]
+		mesh.patches[newIndex].Metadata = m.patches[oldIndex].Metadata.Clone()
+	}
+
+	for oldIndex, newIndex := range indexMaterials {
+		mesh.materials[newIndex] = m.materials[oldIndex]
 	}
 
 	for oldIndex, newIndex := range indexVertices {
@@ -634,12 +1572,16 @@ func (m *HalfEdgeMesh) Extract(faces []int) *HalfEdgeMesh {
 	for oldIndex, newIndex := range indexHalfEdges {
 		halfEdge := m.halfEdges[oldIndex]
 		halfEdge.Origin = indexVertices[halfEdge.Origin]
-		halfEdge.Face = -1
+		halfEdge.Face = indexFaces[halfEdge.Face]
 		halfEdge.Next = indexHalfEdges[halfEdge.Next]
 		halfEdge.Prev = indexHalfEdges[halfEdge.Prev]
 
 		if !halfEdge.IsBoundary() {
-			halfEdge.Twin = indexHalfEdges[halfEdge.Twin]
+			if twin, ok := indexHalfEdges[halfEdge.Twin]; ok {
+				halfEdge.Twin = twin
+			} else {
+				halfEdge.Twin = -1
+			}
 		}
 
 		mesh.halfEdges[newIndex] = halfEdge
@@ -647,16 +1589,64 @@ func (m *HalfEdgeMesh) Extract(faces []int) *HalfEdgeMesh {
 
 	for newIndex, oldIndex := range faces {
 		face := m.faces[oldIndex]
-		face.Patch = indexPatches[face.Patch]
+		if face.Patch != -1 {
+			face.Patch = indexPatches[face.Patch]
+		}
+		if face.Material != -1 {
+			face.Material = indexMaterials[face.Material]
+		}
 		face.HalfEdge = indexHalfEdges[face.HalfEdge]
 		mesh.faces[newIndex] = face
 	}
 
-	return &mesh
+	for _, set := range m.sets {
+		index := indexFaces
+
+		if set.Kind == meshx.VertexSet {
+			index = indexVertices
+		}
+
+		indices := make([]int, 0)
+
+		for _, oldIndex := range set.Indices {
+			if newIndex, ok := index[oldIndex]; ok {
+				indices = append(indices, newIndex)
+			}
+		}
+
+		mesh.AddSet(set.Name, set.Kind, indices)
+	}
+
+	extractMap := ExtractMap{
+		Vertices:  getInverseIndex(indexVertices),
+		Faces:     append([]int{}, faces...),
+		Patches:   getInverseIndex(indexPatches),
+		Materials: getInverseIndex(indexMaterials),
+	}
+
+	return &mesh, extractMap
+}
+
+// Get the old index of each new index of a map from old to new indices.
+func getInverseIndex(index map[int]int) []int {
+	inverse := make([]int, len(index))
+
+	for oldIndex, newIndex := range index {
+		inverse[newIndex] = oldIndex
+	}
+
+	return inverse
 }
 
 // Extract the patches into a new mesh.
 func (m *HalfEdgeMesh) ExtractPatches(patches []int) *HalfEdgeMesh {
+	mesh, _ := m.ExtractPatchesWithMap(patches)
+	return mesh
+}
+
+// Extract the patches into a new mesh and map its entities to those of the
+// mesh. See ExtractWithMap.
+func (m *HalfEdgeMesh) ExtractPatchesWithMap(patches []int) (*HalfEdgeMesh, ExtractMap) {
 	faces := make([]int, 0)
 	indexPatches := make(map[int]bool)
 
@@ -670,7 +1660,7 @@ func (m *HalfEdgeMesh) ExtractPatches(patches []int) *HalfEdgeMesh {
 		}
 	}
 
-	return m.Extract(faces)
+	return m.ExtractWithMap(faces)
 }
 
 // Translate the mesh by a Vector.
@@ -679,6 +1669,50 @@ func (m *HalfEdgeMesh) Translate(offset meshx.Vector) {
 		m.vertices[i] = Vertex{
 			Point:    vertex.Point.Add(offset),
 			HalfEdge: vertex.HalfEdge,
+			Color:    vertex.Color,
+		}
+	}
+
+	m.notify(VerticesMoved, nil)
+}
+
+// Transform the mesh by an affine Matrix4. The faces are flipped if the
+// transformation is mirrored (negative determinant) to keep the orientation.
+func (m *HalfEdgeMesh) Transform(matrix meshx.Matrix4) {
+	for i, vertex := range m.vertices {
+		m.vertices[i] = Vertex{
+			Point:    matrix.MulPoint(vertex.Point),
+			HalfEdge: vertex.HalfEdge,
+			Color:    vertex.Color,
 		}
 	}
+
+	m.notify(VerticesMoved, nil)
+
+	if matrix.Determinant() < 0 {
+		for i := range m.faces {
+			m.flipFace(i)
+		}
+
+		m.notify(FacesChanged, nil)
+	}
+}
+
+// Convert the coordinates of the mesh between coordinate systems (e.g. from
+// millimeters in a Y-up system to meters in a Z-up system).
+func (m *HalfEdgeMesh) Convert(conversion meshx.Conversion) {
+	m.Transform(conversion.GetMatrix4())
+}
+
+// Morph the mesh by deforming its vertices (e.g. by an FFD lattice or RBF).
+func (m *HalfEdgeMesh) Morph(deformation morph.Deformation) {
+	for i, vertex := range m.vertices {
+		m.vertices[i] = Vertex{
+			Point:    deformation.Deform(vertex.Point),
+			HalfEdge: vertex.HalfEdge,
+			Color:    vertex.Color,
+		}
+	}
+
+	m.notify(VerticesMoved, nil)
 }
@@ -0,0 +1,145 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Octree indexing the faces of a HalfEdgeMesh kept synchronized with the
+// mesh by a hook. Moved faces are refit (see Octree.Update and Octree.Refit)
+// and added or deleted faces are inserted or removed, so the octree is only
+// rebuilt if the mesh is reset or a face moves outside of it. The changes are
+// applied when the octree is next requested.
+type MeshOctree struct {
+	mesh     *HalfEdgeMesh
+	options  spatial.OctreeOptions
+	octree   *spatial.Octree
+	hook     int
+	dirty    map[int]bool
+	refitAll bool
+	invalid  bool
+}
+
+// Construct a MeshOctree of the faces with options. The octree is built
+// immediately and the item index of each face is the face index.
+func (m *HalfEdgeMesh) NewMeshOctree(options spatial.OctreeOptions) *MeshOctree {
+	o := &MeshOctree{
+		mesh:    m,
+		options: options,
+		octree:  m.BuildOctreeWithOptions(options),
+		dirty:   make(map[int]bool),
+	}
+
+	o.hook = m.AddHook(o.update)
+
+	return o
+}
+
+// Get the octree synchronized with the mesh. The octree returned must not be
+// used once the mesh changes, as it may be refit or replaced.
+func (o *MeshOctree) GetOctree() *spatial.Octree {
+	if !o.invalid && o.refitAll {
+		o.invalid = o.octree.Refit(o.mesh.getFaceItems()) != nil
+	} else if !o.invalid {
+		for face := range o.dirty {
+			if err := o.octree.Update(face, o.mesh.getFaceItem(face)); err != nil {
+				o.invalid = true
+				break
+			}
+		}
+	}
+
+	if o.invalid {
+		o.octree = o.mesh.BuildOctreeWithOptions(o.options)
+	}
+
+	o.dirty = make(map[int]bool)
+	o.refitAll = false
+	o.invalid = false
+
+	return o.octree
+}
+
+// Return true if the octree is synchronized with the mesh (no changes are
+// pending).
+func (o *MeshOctree) IsSynchronized() bool {
+	return !o.invalid && !o.refitAll && len(o.dirty) == 0
+}
+
+// Rebuild the octree when it is next requested (e.g. after changes to the
+// mesh not notified to its hooks).
+func (o *MeshOctree) Invalidate() {
+	o.invalid = true
+}
+
+// Refit the faces when the octree is next requested (e.g. after their
+// vertices are moved without notifying the hooks).
+func (o *MeshOctree) InvalidateFaces(faces []int) {
+	for _, face := range faces {
+		o.dirty[face] = true
+	}
+}
+
+// Stop synchronizing the octree with the mesh by removing its hook.
+func (o *MeshOctree) Close() {
+	o.mesh.RemoveHook(o.hook)
+}
+
+// Record a mutation of the mesh.
+func (o *MeshOctree) update(event MeshEvent, indices []int) {
+	if o.invalid {
+		return
+	}
+
+	switch event {
+	case VerticesMoved:
+		if indices == nil {
+			o.refitAll = true
+			return
+		}
+
+		moved := make(map[int]bool)
+
+		for _, vertex := range indices {
+			moved[vertex] = true
+		}
+
+		for _, halfEdge := range o.mesh.halfEdges {
+			if moved[halfEdge.Origin] {
+				o.dirty[halfEdge.Face] = true
+			}
+		}
+	case FacesChanged:
+		if indices == nil {
+			o.refitAll = true
+			return
+		}
+
+		o.InvalidateFaces(indices)
+	case FacesAdded:
+		for _, face := range indices {
+			if face != o.octree.GetNumberOfItems() {
+				o.invalid = true
+				return
+			}
+
+			if err := o.octree.Insert(o.mesh.getFaceItem(face)); err != nil {
+				o.invalid = true
+				return
+			}
+		}
+	case FacesDeleted:
+		for _, face := range indices {
+			last := o.octree.GetNumberOfItems() - 1
+			o.octree.Remove(face)
+
+			o.dirty[face] = o.dirty[last] && face != last
+			delete(o.dirty, last)
+
+			if !o.dirty[face] {
+				delete(o.dirty, face)
+			}
+		}
+	case MeshReset:
+		o.invalid = true
+	}
+}
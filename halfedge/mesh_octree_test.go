@@ -0,0 +1,88 @@
+package halfedge
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+	"github.com/stretchr/testify/assert"
+)
+
+// Assert the octree of a MeshOctree finds the same faces as brute force.
+func assertMeshOctreeQuery(t *testing.T, mesh *HalfEdgeMesh, octree *MeshOctree) {
+	queries := []meshx.AABB{
+		meshx.NewAABB(meshx.NewVector(1, 1, 0), meshx.NewVector(0.5, 0.5, 0.1)),
+		meshx.NewAABB(meshx.NewVector(2.5, 1.5, 0), meshx.NewVector(0.3, 0.6, 0.1)),
+	}
+
+	for _, query := range queries {
+		expected := make([]int, 0)
+
+		for i := range mesh.GetNumberOfFaces() {
+			if mesh.getFaceItem(i).IntersectsAABB(query) {
+				expected = append(expected, i)
+			}
+		}
+
+		items := octree.GetOctree().Query(query)
+		sort.Ints(items)
+		assert.NotEmpty(t, expected)
+		assert.Equal(t, expected, items)
+	}
+}
+
+// Test the octree is refit as the vertices of the mesh move.
+func TestMeshOctreeRefit(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 4)
+	octree := mesh.NewMeshOctree(spatial.OctreeOptions{MaxDepth: 4, MaxLeafItems: 4})
+	built := octree.GetOctree()
+	assert.True(t, octree.IsSynchronized())
+
+	center := meshx.NewVector(2, 2, 0)
+	matrix := meshx.NewTranslationMatrix4(center).
+		Mul(meshx.NewScaleMatrix4(meshx.NewVector(0.9, 0.8, 1))).
+		Mul(meshx.NewTranslationMatrix4(center.MulScalar(-1)))
+
+	mesh.Transform(matrix)
+	assert.False(t, octree.IsSynchronized())
+	assertMeshOctreeQuery(t, mesh, octree)
+	assert.Same(t, built, octree.GetOctree())
+
+	// The mesh moved outside the octree is rebuilt.
+	mesh.Translate(meshx.NewVector(10, 0, 0))
+	assert.NotSame(t, built, octree.GetOctree())
+	mesh.Translate(meshx.NewVector(-10, 0, 0))
+	assertMeshOctreeQuery(t, mesh, octree)
+
+	octree.Close()
+	mesh.Translate(meshx.NewVector(1, 0, 0))
+	assert.True(t, octree.IsSynchronized())
+}
+
+// Test the octree follows the edits of the mesh.
+func TestMeshOctreeEdits(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 4)
+	octree := mesh.NewMeshOctree(spatial.OctreeOptions{MaxDepth: 4, MaxLeafItems: 4})
+	built := octree.GetOctree()
+
+	_, err := mesh.SplitEdge(findHalfEdge(mesh, 6, 12), meshx.NewVector(1.6, 1.4, 0))
+	assert.Empty(t, err)
+	assertMeshOctreeQuery(t, mesh, octree)
+
+	assert.Empty(t, mesh.CollapseEdge(findHalfEdge(mesh, 7, 13), meshx.NewVector(2.4, 1.4, 0)))
+	assert.Empty(t, mesh.FlipEdge(findHalfEdge(mesh, 11, 17)))
+	assert.Empty(t, mesh.DeleteFace(0))
+	assertMeshOctreeQuery(t, mesh, octree)
+	assert.Same(t, built, octree.GetOctree())
+	assert.Equal(t, mesh.GetNumberOfFaces(), built.GetNumberOfItems())
+
+	mesh.StartJournal(0)
+	assert.Empty(t, mesh.DeleteFace(5))
+	assert.Empty(t, mesh.Undo())
+	assert.False(t, octree.IsSynchronized())
+	assertMeshOctreeQuery(t, mesh, octree)
+
+	octree.Invalidate()
+	assert.NotSame(t, built, octree.GetOctree())
+}
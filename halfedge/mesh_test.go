@@ -0,0 +1,539 @@
+package halfedge
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/morph"
+	"github.com/ajcurley/meshx-go/spatial"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the minimal bounding sphere of a mesh.
+func TestGetBoundingSphere(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	sphere := mesh.GetBoundingSphere()
+	assert.InDelta(t, 0, sphere.Center.Sub(meshx.NewVector(0.5, 0.5, 0.5)).Mag(), 1e-12)
+	assert.InDelta(t, math.Sqrt(3)/2, sphere.Radius, 1e-12)
+}
+
+// Test the bounding box of each patch of a mesh.
+func TestGetPatchAABBs(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	aabbs := mesh.GetPatchAABBs()
+	assert.Equal(t, mesh.GetNumberOfPatches(), len(aabbs))
+
+	for i, aabb := range aabbs {
+		if mesh.GetPatch(i).Name == "front" {
+			assert.Equal(t, meshx.NewVector(0, 0, 0), aabb.GetMinBound())
+			assert.Equal(t, meshx.NewVector(0, 1, 1), aabb.GetMaxBound())
+		}
+
+		assert.Equal(t, aabb, mesh.GetPatchAABB(i))
+		assert.Equal(t, 0.0, min(aabb.HalfSize[0], aabb.HalfSize[1], aabb.HalfSize[2]))
+	}
+}
+
+// Test writing and reading the octree of a mesh.
+func TestReadOctree(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	octree := mesh.BuildOctree()
+
+	var buffer bytes.Buffer
+	assert.Empty(t, octree.Write(&buffer))
+
+	result, err := mesh.ReadOctree(&buffer)
+	assert.Empty(t, err)
+
+	ray := meshx.NewRay(meshx.NewVector(-1, 0.3, 0.6), meshx.NewVector(1, 0.1, -0.1))
+	assert.Equal(t, octree.RaycastAll(ray), result.RaycastAll(ray))
+}
+
+// Test constructing a mesh and its octree with progress and cancellation.
+func TestNewHalfEdgeMeshContext(t *testing.T) {
+	reader, err := meshx.ReadOBJFromPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	var completed, total int64
+	progress := func(c, t int64) { completed, total = c, t }
+
+	mesh, err := NewHalfEdgeMeshContext(context.Background(), reader, progress)
+	assert.Empty(t, err)
+	assert.Equal(t, int64(mesh.GetNumberOfFaces()), completed)
+	assert.Equal(t, int64(mesh.GetNumberOfFaces()), total)
+
+	completed, total = 0, 0
+	octree, err := mesh.BuildOctreeContext(context.Background(), spatial.OctreeOptions{}, progress)
+	assert.Empty(t, err)
+	assert.NotNil(t, octree)
+	assert.Equal(t, int64(mesh.GetNumberOfFaces()), completed)
+	assert.Equal(t, int64(mesh.GetNumberOfFaces()), total)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = NewHalfEdgeMeshContext(ctx, reader, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = mesh.BuildOctreeContext(ctx, spatial.OctreeOptions{}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// Test the materials of a mesh survive writing and reading an OBJ file.
+func TestWriteOBJToPathMaterials(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.materials.obj")
+	assert.Empty(t, err)
+	assert.Equal(t, 3, mesh.GetNumberOfMaterials())
+	assert.Equal(t, []int{0, 1, 4, 5, 6}, mesh.GetMaterialFaces(0))
+	assert.Equal(t, []int{2, 3}, mesh.GetMaterialFaces(1))
+
+	path := filepath.Join(t.TempDir(), "result.obj")
+	assert.Empty(t, mesh.WriteOBJToPath(path))
+
+	materials, err := meshx.ReadMTLFromPath(filepath.Join(filepath.Dir(path), "result.mtl"))
+	assert.Empty(t, err)
+	assert.Equal(t, 3, len(materials))
+
+	result, err := NewHalfEdgeMeshFromOBJPath(path)
+	assert.Empty(t, err)
+	assert.Equal(t, mesh.GetNumberOfMaterials(), result.GetNumberOfMaterials())
+
+	for i := range mesh.GetNumberOfMaterials() {
+		assert.Equal(t, mesh.GetMaterial(i), result.GetMaterial(i))
+	}
+
+	for i := range mesh.GetNumberOfPatches() {
+		faces := mesh.GetPatchFaces(i)
+		resultFaces := result.GetPatchFaces(i)
+		assert.Equal(t, len(faces), len(resultFaces))
+
+		for j := range faces {
+			assert.Equal(t, mesh.GetFace(faces[j]).Material, result.GetFace(resultFaces[j]).Material)
+		}
+	}
+}
+
+// Test constructing open and non-manifold meshes. An open mesh is accepted
+// with the half edges of its open edges as boundaries.
+func TestNewHalfEdgeMeshOpen(t *testing.T) {
+	mesh, err := NewHalfEdgeMesh(newTwinTestSource(3))
+	assert.Empty(t, err)
+	assert.False(t, mesh.IsClosed())
+	assert.Equal(t, 1, len(mesh.GetBoundaryLoops()))
+	assert.Equal(t, 8, len(mesh.GetBoundaryLoops()[0]))
+
+	source := newTwinTestSource(3)
+	source.vertices = append(source.vertices, meshx.NewVector(0, 0, 1))
+	source.addFace([]int{1, len(source.vertices) - 1, 4}, Face{Patch: -1, Material: -1})
+
+	_, err = NewHalfEdgeMesh(source)
+	assert.ErrorIs(t, err, meshx.ErrNonManifold)
+}
+
+// Test extracting faces leaves the half edges across the cut as boundaries
+// and the faces without a patch or material without one.
+func TestExtractBoundary(t *testing.T) {
+	mesh := newTestCube(t)
+
+	extracted := mesh.Extract([]int{1, 3})
+	assert.Equal(t, 2, extracted.GetNumberOfFaces())
+	assert.Equal(t, 6, extracted.GetNumberOfVertices())
+	assert.Equal(t, 1, len(extracted.GetBoundaryLoops()))
+
+	var boundary int
+
+	for i := range extracted.GetNumberOfFaces() {
+		assert.Equal(t, -1, extracted.GetFace(i).Patch)
+		assert.Equal(t, -1, extracted.GetFace(i).Material)
+
+		for _, id := range extracted.GetFaceHalfEdges(i) {
+			halfEdge := extracted.GetHalfEdge(id)
+			assert.Equal(t, i, halfEdge.Face)
+
+			if halfEdge.IsBoundary() {
+				boundary++
+			} else {
+				assert.Equal(t, id, extracted.GetHalfEdge(halfEdge.Twin).Twin)
+			}
+		}
+	}
+
+	assert.Equal(t, 6, boundary)
+}
+
+// Test the materials of extracted and merged meshes.
+func TestExtractMaterials(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.materials.obj")
+	assert.Empty(t, err)
+
+	extracted := mesh.Extract([]int{2, 3, 4})
+	assert.Equal(t, 2, extracted.GetNumberOfMaterials())
+	assert.Equal(t, "blue", extracted.GetMaterial(extracted.GetFace(1).Material).Name)
+	assert.Equal(t, "red", extracted.GetMaterial(extracted.GetFace(2).Material).Name)
+
+	mesh.Merge(extracted)
+	assert.Equal(t, 3, mesh.GetNumberOfMaterials())
+	assert.Equal(t, 1, mesh.GetFace(8).Material)
+	assert.Equal(t, 0, mesh.GetFace(9).Material)
+}
+
+// Test the map of the entities of an extracted mesh to the original.
+func TestExtractWithMap(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.materials.obj")
+	assert.Empty(t, err)
+
+	faces := []int{4, 2, 3}
+	extracted, extractMap := mesh.ExtractWithMap(faces)
+	assert.Equal(t, faces, extractMap.Faces)
+	assert.Equal(t, extracted.GetNumberOfVertices(), len(extractMap.Vertices))
+	assert.Equal(t, extracted.GetNumberOfMaterials(), len(extractMap.Materials))
+
+	for i, vertex := range extractMap.Vertices {
+		assert.Equal(t, mesh.GetVertex(vertex).Point, extracted.GetVertex(i).Point)
+	}
+
+	for i, face := range extractMap.Faces {
+		assert.Equal(t, mesh.GetFaceNormal(face), extracted.GetFaceNormal(i))
+
+		if material := extracted.GetFace(i).Material; material != -1 {
+			assert.Equal(t, mesh.GetFace(face).Material, extractMap.Materials[material])
+		}
+	}
+
+	patches, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	extracted, extractMap = patches.ExtractPatchesWithMap([]int{5, 1})
+	assert.Equal(t, []int{1, 2, 6}, extractMap.Faces)
+	assert.Equal(t, []int{1, 5}, extractMap.Patches)
+	assert.Equal(t, "back", extracted.GetPatch(0).Name)
+}
+
+// Test the colors of a mesh survive writing and reading a PLY file.
+func TestWritePLYColors(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+	assert.False(t, mesh.HasVertexColors())
+	assert.False(t, mesh.HasFaceColors())
+
+	vertexColors := make([]meshx.Color, mesh.GetNumberOfVertices())
+	faceColors := make([]meshx.Color, mesh.GetNumberOfFaces())
+
+	for i := range vertexColors {
+		vertexColors[i] = meshx.NewColor(uint8(10*i), 0, 0, 255)
+	}
+
+	for i := range faceColors {
+		faceColors[i] = meshx.NewColor(0, uint8(10*i), 0, 255)
+	}
+
+	mesh.SetVertexColors(vertexColors)
+	mesh.SetFaceColors(faceColors)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, mesh.WritePLY(&buffer, meshx.PLYFormatBinaryLittleEndian))
+
+	result, err := NewHalfEdgeMeshFromPLY(&buffer)
+	assert.Empty(t, err)
+	assert.True(t, result.HasVertexColors())
+	assert.True(t, result.HasFaceColors())
+	assert.Equal(t, mesh.GetNumberOfPatches(), result.GetNumberOfPatches())
+
+	for i := range mesh.GetNumberOfVertices() {
+		assert.Equal(t, mesh.GetVertex(i), result.GetVertex(i))
+	}
+
+	for i := range mesh.GetNumberOfFaces() {
+		assert.Equal(t, mesh.GetFace(i), result.GetFace(i))
+	}
+
+	extracted := result.Extract([]int{3})
+	assert.True(t, extracted.HasFaceColors())
+	assert.Equal(t, faceColors[3], extracted.GetFace(0).Color)
+
+	result.ClearFaceColors()
+	assert.False(t, result.HasFaceColors())
+	assert.Equal(t, meshx.ColorWhite, result.GetFace(3).Color)
+}
+
+// Test the faces and patches of a mesh survive writing and reading an MSH
+// file.
+func TestWriteMSH(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	for _, version := range []meshx.MSHVersion{meshx.MSHVersion2, meshx.MSHVersion4} {
+		var buffer bytes.Buffer
+		assert.Empty(t, mesh.WriteMSH(&buffer, version))
+
+		result, err := NewHalfEdgeMeshFromMSH(&buffer)
+		assert.Empty(t, err)
+		assert.Equal(t, mesh.GetNumberOfVertices(), result.GetNumberOfVertices())
+		assert.Equal(t, mesh.GetNumberOfFaces(), result.GetNumberOfFaces())
+		assert.Equal(t, mesh.GetNumberOfPatches(), result.GetNumberOfPatches())
+		assert.True(t, result.IsClosed())
+
+		for i := range mesh.GetNumberOfPatches() {
+			assert.Equal(t, mesh.GetPatch(i).Name, result.GetPatch(i).Name)
+			assert.Equal(t, len(mesh.GetPatchFaces(i)), len(result.GetPatchFaces(i)))
+		}
+	}
+}
+
+// Test morphing a mesh by the landmarks of its corners.
+func TestMorph(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	sources := make([]meshx.Vector, mesh.GetNumberOfVertices())
+	targets := make([]meshx.Vector, mesh.GetNumberOfVertices())
+
+	for i := range sources {
+		sources[i] = mesh.GetVertex(i).Point
+		targets[i] = sources[i].MulScalar(1 + sources[i][2])
+	}
+
+	rbf, err := morph.NewRBF(sources, targets)
+	assert.Empty(t, err)
+
+	mesh.Morph(rbf)
+
+	for i, target := range targets {
+		assert.InDelta(t, 0, mesh.GetVertex(i).Point.Sub(target).Mag(), 1e-9)
+	}
+
+	assert.True(t, mesh.IsClosed())
+}
+
+// Test converting a mesh to a left-handed system keeps the faces outward.
+func TestConvert(t *testing.T) {
+	mesh := newTestCylinder(t, 1, 0, 1)
+	volume := getTestVolume(mesh)
+	system := meshx.CoordinateSystem{Unit: meshx.UnitCentimeter, UpAxis: meshx.UpAxisY, LeftHanded: true}
+	mesh.Convert(meshx.NewConversion(meshx.CoordinateSystem{}, system))
+
+	assert.True(t, mesh.IsClosed())
+	assert.True(t, mesh.IsConsistent())
+	assert.InDelta(t, volume*1e6, getTestVolume(mesh), 1e-6)
+
+	// The Y and Z axes are swapped by the change of handedness.
+	aabb := mesh.GetAABB()
+	assert.InDelta(t, 0, aabb.Center.Sub(meshx.NewVector(0, 50, 0)).Mag(), 1e-9)
+	assert.InDelta(t, 0, aabb.HalfSize.Sub(meshx.NewVector(100, 50, 100)).Mag(), 1e-9)
+}
+
+// Test the accessors into a buffer match those allocating a slice.
+func TestGetFaceInto(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	buf := make([]int, 0, 2)
+
+	for i := range mesh.GetNumberOfFaces() {
+		buf = mesh.GetFaceVerticesInto(i, buf)
+		assert.Equal(t, mesh.GetFaceVertices(i), buf)
+
+		buf = mesh.GetFaceHalfEdgesInto(i, buf)
+		assert.Equal(t, mesh.GetFaceHalfEdges(i), buf)
+
+		buf = mesh.GetFaceNeighborsInto(i, buf)
+		assert.Equal(t, mesh.GetFaceNeighbors(i), buf)
+	}
+
+	assert.Equal(t, 0.0, testing.AllocsPerRun(10, func() {
+		buf = mesh.GetFaceVerticesInto(0, buf)
+	}))
+}
+
+// Benchmark getting the vertices of a million quads.
+func BenchmarkGetFaceVertices(b *testing.B) {
+	mesh, err := NewHalfEdgeMesh(newTwinTestSource(1001))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		for i := range mesh.GetNumberOfFaces() {
+			mesh.GetFaceVertices(i)
+		}
+	}
+}
+
+// Benchmark getting the vertices of a million quads into a buffer.
+func BenchmarkGetFaceVerticesInto(b *testing.B) {
+	mesh, err := NewHalfEdgeMesh(newTwinTestSource(1001))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	buf := make([]int, 0, 4)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		for i := range mesh.GetNumberOfFaces() {
+			buf = mesh.GetFaceVerticesInto(i, buf)
+		}
+	}
+}
+
+// Benchmark getting the half edges of a million quads. The adjacency is
+// built, so the lazy adjacency check is lock-free.
+func BenchmarkGetHalfEdge(b *testing.B) {
+	mesh, err := NewHalfEdgeMesh(newTwinTestSource(1001))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		for i := range mesh.GetNumberOfHalfEdges() {
+			mesh.GetHalfEdge(i)
+		}
+	}
+}
+
+// Test the sign of the dihedral angle of convex and concave edges.
+func TestGetHalfEdgeDihedralAngle(t *testing.T) {
+	mesh := newTestCube(t)
+
+	for i := range mesh.GetNumberOfHalfEdges() {
+		assert.InDelta(t, math.Pi/2, mesh.GetHalfEdgeDihedralAngle(i), 1e-12)
+	}
+
+	for i := range mesh.GetNumberOfEdges() {
+		assert.InDelta(t, math.Pi/2, mesh.GetEdgeDihedralAngle(i), 1e-12)
+	}
+
+	for i := range mesh.GetNumberOfFaces() {
+		mesh.flipFace(i)
+	}
+
+	for i := range mesh.GetNumberOfHalfEdges() {
+		assert.InDelta(t, -math.Pi/2, mesh.GetHalfEdgeDihedralAngle(i), 1e-12)
+	}
+}
+
+// Test the edges pair the twin half edges.
+func TestGetEdges(t *testing.T) {
+	mesh := newTestCube(t)
+	assert.Equal(t, 12, mesh.GetNumberOfEdges())
+
+	for i := range mesh.GetNumberOfEdges() {
+		edge := mesh.GetEdge(i)
+		assert.False(t, edge.IsBoundary())
+		assert.Equal(t, edge.Twin, mesh.GetHalfEdge(edge.HalfEdge).Twin)
+		assert.Equal(t, i, mesh.GetHalfEdgeEdge(edge.HalfEdge))
+		assert.Equal(t, i, mesh.GetHalfEdgeEdge(edge.Twin))
+		assert.Len(t, mesh.GetEdgeFaces(i), 2)
+
+		vertices := mesh.GetEdgeVertices(i)
+		assert.NotEqual(t, vertices[0], vertices[1])
+	}
+
+	open := mesh.Extract([]int{0, 1, 2})
+	assert.Equal(t, 10, open.GetNumberOfEdges())
+
+	var boundary int
+
+	for i := range open.GetNumberOfEdges() {
+		if open.GetEdge(i).IsBoundary() {
+			boundary++
+			assert.Len(t, open.GetEdgeFaces(i), 1)
+			assert.Equal(t, 0.0, open.GetEdgeDihedralAngle(i))
+		}
+	}
+
+	assert.Equal(t, 8, boundary)
+
+	open.Merge(mesh)
+	assert.Equal(t, 22, open.GetNumberOfEdges())
+}
+
+// Test the centroid of a face.
+func TestGetFaceCentroid(t *testing.T) {
+	mesh := newTestCube(t)
+
+	for i := range mesh.GetNumberOfFaces() {
+		centroid := mesh.GetFaceCentroid(i)
+		offset := centroid.Sub(meshx.NewVector(0.5, 0.5, 0.5))
+		assert.InDelta(t, 0.5, offset.Mag(), 1e-12)
+		assert.InDelta(t, 0.5, offset.Dot(mesh.GetFaceNormal(i).Unit()), 1e-12)
+	}
+}
+
+// Test a closed mesh with a baffle.
+func TestBaffles(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshWithOptions(newBaffleTestSource(), HalfEdgeMeshOptions{Baffles: []string{"baffle"}})
+	assert.Empty(t, err)
+	assert.True(t, mesh.IsClosed())
+	assert.True(t, mesh.IsConsistent())
+
+	// The baffle is oriented independently of the enclosing surface.
+	mesh.flipFace(1)
+	mesh.flipFace(10)
+	assert.False(t, mesh.IsConsistent())
+	mesh.Orient()
+	assert.True(t, mesh.IsConsistent())
+	assert.Equal(t, meshx.NewVector(1, 0, 0), mesh.GetFaceNormal(1).Unit())
+	assert.Equal(t, meshx.NewVector(-1, 0, 0), mesh.GetFaceNormal(10).Unit())
+
+	// The baffles are retained by the mesh reader.
+	copied, err := NewHalfEdgeMesh(mesh.GetMeshReader())
+	assert.Empty(t, err)
+	assert.True(t, copied.GetPatch(1).IsBaffle)
+
+	// The baffle cannot be untagged while it shares the edges of the walls.
+	assert.ErrorIs(t, mesh.SetPatchBaffle(1, false), meshx.ErrNonManifold)
+	assert.True(t, mesh.GetPatch(1).IsBaffle)
+
+	// The open edges of baffles are ignored.
+	open := newTestCube(t)
+	assert.Empty(t, open.DeleteFace(0))
+	assert.False(t, open.IsClosed())
+	patch := open.AddPatch("screen")
+
+	for i := range open.GetNumberOfFaces() {
+		open.SetFacePatch(i, patch)
+	}
+
+	assert.Empty(t, open.SetPatchBaffle(patch, true))
+	assert.True(t, open.IsBaffleFace(0))
+	assert.True(t, open.IsClosed())
+}
+
+// Test the face normals of a mesh transformed by a mirrored non-uniform
+// scale and shear.
+func TestTransform(t *testing.T) {
+	mesh := newTestCube(t)
+	normals := make([]meshx.Vector, mesh.GetNumberOfFaces())
+
+	for i := range normals {
+		normals[i] = mesh.GetFaceNormal(i)
+	}
+
+	matrix := meshx.NewScaleMatrix4(meshx.NewVector(-1, 2, 3))
+	matrix[1][2] = 0.5
+	mesh.Transform(matrix)
+
+	assert.True(t, mesh.IsConsistent())
+
+	for i, normal := range normals {
+		assert.InDelta(t, 0, mesh.GetFaceNormal(i).Sub(matrix.MulNormal(normal)).Mag(), 1e-12)
+	}
+}
@@ -0,0 +1,134 @@
+package halfedge
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Default number of rays cast per point for the occlusion.
+const DefaultOcclusionSamples = 64
+
+// Options to compute the ambient occlusion of a mesh.
+type OcclusionOptions struct {
+	// Number of rays cast per point. The default is
+	// DefaultOcclusionSamples.
+	Samples int
+
+	// Maximum distance of an occluding face. Zero is unlimited.
+	MaxDistance float64
+}
+
+// Compute the ambient occlusion of each vertex: the fraction of rays cast
+// over the hemisphere about the vertex normal (cosine weighted) that hit the
+// mesh. The occlusion is zero for a fully visible vertex and one for a
+// vertex enclosed by the mesh (e.g. inside a cavity). The values may be
+// colorized with ColorizeVertices.
+func (m *HalfEdgeMesh) ComputeVertexOcclusion(options OcclusionOptions) []float64 {
+	normals := m.getVertexNormals()
+	occlusion := make([]float64, m.GetNumberOfVertices())
+
+	if m.GetNumberOfFaces() == 0 {
+		return occlusion
+	}
+
+	octree := m.BuildOctree()
+	aabb := m.GetAABB()
+
+	for i, vertex := range m.vertices {
+		occlusion[i] = getOcclusion(octree, aabb, vertex.Point, normals[i], options)
+	}
+
+	return occlusion
+}
+
+// Compute the ambient occlusion of each face from the centroid of its
+// vertices. See ComputeVertexOcclusion. The values may be colorized with
+// ColorizeFaces.
+func (m *HalfEdgeMesh) ComputeFaceOcclusion(options OcclusionOptions) []float64 {
+	occlusion := make([]float64, m.GetNumberOfFaces())
+
+	if m.GetNumberOfFaces() == 0 {
+		return occlusion
+	}
+
+	octree := m.BuildOctree()
+	aabb := m.GetAABB()
+
+	for i := range occlusion {
+		var center meshx.Vector
+
+		points := m.getFacePoints(i)
+
+		for _, point := range points {
+			center = center.Add(point)
+		}
+
+		center = center.DivScalar(float64(len(points)))
+		occlusion[i] = getOcclusion(octree, aabb, center, m.GetFaceNormal(i), options)
+	}
+
+	return occlusion
+}
+
+// Compute the fraction of rays from a point over the hemisphere about the
+// normal that hit an octree of faces. The rays start slightly off the
+// surface to avoid hitting the faces at the point. A point without a normal
+// is not occluded.
+func getOcclusion(octree *spatial.Octree, aabb meshx.AABB, point, normal meshx.Vector, options OcclusionOptions) float64 {
+	if normal.Mag() == 0 {
+		return 0
+	}
+
+	samples := options.Samples
+
+	if samples <= 0 {
+		samples = DefaultOcclusionSamples
+	}
+
+	length := 4 * aabb.HalfSize.Mag()
+	offset := 1e-6 * length
+
+	if options.MaxDistance > 0 {
+		length = min(length, options.MaxDistance)
+	}
+
+	origin := point.Add(normal.MulScalar(offset))
+	var hits int
+
+	for _, direction := range getHemisphereDirections(normal, samples) {
+		segment := meshx.NewSegment(origin, origin.Add(direction.MulScalar(length)))
+
+		if len(octree.Query(segment)) > 0 {
+			hits++
+		}
+	}
+
+	return float64(hits) / float64(samples)
+}
+
+// Get a number of unit directions over the hemisphere about the unit normal
+// with a cosine-weighted density. The directions are deterministic (a golden
+// angle spiral on the unit disk projected onto the hemisphere).
+func getHemisphereDirections(normal meshx.Vector, count int) []meshx.Vector {
+	axis := meshx.NewVector(1, 0, 0)
+
+	if math.Abs(normal[0]) > 0.9 {
+		axis = meshx.NewVector(0, 1, 0)
+	}
+
+	u := axis.Cross(normal).Unit()
+	v := normal.Cross(u)
+	golden := math.Pi * (3 - math.Sqrt(5))
+	directions := make([]meshx.Vector, count)
+
+	for i := range directions {
+		r := math.Sqrt((float64(i) + 0.5) / float64(count))
+		theta := golden * float64(i)
+		x, y, z := r*math.Cos(theta), r*math.Sin(theta), math.Sqrt(1-r*r)
+		directions[i] = u.MulScalar(x).Add(v.MulScalar(y)).Add(normal.MulScalar(z))
+	}
+
+	return directions
+}
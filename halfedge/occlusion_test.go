@@ -0,0 +1,56 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Construct a closed cylinder along the Z-axis.
+func newTestCylinder(t *testing.T, radius, z0, z1 float64) *HalfEdgeMesh {
+	profile := []meshx.Vector{
+		meshx.NewVector(0, 0, z0),
+		meshx.NewVector(radius, 0, z0),
+		meshx.NewVector(radius, 0, z1),
+		meshx.NewVector(0, 0, z1),
+	}
+
+	mesh, err := NewRevolve(profile, meshx.Vector{}, meshx.NewVector(0, 0, 1), 16)
+	assert.Empty(t, err)
+	return mesh
+}
+
+// Test the occlusion of a cylinder enclosed by another.
+func TestComputeOcclusion(t *testing.T) {
+	mesh := newTestCylinder(t, 2, 0, 3)
+	outerVertices := mesh.GetNumberOfVertices()
+	outerFaces := mesh.GetNumberOfFaces()
+	mesh.Merge(newTestCylinder(t, 1, 1, 2))
+
+	vertexOcclusion := mesh.ComputeVertexOcclusion(OcclusionOptions{})
+	faceOcclusion := mesh.ComputeFaceOcclusion(OcclusionOptions{Samples: 16})
+
+	for i, occlusion := range vertexOcclusion {
+		if i < outerVertices {
+			assert.Equal(t, 0.0, occlusion)
+		} else {
+			assert.Equal(t, 1.0, occlusion)
+		}
+	}
+
+	for i, occlusion := range faceOcclusion {
+		if i < outerFaces {
+			assert.Equal(t, 0.0, occlusion)
+		} else {
+			assert.Equal(t, 1.0, occlusion)
+		}
+	}
+
+	// The enclosing cylinder is at least a unit distance away.
+	options := OcclusionOptions{MaxDistance: 0.5}
+
+	for _, occlusion := range mesh.ComputeVertexOcclusion(options) {
+		assert.Equal(t, 0.0, occlusion)
+	}
+}
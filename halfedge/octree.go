@@ -0,0 +1,189 @@
+package halfedge
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Face of a HalfEdgeMesh as a triangle fan indexed by an octree.
+type faceItem struct {
+	triangles []meshx.Triangle
+}
+
+// Implement the IntersectsAABB interface.
+func (f faceItem) IntersectsAABB(query meshx.AABB) bool {
+	for _, triangle := range f.triangles {
+		if triangle.IntersectsAABB(query) {
+			return true
+		}
+	}
+	return false
+}
+
+// Implement the IntersectsRay interface. Both sides of the face are
+// considered for intersection.
+func (f faceItem) IntersectsRay(query meshx.Ray) bool {
+	for _, triangle := range f.triangles {
+		if query.IntersectsTriangleTwoSided(triangle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Implement the Raycast interface. Both sides of the face are considered.
+func (f faceItem) Raycast(query meshx.Ray) []meshx.Hit {
+	var hits []meshx.Hit
+
+	for _, triangle := range f.triangles {
+		hits = append(hits, triangle.Raycast(query)...)
+	}
+
+	return hits
+}
+
+// Implement the IntersectsSegment interface.
+func (f faceItem) IntersectsSegment(query meshx.Segment) bool {
+	for _, triangle := range f.triangles {
+		if query.IntersectsTriangle(triangle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Implement the IntersectsCylinder interface.
+func (f faceItem) IntersectsCylinder(query meshx.Cylinder) bool {
+	for _, triangle := range f.triangles {
+		if query.IntersectsTriangle(triangle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Implement the IntersectsCapsule interface.
+func (f faceItem) IntersectsCapsule(query meshx.Capsule) bool {
+	for _, triangle := range f.triangles {
+		if query.IntersectsTriangle(triangle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Implement the IntersectsSphere interface.
+func (f faceItem) IntersectsSphere(query meshx.Sphere) bool {
+	for _, triangle := range f.triangles {
+		if query.IntersectsTriangle(triangle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Implement the IntersectsPolytope interface.
+func (f faceItem) IntersectsPolytope(query meshx.Polytope) bool {
+	for _, triangle := range f.triangles {
+		if query.IntersectsTriangle(triangle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Implement the ClosestPoint interface.
+func (f faceItem) ClosestPoint(point meshx.Vector) meshx.Vector {
+	var closest meshx.Vector
+
+	distance := math.Inf(1)
+
+	for _, triangle := range f.triangles {
+		candidate := triangle.ClosestPoint(point)
+
+		if d := candidate.Sub(point).Mag(); d < distance {
+			closest = candidate
+			distance = d
+		}
+	}
+
+	return closest
+}
+
+// Build an octree indexing the faces. The item index of each face in the
+// octree is the face index.
+func (m *HalfEdgeMesh) BuildOctree() *spatial.Octree {
+	return m.BuildOctreeWithOptions(spatial.OctreeOptions{})
+}
+
+// Build an octree indexing the faces with options. The item index of each
+// face in the octree is the face index.
+func (m *HalfEdgeMesh) BuildOctreeWithOptions(options spatial.OctreeOptions) *spatial.Octree {
+	octree, _ := m.BuildOctreeContext(context.Background(), options, nil)
+	return octree
+}
+
+// Build an octree indexing the faces with options, cancellation and progress
+// reported as the number of faces inserted. The item index of each face in
+// the octree is the face index.
+func (m *HalfEdgeMesh) BuildOctreeContext(ctx context.Context, options spatial.OctreeOptions, progress meshx.ProgressFunc) (*spatial.Octree, error) {
+	aabb := m.GetAABB()
+	aabb.HalfSize = aabb.HalfSize.AddScalar(1e-6 * aabb.HalfSize.Mag())
+	octree := spatial.NewOctreeWithOptions(aabb.Buffer(0.01), options)
+	items := m.getFaceItems()
+
+	for i, item := range items {
+		if i%meshx.ProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			progress.Report(int64(i), int64(len(items)))
+		}
+
+		octree.Insert(item)
+	}
+
+	progress.Report(int64(len(items)), int64(len(items)))
+
+	return octree, nil
+}
+
+// Read an octree indexing the faces written by Octree.Write. The octree must
+// have been built by BuildOctree for the same mesh.
+func (m *HalfEdgeMesh) ReadOctree(reader io.Reader) (*spatial.Octree, error) {
+	return spatial.ReadOctree(reader, m.getFaceItems())
+}
+
+// Read an octree indexing the faces from a file written by
+// Octree.WriteToPath.
+func (m *HalfEdgeMesh) ReadOctreeFromPath(path string) (*spatial.Octree, error) {
+	return spatial.ReadOctreeFromPath(path, m.getFaceItems())
+}
+
+// Refit an octree built by BuildOctree to the faces after their vertices
+// moved (e.g. by smoothing or morphing) without rebuilding it. See
+// Octree.Refit and NewMeshOctree to refit an octree as the mesh changes.
+func (m *HalfEdgeMesh) RefitOctree(octree *spatial.Octree) error {
+	return octree.Refit(m.getFaceItems())
+}
+
+// Get the faces as octree items.
+func (m *HalfEdgeMesh) getFaceItems() []meshx.IntersectsAABB {
+	items := make([]meshx.IntersectsAABB, m.GetNumberOfFaces())
+
+	for i := range items {
+		items[i] = m.getFaceItem(i)
+	}
+
+	return items
+}
+
+// Get a face as an octree item.
+func (m *HalfEdgeMesh) getFaceItem(index int) meshx.IntersectsAABB {
+	return faceItem{m.GetFaceTriangles(index)}
+}
@@ -0,0 +1,100 @@
+package halfedge
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Default type of a patch without one in an OpenFOAM boundary dictionary.
+const OpenFOAMDefaultPatchType = "patch"
+
+// Write the patches as an OpenFOAM boundary dictionary (constant/polyMesh/
+// boundary). The faces of each patch are numbered contiguously in the order
+// of the patches from startFace (e.g. the number of internal faces of a
+// volume mesh bounded by the faces in that order). The aliases of a patch are
+// written as its groups and its parameters and properties as entries. Faces
+// without a patch are not written.
+func (m *HalfEdgeMesh) WriteOpenFOAMBoundary(writer io.Writer, startFace int) error {
+	counts := make([]int, len(m.patches))
+
+	for _, face := range m.faces {
+		if face.Patch >= 0 {
+			counts[face.Patch]++
+		}
+	}
+
+	buffer := bufio.NewWriter(writer)
+
+	fmt.Fprintf(buffer, "FoamFile\n{\n")
+	fmt.Fprintf(buffer, "    version     2.0;\n")
+	fmt.Fprintf(buffer, "    format      ascii;\n")
+	fmt.Fprintf(buffer, "    class       polyBoundaryMesh;\n")
+	fmt.Fprintf(buffer, "    location    \"constant/polyMesh\";\n")
+	fmt.Fprintf(buffer, "    object      boundary;\n")
+	fmt.Fprintf(buffer, "}\n\n%d\n(\n", len(m.patches))
+
+	for i, patch := range m.patches {
+		metadata := patch.Metadata
+		patchType := metadata.Type
+
+		if patchType == "" {
+			patchType = OpenFOAMDefaultPatchType
+		}
+
+		fmt.Fprintf(buffer, "    %s\n    {\n", patch.Name)
+		fmt.Fprintf(buffer, "        %-15s %s;\n", "type", patchType)
+
+		if len(metadata.Aliases) > 0 {
+			groups := fmt.Sprintf("List<word> %d(%s)", len(metadata.Aliases), strings.Join(metadata.Aliases, " "))
+			fmt.Fprintf(buffer, "        %-15s %s;\n", "inGroups", groups)
+		}
+
+		for _, key := range getSortedKeys(metadata.Parameters) {
+			value := strconv.FormatFloat(metadata.Parameters[key], 'g', -1, 64)
+			fmt.Fprintf(buffer, "        %-15s %s;\n", key, value)
+		}
+
+		for _, key := range getSortedKeys(metadata.Properties) {
+			fmt.Fprintf(buffer, "        %-15s %s;\n", key, metadata.Properties[key])
+		}
+
+		fmt.Fprintf(buffer, "        %-15s %d;\n", "nFaces", counts[i])
+		fmt.Fprintf(buffer, "        %-15s %d;\n", "startFace", startFace)
+		fmt.Fprintf(buffer, "    }\n")
+
+		startFace += counts[i]
+	}
+
+	fmt.Fprintf(buffer, ")\n")
+
+	return buffer.Flush()
+}
+
+// Get the keys of a map in ascending order.
+func getSortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Write the patches as an OpenFOAM boundary dictionary file path.
+func (m *HalfEdgeMesh) WriteOpenFOAMBoundaryToPath(path string, startFace int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.WriteOpenFOAMBoundary(file, startFace)
+}
@@ -0,0 +1,54 @@
+package halfedge
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test writing the patches as an OpenFOAM boundary dictionary.
+func TestWriteOpenFOAMBoundary(t *testing.T) {
+	mesh := newPatchMetadataTestMesh(t)
+	mesh.SetPatchMetadata(mesh.GetPatchIndex("back"), PatchMetadata{Type: "wall"})
+
+	var buffer bytes.Buffer
+	assert.Empty(t, mesh.WriteOpenFOAMBoundary(&buffer, 10))
+
+	expected := `FoamFile
+{
+    version     2.0;
+    format      ascii;
+    class       polyBoundaryMesh;
+    location    "constant/polyMesh";
+    object      boundary;
+}
+
+6
+(
+    front
+    {
+        type            patch;
+        inGroups        List<word> 2(inlet inflow);
+        velocity        2.5;
+        profile         uniform;
+        nFaces          1;
+        startFace       10;
+    }
+    back
+    {
+        type            wall;
+        nFaces          2;
+        startFace       11;
+    }
+    left
+    {
+        type            patch;
+        nFaces          1;
+        startFace       13;
+    }
+`
+
+	assert.True(t, bytes.HasPrefix(buffer.Bytes(), []byte(expected)), buffer.String())
+	assert.True(t, bytes.HasSuffix(buffer.Bytes(), []byte("        startFace       16;\n    }\n)\n")))
+}
@@ -1,5 +1,131 @@
 package halfedge
 
+import (
+	"encoding/json"
+	"io"
+	"maps"
+	"os"
+	"slices"
+)
+
 type Patch struct {
 	Name string
+
+	// Interior surface (e.g. a thin wall or a porous screen) that is
+	// two-sided rather than part of the boundary of the domain. The edges
+	// of a baffle may be shared with other faces (see NewHalfEdgeMesh).
+	IsBaffle bool
+
+	// Boundary condition of the patch.
+	Metadata PatchMetadata
+}
+
+// Return true if a name is the name or an alias of the patch.
+func (p Patch) HasName(name string) bool {
+	return p.Name == name || slices.Contains(p.Metadata.Aliases, name)
+}
+
+// Boundary condition metadata of a patch (e.g. to set up a solver). The zero
+// value has no metadata.
+type PatchMetadata struct {
+	// Type of the boundary condition (e.g. "wall", "patch" or
+	// "symmetryPlane").
+	Type string `json:"type,omitempty"`
+
+	// Other names of the patch (e.g. the names of the same boundary in
+	// another tool or the groups it belongs to).
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Numeric parameters of the boundary condition (e.g. a velocity).
+	Parameters map[string]float64 `json:"parameters,omitempty"`
+
+	// Other parameters of the boundary condition.
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// Copy the metadata without sharing its aliases, parameters or properties.
+func (p PatchMetadata) Clone() PatchMetadata {
+	p.Aliases = slices.Clone(p.Aliases)
+	p.Parameters = maps.Clone(p.Parameters)
+	p.Properties = maps.Clone(p.Properties)
+	return p
+}
+
+// Optional interface of a MeshReader retaining the metadata of each patch.
+type PatchMetadataReader interface {
+	GetPatchMetadata(int) PatchMetadata
+}
+
+// Extension of the patch metadata sidecar of a mesh file (appended to its
+// path) for formats that cannot store the metadata.
+const PatchMetadataExtension = ".patches.json"
+
+// Patch metadata sidecar in JSON.
+type patchMetadataFile struct {
+	Patches []patchMetadataEntry `json:"patches"`
+}
+
+// Patch of the patch metadata sidecar.
+type patchMetadataEntry struct {
+	Name     string `json:"name"`
+	IsBaffle bool   `json:"baffle,omitempty"`
+	PatchMetadata
+}
+
+// Write the metadata (and baffle tags) of the patches as a JSON sidecar.
+func (m *HalfEdgeMesh) WritePatchMetadata(writer io.Writer) error {
+	file := patchMetadataFile{Patches: make([]patchMetadataEntry, len(m.patches))}
+
+	for i, patch := range m.patches {
+		file.Patches[i] = patchMetadataEntry{patch.Name, patch.IsBaffle, patch.Metadata}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// Write the metadata of the patches as a JSON sidecar file path.
+func (m *HalfEdgeMesh) WritePatchMetadataToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.WritePatchMetadata(file)
+}
+
+// Read the metadata of the patches from a JSON sidecar written by
+// WritePatchMetadata. The metadata is set on the patches by name (either
+// name or alias of either patch) and patches missing from the mesh are
+// ignored. The baffle tags are not read (see HalfEdgeMeshOptions).
+func (m *HalfEdgeMesh) ReadPatchMetadata(reader io.Reader) error {
+	var file patchMetadataFile
+
+	if err := json.NewDecoder(reader).Decode(&file); err != nil {
+		return err
+	}
+
+	for _, entry := range file.Patches {
+		for _, name := range append([]string{entry.Name}, entry.Aliases...) {
+			if index := m.GetPatchIndex(name); index >= 0 {
+				m.SetPatchMetadata(index, entry.PatchMetadata)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// Read the metadata of the patches from a JSON sidecar file path.
+func (m *HalfEdgeMesh) ReadPatchMetadataFromPath(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.ReadPatchMetadata(file)
 }
@@ -0,0 +1,69 @@
+package halfedge
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Get the box with boundary condition metadata on its front patch.
+func newPatchMetadataTestMesh(t *testing.T) *HalfEdgeMesh {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh.SetPatchMetadata(mesh.GetPatchIndex("front"), PatchMetadata{
+		Type:       "patch",
+		Aliases:    []string{"inlet", "inflow"},
+		Parameters: map[string]float64{"velocity": 2.5},
+		Properties: map[string]string{"profile": "uniform"},
+	})
+
+	return mesh
+}
+
+// Test the metadata of the patches is preserved by the operations copying
+// the patches.
+func TestPatchMetadata(t *testing.T) {
+	mesh := newPatchMetadataTestMesh(t)
+	index := mesh.GetPatchIndex("inlet")
+	expected := mesh.GetPatch(index).Metadata
+
+	assert.Equal(t, index, mesh.GetPatchIndex("front"))
+	assert.Equal(t, -1, mesh.GetPatchIndex("outlet"))
+	assert.Equal(t, "patch", expected.Type)
+
+	extracted := mesh.ExtractPatches([]int{index})
+	assert.Equal(t, expected, extracted.GetPatch(0).Metadata)
+
+	copied, err := NewHalfEdgeMesh(mesh.GetMeshReader())
+	assert.Empty(t, err)
+	assert.Equal(t, expected, copied.GetPatch(index).Metadata)
+
+	other := newTestCube(t)
+	other.Merge(mesh)
+	assert.Equal(t, expected, other.GetPatch(other.GetPatchIndex("inlet")).Metadata)
+
+	// The merged metadata is a copy.
+	other.GetPatch(other.GetPatchIndex("inlet")).Metadata.Parameters["velocity"] = 1
+	assert.Equal(t, 2.5, mesh.GetPatch(index).Metadata.Parameters["velocity"])
+}
+
+// Test reading the metadata of the patches written to a sidecar.
+func TestWritePatchMetadata(t *testing.T) {
+	mesh := newPatchMetadataTestMesh(t)
+	path := filepath.Join(t.TempDir(), "box.obj"+PatchMetadataExtension)
+	assert.Empty(t, mesh.WritePatchMetadataToPath(path))
+
+	other, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+	other.SetPatchName(other.GetPatchIndex("front"), "inlet")
+	assert.Empty(t, other.ReadPatchMetadataFromPath(path))
+
+	for i := range mesh.GetNumberOfPatches() {
+		assert.Equal(t, mesh.GetPatch(i).Metadata, other.GetPatch(i).Metadata)
+	}
+
+	assert.Error(t, other.ReadPatchMetadata(bytes.NewBufferString("{")))
+}
@@ -0,0 +1,152 @@
+package halfedge
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	// Default distance of the vertices from the plane of a planar region
+	// relative to the diagonal of the bounding box of the mesh.
+	PlanarRegionTolerance = 1e-6
+
+	// Default maximum angle (radians) between the normal of a face and the
+	// normal of its planar region.
+	PlanarRegionAngle = math.Pi / 180
+)
+
+// Connected set of coplanar faces (e.g. an inlet, outlet or symmetry plane).
+type PlanarRegion struct {
+	// Faces of the region (sorted).
+	Faces []int
+
+	// Plane of the region through the area-weighted centroid of its faces
+	// with the area-weighted mean (unit) normal.
+	Origin meshx.Vector
+	Normal meshx.Vector
+
+	// Total area of the faces.
+	Area float64
+}
+
+// Options for detecting planar regions. Zero values use the defaults.
+type PlanarRegionOptions struct {
+	// Maximum distance of the vertices of a face from the plane of its
+	// region. The default is PlanarRegionTolerance times the diagonal of the
+	// bounding box of the mesh.
+	Tolerance float64
+
+	// Maximum angle (radians) between the normal of a face and the normal
+	// of its region.
+	Angle float64
+
+	// Minimum area of a region. Smaller regions are not returned.
+	MinArea float64
+}
+
+// Detect the maximal planar regions: the connected sets of faces within the
+// tolerances of a common plane. The regions are grown from the largest faces
+// across the edges of the mesh and returned in order of decreasing area.
+// Degenerate faces are not in any region.
+func (m *HalfEdgeMesh) GetPlanarRegions(options PlanarRegionOptions) []PlanarRegion {
+	regions := make([]PlanarRegion, 0)
+
+	if m.GetNumberOfFaces() == 0 {
+		return regions
+	}
+
+	if options.Tolerance <= 0 {
+		options.Tolerance = PlanarRegionTolerance * 2 * m.GetAABB().HalfSize.Mag()
+	}
+
+	if options.Angle <= 0 {
+		options.Angle = PlanarRegionAngle
+	}
+
+	m.ensureAdjacency()
+
+	threshold := math.Cos(options.Angle)
+	areas := make([]float64, m.GetNumberOfFaces())
+	normals := make([]meshx.Vector, m.GetNumberOfFaces())
+	centroids := make([]meshx.Vector, m.GetNumberOfFaces())
+	seeds := make([]int, 0, m.GetNumberOfFaces())
+
+	for i := range m.GetNumberOfFaces() {
+		if normal := m.GetFaceNormal(i); normal.Mag() > 0 {
+			areas[i] = m.GetFaceArea(i)
+			normals[i] = normal.Unit()
+			centroids[i] = m.GetFaceCentroid(i)
+			seeds = append(seeds, i)
+		}
+	}
+
+	slices.SortStableFunc(seeds, func(a, b int) int {
+		return cmp.Compare(areas[b], areas[a])
+	})
+
+	visited := make([]bool, m.GetNumberOfFaces())
+
+	for _, seed := range seeds {
+		if visited[seed] {
+			continue
+		}
+
+		visited[seed] = true
+		faces := []int{seed}
+		normal := normals[seed].MulScalar(areas[seed])
+		moment := centroids[seed].MulScalar(areas[seed])
+		area := areas[seed]
+
+		for k := 0; k < len(faces); k++ {
+			for _, neighbor := range m.GetFaceNeighbors(faces[k]) {
+				if visited[neighbor] || areas[neighbor] == 0 {
+					continue
+				}
+
+				unit := normal.Unit()
+
+				if normals[neighbor].Dot(unit) < threshold {
+					continue
+				}
+
+				origin := moment.DivScalar(area)
+
+				isCoplanar := !slices.ContainsFunc(m.getFacePoints(neighbor), func(point meshx.Vector) bool {
+					return math.Abs(point.Sub(origin).Dot(unit)) > options.Tolerance
+				})
+
+				if !isCoplanar {
+					continue
+				}
+
+				visited[neighbor] = true
+				faces = append(faces, neighbor)
+				normal = normal.Add(normals[neighbor].MulScalar(areas[neighbor]))
+				moment = moment.Add(centroids[neighbor].MulScalar(areas[neighbor]))
+				area += areas[neighbor]
+			}
+		}
+
+		if area < options.MinArea {
+			continue
+		}
+
+		slices.Sort(faces)
+
+		regions = append(regions, PlanarRegion{
+			Faces:  faces,
+			Origin: moment.DivScalar(area),
+			Normal: normal.Unit(),
+			Area:   area,
+		})
+	}
+
+	slices.SortStableFunc(regions, func(a, b PlanarRegion) int {
+		return cmp.Compare(b.Area, a.Area)
+	})
+
+	return regions
+}
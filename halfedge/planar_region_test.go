@@ -0,0 +1,43 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test detecting the planar regions of a cube and a grid.
+func TestGetPlanarRegions(t *testing.T) {
+	cube := newTestCube(t)
+	regions := cube.GetPlanarRegions(PlanarRegionOptions{})
+	assert.Equal(t, 6, len(regions))
+
+	for _, region := range regions {
+		assert.Equal(t, 1, len(region.Faces))
+		assert.InDelta(t, 1, region.Area, 1e-12)
+		assert.Equal(t, cube.GetFaceNormal(region.Faces[0]), region.Normal)
+		assert.InDelta(t, 0.5, region.Origin.Sub(meshx.NewVector(0.5, 0.5, 0.5)).Mag(), 1e-12)
+	}
+
+	grid := newTestTriangleGrid(t, 4)
+	regions = grid.GetPlanarRegions(PlanarRegionOptions{})
+	assert.Equal(t, 1, len(regions))
+	assert.Equal(t, grid.GetNumberOfFaces(), len(regions[0].Faces))
+	assert.InDelta(t, 16, regions[0].Area, 1e-12)
+	assert.InDelta(t, 0, regions[0].Origin.Sub(meshx.NewVector(2, 2, 0)).Mag(), 1e-12)
+	assert.InDelta(t, 0, regions[0].Normal.Sub(meshx.NewVector(0, 0, 1)).Mag(), 1e-12)
+
+	// A vertex lifted off the plane splits its faces from the region unless
+	// within the tolerances.
+	grid.vertices[12].Point[2] = 1e-3
+	regions = grid.GetPlanarRegions(PlanarRegionOptions{})
+	assert.Less(t, 1, len(regions))
+	assert.InDelta(t, 13, regions[0].Area, 1e-12)
+
+	regions = grid.GetPlanarRegions(PlanarRegionOptions{Tolerance: 1e-2, Angle: 0.1})
+	assert.Equal(t, 1, len(regions))
+
+	regions = grid.GetPlanarRegions(PlanarRegionOptions{MinArea: 1})
+	assert.Equal(t, 1, len(regions))
+}
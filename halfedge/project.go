@@ -0,0 +1,119 @@
+package halfedge
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Options for projecting the vertices of a mesh onto a target surface.
+type ProjectOptions struct {
+	// Maximum distance a vertex may move. Vertices farther from the target
+	// are left in place. Zero is unlimited.
+	MaxDistance float64
+
+	// Project along the vertex normal (in either direction) rather than to
+	// the closest point on the target.
+	AlongNormal bool
+}
+
+// Move each vertex to its closest point on the target surface. The number of
+// vertices moved is returned.
+func (m *HalfEdgeMesh) ProjectOnto(target *HalfEdgeMesh) int {
+	return m.ProjectOntoWithOptions(target, ProjectOptions{})
+}
+
+// Move each vertex onto the target surface with options. The number of
+// vertices moved is returned.
+func (m *HalfEdgeMesh) ProjectOntoWithOptions(target *HalfEdgeMesh, options ProjectOptions) int {
+	var normals []meshx.Vector
+
+	if target.GetNumberOfFaces() == 0 {
+		return 0
+	}
+
+	octree := target.BuildOctree()
+	maxDistance := options.MaxDistance
+
+	if maxDistance <= 0 {
+		maxDistance = math.Inf(1)
+	}
+
+	if options.AlongNormal {
+		normals = m.getVertexNormals()
+	}
+
+	moved := make([]int, 0)
+
+	for i, vertex := range m.vertices {
+		var projected meshx.Vector
+		var ok bool
+
+		if options.AlongNormal {
+			projected, ok = projectAlongNormal(octree, vertex.Point, normals[i])
+		} else {
+			_, projected = octree.QueryNearest(vertex.Point)
+			ok = true
+		}
+
+		if ok && projected.Sub(vertex.Point).Mag() <= maxDistance {
+			m.vertices[i].Point = projected
+			moved = append(moved, i)
+		}
+	}
+
+	m.notify(VerticesMoved, moved)
+
+	return len(moved)
+}
+
+// Project a point along its normal in either direction onto the nearest
+// surface hit.
+func projectAlongNormal(octree *spatial.Octree, point, normal meshx.Vector) (meshx.Vector, bool) {
+	var projected meshx.Vector
+
+	if normal.Mag() == 0 {
+		return projected, false
+	}
+
+	distance := math.Inf(1)
+
+	for _, direction := range []meshx.Vector{normal, normal.MulScalar(-1)} {
+		hits := octree.RaycastAll(meshx.NewRay(point, direction.Unit()))
+
+		if len(hits) > 0 && hits[0].Distance < distance {
+			distance = hits[0].Distance
+			projected = hits[0].Point
+		}
+	}
+
+	return projected, !math.IsInf(distance, 1)
+}
+
+// Compute the area-weighted normal of each vertex.
+func (m *HalfEdgeMesh) getVertexNormals() []meshx.Vector {
+	normals := make([]meshx.Vector, m.GetNumberOfVertices())
+
+	for i := 0; i < m.GetNumberOfFaces(); i++ {
+		area := m.GetFaceArea(i)
+
+		if area == 0 {
+			continue
+		}
+
+		normal := m.GetFaceNormal(i).MulScalar(area)
+
+		for _, vertex := range m.GetFaceVertices(i) {
+			normals[vertex] = normals[vertex].Add(normal)
+		}
+	}
+
+	for i, normal := range normals {
+		if mag := normal.Mag(); mag > 0 {
+			normals[i] = normal.DivScalar(mag)
+		}
+	}
+
+	return normals
+}
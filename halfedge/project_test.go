@@ -0,0 +1,73 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate a planar grid on z = 0.2 within the box.
+func newProjectTestGrid(t *testing.T) *HalfEdgeMesh {
+	grid := make([][]meshx.Vector, 5)
+
+	for i := range grid {
+		grid[i] = make([]meshx.Vector, 5)
+
+		for j := range grid[i] {
+			grid[i][j] = meshx.NewVector(0.3+0.1*float64(i), 0.3+0.1*float64(j), 0.2)
+		}
+	}
+
+	mesh, err := NewStructuredGrid(grid, false)
+	assert.Empty(t, err)
+
+	return mesh
+}
+
+// Test projecting the vertices of a mesh to the closest point on a target.
+func TestProjectOnto(t *testing.T) {
+	target, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh := newProjectTestGrid(t)
+	assert.Equal(t, 25, mesh.ProjectOnto(target))
+
+	for i := 0; i < mesh.GetNumberOfVertices(); i++ {
+		assert.InDelta(t, 0, mesh.GetVertex(i).Point[2], 1e-12)
+	}
+}
+
+// Test projecting the vertices of a mesh with a maximum distance.
+func TestProjectOntoMaxDistance(t *testing.T) {
+	target, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh := newProjectTestGrid(t)
+	options := ProjectOptions{MaxDistance: 0.1}
+	assert.Equal(t, 0, mesh.ProjectOntoWithOptions(target, options))
+
+	for i := 0; i < mesh.GetNumberOfVertices(); i++ {
+		assert.InDelta(t, 0.2, mesh.GetVertex(i).Point[2], 1e-12)
+	}
+}
+
+// Test projecting the vertices of a mesh along the vertex normals.
+func TestProjectOntoAlongNormal(t *testing.T) {
+	target, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	// Shift the grid so the closest point of the edge vertices is on the side
+	// of the box rather than along the normal.
+	mesh := newProjectTestGrid(t)
+	mesh.Translate(meshx.NewVector(-0.25, 0, 0))
+
+	options := ProjectOptions{AlongNormal: true}
+	assert.Equal(t, 25, mesh.ProjectOntoWithOptions(target, options))
+
+	for i := 0; i < mesh.GetNumberOfVertices(); i++ {
+		point := mesh.GetVertex(i).Point
+		assert.InDelta(t, 0, point[2], 1e-12)
+		assert.GreaterOrEqual(t, point[0], 0.05-1e-12)
+	}
+}
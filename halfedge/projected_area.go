@@ -0,0 +1,119 @@
+package halfedge
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/planar"
+)
+
+// Default number of pixels along the longest side of the projection.
+const DefaultProjectedAreaResolution = 1024
+
+// Options to compute the projected area of a mesh.
+type ProjectedAreaOptions struct {
+	// Number of pixels along the longest side of the projection. The
+	// default is DefaultProjectedAreaResolution.
+	Resolution int
+}
+
+// Compute the projected (frontal) area of the mesh along a direction. See
+// ProjectedAreaWithOptions.
+func (m *HalfEdgeMesh) ProjectedArea(direction meshx.Vector) float64 {
+	return m.ProjectedAreaWithOptions(direction, ProjectedAreaOptions{})
+}
+
+// Compute the projected (frontal) area of the mesh along a direction: the
+// area of the silhouette on a plane normal to the direction, with the
+// overlapping faces counted once. The faces are rasterized on a grid of
+// square pixels covering the projection and the area is that of the pixels
+// with centers inside a face, so the error is of the order of the
+// silhouette perimeter times the pixel size. Open meshes and faces in either
+// orientation are supported.
+func (m *HalfEdgeMesh) ProjectedAreaWithOptions(direction meshx.Vector, options ProjectedAreaOptions) float64 {
+	resolution := options.Resolution
+
+	if resolution <= 0 {
+		resolution = DefaultProjectedAreaResolution
+	}
+
+	if len(m.vertices) == 0 || direction.Mag() == 0 {
+		return 0
+	}
+
+	projected := planar.ProjectToPlane(m.getVertexPoints(), direction)
+	lower := meshx.NewVector(math.Inf(1), math.Inf(1), 0)
+	upper := meshx.NewVector(math.Inf(-1), math.Inf(-1), 0)
+
+	for _, point := range projected {
+		lower = meshx.NewVector(min(lower[0], point[0]), min(lower[1], point[1]), 0)
+		upper = meshx.NewVector(max(upper[0], point[0]), max(upper[1], point[1]), 0)
+	}
+
+	size := max(upper[0]-lower[0], upper[1]-lower[1]) / float64(resolution)
+
+	if size == 0 {
+		return 0
+	}
+
+	nx := max(1, int(math.Ceil((upper[0]-lower[0])/size)))
+	ny := max(1, int(math.Ceil((upper[1]-lower[1])/size)))
+	covered := make([]bool, nx*ny)
+
+	for i := range m.GetNumberOfFaces() {
+		vertices := m.GetFaceVertices(i)
+		points := make([]meshx.Vector, len(vertices))
+
+		for j, vertex := range vertices {
+			points[j] = projected[vertex]
+		}
+
+		for _, triangle := range planar.TriangulateFace(m.getFacePoints(i)) {
+			a, b, c := points[triangle[0]], points[triangle[1]], points[triangle[2]]
+			rasterizeTriangle(a.Sub(lower), b.Sub(lower), c.Sub(lower), size, nx, ny, covered)
+		}
+	}
+
+	var count int
+
+	for _, isCovered := range covered {
+		if isCovered {
+			count++
+		}
+	}
+
+	return float64(count) * size * size
+}
+
+// Mark the pixels with centers inside a triangle in the XY-plane (with the
+// grid origin at zero) as covered. Triangles seen edge on are skipped.
+func rasterizeTriangle(a, b, c meshx.Vector, size float64, nx, ny int, covered []bool) {
+	area := meshx.Orient2D(a, b, c)
+
+	if area == 0 {
+		return
+	}
+
+	if area < 0 {
+		b, c = c, b
+	}
+
+	i0 := max(0, int(math.Floor(min(a[0], b[0], c[0])/size-0.5)))
+	i1 := min(nx-1, int(math.Ceil(max(a[0], b[0], c[0])/size-0.5)))
+	j0 := max(0, int(math.Floor(min(a[1], b[1], c[1])/size-0.5)))
+	j1 := min(ny-1, int(math.Ceil(max(a[1], b[1], c[1])/size-0.5)))
+
+	for j := j0; j <= j1; j++ {
+		for i := i0; i <= i1; i++ {
+			if covered[j*nx+i] {
+				continue
+			}
+
+			center := meshx.NewVector((float64(i)+0.5)*size, (float64(j)+0.5)*size, 0)
+
+			if meshx.Orient2D(a, b, center) >= 0 && meshx.Orient2D(b, c, center) >= 0 && meshx.Orient2D(c, a, center) >= 0 {
+				covered[j*nx+i] = true
+			}
+		}
+	}
+}
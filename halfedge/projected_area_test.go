@@ -0,0 +1,41 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the projected area of a box along an axis and a diagonal.
+func TestProjectedArea(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	assert.InDelta(t, 1, mesh.ProjectedArea(meshx.NewVector(1, 0, 0)), 1e-12)
+	assert.InDelta(t, 1, mesh.ProjectedArea(meshx.NewVector(0, 0, -3)), 1e-12)
+	assert.InDelta(t, math.Sqrt(2), mesh.ProjectedArea(meshx.NewVector(1, 1, 0)), 1e-2)
+	assert.InDelta(t, math.Sqrt(3), mesh.ProjectedArea(meshx.NewVector(1, 1, 1)), 1e-2)
+	assert.Equal(t, 0.0, mesh.ProjectedArea(meshx.Vector{}))
+}
+
+// Test the projected area of a cylinder at a coarse resolution.
+func TestProjectedAreaWithOptions(t *testing.T) {
+	profile := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(1, 0, 3),
+		meshx.NewVector(0, 0, 3),
+	}
+
+	mesh, err := NewRevolve(profile, meshx.Vector{}, meshx.NewVector(0, 0, 1), 64)
+	assert.Empty(t, err)
+
+	circle := 32 * math.Sin(2*math.Pi/64)
+	assert.InDelta(t, circle, mesh.ProjectedArea(meshx.NewVector(0, 0, 1)), 1e-2)
+
+	options := ProjectedAreaOptions{Resolution: 64}
+	assert.InDelta(t, circle, mesh.ProjectedAreaWithOptions(meshx.NewVector(0, 0, 1), options), 1e-1)
+	assert.InDelta(t, 6, mesh.ProjectedAreaWithOptions(meshx.NewVector(0, 1, 0), options), 1e-1)
+}
@@ -0,0 +1,94 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Closest pair of points between two meshes.
+type Proximity struct {
+	// Distance between the points (zero if the meshes intersect).
+	Distance float64
+
+	// Closest points on each mesh.
+	PointA meshx.Vector
+	PointB meshx.Vector
+
+	// Faces of the closest points (-1 if either mesh is empty).
+	FaceA int
+	FaceB int
+}
+
+// Compute the closest pair of points between two meshes (e.g. the clearance
+// between two components). See spatial.MinimumDistance.
+func MinimumDistance(a, b *HalfEdgeMesh) Proximity {
+	trianglesA, facesA := a.getTriangleFaces()
+	trianglesB, facesB := b.getTriangleFaces()
+	return newProximity(spatial.MinimumDistance(trianglesA, trianglesB), facesA, facesB)
+}
+
+// Get the triangles of all faces and the face of each triangle.
+func (m *HalfEdgeMesh) getTriangleFaces() ([]meshx.Triangle, []int) {
+	triangles := make([]meshx.Triangle, 0, m.GetNumberOfFaces())
+	faces := make([]int, 0, m.GetNumberOfFaces())
+
+	for i := range m.GetNumberOfFaces() {
+		for _, triangle := range m.GetFaceTriangles(i) {
+			triangles = append(triangles, triangle)
+			faces = append(faces, i)
+		}
+	}
+
+	return triangles, faces
+}
+
+// Contact of a moving mesh with another mesh.
+type Contact struct {
+	// True if the meshes come into contact along the path.
+	Collides bool
+
+	// Earliest parameter of contact along the path in [0, 1] (1 if the
+	// meshes do not collide).
+	T float64
+
+	// Closest points of the moving mesh (a) at T and the other mesh (b).
+	Proximity
+}
+
+// Find the earliest contact of mesh a moving along a path with mesh b (e.g.
+// for a kinematic clearance study). Each vertex of a moves linearly from its
+// start to its end transform. See spatial.SweepContact.
+func SweepContact(a, b *HalfEdgeMesh, start, end meshx.Matrix4, options spatial.SweepOptions) (Contact, error) {
+	trianglesA, facesA := a.getTriangleFaces()
+	trianglesB, facesB := b.getTriangleFaces()
+	startA := make([]meshx.Triangle, len(trianglesA))
+	endA := make([]meshx.Triangle, len(trianglesA))
+
+	for i, triangle := range trianglesA {
+		startA[i] = meshx.NewTriangle(start.MulPoint(triangle.P), start.MulPoint(triangle.Q), start.MulPoint(triangle.R))
+		endA[i] = meshx.NewTriangle(end.MulPoint(triangle.P), end.MulPoint(triangle.Q), end.MulPoint(triangle.R))
+	}
+
+	contact, err := spatial.SweepContact(startA, endA, trianglesB, options)
+	proximity := newProximity(contact.Proximity, facesA, facesB)
+
+	return Contact{contact.Collides, contact.T, proximity}, err
+}
+
+// Convert the proximity of the triangles of two meshes to that of their faces.
+func newProximity(proximity spatial.Proximity, facesA, facesB []int) Proximity {
+	result := Proximity{
+		Distance: proximity.Distance,
+		PointA:   proximity.PointA,
+		PointB:   proximity.PointB,
+		FaceA:    -1,
+		FaceB:    -1,
+	}
+
+	if proximity.IndexA >= 0 && proximity.IndexB >= 0 {
+		result.FaceA = facesA[proximity.IndexA]
+		result.FaceB = facesB[proximity.IndexB]
+	}
+
+	return result
+}
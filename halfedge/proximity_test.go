@@ -0,0 +1,62 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+	"github.com/stretchr/testify/assert"
+)
+
+// Compute the distance from a point to a face.
+func getFaceDistance(mesh *HalfEdgeMesh, face int, point meshx.Vector) float64 {
+	distance := math.Inf(1)
+
+	for _, triangle := range mesh.GetFaceTriangles(face) {
+		distance = min(distance, triangle.DistanceTo(point))
+	}
+
+	return distance
+}
+
+// Test the minimum distance between two cubes.
+func TestMinimumDistance(t *testing.T) {
+	a := newTestCube(t)
+	b := newTestCube(t)
+	b.Translate(meshx.NewVector(1.25, 0.5, 0))
+
+	proximity := MinimumDistance(a, b)
+	assert.InDelta(t, 0.25, proximity.Distance, 1e-12)
+	assert.InDelta(t, 1.0, proximity.PointA[0], 1e-12)
+	assert.InDelta(t, 1.25, proximity.PointB[0], 1e-12)
+	assert.InDelta(t, 0.0, getFaceDistance(a, proximity.FaceA, proximity.PointA), 1e-12)
+	assert.InDelta(t, 0.0, getFaceDistance(b, proximity.FaceB, proximity.PointB), 1e-12)
+
+	b.Translate(meshx.NewVector(-0.5, 0, 0))
+	assert.Equal(t, 0.0, MinimumDistance(a, b).Distance)
+
+	proximity = MinimumDistance(a, &HalfEdgeMesh{})
+	assert.True(t, math.IsInf(proximity.Distance, 1))
+	assert.Equal(t, -1, proximity.FaceA)
+}
+
+// Test sweeping a cube into another cube.
+func TestSweepContact(t *testing.T) {
+	a := newTestCube(t)
+	b := newTestCube(t)
+	start := meshx.NewTranslationMatrix4(meshx.NewVector(-3, 0.5, 0.5))
+	end := meshx.NewTranslationMatrix4(meshx.NewVector(1, 0.5, 0.5))
+
+	contact, err := SweepContact(a, b, start, end, spatial.SweepOptions{})
+	assert.Empty(t, err)
+	assert.True(t, contact.Collides)
+	assert.InDelta(t, 0.5, contact.T, 1e-5)
+	assert.InDelta(t, 0.0, contact.PointB[0], 1e-5)
+	assert.GreaterOrEqual(t, contact.FaceA, 0)
+
+	end = meshx.NewTranslationMatrix4(meshx.NewVector(-3, 3, 0.5))
+	contact, err = SweepContact(a, b, start, end, spatial.SweepOptions{})
+	assert.Empty(t, err)
+	assert.False(t, contact.Collides)
+}
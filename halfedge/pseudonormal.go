@@ -0,0 +1,151 @@
+package halfedge
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Angle-weighted pseudo-normals of the vertices and edges of a mesh
+// (Bærentzen and Aanæs). The sign of the distance to a closed, consistently
+// oriented mesh is given by the pseudo-normal of the feature (face, edge or
+// vertex) of the closest point, which the face normals get wrong near edges
+// and corners.
+type PseudoNormals struct {
+	mesh      *HalfEdgeMesh
+	vertices  []meshx.Vector
+	halfEdges []meshx.Vector
+}
+
+// Compute the pseudo-normals of the vertices and edges. The pseudo-normal of
+// a vertex is the sum of the normals of its faces weighted by the angles of
+// the faces at the vertex. The pseudo-normal of an edge is the sum of the
+// normals of its faces (the normal of the face on the boundary).
+func (m *HalfEdgeMesh) ComputePseudoNormals() *PseudoNormals {
+	m.ensureAdjacency()
+
+	p := PseudoNormals{
+		mesh:      m,
+		vertices:  make([]meshx.Vector, m.GetNumberOfVertices()),
+		halfEdges: make([]meshx.Vector, m.GetNumberOfHalfEdges()),
+	}
+
+	normals := make([]meshx.Vector, m.GetNumberOfFaces())
+	buf := make([]int, 0, 4)
+
+	for i := range normals {
+		normals[i] = m.GetFaceNormal(i)
+		buf = m.GetFaceVerticesInto(i, buf)
+
+		for j, vertex := range buf {
+			point := m.vertices[vertex].Point
+			prev := m.vertices[buf[(j+len(buf)-1)%len(buf)]].Point
+			next := m.vertices[buf[(j+1)%len(buf)]].Point
+			angle := getCornerAngle(prev.Sub(point), next.Sub(point))
+			p.vertices[vertex] = p.vertices[vertex].Add(normals[i].MulScalar(angle))
+		}
+	}
+
+	for i, halfEdge := range m.halfEdges {
+		normal := normals[halfEdge.Face]
+
+		if !halfEdge.IsBoundary() {
+			normal = normal.Add(normals[m.halfEdges[halfEdge.Twin].Face])
+		}
+
+		p.halfEdges[i] = getUnitOrZero(normal)
+	}
+
+	for i, normal := range p.vertices {
+		p.vertices[i] = getUnitOrZero(normal)
+	}
+
+	return &p
+}
+
+// Get the angle between two vectors from a corner. The angle is zero if
+// either vector is zero.
+func getCornerAngle(u, v meshx.Vector) float64 {
+	mag := u.Mag() * v.Mag()
+
+	if mag == 0 {
+		return 0
+	}
+
+	return math.Acos(max(-1, min(1, u.Dot(v)/mag)))
+}
+
+// Get the unit vector or zero for the zero vector.
+func getUnitOrZero(v meshx.Vector) meshx.Vector {
+	if v.Mag() == 0 {
+		return v
+	}
+
+	return v.Unit()
+}
+
+// Get the pseudo-normal of a vertex.
+func (p *PseudoNormals) GetVertexNormal(index int) meshx.Vector {
+	return p.vertices[index]
+}
+
+// Get the pseudo-normal of the edge of a half edge (the same for its twin).
+func (p *PseudoNormals) GetHalfEdgeNormal(index int) meshx.Vector {
+	return p.halfEdges[index]
+}
+
+// Get the pseudo-normal of the feature of a face nearest a point on the face
+// (e.g. the closest point on the face to a query point): the pseudo-normal
+// of a vertex or edge if the point is on it (within a tolerance relative to
+// the size of the face) or the normal of the face otherwise.
+func (p *PseudoNormals) GetNormal(face int, point meshx.Vector) meshx.Vector {
+	m := p.mesh
+	halfEdges := m.GetFaceHalfEdges(face)
+	points := m.getFacePoints(face)
+
+	var perimeter float64
+
+	for i, q := range points {
+		perimeter += points[(i+1)%len(points)].Sub(q).Mag()
+	}
+
+	tolerance := 1e-9 * perimeter
+
+	for i, q := range points {
+		if q.Sub(point).Mag() <= tolerance {
+			return p.vertices[m.halfEdges[halfEdges[i]].Origin]
+		}
+	}
+
+	for i, q := range points {
+		edge := meshx.NewSegment(q, points[(i+1)%len(points)])
+
+		if edge.DistanceTo(point) <= tolerance {
+			return p.halfEdges[halfEdges[i]]
+		}
+	}
+
+	return m.GetFaceNormal(face)
+}
+
+// Get the signed distance from a point to the mesh: positive outside and
+// negative inside a closed mesh oriented outward. The octree must have been
+// built by BuildOctree for the mesh. The distance is +Inf for an empty
+// octree.
+func (p *PseudoNormals) GetSignedDistance(octree *spatial.Octree, point meshx.Vector) float64 {
+	face, closest := octree.QueryNearest(point)
+
+	if face == -1 {
+		return math.Inf(1)
+	}
+
+	offset := point.Sub(closest)
+	distance := offset.Mag()
+
+	if offset.Dot(p.GetNormal(face, closest)) < 0 {
+		return -distance
+	}
+
+	return distance
+}
@@ -0,0 +1,57 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Construct the unit cube of quads oriented outward.
+func newTestCube(t *testing.T) *HalfEdgeMesh {
+	source := meshSource{}
+
+	for i := range 8 {
+		source.vertices = append(source.vertices, meshx.NewVector(float64(i&1), float64(i>>1&1), float64(i>>2&1)))
+	}
+
+	faces := [][]int{{0, 2, 3, 1}, {4, 5, 7, 6}, {0, 1, 5, 4}, {2, 6, 7, 3}, {0, 4, 6, 2}, {1, 3, 7, 5}}
+
+	for _, face := range faces {
+		source.addFace(face, Face{Patch: -1, Material: -1})
+	}
+
+	mesh, err := NewHalfEdgeMesh(&source)
+	assert.Empty(t, err)
+	return mesh
+}
+
+func TestComputePseudoNormals(t *testing.T) {
+	mesh := newTestCube(t)
+	normals := mesh.ComputePseudoNormals()
+
+	corner := normals.GetVertexNormal(7)
+	assert.InDelta(t, 0, corner.Sub(meshx.NewVector(1, 1, 1).Unit()).Mag(), 1e-12)
+
+	for i := range mesh.GetNumberOfHalfEdges() {
+		halfEdge := mesh.GetHalfEdge(i)
+		p := mesh.GetVertex(halfEdge.Origin).Point
+		q := mesh.GetVertex(mesh.GetHalfEdge(halfEdge.Next).Origin).Point
+		midpoint := p.Add(q).MulScalar(0.5)
+		expected := midpoint.Sub(meshx.NewVector(0.5, 0.5, 0.5)).Unit()
+		assert.InDelta(t, 0, normals.GetHalfEdgeNormal(i).Sub(expected).Mag(), 1e-12)
+	}
+}
+
+func TestGetSignedDistance(t *testing.T) {
+	mesh := newTestCube(t)
+	normals := mesh.ComputePseudoNormals()
+	octree := mesh.BuildOctree()
+
+	assert.InDelta(t, 0.1*math.Sqrt(3), normals.GetSignedDistance(octree, meshx.NewVector(1.1, 1.1, 1.1)), 1e-12)
+	assert.InDelta(t, 0.1*math.Sqrt(2), normals.GetSignedDistance(octree, meshx.NewVector(1.1, 1.1, 0.5)), 1e-12)
+	assert.InDelta(t, -0.01, normals.GetSignedDistance(octree, meshx.NewVector(0.99, 0.99, 0.99)), 1e-12)
+	assert.InDelta(t, -0.25, normals.GetSignedDistance(octree, meshx.NewVector(0.5, 0.25, 0.5)), 1e-12)
+	assert.InDelta(t, 0.5, normals.GetSignedDistance(octree, meshx.NewVector(0.5, 0.5, 1.5)), 1e-12)
+}
@@ -0,0 +1,168 @@
+package halfedge
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Default maximum corner angle of a quad merged from two triangles.
+const QuadMaxAngle = 3 * math.Pi / 4
+
+// Default maximum warpage of a quad merged from two triangles.
+const QuadMaxWarpage = math.Pi / 9
+
+// Options to merge the triangles into quads. The zero value uses the
+// defaults.
+type QuadOptions struct {
+	// Maximum corner angle of a quad (radians). The default is QuadMaxAngle.
+	MaxAngle float64
+
+	// Maximum warpage of a quad (radians), the angle between the normals of
+	// the triangles merged. The default is QuadMaxWarpage.
+	MaxWarpage float64
+}
+
+// Candidate pair of triangles sharing a half edge merged into a quad.
+type quadCandidate struct {
+	halfEdge int
+	quad     []int
+	cost     float64
+}
+
+// Merge the pairs of neighboring triangles into quads (in place) to produce
+// a quad-dominant mesh (e.g. for a structural solver preferring quads). The
+// pairs are matched greedily from the most rectangular quad, measured by the
+// largest deviation of its corner angles from a right angle, which
+// approximates the optimal (Blossom) matching. Only triangles of the same
+// patch and material are merged, not across a feature edge, and quads
+// exceeding the maximum corner angle or warpage are rejected. The named face
+// sets follow the faces. The number of quads merged is returned. An error is
+// returned if the mesh cannot be rebuilt, in which case it is unchanged.
+func (m *HalfEdgeMesh) Quadrangulate(options QuadOptions) (int, error) {
+	if options.MaxAngle <= 0 {
+		options.MaxAngle = QuadMaxAngle
+	}
+
+	if options.MaxWarpage <= 0 {
+		options.MaxWarpage = QuadMaxWarpage
+	}
+
+	m.ensureAdjacency()
+
+	candidates := make([]quadCandidate, 0)
+
+	for i, halfEdge := range m.halfEdges {
+		if halfEdge.IsBoundary() || halfEdge.Twin < i || halfEdge.IsFeature {
+			continue
+		}
+
+		twin := m.halfEdges[halfEdge.Twin]
+		a, b := m.faces[halfEdge.Face], m.faces[twin.Face]
+
+		if halfEdge.Face == twin.Face || a.Patch != b.Patch || a.Material != b.Material {
+			continue
+		}
+
+		if len(m.GetFaceHalfEdges(halfEdge.Face)) != 3 || len(m.GetFaceHalfEdges(twin.Face)) != 3 {
+			continue
+		}
+
+		// The quad replaces the half edge p -> q of the triangle (p, q, r) and
+		// its twin of the triangle (q, p, s) by the diagonal.
+		q := m.halfEdges[halfEdge.Next].Origin
+		r := m.halfEdges[halfEdge.Prev].Origin
+		p := halfEdge.Origin
+		s := m.halfEdges[twin.Prev].Origin
+		quad := []int{q, r, p, s}
+
+		if cost, ok := m.getQuadCost(quad, options); ok {
+			candidates = append(candidates, quadCandidate{i, quad, cost})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b quadCandidate) int {
+		return cmp.Compare(a.cost, b.cost)
+	})
+
+	merged := make([]bool, m.GetNumberOfFaces())
+	quads := make(map[int][]int)
+	faceMap := make([]int, m.GetNumberOfFaces())
+
+	for i := range faceMap {
+		faceMap[i] = i
+	}
+
+	for _, candidate := range candidates {
+		halfEdge := m.halfEdges[candidate.halfEdge]
+		a, b := halfEdge.Face, m.halfEdges[halfEdge.Twin].Face
+
+		if merged[a] || merged[b] {
+			continue
+		}
+
+		merged[a], merged[b] = true, true
+		quads[a] = candidate.quad
+		faceMap[b] = a
+	}
+
+	if len(quads) == 0 {
+		return 0, nil
+	}
+
+	source := newMeshSource(m)
+	source.faces = nil
+	source.facePatches = nil
+	source.faceMaterials = nil
+	source.faceColors = nil
+	source.smoothingGroups = nil
+	indices := make([]int, m.GetNumberOfFaces())
+
+	for i, face := range m.faces {
+		if faceMap[i] != i {
+			continue
+		}
+
+		indices[i] = len(source.faces)
+
+		if quad, ok := quads[i]; ok {
+			source.addFace(quad, face)
+		} else {
+			source.addFace(m.GetFaceVertices(i), face)
+		}
+	}
+
+	for i, set := range source.sets {
+		if set.Kind == meshx.FaceSet {
+			for j, face := range set.Indices {
+				set.Indices[j] = indices[faceMap[face]]
+			}
+
+			slices.Sort(set.Indices)
+			source.sets[i].Indices = slices.Compact(set.Indices)
+		}
+	}
+
+	return len(quads), m.rebuild(source)
+}
+
+// Compute the cost of a quad as the largest deviation of its corner angles
+// from a right angle. The quad is rejected if a corner angle or its warpage
+// exceeds the maximum.
+func (m *HalfEdgeMesh) getQuadCost(quad []int, options QuadOptions) (float64, bool) {
+	points := make([]meshx.Vector, len(quad))
+
+	for i, vertex := range quad {
+		points[i] = m.vertices[vertex].Point
+	}
+
+	quality := ComputeFaceQuality(points)
+
+	if quality.MaxAngle > options.MaxAngle || quality.Warpage > options.MaxWarpage {
+		return 0, false
+	}
+
+	return max(quality.MaxAngle-math.Pi/2, math.Pi/2-quality.MinAngle), true
+}
@@ -0,0 +1,48 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test merging the triangles of a grid into squares.
+func TestQuadrangulate(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+	assert.Empty(t, mesh.AddSet("zone", meshx.FaceSet, []int{1, 7}))
+
+	count, err := mesh.Quadrangulate(QuadOptions{})
+	assert.Empty(t, err)
+	assert.Equal(t, 4, count)
+	assert.Equal(t, 4, mesh.GetNumberOfFaces())
+	assert.True(t, mesh.IsConsistent())
+
+	for i := range mesh.GetNumberOfFaces() {
+		assert.Len(t, mesh.GetFaceVertices(i), 4)
+		assert.InDelta(t, 1, mesh.GetFaceArea(i), 1e-12)
+	}
+
+	set, _ := mesh.GetSet("zone", meshx.FaceSet)
+	assert.Equal(t, []int{0, 3}, set.Indices)
+
+	// Quads are not merged again.
+	count, err = mesh.Quadrangulate(QuadOptions{})
+	assert.Empty(t, err)
+	assert.Equal(t, 0, count)
+}
+
+// Test the triangles of different patches or forming a skewed quad are not
+// merged.
+func TestQuadrangulateRejected(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+	mesh.AssignPatch("a", []int{0})
+	mesh.AssignPatch("b", []int{1, 2, 3, 4, 5, 6, 7})
+
+	count, err := mesh.Quadrangulate(QuadOptions{MaxAngle: 2})
+	assert.Empty(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 5, mesh.GetNumberOfFaces())
+	assert.Len(t, mesh.GetFaceVertices(0), 3)
+	assert.Len(t, mesh.GetFaceVertices(1), 3)
+}
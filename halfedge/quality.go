@@ -0,0 +1,166 @@
+package halfedge
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/planar"
+)
+
+// Quality metrics of a polygonal face. The angles are in radians. The
+// interior angles exceed pi at reflex vertices. The warpage, taper and skew
+// are defined for quadrilaterals and are zero for other faces.
+type FaceQuality struct {
+	Area        float64
+	MinAngle    float64
+	MaxAngle    float64
+	AspectRatio float64
+	Warpage     float64
+	Taper       float64
+	Skew        float64
+}
+
+// Summary of the quality metrics over the faces of a HalfEdgeMesh.
+type QualitySummary struct {
+	NumberOfTriangles int     `json:"numberOfTriangles"`
+	NumberOfQuads     int     `json:"numberOfQuads"`
+	NumberOfPolygons  int     `json:"numberOfPolygons"`
+	MinArea           float64 `json:"minArea"`
+	MaxArea           float64 `json:"maxArea"`
+	MinAngle          float64 `json:"minAngle"`
+	MaxAngle          float64 `json:"maxAngle"`
+	MaxAspectRatio    float64 `json:"maxAspectRatio"`
+	MeanAspectRatio   float64 `json:"meanAspectRatio"`
+	MaxWarpage        float64 `json:"maxWarpage"`
+	MaxTaper          float64 `json:"maxTaper"`
+	MaxSkew           float64 `json:"maxSkew"`
+}
+
+// Compute the quality metrics of a face from its points in order. The
+// aspect ratio is the ratio of the longest to the shortest edge. For a
+// quadrilateral, the warpage is the largest angle between the normals of the
+// two triangles on either side of a diagonal, and the taper and skew are
+// computed from the principal axes through the midpoints of opposite edges
+// (both zero for a rectangle).
+func ComputeFaceQuality(points []meshx.Vector) FaceQuality {
+	quality := FaceQuality{
+		MinAngle: math.Inf(1),
+		MaxAngle: math.Inf(-1),
+	}
+
+	for _, triangle := range planar.TriangulateFace(points) {
+		quality.Area += meshx.NewTriangle(points[triangle[0]], points[triangle[1]], points[triangle[2]]).Area()
+	}
+
+	normal := planar.ComputeLoopNormal(points)
+	shortest, longest := math.Inf(1), 0.0
+
+	for i, point := range points {
+		prev := points[(i+len(points)-1)%len(points)].Sub(point)
+		next := points[(i+1)%len(points)].Sub(point)
+		angle := getAngle(next, prev)
+
+		if next.Cross(prev).Dot(normal) < 0 {
+			angle = 2*math.Pi - angle
+		}
+
+		quality.MinAngle = min(quality.MinAngle, angle)
+		quality.MaxAngle = max(quality.MaxAngle, angle)
+		shortest = min(shortest, next.Mag())
+		longest = max(longest, next.Mag())
+	}
+
+	quality.AspectRatio = longest / shortest
+
+	if len(points) == 4 {
+		p0, p1, p2, p3 := points[0], points[1], points[2], points[3]
+
+		for i := range 2 {
+			a, b, c, d := points[i], points[i+1], points[i+2], points[(i+3)%4]
+			u := b.Sub(a).Cross(c.Sub(a))
+			v := c.Sub(a).Cross(d.Sub(a))
+
+			if u.Mag() != 0 && v.Mag() != 0 {
+				quality.Warpage = max(quality.Warpage, getAngle(u, v))
+			}
+		}
+
+		x1 := p1.Sub(p0).Add(p2.Sub(p3))
+		x2 := p2.Sub(p1).Add(p3.Sub(p0))
+		x12 := p0.Sub(p1).Add(p2.Sub(p3))
+
+		if size := min(x1.Mag(), x2.Mag()); size != 0 {
+			quality.Taper = x12.Mag() / size
+			quality.Skew = math.Abs(x1.Unit().Dot(x2.Unit()))
+		}
+	}
+
+	return quality
+}
+
+// Compute the angle between two vectors in [0, pi].
+func getAngle(u, v meshx.Vector) float64 {
+	return math.Atan2(u.Cross(v).Mag(), u.Dot(v))
+}
+
+// Compute the quality metrics of a face by index.
+func (m *HalfEdgeMesh) ComputeFaceQuality(index int) FaceQuality {
+	return ComputeFaceQuality(m.getFacePoints(index))
+}
+
+// Compute the quality metrics of each face.
+func (m *HalfEdgeMesh) ComputeQuality() []FaceQuality {
+	qualities := make([]FaceQuality, m.GetNumberOfFaces())
+
+	for i := range qualities {
+		qualities[i] = m.ComputeFaceQuality(i)
+	}
+
+	return qualities
+}
+
+// Summarize the quality metrics over the faces.
+func (m *HalfEdgeMesh) ComputeQualitySummary() QualitySummary {
+	sizes := make([]int, m.GetNumberOfFaces())
+	buf := make([]int, 0, 4)
+
+	for i := range sizes {
+		sizes[i] = len(m.GetFaceVerticesInto(i, buf))
+	}
+
+	return summarizeQuality(m.ComputeQuality(), sizes)
+}
+
+// Summarize the quality metrics of faces with a number of vertices each.
+func summarizeQuality(qualities []FaceQuality, sizes []int) QualitySummary {
+	summary := QualitySummary{
+		MinArea:        math.Inf(1),
+		MaxArea:        math.Inf(-1),
+		MinAngle:       math.Inf(1),
+		MaxAngle:       math.Inf(-1),
+		MaxAspectRatio: math.Inf(-1),
+	}
+
+	for i, quality := range qualities {
+		switch sizes[i] {
+		case 3:
+			summary.NumberOfTriangles++
+		case 4:
+			summary.NumberOfQuads++
+		default:
+			summary.NumberOfPolygons++
+		}
+
+		summary.MinArea = min(summary.MinArea, quality.Area)
+		summary.MaxArea = max(summary.MaxArea, quality.Area)
+		summary.MinAngle = min(summary.MinAngle, quality.MinAngle)
+		summary.MaxAngle = max(summary.MaxAngle, quality.MaxAngle)
+		summary.MaxAspectRatio = max(summary.MaxAspectRatio, quality.AspectRatio)
+		summary.MeanAspectRatio += quality.AspectRatio / float64(len(qualities))
+		summary.MaxWarpage = max(summary.MaxWarpage, quality.Warpage)
+		summary.MaxTaper = max(summary.MaxTaper, quality.Taper)
+		summary.MaxSkew = max(summary.MaxSkew, quality.Skew)
+	}
+
+	return summary
+}
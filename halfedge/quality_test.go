@@ -0,0 +1,117 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the quality metrics of a triangle and a square.
+func TestComputeFaceQuality(t *testing.T) {
+	triangle := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(0.5, math.Sqrt(3)/2, 0),
+	}
+
+	quality := ComputeFaceQuality(triangle)
+	assert.InDelta(t, math.Sqrt(3)/4, quality.Area, 1e-12)
+	assert.InDelta(t, math.Pi/3, quality.MinAngle, 1e-12)
+	assert.InDelta(t, math.Pi/3, quality.MaxAngle, 1e-12)
+	assert.InDelta(t, 1, quality.AspectRatio, 1e-12)
+	assert.Equal(t, 0.0, quality.Warpage)
+
+	square := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(2, 0, 0),
+		meshx.NewVector(2, 1, 0),
+		meshx.NewVector(0, 1, 0),
+	}
+
+	quality = ComputeFaceQuality(square)
+	assert.InDelta(t, 2, quality.Area, 1e-12)
+	assert.InDelta(t, math.Pi/2, quality.MinAngle, 1e-12)
+	assert.InDelta(t, math.Pi/2, quality.MaxAngle, 1e-12)
+	assert.InDelta(t, 2, quality.AspectRatio, 1e-12)
+	assert.Equal(t, 0.0, quality.Warpage)
+	assert.Equal(t, 0.0, quality.Taper)
+	assert.Equal(t, 0.0, quality.Skew)
+}
+
+// Test the quad specific quality metrics of distorted quadrilaterals.
+func TestComputeFaceQualityQuad(t *testing.T) {
+	warped := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(1, 1, 1),
+		meshx.NewVector(0, 1, 0),
+	}
+
+	quality := ComputeFaceQuality(warped)
+	assert.InDelta(t, math.Pi/3, quality.Warpage, 1e-12)
+
+	trapezoid := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(4, 0, 0),
+		meshx.NewVector(3, 1, 0),
+		meshx.NewVector(1, 1, 0),
+	}
+
+	quality = ComputeFaceQuality(trapezoid)
+	assert.InDelta(t, 3, quality.Area, 1e-12)
+	assert.InDelta(t, 1, quality.Taper, 1e-12)
+	assert.InDelta(t, 0, quality.Skew, 1e-12)
+
+	parallelogram := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(2, 1, 0),
+		meshx.NewVector(1, 1, 0),
+	}
+
+	quality = ComputeFaceQuality(parallelogram)
+	assert.InDelta(t, 0, quality.Taper, 1e-12)
+	assert.InDelta(t, math.Sqrt(2)/2, quality.Skew, 1e-12)
+	assert.InDelta(t, math.Pi/4, quality.MinAngle, 1e-12)
+
+	// Arrowhead with the reflex vertex last.
+	arrow := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(2, 1, 0),
+		meshx.NewVector(0, 2, 0),
+		meshx.NewVector(1, 1, 0),
+	}
+
+	quality = ComputeFaceQuality(arrow)
+	assert.InDelta(t, 1, quality.Area, 1e-12)
+	assert.InDelta(t, 1.5*math.Pi, quality.MaxAngle, 1e-12)
+}
+
+// Test the normals, areas and quality summary of a mixed mesh.
+func TestComputeQualitySummary(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	summary := mesh.ComputeQualitySummary()
+	assert.Equal(t, 2, summary.NumberOfTriangles)
+	assert.Equal(t, 5, summary.NumberOfQuads)
+	assert.Equal(t, 0, summary.NumberOfPolygons)
+	assert.InDelta(t, 0.5, summary.MinArea, 1e-12)
+	assert.InDelta(t, 1, summary.MaxArea, 1e-12)
+	assert.InDelta(t, math.Pi/4, summary.MinAngle, 1e-12)
+	assert.InDelta(t, math.Pi/2, summary.MaxAngle, 1e-12)
+	assert.Equal(t, 0.0, summary.MaxWarpage)
+	assert.Equal(t, 0.0, summary.MaxTaper)
+	assert.Equal(t, 0.0, summary.MaxSkew)
+
+	var area float64
+
+	for i := range mesh.GetNumberOfFaces() {
+		area += mesh.GetFaceArea(i)
+		assert.InDelta(t, 1, mesh.GetFaceNormal(i).Mag(), 1e-12)
+	}
+
+	assert.InDelta(t, 6, area, 1e-12)
+}
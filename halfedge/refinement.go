@@ -0,0 +1,106 @@
+package halfedge
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Shape of a refinement region (e.g. meshx.AABB, meshx.Sphere or
+// meshx.Cylinder).
+type RefinementShape interface {
+	// Compute the distance to a point (zero inside the shape).
+	DistanceTo(point meshx.Vector) float64
+}
+
+// Region of space with a local voxel size (e.g. finer around a small
+// feature or coarser where gaps should be closed).
+type RefinementRegion struct {
+	Shape RefinementShape
+
+	// Offset from the shape within which the region applies.
+	Distance float64
+
+	// Edge length of the voxels in the region.
+	Size float64
+}
+
+// Construct a RefinementRegion within a distance of a shape.
+func NewRefinementRegion(shape RefinementShape, distance, size float64) RefinementRegion {
+	return RefinementRegion{shape, distance, size}
+}
+
+// Construct a RefinementRegion within a distance of the faces of the
+// patches.
+func (m *HalfEdgeMesh) NewPatchRefinementRegion(patches []int, distance, size float64) RefinementRegion {
+	shape := patchShape{m.ExtractPatches(patches).BuildOctree()}
+	return NewRefinementRegion(shape, distance, size)
+}
+
+// Check if the region contains a point.
+func (r RefinementRegion) Contains(point meshx.Vector) bool {
+	return r.Shape.DistanceTo(point) <= r.Distance
+}
+
+// Shape of the faces of a set of patches.
+type patchShape struct {
+	octree *spatial.Octree
+}
+
+// Implement the RefinementShape interface.
+func (s patchShape) DistanceTo(point meshx.Vector) float64 {
+	index, closest := s.octree.QueryNearest(point)
+
+	if index == -1 {
+		return math.Inf(1)
+	}
+
+	return closest.Sub(point).Mag()
+}
+
+// Get the local size at a point: the smallest size of the regions
+// containing the point, or the default size outside the regions.
+func GetRefinementSize(regions []RefinementRegion, size float64, point meshx.Vector) float64 {
+	local := math.Inf(1)
+
+	for _, region := range regions {
+		if region.Size > 0 && region.Size < local && region.Contains(point) {
+			local = region.Size
+		}
+	}
+
+	if math.IsInf(local, 1) {
+		return size
+	}
+
+	return local
+}
+
+// Construct a function of the local size at a point (e.g. the SizeFunc of
+// the options to tetrahedralize). See GetRefinementSize.
+func NewRefinementSizeFunc(regions []RefinementRegion, size float64) func(meshx.Vector) float64 {
+	return func(point meshx.Vector) float64 {
+		return GetRefinementSize(regions, size, point)
+	}
+}
+
+// Get the smallest of the default size and the sizes of the regions.
+func getMinRefinementSize(regions []RefinementRegion, size float64) float64 {
+	for _, region := range regions {
+		if region.Size > 0 {
+			size = min(size, region.Size)
+		}
+	}
+
+	return size
+}
+
+// Get the largest of the default size and the sizes of the regions.
+func getMaxRefinementSize(regions []RefinementRegion, size float64) float64 {
+	for _, region := range regions {
+		size = max(size, region.Size)
+	}
+
+	return size
+}
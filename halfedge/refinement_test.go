@@ -0,0 +1,81 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the local size of overlapping refinement regions.
+func TestGetRefinementSize(t *testing.T) {
+	box := meshx.NewAABB(meshx.NewVector(0, 0, 0), meshx.NewVector(1, 1, 1))
+	sphere := meshx.NewSphere(meshx.NewVector(1, 0, 0), 0.5)
+	cylinder := meshx.NewCylinder(meshx.NewVector(0, 0, -2), meshx.NewVector(0, 0, 2), 0.1)
+
+	regions := []RefinementRegion{
+		NewRefinementRegion(box, 0, 0.5),
+		NewRefinementRegion(sphere, 0.5, 0.2),
+		NewRefinementRegion(cylinder, 0, 0.1),
+	}
+
+	sizeFunc := NewRefinementSizeFunc(regions, 1)
+	assert.Equal(t, 1.0, sizeFunc(meshx.NewVector(3, 3, 3)))
+	assert.Equal(t, 0.5, sizeFunc(meshx.NewVector(-0.5, 0.5, 0.5)))
+	assert.Equal(t, 0.2, sizeFunc(meshx.NewVector(1.9, 0, 0)))
+	assert.Equal(t, 0.1, sizeFunc(meshx.NewVector(0, 0, 1.5)))
+	assert.Equal(t, 0.1, GetRefinementSize(regions, 1, meshx.NewVector(0, 0, 0)))
+}
+
+// Test a refinement region offset from the faces of a patch.
+func TestNewPatchRefinementRegion(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	region := mesh.NewPatchRefinementRegion([]int{0}, 0.1, 0.05)
+	assert.True(t, region.Contains(meshx.NewVector(0.05, 0.5, 0.5)))
+	assert.True(t, region.Contains(meshx.NewVector(-0.05, 0.5, 0.5)))
+	assert.False(t, region.Contains(meshx.NewVector(0.5, 0.5, 0.5)))
+}
+
+// Test closing a gap in a cylinder by a coarse refinement region.
+func TestRefinementCloseGap(t *testing.T) {
+	mesh := newTestCylinder(t, 1, 0, 0.4)
+	faces := make([]int, 0)
+
+	for i := range mesh.GetNumberOfFaces() {
+		if i != 16 {
+			faces = append(faces, i)
+		}
+	}
+
+	gap := getTestFaceCenter(mesh, 16)
+	open := mesh.Extract(faces)
+	expected := getTestVolume(mesh)
+
+	// The gap is wider than the voxels so the wrap is inside the cylinder.
+	wrap, err := open.WrapWithOptions(WrapOptions{VoxelSize: 0.05})
+	assert.Empty(t, err)
+	assert.True(t, wrap.IsClosed())
+	assert.Less(t, getTestVolume(wrap), 0.5*expected)
+
+	region := NewRefinementRegion(meshx.NewSphere(gap, 0.5), 0, 0.5)
+	options := WrapOptions{VoxelSize: 0.05, Refinements: []RefinementRegion{region}}
+	wrap, err = open.WrapWithOptions(options)
+	assert.Empty(t, err)
+	assert.True(t, wrap.IsClosed())
+	assert.True(t, wrap.IsConsistent())
+	assert.InDelta(t, expected, getTestVolume(wrap), 0.05*expected)
+
+	interior := meshx.NewVector(0, 0, 0.2)
+	exterior := meshx.NewVector(2, 2, 0.2)
+
+	leaks, err := open.FindLeaksWithOptions(interior, exterior, LeakOptions{VoxelSize: 0.05})
+	assert.Empty(t, err)
+	assert.Equal(t, 1, len(leaks))
+
+	leakOptions := LeakOptions{VoxelSize: 0.05, Refinements: []RefinementRegion{region}}
+	leaks, err = open.FindLeaksWithOptions(interior, exterior, leakOptions)
+	assert.Empty(t, err)
+	assert.Equal(t, 0, len(leaks))
+}
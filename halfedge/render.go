@@ -0,0 +1,171 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/planar"
+)
+
+// Options for the render buffers of a HalfEdgeMesh. Zero values use the
+// defaults.
+type RenderBufferOptions struct {
+	// Interleave the attributes of each vertex (position, normal and texture
+	// coordinates) in a single buffer rather than a buffer for each.
+	Interleaved bool
+
+	// Include the normals of the face corners from the smoothing groups (see
+	// ComputeCornerNormals).
+	Normals bool
+
+	// Texture coordinates of each vertex included if not nil.
+	UVs [][2]float64
+
+	// Triangulate the faces as fans from their first vertex (valid for convex
+	// faces only) rather than by ear clipping.
+	Fan bool
+
+	// Share a render vertex between the face corners with the same attributes
+	// rather than a render vertex for each face corner.
+	Deduplicate bool
+}
+
+// Flat buffers of the triangles of a HalfEdgeMesh to upload to a GPU. The
+// attributes are either interleaved (Interleaved with Stride values per
+// vertex) or in separate buffers (Positions, Normals and UVs).
+type RenderBuffers struct {
+	Interleaved []float32
+	Stride      int
+	Positions   []float32
+	Normals     []float32
+	UVs         []float32
+	Indices     []uint32
+
+	// Mesh vertex of each render vertex.
+	Vertices []int
+}
+
+// Get the number of render vertices.
+func (b RenderBuffers) GetNumberOfVertices() int {
+	return len(b.Vertices)
+}
+
+// Key of a render vertex to deduplicate.
+type renderVertexKey struct {
+	vertex int
+	normal [3]float32
+}
+
+// Get the render buffers of the triangles of the faces.
+func (m *HalfEdgeMesh) GetRenderBuffers(options RenderBufferOptions) RenderBuffers {
+	var normals []meshx.Vector
+
+	if options.Normals {
+		normals = m.ComputeCornerNormals()
+	}
+
+	buffers := RenderBuffers{
+		Positions: make([]float32, 0, 3*m.GetNumberOfVertices()),
+		Indices:   make([]uint32, 0, 3*m.GetNumberOfFaces()),
+		Vertices:  make([]int, 0, m.GetNumberOfVertices()),
+	}
+
+	if options.Normals {
+		buffers.Normals = make([]float32, 0, 3*m.GetNumberOfVertices())
+	}
+
+	if options.UVs != nil {
+		buffers.UVs = make([]float32, 0, 2*m.GetNumberOfVertices())
+	}
+
+	indexVertices := make(map[renderVertexKey]uint32)
+
+	addVertex := func(halfEdge int) uint32 {
+		key := renderVertexKey{vertex: m.halfEdges[halfEdge].Origin}
+
+		if options.Normals {
+			normal := normals[halfEdge]
+			key.normal = [3]float32{float32(normal[0]), float32(normal[1]), float32(normal[2])}
+		}
+
+		if options.Deduplicate {
+			if index, ok := indexVertices[key]; ok {
+				return index
+			}
+		}
+
+		index := uint32(len(buffers.Vertices))
+		point := m.vertices[key.vertex].Point
+		buffers.Vertices = append(buffers.Vertices, key.vertex)
+		buffers.Positions = append(buffers.Positions, float32(point[0]), float32(point[1]), float32(point[2]))
+
+		if options.Normals {
+			buffers.Normals = append(buffers.Normals, key.normal[:]...)
+		}
+
+		if options.UVs != nil {
+			uv := options.UVs[key.vertex]
+			buffers.UVs = append(buffers.UVs, float32(uv[0]), float32(uv[1]))
+		}
+
+		if options.Deduplicate {
+			indexVertices[key] = index
+		}
+
+		return index
+	}
+
+	for i := range m.faces {
+		halfEdges := m.GetFaceHalfEdges(i)
+		corners := make([]uint32, len(halfEdges))
+
+		for j, halfEdge := range halfEdges {
+			corners[j] = addVertex(halfEdge)
+		}
+
+		if options.Fan {
+			for j := 1; j < len(corners)-1; j++ {
+				buffers.Indices = append(buffers.Indices, corners[0], corners[j], corners[j+1])
+			}
+
+			continue
+		}
+
+		for _, triangle := range planar.TriangulateFace(m.getFacePoints(i)) {
+			buffers.Indices = append(buffers.Indices, corners[triangle[0]], corners[triangle[1]], corners[triangle[2]])
+		}
+	}
+
+	if options.Interleaved {
+		buffers.interleave(options.Normals, options.UVs != nil)
+	}
+
+	return buffers
+}
+
+// Interleave the separate buffers of the attributes.
+func (b *RenderBuffers) interleave(hasNormals, hasUVs bool) {
+	b.Stride = 3
+
+	if hasNormals {
+		b.Stride += 3
+	}
+
+	if hasUVs {
+		b.Stride += 2
+	}
+
+	b.Interleaved = make([]float32, 0, b.Stride*len(b.Vertices))
+
+	for i := range b.Vertices {
+		b.Interleaved = append(b.Interleaved, b.Positions[3*i:3*i+3]...)
+
+		if hasNormals {
+			b.Interleaved = append(b.Interleaved, b.Normals[3*i:3*i+3]...)
+		}
+
+		if hasUVs {
+			b.Interleaved = append(b.Interleaved, b.UVs[2*i:2*i+2]...)
+		}
+	}
+
+	b.Positions, b.Normals, b.UVs = nil, nil, nil
+}
@@ -0,0 +1,90 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Get a render vertex position.
+func getRenderPosition(values []float32, stride, index int) meshx.Vector {
+	return meshx.NewVector(float64(values[stride*index]), float64(values[stride*index+1]), float64(values[stride*index+2]))
+}
+
+func TestGetRenderBuffers(t *testing.T) {
+	mesh := newTestCube(t)
+
+	for _, fan := range []bool{false, true} {
+		buffers := mesh.GetRenderBuffers(RenderBufferOptions{Fan: fan})
+		assert.Equal(t, 24, buffers.GetNumberOfVertices())
+		assert.Equal(t, 3*24, len(buffers.Positions))
+		assert.Equal(t, 3*12, len(buffers.Indices))
+		assert.Nil(t, buffers.Normals)
+		assert.Nil(t, buffers.Interleaved)
+
+		for i, vertex := range buffers.Vertices {
+			assert.Equal(t, mesh.GetVertex(vertex).Point, getRenderPosition(buffers.Positions, 3, i))
+		}
+
+		// The triangles are oriented as their faces.
+		for i := 0; i < len(buffers.Indices); i += 3 {
+			p := getRenderPosition(buffers.Positions, 3, int(buffers.Indices[i]))
+			q := getRenderPosition(buffers.Positions, 3, int(buffers.Indices[i+1]))
+			r := getRenderPosition(buffers.Positions, 3, int(buffers.Indices[i+2]))
+			normal := q.Sub(p).Cross(r.Sub(p))
+			assert.Greater(t, normal.Dot(p.Sub(meshx.NewVector(0.5, 0.5, 0.5))), 0.0)
+		}
+	}
+}
+
+func TestGetRenderBuffersDeduplicate(t *testing.T) {
+	mesh := newTestCube(t)
+
+	buffers := mesh.GetRenderBuffers(RenderBufferOptions{Deduplicate: true})
+	assert.Equal(t, 8, buffers.GetNumberOfVertices())
+
+	// The corners of flat faces have distinct normals.
+	buffers = mesh.GetRenderBuffers(RenderBufferOptions{Deduplicate: true, Normals: true})
+	assert.Equal(t, 24, buffers.GetNumberOfVertices())
+
+	for i := range mesh.GetNumberOfFaces() {
+		mesh.SetFaceSmoothingGroup(i, 1)
+	}
+
+	buffers = mesh.GetRenderBuffers(RenderBufferOptions{Deduplicate: true, Normals: true})
+	assert.Equal(t, 8, buffers.GetNumberOfVertices())
+	assert.Equal(t, 3*8, len(buffers.Normals))
+	assert.Equal(t, 3*12, len(buffers.Indices))
+}
+
+func TestGetRenderBuffersInterleaved(t *testing.T) {
+	mesh := newTestCube(t)
+	uvs := make([][2]float64, mesh.GetNumberOfVertices())
+
+	for i := range uvs {
+		point := mesh.GetVertex(i).Point
+		uvs[i] = [2]float64{point[0], point[1]}
+	}
+
+	options := RenderBufferOptions{Interleaved: true, Normals: true, UVs: uvs}
+	buffers := mesh.GetRenderBuffers(options)
+	assert.Equal(t, 8, buffers.Stride)
+	assert.Equal(t, 8*24, len(buffers.Interleaved))
+	assert.Nil(t, buffers.Positions)
+	assert.Nil(t, buffers.Normals)
+	assert.Nil(t, buffers.UVs)
+
+	for i, vertex := range buffers.Vertices {
+		point := mesh.GetVertex(vertex).Point
+		values := buffers.Interleaved[8*i : 8*i+8]
+		assert.Equal(t, point, getRenderPosition(values, 0, 0))
+		assert.Equal(t, []float32{float32(point[0]), float32(point[1])}, values[6:])
+		assert.InDelta(t, 1, getRenderPosition(values[3:], 0, 0).Mag(), 1e-6)
+	}
+
+	options.UVs = nil
+	buffers = mesh.GetRenderBuffers(options)
+	assert.Equal(t, 6, buffers.Stride)
+	assert.Equal(t, 6*24, len(buffers.Interleaved))
+}
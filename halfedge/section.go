@@ -0,0 +1,121 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/curve"
+	"github.com/ajcurley/meshx-go/planar"
+)
+
+// Cross section of a closed mesh by a plane.
+type CrossSection struct {
+	// Arc length along the path (zero for a single plane).
+	Station float64
+
+	// Origin and unit normal of the plane.
+	Origin meshx.Vector
+	Normal meshx.Vector
+
+	// Area and perimeter of the section (including holes).
+	Area      float64
+	Perimeter float64
+
+	// Hydraulic diameter (4 * Area / Perimeter). Zero without a perimeter.
+	HydraulicDiameter float64
+}
+
+// Compute the cross section of a closed mesh by the plane through the
+// origin with the normal. The section is the region bounded by the smallest
+// contour around the origin, less the holes inside it, so the other parts
+// of the mesh cut by the plane (e.g. a duct bending back) are excluded. The
+// section is empty if no contour encloses the origin.
+func (m *HalfEdgeMesh) ComputeCrossSection(origin, normal meshx.Vector) CrossSection {
+	normal = normal.Unit()
+	section := CrossSection{Origin: origin, Normal: normal}
+	network := m.Slice(origin, normal)
+
+	loops := make([][]meshx.Vector, 0)
+	areas := make([]float64, 0)
+	lengths := make([]float64, 0)
+	var total float64
+
+	for _, polyline := range network.GetPolylines() {
+		if polyline.Closed {
+			area := planar.ComputeLoopNormal(polyline.Points).Dot(normal) / 2
+			loops = append(loops, planar.ProjectToPlane(polyline.Points, normal))
+			areas = append(areas, area)
+			lengths = append(lengths, polyline.Length())
+			total += area
+		}
+	}
+
+	// The contours are clockwise around the material for an inward mesh.
+	if total < 0 {
+		for i := range areas {
+			areas[i] = -areas[i]
+		}
+	}
+
+	center := planar.ProjectToPlane([]meshx.Vector{origin}, normal)[0]
+	outer := -1
+
+	for i, loop := range loops {
+		if areas[i] > 0 && planar.ContainsPoint(loop, center) {
+			if outer == -1 || areas[i] < areas[outer] {
+				outer = i
+			}
+		}
+	}
+
+	if outer == -1 {
+		return section
+	}
+
+	section.Area = areas[outer]
+	section.Perimeter = lengths[outer]
+
+	for i, loop := range loops {
+		if areas[i] < 0 && planar.ContainsPoint(loops[outer], loop[0]) {
+			section.Area += areas[i]
+			section.Perimeter += lengths[i]
+		}
+	}
+
+	if section.Perimeter > 0 {
+		section.HydraulicDiameter = 4 * section.Area / section.Perimeter
+	}
+
+	return section
+}
+
+// Compute the cross sections of a closed (tubular) mesh at a number of
+// stations uniformly spaced along a path. The planes are normal to the path
+// (by central differences between the stations). A section is empty where
+// the path has no direction. See ComputeCrossSection.
+func (m *HalfEdgeMesh) ComputeCrossSections(path curve.Polyline, count int) []CrossSection {
+	stations := path.Resample(count).Points
+	sections := make([]CrossSection, len(stations))
+
+	spacing := path.Length() / float64(max(1, count-1))
+
+	if path.Closed {
+		spacing = path.Length() / float64(max(1, count))
+	}
+
+	for i, station := range stations {
+		prev, next := max(i-1, 0), min(i+1, len(stations)-1)
+
+		if path.Closed {
+			prev, next = (i+len(stations)-1)%len(stations), (i+1)%len(stations)
+		}
+
+		sections[i] = CrossSection{Origin: station}
+
+		if tangent := stations[next].Sub(stations[prev]); tangent.Mag() > 0 {
+			sections[i] = m.ComputeCrossSection(station, tangent)
+		}
+
+		sections[i].Station = float64(i) * spacing
+	}
+
+	return sections
+}
@@ -0,0 +1,65 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/curve"
+	"github.com/stretchr/testify/assert"
+)
+
+// Construct an annular pipe along the Z-axis.
+func newTestPipe(t *testing.T) *HalfEdgeMesh {
+	profile := []meshx.Vector{
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(2, 0, 0),
+		meshx.NewVector(2, 0, 4),
+		meshx.NewVector(1, 0, 4),
+		meshx.NewVector(1, 0, 0),
+	}
+
+	mesh, err := NewRevolve(profile, meshx.Vector{}, meshx.NewVector(0, 0, 1), 32)
+	assert.Empty(t, err)
+	return mesh
+}
+
+// Test the cross section of an annular pipe with the origin in the hole.
+func TestComputeCrossSection(t *testing.T) {
+	mesh := newTestPipe(t)
+	n := 32.0
+
+	section := mesh.ComputeCrossSection(meshx.NewVector(0, 0, 2), meshx.NewVector(0, 0, -2))
+	assert.Equal(t, meshx.NewVector(0, 0, -1), section.Normal)
+	assert.InDelta(t, n/2*math.Sin(2*math.Pi/n)*3, section.Area, 1e-12)
+	assert.InDelta(t, 2*n*math.Sin(math.Pi/n)*3, section.Perimeter, 1e-12)
+	assert.InDelta(t, 2*math.Cos(math.Pi/n), section.HydraulicDiameter, 1e-12)
+
+	section = mesh.ComputeCrossSection(meshx.NewVector(5, 0, 2), meshx.NewVector(0, 0, 1))
+	assert.Equal(t, 0.0, section.Area)
+	assert.Equal(t, 0.0, section.HydraulicDiameter)
+}
+
+// Test the cross sections at stations along the axis of a pipe.
+func TestComputeCrossSections(t *testing.T) {
+	mesh := newTestPipe(t)
+	path := curve.NewPolyline([]meshx.Vector{
+		meshx.NewVector(0, 0, 0.5),
+		meshx.NewVector(0, 0, 5.5),
+	}, false)
+
+	sections := mesh.ComputeCrossSections(path, 6)
+	assert.Equal(t, 6, len(sections))
+
+	for i, section := range sections {
+		assert.InDelta(t, float64(i), section.Station, 1e-12)
+		assert.InDelta(t, 0.5+float64(i), section.Origin[2], 1e-12)
+		assert.Equal(t, meshx.NewVector(0, 0, 1), section.Normal)
+
+		if i < 4 {
+			assert.InDelta(t, 2*math.Cos(math.Pi/32), section.HydraulicDiameter, 1e-12)
+		} else {
+			assert.Equal(t, 0.0, section.Area)
+		}
+	}
+}
@@ -0,0 +1,498 @@
+package halfedge
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrInvalidQuery = errors.New("invalid selection query")
+)
+
+// Value of an attribute or literal of a selection query.
+type selectValue struct {
+	number float64
+	text   string
+}
+
+// Operand of a selection query evaluated per element.
+type selectOperand struct {
+	isText bool
+	eval   func(int) selectValue
+}
+
+// Predicate of a selection query evaluated per element.
+type selectPredicate func(int) bool
+
+// Select the faces matching a query, e.g.
+//
+//	normal.z > 0.9 && area < 1e-4 || patch =~ "wheel_*"
+//
+// The query compares the attributes of a face with numbers or quoted strings
+// by <, <=, >, >=, == and != or matches a string attribute with a glob
+// pattern by =~ (!~ to not match), combined by !, && and || (in order of
+// precedence) and parentheses. A numeric attribute alone is true if nonzero
+// and a string attribute if not empty. The attributes of a face are index,
+// area, normal.x/y/z, centroid.x/y/z, vertices (the number of vertices),
+// patch and material (the names or empty), baffle and boundary (1 if a
+// baffle or on the boundary). The sorted faces are returned, e.g. to extract
+// them, add them as a set or assign them to a patch (see AssignPatch).
+func (m *HalfEdgeMesh) SelectFaces(query string) ([]int, error) {
+	attributes := map[string]selectOperand{
+		"index": newSelectNumber(func(i int) float64 { return float64(i) }),
+		"area":  newSelectNumber(m.GetFaceArea),
+		"vertices": newSelectNumber(func(i int) float64 {
+			return float64(len(m.GetFaceHalfEdges(i)))
+		}),
+		"patch": newSelectText(func(i int) string {
+			if patch := m.faces[i].Patch; patch >= 0 {
+				return m.patches[patch].Name
+			}
+			return ""
+		}),
+		"material": newSelectText(func(i int) string {
+			if material := m.faces[i].Material; material >= 0 {
+				return m.materials[material].Name
+			}
+			return ""
+		}),
+		"baffle": newSelectBool(m.IsBaffleFace),
+		"boundary": newSelectBool(func(i int) bool {
+			return slices.ContainsFunc(m.GetFaceHalfEdges(i), func(halfEdge int) bool {
+				return m.halfEdges[halfEdge].IsBoundary()
+			})
+		}),
+	}
+
+	addSelectVector(attributes, "normal", m.GetFaceNormal)
+	addSelectVector(attributes, "centroid", m.GetFaceCentroid)
+
+	return m.selectElements(query, attributes, m.GetNumberOfFaces())
+}
+
+// Select the vertices matching a query (see SelectFaces). The attributes of
+// a vertex are index, x, y and z (or point.x/y/z), normal.x/y/z (the
+// area-weighted normal of its faces), valence (the number of its faces) and
+// boundary (1 if on the boundary). The sorted vertices are returned.
+func (m *HalfEdgeMesh) SelectVertices(query string) ([]int, error) {
+	var normals []meshx.Vector
+	var valences []int
+	var boundaries []bool
+
+	// Count the faces and find the boundary half edges of the vertices.
+	countVertices := func() {
+		if valences != nil {
+			return
+		}
+
+		valences = make([]int, m.GetNumberOfVertices())
+		boundaries = make([]bool, m.GetNumberOfVertices())
+
+		for _, halfEdge := range m.halfEdges {
+			valences[halfEdge.Origin]++
+
+			if halfEdge.IsBoundary() {
+				boundaries[halfEdge.Origin] = true
+				boundaries[m.halfEdges[halfEdge.Next].Origin] = true
+			}
+		}
+	}
+
+	attributes := map[string]selectOperand{
+		"index": newSelectNumber(func(i int) float64 { return float64(i) }),
+		"valence": newSelectNumber(func(i int) float64 {
+			countVertices()
+			return float64(valences[i])
+		}),
+		"boundary": newSelectBool(func(i int) bool {
+			countVertices()
+			return boundaries[i]
+		}),
+	}
+
+	point := func(i int) meshx.Vector { return m.vertices[i].Point }
+	addSelectVector(attributes, "point", point)
+	addSelectVector(attributes, "", point)
+	addSelectVector(attributes, "normal", func(i int) meshx.Vector {
+		if normals == nil {
+			normals = m.getVertexNormals()
+		}
+		return normals[i]
+	})
+
+	return m.selectElements(query, attributes, m.GetNumberOfVertices())
+}
+
+// Assign faces (e.g. a selection) to the patch of a name or alias, adding
+// the patch if it does not exist. The index of the patch is returned.
+func (m *HalfEdgeMesh) AssignPatch(name string, faces []int) int {
+	patch := m.GetPatchIndex(name)
+
+	if patch < 0 {
+		patch = m.AddPatch(name)
+	}
+
+	for _, face := range faces {
+		m.faces[face].Patch = patch
+	}
+
+	changed := slices.Clone(faces)
+	slices.Sort(changed)
+	m.notify(PatchesChanged, slices.Compact(changed))
+
+	return patch
+}
+
+// Select the elements matching a query of their attributes.
+func (m *HalfEdgeMesh) selectElements(query string, attributes map[string]selectOperand, count int) ([]int, error) {
+	m.ensureAdjacency()
+
+	parser := selectParser{attributes: attributes}
+
+	if err := parser.tokenize(query); err != nil {
+		return nil, err
+	}
+
+	predicate, err := parser.parseOr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.position < len(parser.tokens) {
+		return nil, parser.errorf("unexpected %q", parser.tokens[parser.position])
+	}
+
+	selected := make([]int, 0)
+
+	for i := range count {
+		if predicate(i) {
+			selected = append(selected, i)
+		}
+	}
+
+	return selected, nil
+}
+
+// Construct a numeric operand.
+func newSelectNumber(eval func(int) float64) selectOperand {
+	return selectOperand{eval: func(i int) selectValue {
+		return selectValue{number: eval(i)}
+	}}
+}
+
+// Construct a string operand.
+func newSelectText(eval func(int) string) selectOperand {
+	return selectOperand{isText: true, eval: func(i int) selectValue {
+		return selectValue{text: eval(i)}
+	}}
+}
+
+// Construct a boolean (numeric 1 or 0) operand.
+func newSelectBool(eval func(int) bool) selectOperand {
+	return newSelectNumber(func(i int) float64 {
+		if eval(i) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Add the components of a vector attribute (name.x, name.y and name.z, or
+// x, y and z without a name).
+func addSelectVector(attributes map[string]selectOperand, name string, eval func(int) meshx.Vector) {
+	for i, component := range []string{"x", "y", "z"} {
+		if name != "" {
+			component = name + "." + component
+		}
+
+		attributes[component] = newSelectNumber(func(index int) float64 {
+			return eval(index)[i]
+		})
+	}
+}
+
+// Recursive descent parser of a selection query.
+type selectParser struct {
+	attributes map[string]selectOperand
+	tokens     []string
+	position   int
+}
+
+// Operators of a selection query (longest first).
+var selectOperators = []string{"&&", "||", "<=", ">=", "==", "!=", "=~", "!~", "<", ">", "!", "(", ")", "-"}
+
+// Split a query into tokens: operators, numbers, identifiers and quoted
+// strings (kept quoted).
+func (p *selectParser) tokenize(query string) error {
+	for i := 0; i < len(query); {
+		c := rune(query[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"':
+			j := i + 1
+
+			for j < len(query) && query[j] != '"' {
+				if query[j] == '\\' {
+					j++
+				}
+				j++
+			}
+
+			if j >= len(query) {
+				return fmt.Errorf("%w: unterminated string", ErrInvalidQuery)
+			}
+
+			p.tokens = append(p.tokens, query[i:j+1])
+			i = j + 1
+		case unicode.IsDigit(c) || c == '.':
+			j := i + 1
+
+			for j < len(query) && (isSelectIdentifier(rune(query[j])) || query[j] == '.' ||
+				(strings.ContainsRune("+-", rune(query[j])) && strings.ContainsRune("eE", rune(query[j-1])))) {
+				j++
+			}
+
+			p.tokens = append(p.tokens, query[i:j])
+			i = j
+		case isSelectIdentifier(c):
+			j := i + 1
+
+			for j < len(query) && (isSelectIdentifier(rune(query[j])) || query[j] == '.') {
+				j++
+			}
+
+			p.tokens = append(p.tokens, query[i:j])
+			i = j
+		default:
+			k := slices.IndexFunc(selectOperators, func(operator string) bool {
+				return strings.HasPrefix(query[i:], operator)
+			})
+
+			if k < 0 {
+				return fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, c)
+			}
+
+			p.tokens = append(p.tokens, selectOperators[k])
+			i += len(selectOperators[k])
+		}
+	}
+
+	return nil
+}
+
+// Check if a character is part of an identifier.
+func isSelectIdentifier(c rune) bool {
+	return c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+// Get an error of the query.
+func (p *selectParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidQuery, fmt.Sprintf(format, args...))
+}
+
+// Get the current token (empty at the end).
+func (p *selectParser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+// Parse a disjunction.
+func (p *selectParser) parseOr() (selectPredicate, error) {
+	left, err := p.parseAnd()
+
+	for err == nil && p.peek() == "||" {
+		p.position++
+		var right selectPredicate
+
+		if right, err = p.parseAnd(); err == nil {
+			a, b := left, right
+			left = func(i int) bool { return a(i) || b(i) }
+		}
+	}
+
+	return left, err
+}
+
+// Parse a conjunction.
+func (p *selectParser) parseAnd() (selectPredicate, error) {
+	left, err := p.parseNot()
+
+	for err == nil && p.peek() == "&&" {
+		p.position++
+		var right selectPredicate
+
+		if right, err = p.parseNot(); err == nil {
+			a, b := left, right
+			left = func(i int) bool { return a(i) && b(i) }
+		}
+	}
+
+	return left, err
+}
+
+// Parse a negation, a parenthesized query or a comparison.
+func (p *selectParser) parseNot() (selectPredicate, error) {
+	switch p.peek() {
+	case "!":
+		p.position++
+		predicate, err := p.parseNot()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return func(i int) bool { return !predicate(i) }, nil
+	case "(":
+		p.position++
+		predicate, err := p.parseOr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() != ")" {
+			return nil, p.errorf("expected )")
+		}
+
+		p.position++
+		return predicate, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+// Parse a comparison or an operand alone.
+func (p *selectParser) parseComparison() (selectPredicate, error) {
+	left, err := p.parseOperand()
+
+	if err != nil {
+		return nil, err
+	}
+
+	operator := p.peek()
+
+	switch operator {
+	case "<", "<=", ">", ">=", "==", "!=":
+		p.position++
+	case "=~", "!~":
+		p.position++
+		return p.parseMatch(left, operator == "!~")
+	default:
+		if left.isText {
+			return func(i int) bool { return left.eval(i).text != "" }, nil
+		}
+		return func(i int) bool { return left.eval(i).number != 0 }, nil
+	}
+
+	right, err := p.parseOperand()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if left.isText != right.isText {
+		return nil, p.errorf("%s compares a string with a number", operator)
+	}
+
+	if left.isText {
+		switch operator {
+		case "==":
+			return func(i int) bool { return left.eval(i).text == right.eval(i).text }, nil
+		case "!=":
+			return func(i int) bool { return left.eval(i).text != right.eval(i).text }, nil
+		default:
+			return nil, p.errorf("%s compares strings", operator)
+		}
+	}
+
+	compare := map[string]func(a, b float64) bool{
+		"<":  func(a, b float64) bool { return a < b },
+		"<=": func(a, b float64) bool { return a <= b },
+		">":  func(a, b float64) bool { return a > b },
+		">=": func(a, b float64) bool { return a >= b },
+		"==": func(a, b float64) bool { return a == b },
+		"!=": func(a, b float64) bool { return a != b },
+	}[operator]
+
+	return func(i int) bool { return compare(left.eval(i).number, right.eval(i).number) }, nil
+}
+
+// Parse the glob pattern (a string literal) matched by a string operand.
+func (p *selectParser) parseMatch(left selectOperand, negate bool) (selectPredicate, error) {
+	token := p.peek()
+
+	if !left.isText || !strings.HasPrefix(token, `"`) {
+		return nil, p.errorf("=~ matches a string with a string pattern")
+	}
+
+	p.position++
+	pattern, err := strconv.Unquote(token)
+
+	if err != nil {
+		return nil, p.errorf("invalid string %s", token)
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, p.errorf("invalid pattern %s", token)
+	}
+
+	return func(i int) bool {
+		matched, _ := path.Match(pattern, left.eval(i).text)
+		return matched != negate
+	}, nil
+}
+
+// Parse an attribute, a number (optionally negated) or a quoted string.
+func (p *selectParser) parseOperand() (selectOperand, error) {
+	token := p.peek()
+	p.position++
+
+	switch {
+	case token == "":
+		return selectOperand{}, p.errorf("unexpected end of query")
+	case token == "-":
+		operand, err := p.parseOperand()
+
+		if err != nil || operand.isText {
+			return selectOperand{}, p.errorf("- negates a number")
+		}
+
+		return selectOperand{eval: func(i int) selectValue {
+			return selectValue{number: -operand.eval(i).number}
+		}}, nil
+	case strings.HasPrefix(token, `"`):
+		text, err := strconv.Unquote(token)
+
+		if err != nil {
+			return selectOperand{}, p.errorf("invalid string %s", token)
+		}
+
+		return selectOperand{isText: true, eval: func(int) selectValue {
+			return selectValue{text: text}
+		}}, nil
+	}
+
+	if operand, ok := p.attributes[token]; ok {
+		return operand, nil
+	}
+
+	if number, err := strconv.ParseFloat(token, 64); err == nil {
+		return selectOperand{eval: func(int) selectValue {
+			return selectValue{number: number}
+		}}, nil
+	}
+
+	return selectOperand{}, p.errorf("unknown attribute %q", token)
+}
@@ -0,0 +1,84 @@
+package halfedge
+
+import (
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Faces classified against a solid shape (e.g. a refinement box). The face
+// indices of each classification are sorted.
+type ShapeSelection struct {
+	// Faces with all of their vertices in the shape (including its boundary).
+	Inside []int
+
+	// Faces crossing the boundary of the shape.
+	Intersecting []int
+
+	// Faces not touching the shape.
+	Outside []int
+}
+
+// Classify the faces as inside, intersecting, or outside of an AABB.
+func (m *HalfEdgeMesh) SelectFacesByAABB(aabb meshx.AABB) ShapeSelection {
+	return m.selectFacesByShape(aabb, func(point meshx.Vector) bool {
+		return aabb.DistanceTo(point) == 0
+	})
+}
+
+// Classify the faces as inside, intersecting, or outside of a sphere.
+func (m *HalfEdgeMesh) SelectFacesBySphere(sphere meshx.Sphere) ShapeSelection {
+	return m.selectFacesByShape(sphere, func(point meshx.Vector) bool {
+		return sphere.DistanceTo(point) == 0
+	})
+}
+
+// Classify the faces as inside, intersecting, or outside of a convex
+// polytope (e.g. a view frustum).
+func (m *HalfEdgeMesh) SelectFacesByPolytope(polytope meshx.Polytope) ShapeSelection {
+	return m.selectFacesByShape(polytope, polytope.ContainsPoint)
+}
+
+// Classify the faces against a convex shape. The faces touching the shape
+// are queried from an octree and are inside if it contains all of their
+// vertices, since the shape is convex.
+func (m *HalfEdgeMesh) selectFacesByShape(query meshx.IntersectsAABB, contains func(meshx.Vector) bool) ShapeSelection {
+	var selection ShapeSelection
+
+	if m.GetNumberOfFaces() == 0 {
+		return selection
+	}
+
+	touching := m.BuildOctree().Query(query)
+	slices.Sort(touching)
+
+	isTouching := make([]bool, m.GetNumberOfFaces())
+	vertices := make([]int, 0, 4)
+
+	for _, face := range touching {
+		isTouching[face] = true
+		inside := true
+		vertices = m.GetFaceVerticesInto(face, vertices)
+
+		for _, vertex := range vertices {
+			if !contains(m.vertices[vertex].Point) {
+				inside = false
+				break
+			}
+		}
+
+		if inside {
+			selection.Inside = append(selection.Inside, face)
+		} else {
+			selection.Intersecting = append(selection.Intersecting, face)
+		}
+	}
+
+	for face, ok := range isTouching {
+		if !ok {
+			selection.Outside = append(selection.Outside, face)
+		}
+	}
+
+	return selection
+}
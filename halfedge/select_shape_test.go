@@ -0,0 +1,56 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Check a selection covers each face of the mesh once.
+func assertShapeSelection(t *testing.T, mesh *HalfEdgeMesh, selection ShapeSelection, inside, intersecting []int) {
+	assert.Equal(t, inside, selection.Inside)
+	assert.Equal(t, intersecting, selection.Intersecting)
+	assert.Len(t, selection.Outside, mesh.GetNumberOfFaces()-len(inside)-len(intersecting))
+
+	for _, face := range selection.Outside {
+		assert.NotContains(t, inside, face)
+		assert.NotContains(t, intersecting, face)
+	}
+}
+
+// Test selecting the faces by an AABB.
+func TestSelectFacesByAABB(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 4)
+	aabb := meshx.NewAABBFromBounds(meshx.NewVector(-0.5, -0.5, -1), meshx.NewVector(1.5, 1.5, 1))
+
+	selection := mesh.SelectFacesByAABB(aabb)
+	assertShapeSelection(t, mesh, selection, []int{0, 1}, []int{2, 3, 8, 9, 10, 11})
+}
+
+// Test selecting the faces by a sphere.
+func TestSelectFacesBySphere(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 4)
+	sphere := meshx.NewSphere(meshx.NewVector(0, 0, 0), 1.5)
+
+	selection := mesh.SelectFacesBySphere(sphere)
+	assertShapeSelection(t, mesh, selection, []int{0, 1}, []int{2, 3, 8, 9, 10, 11})
+
+	// The faces beyond the sphere are outside.
+	selection = mesh.SelectFacesBySphere(meshx.NewSphere(meshx.NewVector(2, 2, 3), 1))
+	assertShapeSelection(t, mesh, selection, nil, nil)
+}
+
+// Test selecting the faces by a polytope. The faces touching its boundary
+// intersect it.
+func TestSelectFacesByPolytope(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 4)
+	polytope := meshx.NewPolytope(meshx.NewHalfSpace(meshx.NewVector(1, 1, 0), meshx.NewVector(1, 1, 0)))
+
+	selection := mesh.SelectFacesByPolytope(polytope)
+	assertShapeSelection(t, mesh, selection, []int{0, 1}, []int{2, 3, 4, 5, 8, 9, 10, 11, 16, 17})
+
+	// The polytope of an AABB selects the same faces as the AABB.
+	aabb := meshx.NewAABBFromBounds(meshx.NewVector(0.5, 0.5, -1), meshx.NewVector(3, 3, 1))
+	assert.Equal(t, mesh.SelectFacesByAABB(aabb), mesh.SelectFacesByPolytope(meshx.NewPolytopeFromAABB(aabb)))
+}
@@ -0,0 +1,81 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test selecting faces by their attributes.
+func TestSelectFaces(t *testing.T) {
+	mesh := newTestCube(t)
+	mesh.AssignPatch("body", []int{0, 1, 4, 5})
+	mesh.AssignPatch("wheel_front", []int{2})
+	mesh.AssignPatch("wheel_rear", []int{3})
+
+	for query, expected := range map[string][]int{
+		"normal.z > 0.9": {1},
+		`normal.z > 0.9 && area < 1e-4 || patch =~ "wheel_*"`:                    {2, 3},
+		`normal.z > 0.9 && !(area < 1e-4) || patch =~ "wheel_*"`:                 {1, 2, 3},
+		`patch != "body" && centroid.z >= 0.5 && vertices == 4 && !boundary`:     {2, 3},
+		`(normal.x < -0.5 || normal.x > 0.5) && material == "" && baffle == 0`:   {4, 5},
+		`patch !~ "*" || index == -1 || centroid.y < -.5e-3 && normal.y > -1e+3`: {},
+	} {
+		faces, err := mesh.SelectFaces(query)
+		assert.Empty(t, err, query)
+		assert.Equal(t, expected, faces, query)
+	}
+}
+
+// Test selecting vertices by their attributes.
+func TestSelectVertices(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+
+	vertices, err := mesh.SelectVertices("x <= 0.5 && point.y > 0.5")
+	assert.Empty(t, err)
+	assert.Equal(t, []int{3, 6}, vertices)
+
+	vertices, err = mesh.SelectVertices("!boundary")
+	assert.Empty(t, err)
+	assert.Equal(t, []int{4}, vertices)
+
+	vertices, err = mesh.SelectVertices("valence == 6 && normal.z > 0.99")
+	assert.Empty(t, err)
+	assert.Equal(t, []int{4}, vertices)
+}
+
+// Test invalid selection queries.
+func TestSelectInvalid(t *testing.T) {
+	mesh := newTestCube(t)
+
+	for _, query := range []string{
+		"",
+		"area >",
+		"color > 1",
+		`patch > "a"`,
+		`area == "a"`,
+		`area =~ "a"`,
+		`patch =~ "["`,
+		`patch == "a`,
+		"(area > 1",
+		"area > 1)",
+		"area @ 1",
+	} {
+		_, err := mesh.SelectFaces(query)
+		assert.ErrorIs(t, err, ErrInvalidQuery, query)
+	}
+}
+
+// Test assigning a selection to a patch.
+func TestAssignPatch(t *testing.T) {
+	mesh := newTestCube(t)
+
+	faces, err := mesh.SelectFaces("normal.z < -0.5 || normal.z > 0.5")
+	assert.Empty(t, err)
+
+	patch := mesh.AssignPatch("caps", faces)
+	assert.Equal(t, 0, patch)
+	assert.Equal(t, faces, mesh.GetPatchFaces(patch))
+	assert.Equal(t, patch, mesh.AssignPatch("caps", []int{2}))
+	assert.Len(t, mesh.GetPatchFaces(patch), 3)
+}
@@ -0,0 +1,271 @@
+package halfedge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Extension of the named sets sidecar of a mesh file (appended to its path)
+// for formats that cannot store the sets.
+const SetsExtension = ".sets.json"
+
+var (
+	ErrInvalidSet = errors.New("invalid set")
+)
+
+// Names of the kinds of sets in the sidecar.
+var setKindNames = map[meshx.SetKind]string{
+	meshx.FaceSet:   "faces",
+	meshx.VertexSet: "vertices",
+}
+
+// Named sets sidecar in JSON.
+type setsFile struct {
+	Sets []setsEntry `json:"sets"`
+}
+
+// Set of the named sets sidecar.
+type setsEntry struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Indices []int  `json:"indices"`
+}
+
+// Add a named set of faces or vertices (e.g. a selection to reuse later),
+// replacing the set of the same name and kind. The indices are kept sorted
+// without duplicates and follow the elements as faces and vertices are
+// deleted. An error is returned if an index does not exist.
+func (m *HalfEdgeMesh) AddSet(name string, kind meshx.SetKind, indices []int) error {
+	count := m.getSetCount(kind)
+
+	if count < 0 {
+		return fmt.Errorf("%w: unknown kind %d", ErrInvalidSet, kind)
+	}
+
+	for _, index := range indices {
+		if index < 0 || index >= count {
+			return fmt.Errorf("%w: %s: index %d does not exist", ErrInvalidSet, name, index)
+		}
+	}
+
+	indices = slices.Clone(indices)
+	slices.Sort(indices)
+	set := meshx.Set{Name: name, Kind: kind, Indices: slices.Compact(indices)}
+
+	if i := m.getSetIndex(name, kind); i >= 0 {
+		m.sets[i] = set
+	} else {
+		m.sets = append(m.sets, set)
+	}
+
+	return nil
+}
+
+// Get a named set of faces or vertices.
+func (m *HalfEdgeMesh) GetSet(name string, kind meshx.SetKind) (meshx.Set, bool) {
+	if i := m.getSetIndex(name, kind); i >= 0 {
+		set := m.sets[i]
+		set.Indices = slices.Clone(set.Indices)
+		return set, true
+	}
+
+	return meshx.Set{}, false
+}
+
+// Get the named sets in the order added. This implements the SetReader
+// interface.
+func (m *HalfEdgeMesh) GetSets() []meshx.Set {
+	sets := make([]meshx.Set, len(m.sets))
+
+	for i, set := range m.sets {
+		sets[i] = set
+		sets[i].Indices = slices.Clone(set.Indices)
+	}
+
+	return sets
+}
+
+// Remove a named set of faces or vertices if it exists.
+func (m *HalfEdgeMesh) RemoveSet(name string, kind meshx.SetKind) {
+	if i := m.getSetIndex(name, kind); i >= 0 {
+		m.sets = slices.Delete(m.sets, i, i+1)
+	}
+}
+
+// Get the index of a named set or -1 if it does not exist.
+func (m *HalfEdgeMesh) getSetIndex(name string, kind meshx.SetKind) int {
+	return slices.IndexFunc(m.sets, func(set meshx.Set) bool {
+		return set.Name == name && set.Kind == kind
+	})
+}
+
+// Get the number of elements of a kind of set or -1 if unknown.
+func (m *HalfEdgeMesh) getSetCount(kind meshx.SetKind) int {
+	switch kind {
+	case meshx.FaceSet:
+		return len(m.faces)
+	case meshx.VertexSet:
+		return len(m.vertices)
+	default:
+		return -1
+	}
+}
+
+// Update the indices of the sets on a mutation. A deleted element is removed
+// from the sets and the last element replacing it renumbered. The indices
+// that no longer exist are dropped when the whole mesh changed.
+func (m *HalfEdgeMesh) updateSets(event MeshEvent, indices []int) {
+	var kind meshx.SetKind
+
+	switch event {
+	case FacesDeleted:
+		kind = meshx.FaceSet
+	case VerticesDeleted:
+		kind = meshx.VertexSet
+	case MeshReset:
+		for i, set := range m.sets {
+			count := m.getSetCount(set.Kind)
+			m.sets[i].Indices = slices.DeleteFunc(set.Indices, func(index int) bool {
+				return index >= count
+			})
+		}
+		return
+	default:
+		return
+	}
+
+	count := m.getSetCount(kind)
+
+	for i, set := range m.sets {
+		if set.Kind != kind {
+			continue
+		}
+
+		members := make(map[int]bool, len(set.Indices))
+
+		for _, index := range set.Indices {
+			members[index] = true
+		}
+
+		// The indices are removed in descending order, each replaced by the
+		// last element before its removal.
+		for k, index := range indices {
+			last := count + len(indices) - k - 1
+			isMember := members[last]
+			delete(members, index)
+			delete(members, last)
+
+			if isMember && index != last {
+				members[index] = true
+			}
+		}
+
+		remapped := make([]int, 0, len(members))
+
+		for index := range members {
+			remapped = append(remapped, index)
+		}
+
+		slices.Sort(remapped)
+		m.sets[i].Indices = remapped
+	}
+}
+
+// Copy the sets of a SetReader keeping the indices that exist.
+func (m *HalfEdgeMesh) readSets(source meshx.SetReader) {
+	for _, set := range source.GetSets() {
+		count := m.getSetCount(set.Kind)
+
+		if count < 0 {
+			continue
+		}
+
+		indices := slices.DeleteFunc(slices.Clone(set.Indices), func(index int) bool {
+			return index < 0 || index >= count
+		})
+
+		m.AddSet(set.Name, set.Kind, indices)
+	}
+}
+
+// Write the named sets as a JSON sidecar.
+func (m *HalfEdgeMesh) WriteSets(writer io.Writer) error {
+	file := setsFile{Sets: make([]setsEntry, len(m.sets))}
+
+	for i, set := range m.sets {
+		file.Sets[i] = setsEntry{set.Name, setKindNames[set.Kind], set.Indices}
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// Write the named sets as a JSON sidecar file path.
+func (m *HalfEdgeMesh) WriteSetsToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.WriteSets(file)
+}
+
+// Read the named sets from a JSON sidecar written by WriteSets, replacing
+// the sets of the same names. An error is returned if a set has an unknown
+// kind or an index that does not exist, in which case no set is added.
+func (m *HalfEdgeMesh) ReadSets(reader io.Reader) error {
+	var file setsFile
+
+	if err := json.NewDecoder(reader).Decode(&file); err != nil {
+		return err
+	}
+
+	sets := make([]meshx.Set, len(file.Sets))
+
+	for i, entry := range file.Sets {
+		sets[i] = meshx.Set{Name: entry.Name, Kind: -1, Indices: entry.Indices}
+
+		for kind, name := range setKindNames {
+			if entry.Kind == name {
+				sets[i].Kind = kind
+			}
+		}
+
+		if sets[i].Kind < 0 {
+			return fmt.Errorf("%w: %s: unknown kind %q", ErrInvalidSet, entry.Name, entry.Kind)
+		}
+
+		count := m.getSetCount(sets[i].Kind)
+
+		for _, index := range entry.Indices {
+			if index < 0 || index >= count {
+				return fmt.Errorf("%w: %s: index %d does not exist", ErrInvalidSet, entry.Name, index)
+			}
+		}
+	}
+
+	for _, set := range sets {
+		m.AddSet(set.Name, set.Kind, set.Indices)
+	}
+
+	return nil
+}
+
+// Read the named sets from a JSON sidecar file path.
+func (m *HalfEdgeMesh) ReadSetsFromPath(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.ReadSets(file)
+}
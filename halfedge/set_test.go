@@ -0,0 +1,103 @@
+package halfedge
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test adding, getting and removing named sets.
+func TestSets(t *testing.T) {
+	mesh := newTestCube(t)
+
+	assert.Empty(t, mesh.AddSet("zone", meshx.FaceSet, []int{4, 1, 1}))
+	assert.Empty(t, mesh.AddSet("zone", meshx.VertexSet, []int{7}))
+	assert.ErrorIs(t, mesh.AddSet("invalid", meshx.FaceSet, []int{6}), ErrInvalidSet)
+	assert.ErrorIs(t, mesh.AddSet("invalid", meshx.SetKind(2), nil), ErrInvalidSet)
+
+	set, ok := mesh.GetSet("zone", meshx.FaceSet)
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 4}, set.Indices)
+	assert.Len(t, mesh.GetSets(), 2)
+
+	// Replacing a set keeps its order.
+	assert.Empty(t, mesh.AddSet("zone", meshx.FaceSet, []int{0}))
+	assert.Equal(t, meshx.FaceSet, mesh.GetSets()[0].Kind)
+	assert.Equal(t, []int{0}, mesh.GetSets()[0].Indices)
+
+	mesh.RemoveSet("zone", meshx.FaceSet)
+	_, ok = mesh.GetSet("zone", meshx.FaceSet)
+	assert.False(t, ok)
+	assert.Len(t, mesh.GetSets(), 1)
+}
+
+// Test the sets follow the faces and vertices as they are deleted.
+func TestSetsDeleteFace(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 1)
+
+	assert.Empty(t, mesh.AddSet("faces", meshx.FaceSet, []int{0, 1}))
+	assert.Empty(t, mesh.AddSet("vertices", meshx.VertexSet, []int{0, 1, 3}))
+	assert.Empty(t, mesh.DeleteFace(0))
+
+	// The last face and vertex replace the deleted face and vertex 1.
+	set, _ := mesh.GetSet("faces", meshx.FaceSet)
+	assert.Equal(t, []int{0}, set.Indices)
+
+	set, _ = mesh.GetSet("vertices", meshx.VertexSet)
+	assert.Equal(t, []int{0, 1}, set.Indices)
+	assert.Equal(t, meshx.NewVector(1, 1, 0), mesh.GetVertex(1).Point)
+}
+
+// Test the sets of extracted and merged meshes.
+func TestSetsExtractMerge(t *testing.T) {
+	mesh := newTestCube(t)
+	assert.Empty(t, mesh.AddSet("zone", meshx.FaceSet, []int{1, 3}))
+
+	extracted := mesh.Extract([]int{3, 4})
+	set, ok := extracted.GetSet("zone", meshx.FaceSet)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0}, set.Indices)
+
+	mesh.Merge(extracted)
+	set, _ = mesh.GetSet("zone", meshx.FaceSet)
+	assert.Equal(t, []int{1, 3, 6}, set.Indices)
+
+	// The sets are kept through a rebuild.
+	rebuilt, err := NewHalfEdgeMesh(mesh.GetMeshReader())
+	assert.Empty(t, err)
+	assert.Equal(t, mesh.GetSets(), rebuilt.GetSets())
+}
+
+// Test writing and reading the sets as a JSON sidecar.
+func TestWriteReadSets(t *testing.T) {
+	mesh := newTestCube(t)
+	assert.Empty(t, mesh.AddSet("refinement_zone_A", meshx.FaceSet, []int{2, 5}))
+	assert.Empty(t, mesh.AddSet("probes", meshx.VertexSet, []int{0}))
+
+	path := filepath.Join(t.TempDir(), "cube.obj"+SetsExtension)
+	assert.Empty(t, mesh.WriteSetsToPath(path))
+
+	other := newTestCube(t)
+	assert.Empty(t, other.ReadSetsFromPath(path))
+	assert.Equal(t, mesh.GetSets(), other.GetSets())
+
+	data := `{"sets": [{"name": "a", "kind": "edges", "indices": []}]}`
+	assert.ErrorIs(t, other.ReadSets(bytes.NewBufferString(data)), ErrInvalidSet)
+
+	data = `{"sets": [{"name": "a", "kind": "faces", "indices": [6]}]}`
+	assert.ErrorIs(t, other.ReadSets(bytes.NewBufferString(data)), ErrInvalidSet)
+	assert.Len(t, other.GetSets(), 2)
+}
+
+// Test the sets follow the faces kept when the mesh is replaced.
+func TestSetsReplace(t *testing.T) {
+	mesh := newTestNestedCubes(t)
+	assert.Empty(t, mesh.AddSet("zone", meshx.FaceSet, []int{5, 7}))
+	assert.Equal(t, 1, mesh.RemoveInternalComponents())
+
+	set, _ := mesh.GetSet("zone", meshx.FaceSet)
+	assert.Equal(t, []int{5}, set.Indices)
+}
@@ -0,0 +1,365 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/curve"
+)
+
+// Default number of voxels along the longest side of the AABB.
+const DefaultSkeletonResolution = 64
+
+// Options to compute the skeleton of a closed mesh.
+type SkeletonOptions struct {
+	// Edge length of the voxels. The default is the longest side of the
+	// AABB divided by DefaultSkeletonResolution.
+	VoxelSize float64
+
+	// Remove the branches from an end to a junction shorter than the
+	// length. Zero keeps all branches.
+	MinBranchLength float64
+
+	// Maximum number of voxels. ErrTooManyVoxels is returned if the voxel
+	// size is too small for the mesh. The default is DefaultMaxVoxels.
+	MaxVoxels int
+}
+
+// Compute the curve skeleton (medial axis approximation) of a closed mesh.
+// See ComputeSkeletonWithOptions.
+func (m *HalfEdgeMesh) ComputeSkeleton() (*curve.CurveNetwork, error) {
+	return m.ComputeSkeletonWithOptions(SkeletonOptions{})
+}
+
+// Compute the curve skeleton (medial axis approximation) of a closed mesh by
+// voxel thinning. The voxels with centers inside the mesh are thinned from
+// the boundary inward while preserving the topology and the curve ends. The
+// vertices of the network are the centers of the remaining voxels, chained
+// into polylines that break at the ends and junctions. A part of the mesh
+// thinner than a voxel may be lost, and a part without holes or branches may
+// thin to a single voxel without edges.
+func (m *HalfEdgeMesh) ComputeSkeletonWithOptions(options SkeletonOptions) (*curve.CurveNetwork, error) {
+	network, _ := curve.NewCurveNetwork(nil, nil)
+
+	if m.GetNumberOfFaces() == 0 {
+		return network, nil
+	}
+
+	aabb := m.GetAABB()
+	size := options.VoxelSize
+
+	if size <= 0 {
+		size = 2 * max(aabb.HalfSize[0], aabb.HalfSize[1], aabb.HalfSize[2]) / DefaultSkeletonResolution
+	}
+
+	if size <= 0 {
+		return network, nil
+	}
+
+	grid, err := newVoxelGrid(aabb, size, options.MaxVoxels)
+	if err != nil {
+		return nil, err
+	}
+
+	grid.voxelize(m)
+	grid.thin()
+
+	vertices, edges := grid.getGraph(options.MinBranchLength)
+	return curve.NewCurveNetworkFromEdges(vertices, edges)
+}
+
+// Occupy the voxels with centers inside (or on the surface of) the mesh.
+// The padding layer is left empty.
+func (g *voxelGrid) voxelize(m *HalfEdgeMesh) {
+	indices := make([]int, 0)
+	points := make([]meshx.Vector, 0)
+
+	for k := 1; k < g.shape[2]-1; k++ {
+		for j := 1; j < g.shape[1]-1; j++ {
+			for i := 1; i < g.shape[0]-1; i++ {
+				index := g.getIndex(i, j, k)
+				indices = append(indices, index)
+				points = append(points, g.getCenter(index))
+			}
+		}
+	}
+
+	for i, classification := range m.ClassifyPoints(points) {
+		g.occupied[indices[i]] = classification != ClassificationOutside
+	}
+}
+
+// Get the 3x3x3 neighborhood of a voxel indexed by (k+1)*9 + (j+1)*3 + i+1
+// for the offset (i, j, k). The center is excluded.
+func (g *voxelGrid) getNeighborhood(index int) [27]bool {
+	var neighborhood [27]bool
+
+	i, j, k := g.getCoordinates(index)
+
+	for _, offset := range voxelOffsets {
+		neighbor := g.getIndex(i+offset[0], j+offset[1], k+offset[2])
+		neighborhood[(offset[2]+1)*9+(offset[1]+1)*3+offset[0]+1] = g.occupied[neighbor]
+	}
+
+	return neighborhood
+}
+
+// Count the occupied neighbors of a voxel.
+func (g *voxelGrid) countNeighbors(index int) int {
+	var count int
+
+	for _, occupied := range g.getNeighborhood(index) {
+		if occupied {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Return true if removing the voxel preserves the topology (26-connected
+// voxels and a 6-connected background). The occupied neighbors must form
+// one 26-connected component and the empty 18-neighbors one 6-connected
+// component touching a face of the voxel.
+func (g *voxelGrid) isSimple(index int) bool {
+	neighborhood := g.getNeighborhood(index)
+
+	// Count the components of the cells selected by the filter, connected
+	// by the offsets, that contain a seed.
+	countComponents := func(filter func(int) bool, offsets [][3]int, isSeed func(int) bool) int {
+		var count int
+		var visited [27]bool
+
+		for start := range neighborhood {
+			if visited[start] || !filter(start) || !isSeed(start) {
+				continue
+			}
+
+			count++
+			visited[start] = true
+			stack := []int{start}
+
+			for len(stack) > 0 {
+				cell := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				i, j, k := cell%3, (cell/3)%3, cell/9
+
+				for _, offset := range offsets {
+					ni, nj, nk := i+offset[0], j+offset[1], k+offset[2]
+
+					if ni < 0 || ni > 2 || nj < 0 || nj > 2 || nk < 0 || nk > 2 {
+						continue
+					}
+
+					next := nk*9 + nj*3 + ni
+
+					if !visited[next] && filter(next) {
+						visited[next] = true
+						stack = append(stack, next)
+					}
+				}
+			}
+		}
+
+		return count
+	}
+
+	// Number of non-zero offsets of a cell from the center.
+	getOrder := func(cell int) int {
+		var order int
+
+		for _, c := range [3]int{cell % 3, (cell / 3) % 3, cell / 9} {
+			if c != 1 {
+				order++
+			}
+		}
+
+		return order
+	}
+
+	isObject := func(cell int) bool {
+		return cell != 13 && neighborhood[cell]
+	}
+
+	isAny := func(int) bool { return true }
+
+	if countComponents(isObject, voxelOffsets, isAny) != 1 {
+		return false
+	}
+
+	isBackground := func(cell int) bool {
+		order := getOrder(cell)
+		return order > 0 && order < 3 && !neighborhood[cell]
+	}
+
+	isFace := func(cell int) bool {
+		return getOrder(cell) == 1
+	}
+
+	faceOffsets := [][3]int{{-1, 0, 0}, {1, 0, 0}, {0, -1, 0}, {0, 1, 0}, {0, 0, -1}, {0, 0, 1}}
+	return countComponents(isBackground, faceOffsets, isFace) == 1
+}
+
+// Thin the occupied voxels to a curve skeleton. Each pass removes the simple
+// voxels exposed in one of the six axis directions (empty on that side and
+// occupied on the opposite side, so a layer one voxel thick is not removed
+// from either side), checking each voxel again before removal so that the
+// topology is preserved. Voxels with one neighbor (curve ends) are kept.
+func (g *voxelGrid) thin() {
+	directions := [][3]int{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, direction := range directions {
+			candidates := make([]int, 0)
+
+			for index, occupied := range g.occupied {
+				if !occupied {
+					continue
+				}
+
+				i, j, k := g.getCoordinates(index)
+				front := g.getIndex(i+direction[0], j+direction[1], k+direction[2])
+				back := g.getIndex(i-direction[0], j-direction[1], k-direction[2])
+
+				if g.occupied[front] || !g.occupied[back] {
+					continue
+				}
+
+				if g.countNeighbors(index) > 1 && g.isSimple(index) {
+					candidates = append(candidates, index)
+				}
+			}
+
+			for _, index := range candidates {
+				if g.countNeighbors(index) > 1 && g.isSimple(index) {
+					g.occupied[index] = false
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// Get the graph of the occupied voxels. Adjacent voxels are connected
+// unless a voxel adjacent to both is closer to each, so that the corners
+// of a staircase are not cut. Terminal branches shorter than the minimum
+// length are removed.
+func (g *voxelGrid) getGraph(minBranchLength float64) ([]meshx.Vector, [][2]int) {
+	nodes := make(map[int]int)
+	voxels := make([]int, 0)
+
+	for index, occupied := range g.occupied {
+		if occupied {
+			nodes[index] = len(voxels)
+			voxels = append(voxels, index)
+		}
+	}
+
+	getDistance := func(a, b [3]int) int {
+		var distance int
+
+		for i := range a {
+			distance += (a[i] - b[i]) * (a[i] - b[i])
+		}
+
+		return distance
+	}
+
+	adjacency := make([][]int, len(voxels))
+
+	for a, index := range voxels {
+		i, j, k := g.getCoordinates(index)
+		p := [3]int{i, j, k}
+
+		for _, offset := range voxelOffsets {
+			q := [3]int{i + offset[0], j + offset[1], k + offset[2]}
+			b, ok := nodes[g.getIndex(q[0], q[1], q[2])]
+
+			if !ok || b < a {
+				continue
+			}
+
+			distance := getDistance(p, q)
+			isShortcut := false
+
+			for _, other := range voxelOffsets {
+				r := [3]int{i + other[0], j + other[1], k + other[2]}
+
+				if r == q || getDistance(q, r) > 3 || !g.occupied[g.getIndex(r[0], r[1], r[2])] {
+					continue
+				}
+
+				if getDistance(p, r) < distance && getDistance(q, r) < distance {
+					isShortcut = true
+					break
+				}
+			}
+
+			if !isShortcut {
+				adjacency[a] = append(adjacency[a], b)
+				adjacency[b] = append(adjacency[b], a)
+			}
+		}
+	}
+
+	removed := make([]bool, len(voxels))
+
+	if minBranchLength > 0 {
+		for start := range voxels {
+			if len(adjacency[start]) != 1 {
+				continue
+			}
+
+			branch := []int{start}
+			prev, node := start, adjacency[start][0]
+			var length float64
+
+			for {
+				length += g.getCenter(voxels[node]).Sub(g.getCenter(voxels[prev])).Mag()
+
+				if len(adjacency[node]) != 2 {
+					break
+				}
+
+				branch = append(branch, node)
+				next := adjacency[node][0]
+
+				if next == prev {
+					next = adjacency[node][1]
+				}
+
+				prev, node = node, next
+			}
+
+			if len(adjacency[node]) > 2 && length < minBranchLength {
+				for _, node := range branch {
+					removed[node] = true
+				}
+			}
+		}
+	}
+
+	vertexMap := make([]int, len(voxels))
+	vertices := make([]meshx.Vector, 0)
+
+	for node, index := range voxels {
+		vertexMap[node] = -1
+
+		if !removed[node] {
+			vertexMap[node] = len(vertices)
+			vertices = append(vertices, g.getCenter(index))
+		}
+	}
+
+	edges := make([][2]int, 0)
+
+	for a := range adjacency {
+		for _, b := range adjacency[a] {
+			if a < b && !removed[a] && !removed[b] {
+				edges = append(edges, [2]int{vertexMap[a], vertexMap[b]})
+			}
+		}
+	}
+
+	return vertices, edges
+}
@@ -0,0 +1,79 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the skeleton of a cylinder is a curve along the axis.
+func TestComputeSkeleton(t *testing.T) {
+	profile := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(1, 0, 6),
+		meshx.NewVector(0, 0, 6),
+	}
+
+	mesh, err := NewRevolve(profile, meshx.Vector{}, meshx.NewVector(0, 0, 1), 32)
+	assert.Empty(t, err)
+
+	skeleton, err := mesh.ComputeSkeleton()
+	assert.Empty(t, err)
+	assert.Equal(t, 1, skeleton.GetNumberOfPolylines())
+	assert.Greater(t, skeleton.Length(), 3.0)
+	assert.Less(t, skeleton.Length(), 6.0)
+
+	for i := range skeleton.GetNumberOfVertices() {
+		point := skeleton.GetVertex(i)
+		assert.Less(t, meshx.NewVector(point[0], point[1], 0).Mag(), 0.2)
+	}
+}
+
+// Test the skeleton of a box thins to its center.
+func TestComputeSkeletonBox(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	skeleton, err := mesh.ComputeSkeletonWithOptions(SkeletonOptions{VoxelSize: 0.1})
+	assert.Empty(t, err)
+	assert.Greater(t, skeleton.GetNumberOfVertices(), 0)
+
+	for i := range skeleton.GetNumberOfVertices() {
+		assert.Less(t, skeleton.GetVertex(i).Mag(), 0.2)
+	}
+
+	_, err = mesh.ComputeSkeletonWithOptions(SkeletonOptions{VoxelSize: 1e-6})
+	assert.ErrorIs(t, err, ErrTooManyVoxels)
+
+	_, err = mesh.ComputeSkeletonWithOptions(SkeletonOptions{VoxelSize: 0.1, MaxVoxels: 100})
+	assert.ErrorIs(t, err, ErrTooManyVoxels)
+}
+
+// Test pruning a short branch from the graph of the voxels.
+func TestVoxelGridGetGraph(t *testing.T) {
+	aabb := meshx.NewAABB(meshx.Vector{}, meshx.NewVector(5, 5, 0.5))
+	grid, err := newVoxelGrid(aabb, 1, 0)
+	assert.Empty(t, err)
+
+	// Line along X with a diagonal step and a branch of two voxels.
+	for i := 1; i <= 10; i++ {
+		grid.occupied[grid.getIndex(i, 5+i/6, 1)] = true
+	}
+
+	grid.occupied[grid.getIndex(5, 4, 1)] = true
+	grid.occupied[grid.getIndex(5, 3, 1)] = true
+
+	vertices, edges := grid.getGraph(0)
+	assert.Equal(t, 12, len(vertices))
+	assert.Equal(t, 11, len(edges))
+
+	vertices, edges = grid.getGraph(3)
+	assert.Equal(t, 10, len(vertices))
+	assert.Equal(t, 9, len(edges))
+
+	vertices, edges = grid.getGraph(1.5)
+	assert.Equal(t, 12, len(vertices))
+	assert.Equal(t, 11, len(edges))
+}
@@ -0,0 +1,73 @@
+package halfedge
+
+import (
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/curve"
+)
+
+// Slice the mesh by the plane through the origin with the normal. The
+// contours are chained from the segments where the faces cross the plane.
+// Vertices on the plane are considered above it. For a closed mesh with
+// outward faces, the contours are closed polylines counterclockwise about
+// the normal around the material (and clockwise around holes).
+func (m *HalfEdgeMesh) Slice(origin, normal meshx.Vector) *curve.CurveNetwork {
+	distances := make([]float64, len(m.vertices))
+
+	for i, vertex := range m.vertices {
+		distances[i] = vertex.Point.Sub(origin).Dot(normal)
+	}
+
+	points := make([]meshx.Vector, 0)
+	edgePoints := make(map[[2]int]int)
+	edges := make([][2]int, 0)
+
+	// Get the index of the point where an edge crosses the plane. The point
+	// is interpolated from the lower vertex so both faces of the edge agree.
+	getPoint := func(a, b int) int {
+		key := [2]int{min(a, b), max(a, b)}
+
+		if index, ok := edgePoints[key]; ok {
+			return index
+		}
+
+		p, q := m.vertices[key[0]].Point, m.vertices[key[1]].Point
+		t := distances[key[0]] / (distances[key[0]] - distances[key[1]])
+		edgePoints[key] = len(points)
+		points = append(points, p.Add(q.Sub(p).MulScalar(t)))
+		return edgePoints[key]
+	}
+
+	for i := range m.GetNumberOfFaces() {
+		vertices := m.GetFaceVertices(i)
+		crossings := make([]int, 0, 2)
+
+		for j, a := range vertices {
+			b := vertices[(j+1)%len(vertices)]
+
+			if (distances[a] >= 0) != (distances[b] >= 0) {
+				crossings = append(crossings, getPoint(a, b))
+			}
+		}
+
+		if len(crossings) < 2 {
+			continue
+		}
+
+		// Pair the crossings along the line of the face in the plane. The
+		// segments are directed with the material to the left.
+		direction := normal.Cross(m.GetFaceNormal(i))
+
+		sort.Slice(crossings, func(a, b int) bool {
+			return points[crossings[a]].Dot(direction) < points[crossings[b]].Dot(direction)
+		})
+
+		for j := 0; j+1 < len(crossings); j += 2 {
+			edges = append(edges, [2]int{crossings[j], crossings[j+1]})
+		}
+	}
+
+	network, _ := curve.NewCurveNetworkFromEdges(points, edges)
+	return network
+}
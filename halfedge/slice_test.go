@@ -0,0 +1,48 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/planar"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test slicing a closed cylinder normal to and along its axis.
+func TestSlice(t *testing.T) {
+	profile := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(1, 0, 2),
+		meshx.NewVector(0, 0, 2),
+	}
+
+	mesh, err := NewRevolve(profile, meshx.Vector{}, meshx.NewVector(0, 0, 1), 16)
+	assert.Empty(t, err)
+
+	normal := meshx.NewVector(0, 0, 1)
+	contours := mesh.Slice(meshx.NewVector(0, 0, 0.5), normal)
+	assert.Equal(t, 1, contours.GetNumberOfPolylines())
+	assert.Equal(t, 16, contours.GetNumberOfVertices())
+
+	contour := contours.GetPolyline(0)
+	assert.True(t, contour.Closed)
+	assert.InDelta(t, 32*math.Sin(math.Pi/16), contour.Length(), 1e-12)
+
+	// Counterclockwise about the normal around the material.
+	area := planar.ComputeLoopNormal(contour.Points).Dot(normal) / 2
+	assert.InDelta(t, 8*math.Sin(math.Pi/8), area, 1e-12)
+
+	for _, point := range contour.Points {
+		assert.InDelta(t, 0.5, point[2], 1e-12)
+	}
+
+	contours = mesh.Slice(meshx.NewVector(0, 0, 1), meshx.NewVector(1, 0, 0))
+	assert.Equal(t, 1, contours.GetNumberOfPolylines())
+	assert.True(t, contours.GetPolyline(0).Closed)
+	assert.InDelta(t, 8, contours.Length(), 1e-12)
+
+	contours = mesh.Slice(meshx.NewVector(0, 0, 3), normal)
+	assert.Equal(t, 0, contours.GetNumberOfPolylines())
+}
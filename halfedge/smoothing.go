@@ -0,0 +1,48 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+)
+
+// Set the smoothing group of a face. A face of group zero is not smoothed.
+func (m *HalfEdgeMesh) SetFaceSmoothingGroup(index, group int) {
+	m.faces[index].SmoothingGroup = group
+}
+
+// Compute the normal of the corner of the face at the origin of each half
+// edge (indexed by half edge) from the smoothing groups. The corner normal
+// of a face of a nonzero group is the area-weighted normal of the faces of
+// the group sharing the vertex (as in DCC tools) and the face normal
+// otherwise.
+func (m *HalfEdgeMesh) ComputeCornerNormals() []meshx.Vector {
+	normals := make([]meshx.Vector, m.GetNumberOfHalfEdges())
+	faceNormals := make([]meshx.Vector, m.GetNumberOfFaces())
+	groupNormals := make(map[[2]int]meshx.Vector)
+
+	for i, face := range m.faces {
+		faceNormals[i] = m.GetFaceNormal(i)
+
+		if face.SmoothingGroup == 0 {
+			continue
+		}
+
+		normal := faceNormals[i].MulScalar(m.GetFaceArea(i))
+
+		for _, vertex := range m.GetFaceVertices(i) {
+			key := [2]int{vertex, face.SmoothingGroup}
+			groupNormals[key] = groupNormals[key].Add(normal)
+		}
+	}
+
+	for i, halfEdge := range m.halfEdges {
+		normals[i] = faceNormals[halfEdge.Face]
+
+		if group := m.faces[halfEdge.Face].SmoothingGroup; group != 0 {
+			if normal := groupNormals[[2]int{halfEdge.Origin, group}]; normal.Mag() > 0 {
+				normals[i] = normal.Unit()
+			}
+		}
+	}
+
+	return normals
+}
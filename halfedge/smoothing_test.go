@@ -0,0 +1,58 @@
+package halfedge
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeCornerNormals(t *testing.T) {
+	mesh := newTestCube(t)
+
+	for i, normal := range mesh.ComputeCornerNormals() {
+		assert.Equal(t, mesh.GetFaceNormal(mesh.GetHalfEdge(i).Face), normal)
+	}
+
+	// Smooth the top (z = 1) and side (x = 1) faces in one group and the
+	// back (y = 1) face in another.
+	mesh.SetFaceSmoothingGroup(1, 1)
+	mesh.SetFaceSmoothingGroup(5, 1)
+	mesh.SetFaceSmoothingGroup(3, 2)
+
+	normals := mesh.ComputeCornerNormals()
+	smooth := meshx.NewVector(1, 0, 1).Unit()
+
+	for i, normal := range normals {
+		halfEdge := mesh.GetHalfEdge(i)
+		expected := mesh.GetFaceNormal(halfEdge.Face)
+
+		if halfEdge.Face != 3 && mesh.GetFace(halfEdge.Face).SmoothingGroup == 1 && (halfEdge.Origin == 5 || halfEdge.Origin == 7) {
+			expected = smooth
+		}
+
+		assert.InDelta(t, 0, normal.Sub(expected).Mag(), 1e-12)
+	}
+}
+
+func TestSmoothingGroupsOBJ(t *testing.T) {
+	mesh := newTestCube(t)
+	mesh.SetFaceSmoothingGroup(1, 1)
+	mesh.SetFaceSmoothingGroup(5, 1)
+	mesh.SetFaceSmoothingGroup(3, 2)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, mesh.WriteOBJ(&buffer))
+
+	result, err := NewHalfEdgeMeshFromOBJ(&buffer)
+	assert.Empty(t, err)
+
+	for i, group := range []int{0, 1, 0, 2, 0, 1} {
+		assert.Equal(t, group, result.GetFace(i).SmoothingGroup)
+	}
+
+	extracted := result.Extract([]int{3, 5})
+	assert.Equal(t, 2, extracted.GetFace(0).SmoothingGroup)
+	assert.Equal(t, 1, extracted.GetFace(1).SmoothingGroup)
+}
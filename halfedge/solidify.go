@@ -0,0 +1,81 @@
+package halfedge
+
+import (
+	"errors"
+	"math"
+)
+
+// Maximum scale of the offset of a vertex to keep the thickness of the
+// faces about it (at creases).
+const SolidifyMaxScale = 2
+
+var (
+	ErrInvalidThickness = errors.New("invalid thickness")
+)
+
+// Thicken the surface into a closed shell (in place), e.g. to volume mesh a
+// thin part modeled as a surface. The opposite side is a copy of the faces
+// offset by the thickness against their normals (along them for a negative
+// thickness) with its orientation reversed, and the open boundaries are
+// closed by rim faces between the sides. The offset of each vertex follows
+// its area-weighted normal, scaled (up to SolidifyMaxScale) so that the
+// faces about it keep the thickness at creases. The new faces are assigned
+// to the patch of the face they are copied from or adjacent to. An error is
+// returned if the thickness is zero or the shell is non-manifold, in which
+// case the mesh is unchanged.
+func (m *HalfEdgeMesh) Solidify(thickness float64) error {
+	if thickness == 0 || math.IsNaN(thickness) || math.IsInf(thickness, 0) {
+		return ErrInvalidThickness
+	}
+
+	m.ensureAdjacency()
+
+	normals := m.getVertexNormals()
+	scales := make([]float64, m.GetNumberOfVertices())
+
+	for i := range scales {
+		scales[i] = 1
+	}
+
+	for i := range m.GetNumberOfFaces() {
+		normal := m.GetFaceNormal(i)
+
+		for _, vertex := range m.GetFaceVertices(i) {
+			if cos := normals[vertex].Dot(normal); cos > 0 {
+				scales[vertex] = max(scales[vertex], min(1/cos, SolidifyMaxScale))
+			}
+		}
+	}
+
+	source := newMeshSource(m)
+	offset := len(source.vertices)
+
+	for i, vertex := range m.vertices {
+		point := vertex.Point.Sub(normals[i].MulScalar(thickness * scales[i]))
+		source.vertices = append(source.vertices, point)
+		source.vertexColors = append(source.vertexColors, vertex.Color)
+	}
+
+	for i, face := range m.faces {
+		vertices := m.GetFaceVertices(i)
+		reversed := make([]int, len(vertices))
+
+		for j, vertex := range vertices {
+			reversed[len(vertices)-1-j] = vertex + offset
+		}
+
+		source.addFace(reversed, face)
+	}
+
+	for _, halfEdge := range m.halfEdges {
+		if halfEdge.IsBoundary() {
+			p := halfEdge.Origin
+			q := m.halfEdges[halfEdge.Next].Origin
+			source.addFace([]int{q, p, p + offset, q + offset}, m.faces[halfEdge.Face])
+		}
+	}
+
+	source.removeUnusedVertices()
+
+	return m.rebuild(source)
+}
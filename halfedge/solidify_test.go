@@ -0,0 +1,53 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test thickening an open surface into a closed shell.
+func TestSolidify(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 2)
+
+	assert.Empty(t, mesh.Solidify(0.1))
+	assert.Equal(t, 16+8, mesh.GetNumberOfFaces())
+	assert.Equal(t, 18, mesh.GetNumberOfVertices())
+	assert.True(t, mesh.IsClosed())
+	assert.True(t, mesh.IsConsistent())
+	assert.Empty(t, mesh.GetBoundaryLoops())
+
+	aabb := mesh.GetAABB()
+	assert.InDelta(t, 0.1, 2*aabb.HalfSize[2], 1e-12)
+	assert.InDelta(t, -0.05, aabb.Center[2], 1e-12)
+
+	// The opposite side faces away from the surface.
+	assert.InDelta(t, -1, mesh.GetFaceNormal(8).Dot(mesh.GetFaceNormal(0)), 1e-12)
+	assert.ErrorIs(t, mesh.Solidify(0), ErrInvalidThickness)
+}
+
+// Test the thickness is kept at a crease.
+func TestSolidifyCrease(t *testing.T) {
+	source := meshSource{
+		vertices: []meshx.Vector{
+			meshx.NewVector(0, 0, 0),
+			meshx.NewVector(0, 1, 0),
+			meshx.NewVector(1, 0, 0),
+			meshx.NewVector(1, 1, 0),
+			meshx.NewVector(1, 0, -1),
+			meshx.NewVector(1, 1, -1),
+		},
+	}
+
+	source.addFace([]int{0, 2, 3, 1}, Face{Patch: -1, Material: -1})
+	source.addFace([]int{2, 4, 5, 3}, Face{Patch: -1, Material: -1})
+
+	mesh, err := NewHalfEdgeMesh(&source)
+	assert.Empty(t, err)
+	assert.Empty(t, mesh.Solidify(-0.1))
+	assert.True(t, mesh.IsClosed())
+
+	// The crease vertex is offset to the corner of the offset faces.
+	assert.InDelta(t, 0, mesh.GetVertex(8).Point.Sub(meshx.NewVector(1.1, 0, 0.1)).Mag(), 1e-12)
+}
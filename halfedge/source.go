@@ -0,0 +1,260 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+)
+
+// In-memory implementation of the MeshReader interface used to construct
+// (or reconstruct) a HalfEdgeMesh from indexed faces.
+type meshSource struct {
+	vertices        []meshx.Vector
+	vertexColors    []meshx.Color
+	faces           [][]int
+	facePatches     []int
+	faceColors      []meshx.Color
+	patches         []string
+	baffles         []bool
+	patchMetadata   []PatchMetadata
+	sets            []meshx.Set
+	faceMaterials   []int
+	materials       []meshx.Material
+	smoothingGroups []int
+	hasVertexColors bool
+	hasFaceColors   bool
+}
+
+// Construct the mesh source of a HalfEdgeMesh.
+func newMeshSource(m *HalfEdgeMesh) *meshSource {
+	source := meshSource{
+		vertices:        make([]meshx.Vector, m.GetNumberOfVertices()),
+		vertexColors:    make([]meshx.Color, m.GetNumberOfVertices()),
+		faces:           make([][]int, m.GetNumberOfFaces()),
+		facePatches:     make([]int, m.GetNumberOfFaces()),
+		faceColors:      make([]meshx.Color, m.GetNumberOfFaces()),
+		patches:         make([]string, m.GetNumberOfPatches()),
+		baffles:         make([]bool, m.GetNumberOfPatches()),
+		patchMetadata:   make([]PatchMetadata, m.GetNumberOfPatches()),
+		sets:            m.GetSets(),
+		faceMaterials:   make([]int, m.GetNumberOfFaces()),
+		materials:       m.materials,
+		smoothingGroups: make([]int, m.GetNumberOfFaces()),
+		hasVertexColors: m.hasVertexColors,
+		hasFaceColors:   m.hasFaceColors,
+	}
+
+	for i, vertex := range m.vertices {
+		source.vertices[i] = vertex.Point
+		source.vertexColors[i] = vertex.Color
+	}
+
+	for i, face := range m.faces {
+		source.faces[i] = m.GetFaceVertices(i)
+		source.facePatches[i] = face.Patch
+		source.faceMaterials[i] = face.Material
+		source.faceColors[i] = face.Color
+		source.smoothingGroups[i] = face.SmoothingGroup
+	}
+
+	for i, patch := range m.patches {
+		source.patches[i] = patch.Name
+		source.baffles[i] = patch.IsBaffle
+		source.patchMetadata[i] = patch.Metadata
+	}
+
+	return &source
+}
+
+// Get a MeshReader of the indexed faces of the mesh (e.g. to write the mesh
+// with a MeshWriter). The reader also implements the MaterialReader,
+// ColorReader, SmoothingGroupReader, BaffleReader, PatchMetadataReader and
+// SetReader interfaces.
+func (m *HalfEdgeMesh) GetMeshReader() meshx.MeshReader {
+	return newMeshSource(m)
+}
+
+// Implement the MeshReader interface.
+func (s *meshSource) Read() error {
+	return nil
+}
+
+// Implement the MeshReader interface.
+func (s *meshSource) GetNumberOfVertices() int {
+	return len(s.vertices)
+}
+
+// Implement the MeshReader interface.
+func (s *meshSource) GetNumberOfFaces() int {
+	return len(s.faces)
+}
+
+// Implement the MeshReader interface.
+func (s *meshSource) GetNumberOfFaceEdges() int {
+	var count int
+
+	for _, face := range s.faces {
+		count += len(face)
+	}
+
+	return count
+}
+
+// Implement the MeshReader interface.
+func (s *meshSource) GetNumberOfPatches() int {
+	return len(s.patches)
+}
+
+// Implement the MeshReader interface.
+func (s *meshSource) GetVertex(index int) meshx.Vector {
+	return s.vertices[index]
+}
+
+// Implement the MeshReader interface.
+func (s *meshSource) GetFace(index int) []int {
+	return s.faces[index]
+}
+
+// Implement the MeshReader interface.
+func (s *meshSource) GetFacePatch(index int) int {
+	return s.facePatches[index]
+}
+
+// Implement the MeshReader interface.
+func (s *meshSource) GetPatch(index int) string {
+	return s.patches[index]
+}
+
+// Implement the MaterialReader interface.
+func (s *meshSource) GetNumberOfMaterials() int {
+	return len(s.materials)
+}
+
+// Implement the MaterialReader interface.
+func (s *meshSource) GetMaterial(index int) meshx.Material {
+	return s.materials[index]
+}
+
+// Implement the MaterialReader interface.
+func (s *meshSource) GetFaceMaterial(index int) int {
+	return s.faceMaterials[index]
+}
+
+// Implement the ColorReader interface.
+func (s *meshSource) HasVertexColors() bool {
+	return s.hasVertexColors
+}
+
+// Implement the ColorReader interface.
+func (s *meshSource) GetVertexColor(index int) meshx.Color {
+	return s.vertexColors[index]
+}
+
+// Implement the ColorReader interface.
+func (s *meshSource) HasFaceColors() bool {
+	return s.hasFaceColors
+}
+
+// Implement the ColorReader interface.
+func (s *meshSource) GetFaceColor(index int) meshx.Color {
+	return s.faceColors[index]
+}
+
+// Implement the SmoothingGroupReader interface.
+func (s *meshSource) GetFaceSmoothingGroup(index int) int {
+	return s.smoothingGroups[index]
+}
+
+// Implement the BaffleReader interface.
+func (s *meshSource) IsPatchBaffle(index int) bool {
+	return index < len(s.baffles) && s.baffles[index]
+}
+
+// Implement the PatchMetadataReader interface.
+func (s *meshSource) GetPatchMetadata(index int) PatchMetadata {
+	if index < len(s.patchMetadata) {
+		return s.patchMetadata[index]
+	}
+
+	return PatchMetadata{}
+}
+
+// Implement the SetReader interface.
+func (s *meshSource) GetSets() []meshx.Set {
+	return s.sets
+}
+
+// Add a face with the patch, material, color and smoothing group of an
+// existing face.
+func (s *meshSource) addFace(face []int, attributes Face) {
+	s.faces = append(s.faces, face)
+	s.facePatches = append(s.facePatches, attributes.Patch)
+	s.faceMaterials = append(s.faceMaterials, attributes.Material)
+	s.faceColors = append(s.faceColors, attributes.Color)
+	s.smoothingGroups = append(s.smoothingGroups, attributes.SmoothingGroup)
+}
+
+// Remap the vertices of each face, dropping repeated consecutive vertices
+// and faces that degenerate to fewer than three vertices. Vertices no longer
+// referenced by any face are removed.
+func (s *meshSource) remapVertices(vertexMap []int) {
+	faces := make([][]int, 0, len(s.faces))
+	facePatches := make([]int, 0, len(s.facePatches))
+	faceMaterials := make([]int, 0, len(s.faceMaterials))
+	faceColors := make([]meshx.Color, 0, len(s.faceColors))
+	smoothingGroups := make([]int, 0, len(s.smoothingGroups))
+
+	for i, face := range s.faces {
+		remapped := make([]int, 0, len(face))
+
+		for j := range face {
+			vertex := vertexMap[face[j]]
+			next := vertexMap[face[(j+1)%len(face)]]
+
+			if vertex != next {
+				remapped = append(remapped, vertex)
+			}
+		}
+
+		if len(remapped) >= 3 {
+			faces = append(faces, remapped)
+			facePatches = append(facePatches, s.facePatches[i])
+			faceMaterials = append(faceMaterials, s.faceMaterials[i])
+			faceColors = append(faceColors, s.faceColors[i])
+			smoothingGroups = append(smoothingGroups, s.smoothingGroups[i])
+		}
+	}
+
+	indexVertices := make(map[int]int)
+	vertices := make([]meshx.Vector, 0, len(s.vertices))
+	vertexColors := make([]meshx.Color, 0, len(s.vertexColors))
+
+	for _, face := range faces {
+		for j, vertex := range face {
+			if _, ok := indexVertices[vertex]; !ok {
+				indexVertices[vertex] = len(vertices)
+				vertices = append(vertices, s.vertices[vertex])
+				vertexColors = append(vertexColors, s.vertexColors[vertex])
+			}
+
+			face[j] = indexVertices[vertex]
+		}
+	}
+
+	s.vertices = vertices
+	s.vertexColors = vertexColors
+	s.faces = faces
+	s.facePatches = facePatches
+	s.faceMaterials = faceMaterials
+	s.faceColors = faceColors
+	s.smoothingGroups = smoothingGroups
+}
+
+// Remove the vertices not referenced by any face.
+func (s *meshSource) removeUnusedVertices() {
+	vertexMap := make([]int, len(s.vertices))
+
+	for i := range vertexMap {
+		vertexMap[i] = i
+	}
+
+	s.remapVertices(vertexMap)
+}
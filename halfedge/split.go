@@ -0,0 +1,237 @@
+package halfedge
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	// Default maximum angle (radians) between the normal of a face and the
+	// mean normal of its region when splitting a patch.
+	SplitPatchAngle = math.Pi / 6
+
+	// Maximum number of iterations of the k-means clustering of the normals.
+	SplitPatchMaxIterations = 100
+)
+
+// Options for splitting a patch by the normals of its faces. Zero values use
+// the defaults.
+type SplitPatchOptions struct {
+	// Maximum angle (radians) between the normal of a face and the
+	// area-weighted mean normal of the region grown to include it.
+	Angle float64
+
+	// Number of clusters of the normals (k-means) rather than regions grown
+	// by the angle. The faces of a cluster need not be connected.
+	Clusters int
+}
+
+// Split a patch (-1 for the faces without a patch) into regions of faces with
+// similar normals, e.g. to get regions to assign boundary conditions to for
+// a mesh imported without groups. The regions are grown from the largest
+// faces across the edges of the patch while the angle of each face to the
+// mean normal of the region is within the threshold, or the normals are
+// clustered by k-means if a number of clusters is given. The first region
+// keeps the patch and the others are added as patches named after it (with
+// its baffle tag and metadata). The patch of each region is returned.
+func (m *HalfEdgeMesh) SplitPatch(patch int, options SplitPatchOptions) []int {
+	if options.Angle <= 0 {
+		options.Angle = SplitPatchAngle
+	}
+
+	faces := m.GetPatchFaces(patch)
+
+	if len(faces) == 0 {
+		return []int{}
+	}
+
+	normals := make(map[int]meshx.Vector, len(faces))
+
+	for _, face := range faces {
+		if normal := m.GetFaceNormal(face); normal.Mag() > 0 {
+			normals[face] = normal.Unit().MulScalar(m.GetFaceArea(face))
+		} else {
+			normals[face] = meshx.Vector{}
+		}
+	}
+
+	var regions [][]int
+
+	if options.Clusters > 0 {
+		regions = clusterNormals(faces, normals, options.Clusters)
+	} else {
+		regions = m.growNormalRegions(faces, normals, math.Cos(options.Angle))
+	}
+
+	name, template := "patch", Patch{}
+
+	if patch >= 0 {
+		name, template = m.patches[patch].Name, m.patches[patch]
+	}
+
+	patches := make([]int, len(regions))
+	changed := make([]int, 0)
+
+	for i, region := range regions {
+		if i == 0 && patch >= 0 {
+			patches[i] = patch
+			continue
+		}
+
+		patches[i] = len(m.patches)
+		m.patches = append(m.patches, Patch{
+			Name:     fmt.Sprintf("%s_%d", name, i),
+			IsBaffle: template.IsBaffle,
+			Metadata: template.Metadata.Clone(),
+		})
+
+		for _, face := range region {
+			m.faces[face].Patch = patches[i]
+			changed = append(changed, face)
+		}
+	}
+
+	slices.Sort(changed)
+	m.notify(PatchesChanged, changed)
+
+	return patches
+}
+
+// Grow the regions of the faces (by their area-weighted normals) from the
+// largest faces across the edges between the faces while the cosine of the
+// angle to the mean normal of the region is above the threshold.
+func (m *HalfEdgeMesh) growNormalRegions(faces []int, normals map[int]meshx.Vector, threshold float64) [][]int {
+	m.ensureAdjacency()
+
+	seeds := slices.Clone(faces)
+
+	slices.SortStableFunc(seeds, func(a, b int) int {
+		return cmp.Compare(normals[b].Mag(), normals[a].Mag())
+	})
+
+	regions := make([][]int, 0)
+	visited := make(map[int]bool, len(faces))
+
+	for _, seed := range seeds {
+		if visited[seed] {
+			continue
+		}
+
+		region := []int{seed}
+		normal := normals[seed]
+		visited[seed] = true
+
+		for k := 0; k < len(region); k++ {
+			for _, neighbor := range m.GetFaceNeighbors(region[k]) {
+				candidate, ok := normals[neighbor]
+
+				if !ok || visited[neighbor] {
+					continue
+				}
+
+				if candidate.Mag() > 0 && normal.Mag() > 0 && candidate.Unit().Dot(normal.Unit()) < threshold {
+					continue
+				}
+
+				visited[neighbor] = true
+				region = append(region, neighbor)
+				normal = normal.Add(candidate)
+			}
+		}
+
+		slices.Sort(region)
+		regions = append(regions, region)
+	}
+
+	return regions
+}
+
+// Cluster the faces by their (area-weighted) normals with spherical k-means
+// seeded by the farthest normals from the largest face. Empty clusters are
+// dropped.
+func clusterNormals(faces []int, normals map[int]meshx.Vector, k int) [][]int {
+	units := make([]meshx.Vector, len(faces))
+	largest := 0
+
+	for i, face := range faces {
+		if normals[face].Mag() > 0 {
+			units[i] = normals[face].Unit()
+		}
+
+		if normals[face].Mag() > normals[faces[largest]].Mag() {
+			largest = i
+		}
+	}
+
+	centers := []meshx.Vector{units[largest]}
+	distances := make([]float64, len(faces))
+
+	for i := range distances {
+		distances[i] = math.Inf(1)
+	}
+
+	for len(centers) < min(k, len(faces)) {
+		farthest := 0
+
+		for i, unit := range units {
+			distances[i] = min(distances[i], unit.Sub(centers[len(centers)-1]).Mag())
+
+			if distances[i] > distances[farthest] {
+				farthest = i
+			}
+		}
+
+		centers = append(centers, units[farthest])
+	}
+
+	assignments := make([]int, len(faces))
+
+	for iteration := range SplitPatchMaxIterations {
+		changed := iteration == 0
+
+		for i, unit := range units {
+			best := 0
+
+			for j, center := range centers {
+				if unit.Dot(center) > unit.Dot(centers[best]) {
+					best = j
+				}
+			}
+
+			if best != assignments[i] {
+				assignments[i] = best
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+
+		sums := make([]meshx.Vector, len(centers))
+
+		for i, face := range faces {
+			sums[assignments[i]] = sums[assignments[i]].Add(normals[face])
+		}
+
+		for j, sum := range sums {
+			if sum.Mag() > 0 {
+				centers[j] = sum.Unit()
+			}
+		}
+	}
+
+	clusters := make([][]int, len(centers))
+
+	for i, face := range faces {
+		clusters[assignments[i]] = append(clusters[assignments[i]], face)
+	}
+
+	return slices.DeleteFunc(clusters, func(cluster []int) bool {
+		return len(cluster) == 0
+	})
+}
@@ -0,0 +1,75 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Assert the faces of each patch have the same normal.
+func assertSplitPatches(t *testing.T, mesh *HalfEdgeMesh, patches []int) {
+	for _, patch := range patches {
+		faces := mesh.GetPatchFaces(patch)
+		assert.NotEmpty(t, faces)
+
+		for _, face := range faces {
+			normal := mesh.GetFaceNormal(face).Unit()
+			assert.InDelta(t, 0, normal.Sub(mesh.GetFaceNormal(faces[0]).Unit()).Mag(), 1e-12)
+		}
+	}
+}
+
+// Test splitting a patch by growing regions of similar normals.
+func TestSplitPatch(t *testing.T) {
+	mesh := newTestCube(t)
+	patches := mesh.SplitPatch(-1, SplitPatchOptions{})
+	assert.Equal(t, 6, len(patches))
+	assert.Equal(t, 6, mesh.GetNumberOfPatches())
+	assert.Equal(t, "patch_0", mesh.GetPatch(patches[0]).Name)
+	assertSplitPatches(t, mesh, patches)
+
+	// A flat grid is a single region.
+	grid := newTestTriangleGrid(t, 4)
+	patch := grid.AddPatch("surface")
+
+	for i := range grid.GetNumberOfFaces() {
+		grid.SetFacePatch(i, patch)
+	}
+
+	assert.Equal(t, []int{patch}, grid.SplitPatch(patch, SplitPatchOptions{}))
+	assert.Empty(t, grid.SplitPatch(-1, SplitPatchOptions{}))
+
+	// The faces of a bent grid are split at the bend.
+	for i := range grid.GetNumberOfVertices() {
+		if point := grid.GetVertex(i).Point; point[0] > 2 {
+			grid.vertices[i].Point = meshx.NewVector(2, point[1], point[0]-2)
+		}
+	}
+
+	grid.SetPatchMetadata(patch, PatchMetadata{Type: "wall"})
+	patches = grid.SplitPatch(patch, SplitPatchOptions{})
+	assert.Equal(t, 2, len(patches))
+	assert.Equal(t, patch, patches[0])
+	assert.Equal(t, "surface_1", grid.GetPatch(patches[1]).Name)
+	assert.Equal(t, "wall", grid.GetPatch(patches[1]).Metadata.Type)
+	assert.Equal(t, 16, len(grid.GetPatchFaces(patches[0])))
+	assertSplitPatches(t, grid, patches)
+}
+
+// Test splitting a patch by clustering the normals.
+func TestSplitPatchClusters(t *testing.T) {
+	mesh := newTestCube(t)
+	patches := mesh.SplitPatch(-1, SplitPatchOptions{Clusters: 6})
+	assert.Equal(t, 6, len(patches))
+	assertSplitPatches(t, mesh, patches)
+
+	mesh = newTestCube(t)
+	patches = mesh.SplitPatch(-1, SplitPatchOptions{Clusters: 10})
+	assert.Equal(t, 6, len(patches))
+
+	mesh = newTestCube(t)
+	patches = mesh.SplitPatch(-1, SplitPatchOptions{Clusters: 2})
+	assert.Equal(t, 2, len(patches))
+	assert.Equal(t, 6, len(mesh.GetPatchFaces(patches[0]))+len(mesh.GetPatchFaces(patches[1])))
+}
@@ -0,0 +1,215 @@
+package halfedge
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Upper edges of the bins of the aspect ratio histogram of Stats.
+var statsAspectRatioEdges = []float64{1.5, 2, 3, 5, 10}
+
+// Upper edges of the bins of the minimum angle histogram of Stats (10 to
+// 80 degrees in radians).
+var statsMinAngleEdges = []float64{
+	math.Pi / 18,
+	2 * math.Pi / 18,
+	3 * math.Pi / 18,
+	4 * math.Pi / 18,
+	5 * math.Pi / 18,
+	6 * math.Pi / 18,
+	7 * math.Pi / 18,
+	8 * math.Pi / 18,
+}
+
+// Summary statistics of a HalfEdgeMesh serializable as JSON (e.g. to log or
+// diff the geometry between the steps of a pipeline).
+type Stats struct {
+	NumberOfVertices      int            `json:"numberOfVertices"`
+	NumberOfFaces         int            `json:"numberOfFaces"`
+	NumberOfEdges         int            `json:"numberOfEdges"`
+	NumberOfBoundaryEdges int            `json:"numberOfBoundaryEdges"`
+	NumberOfPatches       int            `json:"numberOfPatches"`
+	NumberOfMaterials     int            `json:"numberOfMaterials"`
+	MinBound              meshx.Vector   `json:"minBound"`
+	MaxBound              meshx.Vector   `json:"maxBound"`
+	Area                  float64        `json:"area"`
+	Volume                float64        `json:"volume"`
+	IsClosed              bool           `json:"isClosed"`
+	IsConsistent          bool           `json:"isConsistent"`
+	Components            []PartStats    `json:"components"`
+	Patches               []PartStats    `json:"patches"`
+	Quality               QualitySummary `json:"quality"`
+
+	// Faces with non-finite quality metrics (e.g. a zero length edge). They
+	// are excluded from the quality summary and histograms.
+	NumberOfDegenerateFaces int `json:"numberOfDegenerateFaces"`
+
+	AspectRatioHistogram Histogram `json:"aspectRatioHistogram"`
+	MinAngleHistogram    Histogram `json:"minAngleHistogram"`
+}
+
+// Statistics of a part (component or patch) of a HalfEdgeMesh.
+type PartStats struct {
+	Name          string  `json:"name,omitempty"`
+	NumberOfFaces int     `json:"numberOfFaces"`
+	Area          float64 `json:"area"`
+}
+
+// Histogram of values by the upper edges of the bins. Counts[i] is the
+// number of values below Edges[i] (and not below the previous edge) and the
+// last count is the number of values not below the last edge.
+type Histogram struct {
+	Edges  []float64 `json:"edges"`
+	Counts []int     `json:"counts"`
+}
+
+// Construct an empty Histogram with the upper edges of the bins.
+func NewHistogram(edges []float64) Histogram {
+	return Histogram{edges, make([]int, len(edges)+1)}
+}
+
+// Add a value to its bin.
+func (h *Histogram) Add(value float64) {
+	for i, edge := range h.Edges {
+		if value < edge {
+			h.Counts[i]++
+			return
+		}
+	}
+
+	h.Counts[len(h.Edges)]++
+}
+
+// Compute the summary statistics. The volume is enclosed by the faces if the
+// mesh is closed and zero otherwise (negative if oriented inward).
+func (m *HalfEdgeMesh) Stats() Stats {
+	stats := Stats{
+		NumberOfVertices:     m.GetNumberOfVertices(),
+		NumberOfFaces:        m.GetNumberOfFaces(),
+		NumberOfEdges:        m.GetNumberOfEdges(),
+		NumberOfPatches:      m.GetNumberOfPatches(),
+		NumberOfMaterials:    m.GetNumberOfMaterials(),
+		IsClosed:             m.IsClosed(),
+		IsConsistent:         m.IsConsistent(),
+		Components:           make([]PartStats, 0),
+		Patches:              make([]PartStats, m.GetNumberOfPatches()),
+		AspectRatioHistogram: NewHistogram(statsAspectRatioEdges),
+		MinAngleHistogram:    NewHistogram(statsMinAngleEdges),
+	}
+
+	for i := range stats.NumberOfEdges {
+		if m.edges[i].IsBoundary() {
+			stats.NumberOfBoundaryEdges++
+		}
+	}
+
+	if stats.NumberOfVertices > 0 {
+		aabb := m.GetAABB()
+		stats.MinBound = aabb.GetMinBound()
+		stats.MaxBound = aabb.GetMaxBound()
+	}
+
+	for i, patch := range m.patches {
+		stats.Patches[i].Name = patch.Name
+	}
+
+	areas := make([]float64, m.GetNumberOfFaces())
+	qualities := make([]FaceQuality, 0, m.GetNumberOfFaces())
+	sizes := make([]int, 0, m.GetNumberOfFaces())
+	buf := make([]int, 0, 4)
+
+	for i := range areas {
+		quality := m.ComputeFaceQuality(i)
+		areas[i] = quality.Area
+		stats.Area += quality.Area
+
+		if patch := m.faces[i].Patch; patch != -1 {
+			stats.Patches[patch].NumberOfFaces++
+			stats.Patches[patch].Area += quality.Area
+		}
+
+		if !isFiniteQuality(quality) {
+			stats.NumberOfDegenerateFaces++
+			continue
+		}
+
+		qualities = append(qualities, quality)
+		sizes = append(sizes, len(m.GetFaceVerticesInto(i, buf)))
+		stats.AspectRatioHistogram.Add(quality.AspectRatio)
+		stats.MinAngleHistogram.Add(quality.MinAngle)
+	}
+
+	if len(qualities) > 0 {
+		stats.Quality = summarizeQuality(qualities, sizes)
+	}
+
+	for _, component := range m.GetComponents() {
+		part := PartStats{NumberOfFaces: len(component)}
+
+		for _, face := range component {
+			part.Area += areas[face]
+		}
+
+		stats.Components = append(stats.Components, part)
+	}
+
+	if stats.IsClosed {
+		stats.Volume = m.getVolume()
+	}
+
+	return stats
+}
+
+// Check if the quality metrics of a face are finite.
+func isFiniteQuality(quality FaceQuality) bool {
+	values := []float64{
+		quality.Area,
+		quality.MinAngle,
+		quality.MaxAngle,
+		quality.AspectRatio,
+		quality.Warpage,
+		quality.Taper,
+		quality.Skew,
+	}
+
+	for _, value := range values {
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compute the signed volume enclosed by the faces by the divergence theorem.
+func (m *HalfEdgeMesh) getVolume() float64 {
+	var volume float64
+
+	for _, triangle := range m.GetTriangles() {
+		volume += triangle.P.Dot(triangle.Q.Cross(triangle.R)) / 6
+	}
+
+	return volume
+}
+
+// Write the Stats to a JSON writer (indented).
+func (s Stats) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// Write the Stats to a JSON file path.
+func (s Stats) WriteJSONToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return s.WriteJSON(file)
+}
@@ -0,0 +1,41 @@
+package halfedge
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	mesh := newTestCube(t)
+	stats := mesh.Stats()
+	assert.Equal(t, 8, stats.NumberOfVertices)
+	assert.Equal(t, 6, stats.NumberOfFaces)
+	assert.Equal(t, 12, stats.NumberOfEdges)
+	assert.Equal(t, 0, stats.NumberOfBoundaryEdges)
+	assert.Equal(t, meshx.NewVector(1, 1, 1), stats.MaxBound)
+	assert.InDelta(t, 6, stats.Area, 1e-12)
+	assert.InDelta(t, 1, stats.Volume, 1e-12)
+	assert.True(t, stats.IsClosed)
+	assert.Equal(t, []PartStats{{NumberOfFaces: 6, Area: 6}}, stats.Components)
+	assert.Equal(t, 6, stats.Quality.NumberOfQuads)
+	assert.Equal(t, []int{6, 0, 0, 0, 0, 0}, stats.AspectRatioHistogram.Counts)
+	assert.Equal(t, 6, stats.MinAngleHistogram.Counts[len(statsMinAngleEdges)])
+
+	var buffer bytes.Buffer
+	assert.Empty(t, stats.WriteJSON(&buffer))
+
+	var decoded Stats
+	assert.Empty(t, json.Unmarshal(buffer.Bytes(), &decoded))
+	assert.Equal(t, stats, decoded)
+
+	patches, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	stats = patches.Stats()
+	assert.Equal(t, patches.GetNumberOfPatches(), len(stats.Patches))
+	assert.Equal(t, "back", stats.Patches[1].Name)
+}
@@ -0,0 +1,86 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Stitch the open boundaries of the mesh (in place) by merging boundary
+// vertices within the tolerance of each other. This closes the seams left
+// by merging adjacent open meshes. An error is returned if the stitched mesh
+// is non-manifold, in which case the mesh is unchanged.
+func (m *HalfEdgeMesh) Stitch(tolerance float64) error {
+	m.ensureAdjacency()
+
+	boundary := m.getBoundaryVertices()
+
+	if len(boundary) == 0 {
+		return nil
+	}
+
+	vertexMap := make([]int, m.GetNumberOfVertices())
+
+	for i := range vertexMap {
+		vertexMap[i] = i
+	}
+
+	points := make([]meshx.Vector, len(boundary))
+
+	for i, vertex := range boundary {
+		points[i] = m.vertices[vertex].Point
+	}
+
+	halfSize := meshx.NewVector(tolerance, tolerance, tolerance)
+	aabb := meshx.NewAABBFromVectors(points)
+	cellSize := max(tolerance, 1e-9*aabb.HalfSize.Mag(), spatial.GetHashGridMinCellSize(aabb))
+
+	hashGrid, err := spatial.NewHashGrid(cellSize)
+
+	if err != nil {
+		return err
+	}
+
+	for _, point := range points {
+		hashGrid.InsertPoint(point)
+	}
+
+	for i, point := range points {
+		if vertexMap[boundary[i]] != boundary[i] {
+			continue
+		}
+
+		for _, j := range hashGrid.Query(meshx.NewAABB(point, halfSize)) {
+			if j > i && vertexMap[boundary[j]] == boundary[j] {
+				if points[j].Sub(point).Mag() <= tolerance {
+					vertexMap[boundary[j]] = boundary[i]
+				}
+			}
+		}
+	}
+
+	source := newMeshSource(m)
+	source.remapVertices(vertexMap)
+
+	return m.rebuild(source)
+}
+
+// Get the vertices on an open boundary.
+func (m *HalfEdgeMesh) getBoundaryVertices() []int {
+	visited := make(map[int]bool)
+	vertices := make([]int, 0)
+
+	for _, halfEdge := range m.halfEdges {
+		if halfEdge.IsBoundary() {
+			next := m.halfEdges[halfEdge.Next]
+
+			for _, vertex := range []int{halfEdge.Origin, next.Origin} {
+				if !visited[vertex] {
+					visited[vertex] = true
+					vertices = append(vertices, vertex)
+				}
+			}
+		}
+	}
+
+	return vertices
+}
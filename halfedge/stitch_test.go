@@ -0,0 +1,64 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test stitching the disconnected faces of a box.
+func TestStitch(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.obj")
+	assert.Empty(t, err)
+	assert.False(t, mesh.IsClosed())
+
+	err = mesh.Stitch(1e-6)
+	assert.Empty(t, err)
+	assert.True(t, mesh.IsClosed())
+	assert.Equal(t, 8, mesh.GetNumberOfVertices())
+	assert.Equal(t, 12, mesh.GetNumberOfFaces())
+	assert.Equal(t, 1, len(mesh.GetComponents()))
+}
+
+// Test stitching two merged halves of a box.
+func TestStitchMerged(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	a := mesh.ExtractPatches([]int{0, 1, 2})
+	b := mesh.ExtractPatches([]int{3, 4, 5})
+	a.Merge(b)
+	assert.False(t, a.IsClosed())
+	assert.Equal(t, 16, a.GetNumberOfVertices())
+
+	err = a.Stitch(1e-6)
+	assert.Empty(t, err)
+	assert.True(t, a.IsClosed())
+	assert.Equal(t, 8, a.GetNumberOfVertices())
+	assert.Equal(t, 7, a.GetNumberOfFaces())
+	assert.Equal(t, 6, a.GetNumberOfPatches())
+}
+
+// Test merging with patch deduplication and vertex welding.
+func TestMergeWithOptions(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	a := mesh.ExtractPatches([]int{0, 1, 2})
+	options := MergeOptions{
+		MergePatches:  true,
+		WeldVertices:  true,
+		WeldTolerance: 1e-6,
+	}
+
+	err = a.MergeWithOptions(mesh.ExtractPatches([]int{3, 4, 5}), options)
+	assert.Empty(t, err)
+	assert.True(t, a.IsClosed())
+	assert.Equal(t, 6, a.GetNumberOfPatches())
+
+	c := mesh.ExtractPatches([]int{0})
+	err = c.MergeWithOptions(mesh.ExtractPatches([]int{0}), MergeOptions{MergePatches: true})
+	assert.Empty(t, err)
+	assert.Equal(t, 1, c.GetNumberOfPatches())
+	assert.Equal(t, 2, len(c.GetPatchFaces(0)))
+}
@@ -0,0 +1,183 @@
+package halfedge
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Maximum number of iterations to shrink the inscribed sphere of a face.
+const ThicknessMaxIterations = 64
+
+// Method to measure the wall thickness.
+type ThicknessMethod int
+
+const (
+	// Distance along a ray cast inward from the centroid of each face to the
+	// opposite wall.
+	ThicknessRay ThicknessMethod = iota
+
+	// Diameter of the largest sphere inside the solid touching the centroid
+	// of each face (the shrinking ball method). Unlike the ray, the sphere
+	// also measures the thickness of the walls meeting a face at an angle
+	// (e.g. along the edges of a thin plate).
+	ThicknessSphere
+)
+
+// Options to measure the wall thickness of a closed solid.
+type ThicknessOptions struct {
+	// Method to measure the thickness. The default is ThicknessRay.
+	Method ThicknessMethod
+
+	// Thickness below which the faces are hotspots. Zero finds none.
+	Threshold float64
+}
+
+// Region of connected faces thinner than the threshold.
+type ThicknessHotspot struct {
+	// Thinnest face of the region, its thickness and its centroid.
+	Face      int
+	Thickness float64
+	Point     meshx.Vector
+
+	// Faces of the region (sorted).
+	Faces []int
+}
+
+// Wall thickness of a closed solid.
+type WallThickness struct {
+	// Thickness of each face (infinite if no opposite wall is found). The
+	// values may be colorized with ColorizeFaces.
+	Thickness []float64
+
+	// Regions thinner than the threshold, sorted by their thickness.
+	Hotspots []ThicknessHotspot
+}
+
+// Measure the local wall thickness of a closed solid at each face (e.g. to
+// find walls too thin to manufacture or to volume mesh). The faces must be
+// consistently oriented outward.
+func (m *HalfEdgeMesh) ComputeWallThickness(options ThicknessOptions) WallThickness {
+	thickness := make([]float64, m.GetNumberOfFaces())
+
+	if m.GetNumberOfFaces() == 0 {
+		return WallThickness{Thickness: thickness}
+	}
+
+	octree := m.BuildOctree()
+	aabb := m.GetAABB()
+	offset := 1e-6 * aabb.HalfSize.Mag()
+
+	for i := range thickness {
+		point := m.GetFaceCentroid(i)
+		normal := m.GetFaceNormal(i)
+		thickness[i] = getRayThickness(octree, i, point, normal, offset)
+
+		if options.Method == ThicknessSphere && normal.Mag() > 0 {
+			radius := thickness[i] / 2
+
+			if math.IsInf(radius, 1) {
+				radius = aabb.HalfSize.Mag()
+			}
+
+			thickness[i] = 2 * getInscribedRadius(octree, point, normal, radius)
+		}
+	}
+
+	return WallThickness{thickness, m.getThicknessHotspots(thickness, options.Threshold)}
+}
+
+// Get the distance from a point on a face to the first other face hit by a
+// ray cast against its normal, or infinity if none is hit.
+func getRayThickness(octree *spatial.Octree, face int, point, normal meshx.Vector, offset float64) float64 {
+	if normal.Mag() == 0 {
+		return math.Inf(1)
+	}
+
+	ray := meshx.NewRay(point.Sub(normal.MulScalar(offset)), normal.MulScalar(-1))
+
+	for _, hit := range octree.RaycastAll(ray) {
+		if hit.Index != face {
+			return hit.Distance + offset
+		}
+	}
+
+	return math.Inf(1)
+}
+
+// Get the radius of the largest sphere inside the faces of an octree
+// touching a point with an outward normal by shrinking a sphere of an
+// initial radius. The center is moved along the normal until the closest
+// point on the faces is on the sphere.
+func getInscribedRadius(octree *spatial.Octree, point, normal meshx.Vector, radius float64) float64 {
+	const epsilon = 1e-9
+
+	for range ThicknessMaxIterations {
+		center := point.Sub(normal.MulScalar(radius))
+		_, closest := octree.QueryNearest(center)
+
+		if closest.Sub(center).Mag() >= radius*(1-epsilon) {
+			break
+		}
+
+		// The sphere touching the point and the closest point.
+		d := point.Sub(closest)
+		cos := normal.Dot(d)
+
+		if cos <= 0 {
+			break
+		}
+
+		radius = d.Dot(d) / (2 * cos)
+	}
+
+	return radius
+}
+
+// Get the regions of connected faces thinner than the threshold sorted by
+// their thickness.
+func (m *HalfEdgeMesh) getThicknessHotspots(thickness []float64, threshold float64) []ThicknessHotspot {
+	hotspots := make([]ThicknessHotspot, 0)
+	visited := make([]bool, len(thickness))
+
+	for i, value := range thickness {
+		if visited[i] || value >= threshold {
+			continue
+		}
+
+		hotspot := ThicknessHotspot{Face: i, Thickness: value}
+		queue := []int{i}
+		visited[i] = true
+
+		for len(queue) > 0 {
+			face := queue[0]
+			queue = queue[1:]
+			hotspot.Faces = append(hotspot.Faces, face)
+
+			if thickness[face] < hotspot.Thickness {
+				hotspot.Face = face
+				hotspot.Thickness = thickness[face]
+			}
+
+			for _, neighbor := range m.GetFaceNeighbors(face) {
+				if !visited[neighbor] && thickness[neighbor] < threshold {
+					visited[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+
+		slices.Sort(hotspot.Faces)
+		hotspot.Point = m.GetFaceCentroid(hotspot.Face)
+		hotspots = append(hotspots, hotspot)
+	}
+
+	slices.SortStableFunc(hotspots, func(a, b ThicknessHotspot) int {
+		return cmp.Compare(a.Thickness, b.Thickness)
+	})
+
+	return hotspots
+}
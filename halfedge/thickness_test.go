@@ -0,0 +1,56 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Construct a thin plate of thickness 0.1 (faces 0 and 1 are the sides).
+func newTestPlate(t *testing.T) *HalfEdgeMesh {
+	mesh := newTestCube(t)
+	mesh.Transform(meshx.NewScaleMatrix4(meshx.NewVector(1, 1, 0.1)))
+	return mesh
+}
+
+// Test the wall thickness measured by rays.
+func TestComputeWallThicknessRay(t *testing.T) {
+	mesh := newTestPlate(t)
+	thickness := mesh.ComputeWallThickness(ThicknessOptions{Threshold: 0.5})
+
+	expected := []float64{0.1, 0.1, 1, 1, 1, 1}
+
+	for i, value := range thickness.Thickness {
+		assert.InDelta(t, expected[i], value, 1e-9)
+	}
+
+	// The sides are not connected through the thin faces.
+	assert.Len(t, thickness.Hotspots, 2)
+	assert.Equal(t, []int{0}, thickness.Hotspots[0].Faces)
+	assert.Equal(t, []int{1}, thickness.Hotspots[1].Faces)
+	assert.InDelta(t, 0, thickness.Hotspots[1].Point.Sub(meshx.NewVector(0.5, 0.5, 0.1)).Mag(), 1e-12)
+}
+
+// Test the wall thickness measured by inscribed spheres.
+func TestComputeWallThicknessSphere(t *testing.T) {
+	mesh := newTestPlate(t)
+	thickness := mesh.ComputeWallThickness(ThicknessOptions{Method: ThicknessSphere, Threshold: 0.5})
+
+	for _, value := range thickness.Thickness {
+		assert.InDelta(t, 0.1, value, 1e-6)
+	}
+
+	assert.Len(t, thickness.Hotspots, 1)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, thickness.Hotspots[0].Faces)
+}
+
+// Test the thickness of an open surface without an opposite wall.
+func TestComputeWallThicknessOpen(t *testing.T) {
+	mesh := newTestTriangleGrid(t, 1)
+	thickness := mesh.ComputeWallThickness(ThicknessOptions{Threshold: 1})
+
+	assert.True(t, math.IsInf(thickness.Thickness[0], 1))
+	assert.Empty(t, thickness.Hotspots)
+}
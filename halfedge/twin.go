@@ -0,0 +1,251 @@
+package halfedge
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	// Minimum number of half edges matched by each worker when matching the
+	// twins in parallel.
+	twinMinHalfEdgesPerWorker = 1 << 16
+
+	// Maximum number of workers matching the twins in parallel.
+	twinMaxWorkers = 1 << 8
+)
+
+// Match the twins of the half edges in place. The half edges are bucketed
+// into shards by a hash of their undirected edge so that both half edges of
+// an edge always belong to the same shard. The shards are then matched
+// concurrently with a map local to each worker. An edge shared by more than
+// two half edges is non-manifold unless the half edges of the baffle faces
+// (nil if there are none) and of the other faces can be matched separately.
+// If strict, ErrNonManifold is returned at the first non-manifold edge.
+// Otherwise the half edges of the non-manifold edges are left unmatched and
+// ErrNonManifold is returned once all of the others are matched. Each edge is
+// matched independently, so the twins are the same for any number of workers
+// (and GOMAXPROCS).
+func matchTwins(halfEdges []HalfEdge, baffles []bool, strict bool) error {
+	workers := min(runtime.GOMAXPROCS(0), twinMaxWorkers, max(1, len(halfEdges)/twinMinHalfEdgesPerWorker))
+
+	if workers == 1 {
+		return matchTwinShard(halfEdges, nil, baffles, strict)
+	}
+
+	shards := bucketTwinShards(halfEdges, workers)
+	errs := make([]error, workers)
+
+	var wg sync.WaitGroup
+
+	for w := range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			errs[w] = matchTwinShard(halfEdges, shards[w], baffles, strict)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Bucket the half edge indices into shards by the hash of their undirected
+// edge. Each shard is in ascending order of the half edge index.
+func bucketTwinShards(halfEdges []HalfEdge, workers int) [][]int32 {
+	chunk := (len(halfEdges) + workers - 1) / workers
+	keys := make([]uint8, len(halfEdges))
+	counts := make([][]int, workers)
+
+	var wg sync.WaitGroup
+
+	for w := range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			counts[w] = make([]int, workers)
+
+			for k := w * chunk; k < min((w+1)*chunk, len(halfEdges)); k++ {
+				shard := twinShard(halfEdges, k, workers)
+				keys[k] = uint8(shard)
+				counts[w][shard]++
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	shards := make([][]int32, workers)
+	offsets := make([][]int, workers)
+
+	for s := range workers {
+		var size int
+
+		for w := range workers {
+			size += counts[w][s]
+		}
+
+		shards[s] = make([]int32, size)
+	}
+
+	for w := range workers {
+		offsets[w] = make([]int, workers)
+
+		if w != 0 {
+			for s := range workers {
+				offsets[w][s] = offsets[w-1][s] + counts[w-1][s]
+			}
+		}
+	}
+
+	for w := range workers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			offset := offsets[w]
+
+			for k := w * chunk; k < min((w+1)*chunk, len(halfEdges)); k++ {
+				shard := keys[k]
+				shards[shard][offset[shard]] = int32(k)
+				offset[shard]++
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return shards
+}
+
+// Get the shard of a half edge by the hash of its undirected edge.
+func twinShard(halfEdges []HalfEdge, index, workers int) int {
+	edge := twinKey(halfEdges, index)
+	hash := uint64(edge[0])*0x9e3779b97f4a7c15 ^ uint64(edge[1])*0xc2b2ae3d27d4eb4f
+	hash ^= hash >> 29
+	return int(hash % uint64(workers))
+}
+
+// Get the undirected edge of a half edge as its sorted vertex indices.
+func twinKey(halfEdges []HalfEdge, index int) [2]int {
+	origin := halfEdges[index].Origin
+	target := halfEdges[halfEdges[index].Next].Origin
+	return [2]int{min(origin, target), max(origin, target)}
+}
+
+// Match the twins of the half edges in a shard (see matchTwins). A nil shard
+// matches all of the half edges.
+func matchTwinShard(halfEdges []HalfEdge, shard []int32, baffles []bool, strict bool) error {
+	size := len(shard)
+
+	if shard == nil {
+		size = len(halfEdges)
+	}
+
+	// Unpaired half edge of each edge, -2 minus the first half edge once the
+	// edge is paired, or -1 once the edge is shared by more than two.
+	edges := make(map[[2]int]int, size/2)
+
+	// Half edges of each edge shared by more than two.
+	var shared map[[2]int][]int
+
+	for i := range size {
+		k := i
+
+		if shard != nil {
+			k = int(shard[i])
+		}
+
+		edge := twinKey(halfEdges, k)
+
+		twin, ok := edges[edge]
+
+		if !ok {
+			edges[edge] = k
+			continue
+		}
+
+		if twin < 0 {
+			if baffles == nil && strict {
+				return meshx.ErrNonManifold
+			}
+
+			if shared == nil {
+				shared = make(map[[2]int][]int)
+			}
+
+			if twin != -1 {
+				first := -2 - twin
+				shared[edge] = []int{first, halfEdges[first].Twin}
+				edges[edge] = -1
+			}
+
+			shared[edge] = append(shared[edge], k)
+			continue
+		}
+
+		halfEdges[k].Twin = twin
+		halfEdges[twin].Twin = k
+		edges[edge] = -2 - twin
+	}
+
+	var err error
+
+	for _, group := range shared {
+		if groupErr := matchSharedTwins(halfEdges, group, baffles, strict); groupErr != nil {
+			if strict {
+				return groupErr
+			}
+
+			err = groupErr
+		}
+	}
+
+	return err
+}
+
+// Match the twins of the half edges of an edge shared by more than two: the
+// half edges of the baffle faces with each other and those of the other faces
+// with each other. A single half edge of either is left unmatched, as are
+// more than two unless strict.
+func matchSharedTwins(halfEdges []HalfEdge, group []int, baffles []bool, strict bool) error {
+	var baffle, other []int
+	var err error
+
+	for _, k := range group {
+		halfEdges[k].Twin = -1
+
+		if baffles != nil && baffles[halfEdges[k].Face] {
+			baffle = append(baffle, k)
+		} else {
+			other = append(other, k)
+		}
+	}
+
+	for _, pair := range [][]int{baffle, other} {
+		if len(pair) > 2 {
+			if strict {
+				return meshx.ErrNonManifold
+			}
+
+			err = meshx.ErrNonManifold
+		}
+
+		if len(pair) == 2 {
+			halfEdges[pair[0]].Twin = pair[1]
+			halfEdges[pair[1]].Twin = pair[0]
+		}
+	}
+
+	return err
+}
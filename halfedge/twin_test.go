@@ -0,0 +1,204 @@
+package halfedge
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate an indexed planar grid of quads with n by n vertices.
+func newTwinTestSource(n int) *meshSource {
+	source := meshSource{}
+
+	for i := range n {
+		for j := range n {
+			source.vertices = append(source.vertices, meshx.NewVector(float64(i), float64(j), 0))
+		}
+	}
+
+	for i := range n - 1 {
+		for j := range n - 1 {
+			v := i*n + j
+			source.addFace([]int{v, v + n, v + n + 1, v + 1}, Face{Patch: -1, Material: -1})
+		}
+	}
+
+	return &source
+}
+
+// Test matching the twins in parallel matches them sequentially.
+func TestMatchTwins(t *testing.T) {
+	mesh, err := NewHalfEdgeMesh(newTwinTestSource(256))
+	assert.Empty(t, err)
+	assert.Greater(t, mesh.GetNumberOfHalfEdges(), 2*twinMinHalfEdgesPerWorker)
+
+	expected := append([]HalfEdge(nil), mesh.halfEdges...)
+
+	for i := range expected {
+		expected[i].Twin = -1
+	}
+
+	assert.Empty(t, matchTwinShard(expected, nil, nil, true))
+	assert.Equal(t, expected, mesh.halfEdges)
+
+	for workers := 2; workers <= 5; workers++ {
+		shards := bucketTwinShards(mesh.halfEdges, workers)
+
+		var count int
+
+		for _, shard := range shards {
+			count += len(shard)
+
+			for i := 1; i < len(shard); i++ {
+				assert.Less(t, shard[i-1], shard[i])
+			}
+		}
+
+		assert.Equal(t, len(mesh.halfEdges), count)
+	}
+}
+
+// Test constructing a mesh is reproducible for any GOMAXPROCS.
+func TestNewHalfEdgeMeshReproducible(t *testing.T) {
+	source := newTwinTestSource(256)
+	procs := runtime.GOMAXPROCS(0)
+	defer runtime.GOMAXPROCS(procs)
+
+	runtime.GOMAXPROCS(1)
+	expected, err := NewHalfEdgeMesh(source)
+	assert.Empty(t, err)
+
+	for _, n := range []int{2, 3, 8} {
+		runtime.GOMAXPROCS(n)
+		mesh, err := NewHalfEdgeMesh(source)
+		assert.Empty(t, err)
+		assert.Equal(t, expected.halfEdges, mesh.halfEdges)
+	}
+}
+
+// Test matching the twins in parallel with a non-manifold edge.
+func TestMatchTwinsNonManifold(t *testing.T) {
+	source := newTwinTestSource(256)
+	source.vertices = append(source.vertices, meshx.NewVector(0, 0, 1))
+	source.addFace([]int{1, len(source.vertices) - 1, 257}, Face{Patch: -1, Material: -1})
+
+	_, err := NewHalfEdgeMesh(source)
+	assert.ErrorIs(t, err, meshx.ErrNonManifold)
+}
+
+// Benchmark constructing a half edge mesh of a million quads.
+func BenchmarkNewHalfEdgeMesh(b *testing.B) {
+	source := newTwinTestSource(1001)
+	b.ResetTimer()
+
+	for range b.N {
+		if _, err := NewHalfEdgeMesh(source); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark matching the twins of a million quads in parallel.
+func BenchmarkMatchTwins(b *testing.B) {
+	mesh, err := NewHalfEdgeMesh(newTwinTestSource(1001))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for range b.N {
+		if err := matchTwins(mesh.halfEdges, nil, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Benchmark matching the twins of a million quads sequentially.
+func BenchmarkMatchTwinsSequential(b *testing.B) {
+	mesh, err := NewHalfEdgeMesh(newTwinTestSource(1001))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	for range b.N {
+		if err := matchTwinShard(mesh.halfEdges, nil, nil, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Generate a closed box of two unit cells (patch "wall") divided by a baffle
+// (patch "baffle") at x = 1.
+func newBaffleTestSource() *meshSource {
+	source := meshSource{patches: []string{"wall", "baffle"}}
+	v := func(i, j, k int) int { return i*4 + j*2 + k }
+
+	for i := range 3 {
+		for j := range 2 {
+			for k := range 2 {
+				source.vertices = append(source.vertices, meshx.NewVector(float64(i), float64(j), float64(k)))
+			}
+		}
+	}
+
+	faces := [][]int{
+		{v(0, 0, 0), v(0, 0, 1), v(0, 1, 1), v(0, 1, 0)},
+		{v(2, 0, 0), v(2, 1, 0), v(2, 1, 1), v(2, 0, 1)},
+	}
+
+	for i := range 2 {
+		faces = append(faces,
+			[]int{v(i, 0, 0), v(i, 1, 0), v(i+1, 1, 0), v(i+1, 0, 0)},
+			[]int{v(i, 0, 1), v(i+1, 0, 1), v(i+1, 1, 1), v(i, 1, 1)},
+			[]int{v(i, 0, 0), v(i+1, 0, 0), v(i+1, 0, 1), v(i, 0, 1)},
+			[]int{v(i, 1, 0), v(i, 1, 1), v(i+1, 1, 1), v(i+1, 1, 0)},
+		)
+	}
+
+	for _, face := range faces {
+		source.addFace(face, Face{Patch: 0, Material: -1})
+	}
+
+	source.addFace([]int{v(1, 0, 0), v(1, 1, 0), v(1, 1, 1), v(1, 0, 1)}, Face{Patch: 1, Material: -1})
+
+	return &source
+}
+
+// Test matching the twins of the edges shared by a baffle.
+func TestMatchTwinsBaffles(t *testing.T) {
+	_, err := NewHalfEdgeMesh(newBaffleTestSource())
+	assert.ErrorIs(t, err, meshx.ErrNonManifold)
+
+	mesh, err := NewHalfEdgeMeshWithOptions(newBaffleTestSource(), HalfEdgeMeshOptions{Baffles: []string{"baffle"}})
+	assert.Empty(t, err)
+	assert.True(t, mesh.GetPatch(1).IsBaffle)
+	assert.True(t, mesh.IsBaffleFace(10))
+
+	for i := range mesh.GetNumberOfHalfEdges() {
+		halfEdge := mesh.GetHalfEdge(i)
+		assert.Equal(t, halfEdge.Face == 10, halfEdge.IsBoundary())
+
+		if !halfEdge.IsBoundary() {
+			assert.False(t, mesh.IsBaffleFace(mesh.GetHalfEdge(halfEdge.Twin).Face))
+		}
+	}
+
+	// Two baffles crossing at an edge are matched with each other.
+	source := newBaffleTestSource()
+	source.vertices = append(source.vertices, meshx.NewVector(1, -1, 0), meshx.NewVector(1, -1, 1))
+	source.addFace([]int{4, 5, 13, 12}, Face{Patch: 1, Material: -1})
+	source.baffles = []bool{false, true}
+
+	mesh, err = NewHalfEdgeMesh(source)
+	assert.Empty(t, err)
+	for _, index := range mesh.GetFaceHalfEdges(10) {
+		if halfEdge := mesh.GetHalfEdge(index); halfEdge.Origin == 5 {
+			assert.Equal(t, 11, mesh.GetHalfEdge(halfEdge.Twin).Face)
+		}
+	}
+}
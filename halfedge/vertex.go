@@ -7,4 +7,5 @@ import (
 type Vertex struct {
 	Point    meshx.Vector
 	HalfEdge int
+	Color    meshx.Color
 }
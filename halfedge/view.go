@@ -0,0 +1,310 @@
+package halfedge
+
+import (
+	"io"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Read-only view of a subset of the faces of a HalfEdgeMesh. The view
+// references the faces of the mesh without copying them (unlike Extract),
+// so it is cheap to construct for per-patch analysis of large meshes. The
+// view also implements the MeshReader, MaterialReader, ColorReader,
+// SmoothingGroupReader, BaffleReader and PatchMetadataReader interfaces over
+// its faces and the vertices they reference, so it may be written or copied
+// into a new mesh with NewHalfEdgeMesh. The view is invalidated by changes to the faces or
+// vertices of the mesh.
+type MeshView struct {
+	mesh          *HalfEdgeMesh
+	faces         []int
+	vertices      []int
+	indexVertices map[int]int
+}
+
+// Construct a MeshView of the faces.
+func (m *HalfEdgeMesh) NewView(faces []int) *MeshView {
+	return &MeshView{mesh: m, faces: faces}
+}
+
+// Construct a MeshView of the faces of the patches.
+func (m *HalfEdgeMesh) NewPatchView(patches []int) *MeshView {
+	faces := make([]int, 0)
+	indexPatches := make(map[int]bool)
+
+	for _, patch := range patches {
+		indexPatches[patch] = true
+	}
+
+	for id, face := range m.faces {
+		if indexPatches[face.Patch] {
+			faces = append(faces, id)
+		}
+	}
+
+	return m.NewView(faces)
+}
+
+// Get the mesh of the view.
+func (v *MeshView) GetMesh() *HalfEdgeMesh {
+	return v.mesh
+}
+
+// Get the index in the mesh of a face of the view.
+func (v *MeshView) GetFaceIndex(index int) int {
+	return v.faces[index]
+}
+
+// Get the index in the mesh of a vertex of the view.
+func (v *MeshView) GetVertexIndex(index int) int {
+	v.ensureVertices()
+	return v.vertices[index]
+}
+
+// Build the vertices of the view (in the order first referenced by the
+// faces) on first use.
+func (v *MeshView) ensureVertices() {
+	if v.indexVertices != nil {
+		return
+	}
+
+	v.vertices = make([]int, 0)
+	v.indexVertices = make(map[int]int)
+
+	buf := make([]int, 0, 4)
+
+	for _, face := range v.faces {
+		buf = v.mesh.GetFaceVerticesInto(face, buf)
+
+		for _, vertex := range buf {
+			if _, ok := v.indexVertices[vertex]; !ok {
+				v.indexVertices[vertex] = len(v.vertices)
+				v.vertices = append(v.vertices, vertex)
+			}
+		}
+	}
+}
+
+// Get the vertices of a face of the view as indices of the mesh.
+func (v *MeshView) GetFaceVertices(index int) []int {
+	return v.mesh.GetFaceVertices(v.faces[index])
+}
+
+// Get the unit normal vector of a face of the view.
+func (v *MeshView) GetFaceNormal(index int) meshx.Vector {
+	return v.mesh.GetFaceNormal(v.faces[index])
+}
+
+// Get the area of a face of the view.
+func (v *MeshView) GetFaceArea(index int) float64 {
+	return v.mesh.GetFaceArea(v.faces[index])
+}
+
+// Get the triangles of a face of the view.
+func (v *MeshView) GetFaceTriangles(index int) []meshx.Triangle {
+	return v.mesh.GetFaceTriangles(v.faces[index])
+}
+
+// Get the triangles of all faces of the view.
+func (v *MeshView) GetTriangles() []meshx.Triangle {
+	triangles := make([]meshx.Triangle, 0, len(v.faces))
+
+	for _, face := range v.faces {
+		triangles = append(triangles, v.mesh.GetFaceTriangles(face)...)
+	}
+
+	return triangles
+}
+
+// Get the total area of the faces of the view.
+func (v *MeshView) GetArea() float64 {
+	var area float64
+
+	for _, face := range v.faces {
+		area += v.mesh.GetFaceArea(face)
+	}
+
+	return area
+}
+
+// Get the axis-aligned bounding box of the faces of the view. The AABB is
+// zero if the view has no faces.
+func (v *MeshView) GetAABB() meshx.AABB {
+	if len(v.faces) == 0 {
+		return meshx.AABB{}
+	}
+
+	minBound := v.mesh.vertices[v.GetFaceVertices(0)[0]].Point
+	maxBound := minBound
+
+	buf := make([]int, 0, 4)
+
+	for _, face := range v.faces {
+		buf = v.mesh.GetFaceVerticesInto(face, buf)
+
+		for _, vertex := range buf {
+			point := v.mesh.vertices[vertex].Point
+
+			for i := 0; i < 3; i++ {
+				minBound[i] = min(minBound[i], point[i])
+				maxBound[i] = max(maxBound[i], point[i])
+			}
+		}
+	}
+
+	return meshx.NewAABBFromBounds(minBound, maxBound)
+}
+
+// Implement the MeshReader interface.
+func (v *MeshView) Read() error {
+	return nil
+}
+
+// Implement the MeshReader interface.
+func (v *MeshView) GetNumberOfVertices() int {
+	v.ensureVertices()
+	return len(v.vertices)
+}
+
+// Implement the MeshReader interface.
+func (v *MeshView) GetNumberOfFaces() int {
+	return len(v.faces)
+}
+
+// Implement the MeshReader interface.
+func (v *MeshView) GetNumberOfFaceEdges() int {
+	var count int
+	buf := make([]int, 0, 4)
+
+	for _, face := range v.faces {
+		buf = v.mesh.GetFaceHalfEdgesInto(face, buf)
+		count += len(buf)
+	}
+
+	return count
+}
+
+// Implement the MeshReader interface. The patches are those of the mesh.
+func (v *MeshView) GetNumberOfPatches() int {
+	return v.mesh.GetNumberOfPatches()
+}
+
+// Implement the MeshReader interface.
+func (v *MeshView) GetVertex(index int) meshx.Vector {
+	return v.mesh.vertices[v.GetVertexIndex(index)].Point
+}
+
+// Implement the MeshReader interface. The vertices are indices of the view
+// (see GetFaceVertices for the indices of the mesh).
+func (v *MeshView) GetFace(index int) []int {
+	v.ensureVertices()
+	vertices := v.GetFaceVertices(index)
+
+	for i, vertex := range vertices {
+		vertices[i] = v.indexVertices[vertex]
+	}
+
+	return vertices
+}
+
+// Implement the MeshReader interface.
+func (v *MeshView) GetFacePatch(index int) int {
+	return v.mesh.faces[v.faces[index]].Patch
+}
+
+// Implement the MeshReader interface.
+func (v *MeshView) GetPatch(index int) string {
+	return v.mesh.patches[index].Name
+}
+
+// Implement the BaffleReader interface.
+func (v *MeshView) IsPatchBaffle(index int) bool {
+	return v.mesh.patches[index].IsBaffle
+}
+
+// Implement the PatchMetadataReader interface.
+func (v *MeshView) GetPatchMetadata(index int) PatchMetadata {
+	return v.mesh.patches[index].Metadata
+}
+
+// Implement the MaterialReader interface. The materials are those of the
+// mesh.
+func (v *MeshView) GetNumberOfMaterials() int {
+	return v.mesh.GetNumberOfMaterials()
+}
+
+// Implement the MaterialReader interface.
+func (v *MeshView) GetMaterial(index int) meshx.Material {
+	return v.mesh.materials[index]
+}
+
+// Implement the MaterialReader interface.
+func (v *MeshView) GetFaceMaterial(index int) int {
+	return v.mesh.faces[v.faces[index]].Material
+}
+
+// Implement the ColorReader interface.
+func (v *MeshView) HasVertexColors() bool {
+	return v.mesh.hasVertexColors
+}
+
+// Implement the ColorReader interface.
+func (v *MeshView) GetVertexColor(index int) meshx.Color {
+	return v.mesh.vertices[v.GetVertexIndex(index)].Color
+}
+
+// Implement the ColorReader interface.
+func (v *MeshView) HasFaceColors() bool {
+	return v.mesh.hasFaceColors
+}
+
+// Implement the ColorReader interface.
+func (v *MeshView) GetFaceColor(index int) meshx.Color {
+	return v.mesh.faces[v.faces[index]].Color
+}
+
+// Implement the SmoothingGroupReader interface.
+func (v *MeshView) GetFaceSmoothingGroup(index int) int {
+	return v.mesh.faces[v.faces[index]].SmoothingGroup
+}
+
+// Write the faces of the view to an OBJ file. The face materials are
+// written without a material library.
+func (v *MeshView) WriteOBJ(writer io.Writer) error {
+	vertices := make([]meshx.Vector, v.GetNumberOfVertices())
+	faces := make([][]int, v.GetNumberOfFaces())
+	facePatches := make([]int, v.GetNumberOfFaces())
+	faceMaterials := make([]int, v.GetNumberOfFaces())
+	smoothingGroups := make([]int, v.GetNumberOfFaces())
+	patches := make([]string, v.GetNumberOfPatches())
+	materials := make([]string, v.GetNumberOfMaterials())
+
+	for i := range patches {
+		patches[i] = v.GetPatch(i)
+	}
+
+	for i := range materials {
+		materials[i] = v.GetMaterial(i).Name
+	}
+
+	for i := range vertices {
+		vertices[i] = v.GetVertex(i)
+	}
+
+	for i := range faces {
+		faces[i] = v.GetFace(i)
+		facePatches[i] = v.GetFacePatch(i)
+		faceMaterials[i] = v.GetFaceMaterial(i)
+		smoothingGroups[i] = v.GetFaceSmoothingGroup(i)
+	}
+
+	objWriter := meshx.NewOBJWriter(writer)
+	objWriter.SetVertices(vertices)
+	objWriter.SetFaces(faces)
+	objWriter.SetFacePatches(facePatches)
+	objWriter.SetPatches(patches)
+	objWriter.SetFaceMaterials(faceMaterials)
+	objWriter.SetMaterials(materials)
+	objWriter.SetFaceSmoothingGroups(smoothingGroups)
+
+	return objWriter.Write()
+}
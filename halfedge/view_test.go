@@ -0,0 +1,55 @@
+package halfedge
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeshView(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	view := mesh.NewPatchView([]int{5, 1})
+	extracted := mesh.ExtractPatches([]int{5, 1})
+	assert.Equal(t, extracted.GetNumberOfFaces(), view.GetNumberOfFaces())
+	assert.Equal(t, extracted.GetNumberOfVertices(), view.GetNumberOfVertices())
+	assert.Equal(t, extracted.GetAABB(), view.GetAABB())
+
+	var area float64
+
+	for i := range extracted.GetNumberOfFaces() {
+		area += extracted.GetFaceArea(i)
+	}
+
+	assert.InDelta(t, area, view.GetArea(), 1e-12)
+
+	for i := range view.GetNumberOfFaces() {
+		face := view.GetFaceIndex(i)
+		assert.Equal(t, mesh.GetFaceNormal(face), view.GetFaceNormal(i))
+		assert.Equal(t, mesh.GetFace(face).Patch, view.GetFacePatch(i))
+
+		for j, vertex := range view.GetFace(i) {
+			assert.Equal(t, mesh.GetVertex(view.GetFaceVertices(i)[j]).Point, view.GetVertex(vertex))
+		}
+	}
+
+	copied, err := NewHalfEdgeMesh(view)
+	assert.Empty(t, err)
+	assert.Equal(t, view.GetNumberOfFaces(), copied.GetNumberOfFaces())
+	assert.Equal(t, view.GetNumberOfVertices(), copied.GetNumberOfVertices())
+
+	var buffer bytes.Buffer
+	assert.Empty(t, view.WriteOBJ(&buffer))
+
+	reader := meshx.NewOBJReader(&buffer)
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, view.GetNumberOfFaces(), reader.GetNumberOfFaces())
+	assert.Equal(t, view.GetNumberOfVertices(), reader.GetNumberOfVertices())
+
+	empty := mesh.NewView(nil)
+	assert.Equal(t, 0, empty.GetNumberOfVertices())
+	assert.Equal(t, meshx.AABB{}, empty.GetAABB())
+}
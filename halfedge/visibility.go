@@ -0,0 +1,105 @@
+package halfedge
+
+import (
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Options to select the faces visible from a viewpoint.
+type VisibilityOptions struct {
+	// Frustum of the camera at the viewpoint (see meshx.NewFrustum). A face
+	// is only visible through its points in the frustum. The zero value has
+	// no half-spaces and sees in every direction.
+	Frustum meshx.Polytope
+
+	// Cull the faces seen from behind (opposite their normal).
+	CullBackFaces bool
+
+	// Additional viewpoints from which the faces are also visible (e.g. the
+	// corners of a box around the mesh to keep the externally visible
+	// surfaces). The frustum only applies to the first viewpoint.
+	Viewpoints []meshx.Vector
+}
+
+// Select the faces visible from a viewpoint. See
+// SelectVisibleFacesWithOptions.
+func (m *HalfEdgeMesh) SelectVisibleFaces(viewpoint meshx.Vector) []int {
+	return m.SelectVisibleFacesWithOptions(viewpoint, VisibilityOptions{})
+}
+
+// Select the faces visible from a viewpoint with options (e.g. to strip the
+// internal geometry hidden by the external surfaces). A face is visible if
+// the segment from the viewpoint to one of its sample points (the centroid
+// of each triangle and the points halfway to its corners) does not hit
+// another face. The faces are sorted.
+func (m *HalfEdgeMesh) SelectVisibleFacesWithOptions(viewpoint meshx.Vector, options VisibilityOptions) []int {
+	faces := make([]int, 0)
+
+	if m.GetNumberOfFaces() == 0 {
+		return faces
+	}
+
+	octree := m.BuildOctree()
+	offset := 1e-6 * m.GetAABB().HalfSize.Mag()
+
+	for i := range m.GetNumberOfFaces() {
+		if m.isFaceVisible(octree, i, viewpoint, options.Frustum, options.CullBackFaces, offset) {
+			faces = append(faces, i)
+			continue
+		}
+
+		for _, other := range options.Viewpoints {
+			if m.isFaceVisible(octree, i, other, meshx.Polytope{}, options.CullBackFaces, offset) {
+				faces = append(faces, i)
+				break
+			}
+		}
+	}
+
+	return faces
+}
+
+// Check if a face is visible from a viewpoint through a frustum. The
+// segments to its sample points stop short by an offset so that the face
+// and its neighbors sharing the sample points are not hit.
+func (m *HalfEdgeMesh) isFaceVisible(octree *spatial.Octree, face int, viewpoint meshx.Vector, frustum meshx.Polytope, cullBackFaces bool, offset float64) bool {
+	normal := m.GetFaceNormal(face)
+
+	for _, point := range getVisibilitySamples(m.GetFaceTriangles(face)) {
+		direction := viewpoint.Sub(point)
+
+		if cullBackFaces && normal.Dot(direction) <= 0 {
+			return false
+		}
+
+		if !frustum.ContainsPoint(point) || direction.Mag() <= offset {
+			continue
+		}
+
+		end := point.Add(direction.Unit().MulScalar(offset))
+		hits := octree.Query(meshx.NewSegment(viewpoint, end))
+
+		if len(hits) == 0 || (len(hits) == 1 && hits[0] == face) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get the sample points of the triangles of a face: the centroid of each
+// triangle and the points halfway from it to the corners.
+func getVisibilitySamples(triangles []meshx.Triangle) []meshx.Vector {
+	samples := make([]meshx.Vector, 0, 4*len(triangles))
+
+	for _, triangle := range triangles {
+		centroid := triangle.Centroid()
+		samples = append(samples, centroid)
+
+		for _, corner := range []meshx.Vector{triangle.P, triangle.Q, triangle.R} {
+			samples = append(samples, centroid.Add(corner).MulScalar(0.5))
+		}
+	}
+
+	return samples
+}
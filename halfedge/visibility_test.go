@@ -0,0 +1,53 @@
+package halfedge
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Construct a unit cube enclosing a smaller cube (faces 6 to 11).
+func newTestNestedCubes(t *testing.T) *HalfEdgeMesh {
+	mesh := newTestCube(t)
+	inner := newTestCube(t)
+	inner.Transform(meshx.NewTranslationMatrix4(meshx.NewVector(0.6, 0.6, 0.6)).Mul(meshx.NewScaleMatrix4(meshx.NewVector(0.2, 0.2, 0.2))))
+	mesh.Merge(inner)
+	return mesh
+}
+
+// Test selecting the faces visible from a viewpoint.
+func TestSelectVisibleFaces(t *testing.T) {
+	mesh := newTestNestedCubes(t)
+
+	assert.Equal(t, []int{5}, mesh.SelectVisibleFaces(meshx.NewVector(5, 0.5, 0.5)))
+	assert.Equal(t, []int{1, 3, 5}, mesh.SelectVisibleFaces(meshx.NewVector(5, 5, 5)))
+
+	// The inner cube hides the faces behind it from inside the outer cube.
+	visible := mesh.SelectVisibleFaces(meshx.NewVector(0.2, 0.2, 0.2))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 8, 10}, visible)
+}
+
+// Test selecting the visible faces with options.
+func TestSelectVisibleFacesWithOptions(t *testing.T) {
+	mesh := newTestNestedCubes(t)
+
+	options := VisibilityOptions{CullBackFaces: true}
+	assert.Equal(t, []int{6, 8, 10}, mesh.SelectVisibleFacesWithOptions(meshx.NewVector(0.2, 0.2, 0.2), options))
+
+	// The externally visible faces from the corners of a box around the mesh.
+	options = VisibilityOptions{}
+	viewpoint := meshx.NewVector(-5, -5, -5)
+
+	for i := 1; i < 8; i++ {
+		options.Viewpoints = append(options.Viewpoints, meshx.NewVector(float64(i&1), float64(i>>1&1), float64(i>>2&1)).MulScalar(10).Add(viewpoint))
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, mesh.SelectVisibleFacesWithOptions(viewpoint, options))
+
+	// The frustum looking down at the top face.
+	eye := meshx.NewVector(0.5, 0.5, 3)
+	options = VisibilityOptions{Frustum: meshx.NewFrustum(eye, meshx.NewVector(0.5, 0.5, 0), meshx.NewVector(0, 1, 0), math.Pi/3, 1)}
+	assert.Equal(t, []int{1}, mesh.SelectVisibleFacesWithOptions(eye, options))
+}
@@ -0,0 +1,102 @@
+package halfedge
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrTooManyVoxels = errors.New("too many voxels")
+)
+
+// Default maximum number of voxels of a grid or octree.
+const DefaultMaxVoxels = 1 << 22
+
+// Uniform grid of occupied or empty voxels covering an AABB. The grid is
+// padded by a layer of voxels so every voxel within the AABB has a full
+// neighborhood.
+type voxelGrid struct {
+	origin   meshx.Vector
+	size     float64
+	shape    [3]int
+	occupied []bool
+}
+
+// Offsets of the 26 neighbors of a voxel.
+var voxelOffsets = func() [][3]int {
+	offsets := make([][3]int, 0, 26)
+
+	for k := -1; k <= 1; k++ {
+		for j := -1; j <= 1; j++ {
+			for i := -1; i <= 1; i++ {
+				if i != 0 || j != 0 || k != 0 {
+					offsets = append(offsets, [3]int{i, j, k})
+				}
+			}
+		}
+	}
+
+	return offsets
+}()
+
+// Construct a voxelGrid of empty voxels covering the AABB. ErrTooManyVoxels
+// is returned if the grid has more than the maximum number of voxels (or
+// DefaultMaxVoxels if zero).
+func newVoxelGrid(aabb meshx.AABB, size float64, maxVoxels int) (*voxelGrid, error) {
+	var shape [3]int
+
+	if maxVoxels <= 0 {
+		maxVoxels = DefaultMaxVoxels
+	}
+
+	count := 1.0
+
+	for i := range shape {
+		n := math.Ceil(2*aabb.HalfSize[i]/size) + 2
+		count *= n
+
+		if !(count <= float64(maxVoxels)) {
+			return nil, ErrTooManyVoxels
+		}
+
+		shape[i] = int(n)
+	}
+
+	// Center the voxels on the AABB.
+	origin := aabb.Center
+
+	for i := range origin {
+		origin[i] -= float64(shape[i]) * size / 2
+	}
+
+	grid := voxelGrid{
+		origin:   origin,
+		size:     size,
+		shape:    shape,
+		occupied: make([]bool, shape[0]*shape[1]*shape[2]),
+	}
+
+	return &grid, nil
+}
+
+// Get the index of a voxel.
+func (g *voxelGrid) getIndex(i, j, k int) int {
+	return (k*g.shape[1]+j)*g.shape[0] + i
+}
+
+// Get the voxel coordinates of an index.
+func (g *voxelGrid) getCoordinates(index int) (int, int, int) {
+	i := index % g.shape[0]
+	j := (index / g.shape[0]) % g.shape[1]
+	k := index / (g.shape[0] * g.shape[1])
+	return i, j, k
+}
+
+// Get the center of a voxel.
+func (g *voxelGrid) getCenter(index int) meshx.Vector {
+	i, j, k := g.getCoordinates(index)
+	offset := meshx.NewVector(float64(i)+0.5, float64(j)+0.5, float64(k)+0.5)
+	return g.origin.Add(offset.MulScalar(g.size))
+}
@@ -0,0 +1,404 @@
+package halfedge
+
+import (
+	"math"
+	"slices"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Deepest level of a voxelTree (relative to the root).
+const voxelTreeMaxDepth = 30
+
+// Key of a node of a voxelTree: its level and coordinates at the level.
+type voxelKey struct {
+	level   int
+	i, j, k int
+}
+
+// Get the key of a child by octant (bits of the offset i, j, k).
+func (k voxelKey) child(octant int) voxelKey {
+	return voxelKey{k.level + 1, 2*k.i + octant&1, 2*k.j + octant>>1&1, 2*k.k + octant>>2&1}
+}
+
+// Adaptive octree of cubic voxels covering a cube. The leaves intersecting
+// the triangles (occupied) are of the local size of the refinement regions
+// (or smaller), the empty leaves touching them are of their size and the
+// other leaves are as large as possible.
+type voxelTree struct {
+	origin   meshx.Vector
+	size     float64
+	depth    int
+	nodes    map[voxelKey]int
+	leaves   []voxelKey
+	occupied []bool
+}
+
+// Construct a voxelTree covering the AABB. The local size of a leaf is that
+// of the refinement regions at its center (see GetRefinementSize) or of a
+// finer region it intersects. The local sizes are rounded down to the size
+// by a power of two. ErrTooManyVoxels is returned if the tree has more than
+// the maximum number of leaves (or DefaultMaxVoxels if zero).
+func newVoxelTree(aabb meshx.AABB, triangles []meshx.Triangle, regions []RefinementRegion, size float64, maxVoxels int) (*voxelTree, error) {
+	if maxVoxels <= 0 {
+		maxVoxels = DefaultMaxVoxels
+	}
+
+	extent := 2 * max(aabb.HalfSize[0], aabb.HalfSize[1], aabb.HalfSize[2])
+
+	if !(size > 0) || math.IsInf(extent/size, 1) {
+		return nil, ErrTooManyVoxels
+	}
+
+	// Level of the voxels of the size, so the root covers the AABB when
+	// offset by half a voxel.
+	base := max(0, int(math.Ceil(math.Log2(extent/size+1)-1e-9)))
+	depth := base
+
+	for _, region := range regions {
+		if region.Size > 0 && region.Size < size {
+			depth = max(depth, base+int(math.Ceil(math.Log2(size/region.Size)-1e-9)))
+		}
+	}
+
+	if depth > voxelTreeMaxDepth {
+		return nil, ErrTooManyVoxels
+	}
+
+	// Offset the root by half a voxel so the center of the AABB (and the
+	// axis-aligned faces at a multiple of the size from it) are at the
+	// centers of the voxels of the size rather than between voxels.
+	rootSize := math.Ldexp(size, base)
+	half := meshx.NewVector(rootSize/2+size/2, rootSize/2+size/2, rootSize/2+size/2)
+
+	t := voxelTree{
+		origin: aabb.Center.Sub(half),
+		size:   rootSize,
+		depth:  depth,
+	}
+
+	// Get the level of the voxels of a local size.
+	getLevel := func(local float64) int {
+		return min(depth, max(0, base+int(math.Ceil(math.Log2(size/local)-1e-9))))
+	}
+
+	// Get the level of the local size of a node.
+	getTargetLevel := func(key voxelKey) int {
+		bounds := t.getAABB(key)
+		local := GetRefinementSize(regions, size, bounds.Center)
+		radius := bounds.HalfSize.Mag()
+
+		for _, region := range regions {
+			if region.Size > 0 && region.Size < local && region.Shape.DistanceTo(bounds.Center) <= region.Distance+radius {
+				local = region.Size
+			}
+		}
+
+		return getLevel(local)
+	}
+
+	// Get the triangles of a list intersecting a node. The node is slightly
+	// enlarged so that the triangles on the boundary between nodes are not
+	// lost to round off.
+	getTriangles := func(key voxelKey, candidates []int) []int {
+		bounds := t.getAABB(key)
+		bounds.HalfSize = bounds.HalfSize.MulScalar(1 + 2e-6)
+		intersecting := make([]int, 0)
+
+		for _, index := range candidates {
+			if triangles[index].IntersectsAABB(bounds) {
+				intersecting = append(intersecting, index)
+			}
+		}
+
+		return intersecting
+	}
+
+	// Split the leaves intersecting the triangles to their local size, one
+	// level at a time so the number of leaves is checked before splitting.
+	keys := []voxelKey{{}}
+	lists := [][]int{make([]int, len(triangles))}
+	numberOfLeaves := 1
+
+	for i := range lists[0] {
+		lists[0][i] = i
+	}
+
+	splitKeys := make([]voxelKey, 0)
+	leafKeys := make([]voxelKey, 0)
+	leafLists := make([][]int, 0)
+
+	for len(keys) > 0 {
+		isSplit := make([]bool, len(keys))
+		count := 0
+
+		for i, key := range keys {
+			if len(lists[i]) > 0 && key.level < getTargetLevel(key) {
+				isSplit[i] = true
+				count++
+			}
+		}
+
+		if numberOfLeaves+7*count > maxVoxels {
+			return nil, ErrTooManyVoxels
+		}
+
+		numberOfLeaves += 7 * count
+		nextKeys := make([]voxelKey, 0, 8*count)
+		nextLists := make([][]int, 0, 8*count)
+
+		for i, key := range keys {
+			if !isSplit[i] {
+				leafKeys = append(leafKeys, key)
+				leafLists = append(leafLists, lists[i])
+				continue
+			}
+
+			splitKeys = append(splitKeys, key)
+
+			for octant := range 8 {
+				child := key.child(octant)
+				nextKeys = append(nextKeys, child)
+				nextLists = append(nextLists, getTriangles(child, lists[i]))
+			}
+		}
+
+		keys, lists = nextKeys, nextLists
+	}
+
+	// Nodes by key and whether they are leaves.
+	nodes := make(map[voxelKey]bool, len(splitKeys)+len(leafKeys))
+	occupied := make(map[voxelKey]bool)
+
+	for _, key := range splitKeys {
+		nodes[key] = false
+	}
+
+	for i, key := range leafKeys {
+		nodes[key] = true
+
+		if len(leafLists[i]) > 0 {
+			occupied[key] = true
+		}
+	}
+
+	// Split the empty leaves coarser than an occupied neighbor (by face, edge
+	// or corner) so the boundary of the occupied leaves is of their size.
+	queue := make([]voxelKey, 0, len(occupied))
+
+	for key := range occupied {
+		queue = append(queue, key)
+	}
+
+	for len(queue) > 0 {
+		key := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		n := 1 << key.level
+
+		for _, offset := range voxelOffsets {
+			i, j, k := key.i+offset[0], key.j+offset[1], key.k+offset[2]
+
+			if i < 0 || i >= n || j < 0 || j >= n || k < 0 || k >= n {
+				continue
+			}
+
+			for level := key.level - 1; level >= 0; level-- {
+				shift := key.level - level
+				ancestor := voxelKey{level, i >> shift, j >> shift, k >> shift}
+				isLeaf, ok := nodes[ancestor]
+
+				if !ok {
+					continue
+				}
+
+				if isLeaf && !occupied[ancestor] {
+					if numberOfLeaves+7 > maxVoxels {
+						return nil, ErrTooManyVoxels
+					}
+
+					numberOfLeaves += 7
+					nodes[ancestor] = false
+
+					for octant := range 8 {
+						nodes[ancestor.child(octant)] = true
+					}
+
+					queue = append(queue, key)
+				}
+
+				break
+			}
+		}
+	}
+
+	t.nodes = make(map[voxelKey]int, len(nodes))
+	t.leaves = make([]voxelKey, 0, numberOfLeaves)
+
+	for key, isLeaf := range nodes {
+		t.nodes[key] = -1
+
+		if isLeaf {
+			t.leaves = append(t.leaves, key)
+		}
+	}
+
+	slices.SortFunc(t.leaves, compareVoxelKeys)
+	t.occupied = make([]bool, len(t.leaves))
+
+	for index, key := range t.leaves {
+		t.nodes[key] = index
+		t.occupied[index] = occupied[key]
+	}
+
+	return &t, nil
+}
+
+// Compare the keys of voxels by level and then coordinates.
+func compareVoxelKeys(a, b voxelKey) int {
+	for _, d := range [4]int{a.level - b.level, a.k - b.k, a.j - b.j, a.i - b.i} {
+		if d != 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// Get the number of leaves.
+func (t *voxelTree) getNumberOfLeaves() int {
+	return len(t.leaves)
+}
+
+// Get the edge length of the voxels of a level.
+func (t *voxelTree) getSize(level int) float64 {
+	return math.Ldexp(t.size, -level)
+}
+
+// Get the bounding box of a node.
+func (t *voxelTree) getAABB(key voxelKey) meshx.AABB {
+	size := t.getSize(key.level)
+	center := meshx.NewVector(float64(key.i)+0.5, float64(key.j)+0.5, float64(key.k)+0.5)
+	return meshx.NewAABB(t.origin.Add(center.MulScalar(size)), meshx.NewVector(size/2, size/2, size/2))
+}
+
+// Get the center of a leaf.
+func (t *voxelTree) getCenter(index int) meshx.Vector {
+	return t.getAABB(t.leaves[index]).Center
+}
+
+// Get the edge length of a leaf.
+func (t *voxelTree) getLeafSize(index int) float64 {
+	return t.getSize(t.leaves[index].level)
+}
+
+// Return true if a leaf is on the boundary of the root.
+func (t *voxelTree) isBoundary(index int) bool {
+	key := t.leaves[index]
+	n := 1<<key.level - 1
+	return key.i == 0 || key.j == 0 || key.k == 0 || key.i == n || key.j == n || key.k == n
+}
+
+// Find the leaf containing a node (at the level of the node or above). False
+// is returned if the node is outside the root or split into smaller leaves.
+func (t *voxelTree) findLeaf(key voxelKey) (int, bool) {
+	n := 1 << key.level
+
+	if key.i < 0 || key.i >= n || key.j < 0 || key.j >= n || key.k < 0 || key.k >= n {
+		return -1, false
+	}
+
+	for level := key.level; level >= 0; level-- {
+		shift := key.level - level
+
+		if index, ok := t.nodes[voxelKey{level, key.i >> shift, key.j >> shift, key.k >> shift}]; ok {
+			return index, index != -1
+		}
+	}
+
+	return -1, false
+}
+
+// Find the leaf containing a point. False is returned if the point is
+// outside the root.
+func (t *voxelTree) findPoint(point meshx.Vector) (int, bool) {
+	var coordinates [3]int
+
+	size := t.getSize(t.depth)
+
+	for i := range coordinates {
+		coordinates[i] = int(math.Floor((point[i] - t.origin[i]) / size))
+	}
+
+	return t.findLeaf(voxelKey{t.depth, coordinates[0], coordinates[1], coordinates[2]})
+}
+
+// Get the leaves sharing a face with a leaf.
+func (t *voxelTree) getNeighbors(index int) []int {
+	key := t.leaves[index]
+	neighbors := make([]int, 0, 6)
+
+	for axis := range 3 {
+		for _, sign := range [2]int{-1, 1} {
+			coordinates := [3]int{key.i, key.j, key.k}
+			coordinates[axis] += sign
+			stack := []voxelKey{{key.level, coordinates[0], coordinates[1], coordinates[2]}}
+
+			// Descend into a split neighbor to the leaves on the shared face.
+			for len(stack) > 0 {
+				neighbor := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+
+				if leaf, ok := t.findLeaf(neighbor); ok {
+					neighbors = append(neighbors, leaf)
+					continue
+				}
+
+				if t.nodes[neighbor] != -1 {
+					continue
+				}
+
+				for octant := range 8 {
+					if bit := octant >> axis & 1; (sign > 0) == (bit == 0) {
+						stack = append(stack, neighbor.child(octant))
+					}
+				}
+			}
+		}
+	}
+
+	return neighbors
+}
+
+// Get the leaves intersecting an AABB.
+func (t *voxelTree) query(query meshx.AABB) []int {
+	leaves := make([]int, 0)
+	stack := []voxelKey{{}}
+
+	for len(stack) > 0 {
+		key := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !t.getAABB(key).IntersectsAABB(query) {
+			continue
+		}
+
+		if index := t.nodes[key]; index != -1 {
+			leaves = append(leaves, index)
+			continue
+		}
+
+		for octant := range 8 {
+			stack = append(stack, key.child(octant))
+		}
+	}
+
+	return leaves
+}
+
+// Get the leaves touching a leaf by face, edge or corner.
+func (t *voxelTree) getTouching(index int) []int {
+	bounds := t.getAABB(t.leaves[index])
+	bounds.HalfSize = bounds.HalfSize.MulScalar(1 + 1e-6)
+	leaves := t.query(bounds)
+	return slices.DeleteFunc(leaves, func(leaf int) bool { return leaf == index })
+}
@@ -0,0 +1,89 @@
+package halfedge
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the voxels are fine only within the refinement regions.
+func TestNewVoxelTree(t *testing.T) {
+	mesh := newTestCylinder(t, 1, 0, 1)
+	region := NewRefinementRegion(meshx.NewSphere(meshx.NewVector(1, 0, 0.5), 0.2), 0, 0.025)
+	tree, err := newVoxelTree(mesh.GetAABB().Buffer(0.2), mesh.GetTriangles(), []RefinementRegion{region}, 0.1, 0)
+	assert.Empty(t, err)
+
+	for index := range tree.getNumberOfLeaves() {
+		if !tree.occupied[index] {
+			continue
+		}
+
+		center := tree.getCenter(index)
+		size := tree.getLeafSize(index)
+
+		if region.Shape.DistanceTo(center) > 0.2 {
+			assert.InDelta(t, 0.1, size, 1e-9)
+		} else if region.Contains(center) {
+			assert.InDelta(t, 0.025, size, 1e-9)
+		}
+	}
+
+	// The faces of the neighbors of each voxel cover its faces.
+	for index := range tree.getNumberOfLeaves() {
+		size := tree.getLeafSize(index)
+		var area float64
+
+		for _, neighbor := range tree.getNeighbors(index) {
+			area += min(size, tree.getLeafSize(neighbor)) * min(size, tree.getLeafSize(neighbor))
+		}
+
+		if !tree.isBoundary(index) {
+			assert.InDelta(t, 6*size*size, area, 1e-9)
+		}
+	}
+
+	_, err = newVoxelTree(mesh.GetAABB(), mesh.GetTriangles(), []RefinementRegion{region}, 0.1, 100)
+	assert.ErrorIs(t, err, ErrTooManyVoxels)
+}
+
+// Test the boundary of random voxels of an adaptive octree is closed and
+// consistently oriented.
+func TestVoxelTreeGetIsosurface(t *testing.T) {
+	mesh := newTestCylinder(t, 1, 0, 1)
+	regions := []RefinementRegion{
+		NewRefinementRegion(meshx.NewSphere(meshx.NewVector(1, 0, 0.5), 0.3), 0, 0.025),
+		NewRefinementRegion(meshx.NewSphere(meshx.NewVector(-1, 0, 0.5), 0.3), 0, 0.4),
+	}
+
+	tree, err := newVoxelTree(mesh.GetAABB().Buffer(0.5), mesh.GetTriangles(), regions, 0.1, 0)
+	assert.Empty(t, err)
+
+	rng := rand.New(rand.NewSource(1))
+
+	for range 4 {
+		inside := make([]bool, tree.getNumberOfLeaves())
+
+		for index := range inside {
+			inside[index] = !tree.isBoundary(index) && rng.Intn(2) == 0
+		}
+
+		source := tree.getIsosurface(inside)
+		assert.Greater(t, len(source.faces), 0)
+
+		// Each directed edge is matched by exactly one opposite edge.
+		edges := make(map[[2]int]int)
+
+		for _, face := range source.faces {
+			for i := range face {
+				edges[[2]int{face[i], face[(i+1)%3]}]++
+			}
+		}
+
+		for edge, count := range edges {
+			assert.Equal(t, 1, count)
+			assert.Equal(t, 1, edges[[2]int{edge[1], edge[0]}])
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package halfedge
+
+import (
+	"errors"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+var (
+	ErrEmptyWrap = errors.New("no faces to wrap")
+)
+
+// Default number of voxels along the longest side of the AABB.
+const DefaultWrapResolution = 128
+
+// Minimum area of a face of the wrap after projection relative to the area
+// of a face of the smallest voxel.
+const WrapMinFaceArea = 0.01
+
+// Options to wrap a mesh.
+type WrapOptions struct {
+	// Edge length of the voxels. Gaps narrower than about a voxel are
+	// closed. The default is the longest side of the AABB divided by
+	// DefaultWrapResolution.
+	VoxelSize float64
+
+	// Maximum distance a vertex is projected onto the closest point of the
+	// mesh. Vertices farther away (e.g. over a gap) are left in place, as are
+	// those whose projection would flip a face or shrink it below
+	// WrapMinFaceArea (which are only moved part of the way if possible). The
+	// default is twice the largest local voxel size.
+	MaxProjection float64
+
+	// Skip projecting the vertices onto the mesh, leaving the surface up to
+	// a voxel outside the mesh.
+	SkipProjection bool
+
+	// Regions with a local voxel size. The voxels are fine only where the
+	// regions require it and the gaps narrower than about the local size are
+	// closed.
+	Refinements []RefinementRegion
+
+	// Maximum number of voxels. ErrTooManyVoxels is returned if the voxel
+	// size is too small for the mesh. The default is DefaultMaxVoxels.
+	MaxVoxels int
+}
+
+// Wrap the mesh with a closed manifold surface. See WrapWithOptions.
+func (m *HalfEdgeMesh) Wrap() (*HalfEdgeMesh, error) {
+	return m.WrapWithOptions(WrapOptions{})
+}
+
+// Wrap the mesh (e.g. dirty geometry of multiple, intersecting or open
+// components) with a closed manifold triangulated surface (shrink wrap). The
+// faces are voxelized on an adaptive octree, fine only where the refinement
+// regions require it, and the exterior flood filled from the boundary of the
+// octree. The boundary of the remaining voxels is extracted by marching
+// tetrahedra over the dual of the octree and the vertices are projected back
+// onto the closest point of the mesh unless the projection would flip or
+// degenerate a face. Cavities inside the mesh and gaps narrower than a voxel
+// (or the local size of a refinement region) are closed. The faces of the
+// wrap are oriented outward and have no patch.
+func (m *HalfEdgeMesh) WrapWithOptions(options WrapOptions) (*HalfEdgeMesh, error) {
+	if m.GetNumberOfFaces() == 0 {
+		return nil, ErrEmptyWrap
+	}
+
+	aabb := m.GetAABB()
+	size := options.VoxelSize
+	maxProjection := options.MaxProjection
+
+	if size <= 0 {
+		size = 2 * max(aabb.HalfSize[0], aabb.HalfSize[1], aabb.HalfSize[2]) / DefaultWrapResolution
+	}
+
+	coarse := getMaxRefinementSize(options.Refinements, size)
+
+	if maxProjection <= 0 {
+		maxProjection = 2 * coarse
+	}
+
+	// Pad the octree so the voxels on its boundary are clear of the faces.
+	padding := meshx.NewVector(2*coarse, 2*coarse, 2*coarse)
+	aabb = meshx.NewAABB(aabb.Center, aabb.HalfSize.Add(padding))
+	tree, err := newVoxelTree(aabb, m.GetTriangles(), options.Refinements, size, options.MaxVoxels)
+	if err != nil {
+		return nil, err
+	}
+
+	inside := tree.getInterior()
+	source := tree.getIsosurface(inside)
+	fine := getMinRefinementSize(options.Refinements, size)
+
+	if !options.SkipProjection {
+		projectWrap(source, m.BuildOctree(), maxProjection, WrapMinFaceArea*fine*fine)
+	}
+
+	return NewHalfEdgeMesh(source)
+}
+
+// Project the vertices of a wrap onto the closest point of the mesh within
+// the maximum distance. A vertex is moved by the largest of the full, half,
+// quarter and eighth projection that keeps the area of each of its faces
+// along the face normal before projection at least the minimum area, or else
+// left in place.
+func projectWrap(source *meshSource, octree *spatial.Octree, maxProjection, minArea float64) {
+	vertexFaces := make([][]int, len(source.vertices))
+	normals := make([]meshx.Vector, len(source.faces))
+
+	for i, face := range source.faces {
+		p, q, r := source.vertices[face[0]], source.vertices[face[1]], source.vertices[face[2]]
+		normals[i] = q.Sub(p).Cross(r.Sub(p)).Unit()
+
+		for _, vertex := range face {
+			vertexFaces[vertex] = append(vertexFaces[vertex], i)
+		}
+	}
+
+	// Check the faces of a vertex at a point keep the minimum area.
+	isValid := func(vertex int, point meshx.Vector) bool {
+		for _, i := range vertexFaces[vertex] {
+			var points [3]meshx.Vector
+
+			for j, v := range source.faces[i] {
+				points[j] = source.vertices[v]
+
+				if v == vertex {
+					points[j] = point
+				}
+			}
+
+			normal := points[1].Sub(points[0]).Cross(points[2].Sub(points[0]))
+
+			if normal.Dot(normals[i])/2 < minArea {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for i, vertex := range source.vertices {
+		index, closest := octree.QueryNearest(vertex)
+
+		if index == -1 || closest.Sub(vertex).Mag() > maxProjection {
+			continue
+		}
+
+		for t := 1.0; t >= 0.125; t /= 2 {
+			point := vertex.Add(closest.Sub(vertex).MulScalar(t))
+
+			if isValid(i, point) {
+				source.vertices[i] = point
+				break
+			}
+		}
+	}
+}
+
+// Get the voxels not reached by a flood fill of the empty voxels from the
+// boundary of the octree.
+func (t *voxelTree) getInterior() []bool {
+	inside := make([]bool, len(t.leaves))
+	queue := make([]int, 0)
+
+	for index := range inside {
+		inside[index] = true
+
+		if t.isBoundary(index) && !t.occupied[index] {
+			inside[index] = false
+			queue = append(queue, index)
+		}
+	}
+
+	for len(queue) > 0 {
+		index := queue[0]
+		queue = queue[1:]
+
+		for _, neighbor := range t.getNeighbors(index) {
+			if inside[neighbor] && !t.occupied[neighbor] {
+				inside[neighbor] = false
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return inside
+}
+
+// Tetrahedra of the Freudenthal decomposition of a cube by the corners
+// (bits of the offset i, j, k). Adjacent cubes share the diagonals of their
+// common faces so the tetrahedra are conforming.
+var voxelTetrahedra = [6][4]int{
+	{0, 1, 3, 7},
+	{0, 1, 5, 7},
+	{0, 2, 3, 7},
+	{0, 2, 6, 7},
+	{0, 4, 5, 7},
+	{0, 4, 6, 7},
+}
+
+// Extract the boundary between the inside and outside voxels by marching
+// tetrahedra over the dual of the octree: the (possibly degenerate) cubes
+// between the centers of the voxels around each corner of the voxels. The
+// vertices are at the midpoints of the edges between inside and outside
+// voxels. The surface is closed and manifold if the voxels on the boundary
+// of the octree are outside, and the triangles are oriented from the inside
+// to the outside.
+func (t *voxelTree) getIsosurface(inside []bool) *meshSource {
+	source := meshSource{
+		vertices:        make([]meshx.Vector, 0),
+		vertexColors:    make([]meshx.Color, 0),
+		faces:           make([][]int, 0),
+		facePatches:     make([]int, 0),
+		patches:         make([]string, 0),
+		faceMaterials:   make([]int, 0),
+		materials:       make([]meshx.Material, 0),
+		faceColors:      make([]meshx.Color, 0),
+		smoothingGroups: make([]int, 0),
+	}
+
+	edgeVertices := make(map[[2]int]int)
+
+	// Get the vertex at the midpoint of an edge between voxels.
+	getVertex := func(a, b int) int {
+		key := [2]int{min(a, b), max(a, b)}
+
+		if vertex, ok := edgeVertices[key]; ok {
+			return vertex
+		}
+
+		edgeVertices[key] = len(source.vertices)
+		point := t.getCenter(a).Add(t.getCenter(b)).MulScalar(0.5)
+		source.vertices = append(source.vertices, point)
+		source.vertexColors = append(source.vertexColors, meshx.ColorWhite)
+		return edgeVertices[key]
+	}
+
+	// Get the position of a corner of the unit cube.
+	getCorner := func(c int) meshx.Vector {
+		return meshx.NewVector(float64(c&1), float64(c>>1&1), float64(c>>2&1))
+	}
+
+	// Add a triangle between the edges of the corners of the cube oriented
+	// from the inside to the outside corners. The orientation is taken from
+	// the unit cube since the cubes of the dual may be degenerate. Triangles
+	// collapsed by repeated voxels are skipped.
+	addTriangle := func(corners [8]int, edges [3][2]int, in, out meshx.Vector) {
+		var points [3]meshx.Vector
+
+		face := make([]int, 3)
+
+		for i, edge := range edges {
+			face[i] = getVertex(corners[edge[0]], corners[edge[1]])
+			points[i] = getCorner(edge[0]).Add(getCorner(edge[1])).MulScalar(0.5)
+		}
+
+		if face[0] == face[1] || face[1] == face[2] || face[2] == face[0] {
+			return
+		}
+
+		normal := points[1].Sub(points[0]).Cross(points[2].Sub(points[0]))
+
+		if normal.Dot(out.Sub(in)) < 0 {
+			face[1], face[2] = face[2], face[1]
+		}
+
+		source.faces = append(source.faces, face)
+		source.facePatches = append(source.facePatches, -1)
+		source.faceMaterials = append(source.faceMaterials, -1)
+		source.faceColors = append(source.faceColors, meshx.ColorWhite)
+		source.smoothingGroups = append(source.smoothingGroups, 0)
+	}
+
+	for _, vertex := range t.getSurfaceCorners(inside) {
+		var corners [8]int
+
+		for c := range corners {
+			key := voxelKey{t.depth, vertex[0] - 1 + c&1, vertex[1] - 1 + c>>1&1, vertex[2] - 1 + c>>2&1}
+			corners[c], _ = t.findLeaf(key)
+		}
+
+		for _, tetrahedron := range voxelTetrahedra {
+			ins := make([]int, 0, 4)
+			outs := make([]int, 0, 4)
+
+			for _, c := range tetrahedron {
+				if inside[corners[c]] {
+					ins = append(ins, c)
+				} else {
+					outs = append(outs, c)
+				}
+			}
+
+			if len(ins) == 0 || len(outs) == 0 {
+				continue
+			}
+
+			var in, out meshx.Vector
+
+			for _, c := range ins {
+				in = in.Add(getCorner(c).DivScalar(float64(len(ins))))
+			}
+
+			for _, c := range outs {
+				out = out.Add(getCorner(c).DivScalar(float64(len(outs))))
+			}
+
+			switch {
+			case len(ins) == 1:
+				addTriangle(corners, [3][2]int{{ins[0], outs[0]}, {ins[0], outs[1]}, {ins[0], outs[2]}}, in, out)
+			case len(outs) == 1:
+				addTriangle(corners, [3][2]int{{ins[0], outs[0]}, {ins[1], outs[0]}, {ins[2], outs[0]}}, in, out)
+			default:
+				a, b := [2]int{ins[0], outs[0]}, [2]int{ins[0], outs[1]}
+				c, d := [2]int{ins[1], outs[1]}, [2]int{ins[1], outs[0]}
+				addTriangle(corners, [3][2]int{a, b, c}, in, out)
+				addTriangle(corners, [3][2]int{a, c, d}, in, out)
+			}
+		}
+	}
+
+	return &source
+}
+
+// Get the corners of the voxels (by the coordinates of the deepest level)
+// strictly within the octree whose dual cubes may have both inside and
+// outside voxels, in a deterministic order. Such a corner is a corner of the
+// smallest voxel around it, which touches an inside and an outside voxel.
+func (t *voxelTree) getSurfaceCorners(inside []bool) [][3]int {
+	n := 1 << t.depth
+	isCandidate := make([]bool, len(t.leaves))
+
+	for index := range t.leaves {
+		if !inside[index] {
+			continue
+		}
+
+		for _, neighbor := range t.getTouching(index) {
+			if !inside[neighbor] {
+				isCandidate[index] = true
+				isCandidate[neighbor] = true
+			}
+		}
+	}
+
+	seen := make(map[[3]int]bool)
+	corners := make([][3]int, 0)
+
+	for index, key := range t.leaves {
+		if !isCandidate[index] {
+			continue
+		}
+
+		shift := t.depth - key.level
+
+		for c := range 8 {
+			corner := [3]int{
+				(key.i + c&1) << shift,
+				(key.j + c>>1&1) << shift,
+				(key.k + c>>2&1) << shift,
+			}
+
+			if corner[0] <= 0 || corner[1] <= 0 || corner[2] <= 0 || corner[0] >= n || corner[1] >= n || corner[2] >= n {
+				continue
+			}
+
+			if !seen[corner] {
+				seen[corner] = true
+				corners = append(corners, corner)
+			}
+		}
+	}
+
+	return corners
+}
@@ -0,0 +1,102 @@
+package halfedge
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Get the signed volume enclosed by the triangles of a mesh.
+func getTestVolume(mesh *HalfEdgeMesh) float64 {
+	var volume float64
+
+	for _, triangle := range mesh.GetTriangles() {
+		volume += triangle.P.Dot(triangle.Q.Cross(triangle.R)) / 6
+	}
+
+	return volume
+}
+
+// Assert the faces of a wrap are at least the minimum area.
+func assertWrapMinFaceArea(t *testing.T, wrap *HalfEdgeMesh, size float64) {
+	for i := range wrap.GetNumberOfFaces() {
+		assert.GreaterOrEqual(t, wrap.GetFaceArea(i), WrapMinFaceArea*size*size*(1-1e-9))
+	}
+}
+
+// Test wrapping intersecting cylinders enclosing a cavity.
+func TestWrap(t *testing.T) {
+	mesh := newTestCylinder(t, 1, 0, 1)
+	mesh.Merge(newTestCylinder(t, 0.5, 0.5, 2))
+	mesh.Merge(newTestCylinder(t, 0.2, 0.2, 0.4))
+
+	// Volume of the union of the intersecting cylinders.
+	expected := getTestVolume(newTestCylinder(t, 1, 0, 1)) + getTestVolume(newTestCylinder(t, 0.5, 1, 2))
+
+	wrap, err := mesh.WrapWithOptions(WrapOptions{VoxelSize: 0.05})
+	assert.Empty(t, err)
+	assert.True(t, wrap.IsClosed())
+	assert.True(t, wrap.IsConsistent())
+	assert.Equal(t, 1, len(wrap.GetComponents()))
+	assert.InDelta(t, expected, getTestVolume(wrap), 0.05*expected)
+
+	// Most vertices are projected onto the mesh and the others are held back
+	// within about a voxel of it to keep the faces from degenerating.
+	octree := mesh.BuildOctree()
+	var projected int
+
+	for i := range wrap.GetNumberOfVertices() {
+		point := wrap.GetVertex(i).Point
+		_, closest := octree.QueryNearest(point)
+		assert.Less(t, closest.Sub(point).Mag(), 1.5*0.05)
+
+		if closest.Sub(point).Mag() < 1e-9 {
+			projected++
+		}
+	}
+
+	assert.Greater(t, projected, 3*wrap.GetNumberOfVertices()/4)
+	assertWrapMinFaceArea(t, wrap, 0.05)
+
+	// Without projection the wrap is outside the mesh.
+	wrap, err = mesh.WrapWithOptions(WrapOptions{VoxelSize: 0.05, SkipProjection: true})
+	assert.Empty(t, err)
+	assert.True(t, wrap.IsClosed())
+	assert.Greater(t, getTestVolume(wrap), expected)
+
+	empty, err := NewHalfEdgeMesh(&meshSource{})
+	assert.Empty(t, err)
+
+	_, err = empty.Wrap()
+	assert.ErrorIs(t, err, ErrEmptyWrap)
+}
+
+// Test wrapping with more voxels than the maximum.
+func TestWrapTooManyVoxels(t *testing.T) {
+	mesh := newTestCylinder(t, 1, 0, 1)
+
+	_, err := mesh.WrapWithOptions(WrapOptions{VoxelSize: 1e-6})
+	assert.ErrorIs(t, err, ErrTooManyVoxels)
+
+	_, err = mesh.WrapWithOptions(WrapOptions{VoxelSize: 0.05, MaxVoxels: 1000})
+	assert.ErrorIs(t, err, ErrTooManyVoxels)
+
+	region := NewRefinementRegion(meshx.NewSphere(meshx.Vector{}, 0.1), 0, 1e-6)
+	_, err = mesh.WrapWithOptions(WrapOptions{Refinements: []RefinementRegion{region}})
+	assert.ErrorIs(t, err, ErrTooManyVoxels)
+}
+
+// Test projecting the wrap of a box onto its edges and corners without
+// collapsing the faces.
+func TestWrapProjectionDegenerate(t *testing.T) {
+	mesh, err := NewHalfEdgeMeshFromOBJPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	wrap, err := mesh.WrapWithOptions(WrapOptions{VoxelSize: 0.1})
+	assert.Empty(t, err)
+	assert.True(t, wrap.IsClosed())
+	assert.True(t, wrap.IsConsistent())
+	assert.InDelta(t, 1.0, getTestVolume(wrap), 0.02)
+	assertWrapMinFaceArea(t, wrap, 0.1)
+}
@@ -0,0 +1,19 @@
+package meshx
+
+// Intersection of a ray with a primitive.
+type Hit struct {
+	// Index of the intersected item when returned from a spatial query.
+	Index int
+
+	// Distance along the ray in units of the ray direction.
+	Distance float64
+
+	// Point of intersection.
+	Point Vector
+
+	// Unit normal of the primitive at the point of intersection.
+	Normal Vector
+
+	// Whether the ray enters the primitive (opposes the normal) or exits it.
+	Entering bool
+}
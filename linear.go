@@ -0,0 +1,65 @@
+package meshx
+
+import (
+	"math"
+)
+
+// Solve the linear system Ax = b by Gaussian elimination with partial
+// pivoting. The second return value is false if the system is singular: a
+// pivot is within roundoff of zero relative to the largest entry of A.
+func SolveLinear(matrix [][]float64, rhs []float64) ([]float64, bool) {
+	var norm float64
+
+	n := len(matrix)
+	a := make([][]float64, n)
+
+	for i := range a {
+		a[i] = make([]float64, n+1)
+		copy(a[i], matrix[i])
+		a[i][n] = rhs[i]
+
+		for _, value := range matrix[i] {
+			norm = max(norm, math.Abs(value))
+		}
+	}
+
+	tolerance := float64(n) * 0x1p-52 * norm
+
+	for col := 0; col < n; col++ {
+		pivot := col
+
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+
+		if !(math.Abs(a[pivot][col]) > tolerance) {
+			return nil, false
+		}
+
+		a[col], a[pivot] = a[pivot], a[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+
+			for k := col; k <= n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+		}
+	}
+
+	x := make([]float64, n)
+
+	for row := n - 1; row >= 0; row-- {
+		sum := a[row][n]
+
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+
+		x[row] = sum / a[row][row]
+	}
+
+	return x, true
+}
@@ -0,0 +1,26 @@
+package meshx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test solving a linear system requiring pivoting.
+func TestSolveLinear(t *testing.T) {
+	matrix := [][]float64{{0, 2, 1}, {1, 1, 0}, {2, 0, 3}}
+	x, ok := SolveLinear(matrix, []float64{7, 3, 11})
+	assert.True(t, ok)
+	assert.InDeltaSlice(t, []float64{1, 2, 3}, x, 1e-12)
+
+	_, ok = SolveLinear([][]float64{{1, 2}, {2, 4}}, []float64{1, 2})
+	assert.False(t, ok)
+
+	// Singular up to roundoff, and regular with tiny entries.
+	_, ok = SolveLinear([][]float64{{3, 1}, {1, 1.0 / 3}}, []float64{1, 2})
+	assert.False(t, ok)
+
+	x, ok = SolveLinear([][]float64{{1e-200, 0}, {0, 2e-200}}, []float64{1e-200, 1e-200})
+	assert.True(t, ok)
+	assert.InDeltaSlice(t, []float64{1, 0.5}, x, 1e-12)
+}
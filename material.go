@@ -0,0 +1,127 @@
+package meshx
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	PrefixNewMaterial = "newmtl"
+)
+
+var (
+	ErrInvalidMaterial = errors.New("invalid material")
+)
+
+// Material defined in an MTL file. The statements of the material (colors,
+// texture maps, etc.) are retained verbatim so they survive a round trip
+// without being interpreted.
+type Material struct {
+	Name       string
+	Statements []string
+}
+
+// Get the value of the first statement with the keyword. The boolean is
+// false if the material has no such statement.
+func (m Material) GetStatement(keyword string) (string, bool) {
+	for _, statement := range m.Statements {
+		fields := strings.SplitN(statement, " ", 2)
+
+		if fields[0] == keyword {
+			if len(fields) == 1 {
+				return "", true
+			}
+			return strings.TrimSpace(fields[1]), true
+		}
+	}
+
+	return "", false
+}
+
+// Read the materials of an MTL file.
+func ReadMTL(reader io.Reader) ([]Material, error) {
+	count := 1
+	materials := make([]Material, 0)
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		data := bytes.TrimSpace(scanner.Bytes())
+
+		if len(data) != 0 && data[0] != '#' {
+			fields := bytes.Fields(data)
+
+			if string(fields[0]) == PrefixNewMaterial {
+				name := bytes.TrimSpace(data[len(PrefixNewMaterial):])
+
+				if len(name) == 0 {
+					return nil, fmt.Errorf("line %d: %v", count, ErrInvalidMaterial)
+				}
+
+				materials = append(materials, Material{Name: string(name)})
+			} else if len(materials) != 0 {
+				statement := string(fields[0])
+
+				if rest := bytes.TrimSpace(data[len(fields[0]):]); len(rest) != 0 {
+					statement += " " + string(rest)
+				}
+
+				material := &materials[len(materials)-1]
+				material.Statements = append(material.Statements, statement)
+			}
+		}
+
+		count++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return materials, nil
+}
+
+// Read the materials of an MTL file from a file path.
+func ReadMTLFromPath(path string) ([]Material, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadMTL(file)
+}
+
+// Write the materials to an MTL file.
+func WriteMTL(writer io.Writer, materials []Material) error {
+	buffer := bufio.NewWriter(writer)
+
+	for i, material := range materials {
+		if i != 0 {
+			buffer.WriteString("\n")
+		}
+
+		buffer.WriteString(fmt.Sprintf("newmtl %s\n", material.Name))
+
+		for _, statement := range material.Statements {
+			buffer.WriteString(statement + "\n")
+		}
+	}
+
+	return buffer.Flush()
+}
+
+// Write the materials to an MTL file path.
+func WriteMTLToPath(path string, materials []Material) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return WriteMTL(file, materials)
+}
@@ -0,0 +1,55 @@
+package meshx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Read the materials of an MTL file from path.
+func TestReadMTLFromPath(t *testing.T) {
+	materials, err := ReadMTLFromPath("testdata/box.materials.mtl")
+	assert.Empty(t, err)
+	assert.Equal(t, 3, len(materials))
+
+	assert.Equal(t, "red", materials[0].Name)
+	assert.Equal(t, []string{"Ka 0.0 0.0 0.0", "Kd 1.0 0.0 0.0", "map_Kd textures/red.png"}, materials[0].Statements)
+
+	value, ok := materials[1].GetStatement("illum")
+	assert.True(t, ok)
+	assert.Equal(t, "2", value)
+
+	_, ok = materials[2].GetStatement("map_Kd")
+	assert.False(t, ok)
+}
+
+// Read an MTL file with a material missing its name.
+func TestReadMTLInvalid(t *testing.T) {
+	_, err := ReadMTL(bytes.NewBufferString("newmtl\nKd 1 0 0\n"))
+	assert.EqualError(t, err, "line 1: invalid material")
+}
+
+// Write and read the materials of an MTL file.
+func TestWriteMTL(t *testing.T) {
+	materials := []Material{
+		{Name: "red", Statements: []string{"Kd 1 0 0", "map_Kd red.png"}},
+		{Name: "blue", Statements: []string{"Kd 0 0 1"}},
+	}
+
+	var expected string
+	expected += "newmtl red\n"
+	expected += "Kd 1 0 0\n"
+	expected += "map_Kd red.png\n"
+	expected += "\n"
+	expected += "newmtl blue\n"
+	expected += "Kd 0 0 1\n"
+
+	var buffer bytes.Buffer
+	assert.Empty(t, WriteMTL(&buffer, materials))
+	assert.Equal(t, expected, buffer.String())
+
+	result, err := ReadMTL(&buffer)
+	assert.Empty(t, err)
+	assert.Equal(t, materials, result)
+}
@@ -0,0 +1,201 @@
+package meshx
+
+import (
+	"math"
+)
+
+// Affine transformation matrix in homogeneous coordinates (row-major).
+type Matrix4 [4][4]float64
+
+// Construct the identity Matrix4.
+func NewIdentityMatrix4() Matrix4 {
+	return Matrix4{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// Construct a translation Matrix4.
+func NewTranslationMatrix4(offset Vector) Matrix4 {
+	m := NewIdentityMatrix4()
+	m[0][3] = offset[0]
+	m[1][3] = offset[1]
+	m[2][3] = offset[2]
+	return m
+}
+
+// Construct a scaling Matrix4 (about the origin).
+func NewScaleMatrix4(scale Vector) Matrix4 {
+	m := NewIdentityMatrix4()
+	m[0][0] = scale[0]
+	m[1][1] = scale[1]
+	m[2][2] = scale[2]
+	return m
+}
+
+// Construct a rotation Matrix4 about an axis through the origin by an angle
+// in radians (right-hand rule).
+func NewRotationMatrix4(axis Vector, angle float64) Matrix4 {
+	u := axis.Unit()
+	c := math.Cos(angle)
+	s := math.Sin(angle)
+	t := 1 - c
+
+	return Matrix4{
+		{t*u[0]*u[0] + c, t*u[0]*u[1] - s*u[2], t*u[0]*u[2] + s*u[1], 0},
+		{t*u[0]*u[1] + s*u[2], t*u[1]*u[1] + c, t*u[1]*u[2] - s*u[0], 0},
+		{t*u[0]*u[2] - s*u[1], t*u[1]*u[2] + s*u[0], t*u[2]*u[2] + c, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// Construct a rotation Matrix4 from a unit quaternion (w, x, y, z).
+func NewRotationMatrix4FromQuaternion(w, x, y, z float64) Matrix4 {
+	return Matrix4{
+		{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y), 0},
+		{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x), 0},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y), 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// Multiply two matrices m * n. The resulting transform applies n first.
+func (m Matrix4) Mul(n Matrix4) Matrix4 {
+	var r Matrix4
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 4; k++ {
+				r[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+
+	return r
+}
+
+// Transform a point (w = 1).
+func (m Matrix4) MulPoint(p Vector) Vector {
+	var r Vector
+
+	for i := 0; i < 3; i++ {
+		r[i] = m[i][0]*p[0] + m[i][1]*p[1] + m[i][2]*p[2] + m[i][3]
+	}
+
+	return r
+}
+
+// Transform a direction (w = 0).
+func (m Matrix4) MulVector(v Vector) Vector {
+	var r Vector
+
+	for i := 0; i < 3; i++ {
+		r[i] = m[i][0]*v[0] + m[i][1]*v[1] + m[i][2]*v[2]
+	}
+
+	return r
+}
+
+// Transform a normal by the inverse transpose such that it remains
+// perpendicular to the transformed surface under non-uniform scaling and
+// shear. The result is a unit vector, or zero if the matrix is singular or
+// the normal is zero.
+func (m Matrix4) MulNormal(n Vector) Vector {
+	inv, ok := m.Inverse()
+
+	if !ok {
+		return Vector{}
+	}
+
+	r := inv.Transpose().MulVector(n)
+
+	if mag := r.Mag(); mag > 0 {
+		return r.DivScalar(mag)
+	}
+
+	return Vector{}
+}
+
+// Compute the transpose.
+func (m Matrix4) Transpose() Matrix4 {
+	var r Matrix4
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			r[i][j] = m[j][i]
+		}
+	}
+
+	return r
+}
+
+// Compute the determinant.
+func (m Matrix4) Determinant() float64 {
+	var det float64
+
+	for j := 0; j < 4; j++ {
+		sign := 1.0
+
+		if j%2 == 1 {
+			sign = -1.0
+		}
+
+		det += sign * m[0][j] * m.minor(0, j)
+	}
+
+	return det
+}
+
+// Compute the inverse. The second return value is false if the matrix is
+// singular.
+func (m Matrix4) Inverse() (Matrix4, bool) {
+	var r Matrix4
+
+	det := m.Determinant()
+
+	if det == 0 {
+		return r, false
+	}
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			sign := 1.0
+
+			if (i+j)%2 == 1 {
+				sign = -1.0
+			}
+
+			r[j][i] = sign * m.minor(i, j) / det
+		}
+	}
+
+	return r, true
+}
+
+// Compute the determinant of the 3x3 minor excluding a row and column.
+func (m Matrix4) minor(row, col int) float64 {
+	var a [3][3]float64
+
+	for i, r := 0, 0; i < 4; i++ {
+		if i == row {
+			continue
+		}
+
+		for j, c := 0, 0; j < 4; j++ {
+			if j == col {
+				continue
+			}
+
+			a[r][c] = m[i][j]
+			c++
+		}
+
+		r++
+	}
+
+	return a[0][0]*(a[1][1]*a[2][2]-a[1][2]*a[2][1]) -
+		a[0][1]*(a[1][0]*a[2][2]-a[1][2]*a[2][0]) +
+		a[0][2]*(a[1][0]*a[2][1]-a[1][1]*a[2][0])
+}
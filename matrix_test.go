@@ -0,0 +1,66 @@
+package meshx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test rotating a point about an axis.
+func TestMatrix4Rotation(t *testing.T) {
+	m := NewRotationMatrix4(NewVector(0, 0, 1), math.Pi/2)
+	p := m.MulPoint(NewVector(1, 0, 0))
+
+	assert.InDelta(t, 0.0, p[0], 1e-12)
+	assert.InDelta(t, 1.0, p[1], 1e-12)
+	assert.InDelta(t, 0.0, p[2], 1e-12)
+	assert.InDelta(t, 1.0, m.Determinant(), 1e-12)
+}
+
+// Test composing a translation and scale.
+func TestMatrix4Mul(t *testing.T) {
+	m := NewTranslationMatrix4(NewVector(1, 2, 3)).Mul(NewScaleMatrix4(NewVector(2, 2, 2)))
+
+	assert.Equal(t, NewVector(3, 4, 5), m.MulPoint(NewVector(1, 1, 1)))
+	assert.Equal(t, NewVector(2, 2, 2), m.MulVector(NewVector(1, 1, 1)))
+	assert.Equal(t, 8.0, m.Determinant())
+}
+
+// Test inverting a transform.
+func TestMatrix4Inverse(t *testing.T) {
+	m := NewTranslationMatrix4(NewVector(1, 2, 3)).
+		Mul(NewRotationMatrix4(NewVector(1, 1, 0), 0.3)).
+		Mul(NewScaleMatrix4(NewVector(1, 2, 4)))
+
+	inv, ok := m.Inverse()
+	assert.True(t, ok)
+
+	identity := m.Mul(inv)
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			assert.InDelta(t, NewIdentityMatrix4()[i][j], identity[i][j], 1e-12)
+		}
+	}
+
+	_, ok = NewScaleMatrix4(NewVector(1, 0, 1)).Inverse()
+	assert.False(t, ok)
+}
+
+// Test transforming a normal by a non-uniform scale and shear.
+func TestMatrix4MulNormal(t *testing.T) {
+	m := NewScaleMatrix4(NewVector(1, 4, -2))
+	m[0][1] = 3
+
+	// The normal remains perpendicular to the transformed tangents.
+	normal := NewVector(1, 2, 3).Unit()
+	u := NewVector(2, -1, 0)
+	v := NewVector(3, 0, -1)
+	n := m.MulNormal(normal)
+
+	assert.InDelta(t, 1.0, n.Mag(), 1e-12)
+	assert.InDelta(t, 0.0, n.Dot(m.MulVector(u)), 1e-12)
+	assert.InDelta(t, 0.0, n.Dot(m.MulVector(v)), 1e-12)
+	assert.Equal(t, Vector{}, NewScaleMatrix4(NewVector(1, 0, 1)).MulNormal(normal))
+}
@@ -1,5 +1,9 @@
 package meshx
 
+import (
+	"slices"
+)
+
 // Generic mesh reader interface.
 type MeshReader interface {
 	Read() error
@@ -21,3 +25,83 @@ type MeshWriter interface {
 	SetFacePatches([]int)
 	SetPatches([]string)
 }
+
+// Optional interface of a MeshReader retaining the material of each face.
+type MaterialReader interface {
+	GetNumberOfMaterials() int
+	GetMaterial(int) Material
+	GetFaceMaterial(int) int
+}
+
+// Optional interface of a MeshReader retaining the color of each vertex
+// and/or face.
+type ColorReader interface {
+	HasVertexColors() bool
+	GetVertexColor(int) Color
+	HasFaceColors() bool
+	GetFaceColor(int) Color
+}
+
+// Optional interface of a MeshReader retaining the smoothing group of each
+// face. Faces of the same nonzero group are smoothed where they share a
+// vertex and a face of group zero is not smoothed.
+type SmoothingGroupReader interface {
+	GetFaceSmoothingGroup(int) int
+}
+
+// Optional interface of a MeshReader retaining the patches tagged as baffles
+// (two-sided internal surfaces).
+type BaffleReader interface {
+	IsPatchBaffle(int) bool
+}
+
+// Kind of the elements of a named set.
+type SetKind int
+
+const (
+	FaceSet SetKind = iota
+	VertexSet
+)
+
+// Named selection of faces or vertices of a mesh (e.g. a refinement zone)
+// by their sorted indices.
+type Set struct {
+	Name    string
+	Kind    SetKind
+	Indices []int
+}
+
+// Optional interface of a MeshReader retaining named sets.
+type SetReader interface {
+	GetSets() []Set
+}
+
+// Transformation applied by a reader to the vertices (and the orientation of
+// the faces if mirrored) while parsing rather than in a pass afterward. The
+// zero value leaves the coordinates unchanged.
+type readTransform struct {
+	matrix     Matrix4
+	isEnabled  bool
+	isMirrored bool
+}
+
+// Construct the readTransform of an affine Matrix4.
+func newReadTransform(matrix Matrix4) readTransform {
+	return readTransform{matrix, true, matrix.Determinant() < 0}
+}
+
+// Transform a point.
+func (t readTransform) transformPoint(point Vector) Vector {
+	if !t.isEnabled {
+		return point
+	}
+
+	return t.matrix.MulPoint(point)
+}
+
+// Reverse a face in place (keeping the first vertex) if mirrored.
+func (t readTransform) transformFace(face []int) {
+	if t.isMirrored && len(face) > 1 {
+		slices.Reverse(face[1:])
+	}
+}
@@ -0,0 +1,145 @@
+package meshx
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Access every element of a mesh read without an error (e.g. by a fuzz
+// target) so an invalid mesh panics. The faces must reference existing
+// vertices and patches unless only the accessors are checked.
+func checkTestMeshReader(t *testing.T, reader MeshReader, checkIndices bool) {
+	var count int
+
+	for i := range reader.GetNumberOfVertices() {
+		reader.GetVertex(i)
+	}
+
+	for i := range reader.GetNumberOfPatches() {
+		reader.GetPatch(i)
+	}
+
+	for i := range reader.GetNumberOfFaces() {
+		face := reader.GetFace(i)
+		patch := reader.GetFacePatch(i)
+		count += len(face)
+
+		if !checkIndices {
+			continue
+		}
+
+		assert.GreaterOrEqual(t, len(face), 3)
+		assert.True(t, patch >= -1 && patch < reader.GetNumberOfPatches())
+
+		for _, vertex := range face {
+			assert.True(t, vertex >= 0 && vertex < reader.GetNumberOfVertices())
+		}
+	}
+
+	assert.Equal(t, count, reader.GetNumberOfFaceEdges())
+}
+
+// Random indexed mesh for property-based round trip tests.
+type testMesh struct {
+	vertices    []Vector
+	faces       [][]int
+	facePatches []int
+	patches     []string
+}
+
+// Generate a random mesh of polygons referencing random vertices (not
+// necessarily manifold) with some faces without a patch.
+func newRandomTestMesh(rng *rand.Rand) testMesh {
+	var mesh testMesh
+
+	for range 3 + rng.Intn(32) {
+		vertex := NewVector(rng.NormFloat64(), rng.NormFloat64()*1e3, rng.NormFloat64()*1e-3)
+		mesh.vertices = append(mesh.vertices, vertex)
+	}
+
+	for i := range 1 + rng.Intn(4) {
+		mesh.patches = append(mesh.patches, string(rune('a'+i)))
+	}
+
+	for range 1 + rng.Intn(32) {
+		face := rng.Perm(len(mesh.vertices))[:3+rng.Intn(min(len(mesh.vertices)-2, 4))]
+		mesh.faces = append(mesh.faces, face)
+		mesh.facePatches = append(mesh.facePatches, rng.Intn(len(mesh.patches)+1)-1)
+	}
+
+	return mesh
+}
+
+// Get a copy of the mesh transformed by an affine Matrix4. The faces are
+// reversed (keeping the first vertex) if the transformation is mirrored.
+func (m testMesh) transform(matrix Matrix4) testMesh {
+	transformed := m
+	transformed.vertices = make([]Vector, len(m.vertices))
+
+	for i, vertex := range m.vertices {
+		transformed.vertices[i] = matrix.MulPoint(vertex)
+	}
+
+	if matrix.Determinant() < 0 {
+		transformed.faces = NewConversion(CoordinateSystem{}, CoordinateSystem{LeftHanded: true}).ConvertFaces(m.faces)
+	}
+
+	return transformed
+}
+
+// Matrices to test reading with a transformation: a rigid motion and a
+// mirrored scaling.
+var testTransforms = []Matrix4{
+	NewTranslationMatrix4(NewVector(1, 2, 3)).Mul(NewRotationMatrix4(NewVector(0, 0, 1), 0.3)),
+	NewScaleMatrix4(NewVector(-2, 1, 0.5)),
+}
+
+// Set the mesh of a writer.
+func (m testMesh) setWriter(writer MeshWriter) {
+	writer.SetVertices(m.vertices)
+	writer.SetFaces(m.faces)
+	writer.SetFacePatches(m.facePatches)
+	writer.SetPatches(m.patches)
+}
+
+// Assert a mesh read back equals the mesh within a relative tolerance. The
+// faces are compared in any order (e.g. grouped by patch) and their patches
+// by name (unused patches may be dropped).
+func (m testMesh) assertReader(t *testing.T, reader MeshReader, tolerance float64) {
+	assert.Equal(t, len(m.vertices), reader.GetNumberOfVertices())
+
+	for i, vertex := range m.vertices {
+		assert.InDelta(t, 0, vertex.Sub(reader.GetVertex(i)).Mag(), tolerance*(1+vertex.Mag()))
+	}
+
+	expected := make([]string, len(m.faces))
+	actual := make([]string, reader.GetNumberOfFaces())
+
+	for i, face := range m.faces {
+		var patch string
+
+		if m.facePatches[i] != -1 {
+			patch = m.patches[m.facePatches[i]]
+		}
+
+		expected[i] = fmt.Sprint(face, patch)
+	}
+
+	for i := range actual {
+		var patch string
+
+		if index := reader.GetFacePatch(i); index != -1 {
+			patch = reader.GetPatch(index)
+		}
+
+		actual[i] = fmt.Sprint(reader.GetFace(i), patch)
+	}
+
+	sort.Strings(expected)
+	sort.Strings(actual)
+	assert.Equal(t, expected, actual)
+}
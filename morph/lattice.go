@@ -0,0 +1,124 @@
+package morph
+
+import (
+	"errors"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrInvalidLatticeShape = errors.New("invalid lattice shape")
+)
+
+// Free-form deformation (FFD) lattice of control points over an AABB. The
+// control points are uniformly spaced and deform the enclosed space by the
+// trivariate Bernstein polynomials of their displacements (Sederberg and
+// Parry). Points outside the AABB are not deformed.
+type Lattice struct {
+	aabb          meshx.AABB
+	shape         [3]int
+	displacements []meshx.Vector
+}
+
+// Construct a Lattice over an AABB with a number of control points along
+// each axis (at least two).
+func NewLattice(aabb meshx.AABB, shape [3]int) (*Lattice, error) {
+	for i := range shape {
+		if shape[i] < 2 || aabb.HalfSize[i] <= 0 {
+			return nil, ErrInvalidLatticeShape
+		}
+	}
+
+	return &Lattice{
+		aabb:          aabb,
+		shape:         shape,
+		displacements: make([]meshx.Vector, shape[0]*shape[1]*shape[2]),
+	}, nil
+}
+
+// Get the number of control points along each axis.
+func (l *Lattice) GetShape() [3]int {
+	return l.shape
+}
+
+// Get the index of a control point.
+func (l *Lattice) getIndex(i, j, k int) int {
+	return (k*l.shape[1]+j)*l.shape[0] + i
+}
+
+// Get the (displaced) position of a control point.
+func (l *Lattice) GetControlPoint(i, j, k int) meshx.Vector {
+	var point meshx.Vector
+	minBound := l.aabb.GetMinBound()
+
+	for axis, index := range [3]int{i, j, k} {
+		t := float64(index) / float64(l.shape[axis]-1)
+		point[axis] = minBound[axis] + 2*l.aabb.HalfSize[axis]*t
+	}
+
+	return point.Add(l.displacements[l.getIndex(i, j, k)])
+}
+
+// Get the displacement of a control point.
+func (l *Lattice) GetDisplacement(i, j, k int) meshx.Vector {
+	return l.displacements[l.getIndex(i, j, k)]
+}
+
+// Set the displacement of a control point.
+func (l *Lattice) SetDisplacement(i, j, k int, displacement meshx.Vector) {
+	l.displacements[l.getIndex(i, j, k)] = displacement
+}
+
+// Implement the Deformation interface.
+func (l *Lattice) Deform(point meshx.Vector) meshx.Vector {
+	var bases [3][]float64
+	minBound := l.aabb.GetMinBound()
+
+	for axis := range bases {
+		t := (point[axis] - minBound[axis]) / (2 * l.aabb.HalfSize[axis])
+
+		if t < 0 || t > 1 {
+			return point
+		}
+
+		bases[axis] = getBernstein(l.shape[axis]-1, t)
+	}
+
+	var offset meshx.Vector
+
+	for k, bk := range bases[2] {
+		for j, bj := range bases[1] {
+			for i, bi := range bases[0] {
+				displacement := l.displacements[l.getIndex(i, j, k)]
+				offset = offset.Add(displacement.MulScalar(bi * bj * bk))
+			}
+		}
+	}
+
+	return point.Add(offset)
+}
+
+// Get the Bernstein polynomials of a degree at a parameter in [0, 1].
+func getBernstein(degree int, t float64) []float64 {
+	bases := make([]float64, degree+1)
+	bases[0] = 1
+
+	// Build the polynomials of increasing degree by de Casteljau.
+	for n := 1; n <= degree; n++ {
+		for i := n; i >= 0; i-- {
+			var value float64
+
+			if i < n {
+				value = (1 - t) * bases[i]
+			}
+
+			if i > 0 {
+				value += t * bases[i-1]
+			}
+
+			bases[i] = value
+		}
+	}
+
+	return bases
+}
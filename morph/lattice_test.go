@@ -0,0 +1,62 @@
+package morph
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the deformation of a trilinear lattice by a corner control point.
+func TestLatticeDeform(t *testing.T) {
+	aabb := meshx.NewAABB(meshx.NewVector(0.5, 0.5, 0.5), meshx.NewVector(0.5, 0.5, 0.5))
+	lattice, err := NewLattice(aabb, [3]int{2, 2, 2})
+	assert.Empty(t, err)
+
+	point := meshx.NewVector(0.25, 0.5, 0.75)
+	assert.Equal(t, point, lattice.Deform(point))
+
+	lattice.SetDisplacement(1, 1, 1, meshx.NewVector(0, 0, 0.8))
+	assert.Equal(t, meshx.NewVector(1, 1, 1.8), lattice.GetControlPoint(1, 1, 1))
+	assert.Equal(t, meshx.NewVector(0, 0, 0.8), lattice.GetDisplacement(1, 1, 1))
+
+	deformed := lattice.Deform(meshx.NewVector(1, 1, 1))
+	assert.InDelta(t, 1.8, deformed[2], 1e-12)
+
+	deformed = lattice.Deform(meshx.NewVector(0.5, 0.5, 0.5))
+	assert.InDelta(t, 0.6, deformed[2], 1e-12)
+
+	// Points outside the lattice are not deformed.
+	outside := meshx.NewVector(1.5, 1, 1)
+	assert.Equal(t, outside, lattice.Deform(outside))
+}
+
+// Test the deformation of a higher degree lattice is smooth and local to
+// the displaced control point.
+func TestLatticeDeformHigherDegree(t *testing.T) {
+	aabb := meshx.NewAABB(meshx.NewVector(0, 0, 0), meshx.NewVector(2, 1, 1))
+	lattice, err := NewLattice(aabb, [3]int{5, 3, 3})
+	assert.Empty(t, err)
+	assert.Equal(t, [3]int{5, 3, 3}, lattice.GetShape())
+
+	lattice.SetDisplacement(2, 1, 1, meshx.NewVector(0, 1, 0))
+	center := lattice.Deform(meshx.NewVector(0, 0, 0))
+	side := lattice.Deform(meshx.NewVector(1, 0, 0))
+	assert.Greater(t, center[1], side[1])
+	assert.Greater(t, side[1], 0.0)
+
+	// The boundary of the lattice is fixed by the boundary control points.
+	boundary := meshx.NewVector(2, 0.3, 0.2)
+	assert.InDelta(t, 0, lattice.Deform(boundary).Sub(boundary).Mag(), 1e-12)
+}
+
+// Test a lattice with too few control points.
+func TestNewLatticeInvalid(t *testing.T) {
+	aabb := meshx.NewAABB(meshx.NewVector(0, 0, 0), meshx.NewVector(1, 1, 1))
+	_, err := NewLattice(aabb, [3]int{2, 1, 2})
+	assert.ErrorIs(t, err, ErrInvalidLatticeShape)
+
+	flat := meshx.NewAABB(meshx.NewVector(0, 0, 0), meshx.NewVector(1, 1, 0))
+	_, err = NewLattice(flat, [3]int{2, 2, 2})
+	assert.ErrorIs(t, err, ErrInvalidLatticeShape)
+}
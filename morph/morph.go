@@ -0,0 +1,13 @@
+// Package morph implements smooth deformations of space (free-form
+// deformation lattices and radial basis functions) to morph meshes by
+// control point or landmark displacements.
+package morph
+
+import (
+	"github.com/ajcurley/meshx-go"
+)
+
+// Smooth deformation of space mapping points to their deformed positions.
+type Deformation interface {
+	Deform(point meshx.Vector) meshx.Vector
+}
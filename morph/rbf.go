@@ -0,0 +1,137 @@
+package morph
+
+import (
+	"errors"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrInvalidLandmarks = errors.New("invalid landmarks")
+	ErrSingularRBF      = errors.New("singular radial basis function system")
+)
+
+// Options to construct an RBF.
+type RBFOptions struct {
+	// Radius of support of the basis functions. Zero uses the global cubic
+	// basis with an affine term, so the landmarks may also translate,
+	// rotate or scale the whole space. Otherwise the compact Wendland basis
+	// is used and points farther than the radius from every landmark are not
+	// deformed.
+	Radius float64
+}
+
+// Radial basis function (RBF) interpolation of the displacements of source
+// landmarks to target landmarks.
+type RBF struct {
+	sources []meshx.Vector
+	weights []meshx.Vector
+	affine  [4]meshx.Vector
+	radius  float64
+}
+
+// Construct an RBF from pairs of source and target landmarks. See
+// NewRBFWithOptions.
+func NewRBF(sources, targets []meshx.Vector) (*RBF, error) {
+	return NewRBFWithOptions(sources, targets, RBFOptions{})
+}
+
+// Construct an RBF mapping each source landmark exactly to its target and
+// deforming the space in between smoothly. The global basis requires at
+// least four sources not in a plane. Duplicate sources are singular.
+func NewRBFWithOptions(sources, targets []meshx.Vector, options RBFOptions) (*RBF, error) {
+	if len(sources) == 0 || len(sources) != len(targets) {
+		return nil, ErrInvalidLandmarks
+	}
+
+	n := len(sources)
+	size := n
+
+	if options.Radius <= 0 {
+		size += 4
+	}
+
+	rbf := &RBF{
+		sources: sources,
+		weights: make([]meshx.Vector, n),
+		radius:  options.Radius,
+	}
+
+	// Assemble the interpolation conditions (and the orthogonality of the
+	// weights to the affine term).
+	matrix := make([][]float64, size)
+
+	for i := range matrix {
+		matrix[i] = make([]float64, size)
+	}
+
+	for i, p := range sources {
+		for j, q := range sources {
+			matrix[i][j] = rbf.getBasis(p.Sub(q).Mag())
+		}
+
+		if size > n {
+			row := [4]float64{1, p[0], p[1], p[2]}
+
+			for k, value := range row {
+				matrix[i][n+k] = value
+				matrix[n+k][i] = value
+			}
+		}
+	}
+
+	for axis := 0; axis < 3; axis++ {
+		rhs := make([]float64, size)
+
+		for i := range sources {
+			rhs[i] = targets[i][axis] - sources[i][axis]
+		}
+
+		x, ok := meshx.SolveLinear(matrix, rhs)
+
+		if !ok {
+			return nil, ErrSingularRBF
+		}
+
+		for i := range rbf.weights {
+			rbf.weights[i][axis] = x[i]
+		}
+
+		for k := n; k < size; k++ {
+			rbf.affine[k-n][axis] = x[k]
+		}
+	}
+
+	return rbf, nil
+}
+
+// Evaluate the basis function at a distance.
+func (r *RBF) getBasis(distance float64) float64 {
+	if r.radius <= 0 {
+		return distance * distance * distance
+	}
+
+	t := distance / r.radius
+
+	if t >= 1 {
+		return 0
+	}
+
+	return (1 - t) * (1 - t) * (1 - t) * (1 - t) * (4*t + 1)
+}
+
+// Implement the Deformation interface.
+func (r *RBF) Deform(point meshx.Vector) meshx.Vector {
+	offset := r.affine[0].
+		Add(r.affine[1].MulScalar(point[0])).
+		Add(r.affine[2].MulScalar(point[1])).
+		Add(r.affine[3].MulScalar(point[2]))
+
+	for i, source := range r.sources {
+		if basis := r.getBasis(point.Sub(source).Mag()); basis != 0 {
+			offset = offset.Add(r.weights[i].MulScalar(basis))
+		}
+	}
+
+	return point.Add(offset)
+}
@@ -0,0 +1,93 @@
+package morph
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Get the corners of the unit cube.
+func getTestCorners() []meshx.Vector {
+	corners := make([]meshx.Vector, 8)
+
+	for i := range corners {
+		corners[i] = meshx.NewVector(float64(i&1), float64(i>>1&1), float64(i>>2&1))
+	}
+
+	return corners
+}
+
+// Test the RBF interpolates the landmarks.
+func TestRBFDeform(t *testing.T) {
+	sources := getTestCorners()
+	targets := getTestCorners()
+	targets[7] = meshx.NewVector(1.2, 1.3, 1.5)
+
+	rbf, err := NewRBF(sources, targets)
+	assert.Empty(t, err)
+
+	for i, source := range sources {
+		assert.InDelta(t, 0, rbf.Deform(source).Sub(targets[i]).Mag(), 1e-9)
+	}
+
+	center := rbf.Deform(meshx.NewVector(0.5, 0.5, 0.5))
+	assert.Greater(t, center[2], 0.5)
+	assert.Less(t, center[2], 1.0)
+}
+
+// Test the global RBF reproduces an affine transformation.
+func TestRBFDeformAffine(t *testing.T) {
+	sources := getTestCorners()
+	targets := make([]meshx.Vector, len(sources))
+	offset := meshx.NewVector(1, -2, 3)
+
+	for i, source := range sources {
+		targets[i] = source.MulScalar(2).Add(offset)
+	}
+
+	rbf, err := NewRBF(sources, targets)
+	assert.Empty(t, err)
+
+	point := meshx.NewVector(5, -4, 0.5)
+	expected := point.MulScalar(2).Add(offset)
+	assert.InDelta(t, 0, rbf.Deform(point).Sub(expected).Mag(), 1e-9)
+}
+
+// Test the compact RBF does not deform points beyond its radius.
+func TestRBFDeformCompact(t *testing.T) {
+	sources := []meshx.Vector{meshx.NewVector(0, 0, 0), meshx.NewVector(1, 0, 0)}
+	targets := []meshx.Vector{meshx.NewVector(0, 0, 0.5), meshx.NewVector(1, 0, 0)}
+
+	rbf, err := NewRBFWithOptions(sources, targets, RBFOptions{Radius: 0.75})
+	assert.Empty(t, err)
+
+	for i, source := range sources {
+		assert.InDelta(t, 0, rbf.Deform(source).Sub(targets[i]).Mag(), 1e-12)
+	}
+
+	far := meshx.NewVector(3, 0, 0)
+	assert.Equal(t, far, rbf.Deform(far))
+
+	near := rbf.Deform(meshx.NewVector(-0.25, 0, 0))
+	assert.Greater(t, near[2], 0.0)
+}
+
+// Test invalid and singular landmarks.
+func TestNewRBFInvalid(t *testing.T) {
+	corners := getTestCorners()
+
+	_, err := NewRBF(corners, corners[:4])
+	assert.ErrorIs(t, err, ErrInvalidLandmarks)
+
+	_, err = NewRBF(nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidLandmarks)
+
+	// The affine term is undetermined by coplanar landmarks.
+	_, err = NewRBF(corners[:4], corners[:4])
+	assert.ErrorIs(t, err, ErrSingularRBF)
+
+	duplicates := []meshx.Vector{corners[0], corners[0]}
+	_, err = NewRBFWithOptions(duplicates, duplicates, RBFOptions{Radius: 1})
+	assert.ErrorIs(t, err, ErrSingularRBF)
+}
@@ -0,0 +1,921 @@
+package meshx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version of a Gmsh MSH file.
+type MSHVersion int
+
+const (
+	MSHVersion2 MSHVersion = iota
+	MSHVersion4
+)
+
+const (
+	mshTriangle      = 2
+	mshQuadrilateral = 3
+	mshTetrahedron   = 4
+)
+
+var (
+	ErrMSHInvalidHeader  = errors.New("invalid MSH header")
+	ErrMSHInvalidSection = errors.New("invalid MSH section")
+	ErrMSHInvalidElement = errors.New("invalid MSH element")
+)
+
+// Name of each MSH version in the header.
+var mshVersionNames = map[MSHVersion]string{
+	MSHVersion2: "2.2",
+	MSHVersion4: "4.1",
+}
+
+// Number of nodes of each supported MSH element type.
+var mshElementSizes = map[int]int{
+	mshTriangle:      3,
+	mshQuadrilateral: 4,
+	mshTetrahedron:   4,
+}
+
+// MSHReader manages parsing a Gmsh MSH file. This supports the ASCII format
+// of versions 2.2 and 4.1. The triangles and quadrilaterals are the faces
+// and their two-dimensional physical groups are the patches, named by the
+// physical name (or "patch<tag>" if unnamed). The tetrahedra are retained as
+// cells. Other element types are ignored.
+type MSHReader struct {
+	reader      io.Reader
+	version     MSHVersion
+	vertices    []Vector
+	faces       [][]int
+	facePatches []int
+	patches     []string
+	cells       [][4]int
+
+	nodes      map[int]int
+	names      map[int]string
+	entities   map[[2]int]int
+	faceGroups []int
+	conversion Conversion
+	transform  readTransform
+}
+
+// Construct an MSHReader from an io.Reader interface.
+func NewMSHReader(reader io.Reader) *MSHReader {
+	return &MSHReader{
+		reader:      reader,
+		vertices:    make([]Vector, 0),
+		faces:       make([][]int, 0),
+		facePatches: make([]int, 0),
+		patches:     make([]string, 0),
+		cells:       make([][4]int, 0),
+		nodes:       make(map[int]int),
+		names:       make(map[int]string),
+		entities:    make(map[[2]int]int),
+		faceGroups:  make([]int, 0),
+	}
+}
+
+// Read an MSH file from a file path.
+func ReadMSHFromPath(path string) (*MSHReader, error) {
+	return readMSHFromPath(path, readTransform{})
+}
+
+// Read an MSH file from a file path transforming the vertices while reading
+// (e.g. to place a part in a scene). See SetTransform.
+func ReadMSHWithTransform(path string, matrix Matrix4) (*MSHReader, error) {
+	return readMSHFromPath(path, newReadTransform(matrix))
+}
+
+// Read an MSH file from a file path with the transformation applied while
+// reading.
+func readMSHFromPath(path string, transform readTransform) (*MSHReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mshReader := NewMSHReader(file)
+	mshReader.transform = transform
+
+	if err := mshReader.Read(); err != nil {
+		return nil, err
+	}
+
+	return mshReader, nil
+}
+
+// Read the MSH file.
+func (r *MSHReader) Read() error {
+	scanner := bufio.NewScanner(r.reader)
+	scanner.Buffer(make([]byte, 0, 1<<16), 1<<24)
+
+	var lines []string
+	var section string
+	var hasHeader bool
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if !strings.HasPrefix(line, "$") {
+			if section != "" {
+				lines = append(lines, line)
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(line, "$End") {
+			section = line
+			lines = lines[:0]
+			continue
+		}
+
+		if line != "$End"+strings.TrimPrefix(section, "$") {
+			return ErrMSHInvalidSection
+		}
+
+		var err error
+
+		switch section {
+		case "$MeshFormat":
+			err = r.readHeader(lines)
+			hasHeader = err == nil
+		case "$PhysicalNames":
+			err = r.readPhysicalNames(lines)
+		case "$Entities":
+			err = r.readEntities(newMSHTokens(lines))
+		case "$Nodes":
+			err = r.readNodes(newMSHTokens(lines))
+		case "$Elements":
+			err = r.readElements(newMSHTokens(lines))
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !hasHeader {
+			return ErrMSHInvalidHeader
+		}
+
+		section = ""
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if !hasHeader || section != "" {
+		return ErrMSHInvalidHeader
+	}
+
+	r.buildPatches()
+
+	return nil
+}
+
+// Read the version from the $MeshFormat section. Only the ASCII format is
+// supported.
+func (r *MSHReader) readHeader(lines []string) error {
+	if len(lines) == 0 {
+		return ErrMSHInvalidHeader
+	}
+
+	fields := strings.Fields(lines[0])
+
+	if len(fields) < 2 || fields[1] != "0" {
+		return ErrMSHInvalidHeader
+	}
+
+	switch {
+	case strings.HasPrefix(fields[0], "2"):
+		r.version = MSHVersion2
+	case fields[0] == "4.1":
+		r.version = MSHVersion4
+	default:
+		return ErrMSHInvalidHeader
+	}
+
+	return nil
+}
+
+// Read the names of the two-dimensional physical groups.
+func (r *MSHReader) readPhysicalNames(lines []string) error {
+	for _, line := range lines[min(1, len(lines)):] {
+		fields := strings.Fields(line)
+
+		if len(fields) < 3 {
+			return ErrMSHInvalidSection
+		}
+
+		tag, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return ErrMSHInvalidSection
+		}
+
+		name, err := strconv.Unquote(strings.Join(fields[2:], " "))
+		if err != nil {
+			return ErrMSHInvalidSection
+		}
+
+		if fields[0] == "2" {
+			r.names[tag] = name
+		}
+	}
+
+	return nil
+}
+
+// Read the first physical tag of each entity (version 4.1).
+func (r *MSHReader) readEntities(tokens *mshTokens) error {
+	counts := tokens.nextInts(4)
+
+	for dim, count := range counts {
+		for i := 0; i < count && tokens.err == nil; i++ {
+			tag := tokens.nextInt()
+
+			// Points have coordinates and curves, surfaces and volumes have
+			// bounding boxes.
+			if dim == 0 {
+				tokens.nextFloats(3)
+			} else {
+				tokens.nextFloats(6)
+			}
+
+			physicals := tokens.nextInts(tokens.nextInt())
+
+			if len(physicals) != 0 {
+				r.entities[[2]int{dim, tag}] = physicals[0]
+			}
+
+			if dim != 0 {
+				tokens.nextInts(tokens.nextInt())
+			}
+		}
+	}
+
+	return tokens.err
+}
+
+// Read the nodes.
+func (r *MSHReader) readNodes(tokens *mshTokens) error {
+	if r.version == MSHVersion2 {
+		count := tokens.nextInt()
+
+		for i := 0; i < count && tokens.err == nil; i++ {
+			r.addNode(tokens.nextInt(), tokens.nextFloats(3))
+		}
+
+		return tokens.err
+	}
+
+	header := tokens.nextInts(4)
+
+	for i := 0; tokens.err == nil && i < header[0]; i++ {
+		block := tokens.nextInts(4)
+
+		if tokens.err != nil {
+			break
+		}
+
+		tags := tokens.nextInts(block[3])
+
+		for _, tag := range tags {
+			r.addNode(tag, tokens.nextFloats(3))
+
+			// Skip the parametric coordinates.
+			if block[2] != 0 {
+				tokens.nextFloats(block[0])
+			}
+		}
+	}
+
+	return tokens.err
+}
+
+// Add a node by tag.
+func (r *MSHReader) addNode(tag int, coordinates []float64) {
+	if len(coordinates) == 3 {
+		r.nodes[tag] = len(r.vertices)
+		vertex := NewVector(coordinates[0], coordinates[1], coordinates[2])
+		r.vertices = append(r.vertices, r.transform.transformPoint(vertex))
+	}
+}
+
+// Read the elements.
+func (r *MSHReader) readElements(tokens *mshTokens) error {
+	if r.version == MSHVersion2 {
+		// The first line is the number of elements.
+		for _, line := range tokens.lines[min(1, len(tokens.lines)):] {
+			values, err := parseMSHInts(line)
+			if err != nil || len(values) < 3 || len(values) < 3+values[2] {
+				return ErrMSHInvalidElement
+			}
+
+			group := 0
+
+			if values[2] > 0 {
+				group = values[3]
+			}
+
+			if err := r.addElement(values[1], group, values[3+values[2]:]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	header := tokens.nextInts(4)
+
+	for i := 0; tokens.err == nil && i < header[0]; i++ {
+		block := tokens.nextInts(4)
+
+		if tokens.err != nil {
+			break
+		}
+
+		group := r.entities[[2]int{block[0], block[1]}]
+		size, ok := mshElementSizes[block[2]]
+
+		for j := 0; j < block[3] && tokens.err == nil; j++ {
+			if !ok {
+				// Skip the rest of the line of an unsupported element.
+				tokens.nextLine()
+				continue
+			}
+
+			values := tokens.nextInts(size + 1)
+
+			if tokens.err != nil {
+				break
+			}
+
+			if err := r.addElement(block[2], group, values[1:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tokens.err
+}
+
+// Add an element by type with its physical group (zero if none) and nodes.
+// Unsupported element types are ignored.
+func (r *MSHReader) addElement(elementType, group int, nodes []int) error {
+	size, ok := mshElementSizes[elementType]
+
+	if !ok {
+		return nil
+	}
+
+	if len(nodes) != size {
+		return ErrMSHInvalidElement
+	}
+
+	vertices := make([]int, size)
+
+	for i, node := range nodes {
+		vertex, ok := r.nodes[node]
+		if !ok {
+			return ErrMSHInvalidElement
+		}
+		vertices[i] = vertex
+	}
+
+	if elementType == mshTetrahedron {
+		// Keep the cell positively oriented.
+		if r.transform.isMirrored {
+			vertices[1], vertices[2] = vertices[2], vertices[1]
+		}
+
+		r.cells = append(r.cells, [4]int(vertices))
+	} else {
+		r.transform.transformFace(vertices)
+		r.faces = append(r.faces, vertices)
+		r.faceGroups = append(r.faceGroups, group)
+	}
+
+	return nil
+}
+
+// Build the patches from the physical groups of the faces ordered by tag.
+func (r *MSHReader) buildPatches() {
+	for _, group := range r.faceGroups {
+		if _, ok := r.names[group]; !ok && group != 0 {
+			r.names[group] = fmt.Sprintf("patch%d", group)
+		}
+	}
+
+	groups := make([]int, 0, len(r.names))
+
+	for group := range r.names {
+		groups = append(groups, group)
+	}
+
+	sort.Ints(groups)
+	patches := make(map[int]int)
+
+	for _, group := range groups {
+		patches[group] = len(r.patches)
+		r.patches = append(r.patches, r.names[group])
+	}
+
+	for _, group := range r.faceGroups {
+		patch, ok := patches[group]
+
+		if !ok {
+			patch = -1
+		}
+
+		r.facePatches = append(r.facePatches, patch)
+	}
+}
+
+// Get the version.
+func (r *MSHReader) GetVersion() MSHVersion {
+	return r.version
+}
+
+// Set the transformation applied to the vertices while reading. The cells
+// are also reversed if the transformation is mirrored. See
+// OBJReader.SetTransform.
+func (r *MSHReader) SetTransform(matrix Matrix4) {
+	r.transform = newReadTransform(matrix)
+}
+
+// Set the conversion of the coordinates read (e.g. from millimeters in a
+// Y-up system). The faces and cells are reversed if the conversion is
+// mirrored.
+func (r *MSHReader) SetConversion(conversion Conversion) {
+	r.conversion = conversion
+}
+
+// Get a vertex by index.
+func (r *MSHReader) GetVertex(index int) Vector {
+	return r.conversion.ConvertPoint(r.vertices[index])
+}
+
+// Get the number of vertices.
+func (r *MSHReader) GetNumberOfVertices() int {
+	return len(r.vertices)
+}
+
+// Get a face by index.
+func (r *MSHReader) GetFace(index int) []int {
+	return r.conversion.ConvertFace(r.faces[index])
+}
+
+// Get a face patch by index.
+func (r *MSHReader) GetFacePatch(index int) int {
+	return r.facePatches[index]
+}
+
+// Get the number of faces.
+func (r *MSHReader) GetNumberOfFaces() int {
+	return len(r.faces)
+}
+
+// Get the number of face edges.
+func (r *MSHReader) GetNumberOfFaceEdges() int {
+	var count int
+
+	for _, face := range r.faces {
+		count += len(face)
+	}
+
+	return count
+}
+
+// Get a patch by index.
+func (r *MSHReader) GetPatch(index int) string {
+	return r.patches[index]
+}
+
+// Get the number of patches.
+func (r *MSHReader) GetNumberOfPatches() int {
+	return len(r.patches)
+}
+
+// Get a tetrahedral cell by index.
+func (r *MSHReader) GetCell(index int) [4]int {
+	cell := r.cells[index]
+
+	// Keep the cell positively oriented.
+	if r.conversion.IsMirrored() {
+		cell[1], cell[2] = cell[2], cell[1]
+	}
+
+	return cell
+}
+
+// Get the number of tetrahedral cells.
+func (r *MSHReader) GetNumberOfCells() int {
+	return len(r.cells)
+}
+
+// Whitespace separated values of the lines of an MSH section. An invalid or
+// missing value sets the error and reads as zero.
+type mshTokens struct {
+	lines  []string
+	fields []string
+	size   int
+	err    error
+}
+
+// Construct the tokens of the lines of a section.
+func newMSHTokens(lines []string) *mshTokens {
+	tokens := mshTokens{lines: lines}
+
+	for _, line := range lines {
+		tokens.size += len(line)
+	}
+
+	return &tokens
+}
+
+// Get an upper bound of the number of remaining values (so a corrupt count
+// is rejected rather than allocated).
+func (t *mshTokens) getMaxRemaining() int {
+	return len(t.fields) + (t.size+len(t.lines)+1)/2
+}
+
+// Get the next value.
+func (t *mshTokens) next() string {
+	for len(t.fields) == 0 {
+		if len(t.lines) == 0 {
+			t.err = ErrMSHInvalidSection
+			return "0"
+		}
+
+		t.fields = strings.Fields(t.lines[0])
+		t.size -= len(t.lines[0])
+		t.lines = t.lines[1:]
+	}
+
+	field := t.fields[0]
+	t.fields = t.fields[1:]
+	return field
+}
+
+// Skip the remaining values of the current line (or the next line if none
+// remain).
+func (t *mshTokens) nextLine() {
+	if len(t.fields) == 0 && len(t.lines) != 0 {
+		t.size -= len(t.lines[0])
+		t.lines = t.lines[1:]
+	}
+
+	t.fields = nil
+}
+
+// Get the next integer value.
+func (t *mshTokens) nextInt() int {
+	value, err := strconv.Atoi(t.next())
+	if err != nil {
+		t.err = ErrMSHInvalidSection
+	}
+	return value
+}
+
+// Get the next integer values. Zeros are returned once the tokens are
+// exhausted and nil if fewer values remain than the count.
+func (t *mshTokens) nextInts(count int) []int {
+	if count > t.getMaxRemaining() {
+		t.err = ErrMSHInvalidSection
+		return nil
+	}
+
+	values := make([]int, max(count, 0))
+
+	for i := 0; i < len(values) && t.err == nil; i++ {
+		values[i] = t.nextInt()
+	}
+
+	return values
+}
+
+// Get the next floating point values. See nextInts.
+func (t *mshTokens) nextFloats(count int) []float64 {
+	if count > t.getMaxRemaining() {
+		t.err = ErrMSHInvalidSection
+		return nil
+	}
+
+	values := make([]float64, max(count, 0))
+
+	for i := 0; i < len(values) && t.err == nil; i++ {
+		value, err := strconv.ParseFloat(t.next(), 64)
+		if err != nil {
+			t.err = ErrMSHInvalidSection
+		}
+		values[i] = value
+	}
+
+	return values
+}
+
+// Parse the whitespace separated integers of a line.
+func parseMSHInts(line string) ([]int, error) {
+	fields := strings.Fields(line)
+	values := make([]int, len(fields))
+
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// MSHWriter manages writing a Gmsh MSH file in the ASCII format. The faces
+// (triangles or quadrilaterals) of each patch are written in the
+// two-dimensional physical group of the patch (tagged by the patch index
+// plus one) and the faces without a patch are in no physical group. The
+// tetrahedral cells are written following the faces.
+type MSHWriter struct {
+	writer      io.Writer
+	version     MSHVersion
+	vertices    []Vector
+	faces       [][]int
+	facePatches []int
+	patches     []string
+	cells       [][4]int
+	conversion  Conversion
+}
+
+// Construct an MSHWriter from an io.Writer interface.
+func NewMSHWriter(writer io.Writer, version MSHVersion) *MSHWriter {
+	return &MSHWriter{
+		writer:      writer,
+		version:     version,
+		vertices:    make([]Vector, 0),
+		faces:       make([][]int, 0),
+		facePatches: make([]int, 0),
+		patches:     make([]string, 0),
+		cells:       make([][4]int, 0),
+	}
+}
+
+// Set the vertices to write.
+func (w *MSHWriter) SetVertices(vertices []Vector) {
+	w.vertices = vertices
+}
+
+// Set the faces to write.
+func (w *MSHWriter) SetFaces(faces [][]int) {
+	w.faces = faces
+}
+
+// Set the face patches to write.
+func (w *MSHWriter) SetFacePatches(facePatches []int) {
+	w.facePatches = facePatches
+}
+
+// Set the patches to write.
+func (w *MSHWriter) SetPatches(patches []string) {
+	w.patches = patches
+}
+
+// Set the tetrahedral cells to write.
+func (w *MSHWriter) SetCells(cells [][4]int) {
+	w.cells = cells
+}
+
+// Set the conversion of the coordinates written (e.g. to millimeters in a
+// Y-up system). The faces and cells are reversed if the conversion is
+// mirrored.
+func (w *MSHWriter) SetConversion(conversion Conversion) {
+	w.conversion = conversion
+}
+
+// Write the data to the io.Writer interface.
+func (w *MSHWriter) Write() error {
+	converted := *w
+	converted.vertices = w.conversion.ConvertPoints(w.vertices)
+	converted.faces = w.conversion.ConvertFaces(w.faces)
+
+	// Keep the cells positively oriented.
+	if w.conversion.IsMirrored() {
+		converted.cells = make([][4]int, len(w.cells))
+
+		for i, cell := range w.cells {
+			converted.cells[i] = [4]int{cell[0], cell[2], cell[1], cell[3]}
+		}
+	}
+	return converted.write()
+}
+
+// Write the converted data.
+func (w *MSHWriter) write() error {
+	name, ok := mshVersionNames[w.version]
+	if !ok {
+		return ErrMSHInvalidHeader
+	}
+
+	for _, face := range w.faces {
+		if len(face) != 3 && len(face) != 4 {
+			return ErrMSHInvalidElement
+		}
+	}
+
+	writer := bufio.NewWriter(w.writer)
+
+	writer.WriteString(fmt.Sprintf("$MeshFormat\n%s 0 8\n$EndMeshFormat\n", name))
+
+	if len(w.patches) != 0 {
+		writer.WriteString(fmt.Sprintf("$PhysicalNames\n%d\n", len(w.patches)))
+
+		for i, patch := range w.patches {
+			writer.WriteString(fmt.Sprintf("2 %d %s\n", i+1, strconv.Quote(patch)))
+		}
+
+		writer.WriteString("$EndPhysicalNames\n")
+	}
+
+	if w.version == MSHVersion2 {
+		w.writeVersion2(writer)
+	} else {
+		w.writeVersion4(writer)
+	}
+
+	return writer.Flush()
+}
+
+// Get the physical group of a face (zero if none).
+func (w *MSHWriter) getFaceGroup(index int) int {
+	if index >= len(w.facePatches) || w.facePatches[index] < 0 {
+		return 0
+	}
+
+	return w.facePatches[index] + 1
+}
+
+// Get the element type of a face.
+func getMSHFaceType(face []int) int {
+	if len(face) == 4 {
+		return mshQuadrilateral
+	}
+
+	return mshTriangle
+}
+
+// Write the nodes and elements of a version 2.2 file. The elementary tag of
+// a face is its physical group (or the number of patches plus one if none).
+func (w *MSHWriter) writeVersion2(writer *bufio.Writer) {
+	writer.WriteString(fmt.Sprintf("$Nodes\n%d\n", len(w.vertices)))
+
+	for i, vertex := range w.vertices {
+		writer.WriteString(fmt.Sprintf("%d %s\n", i+1, formatMSHVector(vertex)))
+	}
+
+	writer.WriteString("$EndNodes\n")
+	writer.WriteString(fmt.Sprintf("$Elements\n%d\n", len(w.faces)+len(w.cells)))
+
+	for i, face := range w.faces {
+		group := w.getFaceGroup(i)
+		entity := group
+
+		if group == 0 {
+			entity = len(w.patches) + 1
+		}
+
+		writer.WriteString(fmt.Sprintf("%d %d 2 %d %d %s\n", i+1, getMSHFaceType(face), group, entity, formatMSHNodes(face)))
+	}
+
+	for i, cell := range w.cells {
+		writer.WriteString(fmt.Sprintf("%d %d 2 0 1 %s\n", len(w.faces)+i+1, mshTetrahedron, formatMSHNodes(cell[:])))
+	}
+
+	writer.WriteString("$EndElements\n")
+}
+
+// Write the entities, nodes and elements of a version 4.1 file. Each patch
+// is a surface entity (tagged by its physical group) followed by a surface
+// entity for the faces without a patch. The cells are in a single volume
+// entity. The nodes are in the volume entity (or the last surface entity if
+// there are no cells).
+func (w *MSHWriter) writeVersion4(writer *bufio.Writer) {
+	aabb := NewAABBFromVectors(w.vertices)
+	bounds := formatMSHVector(aabb.GetMinBound()) + " " + formatMSHVector(aabb.GetMaxBound())
+	nSurfaces := len(w.patches) + 1
+	nVolumes := min(len(w.cells), 1)
+
+	if len(w.vertices) == 0 {
+		bounds = "0 0 0 0 0 0"
+	}
+
+	writer.WriteString(fmt.Sprintf("$Entities\n0 0 %d %d\n", nSurfaces, nVolumes))
+
+	for i := range nSurfaces {
+		if i < len(w.patches) {
+			writer.WriteString(fmt.Sprintf("%d %s 1 %d 0\n", i+1, bounds, i+1))
+		} else {
+			writer.WriteString(fmt.Sprintf("%d %s 0 0\n", i+1, bounds))
+		}
+	}
+
+	if nVolumes != 0 {
+		writer.WriteString(fmt.Sprintf("1 %s 0 0\n", bounds))
+	}
+
+	writer.WriteString("$EndEntities\n")
+
+	nodeEntity := fmt.Sprintf("2 %d", nSurfaces)
+
+	if nVolumes != 0 {
+		nodeEntity = "3 1"
+	}
+
+	writer.WriteString("$Nodes\n")
+	writer.WriteString(fmt.Sprintf("1 %d 1 %d\n", len(w.vertices), len(w.vertices)))
+	writer.WriteString(fmt.Sprintf("%s 0 %d\n", nodeEntity, len(w.vertices)))
+
+	for i := range w.vertices {
+		writer.WriteString(fmt.Sprintf("%d\n", i+1))
+	}
+
+	for _, vertex := range w.vertices {
+		writer.WriteString(formatMSHVector(vertex) + "\n")
+	}
+
+	writer.WriteString("$EndNodes\n")
+
+	// Group the faces into blocks by entity and element type.
+	blocks := make(map[[2]int][]int)
+	keys := make([][2]int, 0)
+
+	for i, face := range w.faces {
+		entity := w.getFaceGroup(i)
+
+		if entity == 0 {
+			entity = nSurfaces
+		}
+
+		key := [2]int{entity, getMSHFaceType(face)}
+
+		if _, ok := blocks[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		blocks[key] = append(blocks[key], i)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+
+	nElements := len(w.faces) + len(w.cells)
+	writer.WriteString("$Elements\n")
+	writer.WriteString(fmt.Sprintf("%d %d 1 %d\n", len(keys)+nVolumes, nElements, nElements))
+
+	tag := 1
+
+	for _, key := range keys {
+		writer.WriteString(fmt.Sprintf("2 %d %d %d\n", key[0], key[1], len(blocks[key])))
+
+		for _, face := range blocks[key] {
+			writer.WriteString(fmt.Sprintf("%d %s\n", tag, formatMSHNodes(w.faces[face])))
+			tag++
+		}
+	}
+
+	if nVolumes != 0 {
+		writer.WriteString(fmt.Sprintf("3 1 %d %d\n", mshTetrahedron, len(w.cells)))
+
+		for _, cell := range w.cells {
+			writer.WriteString(fmt.Sprintf("%d %s\n", tag, formatMSHNodes(cell[:])))
+			tag++
+		}
+	}
+
+	writer.WriteString("$EndElements\n")
+}
+
+// Format the (one-based) node tags of the vertices.
+func formatMSHNodes(vertices []int) string {
+	fields := make([]string, len(vertices))
+
+	for i, vertex := range vertices {
+		fields[i] = strconv.Itoa(vertex + 1)
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// Format a vector with the shortest representation of each component.
+func formatMSHVector(vector Vector) string {
+	return strconv.FormatFloat(vector[0], 'g', -1, 64) + " " +
+		strconv.FormatFloat(vector[1], 'g', -1, 64) + " " +
+		strconv.FormatFloat(vector[2], 'g', -1, 64)
+}
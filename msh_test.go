@@ -0,0 +1,241 @@
+package meshx
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Read an MSH 2.2 file with physical groups and unsupported elements.
+func TestReadMSHVersion2(t *testing.T) {
+	var data string
+	data += "$MeshFormat\n2.2 0 8\n$EndMeshFormat\n"
+	data += "$PhysicalNames\n2\n1 5 \"edge\"\n2 7 \"bottom\"\n$EndPhysicalNames\n"
+	data += "$Nodes\n5\n"
+	data += "10 0 0 0\n11 1 0 0\n12 1 1 0\n13 0 1 0\n14 0 0 1\n"
+	data += "$EndNodes\n"
+	data += "$Elements\n5\n"
+	data += "1 15 2 0 1 10\n"
+	data += "2 1 2 5 1 10 11\n"
+	data += "3 3 2 7 1 10 13 12 11\n"
+	data += "4 2 2 0 2 10 11 14\n"
+	data += "5 4 2 0 1 10 11 12 14\n"
+	data += "$EndElements\n"
+
+	reader := NewMSHReader(bytes.NewBufferString(data))
+	assert.Empty(t, reader.Read())
+
+	assert.Equal(t, MSHVersion2, reader.GetVersion())
+	assert.Equal(t, 5, reader.GetNumberOfVertices())
+	assert.Equal(t, NewVector(0, 0, 1), reader.GetVertex(4))
+	assert.Equal(t, 2, reader.GetNumberOfFaces())
+	assert.Equal(t, 7, reader.GetNumberOfFaceEdges())
+	assert.Equal(t, []int{0, 3, 2, 1}, reader.GetFace(0))
+	assert.Equal(t, []int{0, 1, 4}, reader.GetFace(1))
+	assert.Equal(t, 1, reader.GetNumberOfPatches())
+	assert.Equal(t, "bottom", reader.GetPatch(0))
+	assert.Equal(t, 0, reader.GetFacePatch(0))
+	assert.Equal(t, -1, reader.GetFacePatch(1))
+	assert.Equal(t, 1, reader.GetNumberOfCells())
+	assert.Equal(t, [4]int{0, 1, 2, 4}, reader.GetCell(0))
+}
+
+// Read an MSH 4.1 file with entities, parametric nodes and unsupported
+// elements.
+func TestReadMSHVersion4(t *testing.T) {
+	var data string
+	data += "$MeshFormat\n4.1 0 8\n$EndMeshFormat\n"
+	data += "$Entities\n"
+	data += "1 0 2 0\n"
+	data += "1 0 0 0 0\n"
+	data += "1 0 0 0 1 1 0 1 3 0\n"
+	data += "2 0 0 0 1 1 1 0 2 1 -1\n"
+	data += "$EndEntities\n"
+	data += "$Nodes\n"
+	data += "2 4 1 8\n"
+	data += "2 1 0 3\n1\n2\n3\n0 0 0\n1 0 0\n1 1 0\n"
+	data += "2 2 1 1\n8\n0 1 0 0.5 0.5\n"
+	data += "$EndNodes\n"
+	data += "$Elements\n"
+	data += "3 3 1 3\n"
+	data += "0 1 15 1\n1 1\n"
+	data += "2 1 2 1\n2 1 2 3\n"
+	data += "2 2 2 1\n3 1 3 8\n"
+	data += "$EndElements\n"
+
+	reader := NewMSHReader(bytes.NewBufferString(data))
+	assert.Empty(t, reader.Read())
+
+	assert.Equal(t, MSHVersion4, reader.GetVersion())
+	assert.Equal(t, 4, reader.GetNumberOfVertices())
+	assert.Equal(t, NewVector(0, 1, 0), reader.GetVertex(3))
+	assert.Equal(t, 2, reader.GetNumberOfFaces())
+	assert.Equal(t, []int{0, 2, 3}, reader.GetFace(1))
+	assert.Equal(t, []string{"patch3"}, reader.patches)
+	assert.Equal(t, 0, reader.GetFacePatch(0))
+	assert.Equal(t, -1, reader.GetFacePatch(1))
+	assert.Equal(t, 0, reader.GetNumberOfCells())
+}
+
+// Read MSH files with an invalid header or section.
+func TestReadMSHInvalid(t *testing.T) {
+	cases := []string{
+		"",
+		"$Nodes\n0\n$EndNodes\n",
+		"$MeshFormat\n2.2 1 8\n$EndMeshFormat\n",
+		"$MeshFormat\n3.0 0 8\n$EndMeshFormat\n",
+		"$MeshFormat\n2.2 0 8\n$EndNodes\n",
+		"$MeshFormat\n2.2 0 8\n$EndMeshFormat\n$Nodes\n1\n1 0 0\n$EndNodes\n",
+		"$MeshFormat\n2.2 0 8\n$EndMeshFormat\n$Elements\n1\n1 2 0 1 2 3\n$EndElements\n",
+		"$MeshFormat\n4.1 0 8\n$EndMeshFormat\n$Nodes\n1 1 1 1\n2 1 0 1\n1\n$EndNodes\n",
+	}
+
+	for _, data := range cases {
+		reader := NewMSHReader(bytes.NewBufferString(data))
+		assert.Error(t, reader.Read())
+	}
+}
+
+// Test writing and reading triangles, quadrilaterals and tetrahedra in each
+// version.
+func TestWriteMSH(t *testing.T) {
+	vertices := []Vector{
+		NewVector(0, 0, 0),
+		NewVector(1, 0, 0),
+		NewVector(1, 1, 0),
+		NewVector(0, 1, 0),
+		NewVector(0, 0, 1),
+	}
+
+	faces := [][]int{{0, 3, 2, 1}, {0, 1, 4}, {1, 2, 4}}
+	facePatches := []int{1, -1, 0}
+	patches := []string{"side", "bottom"}
+	cells := [][4]int{{0, 1, 2, 4}}
+
+	for _, version := range []MSHVersion{MSHVersion2, MSHVersion4} {
+		var buffer bytes.Buffer
+
+		writer := NewMSHWriter(&buffer, version)
+		writer.SetVertices(vertices)
+		writer.SetFaces(faces)
+		writer.SetFacePatches(facePatches)
+		writer.SetPatches(patches)
+		writer.SetCells(cells)
+		assert.Empty(t, writer.Write())
+
+		reader := NewMSHReader(&buffer)
+		assert.Empty(t, reader.Read())
+		assert.Equal(t, version, reader.GetVersion())
+		assert.Equal(t, vertices, reader.vertices)
+		assert.Equal(t, patches, reader.patches)
+		assert.Equal(t, cells, reader.cells)
+		assert.Equal(t, 3, reader.GetNumberOfFaces())
+
+		// The faces are grouped by patch in version 4.1.
+		for i, face := range faces {
+			found := false
+
+			for j := range reader.GetNumberOfFaces() {
+				if assert.ObjectsAreEqual(face, reader.GetFace(j)) {
+					assert.Equal(t, facePatches[i], reader.GetFacePatch(j))
+					found = true
+				}
+			}
+
+			assert.True(t, found)
+		}
+	}
+
+	writer := NewMSHWriter(&bytes.Buffer{}, MSHVersion4)
+	writer.SetFaces([][]int{{0, 1, 2, 3, 4}})
+	assert.ErrorIs(t, writer.Write(), ErrMSHInvalidElement)
+}
+
+// Test writing and reading random meshes preserves them in each version.
+func TestWriteMSHRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for range 100 {
+		mesh := newRandomTestMesh(rng)
+
+		// Only triangles and quadrilaterals are supported.
+		for i, face := range mesh.faces {
+			mesh.faces[i] = face[:min(len(face), 4)]
+		}
+
+		for _, version := range []MSHVersion{MSHVersion2, MSHVersion4} {
+			var buffer bytes.Buffer
+			writer := NewMSHWriter(&buffer, version)
+			mesh.setWriter(writer)
+			assert.Empty(t, writer.Write())
+
+			reader := NewMSHReader(&buffer)
+			assert.Empty(t, reader.Read())
+			mesh.assertReader(t, reader, 1e-12)
+		}
+	}
+}
+
+// Read an MSH file transforming the vertices while reading.
+func TestReadMSHWithTransform(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mesh.msh")
+	vertices := []Vector{NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(1, 1, 0), NewVector(0, 0, 1)}
+	mesh := testMesh{vertices, [][]int{{0, 1, 2}, {0, 1, 2, 3}}, []int{0, -1}, []string{"a"}}
+
+	var buffer bytes.Buffer
+	writer := NewMSHWriter(&buffer, MSHVersion4)
+	mesh.setWriter(writer)
+	writer.SetCells([][4]int{{0, 1, 2, 3}})
+	assert.Empty(t, writer.Write())
+	assert.Empty(t, os.WriteFile(path, buffer.Bytes(), 0644))
+
+	for _, matrix := range testTransforms {
+		reader, err := ReadMSHWithTransform(path, matrix)
+		assert.Empty(t, err)
+		mesh.transform(matrix).assertReader(t, reader, 1e-12)
+
+		// The cell is kept positively oriented.
+		cell := reader.GetCell(0)
+		p := reader.GetVertex(cell[0])
+		volume := reader.GetVertex(cell[1]).Sub(p).Cross(reader.GetVertex(cell[2]).Sub(p)).Dot(reader.GetVertex(cell[3]).Sub(p))
+		assert.Greater(t, volume, 0.0)
+	}
+}
+
+// Fuzz reading an MSH file. A file read without an error must reference
+// existing vertices and patches.
+func FuzzMSHReader(f *testing.F) {
+	for _, version := range []MSHVersion{MSHVersion2, MSHVersion4} {
+		var buffer bytes.Buffer
+		writer := NewMSHWriter(&buffer, version)
+		writer.SetVertices([]Vector{NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(1, 1, 0), NewVector(0, 0, 1)})
+		writer.SetFaces([][]int{{0, 1, 2}, {0, 1, 2, 3}})
+		writer.SetFacePatches([]int{0, -1})
+		writer.SetPatches([]string{"a"})
+		writer.SetCells([][4]int{{0, 1, 2, 3}})
+
+		if err := writer.Write(); err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(buffer.Bytes())
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		reader := NewMSHReader(bytes.NewReader(data))
+
+		if reader.Read() != nil {
+			return
+		}
+
+		checkTestMeshReader(t, reader, true)
+
+		for i := range reader.GetNumberOfCells() {
+			reader.GetCell(i)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package outofcore
+
+import (
+	"errors"
+	"io"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Diagnostics of a mesh computed out of core serializable as JSON.
+type Diagnosis struct {
+	NumberOfVertices          int          `json:"numberOfVertices"`
+	NumberOfFaces             int          `json:"numberOfFaces"`
+	NumberOfPatches           int          `json:"numberOfPatches"`
+	NumberOfEdges             int          `json:"numberOfEdges"`
+	NumberOfBoundaryEdges     int          `json:"numberOfBoundaryEdges"`
+	NumberOfNonManifoldEdges  int          `json:"numberOfNonManifoldEdges"`
+	NumberOfInconsistentEdges int          `json:"numberOfInconsistentEdges"`
+	NumberOfDegenerateFaces   int          `json:"numberOfDegenerateFaces"`
+	NumberOfUnusedVertices    int          `json:"numberOfUnusedVertices"`
+	NumberOfMissingVertices   int          `json:"numberOfMissingVertices"`
+	MinBound                  meshx.Vector `json:"minBound"`
+	MaxBound                  meshx.Vector `json:"maxBound"`
+}
+
+// Check if every edge is shared by exactly two faces.
+func (d Diagnosis) IsClosed() bool {
+	return d.NumberOfBoundaryEdges == 0 && d.NumberOfNonManifoldEdges == 0
+}
+
+// Check if the faces sharing each manifold edge traverse it in opposite
+// directions.
+func (d Diagnosis) IsConsistent() bool {
+	return d.NumberOfInconsistentEdges == 0
+}
+
+// Diagnose a mesh out of core. The edges are matched to their twins by
+// sorting the half edges externally by their undirected edge:
+//
+//   - a boundary edge has a single half edge
+//   - a non-manifold edge has more than two half edges
+//   - an inconsistent edge has two half edges in the same direction
+//
+// A degenerate face repeats a vertex (its edges joining a vertex to itself
+// are ignored), an unused vertex is referenced by no face and a missing
+// vertex is referenced but not defined.
+func Diagnose(reader ChunkReader, options Options) (Diagnosis, error) {
+	var diagnosis Diagnosis
+
+	dir, runSize := options.TempDir, options.getRunSize()
+	halfEdges := newTupleSorter(dir, runSize)
+	defer halfEdges.close()
+	vertices := newTupleSorter(dir, runSize)
+	defer vertices.close()
+
+	minBound := meshx.NewVector(math.Inf(1), math.Inf(1), math.Inf(1))
+	maxBound := meshx.NewVector(math.Inf(-1), math.Inf(-1), math.Inf(-1))
+
+	for {
+		chunk, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return diagnosis, err
+		}
+
+		diagnosis.NumberOfPatches = len(chunk.Patches)
+
+		for _, vertex := range chunk.Vertices {
+			for i := range vertex {
+				minBound[i] = min(minBound[i], vertex[i])
+				maxBound[i] = max(maxBound[i], vertex[i])
+			}
+		}
+
+		for _, face := range chunk.Faces {
+			if hasRepeatedVertex(face) {
+				diagnosis.NumberOfDegenerateFaces++
+			}
+
+			for i, vertex := range face {
+				next := face[(i+1)%len(face)]
+
+				if err := vertices.add(tuple{int64(vertex)}); err != nil {
+					return diagnosis, err
+				}
+
+				if vertex == next {
+					continue
+				}
+
+				halfEdge := tuple{int64(vertex), int64(next), 0, int64(diagnosis.NumberOfFaces)}
+
+				if vertex > next {
+					halfEdge = tuple{int64(next), int64(vertex), 1, int64(diagnosis.NumberOfFaces)}
+				}
+
+				if err := halfEdges.add(halfEdge); err != nil {
+					return diagnosis, err
+				}
+			}
+
+			diagnosis.NumberOfFaces++
+		}
+
+		diagnosis.NumberOfVertices += len(chunk.Vertices)
+	}
+
+	if diagnosis.NumberOfVertices != 0 {
+		diagnosis.MinBound, diagnosis.MaxBound = minBound, maxBound
+	}
+
+	if err := diagnoseEdges(halfEdges, &diagnosis); err != nil {
+		return diagnosis, err
+	}
+
+	if err := diagnoseVertices(vertices, &diagnosis); err != nil {
+		return diagnosis, err
+	}
+
+	return diagnosis, nil
+}
+
+// Count the edges by the number and directions of their sorted half edges.
+func diagnoseEdges(halfEdges *tupleSorter, diagnosis *Diagnosis) error {
+	if err := halfEdges.sort(); err != nil {
+		return err
+	}
+
+	var edge [2]int64
+	var directions [2]int
+
+	count := func() {
+		switch n := directions[0] + directions[1]; {
+		case n == 1:
+			diagnosis.NumberOfBoundaryEdges++
+		case n > 2:
+			diagnosis.NumberOfNonManifoldEdges++
+		case directions[0] != 1:
+			diagnosis.NumberOfInconsistentEdges++
+		}
+	}
+
+	for {
+		t, ok, err := halfEdges.next()
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
+		if diagnosis.NumberOfEdges == 0 || [2]int64(t[:2]) != edge {
+			if diagnosis.NumberOfEdges != 0 {
+				count()
+			}
+
+			edge, directions = [2]int64(t[:2]), [2]int{}
+			diagnosis.NumberOfEdges++
+		}
+
+		directions[t[2]]++
+	}
+
+	if diagnosis.NumberOfEdges != 0 {
+		count()
+	}
+
+	return nil
+}
+
+// Count the distinct sorted vertices referenced by the faces.
+func diagnoseVertices(vertices *tupleSorter, diagnosis *Diagnosis) error {
+	if err := vertices.sort(); err != nil {
+		return err
+	}
+
+	used, previous := 0, int64(-1)
+
+	for {
+		t, ok, err := vertices.next()
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
+		if t[0] == previous {
+			continue
+		}
+
+		if t[0] < int64(diagnosis.NumberOfVertices) {
+			used++
+		} else {
+			diagnosis.NumberOfMissingVertices++
+		}
+
+		previous = t[0]
+	}
+
+	diagnosis.NumberOfUnusedVertices = diagnosis.NumberOfVertices - used
+
+	return nil
+}
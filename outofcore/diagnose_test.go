@@ -0,0 +1,62 @@
+package outofcore
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test diagnosing a closed mesh out of core matches the mesh in memory.
+func TestDiagnose(t *testing.T) {
+	path := "../testdata/box.patches.obj"
+	mesh, err := pipeline.ReadMeshFromPath(path)
+	assert.Empty(t, err)
+
+	file, err := os.Open(path)
+	assert.Empty(t, err)
+	defer file.Close()
+
+	reader, err := NewOBJChunkReader(file)
+	assert.Empty(t, err)
+	reader.SetChunkSize(2)
+
+	diagnosis, err := Diagnose(reader, Options{TempDir: t.TempDir(), RunSize: 4})
+	assert.Empty(t, err)
+	assert.Equal(t, mesh.GetNumberOfVertices(), diagnosis.NumberOfVertices)
+	assert.Equal(t, mesh.GetNumberOfFaces(), diagnosis.NumberOfFaces)
+	assert.Equal(t, mesh.GetNumberOfPatches(), diagnosis.NumberOfPatches)
+	assert.Equal(t, mesh.GetNumberOfHalfEdges()/2, diagnosis.NumberOfEdges)
+	assert.Equal(t, mesh.IsClosed(), diagnosis.IsClosed())
+	assert.Equal(t, mesh.IsConsistent(), diagnosis.IsConsistent())
+	assert.Equal(t, mesh.GetAABB().GetMinBound(), diagnosis.MinBound)
+	assert.Equal(t, mesh.GetAABB().GetMaxBound(), diagnosis.MaxBound)
+}
+
+// Test diagnosing the defects of a mesh out of core.
+func TestDiagnoseDefects(t *testing.T) {
+	data := strings.Join([]string{
+		"v 0 0 0", "v 1 0 0", "v 0 1 0", "v 0 0 1", "v 5 5 5",
+		"f 1 2 3", "f 1 2 4", "f 1 2 3", "f 2 3 3", "f 3 4 7",
+	}, "\n")
+
+	reader, err := NewOBJChunkReader(strings.NewReader(data))
+	assert.Empty(t, err)
+
+	diagnosis, err := Diagnose(reader, Options{TempDir: t.TempDir()})
+	assert.Empty(t, err)
+	assert.Equal(t, 5, diagnosis.NumberOfVertices)
+	assert.Equal(t, 5, diagnosis.NumberOfFaces)
+	assert.Equal(t, 2, diagnosis.NumberOfNonManifoldEdges)
+	assert.Equal(t, 1, diagnosis.NumberOfInconsistentEdges)
+	assert.Equal(t, 5, diagnosis.NumberOfBoundaryEdges)
+	assert.Equal(t, 1, diagnosis.NumberOfDegenerateFaces)
+	assert.Equal(t, 1, diagnosis.NumberOfUnusedVertices)
+	assert.Equal(t, 1, diagnosis.NumberOfMissingVertices)
+	assert.Equal(t, meshx.NewVector(5, 5, 5), diagnosis.MaxBound)
+	assert.False(t, diagnosis.IsClosed())
+	assert.False(t, diagnosis.IsConsistent())
+}
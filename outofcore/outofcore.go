@@ -0,0 +1,68 @@
+// Package outofcore implements processing meshes larger than memory. Meshes
+// are streamed in chunks and the operations requiring a global view of the
+// mesh (e.g. matching the twins of the edges or welding the vertices) sort
+// records externally in temporary files, so the memory used is bounded by
+// the options rather than the size of the mesh.
+package outofcore
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	// Default number of records sorted in memory before spilling a run to a
+	// temporary file (32 MiB of records).
+	DefaultRunSize = 1 << 20
+)
+
+// Options for processing a mesh out of core. Zero values use the defaults.
+type Options struct {
+	// Directory of the temporary files. The default is os.TempDir.
+	TempDir string
+
+	// Number of records sorted in memory before spilling a run to a
+	// temporary file. The default is DefaultRunSize.
+	RunSize int
+
+	// Number of elements (vertices and faces) of a chunk written. The default
+	// is DefaultChunkSize.
+	ChunkSize int
+}
+
+// Get the number of records of a run.
+func (o Options) getRunSize() int {
+	if o.RunSize <= 0 {
+		return DefaultRunSize
+	}
+
+	return o.RunSize
+}
+
+// Get the number of elements of a chunk.
+func (o Options) getChunkSize() int {
+	if o.ChunkSize <= 0 {
+		return DefaultChunkSize
+	}
+
+	return o.ChunkSize
+}
+
+// Convert a mesh between formats by copying the chunks read to the writer.
+// The writer is closed once all chunks are written.
+func Convert(reader ChunkReader, writer ChunkWriter) error {
+	for {
+		chunk, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if err := writer.WriteChunk(chunk); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
@@ -0,0 +1,148 @@
+package outofcore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Read the chunks of an OBJ file path.
+func readTestChunks(t *testing.T, path string, chunkSize int) []*Chunk {
+	data, err := os.ReadFile(path)
+	assert.Empty(t, err)
+
+	reader, err := NewOBJChunkReader(bytes.NewReader(data))
+	assert.Empty(t, err)
+	reader.SetChunkSize(chunkSize)
+
+	chunks := make([]*Chunk, 0)
+
+	for {
+		chunk, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		assert.Empty(t, err)
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// Test reading an OBJ file in chunks.
+func TestOBJChunkReader(t *testing.T) {
+	chunks := readTestChunks(t, "../testdata/box.patches.obj", 5)
+	assert.Equal(t, 3, len(chunks))
+
+	assert.Equal(t, 5, len(chunks[0].Vertices))
+	assert.Equal(t, 3, len(chunks[1].Vertices))
+	assert.Equal(t, meshx.NewVector(1, 1, 1), chunks[1].Vertices[2])
+	assert.Equal(t, [][]int{{0, 1, 3, 2}, {4, 5, 6}}, chunks[1].Faces)
+	assert.Equal(t, []int{0, 1}, chunks[1].FacePatches)
+	assert.Equal(t, []string{"front", "back"}, chunks[1].Patches)
+	assert.Equal(t, 5, len(chunks[2].Faces))
+	assert.Equal(t, 6, len(chunks[2].Patches))
+
+	reader, _ := NewOBJChunkReader(strings.NewReader("v 0 0\n"))
+	_, err := reader.Next()
+	assert.ErrorIs(t, err, meshx.ErrInvalidVertex)
+
+	reader, _ = NewOBJChunkReader(strings.NewReader("v 0 0 0\nf 1 2\n"))
+	_, err = reader.Next()
+	assert.ErrorIs(t, err, meshx.ErrInvalidFace)
+}
+
+// Test reading a GZIP OBJ file in chunks.
+func TestOBJChunkReaderGZIP(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	writer.Write([]byte("v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3"))
+	writer.Close()
+
+	reader, err := NewOBJChunkReader(&buffer)
+	assert.Empty(t, err)
+
+	chunk, err := reader.Next()
+	assert.Empty(t, err)
+	assert.Equal(t, 3, len(chunk.Vertices))
+	assert.Equal(t, [][]int{{0, 1, 2}}, chunk.Faces)
+	assert.Equal(t, []int{-1}, chunk.FacePatches)
+
+	_, err = reader.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+// Test converting an OBJ file to OBJ and PLY in chunks.
+func TestConvert(t *testing.T) {
+	source, err := meshx.ReadOBJFromPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	for _, format := range []string{"obj", "ply"} {
+		file, err := os.Open("../testdata/box.patches.obj")
+		assert.Empty(t, err)
+		defer file.Close()
+
+		reader, err := NewOBJChunkReader(file)
+		assert.Empty(t, err)
+		reader.SetChunkSize(3)
+
+		var buffer bytes.Buffer
+		var writer ChunkWriter = NewOBJChunkWriter(&buffer)
+
+		if format == "ply" {
+			writer = NewPLYChunkWriter(&buffer, t.TempDir())
+		}
+
+		assert.Empty(t, Convert(reader, writer))
+
+		var converted meshx.MeshReader
+
+		if format == "ply" {
+			plyReader := meshx.NewPLYReader(&buffer)
+			assert.Empty(t, plyReader.Read())
+			converted = plyReader
+		} else {
+			objReader := meshx.NewOBJReader(&buffer)
+			assert.Empty(t, objReader.Read())
+			converted = objReader
+		}
+
+		assert.Equal(t, source.GetNumberOfVertices(), converted.GetNumberOfVertices())
+		assert.Equal(t, source.GetNumberOfFaces(), converted.GetNumberOfFaces())
+		assert.Equal(t, source.GetNumberOfPatches(), converted.GetNumberOfPatches())
+
+		for i := 0; i < source.GetNumberOfVertices(); i++ {
+			assert.Equal(t, source.GetVertex(i), converted.GetVertex(i))
+		}
+
+		for i := 0; i < source.GetNumberOfFaces(); i++ {
+			assert.Equal(t, source.GetFace(i), converted.GetFace(i))
+			assert.Equal(t, source.GetPatch(source.GetFacePatch(i)), converted.GetPatch(converted.GetFacePatch(i)))
+		}
+	}
+}
+
+// Test writing a chunk with the shortest round trip formatting.
+func TestOBJChunkWriterFloatFormat(t *testing.T) {
+	chunk := &Chunk{
+		Vertices:    []meshx.Vector{{0.1, 1.0 / 3, 2}, {1, 0, 0}, {0, 1, 0}},
+		Faces:       [][]int{{0, 1, 2}},
+		FacePatches: []int{-1},
+	}
+
+	var buffer bytes.Buffer
+	writer := NewOBJChunkWriter(&buffer)
+	writer.SetFloatFormat(meshx.FloatRoundTrip)
+	assert.Empty(t, writer.WriteChunk(chunk))
+	assert.Empty(t, writer.Close())
+
+	assert.True(t, strings.HasPrefix(buffer.String(), "v 0.1 0.3333333333333333 2\nv 1 0 0\n"))
+}
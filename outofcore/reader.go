@@ -0,0 +1,173 @@
+package outofcore
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	// Default number of elements (vertices and faces) of a chunk.
+	DefaultChunkSize = 1 << 16
+)
+
+// Vertices and faces of a mesh streamed in the order read. The faces
+// reference the vertices by their index in the whole mesh.
+type Chunk struct {
+	Vertices    []meshx.Vector
+	Faces       [][]int
+	FacePatches []int
+
+	// Patches read so far (indexed by the face patches).
+	Patches []string
+}
+
+// Reader of a mesh in chunks. Next returns io.EOF after the last chunk.
+type ChunkReader interface {
+	Next() (*Chunk, error)
+}
+
+// Writer of a mesh in chunks. A face may only reference the vertices of its
+// chunk or a chunk before it. Close completes the mesh and does not close
+// the underlying writer.
+type ChunkWriter interface {
+	WriteChunk(chunk *Chunk) error
+	Close() error
+}
+
+// OBJChunkReader manages reading an OBJ (WaveFront) file in chunks without
+// holding the mesh in memory. This supports both ASCII and GZIP ASCII files.
+// Only the vertices, faces and groups (as patches) are read.
+type OBJChunkReader struct {
+	reader    *bufio.Reader
+	chunkSize int
+	line      int
+	patches   []string
+	patch     int
+	done      bool
+}
+
+// Construct an OBJChunkReader from an io.Reader interface.
+func NewOBJChunkReader(reader io.Reader) (*OBJChunkReader, error) {
+	buffered := bufio.NewReader(reader)
+
+	if data, _ := buffered.Peek(2); len(data) == 2 && data[0] == 31 && data[1] == 139 {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+
+		buffered = bufio.NewReader(gzipReader)
+	}
+
+	return &OBJChunkReader{
+		reader:    buffered,
+		chunkSize: DefaultChunkSize,
+		patches:   make([]string, 0),
+		patch:     -1,
+	}, nil
+}
+
+// Set the number of elements (vertices and faces) of a chunk.
+func (r *OBJChunkReader) SetChunkSize(chunkSize int) {
+	r.chunkSize = max(1, chunkSize)
+}
+
+// Read the next chunk.
+func (r *OBJChunkReader) Next() (*Chunk, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+
+	chunk := &Chunk{
+		Vertices:    make([]meshx.Vector, 0),
+		Faces:       make([][]int, 0),
+		FacePatches: make([]int, 0),
+	}
+
+	for len(chunk.Vertices)+len(chunk.Faces) < r.chunkSize {
+		data, err := r.reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		} else if len(data) == 0 {
+			r.done = true
+			break
+		}
+
+		r.line++
+
+		if err := r.parseLine(chunk, bytes.TrimSpace(data)); err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+	}
+
+	if r.done && len(chunk.Vertices) == 0 && len(chunk.Faces) == 0 {
+		return nil, io.EOF
+	}
+
+	chunk.Patches = r.patches
+
+	return chunk, nil
+}
+
+// Parse a line into a chunk.
+func (r *OBJChunkReader) parseLine(chunk *Chunk, data []byte) error {
+	fields := bytes.Fields(data)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	switch string(fields[0]) {
+	case meshx.PrefixVertex:
+		if len(fields) != 4 && len(fields) != 7 && len(fields) != 8 {
+			return meshx.ErrInvalidVertex
+		}
+
+		var vertex meshx.Vector
+
+		for i := range vertex {
+			value, err := strconv.ParseFloat(string(fields[i+1]), 64)
+			if err != nil {
+				return meshx.ErrInvalidVertex
+			}
+
+			vertex[i] = value
+		}
+
+		chunk.Vertices = append(chunk.Vertices, vertex)
+	case meshx.PrefixFace:
+		if len(fields) <= 3 {
+			return meshx.ErrInvalidFace
+		}
+
+		face := make([]int, len(fields)-1)
+
+		for i, field := range fields[1:] {
+			if index := bytes.IndexByte(field, '/'); index != -1 {
+				field = field[:index]
+			}
+
+			value, err := strconv.Atoi(string(field))
+			if err != nil || value <= 0 {
+				return meshx.ErrInvalidFace
+			}
+
+			face[i] = value - 1
+		}
+
+		chunk.Faces = append(chunk.Faces, face)
+		chunk.FacePatches = append(chunk.FacePatches, r.patch)
+	case meshx.PrefixGroup:
+		r.patch = len(r.patches)
+		r.patches = append(r.patches, string(bytes.TrimSpace(data[len(meshx.PrefixGroup):])))
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package outofcore
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"slices"
+)
+
+const (
+	// Size in bytes of an encoded tuple.
+	tupleSize = 32
+)
+
+// Record of integers sorted lexicographically (e.g. the vertices of an edge
+// and its face).
+type tuple [4]int64
+
+// Compare tuples lexicographically.
+func compareTuples(a, b tuple) int {
+	return slices.Compare(a[:], b[:])
+}
+
+// Temporary file of tuples written then read sequentially.
+type tupleFile struct {
+	file   *os.File
+	writer *bufio.Writer
+	reader *bufio.Reader
+	buffer [tupleSize]byte
+}
+
+// Create an empty tupleFile in a directory.
+func newTupleFile(dir string) (*tupleFile, error) {
+	file, err := os.CreateTemp(dir, "meshx-*.tuples")
+	if err != nil {
+		return nil, err
+	}
+
+	return &tupleFile{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// Write a tuple.
+func (f *tupleFile) write(t tuple) error {
+	for i, value := range t {
+		binary.LittleEndian.PutUint64(f.buffer[8*i:], uint64(value))
+	}
+
+	_, err := f.writer.Write(f.buffer[:])
+	return err
+}
+
+// Flush the tuples written and rewind to read them.
+func (f *tupleFile) rewind() error {
+	if err := f.writer.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	f.reader = bufio.NewReader(f.file)
+
+	return nil
+}
+
+// Read the next tuple. The tuple is not valid at the end of the file.
+func (f *tupleFile) next() (tuple, bool, error) {
+	var t tuple
+
+	if _, err := io.ReadFull(f.reader, f.buffer[:]); errors.Is(err, io.EOF) {
+		return t, false, nil
+	} else if err != nil {
+		return t, false, err
+	}
+
+	for i := range t {
+		t[i] = int64(binary.LittleEndian.Uint64(f.buffer[8*i:]))
+	}
+
+	return t, true, nil
+}
+
+// Close and remove the file.
+func (f *tupleFile) close() {
+	f.file.Close()
+	os.Remove(f.file.Name())
+}
+
+// Sequence of tuples read in order.
+type tupleSource interface {
+	next() (tuple, bool, error)
+}
+
+// In-memory tuples read in order.
+type tupleSlice struct {
+	tuples []tuple
+	index  int
+}
+
+// Read the next tuple.
+func (s *tupleSlice) next() (tuple, bool, error) {
+	if s.index == len(s.tuples) {
+		return tuple{}, false, nil
+	}
+
+	s.index++
+
+	return s.tuples[s.index-1], true, nil
+}
+
+// External merge sort of tuples. Tuples are sorted in memory in runs of a
+// bounded size spilled to temporary files and the runs are merged while
+// reading, so the memory used is independent of the number of tuples.
+type tupleSorter struct {
+	dir     string
+	runSize int
+	tuples  []tuple
+	runs    []*tupleFile
+	merge   tupleMerge
+}
+
+// Construct a tupleSorter spilling runs of a number of tuples to a directory.
+func newTupleSorter(dir string, runSize int) *tupleSorter {
+	return &tupleSorter{
+		dir:     dir,
+		runSize: runSize,
+		tuples:  make([]tuple, 0, min(runSize, 1<<16)),
+		runs:    make([]*tupleFile, 0),
+	}
+}
+
+// Add a tuple to sort.
+func (s *tupleSorter) add(t tuple) error {
+	s.tuples = append(s.tuples, t)
+
+	if len(s.tuples) < s.runSize {
+		return nil
+	}
+
+	return s.spill()
+}
+
+// Sort the tuples in memory and write them as a run.
+func (s *tupleSorter) spill() error {
+	slices.SortFunc(s.tuples, compareTuples)
+
+	run, err := newTupleFile(s.dir)
+	if err != nil {
+		return err
+	}
+
+	s.runs = append(s.runs, run)
+
+	for _, t := range s.tuples {
+		if err := run.write(t); err != nil {
+			return err
+		}
+	}
+
+	s.tuples = s.tuples[:0]
+
+	return run.rewind()
+}
+
+// Sort the tuples added and start reading them in order (see next).
+func (s *tupleSorter) sort() error {
+	slices.SortFunc(s.tuples, compareTuples)
+	sources := []tupleSource{&tupleSlice{tuples: s.tuples}}
+
+	for _, run := range s.runs {
+		sources = append(sources, run)
+	}
+
+	s.merge = tupleMerge{sources: sources}
+
+	for i, source := range sources {
+		t, ok, err := source.next()
+		if err != nil {
+			return err
+		} else if ok {
+			s.merge.heads = append(s.merge.heads, tupleHead{t, i})
+		}
+	}
+
+	heap.Init(&s.merge)
+
+	return nil
+}
+
+// Read the next tuple in order once sorted.
+func (s *tupleSorter) next() (tuple, bool, error) {
+	if len(s.merge.heads) == 0 {
+		return tuple{}, false, nil
+	}
+
+	head := s.merge.heads[0]
+	t, ok, err := s.merge.sources[head.source].next()
+
+	if err != nil {
+		return tuple{}, false, err
+	} else if ok {
+		s.merge.heads[0].value = t
+		heap.Fix(&s.merge, 0)
+	} else {
+		heap.Pop(&s.merge)
+	}
+
+	return head.value, true, nil
+}
+
+// Remove the temporary files of the runs.
+func (s *tupleSorter) close() {
+	for _, run := range s.runs {
+		run.close()
+	}
+
+	s.runs = s.runs[:0]
+}
+
+// Next tuple of a source being merged.
+type tupleHead struct {
+	value  tuple
+	source int
+}
+
+// Heap of the next tuples of the sources being merged (container/heap).
+type tupleMerge struct {
+	sources []tupleSource
+	heads   []tupleHead
+}
+
+// Implement the heap.Interface interface.
+func (m tupleMerge) Len() int {
+	return len(m.heads)
+}
+
+// Implement the heap.Interface interface.
+func (m tupleMerge) Less(i, j int) bool {
+	return compareTuples(m.heads[i].value, m.heads[j].value) < 0
+}
+
+// Implement the heap.Interface interface.
+func (m tupleMerge) Swap(i, j int) {
+	m.heads[i], m.heads[j] = m.heads[j], m.heads[i]
+}
+
+// Implement the heap.Interface interface.
+func (m *tupleMerge) Push(value any) {
+	m.heads = append(m.heads, value.(tupleHead))
+}
+
+// Implement the heap.Interface interface.
+func (m *tupleMerge) Pop() any {
+	head := m.heads[len(m.heads)-1]
+	m.heads = m.heads[:len(m.heads)-1]
+	return head
+}
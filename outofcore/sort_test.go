@@ -0,0 +1,50 @@
+package outofcore
+
+import (
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test sorting tuples externally in runs.
+func TestTupleSorter(t *testing.T) {
+	dir := t.TempDir()
+	random := rand.New(rand.NewSource(1))
+
+	for _, runSize := range []int{3, 7, 100, 10000} {
+		sorter := newTupleSorter(dir, runSize)
+		tuples := make([]tuple, 1000)
+
+		for i := range tuples {
+			tuples[i] = tuple{random.Int63n(10), random.Int63n(10), random.Int63() - random.Int63(), int64(i)}
+			assert.Empty(t, sorter.add(tuples[i]))
+		}
+
+		assert.Equal(t, len(tuples)/runSize, len(sorter.runs))
+		assert.Empty(t, sorter.sort())
+
+		sorted := make([]tuple, 0, len(tuples))
+
+		for {
+			value, ok, err := sorter.next()
+			assert.Empty(t, err)
+
+			if !ok {
+				break
+			}
+
+			sorted = append(sorted, value)
+		}
+
+		slices.SortFunc(tuples, compareTuples)
+		assert.Equal(t, tuples, sorted)
+
+		sorter.close()
+		entries, err := os.ReadDir(dir)
+		assert.Empty(t, err)
+		assert.Empty(t, entries)
+	}
+}
@@ -0,0 +1,388 @@
+package outofcore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Elements of a mesh changed or skipped by welding out of core.
+type WeldReport struct {
+	// Number of vertices merged into another vertex.
+	WeldedVertices int
+
+	// Number of faces skipped for repeating a vertex once welded.
+	DegenerateFaces int
+}
+
+// Weld the vertices of a mesh in the same cell of a grid with a spacing of
+// the tolerance (only coincident vertices if zero) out of core. Unlike
+// welding in memory, vertices within the tolerance of each other but in
+// adjacent cells are not merged. The vertices keep the order of their first
+// occurrence. Consecutive repeated vertices of a face are merged and faces
+// repeating a vertex otherwise or with fewer than three vertices are
+// skipped. The writer is closed once all chunks are written.
+func Weld(reader ChunkReader, writer ChunkWriter, tolerance float64, options Options) (WeldReport, error) {
+	w, err := newWelder(options)
+	if err != nil {
+		return WeldReport{}, err
+	}
+	defer w.close()
+
+	if err := w.read(reader, tolerance); err != nil {
+		return WeldReport{}, err
+	}
+
+	if err := w.writeVertices(writer); err != nil {
+		return w.report, err
+	}
+
+	if err := w.resolveFaces(); err != nil {
+		return w.report, err
+	}
+
+	if err := w.writeFaces(writer); err != nil {
+		return w.report, err
+	}
+
+	return w.report, writer.Close()
+}
+
+// State of welding a mesh out of core. Each step streams sorted records
+// into the sorter of the next:
+//
+//   - keys: (cell, vertex) sorted to group the vertices of a cell
+//   - groups: (first vertex of the cell, vertex) sorted to number the cells
+//   - remap: (vertex, welded vertex) sorted to join the corners
+//   - corners: (vertex, face, corner) sorted to join the remap
+//   - resolved: (face, corner, welded vertex) sorted to rebuild the faces
+type welder struct {
+	options  Options
+	vertices *tupleFile
+	faces    *tupleFile
+	keys     *tupleSorter
+	groups   *tupleSorter
+	remap    *tupleSorter
+	corners  *tupleSorter
+	resolved *tupleSorter
+	patches  []string
+	report   WeldReport
+}
+
+// Construct a welder with its temporary files.
+func newWelder(options Options) (*welder, error) {
+	dir, runSize := options.TempDir, options.getRunSize()
+
+	w := &welder{
+		options:  options,
+		keys:     newTupleSorter(dir, runSize),
+		groups:   newTupleSorter(dir, runSize),
+		remap:    newTupleSorter(dir, runSize),
+		corners:  newTupleSorter(dir, runSize),
+		resolved: newTupleSorter(dir, runSize),
+		patches:  make([]string, 0),
+	}
+
+	var err error
+
+	if w.vertices, err = newTupleFile(dir); err != nil {
+		return nil, err
+	}
+
+	if w.faces, err = newTupleFile(dir); err != nil {
+		w.vertices.close()
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Remove the temporary files.
+func (w *welder) close() {
+	w.vertices.close()
+	w.faces.close()
+
+	for _, sorter := range []*tupleSorter{w.keys, w.groups, w.remap, w.corners, w.resolved} {
+		sorter.close()
+	}
+}
+
+// Get the key of the cell of a vertex. The key of a zero tolerance is the
+// bits of the coordinates (with negative zero as zero).
+func getWeldKey(vertex meshx.Vector, tolerance float64) [3]int64 {
+	var key [3]int64
+
+	for i, value := range vertex {
+		if tolerance > 0 {
+			key[i] = int64(math.Floor(value / tolerance))
+		} else {
+			key[i] = int64(math.Float64bits(value + 0))
+		}
+	}
+
+	return key
+}
+
+// Read the chunks spooling the vertices and faces and keying the vertices
+// and corners.
+func (w *welder) read(reader ChunkReader, tolerance float64) error {
+	numberOfVertices, numberOfFaces := 0, 0
+
+	for {
+		chunk, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		w.patches = chunk.Patches
+
+		for _, vertex := range chunk.Vertices {
+			if err := w.vertices.write(encodeVector(vertex)); err != nil {
+				return err
+			}
+
+			key := getWeldKey(vertex, tolerance)
+
+			if err := w.keys.add(tuple{key[0], key[1], key[2], int64(numberOfVertices)}); err != nil {
+				return err
+			}
+
+			numberOfVertices++
+		}
+
+		for i, face := range chunk.Faces {
+			if err := w.faces.write(tuple{int64(len(face)), int64(chunk.FacePatches[i])}); err != nil {
+				return err
+			}
+
+			for j, vertex := range face {
+				if err := w.corners.add(tuple{int64(vertex), int64(numberOfFaces), int64(j)}); err != nil {
+					return err
+				}
+			}
+
+			numberOfFaces++
+		}
+	}
+
+	return nil
+}
+
+// Number the cells in the order of their first vertex and write the first
+// vertex of each.
+func (w *welder) writeVertices(writer ChunkWriter) error {
+	if err := w.keys.sort(); err != nil {
+		return err
+	}
+
+	var key tuple
+	first := int64(-1)
+
+	for {
+		t, ok, err := w.keys.next()
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
+		if first == -1 || [3]int64(t[:3]) != [3]int64(key[:3]) {
+			key, first = t, t[3]
+		}
+
+		if err := w.groups.add(tuple{first, t[3]}); err != nil {
+			return err
+		}
+	}
+
+	if err := w.groups.sort(); err != nil {
+		return err
+	}
+
+	if err := w.vertices.rewind(); err != nil {
+		return err
+	}
+
+	chunk := newChunk(w.patches)
+	welded, position := int64(-1), int64(0)
+	first = -1
+
+	for {
+		t, ok, err := w.groups.next()
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
+		if t[0] != first {
+			first = t[0]
+			welded++
+
+			for ; position <= first; position++ {
+				vertex, ok, err := w.vertices.next()
+				if err != nil {
+					return err
+				} else if !ok {
+					return io.ErrUnexpectedEOF
+				}
+
+				if position == first {
+					chunk.Vertices = append(chunk.Vertices, decodeVector(vertex))
+				}
+			}
+
+			if len(chunk.Vertices) >= w.options.getChunkSize() {
+				if err := writer.WriteChunk(chunk); err != nil {
+					return err
+				}
+
+				chunk = newChunk(w.patches)
+			}
+		} else {
+			w.report.WeldedVertices++
+		}
+
+		if err := w.remap.add(tuple{t[1], welded}); err != nil {
+			return err
+		}
+	}
+
+	if len(chunk.Vertices) != 0 {
+		return writer.WriteChunk(chunk)
+	}
+
+	return nil
+}
+
+// Join the corners of the faces with the welded vertices.
+func (w *welder) resolveFaces() error {
+	if err := w.remap.sort(); err != nil {
+		return err
+	}
+
+	if err := w.corners.sort(); err != nil {
+		return err
+	}
+
+	remap, hasRemap, err := w.remap.next()
+	if err != nil {
+		return err
+	}
+
+	for {
+		corner, ok, err := w.corners.next()
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
+		for hasRemap && remap[0] < corner[0] {
+			if remap, hasRemap, err = w.remap.next(); err != nil {
+				return err
+			}
+		}
+
+		if !hasRemap || remap[0] != corner[0] {
+			return fmt.Errorf("face %d: %w", corner[1], meshx.ErrInvalidFace)
+		}
+
+		if err := w.resolved.add(tuple{corner[1], corner[2], remap[1]}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Rebuild the faces from their welded corners and write them.
+func (w *welder) writeFaces(writer ChunkWriter) error {
+	if err := w.resolved.sort(); err != nil {
+		return err
+	}
+
+	if err := w.faces.rewind(); err != nil {
+		return err
+	}
+
+	chunk := newChunk(w.patches)
+
+	for {
+		header, ok, err := w.faces.next()
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
+		face := make([]int, 0, header[0])
+
+		for range header[0] {
+			corner, ok, err := w.resolved.next()
+			if err != nil {
+				return err
+			} else if !ok {
+				return io.ErrUnexpectedEOF
+			}
+
+			if vertex := int(corner[2]); len(face) == 0 || face[len(face)-1] != vertex {
+				face = append(face, vertex)
+			}
+		}
+
+		for len(face) > 1 && face[0] == face[len(face)-1] {
+			face = face[:len(face)-1]
+		}
+
+		if len(face) < 3 || hasRepeatedVertex(face) {
+			w.report.DegenerateFaces++
+			continue
+		}
+
+		chunk.Faces = append(chunk.Faces, face)
+		chunk.FacePatches = append(chunk.FacePatches, int(header[1]))
+
+		if len(chunk.Faces) >= w.options.getChunkSize() {
+			if err := writer.WriteChunk(chunk); err != nil {
+				return err
+			}
+
+			chunk = newChunk(w.patches)
+		}
+	}
+
+	if len(chunk.Faces) != 0 {
+		return writer.WriteChunk(chunk)
+	}
+
+	return nil
+}
+
+// Construct an empty Chunk of the patches.
+func newChunk(patches []string) *Chunk {
+	return &Chunk{
+		Vertices:    make([]meshx.Vector, 0),
+		Faces:       make([][]int, 0),
+		FacePatches: make([]int, 0),
+		Patches:     patches,
+	}
+}
+
+// Check if a face repeats a vertex.
+func hasRepeatedVertex(face []int) bool {
+	for i := range face {
+		for j := i + 1; j < len(face); j++ {
+			if face[i] == face[j] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package outofcore
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/stretchr/testify/assert"
+)
+
+// Write the box with a vertex for each face corner offset by a distance and
+// a face of three coincident vertices.
+func newTestUnweldedBox(t *testing.T, offset float64) string {
+	source, err := meshx.ReadOBJFromPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	var builder strings.Builder
+	n := 1
+
+	for i := 0; i < source.GetNumberOfFaces(); i++ {
+		face := source.GetFace(i)
+		builder.WriteString(fmt.Sprintf("g %s\n", source.GetPatch(source.GetFacePatch(i))))
+
+		for _, vertex := range face {
+			p := source.GetVertex(vertex).Add(meshx.NewVector(offset, offset, 0))
+			builder.WriteString(fmt.Sprintf("v %g %g %g\n", p[0], p[1], p[2]))
+		}
+
+		builder.WriteString("f")
+
+		for range face {
+			builder.WriteString(fmt.Sprintf(" %d", n))
+			n++
+		}
+
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("v 0 0 0\nv %g 0 0\nv 0 %g 0\nf %d %d %d\n", offset, offset, n, n+1, n+2))
+
+	return builder.String()
+}
+
+// Test welding a mesh out of core.
+func TestWeld(t *testing.T) {
+	for _, tolerance := range []float64{0, 0.25} {
+		offset := 0.0
+
+		if tolerance != 0 {
+			offset = 1e-6
+		}
+
+		reader, err := NewOBJChunkReader(strings.NewReader(newTestUnweldedBox(t, offset)))
+		assert.Empty(t, err)
+		reader.SetChunkSize(4)
+
+		var buffer bytes.Buffer
+		options := Options{TempDir: t.TempDir(), RunSize: 5, ChunkSize: 3}
+
+		report, err := Weld(reader, NewOBJChunkWriter(&buffer), tolerance, options)
+		assert.Empty(t, err)
+		assert.Equal(t, 29-8, report.WeldedVertices)
+		assert.Equal(t, 1, report.DegenerateFaces)
+
+		objReader := meshx.NewOBJReader(&buffer)
+		assert.Empty(t, objReader.Read())
+		assert.Equal(t, 8, objReader.GetNumberOfVertices())
+		assert.Equal(t, 7, objReader.GetNumberOfFaces())
+		assert.Equal(t, meshx.NewVector(offset, offset, 0), objReader.GetVertex(0))
+		assert.Equal(t, "bottom", objReader.GetPatch(objReader.GetFacePatch(6)))
+
+		mesh, err := halfedge.NewHalfEdgeMesh(objReader)
+		assert.Empty(t, err)
+		assert.True(t, mesh.IsClosed())
+	}
+}
+
+// Test welding a mesh referencing a missing vertex.
+func TestWeldMissingVertex(t *testing.T) {
+	reader, err := NewOBJChunkReader(strings.NewReader("v 0 0 0\nv 1 0 0\nf 1 2 3\n"))
+	assert.Empty(t, err)
+
+	var buffer bytes.Buffer
+	_, err = Weld(reader, NewOBJChunkWriter(&buffer), 0, Options{TempDir: t.TempDir()})
+	assert.ErrorIs(t, err, meshx.ErrInvalidFace)
+}
@@ -0,0 +1,284 @@
+package outofcore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// OBJChunkWriter manages writing an OBJ (WaveFront) file in chunks. The
+// vertices and faces are written in the order of the chunks with a group
+// statement wherever the patch changes.
+type OBJChunkWriter struct {
+	writer      *bufio.Writer
+	patch       int
+	floatFormat meshx.FloatFormat
+}
+
+// Construct an OBJChunkWriter from an io.Writer interface.
+func NewOBJChunkWriter(writer io.Writer) *OBJChunkWriter {
+	return &OBJChunkWriter{writer: bufio.NewWriter(writer), patch: -1}
+}
+
+// Set the formatting of the coordinates written (see OBJWriter).
+func (w *OBJChunkWriter) SetFloatFormat(format meshx.FloatFormat) {
+	w.floatFormat = format
+}
+
+// Write a chunk.
+func (w *OBJChunkWriter) WriteChunk(chunk *Chunk) error {
+	for _, vertex := range chunk.Vertices {
+		line := "v " + w.floatFormat.FormatVector(vertex) + "\n"
+		if _, err := w.writer.WriteString(line); err != nil {
+			return err
+		}
+	}
+
+	for i, face := range chunk.Faces {
+		if patch := chunk.FacePatches[i]; patch != w.patch {
+			line := meshx.PrefixGroup + "\n"
+
+			if patch != -1 {
+				line = fmt.Sprintf("g %s\n", chunk.Patches[patch])
+			}
+
+			if _, err := w.writer.WriteString(line); err != nil {
+				return err
+			}
+
+			w.patch = patch
+		}
+
+		w.writer.WriteString(meshx.PrefixFace)
+
+		for _, vertex := range face {
+			w.writer.WriteString(fmt.Sprintf(" %d", vertex+1))
+		}
+
+		if _, err := w.writer.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush the data written.
+func (w *OBJChunkWriter) Close() error {
+	return w.writer.Flush()
+}
+
+// PLYChunkWriter manages writing a binary little endian PLY (Stanford) file
+// in chunks. The counts of the header are only known once all chunks are
+// written, so the vertices and faces are spooled to temporary files until
+// closed. Face patches are written as by meshx.PLYWriter.
+type PLYChunkWriter struct {
+	writer           io.Writer
+	vertices         *tupleFile
+	faces            *tupleFile
+	patches          []string
+	numberOfVertices int
+	numberOfFaces    int
+	hasPatches       bool
+	err              error
+}
+
+// Construct a PLYChunkWriter from an io.Writer interface spooling to a
+// directory (os.TempDir if empty).
+func NewPLYChunkWriter(writer io.Writer, dir string) *PLYChunkWriter {
+	w := &PLYChunkWriter{writer: writer, patches: make([]string, 0)}
+
+	if w.vertices, w.err = newTupleFile(dir); w.err == nil {
+		w.faces, w.err = newTupleFile(dir)
+	}
+
+	return w
+}
+
+// Write a chunk.
+func (w *PLYChunkWriter) WriteChunk(chunk *Chunk) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	for _, vertex := range chunk.Vertices {
+		if err := w.vertices.write(encodeVector(vertex)); err != nil {
+			return err
+		}
+	}
+
+	for i, face := range chunk.Faces {
+		if len(face) > math.MaxUint8 {
+			return meshx.ErrInvalidFace
+		}
+
+		if err := writeFace(w.faces, face, chunk.FacePatches[i]); err != nil {
+			return err
+		}
+
+		w.hasPatches = w.hasPatches || chunk.FacePatches[i] != -1
+	}
+
+	w.numberOfVertices += len(chunk.Vertices)
+	w.numberOfFaces += len(chunk.Faces)
+
+	if len(chunk.Patches) > len(w.patches) {
+		w.patches = chunk.Patches
+	}
+
+	return nil
+}
+
+// Write the header followed by the spooled vertices and faces and remove
+// the temporary files.
+func (w *PLYChunkWriter) Close() error {
+	defer func() {
+		if w.vertices != nil {
+			w.vertices.close()
+		}
+
+		if w.faces != nil {
+			w.faces.close()
+		}
+	}()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	writer := bufio.NewWriter(w.writer)
+	header := []string{"ply", "format binary_little_endian 1.0"}
+
+	if w.hasPatches {
+		for i, patch := range w.patches {
+			header = append(header, fmt.Sprintf("comment patch %d %s", i, patch))
+		}
+	}
+
+	header = append(header, fmt.Sprintf("element vertex %d", w.numberOfVertices))
+	header = append(header, "property double x", "property double y", "property double z")
+	header = append(header, fmt.Sprintf("element face %d", w.numberOfFaces))
+	header = append(header, "property list uchar int vertex_indices")
+
+	if w.hasPatches {
+		header = append(header, "property int patch")
+	}
+
+	header = append(header, "end_header")
+
+	for _, line := range header {
+		if _, err := writer.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+
+	if err := w.vertices.rewind(); err != nil {
+		return err
+	}
+
+	for {
+		t, ok, err := w.vertices.next()
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
+		binary.Write(writer, binary.LittleEndian, decodeVector(t))
+	}
+
+	if err := w.faces.rewind(); err != nil {
+		return err
+	}
+
+	for {
+		face, patch, ok, err := readFace(w.faces)
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+
+		writer.WriteByte(byte(len(face)))
+
+		for _, vertex := range face {
+			binary.Write(writer, binary.LittleEndian, int32(vertex))
+		}
+
+		if w.hasPatches {
+			binary.Write(writer, binary.LittleEndian, int32(patch))
+		}
+	}
+
+	return writer.Flush()
+}
+
+// Encode a vector as a tuple.
+func encodeVector(vector meshx.Vector) tuple {
+	return tuple{
+		int64(math.Float64bits(vector[0])),
+		int64(math.Float64bits(vector[1])),
+		int64(math.Float64bits(vector[2])),
+	}
+}
+
+// Decode a vector from a tuple.
+func decodeVector(t tuple) meshx.Vector {
+	return meshx.NewVector(
+		math.Float64frombits(uint64(t[0])),
+		math.Float64frombits(uint64(t[1])),
+		math.Float64frombits(uint64(t[2])),
+	)
+}
+
+// Write a face and its patch as a tuple of its size and patch followed by
+// tuples of up to four vertices.
+func writeFace(file *tupleFile, face []int, patch int) error {
+	if err := file.write(tuple{int64(len(face)), int64(patch)}); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(face); i += 4 {
+		var t tuple
+
+		for j := i; j < min(i+4, len(face)); j++ {
+			t[j-i] = int64(face[j])
+		}
+
+		if err := file.write(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Read a face and its patch written by writeFace. The face is not valid at
+// the end of the file.
+func readFace(file *tupleFile) ([]int, int, bool, error) {
+	header, ok, err := file.next()
+	if err != nil || !ok {
+		return nil, 0, false, err
+	}
+
+	face := make([]int, header[0])
+
+	for i := 0; i < len(face); i += 4 {
+		t, ok, err := file.next()
+		if err != nil {
+			return nil, 0, false, err
+		} else if !ok {
+			return nil, 0, false, io.ErrUnexpectedEOF
+		}
+
+		for j := i; j < min(i+4, len(face)); j++ {
+			face[j] = int(t[j-i])
+		}
+	}
+
+	return face, int(header[1]), true, nil
+}
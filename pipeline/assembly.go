@@ -0,0 +1,361 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/exchange"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidPart   = errors.New("invalid assembly part")
+	ErrEmptyAssembly = errors.New("empty assembly")
+)
+
+// Named part of an Assembly: a mesh in its own coordinates placed in the
+// assembly by a transformation.
+type Part struct {
+	Name      string
+	Mesh      *halfedge.HalfEdgeMesh
+	Transform meshx.Matrix4
+}
+
+// Scene of named parts each read from its own mesh file. The parts are kept
+// separate and may be flattened into a single mesh. A file read for more
+// than one part is read once and its mesh is shared by the parts (instanced),
+// so a change to the mesh of one changes the others.
+type Assembly struct {
+	parts      []Part
+	indexParts map[string]int
+	indexPaths map[string]*halfedge.HalfEdgeMesh
+}
+
+// Manifest of an Assembly in JSON or YAML. The paths of the parts are
+// relative to the manifest and the transformations are row-major (the
+// identity if omitted).
+type assemblyManifest struct {
+	Parts []assemblyManifestPart `json:"parts" yaml:"parts"`
+}
+
+// Part of the manifest of an Assembly.
+type assemblyManifestPart struct {
+	Name      string    `json:"name" yaml:"name"`
+	Path      string    `json:"path" yaml:"path"`
+	Transform []float64 `json:"transform,omitempty" yaml:"transform,omitempty"`
+}
+
+// Construct an empty Assembly.
+func NewAssembly() *Assembly {
+	return &Assembly{
+		parts:      make([]Part, 0),
+		indexParts: make(map[string]int),
+		indexPaths: make(map[string]*halfedge.HalfEdgeMesh),
+	}
+}
+
+// Read an Assembly from a JSON or YAML manifest reader. The part files are
+// read relative to a directory.
+func ReadAssembly(reader io.Reader, dir string) (*Assembly, error) {
+	assembly := NewAssembly()
+
+	err := readAssemblyManifest(reader, func(name, path string, transform meshx.Matrix4) error {
+		return assembly.ReadPart(name, filepath.Join(dir, path), transform)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return assembly, nil
+}
+
+// Read an Assembly from a JSON or YAML manifest file path.
+func ReadAssemblyFromPath(path string) (*Assembly, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadAssembly(file, filepath.Dir(path))
+}
+
+// Read an Assembly from a JSON or YAML manifest file of a file system (e.g.
+// an embed.FS). The part files are read relative to the manifest in the
+// file system (see ReadMeshFS).
+func ReadAssemblyFS(fsys fs.FS, name string) (*Assembly, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	assembly := NewAssembly()
+
+	err = readAssemblyManifest(file, func(partName, partPath string, transform meshx.Matrix4) error {
+		partPath = path.Join(path.Dir(name), partPath)
+
+		return assembly.readPart(partName, partPath, transform, func() (*halfedge.HalfEdgeMesh, error) {
+			return ReadMeshFS(fsys, partPath)
+		})
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return assembly, nil
+}
+
+// Decode a manifest and read each part (by its name, path and transform).
+func readAssemblyManifest(reader io.Reader, readPart func(string, string, meshx.Matrix4) error) error {
+	var manifest assemblyManifest
+
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&manifest); err != nil {
+		return err
+	}
+
+	for _, part := range manifest.Parts {
+		transform := meshx.NewIdentityMatrix4()
+
+		if len(part.Transform) != 0 {
+			if len(part.Transform) != 16 {
+				return fmt.Errorf("%w: %s", ErrInvalidPart, part.Name)
+			}
+
+			for i, value := range part.Transform {
+				transform[i/4][i%4] = value
+			}
+		}
+
+		if err := readPart(part.Name, part.Path, transform); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Add a part. The name must be unique and usable as a file name and the
+// transform must not be singular (e.g. a zero scale).
+func (a *Assembly) AddPart(name string, mesh *halfedge.HalfEdgeMesh, transform meshx.Matrix4) error {
+	if _, ok := a.indexParts[name]; ok || !isValidPartName(name) {
+		return fmt.Errorf("%w: %s", ErrInvalidPart, name)
+	}
+
+	if transform.Determinant() == 0 {
+		return fmt.Errorf("%w: %s", meshx.ErrSingularTransform, name)
+	}
+
+	a.indexParts[name] = len(a.parts)
+	a.parts = append(a.parts, Part{name, mesh, transform})
+
+	return nil
+}
+
+// Read a part from a mesh file path by its extension (see ReadMeshFromPath).
+// The mesh of a path already read is shared.
+func (a *Assembly) ReadPart(name, path string, transform meshx.Matrix4) error {
+	path = filepath.Clean(path)
+
+	return a.readPart(name, path, transform, func() (*halfedge.HalfEdgeMesh, error) {
+		return ReadMeshFromPath(path)
+	})
+}
+
+// Read a part by a function unless the mesh of its (clean) path is already
+// read.
+func (a *Assembly) readPart(name, path string, transform meshx.Matrix4, read func() (*halfedge.HalfEdgeMesh, error)) error {
+	if _, ok := a.indexParts[name]; ok || !isValidPartName(name) {
+		return fmt.Errorf("%w: %s", ErrInvalidPart, name)
+	}
+
+	mesh, ok := a.indexPaths[path]
+
+	if !ok {
+		var err error
+
+		if mesh, err = read(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		a.indexPaths[path] = mesh
+	}
+
+	return a.AddPart(name, mesh, transform)
+}
+
+// Check if a part name is usable as a file name.
+func isValidPartName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// Get the number of parts.
+func (a *Assembly) GetNumberOfParts() int {
+	return len(a.parts)
+}
+
+// Get a part by index.
+func (a *Assembly) GetPart(index int) Part {
+	return a.parts[index]
+}
+
+// Get a part by name.
+func (a *Assembly) GetPartByName(name string) (Part, bool) {
+	if index, ok := a.indexParts[name]; ok {
+		return a.parts[index], true
+	}
+
+	return Part{}, false
+}
+
+// Get a copy of the mesh of a part placed by its transformation.
+func (a *Assembly) GetPlacedMesh(index int) (*halfedge.HalfEdgeMesh, error) {
+	part := a.parts[index]
+
+	mesh, err := halfedge.NewHalfEdgeMesh(part.Mesh.GetMeshReader())
+	if err != nil {
+		return nil, err
+	}
+
+	mesh.Transform(part.Transform)
+
+	return mesh, nil
+}
+
+// Flatten the parts placed by their transformations into a single mesh. The
+// patches are prefixed by the name of their part ("part/patch") and the
+// faces of a part without a patch are assigned to a patch named by the part.
+func (a *Assembly) Flatten() (*halfedge.HalfEdgeMesh, error) {
+	if len(a.parts) == 0 {
+		return nil, ErrEmptyAssembly
+	}
+
+	var flattened *halfedge.HalfEdgeMesh
+
+	for i, part := range a.parts {
+		mesh, err := a.GetPlacedMesh(i)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := range mesh.GetNumberOfPatches() {
+			mesh.SetPatchName(j, part.Name+"/"+mesh.GetPatch(j).Name)
+		}
+
+		patch := -1
+
+		for j := range mesh.GetNumberOfFaces() {
+			if mesh.GetFace(j).Patch != -1 {
+				continue
+			}
+
+			if patch == -1 {
+				patch = mesh.AddPatch(part.Name)
+			}
+
+			mesh.SetFacePatch(j, patch)
+		}
+
+		if flattened == nil {
+			flattened = mesh
+		} else {
+			flattened.Merge(mesh)
+		}
+	}
+
+	return flattened, nil
+}
+
+// Write the Assembly to a JSON manifest file path. The mesh of each part is
+// written next to the manifest in its own coordinates named by the part with
+// the extension of a format (e.g. ".obj", see WriteMeshToPath). A mesh shared
+// by parts is written once (named by the first part).
+func (a *Assembly) WriteToPath(path, format string) error {
+	manifest := assemblyManifest{make([]assemblyManifestPart, len(a.parts))}
+	dir := filepath.Dir(path)
+	indexMeshes := make(map[*halfedge.HalfEdgeMesh]string)
+
+	for i, part := range a.parts {
+		name, ok := indexMeshes[part.Mesh]
+
+		if !ok {
+			name = part.Name + format
+
+			if err := WriteMeshToPath(part.Mesh, filepath.Join(dir, name)); err != nil {
+				return fmt.Errorf("%s: %w", part.Name, err)
+			}
+
+			indexMeshes[part.Mesh] = name
+		}
+
+		manifest.Parts[i] = assemblyManifestPart{Name: part.Name, Path: name}
+
+		if part.Transform != meshx.NewIdentityMatrix4() {
+			for _, row := range part.Transform {
+				manifest.Parts[i].Transform = append(manifest.Parts[i].Transform, row[:]...)
+			}
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(manifest)
+}
+
+// Write the Assembly to a glTF file (binary if requested). Each part is a
+// node and a mesh shared by parts is written once (instanced). The
+// coordinates are converted to the Y-up system of glTF.
+func (a *Assembly) WriteGLTF(writer io.Writer, binary bool) error {
+	options := exchange.GLTFOptions{
+		Binary:     binary,
+		Conversion: getGLTFConversion(),
+	}
+
+	gltfWriter := exchange.NewGLTFWriter(writer, options)
+	indexMeshes := make(map[*halfedge.HalfEdgeMesh]int)
+
+	for _, part := range a.parts {
+		mesh, ok := indexMeshes[part.Mesh]
+
+		if !ok {
+			mesh = gltfWriter.AddMesh(part.Name, part.Mesh.GetMeshReader())
+			indexMeshes[part.Mesh] = mesh
+		}
+
+		gltfWriter.AddNode(part.Name, mesh, part.Transform)
+	}
+
+	return gltfWriter.Write()
+}
+
+// Write the Assembly to a glTF file path (binary for a .glb extension). See
+// WriteGLTF.
+func (a *Assembly) WriteGLTFToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return a.WriteGLTF(file, getFormat(path) == ".glb")
+}
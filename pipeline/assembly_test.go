@@ -0,0 +1,157 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test flattening an assembly of two parts.
+func TestAssemblyFlatten(t *testing.T) {
+	assembly := NewAssembly()
+	offset := meshx.NewTranslationMatrix4(meshx.NewVector(2, 0, 0))
+
+	assert.Empty(t, assembly.ReadPart("a", "../testdata/box.patches.obj", meshx.NewIdentityMatrix4()))
+	assert.Empty(t, assembly.ReadPart("b", "../testdata/box.obj", offset))
+	assert.ErrorIs(t, assembly.ReadPart("b", "../testdata/box.obj", offset), ErrInvalidPart)
+	assert.ErrorIs(t, assembly.ReadPart("c/d", "../testdata/box.obj", offset), ErrInvalidPart)
+	assert.Equal(t, 2, assembly.GetNumberOfParts())
+
+	part, ok := assembly.GetPartByName("b")
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, part.Mesh.GetAABB().GetMaxBound()[0])
+
+	placed, err := assembly.GetPlacedMesh(1)
+	assert.Empty(t, err)
+	assert.Equal(t, 2.5, placed.GetAABB().GetMaxBound()[0])
+	assert.Equal(t, 0.5, part.Mesh.GetAABB().GetMaxBound()[0])
+
+	mesh, err := assembly.Flatten()
+	assert.Empty(t, err)
+	assert.Equal(t, 19, mesh.GetNumberOfFaces())
+	assert.Equal(t, 7, mesh.GetNumberOfPatches())
+	assert.Equal(t, "a/front", mesh.GetPatch(0).Name)
+	assert.Equal(t, "b", mesh.GetPatch(6).Name)
+	assert.Equal(t, 12, len(mesh.GetPatchFaces(6)))
+	assert.Equal(t, 2.5, mesh.GetAABB().GetMaxBound()[0])
+
+	_, err = NewAssembly().Flatten()
+	assert.ErrorIs(t, err, ErrEmptyAssembly)
+}
+
+// Test writing and reading an assembly manifest.
+func TestAssemblyWriteRead(t *testing.T) {
+	assembly := NewAssembly()
+	offset := meshx.NewTranslationMatrix4(meshx.NewVector(2, 0, 0))
+	assert.Empty(t, assembly.ReadPart("a", "../testdata/box.patches.obj", meshx.NewIdentityMatrix4()))
+	assert.Empty(t, assembly.ReadPart("b", "../testdata/box.obj", offset))
+
+	path := filepath.Join(t.TempDir(), "assembly.json")
+	assert.Empty(t, assembly.WriteToPath(path, ".ply"))
+
+	result, err := ReadAssemblyFromPath(path)
+	assert.Empty(t, err)
+	assert.Equal(t, 2, result.GetNumberOfParts())
+
+	for i := range result.GetNumberOfParts() {
+		expected, actual := assembly.GetPart(i), result.GetPart(i)
+		assert.Equal(t, expected.Name, actual.Name)
+		assert.Equal(t, expected.Transform, actual.Transform)
+		assert.Equal(t, expected.Mesh.GetNumberOfFaces(), actual.Mesh.GetNumberOfFaces())
+	}
+}
+
+// Test reading an assembly manifest from YAML.
+func TestReadAssembly(t *testing.T) {
+	data := "parts:\n  - name: a\n    path: box.obj\n    transform: [1, 0, 0]\n"
+	_, err := ReadAssembly(strings.NewReader(data), "../testdata")
+	assert.ErrorIs(t, err, ErrInvalidPart)
+
+	data = "parts:\n  - name: a\n    path: box.obj\n    transform: [1, 0, 0, 1, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1]\n"
+	assembly, err := ReadAssembly(strings.NewReader(data), "../testdata")
+	assert.Empty(t, err)
+	assert.Equal(t, 1.0, assembly.GetPart(0).Transform[0][3])
+
+	placed, err := assembly.GetPlacedMesh(0)
+	assert.Empty(t, err)
+	assert.Equal(t, 1.5, placed.GetAABB().GetMaxBound()[0])
+}
+
+// Test reading an assembly manifest and its parts from a file system.
+func TestReadAssemblyFS(t *testing.T) {
+	data, err := os.ReadFile("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	fsys := fstest.MapFS{
+		"scene/assembly.yaml": {Data: []byte("parts:\n  - name: a\n    path: box.obj\n  - name: b\n    path: ./box.obj\n")},
+		"scene/box.obj":       {Data: data},
+	}
+
+	assembly, err := ReadAssemblyFS(fsys, "scene/assembly.yaml")
+	assert.Empty(t, err)
+	assert.Equal(t, 2, assembly.GetNumberOfParts())
+	assert.Same(t, assembly.GetPart(0).Mesh, assembly.GetPart(1).Mesh)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, assembly.WriteGLTF(&buffer, true))
+	assert.Equal(t, "glTF", buffer.String()[:4])
+}
+
+// Test writing an assembly with a part file placed more than once.
+func TestAssemblyInstances(t *testing.T) {
+	assembly := NewAssembly()
+
+	for i, name := range []string{"a", "b", "c"} {
+		offset := meshx.NewTranslationMatrix4(meshx.NewVector(float64(2*i), 0, 0))
+		assert.Empty(t, assembly.ReadPart(name, "../testdata/box.obj", offset))
+	}
+
+	assert.Empty(t, assembly.ReadPart("d", "../testdata/box.patches.obj", meshx.NewIdentityMatrix4()))
+	assert.Same(t, assembly.GetPart(0).Mesh, assembly.GetPart(2).Mesh)
+	assert.NotSame(t, assembly.GetPart(0).Mesh, assembly.GetPart(3).Mesh)
+
+	dir := t.TempDir()
+	assert.Empty(t, assembly.WriteToPath(filepath.Join(dir, "assembly.json"), ".obj"))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.obj"))
+	assert.Empty(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.obj"), filepath.Join(dir, "d.obj")}, files)
+
+	result, err := ReadAssemblyFromPath(filepath.Join(dir, "assembly.json"))
+	assert.Empty(t, err)
+	assert.Same(t, result.GetPart(1).Mesh, result.GetPart(2).Mesh)
+
+	path := filepath.Join(dir, "assembly.gltf")
+	assert.Empty(t, assembly.WriteGLTFToPath(path))
+
+	data, err := os.ReadFile(path)
+	assert.Empty(t, err)
+
+	var document struct {
+		Nodes  []map[string]any `json:"nodes"`
+		Meshes []map[string]any `json:"meshes"`
+	}
+
+	assert.Empty(t, json.Unmarshal(data, &document))
+	assert.Equal(t, 5, len(document.Nodes))
+	assert.Equal(t, 2, len(document.Meshes))
+
+	assert.Empty(t, assembly.WriteGLTFToPath(filepath.Join(dir, "assembly.glb")))
+	assert.Empty(t, WriteMeshToPath(assembly.GetPart(3).Mesh, filepath.Join(dir, "part.glb")))
+}
+
+// Test adding a part with a singular transform.
+func TestAssemblySingularTransform(t *testing.T) {
+	assembly := NewAssembly()
+	err := assembly.ReadPart("a", "../testdata/box.obj", meshx.NewScaleMatrix4(meshx.NewVector(1, 0, 1)))
+
+	assert.ErrorIs(t, err, meshx.ErrSingularTransform)
+	assert.Equal(t, 0, assembly.GetNumberOfParts())
+}
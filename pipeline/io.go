@@ -0,0 +1,229 @@
+package pipeline
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/exchange"
+	"github.com/ajcurley/meshx-go/halfedge"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file format")
+)
+
+// Get the lowercase extension of a path ignoring a trailing .gz extension.
+func getFormat(path string) string {
+	path = strings.ToLower(path)
+	path = strings.TrimSuffix(path, ".gz")
+	return filepath.Ext(path)
+}
+
+// Read a mesh from a reader by its format (a file extension such as ".obj"
+// or ".obj.gz", see ReadMeshFromPath). The material libraries of an OBJ file
+// are not read (see ReadMeshFS).
+func ReadMesh(reader io.Reader, format string) (*halfedge.HalfEdgeMesh, error) {
+	return ReadMeshWithOptions(reader, format, halfedge.HalfEdgeMeshOptions{})
+}
+
+// Read a mesh from a reader by its format with options.
+func ReadMeshWithOptions(reader io.Reader, format string, options halfedge.HalfEdgeMeshOptions) (*halfedge.HalfEdgeMesh, error) {
+	source, err := readMeshSource(reader, getFormat(format))
+	if err != nil {
+		return nil, err
+	}
+
+	return halfedge.NewHalfEdgeMeshWithOptions(source, options)
+}
+
+// Read the source of a mesh from a reader by its format.
+func readMeshSource(reader io.Reader, format string) (meshx.MeshReader, error) {
+	var source interface {
+		meshx.MeshReader
+		Read() error
+	}
+
+	switch format {
+	case ".obj":
+		source = meshx.NewOBJReader(reader)
+	case ".ply":
+		source = meshx.NewPLYReader(reader)
+	case ".msh":
+		source = meshx.NewMSHReader(reader)
+	case ".mxcz":
+		source = exchange.NewCompressedReader(reader)
+	case ".drc":
+		source = exchange.NewDracoReader(reader)
+	case ".mxpm":
+		return exchange.ReadProgressiveMesh(reader)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	if err := source.Read(); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// Read a mesh from a file of a file system (e.g. an embed.FS) by its
+// extension (see ReadMeshFromPath). The material libraries of an OBJ file
+// are read relative to its directory in the file system.
+func ReadMeshFS(fsys fs.FS, name string) (*halfedge.HalfEdgeMesh, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if getFormat(name) != ".obj" {
+		return ReadMesh(file, name)
+	}
+
+	source := meshx.NewOBJReader(file)
+
+	if err := source.Read(); err != nil {
+		return nil, err
+	}
+
+	if err := source.ReadMaterialLibrariesFS(fsys, path.Dir(name)); err != nil {
+		return nil, err
+	}
+
+	return halfedge.NewHalfEdgeMesh(source)
+}
+
+// Read a mesh from a file path by its extension: .obj (and .obj.gz), .ply,
+// .msh (Gmsh), .mxcz (compressed), .drc (Draco) or .mxpm (progressive at
+// full resolution).
+func ReadMeshFromPath(path string) (*halfedge.HalfEdgeMesh, error) {
+	return ReadMeshFromPathWithOptions(path, halfedge.HalfEdgeMeshOptions{})
+}
+
+// Read a mesh from a file path by its extension with options.
+func ReadMeshFromPathWithOptions(path string, options halfedge.HalfEdgeMeshOptions) (*halfedge.HalfEdgeMesh, error) {
+	var source meshx.MeshReader
+	var err error
+
+	switch getFormat(path) {
+	case ".obj":
+		source, err = meshx.ReadOBJFromPath(path)
+	case ".ply":
+		source, err = meshx.ReadPLYFromPath(path)
+	case ".msh":
+		source, err = meshx.ReadMSHFromPath(path)
+	case ".mxcz":
+		source, err = exchange.ReadCompressedFromPath(path)
+	case ".drc":
+		source, err = exchange.ReadDracoFromPath(path)
+	case ".mxpm":
+		source, err = readProgressiveMesh(path)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return halfedge.NewHalfEdgeMeshWithOptions(source, options)
+}
+
+// Read a progressive mesh at full resolution from a file path.
+func readProgressiveMesh(path string) (meshx.MeshReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return exchange.ReadProgressiveMesh(file)
+}
+
+// Write a mesh to a writer by its format (a file extension such as ".obj"
+// or ".obj.gz", see WriteMeshToPath). The materials of an OBJ file are not
+// written.
+func WriteMesh(writer io.Writer, mesh *halfedge.HalfEdgeMesh, format string) error {
+	switch getFormat(format) {
+	case ".obj":
+		if strings.HasSuffix(strings.ToLower(format), ".gz") {
+			gzipWriter := gzip.NewWriter(writer)
+
+			if err := mesh.WriteOBJ(gzipWriter); err != nil {
+				return err
+			}
+
+			return gzipWriter.Close()
+		}
+
+		return mesh.WriteOBJ(writer)
+	case ".ply":
+		return mesh.WritePLY(writer, meshx.PLYFormatBinaryLittleEndian)
+	case ".msh":
+		return mesh.WriteMSH(writer, meshx.MSHVersion4)
+	case ".vtp":
+		return mesh.WriteVTP(writer)
+	case ".mxcz":
+		return exchange.WriteCompressed(writer, mesh.GetMeshReader(), exchange.CompressedOptions{})
+	case ".drc":
+		return exchange.WriteDraco(writer, mesh.GetMeshReader(), exchange.DracoOptions{})
+	case ".gltf", ".glb":
+		options := exchange.GLTFOptions{
+			Binary:     getFormat(format) == ".glb",
+			Conversion: getGLTFConversion(),
+		}
+
+		return exchange.WriteGLTF(writer, mesh.GetMeshReader(), options)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// Write a mesh to a file path by its extension: .obj (and .obj.gz), .ply,
+// .vtp, .msh (Gmsh), .mxcz (compressed), .drc (Draco) or .gltf and .glb
+// (converted to Y-up). The materials of an OBJ file are written to an MTL
+// file alongside it.
+func WriteMeshToPath(mesh *halfedge.HalfEdgeMesh, path string) error {
+	switch getFormat(path) {
+	case ".obj":
+		return mesh.WriteOBJToPath(path)
+	case ".ply", ".msh", ".vtp", ".mxcz", ".drc", ".gltf", ".glb":
+	default:
+		return ErrUnsupportedFormat
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return WriteMesh(file, mesh, path)
+}
+
+// Get the conversion from the coordinate system of a mesh (Z-up) to that of
+// glTF (Y-up).
+func getGLTFConversion() meshx.Conversion {
+	return meshx.NewConversion(meshx.CoordinateSystem{}, meshx.CoordinateSystem{UpAxis: meshx.UpAxisY})
+}
+
+// Decimate a mesh to (at most) the target number of triangles. Faces with
+// more than three vertices are triangulated.
+func Decimate(mesh *halfedge.HalfEdgeMesh, targetFaces int) (*halfedge.HalfEdgeMesh, error) {
+	progressive, err := exchange.NewProgressiveMesh(mesh.GetMeshReader(), targetFaces)
+	if err != nil {
+		return nil, err
+	}
+
+	progressive.SetLevel(0)
+
+	return halfedge.NewHalfEdgeMesh(progressive)
+}
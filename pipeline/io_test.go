@@ -0,0 +1,56 @@
+package pipeline
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test writing and reading a mesh through a buffer in each format.
+func TestWriteReadMesh(t *testing.T) {
+	mesh, err := ReadMeshFromPath("../testdata/box.obj")
+	assert.Empty(t, err)
+
+	for _, format := range []string{".obj", ".OBJ.gz", ".ply", ".msh", ".mxcz", ".drc"} {
+		var buffer bytes.Buffer
+		assert.Empty(t, WriteMesh(&buffer, mesh, format), format)
+
+		result, err := ReadMesh(&buffer, format)
+		assert.Empty(t, err, format)
+		assert.Equal(t, mesh.GetNumberOfVertices(), result.GetNumberOfVertices(), format)
+		assert.Equal(t, mesh.GetNumberOfFaces(), result.GetNumberOfFaces(), format)
+	}
+
+	for _, format := range []string{".vtp", ".gltf", ".glb"} {
+		var buffer bytes.Buffer
+		assert.Empty(t, WriteMesh(&buffer, mesh, format), format)
+		assert.NotZero(t, buffer.Len(), format)
+	}
+
+	var buffer bytes.Buffer
+	assert.ErrorIs(t, WriteMesh(&buffer, mesh, ".stl"), ErrUnsupportedFormat)
+
+	_, err = ReadMesh(&buffer, ".vtp")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+// Test reading a mesh with its material libraries from a file system.
+func TestReadMeshFS(t *testing.T) {
+	fsys := fstest.MapFS{}
+
+	for _, name := range []string{"box.materials.obj", "box.materials.mtl"} {
+		data, err := os.ReadFile("../testdata/" + name)
+		assert.Empty(t, err)
+		fsys["meshes/"+name] = &fstest.MapFile{Data: data}
+	}
+
+	mesh, err := ReadMeshFS(fsys, "meshes/box.materials.obj")
+	assert.Empty(t, err)
+	assert.Equal(t, 3, mesh.GetNumberOfMaterials())
+
+	_, err = ReadMeshFS(fsys, "meshes/missing.obj")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,294 @@
+// Package pipeline executes declarative sequences of mesh operations
+// described in JSON or YAML files.
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"time"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownOperation = errors.New("unknown operation")
+	ErrInvalidStep      = errors.New("invalid step")
+	ErrNoMesh           = errors.New("no mesh read")
+	ErrPatchNotFound    = errors.New("patch not found")
+)
+
+// Step of a pipeline. The operation determines which of the parameters are
+// used:
+//
+//   - read: path
+//   - write: path
+//   - weld: tolerance
+//   - orient
+//   - fill_holes: max_edges (zero fills every hole)
+//   - extract_patches: patches (by name)
+//   - remove_small_components: min_faces
+//   - keep_largest_component
+//   - decimate: faces or ratio
+//   - translate: offset
+//   - feature_edges: angle (degrees)
+//   - stats: path (JSON)
+type Step struct {
+	Op        string    `json:"op" yaml:"op"`
+	Path      string    `json:"path,omitempty" yaml:"path,omitempty"`
+	Tolerance float64   `json:"tolerance,omitempty" yaml:"tolerance,omitempty"`
+	MaxEdges  int       `json:"max_edges,omitempty" yaml:"max_edges,omitempty"`
+	Patches   []string  `json:"patches,omitempty" yaml:"patches,omitempty"`
+	MinFaces  int       `json:"min_faces,omitempty" yaml:"min_faces,omitempty"`
+	Faces     int       `json:"faces,omitempty" yaml:"faces,omitempty"`
+	Ratio     float64   `json:"ratio,omitempty" yaml:"ratio,omitempty"`
+	Offset    []float64 `json:"offset,omitempty" yaml:"offset,omitempty"`
+	Angle     float64   `json:"angle,omitempty" yaml:"angle,omitempty"`
+}
+
+// Declarative sequence of mesh operations.
+type Pipeline struct {
+	Steps []Step `json:"steps" yaml:"steps"`
+}
+
+// Operation of a step applied to the current mesh. The resulting mesh and
+// any attributes to log are returned.
+type operation func(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error)
+
+// Operations by name.
+var operations = map[string]operation{
+	"read":                    runRead,
+	"write":                   runWrite,
+	"weld":                    runWeld,
+	"orient":                  runOrient,
+	"fill_holes":              runFillHoles,
+	"extract_patches":         runExtractPatches,
+	"remove_small_components": runRemoveSmallComponents,
+	"keep_largest_component":  runKeepLargestComponent,
+	"decimate":                runDecimate,
+	"translate":               runTranslate,
+	"feature_edges":           runFeatureEdges,
+	"stats":                   runStats,
+}
+
+// Read a pipeline from a JSON or YAML reader. Unknown fields are rejected.
+func ReadPipeline(reader io.Reader) (*Pipeline, error) {
+	var pipeline Pipeline
+
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&pipeline); err != nil {
+		return nil, err
+	}
+
+	if err := pipeline.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &pipeline, nil
+}
+
+// Read a pipeline from a JSON or YAML file path.
+func ReadPipelineFromPath(path string) (*Pipeline, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadPipeline(file)
+}
+
+// Validate the operations of the steps and their required parameters.
+func (p *Pipeline) Validate() error {
+	for i, step := range p.Steps {
+		if _, ok := operations[step.Op]; !ok {
+			return fmt.Errorf("step %d: %w: %s", i, ErrUnknownOperation, step.Op)
+		}
+
+		var valid bool
+
+		switch step.Op {
+		case "read", "write", "stats":
+			valid = step.Path != ""
+		case "weld":
+			valid = step.Tolerance >= 0
+		case "fill_holes":
+			valid = step.MaxEdges >= 0
+		case "extract_patches":
+			valid = len(step.Patches) != 0
+		case "remove_small_components":
+			valid = step.MinFaces > 0
+		case "decimate":
+			valid = (step.Faces > 0) != (step.Ratio > 0) && step.Ratio <= 1
+		case "translate":
+			valid = len(step.Offset) == 3
+		case "feature_edges":
+			valid = step.Angle >= 0
+		default:
+			valid = true
+		}
+
+		if !valid {
+			return fmt.Errorf("step %d (%s): %w", i, step.Op, ErrInvalidStep)
+		}
+	}
+
+	return nil
+}
+
+// Run the steps in order. Each step is logged with the resulting number of
+// elements and their change. The mesh after the last step is returned. A nil
+// logger discards the logs.
+func (p *Pipeline) Run(logger *slog.Logger) (*halfedge.HalfEdgeMesh, error) {
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	var mesh *halfedge.HalfEdgeMesh
+
+	for i, step := range p.Steps {
+		if mesh == nil && step.Op != "read" {
+			return nil, fmt.Errorf("step %d (%s): %w", i, step.Op, ErrNoMesh)
+		}
+
+		var vertices, faces int
+
+		if mesh != nil {
+			vertices = mesh.GetNumberOfVertices()
+			faces = mesh.GetNumberOfFaces()
+		}
+
+		start := time.Now()
+
+		result, attrs, err := operations[step.Op](mesh, step)
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i, step.Op, err)
+		}
+
+		mesh = result
+
+		attrs = append([]any{
+			"index", i,
+			"op", step.Op,
+			"vertices", mesh.GetNumberOfVertices(),
+			"faces", mesh.GetNumberOfFaces(),
+			"vertex_delta", mesh.GetNumberOfVertices() - vertices,
+			"face_delta", mesh.GetNumberOfFaces() - faces,
+			"patches", mesh.GetNumberOfPatches(),
+			"closed", mesh.IsClosed(),
+			"duration", time.Since(start),
+		}, attrs...)
+
+		logger.Info("step", attrs...)
+	}
+
+	return mesh, nil
+}
+
+// Read the mesh.
+func runRead(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	result, err := ReadMeshFromPath(step.Path)
+	return result, []any{"path", step.Path}, err
+}
+
+// Write the mesh.
+func runWrite(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	return mesh, []any{"path", step.Path}, WriteMeshToPath(mesh, step.Path)
+}
+
+// Weld the open boundaries of the mesh.
+func runWeld(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	loops := len(mesh.GetBoundaryLoops())
+	err := mesh.Stitch(step.Tolerance)
+	return mesh, []any{"boundary_loop_delta", len(mesh.GetBoundaryLoops()) - loops}, err
+}
+
+// Orient the faces of the mesh consistently.
+func runOrient(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	mesh.Orient()
+	return mesh, []any{"consistent", mesh.IsConsistent()}, nil
+}
+
+// Fill the holes of the mesh.
+func runFillHoles(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	count, err := mesh.FillHoles(step.MaxEdges)
+	return mesh, []any{"filled", count}, err
+}
+
+// Extract the patches of the mesh by name.
+func runExtractPatches(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	patches := make([]int, 0, len(step.Patches))
+
+	for _, name := range step.Patches {
+		found := false
+
+		for i := range mesh.GetNumberOfPatches() {
+			if mesh.GetPatch(i).Name == name {
+				patches = append(patches, i)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, nil, fmt.Errorf("%w: %s", ErrPatchNotFound, name)
+		}
+	}
+
+	return mesh.ExtractPatches(patches), nil, nil
+}
+
+// Remove the components of the mesh with fewer than the minimum faces.
+func runRemoveSmallComponents(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	components := len(mesh.GetComponents())
+	mesh.RemoveSmallComponents(step.MinFaces)
+	return mesh, []any{"removed", components - len(mesh.GetComponents())}, nil
+}
+
+// Keep the largest component of the mesh.
+func runKeepLargestComponent(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	components := len(mesh.GetComponents())
+	mesh.KeepLargestComponent()
+	return mesh, []any{"removed", components - len(mesh.GetComponents())}, nil
+}
+
+// Decimate the mesh to a number or ratio of faces.
+func runDecimate(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	targetFaces := step.Faces
+
+	if step.Ratio > 0 {
+		targetFaces = int(math.Round(step.Ratio * float64(mesh.GetNumberOfFaces())))
+	}
+
+	result, err := Decimate(mesh, targetFaces)
+	return result, []any{"target_faces", targetFaces}, err
+}
+
+// Translate the mesh by an offset.
+func runTranslate(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	mesh.Translate(meshx.NewVector(step.Offset[0], step.Offset[1], step.Offset[2]))
+	return mesh, nil, nil
+}
+
+// Compute the feature edges of the mesh.
+func runFeatureEdges(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	mesh.ComputeFeatureEdges(step.Angle * math.Pi / 180)
+	return mesh, []any{"feature_half_edges", len(mesh.GetFeatureEdges())}, nil
+}
+
+// Write the summary statistics of the mesh to a JSON file.
+func runStats(mesh *halfedge.HalfEdgeMesh, step Step) (*halfedge.HalfEdgeMesh, []any, error) {
+	stats := mesh.Stats()
+	attrs := []any{"path", step.Path, "area", stats.Area, "volume", stats.Volume}
+	return mesh, attrs, stats.WriteJSONToPath(step.Path)
+}
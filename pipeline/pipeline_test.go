@@ -0,0 +1,113 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test running a pipeline read from YAML.
+func TestRunYAML(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "result.ply")
+
+	var data string
+	data += "steps:\n"
+	data += "  - op: read\n"
+	data += "    path: ../testdata/box.patches.obj\n"
+	data += "  - op: orient\n"
+	data += "  - op: extract_patches\n"
+	data += "    patches: [front, back, left, right, bottom]\n"
+	data += "  - op: fill_holes\n"
+	data += "  - op: translate\n"
+	data += "    offset: [1, 0, 0]\n"
+	data += "  - op: write\n"
+	data += "    path: " + output + "\n"
+
+	p, err := ReadPipeline(strings.NewReader(data))
+	assert.Empty(t, err)
+	assert.Equal(t, 6, len(p.Steps))
+
+	var logs bytes.Buffer
+	mesh, err := p.Run(slog.New(slog.NewTextHandler(&logs, nil)))
+	assert.Empty(t, err)
+	assert.True(t, mesh.IsClosed())
+	assert.Equal(t, 1.0, mesh.GetAABB().GetMinBound()[0])
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	assert.Equal(t, 6, len(lines))
+	assert.Contains(t, lines[2], "op=extract_patches")
+	assert.Contains(t, lines[2], "face_delta=-1")
+	assert.Contains(t, lines[3], "filled=1")
+	assert.Contains(t, lines[3], "closed=true")
+
+	result, err := ReadMeshFromPath(output)
+	assert.Empty(t, err)
+	assert.Equal(t, mesh.GetNumberOfFaces(), result.GetNumberOfFaces())
+}
+
+// Test writing the summary statistics of the mesh.
+func TestRunStats(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "stats.json")
+	data := `{"steps": [{"op": "read", "path": "../testdata/box.obj"}, {"op": "stats", "path": "` + output + `"}]}`
+
+	p, err := ReadPipeline(strings.NewReader(data))
+	assert.Empty(t, err)
+
+	mesh, err := p.Run(nil)
+	assert.Empty(t, err)
+
+	file, err := os.ReadFile(output)
+	assert.Empty(t, err)
+
+	var stats halfedge.Stats
+	assert.Empty(t, json.Unmarshal(file, &stats))
+	assert.Equal(t, mesh.GetNumberOfFaces(), stats.NumberOfFaces)
+}
+
+// Test running a pipeline read from JSON.
+func TestRunJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.json")
+	data := `{"steps": [{"op": "read", "path": "../testdata/box.obj"}, {"op": "weld", "tolerance": 1e-6}, {"op": "decimate", "ratio": 0.5}]}`
+	assert.Empty(t, os.WriteFile(path, []byte(data), 0644))
+
+	p, err := ReadPipelineFromPath(path)
+	assert.Empty(t, err)
+
+	mesh, err := p.Run(nil)
+	assert.Empty(t, err)
+	assert.Equal(t, 6, mesh.GetNumberOfFaces())
+	assert.True(t, mesh.IsClosed())
+}
+
+// Test reading invalid pipelines.
+func TestReadPipelineInvalid(t *testing.T) {
+	_, err := ReadPipeline(strings.NewReader("steps:\n  - op: smooth\n"))
+	assert.ErrorIs(t, err, ErrUnknownOperation)
+
+	_, err = ReadPipeline(strings.NewReader("steps:\n  - op: read\n"))
+	assert.ErrorIs(t, err, ErrInvalidStep)
+
+	_, err = ReadPipeline(strings.NewReader("steps:\n  - op: read\n    file: mesh.obj\n"))
+	assert.Error(t, err)
+}
+
+// Test running a pipeline without reading a mesh.
+func TestRunNoMesh(t *testing.T) {
+	p := Pipeline{Steps: []Step{{Op: "orient"}}}
+	_, err := p.Run(nil)
+	assert.ErrorIs(t, err, ErrNoMesh)
+
+	p = Pipeline{Steps: []Step{
+		{Op: "read", Path: "../testdata/box.patches.obj"},
+		{Op: "extract_patches", Patches: []string{"missing"}},
+	}}
+	_, err = p.Run(nil)
+	assert.ErrorIs(t, err, ErrPatchNotFound)
+}
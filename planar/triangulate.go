@@ -0,0 +1,289 @@
+// Package planar implements the constrained Delaunay triangulation of planar
+// points, segments and polygons with holes.
+package planar
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrDegenerate           = errors.New("no triangles in the triangulation")
+	ErrDuplicatePoint       = errors.New("duplicate point")
+	ErrInvalidPoint         = errors.New("invalid point")
+	ErrInvalidSegment       = errors.New("invalid segment")
+	ErrIntersectingSegments = errors.New("intersecting segments")
+)
+
+// Options to triangulate planar points. The zero value triangulates the
+// convex hull of the points.
+type TriangulateOptions struct {
+	// Segments (pairs of point indices) that are edges of the triangulation.
+	// The segments may share points but not cross. A segment through a point
+	// is split at the point.
+	Segments [][2]int
+
+	// Triangulate the convex hull of the points with the segments as
+	// interior constraints. Otherwise, only the region bounded by the
+	// segments (by the even-odd rule) is triangulated. Ignored without
+	// segments.
+	ConvexHull bool
+}
+
+// Compute the Delaunay triangulation of the points in the XY-plane (the Z
+// coordinates are ignored). See TriangulateWithOptions.
+func Triangulate(points []meshx.Vector) ([][3]int, error) {
+	return TriangulateWithOptions(points, TriangulateOptions{})
+}
+
+// Compute the constrained Delaunay triangulation of the points in the
+// XY-plane (the Z coordinates are ignored). The triangles are indexed by the
+// points and ordered counterclockwise. No points are added. An error is
+// returned if points coincide, segments cross or no triangles remain.
+func TriangulateWithOptions(points []meshx.Vector, options TriangulateOptions) ([][3]int, error) {
+	segments := options.Segments
+	isConvex := len(segments) == 0 || options.ConvexHull
+
+	if isConvex {
+		hull := getConvexHull(points)
+
+		if len(hull) < 3 {
+			return nil, ErrDegenerate
+		}
+
+		for i := range hull {
+			segments = append(segments, [2]int{hull[i], hull[(i+1)%len(hull)]})
+		}
+	}
+
+	for _, segment := range segments {
+		if min(segment[0], segment[1]) < 0 || max(segment[0], segment[1]) >= len(points) {
+			return nil, ErrInvalidSegment
+		}
+	}
+
+	t := newTriangulation(points)
+
+	for i := range points {
+		if err := t.insertVertex(i); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, segment := range segments {
+		if err := t.insertSegment(segment[0], segment[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	triangles := make([][3]int, 0)
+
+	for i, depth := range t.getDepths() {
+		if (isConvex && depth > 0) || depth%2 == 1 {
+			triangles = append(triangles, t.triangles[i])
+		}
+	}
+
+	if len(triangles) == 0 {
+		return nil, ErrDegenerate
+	}
+
+	return triangles, nil
+}
+
+// Triangulate a polygon with holes in the XY-plane (the Z coordinates are
+// ignored). The polygon is bounded by closed loops of point indices in any
+// orientation and the region inside an odd number of loops is triangulated
+// (holes are loops inside the outer loop). The triangles are ordered
+// counterclockwise.
+func TriangulatePolygon(points []meshx.Vector, loops [][]int) ([][3]int, error) {
+	segments := make([][2]int, 0)
+
+	for _, loop := range loops {
+		for i := range loop {
+			segments = append(segments, [2]int{loop[i], loop[(i+1)%len(loop)]})
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, ErrDegenerate
+	}
+
+	return TriangulateWithOptions(points, TriangulateOptions{Segments: segments})
+}
+
+// Compute the Newell normal of a closed loop of points. The magnitude of
+// the normal is twice the area enclosed by the loop.
+func ComputeLoopNormal(points []meshx.Vector) meshx.Vector {
+	var normal meshx.Vector
+
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		normal[0] += (p[1] - q[1]) * (p[2] + q[2])
+		normal[1] += (p[2] - q[2]) * (p[0] + q[0])
+		normal[2] += (p[0] - q[0]) * (p[1] + q[1])
+	}
+
+	return normal
+}
+
+// Return true if the point is inside the polygon (a closed loop of points)
+// in the XY-plane by the even-odd rule. The Z coordinates are ignored.
+func ContainsPoint(polygon []meshx.Vector, point meshx.Vector) bool {
+	var inside bool
+
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+
+		if (p[1] > point[1]) != (q[1] > point[1]) {
+			x := p[0] + (point[1]-p[1])*(q[0]-p[0])/(q[1]-p[1])
+
+			if point[0] < x {
+				inside = !inside
+			}
+		}
+	}
+
+	return inside
+}
+
+// Project the points onto the plane with the normal (through the origin).
+// The projected points are in the XY-plane, with Z the distance along the
+// normal, so a loop counterclockwise about the normal is counterclockwise
+// in the XY-plane.
+func ProjectToPlane(points []meshx.Vector, normal meshx.Vector) []meshx.Vector {
+	n := normal.Unit()
+	axis := meshx.NewVector(1, 0, 0)
+
+	if math.Abs(n[0]) > 0.9 {
+		axis = meshx.NewVector(0, 1, 0)
+	}
+
+	u := axis.Cross(n).Unit()
+	v := n.Cross(u)
+	projected := make([]meshx.Vector, len(points))
+
+	for i, point := range points {
+		projected[i] = meshx.NewVector(point.Dot(u), point.Dot(v), point.Dot(n))
+	}
+
+	return projected
+}
+
+// Get the vertices of the convex hull of the points in the XY-plane in
+// counterclockwise order (Andrew's monotone chain). Collinear points on the
+// hull are omitted.
+func getConvexHull(points []meshx.Vector) []int {
+	if len(points) < 3 {
+		return nil
+	}
+
+	order := make([]int, len(points))
+
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		p, q := points[order[i]], points[order[j]]
+		return p[0] < q[0] || (p[0] == q[0] && p[1] < q[1])
+	})
+
+	hull := make([]int, 0, 2*len(points))
+
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+
+		for _, index := range order {
+			for len(hull) >= start+2 && meshx.Orient2D(points[hull[len(hull)-2]], points[hull[len(hull)-1]], points[index]) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+
+			hull = append(hull, index)
+		}
+
+		// The last point of each chain is the first of the other.
+		hull = hull[:len(hull)-1]
+
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	return hull
+}
+
+// Triangulate a polygonal face (a closed loop of points in any plane)
+// without adding points. A convex face is triangulated as a fan from its
+// first point and other faces by the constrained Delaunay triangulation of
+// the face projected onto its plane. A face that does not project to a
+// simple polygon is triangulated as a fan. The triangles are indexed by the
+// points and ordered as the face.
+func TriangulateFace(points []meshx.Vector) [][3]int {
+	fan := make([][3]int, max(len(points)-2, 0))
+
+	for i := range fan {
+		fan[i] = [3]int{0, i + 1, i + 2}
+	}
+
+	if len(points) <= 3 {
+		return fan
+	}
+
+	normal := ComputeLoopNormal(points)
+
+	if normal.Mag() == 0 {
+		return fan
+	}
+
+	projected := ProjectToPlane(points, normal)
+	isConvex := true
+
+	for i := range projected {
+		p, q, r := projected[i], projected[(i+1)%len(points)], projected[(i+2)%len(points)]
+
+		if meshx.Orient2D(p, q, r) <= 0 {
+			isConvex = false
+			break
+		}
+	}
+
+	if isConvex {
+		return fan
+	}
+
+	loop := make([]int, len(points))
+
+	for i := range loop {
+		loop[i] = i
+	}
+
+	triangles, err := TriangulatePolygon(projected, [][]int{loop})
+
+	if err != nil || len(triangles) != len(points)-2 {
+		return fan
+	}
+
+	return triangles
+}
+
+// Triangulate a face given by the indices of its vertices. See
+// TriangulateFace. The triangles are indexed by the vertices.
+func TriangulateIndexedFace(vertices []meshx.Vector, face []int) [][3]int {
+	points := make([]meshx.Vector, len(face))
+
+	for i, vertex := range face {
+		points[i] = vertices[vertex]
+	}
+
+	triangles := TriangulateFace(points)
+
+	for i, triangle := range triangles {
+		triangles[i] = [3]int{face[triangle[0]], face[triangle[1]], face[triangle[2]]}
+	}
+
+	return triangles
+}
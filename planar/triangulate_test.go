@@ -0,0 +1,251 @@
+package planar
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Compute the total area of the triangles (negative if clockwise).
+func getTestArea(points []meshx.Vector, triangles [][3]int) float64 {
+	var area float64
+
+	for _, triangle := range triangles {
+		a, b, c := points[triangle[0]], points[triangle[1]], points[triangle[2]]
+		area += meshx.Orient2D(a, b, c) / 2
+	}
+
+	return area
+}
+
+// Return true if the triangles have an edge (in either direction).
+func hasTestEdge(triangles [][3]int, a, b int) bool {
+	for _, triangle := range triangles {
+		for i := range 3 {
+			p, q := triangle[i], triangle[(i+1)%3]
+
+			if (p == a && q == b) || (p == b && q == a) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Test the Delaunay triangulation of random points.
+func TestTriangulate(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+	points := make([]meshx.Vector, 200)
+
+	for i := range points {
+		points[i] = meshx.NewVector(random.Float64(), random.Float64(), random.Float64())
+	}
+
+	triangles, err := Triangulate(points)
+	assert.Empty(t, err)
+
+	hull := getConvexHull(points)
+	assert.Equal(t, 2*len(points)-len(hull)-2, len(triangles))
+
+	var hullArea float64
+
+	for i := 1; i+1 < len(hull); i++ {
+		hullArea += meshx.Orient2D(points[hull[0]], points[hull[i]], points[hull[i+1]]) / 2
+	}
+
+	assert.InDelta(t, hullArea, getTestArea(points, triangles), 1e-12)
+
+	for _, triangle := range triangles {
+		a, b, c := points[triangle[0]], points[triangle[1]], points[triangle[2]]
+		assert.Greater(t, meshx.Orient2D(a, b, c), 0.0)
+
+		for _, point := range points {
+			assert.LessOrEqual(t, meshx.InCircle(a, b, c, point), 0.0)
+		}
+	}
+}
+
+// Test the Delaunay triangulation of a grid with cocircular points.
+func TestTriangulateGrid(t *testing.T) {
+	points := make([]meshx.Vector, 0)
+
+	for i := range 5 {
+		for j := range 5 {
+			points = append(points, meshx.NewVector(float64(i), float64(j), 0))
+		}
+	}
+
+	triangles, err := Triangulate(points)
+	assert.Empty(t, err)
+	assert.Equal(t, 32, len(triangles))
+	assert.Equal(t, 16.0, getTestArea(points, triangles))
+}
+
+// Test triangulating degenerate points.
+func TestTriangulateDegenerate(t *testing.T) {
+	points := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 1, 0),
+		meshx.NewVector(2, 2, 0),
+	}
+
+	_, err := Triangulate(points)
+	assert.ErrorIs(t, err, ErrDegenerate)
+
+	_, err = Triangulate(nil)
+	assert.ErrorIs(t, err, ErrDegenerate)
+
+	points = append(points, meshx.NewVector(0, 1, 0), meshx.NewVector(1, 1, 5))
+
+	_, err = Triangulate(points)
+	assert.ErrorIs(t, err, ErrDuplicatePoint)
+}
+
+// Test triangulating with segments inside the convex hull.
+func TestTriangulateWithOptions(t *testing.T) {
+	points := make([]meshx.Vector, 0)
+
+	for i := range 4 {
+		for j := range 4 {
+			points = append(points, meshx.NewVector(float64(i), float64(j), 0))
+		}
+	}
+
+	// Segments along the diagonal through the grid points and between the
+	// grid points.
+	points = append(points, meshx.NewVector(0.2, 2.6, 0), meshx.NewVector(1.8, 2.9, 0))
+
+	options := TriangulateOptions{
+		Segments:   [][2]int{{0, 15}, {16, 17}},
+		ConvexHull: true,
+	}
+
+	triangles, err := TriangulateWithOptions(points, options)
+	assert.Empty(t, err)
+	assert.InDelta(t, 9, getTestArea(points, triangles), 1e-12)
+	assert.True(t, hasTestEdge(triangles, 0, 5))
+	assert.True(t, hasTestEdge(triangles, 5, 10))
+	assert.True(t, hasTestEdge(triangles, 10, 15))
+	assert.True(t, hasTestEdge(triangles, 16, 17))
+
+	options.Segments = [][2]int{{0, 15}, {3, 12}}
+
+	_, err = TriangulateWithOptions(points, options)
+	assert.ErrorIs(t, err, ErrIntersectingSegments)
+
+	options.Segments = [][2]int{{0, 18}}
+
+	_, err = TriangulateWithOptions(points, options)
+	assert.ErrorIs(t, err, ErrInvalidSegment)
+}
+
+// Test triangulating a non-convex polygon with a hole.
+func TestTriangulatePolygon(t *testing.T) {
+	points := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(4, 0, 0),
+		meshx.NewVector(4, 4, 0),
+		meshx.NewVector(2, 1, 0),
+		meshx.NewVector(0, 4, 0),
+		meshx.NewVector(1, 0.25, 0),
+		meshx.NewVector(1, 0.75, 0),
+		meshx.NewVector(3, 0.75, 0),
+		meshx.NewVector(3, 0.25, 0),
+	}
+
+	loops := [][]int{{0, 1, 2, 3, 4}, {5, 6, 7, 8}}
+
+	triangles, err := TriangulatePolygon(points, loops)
+	assert.Empty(t, err)
+	assert.Equal(t, 9, len(triangles))
+	assert.InDelta(t, 10-1, getTestArea(points, triangles), 1e-12)
+
+	for _, loop := range loops {
+		for i := range loop {
+			assert.True(t, hasTestEdge(triangles, loop[i], loop[(i+1)%len(loop)]))
+		}
+	}
+
+	_, err = TriangulatePolygon(points, nil)
+	assert.ErrorIs(t, err, ErrDegenerate)
+}
+
+// Test the even-odd containment of points in a non-convex polygon.
+func TestContainsPoint(t *testing.T) {
+	polygon := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(4, 0, 0),
+		meshx.NewVector(4, 4, 0),
+		meshx.NewVector(2, 1, 0),
+		meshx.NewVector(0, 4, 0),
+	}
+
+	assert.True(t, ContainsPoint(polygon, meshx.NewVector(1, 1, 5)))
+	assert.True(t, ContainsPoint(polygon, meshx.NewVector(3.5, 3, 0)))
+	assert.False(t, ContainsPoint(polygon, meshx.NewVector(2, 3, 0)))
+	assert.False(t, ContainsPoint(polygon, meshx.NewVector(5, 1, 0)))
+	assert.False(t, ContainsPoint(nil, meshx.NewVector(0, 0, 0)))
+}
+
+// Test projecting a loop onto its plane.
+func TestProjectToPlane(t *testing.T) {
+	points := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(0, 2, 0),
+		meshx.NewVector(0, 2, 2),
+		meshx.NewVector(0, 0, 2),
+	}
+
+	normal := ComputeLoopNormal(points)
+	assert.Equal(t, meshx.NewVector(8, 0, 0), normal)
+
+	projected := ProjectToPlane(points, normal)
+
+	triangles, err := TriangulatePolygon(projected, [][]int{{0, 1, 2, 3}})
+	assert.Empty(t, err)
+	assert.Equal(t, 2, len(triangles))
+	assert.InDelta(t, 4, getTestArea(projected, triangles), 1e-12)
+
+	for _, point := range projected {
+		assert.InDelta(t, 0, point[2], 1e-12)
+	}
+
+	for _, triangle := range triangles {
+		a, b, c := points[triangle[0]], points[triangle[1]], points[triangle[2]]
+		assert.Greater(t, b.Sub(a).Cross(c.Sub(a)).Dot(normal), 0.0)
+	}
+}
+
+// Test triangulating convex and concave faces in space.
+func TestTriangulateFace(t *testing.T) {
+	square := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(0, 0, 1),
+		meshx.NewVector(0, 1, 1),
+		meshx.NewVector(0, 1, 0),
+	}
+
+	assert.Equal(t, [][3]int{{0, 1, 2}, {0, 2, 3}}, TriangulateFace(square))
+	assert.Equal(t, [][3]int{{0, 1, 2}}, TriangulateFace(square[:3]))
+	assert.Equal(t, 0, len(TriangulateFace(square[:2])))
+
+	// Arrowhead with the reflex vertex last so the fan would overlap.
+	arrow := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(2, 1, 0),
+		meshx.NewVector(0, 2, 0),
+		meshx.NewVector(1, 1, 0),
+	}
+
+	triangles := TriangulateFace(arrow)
+	assert.Equal(t, 2, len(triangles))
+	assert.InDelta(t, 1, getTestArea(arrow, triangles), 1e-12)
+
+	for _, triangle := range triangles {
+		a, b, c := arrow[triangle[0]], arrow[triangle[1]], arrow[triangle[2]]
+		assert.Greater(t, meshx.Orient2D(a, b, c), 0.0)
+	}
+}
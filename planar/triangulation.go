@@ -0,0 +1,593 @@
+package planar
+
+import (
+	"github.com/ajcurley/meshx-go"
+)
+
+// Constrained Delaunay triangulation of points in the XY-plane enclosed by a
+// super triangle. Each triangle is counterclockwise and neighbor i is across
+// the edge opposite vertex i (-1 if none). The super vertices follow the
+// points. Points are inserted by splitting the containing triangle (or edge)
+// and restoring the Delaunay property by edge flips. Segments are recovered
+// by flipping the edges they cross (Sloan).
+type triangulation struct {
+	points    []meshx.Vector
+	triangles [][3]int
+	neighbors [][3]int
+	incident  []int
+	segments  map[[2]int]bool
+	last      int
+	super     int
+}
+
+// Construct a triangulation of the super triangle enclosing the points. The
+// points are not inserted.
+func newTriangulation(points []meshx.Vector) *triangulation {
+	aabb := meshx.NewAABBFromVectors(points)
+	center := aabb.Center
+	size := 100 * max(aabb.HalfSize[0], aabb.HalfSize[1], 1e-300)
+
+	t := triangulation{
+		points:   make([]meshx.Vector, len(points), len(points)+3),
+		segments: make(map[[2]int]bool),
+		super:    len(points),
+	}
+
+	copy(t.points, points)
+
+	t.points = append(t.points,
+		meshx.NewVector(center[0]-size, center[1]-size, 0),
+		meshx.NewVector(center[0]+size, center[1]-size, 0),
+		meshx.NewVector(center[0], center[1]+size, 0),
+	)
+
+	t.incident = make([]int, len(t.points))
+	t.addTriangle([3]int{t.super, t.super + 1, t.super + 2}, [3]int{-1, -1, -1})
+
+	return &t
+}
+
+// Add a triangle and return its index.
+func (t *triangulation) addTriangle(vertices, neighbors [3]int) int {
+	t.triangles = append(t.triangles, [3]int{})
+	t.neighbors = append(t.neighbors, neighbors)
+	t.setTriangle(len(t.triangles)-1, vertices)
+	return len(t.triangles) - 1
+}
+
+// Set the vertices of a triangle.
+func (t *triangulation) setTriangle(index int, vertices [3]int) {
+	t.triangles[index] = vertices
+
+	for _, vertex := range vertices {
+		t.incident[vertex] = index
+	}
+}
+
+// Replace the neighbor of a triangle (if any).
+func (t *triangulation) replaceNeighbor(index, previous, next int) {
+	if index == -1 {
+		return
+	}
+
+	for i, neighbor := range t.neighbors[index] {
+		if neighbor == previous {
+			t.neighbors[index][i] = next
+		}
+	}
+}
+
+// Get the index of the vertex in a triangle (-1 if not found).
+func (t *triangulation) indexOf(index, vertex int) int {
+	for i, v := range t.triangles[index] {
+		if v == vertex {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Get the index of the vertex of a triangle opposite its neighbor.
+func (t *triangulation) indexOpposite(index, neighbor int) int {
+	for i, n := range t.neighbors[index] {
+		if n == neighbor {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Compute the orientation of the points by index.
+func (t *triangulation) orientation(a, b, c int) float64 {
+	return meshx.Orient2D(t.points[a], t.points[b], t.points[c])
+}
+
+// Return true if the vertex is strictly inside the circumcircle of the
+// triangle.
+func (t *triangulation) inCircle(index, vertex int) bool {
+	a, b, c := t.triangles[index][0], t.triangles[index][1], t.triangles[index][2]
+	return meshx.InCircle(t.points[a], t.points[b], t.points[c], t.points[vertex]) > 0
+}
+
+// Locate the triangle containing the point. If the point lies on an edge,
+// the index of the vertex opposite the edge is returned, otherwise -1. The
+// triangle is -1 if the point is outside the super triangle.
+func (t *triangulation) locate(point meshx.Vector) (int, int) {
+	current := t.last
+
+	// The walk may cycle in a constrained triangulation, so it is bounded
+	// and followed by an exhaustive search.
+	for step := range 4 * len(t.triangles) {
+		moved := false
+
+		for j := range 3 {
+			i := (j + step) % 3
+			a, b := t.triangles[current][(i+1)%3], t.triangles[current][(i+2)%3]
+
+			if meshx.Orient2D(t.points[a], t.points[b], point) < 0 {
+				current = t.neighbors[current][i]
+				moved = true
+				break
+			}
+		}
+
+		if current == -1 {
+			break
+		}
+
+		if !moved {
+			return current, t.getEdge(current, point)
+		}
+	}
+
+	for i := range t.triangles {
+		if t.contains(i, point) {
+			return i, t.getEdge(i, point)
+		}
+	}
+
+	return -1, -1
+}
+
+// Return true if the point is inside or on the boundary of a triangle.
+func (t *triangulation) contains(index int, point meshx.Vector) bool {
+	for i := range 3 {
+		a, b := t.triangles[index][(i+1)%3], t.triangles[index][(i+2)%3]
+
+		if meshx.Orient2D(t.points[a], t.points[b], point) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Get the index of the vertex opposite the edge of a triangle the point
+// lies on (-1 if none).
+func (t *triangulation) getEdge(index int, point meshx.Vector) int {
+	for i := range 3 {
+		a, b := t.triangles[index][(i+1)%3], t.triangles[index][(i+2)%3]
+
+		if meshx.Orient2D(t.points[a], t.points[b], point) == 0 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Insert a vertex by index.
+func (t *triangulation) insertVertex(vertex int) error {
+	point := t.points[vertex]
+	index, edge := t.locate(point)
+
+	if index == -1 {
+		return ErrInvalidPoint
+	}
+
+	for _, v := range t.triangles[index] {
+		if t.points[v][0] == point[0] && t.points[v][1] == point[1] {
+			return ErrDuplicatePoint
+		}
+	}
+
+	if edge == -1 {
+		t.splitTriangle(index, vertex)
+	} else {
+		t.splitEdge(index, edge, vertex)
+	}
+
+	t.last = index
+
+	return nil
+}
+
+// Split a triangle into three about a vertex inside it.
+func (t *triangulation) splitTriangle(index, vertex int) {
+	a, b, c := t.triangles[index][0], t.triangles[index][1], t.triangles[index][2]
+	na, nb, nc := t.neighbors[index][0], t.neighbors[index][1], t.neighbors[index][2]
+
+	t1 := t.addTriangle([3]int{b, c, vertex}, [3]int{-1, index, na})
+	t2 := t.addTriangle([3]int{c, a, vertex}, [3]int{index, t1, nb})
+	t.neighbors[t1][0] = t2
+
+	t.setTriangle(index, [3]int{a, b, vertex})
+	t.neighbors[index] = [3]int{t1, t2, nc}
+
+	t.replaceNeighbor(na, index, t1)
+	t.replaceNeighbor(nb, index, t2)
+
+	t.legalize([][2]int{{index, 2}, {t1, 2}, {t2, 2}})
+}
+
+// Split the edge of a triangle opposite vertex i (and the neighbor across
+// it) about a vertex on the edge. A segment on the edge is split as well.
+func (t *triangulation) splitEdge(index, i, vertex int) {
+	p := t.triangles[index][i]
+	a := t.triangles[index][(i+1)%3]
+	b := t.triangles[index][(i+2)%3]
+	npa := t.neighbors[index][(i+2)%3]
+	nbp := t.neighbors[index][(i+1)%3]
+	u := t.neighbors[index][i]
+
+	t0 := index
+	t1 := t.addTriangle([3]int{p, vertex, b}, [3]int{-1, nbp, t0})
+	t.setTriangle(t0, [3]int{p, a, vertex})
+	t.neighbors[t0] = [3]int{-1, t1, npa}
+	t.replaceNeighbor(nbp, index, t1)
+
+	stack := [][2]int{{t0, 2}, {t1, 1}}
+
+	if u == -1 {
+		// The edge is on the boundary of the super triangle.
+		t.neighbors[t0][0] = -1
+	} else {
+		j := t.indexOpposite(u, index)
+		q := t.triangles[u][j]
+		naq := t.neighbors[u][(j+1)%3]
+		nqb := t.neighbors[u][(j+2)%3]
+
+		u0 := u
+		u1 := t.addTriangle([3]int{q, b, vertex}, [3]int{t1, -1, nqb})
+		t.setTriangle(u0, [3]int{q, vertex, a})
+		t.neighbors[u0] = [3]int{t0, naq, u1}
+		t.neighbors[u1][1] = u0
+		t.replaceNeighbor(nqb, u, u1)
+
+		t.neighbors[t0][0] = u0
+		t.neighbors[t1][0] = u1
+
+		stack = append(stack, [2]int{u0, 1}, [2]int{u1, 2})
+	}
+
+	if key := getEdgeKey(a, b); t.segments[key] {
+		delete(t.segments, key)
+		t.segments[getEdgeKey(a, vertex)] = true
+		t.segments[getEdgeKey(vertex, b)] = true
+	}
+
+	t.legalize(stack)
+}
+
+// Flip the edges opposite the vertices of the triangles (triangle, vertex
+// index) that are not locally Delaunay until none remain.
+func (t *triangulation) legalize(stack [][2]int) {
+	for len(stack) > 0 {
+		index, i := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+
+		u := t.neighbors[index][i]
+
+		if u == -1 {
+			continue
+		}
+
+		a, b := t.triangles[index][(i+1)%3], t.triangles[index][(i+2)%3]
+
+		if t.segments[getEdgeKey(a, b)] {
+			continue
+		}
+
+		q := t.triangles[u][t.indexOpposite(u, index)]
+
+		if !t.inCircle(index, q) {
+			continue
+		}
+
+		t.flip(index, i)
+		stack = append(stack, [2]int{index, 0}, [2]int{u, 2})
+	}
+}
+
+// Flip the edge opposite vertex i of a triangle. The triangle (p, a, b) and
+// its neighbor (q, b, a) become (p, a, q) and (q, b, p).
+func (t *triangulation) flip(index, i int) {
+	u := t.neighbors[index][i]
+	j := t.indexOpposite(u, index)
+
+	p := t.triangles[index][i]
+	a := t.triangles[index][(i+1)%3]
+	b := t.triangles[index][(i+2)%3]
+	q := t.triangles[u][j]
+
+	npa := t.neighbors[index][(i+2)%3]
+	nbp := t.neighbors[index][(i+1)%3]
+	naq := t.neighbors[u][(j+1)%3]
+	nqb := t.neighbors[u][(j+2)%3]
+
+	t.setTriangle(index, [3]int{p, a, q})
+	t.setTriangle(u, [3]int{q, b, p})
+	t.neighbors[index] = [3]int{naq, u, npa}
+	t.neighbors[u] = [3]int{nbp, index, nqb}
+
+	t.replaceNeighbor(naq, u, index)
+	t.replaceNeighbor(nbp, index, u)
+}
+
+// Get the triangles incident to a vertex.
+func (t *triangulation) getStar(vertex int) []int {
+	start := t.incident[vertex]
+	star := []int{start}
+
+	for current := start; ; {
+		k := t.indexOf(current, vertex)
+		current = t.neighbors[current][(k+1)%3]
+
+		if current == start {
+			return star
+		}
+
+		if current == -1 {
+			break
+		}
+
+		star = append(star, current)
+	}
+
+	for current := start; ; {
+		k := t.indexOf(current, vertex)
+		current = t.neighbors[current][(k+2)%3]
+
+		if current == -1 {
+			return star
+		}
+
+		star = append(star, current)
+	}
+}
+
+// Find the triangle with the edge (in either direction) and the index of
+// the vertex opposite it. The boolean is false if there is no such edge.
+func (t *triangulation) findEdge(a, b int) (int, int, bool) {
+	for _, index := range t.getStar(a) {
+		k := t.indexOf(index, a)
+
+		if t.triangles[index][(k+1)%3] == b {
+			return index, (k + 2) % 3, true
+		}
+
+		if t.triangles[index][(k+2)%3] == b {
+			return index, (k + 1) % 3, true
+		}
+	}
+
+	return -1, -1, false
+}
+
+// Insert a segment between two vertices. A segment passing through another
+// vertex is split at the vertex.
+func (t *triangulation) insertSegment(a, b int) error {
+	if a == b {
+		return nil
+	}
+
+	if _, _, ok := t.findEdge(a, b); ok {
+		t.segments[getEdgeKey(a, b)] = true
+		return nil
+	}
+
+	crossed, through, err := t.getCrossedEdges(a, b)
+	if err != nil {
+		return err
+	}
+
+	if through != -1 {
+		if err := t.insertSegment(a, through); err != nil {
+			return err
+		}
+		return t.insertSegment(through, b)
+	}
+
+	created := make([][2]int, 0)
+
+	for len(crossed) > 0 {
+		edge := crossed[0]
+		crossed = crossed[1:]
+
+		index, i, _ := t.findEdge(edge[0], edge[1])
+		u := t.neighbors[index][i]
+		p := t.triangles[index][i]
+		q := t.triangles[u][t.indexOpposite(u, index)]
+		c := t.triangles[index][(i+1)%3]
+		d := t.triangles[index][(i+2)%3]
+
+		// Only a strictly convex quadrilateral can be flipped.
+		if t.orientation(p, c, q) <= 0 || t.orientation(q, d, p) <= 0 {
+			crossed = append(crossed, edge)
+			continue
+		}
+
+		t.flip(index, i)
+
+		if t.crosses(a, b, p, q) {
+			crossed = append(crossed, [2]int{p, q})
+		} else {
+			created = append(created, [2]int{p, q})
+		}
+	}
+
+	key := getEdgeKey(a, b)
+	t.segments[key] = true
+
+	for flipped := true; flipped; {
+		flipped = false
+
+		for k, edge := range created {
+			if getEdgeKey(edge[0], edge[1]) == key {
+				continue
+			}
+
+			index, i, ok := t.findEdge(edge[0], edge[1])
+			if !ok {
+				continue
+			}
+
+			u := t.neighbors[index][i]
+
+			if u == -1 {
+				continue
+			}
+
+			p := t.triangles[index][i]
+			q := t.triangles[u][t.indexOpposite(u, index)]
+
+			if t.inCircle(index, q) {
+				t.flip(index, i)
+				created[k] = [2]int{p, q}
+				flipped = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// Return true if the segments (a, b) and (c, d) cross at a point interior
+// to both.
+func (t *triangulation) crosses(a, b, c, d int) bool {
+	return t.orientation(a, b, c)*t.orientation(a, b, d) < 0 &&
+		t.orientation(c, d, a)*t.orientation(c, d, b) < 0
+}
+
+// Get the edges crossed by the segment between two vertices in order from
+// the first. If the segment passes through a vertex before crossing any
+// edge, the vertex is returned instead (otherwise -1).
+func (t *triangulation) getCrossedEdges(a, b int) ([][2]int, int, error) {
+	pa, pb := t.points[a], t.points[b]
+	direction := pb.Sub(pa)
+
+	for _, index := range t.getStar(a) {
+		k := t.indexOf(index, a)
+		c := t.triangles[index][(k+1)%3]
+		d := t.triangles[index][(k+2)%3]
+		oc := t.orientation(a, b, c)
+		od := t.orientation(a, b, d)
+
+		if oc == 0 && t.points[c].Sub(pa).Dot(direction) > 0 {
+			return nil, c, nil
+		}
+
+		if od == 0 && t.points[d].Sub(pa).Dot(direction) > 0 {
+			return nil, d, nil
+		}
+
+		if oc >= 0 || od <= 0 {
+			continue
+		}
+
+		crossed := make([][2]int, 0)
+		previous := index
+
+		for {
+			if t.segments[getEdgeKey(c, d)] {
+				return nil, -1, ErrIntersectingSegments
+			}
+
+			crossed = append(crossed, [2]int{c, d})
+
+			current := t.neighbors[previous][t.indexOf(previous, t.getThird(previous, c, d))]
+			e := t.getThird(current, c, d)
+
+			if e == b {
+				return crossed, -1, nil
+			}
+
+			switch oe := t.orientation(a, b, e); {
+			case oe == 0:
+				return nil, e, nil
+			case oe < 0:
+				c = e
+			default:
+				d = e
+			}
+
+			previous = current
+		}
+	}
+
+	return nil, -1, ErrInvalidSegment
+}
+
+// Get the vertex of a triangle other than the two vertices.
+func (t *triangulation) getThird(index, a, b int) int {
+	for _, vertex := range t.triangles[index] {
+		if vertex != a && vertex != b {
+			return vertex
+		}
+	}
+
+	return -1
+}
+
+// Classify the triangles by the number of segments crossed to reach them
+// from the super triangle.
+func (t *triangulation) getDepths() []int {
+	depths := make([]int, len(t.triangles))
+
+	for i := range depths {
+		depths[i] = -1
+	}
+
+	frontier := []int{t.incident[t.super]}
+
+	for depth := 0; len(frontier) > 0; depth++ {
+		next := make([]int, 0)
+		stack := frontier
+
+		for len(stack) > 0 {
+			index := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if depths[index] != -1 {
+				continue
+			}
+
+			depths[index] = depth
+
+			for i, neighbor := range t.neighbors[index] {
+				if neighbor == -1 || depths[neighbor] != -1 {
+					continue
+				}
+
+				a, b := t.triangles[index][(i+1)%3], t.triangles[index][(i+2)%3]
+
+				if t.segments[getEdgeKey(a, b)] {
+					next = append(next, neighbor)
+				} else {
+					stack = append(stack, neighbor)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return depths
+}
+
+// Get the key of an undirected edge.
+func getEdgeKey(a, b int) [2]int {
+	return [2]int{min(a, b), max(a, b)}
+}
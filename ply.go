@@ -0,0 +1,778 @@
+package meshx
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Encoding of the elements of a PLY (Stanford) file.
+type PLYFormat int
+
+const (
+	PLYFormatASCII PLYFormat = iota
+	PLYFormatBinaryLittleEndian
+	PLYFormatBinaryBigEndian
+)
+
+var (
+	ErrPLYInvalidHeader   = errors.New("invalid PLY header")
+	ErrPLYInvalidProperty = errors.New("invalid PLY property")
+	ErrPLYInvalidElement  = errors.New("invalid PLY element")
+)
+
+// Name of each PLY format in the header.
+var plyFormatNames = map[PLYFormat]string{
+	PLYFormatASCII:              "ascii",
+	PLYFormatBinaryLittleEndian: "binary_little_endian",
+	PLYFormatBinaryBigEndian:    "binary_big_endian",
+}
+
+// Size in bytes of each PLY data type (including the sized aliases).
+var plyTypeSizes = map[string]int{
+	"char":    1,
+	"uchar":   1,
+	"short":   2,
+	"ushort":  2,
+	"int":     4,
+	"uint":    4,
+	"float":   4,
+	"double":  8,
+	"int8":    1,
+	"uint8":   1,
+	"int16":   2,
+	"uint16":  2,
+	"int32":   4,
+	"uint32":  4,
+	"float32": 4,
+	"float64": 8,
+}
+
+// Property of a PLY element. The count type is empty unless the property is
+// a list.
+type plyProperty struct {
+	name      string
+	dataType  string
+	countType string
+}
+
+// Element of a PLY file with its properties.
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+// Return true if the PLY data type is a floating point type.
+func isPLYFloatType(dataType string) bool {
+	switch dataType {
+	case "float", "double", "float32", "float64":
+		return true
+	}
+	return false
+}
+
+// PLYReader manages parsing a PLY (Stanford) file. This supports the ASCII
+// and both binary formats. Vertex and face colors are read from the red,
+// green, blue and alpha properties, and face patches are read from the patch
+// property named by "comment patch <index> <name>" header lines.
+type PLYReader struct {
+	reader          io.Reader
+	format          PLYFormat
+	elements        []plyElement
+	vertices        []Vector
+	vertexColors    []Color
+	faces           []int
+	faceOffsets     []int
+	facePatches     []int
+	faceColors      []Color
+	patches         []string
+	hasVertexColors bool
+	hasFaceColors   bool
+	conversion      Conversion
+	transform       readTransform
+}
+
+// Construct a PLY reader from an io.Reader interface.
+func NewPLYReader(reader io.Reader) *PLYReader {
+	return &PLYReader{
+		reader:       reader,
+		elements:     make([]plyElement, 0),
+		vertices:     make([]Vector, 0),
+		vertexColors: make([]Color, 0),
+		faces:        make([]int, 0),
+		faceOffsets:  make([]int, 0),
+		facePatches:  make([]int, 0),
+		faceColors:   make([]Color, 0),
+		patches:      make([]string, 0),
+	}
+}
+
+// Read a PLY file from a file path.
+func ReadPLYFromPath(path string) (*PLYReader, error) {
+	return readPLYFromPath(path, readTransform{})
+}
+
+// Read a PLY file from a file path transforming the vertices while reading
+// (e.g. to place a part in a scene). See SetTransform.
+func ReadPLYWithTransform(path string, matrix Matrix4) (*PLYReader, error) {
+	return readPLYFromPath(path, newReadTransform(matrix))
+}
+
+// Read a PLY file from a file path with the transformation applied while
+// reading.
+func readPLYFromPath(path string, transform readTransform) (*PLYReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	plyReader := NewPLYReader(file)
+	plyReader.transform = transform
+
+	if err := plyReader.Read(); err != nil {
+		return nil, err
+	}
+
+	return plyReader, nil
+}
+
+// Read the PLY file.
+func (r *PLYReader) Read() error {
+	reader := bufio.NewReader(r.reader)
+
+	if err := r.readHeader(reader); err != nil {
+		return err
+	}
+
+	var decoder plyDecoder
+
+	switch r.format {
+	case PLYFormatASCII:
+		scanner := bufio.NewScanner(reader)
+		scanner.Split(bufio.ScanWords)
+		decoder = &plyASCIIDecoder{scanner}
+	case PLYFormatBinaryLittleEndian:
+		decoder = &plyBinaryDecoder{reader: reader, order: binary.LittleEndian}
+	case PLYFormatBinaryBigEndian:
+		decoder = &plyBinaryDecoder{reader: reader, order: binary.BigEndian}
+	}
+
+	for _, element := range r.elements {
+		for i := 0; i < element.count; i++ {
+			if err := r.readElement(decoder, element); err != nil {
+				return fmt.Errorf("%s %d: %v", element.name, i, err)
+			}
+		}
+	}
+
+	for _, vertex := range r.faces {
+		if vertex < 0 || vertex >= len(r.vertices) {
+			return ErrInvalidFace
+		}
+	}
+
+	for _, patch := range r.facePatches {
+		for len(r.patches) <= patch {
+			r.patches = append(r.patches, fmt.Sprintf("patch%d", len(r.patches)))
+		}
+	}
+
+	return nil
+}
+
+// Read the header up to and including the end_header line.
+func (r *PLYReader) readHeader(reader *bufio.Reader) error {
+	var hasFormat bool
+	patches := make(map[int]string)
+
+	for count := 0; ; count++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return ErrPLYInvalidHeader
+		}
+
+		fields := strings.Fields(line)
+
+		if count == 0 {
+			if len(fields) != 1 || fields[0] != "ply" {
+				return ErrPLYInvalidHeader
+			}
+			continue
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "format":
+			if len(fields) != 3 {
+				return ErrPLYInvalidHeader
+			}
+
+			hasFormat = false
+
+			for format, name := range plyFormatNames {
+				if fields[1] == name {
+					r.format = format
+					hasFormat = true
+				}
+			}
+
+			if !hasFormat {
+				return ErrPLYInvalidHeader
+			}
+		case "comment":
+			if len(fields) >= 4 && fields[1] == "patch" {
+				if index, err := strconv.Atoi(fields[2]); err == nil && index >= 0 {
+					patches[index] = strings.Join(fields[3:], " ")
+				}
+			}
+		case "element":
+			if len(fields) != 3 {
+				return ErrPLYInvalidHeader
+			}
+
+			elementCount, err := strconv.Atoi(fields[2])
+			if err != nil || elementCount < 0 {
+				return ErrPLYInvalidHeader
+			}
+
+			r.elements = append(r.elements, plyElement{name: fields[1], count: elementCount})
+		case "property":
+			if len(r.elements) == 0 {
+				return ErrPLYInvalidHeader
+			}
+
+			var property plyProperty
+
+			if len(fields) == 5 && fields[1] == "list" {
+				property = plyProperty{fields[4], fields[3], fields[2]}
+			} else if len(fields) == 3 {
+				property = plyProperty{fields[2], fields[1], ""}
+			} else {
+				return ErrPLYInvalidProperty
+			}
+
+			if _, ok := plyTypeSizes[property.dataType]; !ok {
+				return ErrPLYInvalidProperty
+			}
+
+			if _, ok := plyTypeSizes[property.countType]; !ok && property.countType != "" {
+				return ErrPLYInvalidProperty
+			}
+
+			element := &r.elements[len(r.elements)-1]
+			element.properties = append(element.properties, property)
+		case "end_header":
+			if !hasFormat {
+				return ErrPLYInvalidHeader
+			}
+
+			for i := 0; i < len(patches); i++ {
+				name, ok := patches[i]
+				if !ok {
+					name = fmt.Sprintf("patch%d", i)
+				}
+				r.patches = append(r.patches, name)
+			}
+
+			return nil
+		}
+	}
+}
+
+// Read an element with the decoder.
+func (r *PLYReader) readElement(decoder plyDecoder, element plyElement) error {
+	values := make(map[string]float64)
+	types := make(map[string]string)
+	var indices []int
+
+	for _, property := range element.properties {
+		if property.countType != "" {
+			count, err := decoder.decode(property.countType)
+			if err != nil {
+				return err
+			} else if count < 0 {
+				return ErrPLYInvalidElement
+			}
+
+			list := make([]int, int(count))
+
+			for i := range list {
+				value, err := decoder.decode(property.dataType)
+				if err != nil {
+					return err
+				}
+				list[i] = int(value)
+			}
+
+			if property.name == "vertex_indices" || property.name == "vertex_index" {
+				indices = list
+			}
+		} else {
+			value, err := decoder.decode(property.dataType)
+			if err != nil {
+				return err
+			}
+
+			values[property.name] = value
+			types[property.name] = property.dataType
+		}
+	}
+
+	switch element.name {
+	case "vertex":
+		x, hasX := values["x"]
+		y, hasY := values["y"]
+		z, hasZ := values["z"]
+
+		if !hasX || !hasY || !hasZ {
+			return ErrInvalidVertex
+		}
+
+		color, hasColor := readPLYColor(values, types)
+		r.vertices = append(r.vertices, r.transform.transformPoint(NewVector(x, y, z)))
+		r.vertexColors = append(r.vertexColors, color)
+		r.hasVertexColors = r.hasVertexColors || hasColor
+	case "face":
+		if len(indices) < 3 {
+			return ErrInvalidFace
+		}
+
+		patch := -1
+
+		if value, ok := values["patch"]; ok && value >= 0 {
+			patch = int(value)
+		}
+
+		color, hasColor := readPLYColor(values, types)
+		r.faceOffsets = append(r.faceOffsets, len(r.faces))
+		r.faces = append(r.faces, indices...)
+		r.transform.transformFace(r.faces[len(r.faces)-len(indices):])
+		r.facePatches = append(r.facePatches, patch)
+		r.faceColors = append(r.faceColors, color)
+		r.hasFaceColors = r.hasFaceColors || hasColor
+	}
+
+	return nil
+}
+
+// Read the color of an element from its properties. Floating point channels
+// are in [0, 1] and integer channels are in [0, 255]. The color is white if
+// the element has no color.
+func readPLYColor(values map[string]float64, types map[string]string) (Color, bool) {
+	color := ColorWhite
+	hasColor := false
+
+	for i, name := range []string{"red", "green", "blue", "alpha"} {
+		if value, ok := values[name]; ok {
+			if isPLYFloatType(types[name]) {
+				value *= 255
+			}
+
+			color[i] = uint8(math.Round(min(max(value, 0), 255)))
+			hasColor = true
+		}
+	}
+
+	return color, hasColor
+}
+
+// Get the format.
+func (r *PLYReader) GetFormat() PLYFormat {
+	return r.format
+}
+
+// Set the transformation applied to the vertices while reading. See
+// OBJReader.SetTransform.
+func (r *PLYReader) SetTransform(matrix Matrix4) {
+	r.transform = newReadTransform(matrix)
+}
+
+// Set the conversion of the coordinates read (e.g. from millimeters in a
+// Y-up system). The faces are reversed if the conversion is mirrored.
+func (r *PLYReader) SetConversion(conversion Conversion) {
+	r.conversion = conversion
+}
+
+// Get a vertex by index.
+func (r *PLYReader) GetVertex(index int) Vector {
+	return r.conversion.ConvertPoint(r.vertices[index])
+}
+
+// Get the number of vertices.
+func (r *PLYReader) GetNumberOfVertices() int {
+	return len(r.vertices)
+}
+
+// Return true if any vertex has a color. A vertex without a color is white.
+func (r *PLYReader) HasVertexColors() bool {
+	return r.hasVertexColors
+}
+
+// Get a vertex color by index.
+func (r *PLYReader) GetVertexColor(index int) Color {
+	return r.vertexColors[index]
+}
+
+// Get a face by index.
+func (r *PLYReader) GetFace(index int) []int {
+	return r.conversion.ConvertFace(r.getFace(index))
+}
+
+// Get a face by index without the conversion.
+func (r *PLYReader) getFace(index int) []int {
+	if index == r.GetNumberOfFaces()-1 {
+		return r.faces[r.faceOffsets[index]:]
+	}
+
+	return r.faces[r.faceOffsets[index]:r.faceOffsets[index+1]]
+}
+
+// Get a face patch by index.
+func (r *PLYReader) GetFacePatch(index int) int {
+	return r.facePatches[index]
+}
+
+// Return true if any face has a color. A face without a color is white.
+func (r *PLYReader) HasFaceColors() bool {
+	return r.hasFaceColors
+}
+
+// Get a face color by index.
+func (r *PLYReader) GetFaceColor(index int) Color {
+	return r.faceColors[index]
+}
+
+// Get the number of faces.
+func (r *PLYReader) GetNumberOfFaces() int {
+	return len(r.faceOffsets)
+}
+
+// Get the number of face edges.
+func (r *PLYReader) GetNumberOfFaceEdges() int {
+	return len(r.faces)
+}
+
+// Get a patch by index.
+func (r *PLYReader) GetPatch(index int) string {
+	return r.patches[index]
+}
+
+// Get the number of patches.
+func (r *PLYReader) GetNumberOfPatches() int {
+	return len(r.patches)
+}
+
+// Decoder of the property values of a PLY file.
+type plyDecoder interface {
+	decode(dataType string) (float64, error)
+}
+
+// Decoder of the whitespace separated property values of an ASCII PLY file.
+type plyASCIIDecoder struct {
+	scanner *bufio.Scanner
+}
+
+// Implement the plyDecoder interface.
+func (d *plyASCIIDecoder) decode(dataType string) (float64, error) {
+	if !d.scanner.Scan() {
+		if err := d.scanner.Err(); err != nil {
+			return 0, err
+		}
+		return 0, ErrPLYInvalidElement
+	}
+
+	value, err := strconv.ParseFloat(d.scanner.Text(), 64)
+	if err != nil {
+		return 0, ErrPLYInvalidElement
+	}
+
+	return value, nil
+}
+
+// Decoder of the property values of a binary PLY file.
+type plyBinaryDecoder struct {
+	reader io.Reader
+	order  binary.ByteOrder
+	buffer [8]byte
+}
+
+// Implement the plyDecoder interface.
+func (d *plyBinaryDecoder) decode(dataType string) (float64, error) {
+	data := d.buffer[:plyTypeSizes[dataType]]
+
+	if _, err := io.ReadFull(d.reader, data); err != nil {
+		return 0, ErrPLYInvalidElement
+	}
+
+	switch dataType {
+	case "char", "int8":
+		return float64(int8(data[0])), nil
+	case "uchar", "uint8":
+		return float64(data[0]), nil
+	case "short", "int16":
+		return float64(int16(d.order.Uint16(data))), nil
+	case "ushort", "uint16":
+		return float64(d.order.Uint16(data)), nil
+	case "int", "int32":
+		return float64(int32(d.order.Uint32(data))), nil
+	case "uint", "uint32":
+		return float64(d.order.Uint32(data)), nil
+	case "float", "float32":
+		return float64(math.Float32frombits(d.order.Uint32(data))), nil
+	default:
+		return math.Float64frombits(d.order.Uint64(data)), nil
+	}
+}
+
+// PLYWriter manages writing a PLY (Stanford) file. Colors are written as
+// uchar channels and face patches as an int property named by
+// "comment patch <index> <name>" header lines.
+type PLYWriter struct {
+	writer       io.Writer
+	format       PLYFormat
+	vertices     []Vector
+	vertexColors []Color
+	faces        [][]int
+	facePatches  []int
+	faceColors   []Color
+	patches      []string
+	conversion   Conversion
+}
+
+// Construct a PLYWriter from an io.Writer interface.
+func NewPLYWriter(writer io.Writer, format PLYFormat) *PLYWriter {
+	return &PLYWriter{
+		writer:       writer,
+		format:       format,
+		vertices:     make([]Vector, 0),
+		vertexColors: make([]Color, 0),
+		faces:        make([][]int, 0),
+		facePatches:  make([]int, 0),
+		faceColors:   make([]Color, 0),
+		patches:      make([]string, 0),
+	}
+}
+
+// Set the vertices to write.
+func (w *PLYWriter) SetVertices(vertices []Vector) {
+	w.vertices = vertices
+}
+
+// Set the vertex colors to write.
+func (w *PLYWriter) SetVertexColors(vertexColors []Color) {
+	w.vertexColors = vertexColors
+}
+
+// Set the faces to write.
+func (w *PLYWriter) SetFaces(faces [][]int) {
+	w.faces = faces
+}
+
+// Set the face patches to write.
+func (w *PLYWriter) SetFacePatches(facePatches []int) {
+	w.facePatches = facePatches
+}
+
+// Set the face colors to write.
+func (w *PLYWriter) SetFaceColors(faceColors []Color) {
+	w.faceColors = faceColors
+}
+
+// Set the patches to write.
+func (w *PLYWriter) SetPatches(patches []string) {
+	w.patches = patches
+}
+
+// Set the conversion of the coordinates written (e.g. to millimeters in a
+// Y-up system). The faces are reversed if the conversion is mirrored.
+func (w *PLYWriter) SetConversion(conversion Conversion) {
+	w.conversion = conversion
+}
+
+// Write the data to the io.Writer interface.
+func (w *PLYWriter) Write() error {
+	converted := *w
+	converted.vertices = w.conversion.ConvertPoints(w.vertices)
+	converted.faces = w.conversion.ConvertFaces(w.faces)
+	return converted.write()
+}
+
+// Write the converted data.
+func (w *PLYWriter) write() error {
+	name, ok := plyFormatNames[w.format]
+	if !ok {
+		return ErrPLYInvalidHeader
+	}
+
+	hasPatches := len(w.facePatches) != 0 && len(w.patches) != 0
+	colorProperties := []string{"red", "green", "blue", "alpha"}
+	writer := bufio.NewWriter(w.writer)
+
+	header := []string{"ply", fmt.Sprintf("format %s 1.0", name)}
+
+	if hasPatches {
+		for i, patch := range w.patches {
+			header = append(header, fmt.Sprintf("comment patch %d %s", i, patch))
+		}
+	}
+
+	header = append(header, fmt.Sprintf("element vertex %d", len(w.vertices)))
+	header = append(header, "property double x", "property double y", "property double z")
+
+	if len(w.vertexColors) != 0 {
+		for _, property := range colorProperties {
+			header = append(header, "property uchar "+property)
+		}
+	}
+
+	header = append(header, fmt.Sprintf("element face %d", len(w.faces)))
+	header = append(header, "property list uchar int vertex_indices")
+
+	if hasPatches {
+		header = append(header, "property int patch")
+	}
+
+	if len(w.faceColors) != 0 {
+		for _, property := range colorProperties {
+			header = append(header, "property uchar "+property)
+		}
+	}
+
+	header = append(header, "end_header")
+
+	for _, line := range header {
+		if _, err := writer.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+
+	encoder := newPLYEncoder(writer, w.format)
+
+	for i, vertex := range w.vertices {
+		for _, value := range vertex {
+			encoder.encode("double", value)
+		}
+
+		if len(w.vertexColors) != 0 {
+			for _, value := range w.vertexColors[i] {
+				encoder.encode("uchar", float64(value))
+			}
+		}
+
+		encoder.end()
+	}
+
+	for i, face := range w.faces {
+		if len(face) > math.MaxUint8 {
+			return ErrInvalidFace
+		}
+
+		encoder.encode("uchar", float64(len(face)))
+
+		for _, vertex := range face {
+			encoder.encode("int", float64(vertex))
+		}
+
+		if hasPatches {
+			encoder.encode("int", float64(w.facePatches[i]))
+		}
+
+		if len(w.faceColors) != 0 {
+			for _, value := range w.faceColors[i] {
+				encoder.encode("uchar", float64(value))
+			}
+		}
+
+		encoder.end()
+	}
+
+	if encoder.err != nil {
+		return encoder.err
+	}
+
+	return writer.Flush()
+}
+
+// Encoder of the property values of a PLY file. The first write error is
+// retained and subsequent values are ignored.
+type plyEncoder struct {
+	writer *bufio.Writer
+	format PLYFormat
+	order  binary.AppendByteOrder
+	first  bool
+	buffer []byte
+	err    error
+}
+
+// Construct a plyEncoder for a format.
+func newPLYEncoder(writer *bufio.Writer, format PLYFormat) *plyEncoder {
+	var order binary.AppendByteOrder = binary.LittleEndian
+
+	if format == PLYFormatBinaryBigEndian {
+		order = binary.BigEndian
+	}
+
+	return &plyEncoder{
+		writer: writer,
+		format: format,
+		order:  order,
+		first:  true,
+		buffer: make([]byte, 0, 32),
+	}
+}
+
+// Encode a property value of a data type (uchar, int or double).
+func (e *plyEncoder) encode(dataType string, value float64) {
+	buffer := e.buffer[:0]
+
+	if e.format == PLYFormatASCII {
+		if !e.first {
+			buffer = append(buffer, ' ')
+		}
+
+		if dataType == "double" {
+			buffer = strconv.AppendFloat(buffer, value, 'g', -1, 64)
+		} else {
+			buffer = strconv.AppendInt(buffer, int64(value), 10)
+		}
+	} else {
+		switch dataType {
+		case "uchar":
+			buffer = append(buffer, uint8(value))
+		case "int":
+			buffer = e.order.AppendUint32(buffer, uint32(int32(value)))
+		default:
+			buffer = e.order.AppendUint64(buffer, math.Float64bits(value))
+		}
+	}
+
+	e.first = false
+	e.write(buffer)
+}
+
+// End an element.
+func (e *plyEncoder) end() {
+	if e.format == PLYFormatASCII {
+		e.write([]byte{'\n'})
+	}
+
+	e.first = true
+}
+
+// Write the bytes retaining the first error.
+func (e *plyEncoder) write(data []byte) {
+	if e.err == nil {
+		_, e.err = e.writer.Write(data)
+	}
+}
@@ -0,0 +1,252 @@
+package meshx
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Read an ASCII PLY file with colors and an unknown element.
+func TestReadPLYASCII(t *testing.T) {
+	var data string
+	data += "ply\n"
+	data += "format ascii 1.0\n"
+	data += "comment exported for testing\n"
+	data += "element vertex 4\n"
+	data += "property float x\n"
+	data += "property float y\n"
+	data += "property float z\n"
+	data += "property uchar red\n"
+	data += "property uchar green\n"
+	data += "property uchar blue\n"
+	data += "element face 2\n"
+	data += "property list uchar int vertex_indices\n"
+	data += "property float red\n"
+	data += "property float green\n"
+	data += "property float blue\n"
+	data += "property float alpha\n"
+	data += "element edge 1\n"
+	data += "property int vertex1\n"
+	data += "property int vertex2\n"
+	data += "end_header\n"
+	data += "0 0 0 255 0 0\n"
+	data += "1 0 0 0 255 0\n"
+	data += "1 1 0 0 0 255\n"
+	data += "0 1 0 255 255 255\n"
+	data += "3 0 1 2 1 0 0 1\n"
+	data += "3 0 2 3 0 0 1 0.5\n"
+	data += "0 2\n"
+
+	reader := NewPLYReader(bytes.NewBufferString(data))
+	assert.Empty(t, reader.Read())
+
+	assert.Equal(t, PLYFormatASCII, reader.GetFormat())
+	assert.Equal(t, 4, reader.GetNumberOfVertices())
+	assert.Equal(t, 2, reader.GetNumberOfFaces())
+	assert.Equal(t, 6, reader.GetNumberOfFaceEdges())
+	assert.Equal(t, 0, reader.GetNumberOfPatches())
+	assert.Equal(t, NewVector(1, 1, 0), reader.GetVertex(2))
+	assert.Equal(t, []int{0, 2, 3}, reader.GetFace(1))
+	assert.Equal(t, -1, reader.GetFacePatch(1))
+
+	assert.True(t, reader.HasVertexColors())
+	assert.Equal(t, NewColor(0, 0, 255, 255), reader.GetVertexColor(2))
+	assert.True(t, reader.HasFaceColors())
+	assert.Equal(t, NewColor(255, 0, 0, 255), reader.GetFaceColor(0))
+	assert.Equal(t, NewColor(0, 0, 255, 128), reader.GetFaceColor(1))
+}
+
+// Read a PLY file with an invalid header.
+func TestReadPLYInvalidHeader(t *testing.T) {
+	cases := []string{
+		"obj\n",
+		"ply\nformat binary 1.0\nend_header\n",
+		"ply\nelement vertex 1\nproperty float x\nend_header\n",
+		"ply\nformat ascii 1.0\nproperty float x\nend_header\n",
+		"ply\nformat ascii 1.0\nelement vertex 1\nproperty quad x\nend_header\n",
+		"ply\nformat ascii 1.0\nelement vertex 1\n",
+	}
+
+	for _, data := range cases {
+		reader := NewPLYReader(bytes.NewBufferString(data))
+		assert.Error(t, reader.Read())
+	}
+}
+
+// Read a PLY file with a face referencing a missing vertex.
+func TestReadPLYInvalidFace(t *testing.T) {
+	var data string
+	data += "ply\n"
+	data += "format ascii 1.0\n"
+	data += "element vertex 3\n"
+	data += "property double x\n"
+	data += "property double y\n"
+	data += "property double z\n"
+	data += "element face 1\n"
+	data += "property list uchar int vertex_index\n"
+	data += "end_header\n"
+	data += "0 0 0\n"
+	data += "1 0 0\n"
+	data += "1 1 0\n"
+	data += "3 0 1 3\n"
+
+	reader := NewPLYReader(bytes.NewBufferString(data))
+	assert.ErrorIs(t, reader.Read(), ErrInvalidFace)
+}
+
+// Write and read a PLY file in each format.
+func TestWritePLY(t *testing.T) {
+	vertices := []Vector{
+		NewVector(0, 0, 0),
+		NewVector(0.1, 0, 0),
+		NewVector(0.1, 0.2, 0),
+		NewVector(0, 0.2, 0.3),
+	}
+
+	faces := [][]int{
+		[]int{0, 1, 2, 3},
+		[]int{0, 2, 1},
+	}
+
+	vertexColors := []Color{
+		NewColor(255, 0, 0, 255),
+		NewColor(0, 255, 0, 255),
+		NewColor(0, 0, 255, 255),
+		NewColor(10, 20, 30, 40),
+	}
+
+	faceColors := []Color{
+		NewColor(1, 2, 3, 4),
+		NewColor(5, 6, 7, 8),
+	}
+
+	formats := []PLYFormat{
+		PLYFormatASCII,
+		PLYFormatBinaryLittleEndian,
+		PLYFormatBinaryBigEndian,
+	}
+
+	for _, format := range formats {
+		var buffer bytes.Buffer
+		writer := NewPLYWriter(&buffer, format)
+		writer.SetVertices(vertices)
+		writer.SetVertexColors(vertexColors)
+		writer.SetFaces(faces)
+		writer.SetFaceColors(faceColors)
+		writer.SetFacePatches([]int{1, -1})
+		writer.SetPatches([]string{"inlet", "outlet wall"})
+		assert.Empty(t, writer.Write())
+
+		reader := NewPLYReader(&buffer)
+		assert.Empty(t, reader.Read())
+		assert.Equal(t, format, reader.GetFormat())
+		assert.Equal(t, len(vertices), reader.GetNumberOfVertices())
+		assert.Equal(t, len(faces), reader.GetNumberOfFaces())
+		assert.Equal(t, 2, reader.GetNumberOfPatches())
+		assert.Equal(t, "outlet wall", reader.GetPatch(1))
+
+		for i, vertex := range vertices {
+			assert.Equal(t, vertex, reader.GetVertex(i))
+			assert.Equal(t, vertexColors[i], reader.GetVertexColor(i))
+		}
+
+		for i, face := range faces {
+			assert.Equal(t, face, reader.GetFace(i))
+			assert.Equal(t, faceColors[i], reader.GetFaceColor(i))
+		}
+
+		assert.Equal(t, 1, reader.GetFacePatch(0))
+		assert.Equal(t, -1, reader.GetFacePatch(1))
+	}
+}
+
+// Test writing and reading random meshes preserves them in each format.
+func TestWritePLYRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	formats := []PLYFormat{PLYFormatASCII, PLYFormatBinaryLittleEndian, PLYFormatBinaryBigEndian}
+
+	for range 100 {
+		mesh := newRandomTestMesh(rng)
+
+		for _, format := range formats {
+			var buffer bytes.Buffer
+			writer := NewPLYWriter(&buffer, format)
+			mesh.setWriter(writer)
+			assert.Empty(t, writer.Write())
+
+			reader := NewPLYReader(&buffer)
+			assert.Empty(t, reader.Read())
+			mesh.assertReader(t, reader, 1e-12)
+		}
+	}
+}
+
+// Read a PLY file transforming the vertices while reading.
+func TestReadPLYWithTransform(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mesh.ply")
+	mesh := newRandomTestMesh(rand.New(rand.NewSource(1)))
+
+	var buffer bytes.Buffer
+	writer := NewPLYWriter(&buffer, PLYFormatBinaryLittleEndian)
+	mesh.setWriter(writer)
+	assert.Empty(t, writer.Write())
+	assert.Empty(t, os.WriteFile(path, buffer.Bytes(), 0644))
+
+	for _, matrix := range testTransforms {
+		reader, err := ReadPLYWithTransform(path, matrix)
+		assert.Empty(t, err)
+		mesh.transform(matrix).assertReader(t, reader, 1e-12)
+	}
+}
+
+// Fuzz reading a PLY file. A file read without an error must reference
+// existing vertices and patches.
+func FuzzPLYReader(f *testing.F) {
+	var data string
+	data += "ply\n"
+	data += "format ascii 1.0\n"
+	data += "element vertex 3\n"
+	data += "property float x\n"
+	data += "property float y\n"
+	data += "property float z\n"
+	data += "property uchar red\n"
+	data += "element face 1\n"
+	data += "property list uchar int vertex_indices\n"
+	data += "property int patch\n"
+	data += "end_header\n"
+	data += "0 0 0 255\n"
+	data += "1 0 0 0\n"
+	data += "1 1 0 0\n"
+	data += "3 0 1 2 0\n"
+	f.Add([]byte(data))
+
+	for _, format := range []PLYFormat{PLYFormatBinaryLittleEndian, PLYFormatBinaryBigEndian} {
+		var buffer bytes.Buffer
+		writer := NewPLYWriter(&buffer, format)
+		writer.SetVertices([]Vector{NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(1, 1, 0)})
+		writer.SetFaces([][]int{{0, 1, 2}})
+		writer.SetFacePatches([]int{0})
+		writer.SetPatches([]string{"a"})
+		writer.SetFaceColors([]Color{ColorWhite})
+
+		if err := writer.Write(); err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(buffer.Bytes())
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		reader := NewPLYReader(bytes.NewReader(data))
+
+		if reader.Read() != nil {
+			return
+		}
+
+		checkTestMeshReader(t, reader, true)
+	})
+}
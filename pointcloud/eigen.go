@@ -0,0 +1,122 @@
+package pointcloud
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Compute the eigenvalues (ascending) and eigenvectors of a symmetric 3x3
+// matrix.
+func symmetricEigen3(a [3][3]float64) ([3]float64, [3]meshx.Vector) {
+	var values [3]float64
+	var vectors [3]meshx.Vector
+
+	matrix := make([][]float64, 3)
+
+	for i := range matrix {
+		matrix[i] = a[i][:]
+	}
+
+	eigenValues, eigenVectors := symmetricEigen(matrix)
+
+	for i := 0; i < 3; i++ {
+		values[i] = eigenValues[i]
+		vectors[i] = meshx.NewVector(eigenVectors[i][0], eigenVectors[i][1], eigenVectors[i][2])
+	}
+
+	return values, vectors
+}
+
+// Compute the eigenvalues (ascending) and eigenvectors of a symmetric NxN
+// matrix using the cyclic Jacobi method. The input matrix is not modified.
+func symmetricEigen(matrix [][]float64) ([]float64, [][]float64) {
+	n := len(matrix)
+	a := make([][]float64, n)
+	v := make([][]float64, n)
+
+	for i := range a {
+		a[i] = make([]float64, n)
+		copy(a[i], matrix[i])
+		v[i] = make([]float64, n)
+		v[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		var off float64
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				off += a[p][q] * a[p][q]
+			}
+		}
+
+		if off < 1e-30 {
+			break
+		}
+
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+
+				if theta < 0 {
+					t = -t
+				}
+
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					akp := a[k][p]
+					akq := a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+
+				for k := 0; k < n; k++ {
+					apk := a[p][k]
+					aqk := a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+
+				for k := 0; k < n; k++ {
+					vkp := v[k][p]
+					vkq := v[k][q]
+					v[k][p] = c*vkp - s*vkq
+					v[k][q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		return a[order[i]][order[i]] < a[order[j]][order[j]]
+	})
+
+	values := make([]float64, n)
+	vectors := make([][]float64, n)
+
+	for i, j := range order {
+		values[i] = a[j][j]
+		vectors[i] = make([]float64, n)
+
+		for k := 0; k < n; k++ {
+			vectors[i][k] = v[k][j]
+		}
+	}
+
+	return values, vectors
+}
@@ -0,0 +1,507 @@
+package pointcloud
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+)
+
+const (
+	// Maximum number of iterations of the geometric refinement of a fit.
+	FitMaxIterations = 100
+
+	// Convergence tolerance on the relative change of the sum of the squared
+	// distances between iterations.
+	FitTolerance = 1e-12
+)
+
+var (
+	ErrFitTooFewPoints = errors.New("too few points to fit")
+	ErrFitNormals      = errors.New("normals required to fit")
+	ErrFitDegenerate   = errors.New("degenerate points to fit")
+)
+
+// Least-squares plane of a set of points.
+type PlaneFit struct {
+	// Plane through the centroid with a unit normal.
+	Origin meshx.Vector
+	Normal meshx.Vector
+
+	// Root mean square distance of the points from the plane.
+	RMS float64
+}
+
+// Least-squares sphere of a set of points.
+type SphereFit struct {
+	Center meshx.Vector
+	Radius float64
+
+	// Root mean square distance of the points from the sphere.
+	RMS float64
+}
+
+// Least-squares (infinite) cylinder of a set of points.
+type CylinderFit struct {
+	// Axis through the point on the axis closest to the centroid with a
+	// unit direction.
+	Origin meshx.Vector
+	Axis   meshx.Vector
+	Radius float64
+
+	// Root mean square distance of the points from the cylinder.
+	RMS float64
+}
+
+// Least-squares (infinite, one-sided) cone of a set of points.
+type ConeFit struct {
+	// Apex of the cone with the unit axis pointing into the cone.
+	Apex meshx.Vector
+	Axis meshx.Vector
+
+	// Angle (radians) between the axis and the surface.
+	Angle float64
+
+	// Root mean square distance of the points from the cone.
+	RMS float64
+}
+
+// Construct a PointCloud from the vertices of the faces of a HalfEdgeMesh
+// (e.g. a selection to fit a primitive to) with the area-weighted normals of
+// the faces as the vertex normals.
+func NewPointCloudFromHalfEdgeFaces(mesh *halfedge.HalfEdgeMesh, faces []int) *PointCloud {
+	indexVertices := make(map[int]int)
+	points := make([]meshx.Vector, 0)
+	normals := make([]meshx.Vector, 0)
+
+	for _, face := range faces {
+		normal := mesh.GetFaceNormal(face).MulScalar(mesh.GetFaceArea(face))
+
+		for _, vertex := range mesh.GetFaceVertices(face) {
+			index, ok := indexVertices[vertex]
+
+			if !ok {
+				index = len(points)
+				indexVertices[vertex] = index
+				points = append(points, mesh.GetVertex(vertex).Point)
+				normals = append(normals, meshx.Vector{})
+			}
+
+			normals[index] = normals[index].Add(normal)
+		}
+	}
+
+	for i, normal := range normals {
+		if normal.Mag() > 0 {
+			normals[i] = normal.Unit()
+		}
+	}
+
+	cloud := NewPointCloud(points)
+	cloud.SetNormals(normals)
+
+	return cloud
+}
+
+// Fit a plane to the points by principal component analysis.
+func (p *PointCloud) FitPlane() (PlaneFit, error) {
+	if len(p.points) < 3 {
+		return PlaneFit{}, ErrFitTooFewPoints
+	}
+
+	centroid := getFitCentroid(p.points)
+	values, vectors := symmetricEigen3(getFitCovariance(p.points, centroid))
+
+	if values[1] <= 0 {
+		return PlaneFit{}, ErrFitDegenerate
+	}
+
+	fit := PlaneFit{Origin: centroid, Normal: vectors[0].Unit()}
+	fit.RMS = getFitRMS(p.points, func(point meshx.Vector) float64 {
+		return point.Sub(fit.Origin).Dot(fit.Normal)
+	})
+
+	return fit, nil
+}
+
+// Fit a sphere to the points. The algebraic fit is refined to minimize the
+// distances of the points from the sphere.
+func (p *PointCloud) FitSphere() (SphereFit, error) {
+	if len(p.points) < 4 {
+		return SphereFit{}, ErrFitTooFewPoints
+	}
+
+	// Algebraic fit of x^2 + y^2 + z^2 + Dx + Ey + Fz + G = 0.
+	rows := make([][]float64, len(p.points))
+	rhs := make([]float64, len(p.points))
+
+	for i, point := range p.points {
+		rows[i] = []float64{point[0], point[1], point[2], 1}
+		rhs[i] = -point.Dot(point)
+	}
+
+	solution, ok := solveFitLeastSquares(rows, rhs)
+
+	if !ok {
+		return SphereFit{}, ErrFitDegenerate
+	}
+
+	center := meshx.NewVector(solution[0], solution[1], solution[2]).MulScalar(-0.5)
+	radius := math.Sqrt(max(0, center.Dot(center)-solution[3]))
+
+	params := minimizeFitResiduals([]float64{center[0], center[1], center[2], radius}, len(p.points), func(params, residuals []float64) {
+		center := meshx.NewVector(params[0], params[1], params[2])
+
+		for i, point := range p.points {
+			residuals[i] = point.Sub(center).Mag() - params[3]
+		}
+	})
+
+	fit := SphereFit{Center: meshx.NewVector(params[0], params[1], params[2]), Radius: math.Abs(params[3])}
+	fit.RMS = getFitRMS(p.points, func(point meshx.Vector) float64 {
+		return point.Sub(fit.Center).Mag() - fit.Radius
+	})
+
+	return fit, nil
+}
+
+// Fit a cylinder to the points with normals. The axis is initially the
+// direction most perpendicular to the normals and the radius and origin are
+// from a circle fit of the points projected along the axis, which are then
+// refined to minimize the distances of the points from the cylinder.
+func (p *PointCloud) FitCylinder() (CylinderFit, error) {
+	if len(p.points) < 5 {
+		return CylinderFit{}, ErrFitTooFewPoints
+	}
+
+	if !p.HasNormals() {
+		return CylinderFit{}, ErrFitNormals
+	}
+
+	var moments [3][3]float64
+
+	for _, normal := range p.normals {
+		for i := range 3 {
+			for j := range 3 {
+				moments[i][j] += normal[i] * normal[j]
+			}
+		}
+	}
+
+	_, vectors := symmetricEigen3(moments)
+	axis := vectors[0].Unit()
+	centroid := getFitCentroid(p.points)
+	e1, e2 := getFitBasis(axis)
+
+	// Algebraic fit of the circle x^2 + y^2 + Dx + Ey + F = 0 in the plane
+	// perpendicular to the axis.
+	rows := make([][]float64, len(p.points))
+	rhs := make([]float64, len(p.points))
+
+	for i, point := range p.points {
+		x, y := point.Sub(centroid).Dot(e1), point.Sub(centroid).Dot(e2)
+		rows[i] = []float64{x, y, 1}
+		rhs[i] = -(x*x + y*y)
+	}
+
+	solution, ok := solveFitLeastSquares(rows, rhs)
+
+	if !ok {
+		return CylinderFit{}, ErrFitDegenerate
+	}
+
+	cx, cy := -solution[0]/2, -solution[1]/2
+	origin := centroid.Add(e1.MulScalar(cx)).Add(e2.MulScalar(cy))
+	radius := math.Sqrt(max(0, cx*cx+cy*cy-solution[2]))
+
+	// Refine the offsets of the origin and axis in the plane perpendicular
+	// to the initial axis and the radius.
+	cylinder := func(params []float64) (meshx.Vector, meshx.Vector) {
+		origin := origin.Add(e1.MulScalar(params[0])).Add(e2.MulScalar(params[1]))
+		axis := axis.Add(e1.MulScalar(params[2])).Add(e2.MulScalar(params[3])).Unit()
+		return origin, axis
+	}
+
+	params := minimizeFitResiduals([]float64{0, 0, 0, 0, radius}, len(p.points), func(params, residuals []float64) {
+		origin, axis := cylinder(params)
+
+		for i, point := range p.points {
+			residuals[i] = getAxisDistance(point, origin, axis) - params[4]
+		}
+	})
+
+	origin, axis = cylinder(params)
+	fit := CylinderFit{
+		Origin: origin.Add(axis.MulScalar(centroid.Sub(origin).Dot(axis))),
+		Axis:   axis,
+		Radius: math.Abs(params[4]),
+	}
+
+	fit.RMS = getFitRMS(p.points, func(point meshx.Vector) float64 {
+		return getAxisDistance(point, fit.Origin, fit.Axis) - fit.Radius
+	})
+
+	return fit, nil
+}
+
+// Fit a cone to the points with (consistently oriented) normals. The axis
+// is initially the direction of least variance of the normals and the apex
+// the point closest to the tangent planes of the points, which are then
+// refined to minimize the distances of the points from the cone.
+func (p *PointCloud) FitCone() (ConeFit, error) {
+	if len(p.points) < 6 {
+		return ConeFit{}, ErrFitTooFewPoints
+	}
+
+	if !p.HasNormals() {
+		return ConeFit{}, ErrFitNormals
+	}
+
+	_, vectors := symmetricEigen3(getFitCovariance(p.normals, getFitCentroid(p.normals)))
+	axis := vectors[0].Unit()
+
+	// The apex is on the tangent plane of each point.
+	rows := make([][]float64, len(p.points))
+	rhs := make([]float64, len(p.points))
+
+	for i, point := range p.points {
+		normal := p.normals[i]
+		rows[i] = []float64{normal[0], normal[1], normal[2]}
+		rhs[i] = normal.Dot(point)
+	}
+
+	solution, ok := solveFitLeastSquares(rows, rhs)
+
+	if !ok {
+		return ConeFit{}, ErrFitDegenerate
+	}
+
+	apex := meshx.NewVector(solution[0], solution[1], solution[2])
+
+	if getFitCentroid(p.points).Sub(apex).Dot(axis) < 0 {
+		axis = axis.MulScalar(-1)
+	}
+
+	var angle float64
+
+	for _, point := range p.points {
+		v := point.Sub(apex)
+		angle += math.Atan2(v.Sub(axis.MulScalar(v.Dot(axis))).Mag(), v.Dot(axis))
+	}
+
+	angle /= float64(len(p.points))
+	e1, e2 := getFitBasis(axis)
+
+	// Refine the apex, the offset of the axis in the plane perpendicular to
+	// the initial axis and the angle.
+	cone := func(params []float64) (meshx.Vector, meshx.Vector) {
+		apex := apex.Add(meshx.NewVector(params[0], params[1], params[2]))
+		axis := axis.Add(e1.MulScalar(params[3])).Add(e2.MulScalar(params[4])).Unit()
+		return apex, axis
+	}
+
+	params := minimizeFitResiduals([]float64{0, 0, 0, 0, 0, angle}, len(p.points), func(params, residuals []float64) {
+		apex, axis := cone(params)
+
+		for i, point := range p.points {
+			residuals[i] = getConeDistance(point, apex, axis, params[5])
+		}
+	})
+
+	apex, axis = cone(params)
+	fit := ConeFit{Apex: apex, Axis: axis, Angle: params[5]}
+	fit.RMS = getFitRMS(p.points, func(point meshx.Vector) float64 {
+		return getConeDistance(point, fit.Apex, fit.Axis, fit.Angle)
+	})
+
+	return fit, nil
+}
+
+// Get the centroid of the points.
+func getFitCentroid(points []meshx.Vector) meshx.Vector {
+	var centroid meshx.Vector
+
+	for _, point := range points {
+		centroid = centroid.Add(point)
+	}
+
+	return centroid.DivScalar(float64(len(points)))
+}
+
+// Get the (unnormalized) covariance of the points about a center.
+func getFitCovariance(points []meshx.Vector, center meshx.Vector) [3][3]float64 {
+	var covariance [3][3]float64
+
+	for _, point := range points {
+		d := point.Sub(center)
+
+		for i := range 3 {
+			for j := range 3 {
+				covariance[i][j] += d[i] * d[j]
+			}
+		}
+	}
+
+	return covariance
+}
+
+// Get a unit basis of the plane perpendicular to a unit axis.
+func getFitBasis(axis meshx.Vector) (meshx.Vector, meshx.Vector) {
+	other := meshx.NewVector(1, 0, 0)
+
+	if math.Abs(axis[0]) > 0.9 {
+		other = meshx.NewVector(0, 1, 0)
+	}
+
+	e1 := axis.Cross(other).Unit()
+	return e1, axis.Cross(e1)
+}
+
+// Get the distance of a point from an axis (origin and unit direction).
+func getAxisDistance(point, origin, axis meshx.Vector) float64 {
+	v := point.Sub(origin)
+	return v.Sub(axis.MulScalar(v.Dot(axis))).Mag()
+}
+
+// Get the signed distance of a point from a cone (positive outside).
+func getConeDistance(point, apex, axis meshx.Vector, angle float64) float64 {
+	v := point.Sub(apex)
+	h := v.Dot(axis)
+	rho := v.Sub(axis.MulScalar(h)).Mag()
+	return rho*math.Cos(angle) - h*math.Sin(angle)
+}
+
+// Get the root mean square of the distances of the points.
+func getFitRMS(points []meshx.Vector, distance func(meshx.Vector) float64) float64 {
+	var sum float64
+
+	for _, point := range points {
+		d := distance(point)
+		sum += d * d
+	}
+
+	return math.Sqrt(sum / float64(len(points)))
+}
+
+// Solve the overdetermined linear system by the normal equations.
+func solveFitLeastSquares(rows [][]float64, rhs []float64) ([]float64, bool) {
+	n := len(rows[0])
+	normal := make([][]float64, n)
+	b := make([]float64, n)
+
+	for i := range normal {
+		normal[i] = make([]float64, n)
+	}
+
+	for k, row := range rows {
+		for i := range n {
+			b[i] += row[i] * rhs[k]
+
+			for j := range n {
+				normal[i][j] += row[i] * row[j]
+			}
+		}
+	}
+
+	return meshx.SolveLinear(normal, b)
+}
+
+// Minimize the sum of the squared residuals over the parameters with the
+// Levenberg-Marquardt method and a forward-difference Jacobian.
+func minimizeFitResiduals(params []float64, count int, residuals func(params, residuals []float64)) []float64 {
+	n := len(params)
+	current := make([]float64, count)
+	trial := make([]float64, count)
+	jacobian := make([][]float64, count)
+
+	for i := range jacobian {
+		jacobian[i] = make([]float64, n)
+	}
+
+	residuals(params, current)
+	cost := getFitSumSquares(current)
+	damping := 1e-3
+
+	for range FitMaxIterations {
+		for j := range n {
+			step := 1e-7 * max(1, math.Abs(params[j]))
+			shifted := append([]float64(nil), params...)
+			shifted[j] += step
+			residuals(shifted, trial)
+
+			for i := range count {
+				jacobian[i][j] = (trial[i] - current[i]) / step
+			}
+		}
+
+		normal := make([][]float64, n)
+		gradient := make([]float64, n)
+
+		for i := range n {
+			normal[i] = make([]float64, n)
+
+			for k := range count {
+				gradient[i] -= jacobian[k][i] * current[k]
+
+				for j := range n {
+					normal[i][j] += jacobian[k][i] * jacobian[k][j]
+				}
+			}
+		}
+
+		improved := false
+
+		for ; damping < 1e12 && !improved; damping *= 10 {
+			damped := make([][]float64, n)
+
+			for i := range n {
+				damped[i] = append([]float64(nil), normal[i]...)
+				damped[i][i] += damping * (normal[i][i] + 1e-12)
+			}
+
+			step, ok := meshx.SolveLinear(damped, gradient)
+
+			if !ok {
+				continue
+			}
+
+			candidate := make([]float64, n)
+
+			for i := range n {
+				candidate[i] = params[i] + step[i]
+			}
+
+			residuals(candidate, trial)
+
+			if trialCost := getFitSumSquares(trial); trialCost < cost {
+				improved = true
+				converged := cost-trialCost <= FitTolerance*cost
+				params, cost = candidate, trialCost
+				copy(current, trial)
+				damping /= 100
+
+				if converged {
+					return params
+				}
+			}
+		}
+
+		if !improved {
+			break
+		}
+	}
+
+	return params
+}
+
+// Get the sum of the squared residuals.
+func getFitSumSquares(residuals []float64) float64 {
+	var sum float64
+
+	for _, r := range residuals {
+		sum += r * r
+	}
+
+	return sum
+}
@@ -0,0 +1,136 @@
+package pointcloud
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate points (with normals) on a cylinder about an axis through the
+// origin with a noise in the radial direction.
+func newFitTestCylinder(n int, origin, axis meshx.Vector, radius, noise float64) ([]meshx.Vector, []meshx.Vector) {
+	random := rand.New(rand.NewSource(0))
+	e1, e2 := getFitBasis(axis)
+	points := make([]meshx.Vector, n)
+	normals := make([]meshx.Vector, n)
+
+	for i := range points {
+		angle := 2 * math.Pi * random.Float64()
+		height := 4*random.Float64() - 2
+		normal := e1.MulScalar(math.Cos(angle)).Add(e2.MulScalar(math.Sin(angle)))
+		offset := radius + noise*(2*random.Float64()-1)
+		points[i] = origin.Add(axis.MulScalar(height)).Add(normal.MulScalar(offset))
+		normals[i] = normal
+	}
+
+	return points, normals
+}
+
+// Test fitting a plane to points in a plane.
+func TestFitPlane(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+	points := make([]meshx.Vector, 100)
+
+	for i := range points {
+		x, y := random.Float64(), random.Float64()
+		points[i] = meshx.NewVector(x, y, 2+0.5*x-0.25*y)
+	}
+
+	fit, err := NewPointCloud(points).FitPlane()
+
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, fit.RMS, 1e-9)
+	assert.InDelta(t, 1, math.Abs(fit.Normal.Dot(meshx.NewVector(-0.5, 0.25, 1).Unit())), 1e-9)
+
+	for _, point := range points {
+		assert.InDelta(t, 0, point.Sub(fit.Origin).Dot(fit.Normal), 1e-9)
+	}
+}
+
+// Test fitting a plane to too few points.
+func TestFitPlaneTooFewPoints(t *testing.T) {
+	_, err := NewPointCloud([]meshx.Vector{{0, 0, 0}, {1, 0, 0}}).FitPlane()
+
+	assert.ErrorIs(t, err, ErrFitTooFewPoints)
+}
+
+// Test fitting a sphere to noisy points on a sphere.
+func TestFitSphere(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+	center := meshx.NewVector(1, -2, 3)
+	points := make([]meshx.Vector, 500)
+
+	for i := range points {
+		direction := meshx.NewVector(random.NormFloat64(), random.NormFloat64(), random.NormFloat64()).Unit()
+		points[i] = center.Add(direction.MulScalar(2 + 0.01*(2*random.Float64()-1)))
+	}
+
+	fit, err := NewPointCloud(points).FitSphere()
+
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, fit.Center.Sub(center).Mag(), 1e-3)
+	assert.InDelta(t, 2, fit.Radius, 1e-3)
+	assert.InDelta(t, 0.01/math.Sqrt(3), fit.RMS, 1e-3)
+}
+
+// Test fitting a cylinder to points on an oblique cylinder.
+func TestFitCylinder(t *testing.T) {
+	origin := meshx.NewVector(1, 2, 3)
+	axis := meshx.NewVector(1, 1, 2).Unit()
+	points, normals := newFitTestCylinder(500, origin, axis, 0.5, 0)
+
+	// Perturb the normals such that the initial estimate is refined.
+	random := rand.New(rand.NewSource(1))
+
+	for i, normal := range normals {
+		noise := meshx.NewVector(random.NormFloat64(), random.NormFloat64(), random.NormFloat64())
+		normals[i] = normal.Add(noise.MulScalar(0.05)).Unit()
+	}
+
+	cloud := NewPointCloud(points)
+	cloud.SetNormals(normals)
+	fit, err := cloud.FitCylinder()
+
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.5, fit.Radius, 1e-6)
+	assert.InDelta(t, 0, fit.RMS, 1e-6)
+	assert.InDelta(t, 1, math.Abs(fit.Axis.Dot(axis)), 1e-9)
+	assert.InDelta(t, 0, getAxisDistance(fit.Origin, origin, axis), 1e-6)
+}
+
+// Test fitting a cylinder without normals.
+func TestFitCylinderNormals(t *testing.T) {
+	points, _ := newFitTestCylinder(100, meshx.Vector{}, meshx.NewVector(0, 0, 1), 1, 0)
+	_, err := NewPointCloud(points).FitCylinder()
+
+	assert.ErrorIs(t, err, ErrFitNormals)
+}
+
+// Test fitting a cone to the faces of a revolved cone.
+func TestFitCone(t *testing.T) {
+	apex := meshx.NewVector(0, 0, 2)
+	profile := []meshx.Vector{{1, 0, 0}, {0.75, 0, 0.5}, {0.5, 0, 1}, {0.25, 0, 1.5}}
+	mesh, err := halfedge.NewRevolve(profile, meshx.Vector{}, meshx.NewVector(0, 0, 1), 64)
+	assert.Nil(t, err)
+
+	faces := make([]int, mesh.GetNumberOfFaces())
+
+	for i := range faces {
+		faces[i] = i
+	}
+
+	cloud := NewPointCloudFromHalfEdgeFaces(mesh, faces)
+	fit, err := cloud.FitCone()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4*64, cloud.GetNumberOfPoints())
+	assert.True(t, cloud.HasNormals())
+	assert.InDelta(t, 0, fit.Apex.Sub(apex).Mag(), 1e-6)
+	assert.InDelta(t, 1, -fit.Axis[2], 1e-9)
+	assert.InDelta(t, math.Atan(0.5), fit.Angle, 1e-6)
+	assert.InDelta(t, 0, fit.RMS, 1e-6)
+}
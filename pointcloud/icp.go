@@ -0,0 +1,223 @@
+package pointcloud
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+)
+
+const (
+	ICPMaxIterations = 50
+	ICPTolerance     = 1e-10
+)
+
+var (
+	ErrICPNoCorrespondences = errors.New("no correspondences")
+	ErrICPTargetNormals     = errors.New("target normals required")
+	ErrICPDegenerate        = errors.New("degenerate correspondences")
+)
+
+// Options for the iterative closest point registration.
+type ICPOptions struct {
+	// Maximum number of iterations. If zero, ICPMaxIterations is used.
+	MaxIterations int
+
+	// Convergence tolerance on the change of the RMS error between
+	// iterations. If zero, ICPTolerance is used.
+	Tolerance float64
+
+	// Maximum distance of a correspondence. If zero, all correspondences
+	// are used.
+	MaxCorrespondenceDistance float64
+
+	// Minimize the point-to-plane error rather than the point-to-point
+	// error. The target must have normals.
+	PointToPlane bool
+
+	// Initial transform of the source. If zero, the identity is used.
+	InitialTransform meshx.Matrix4
+}
+
+// Result of the iterative closest point registration.
+type ICPResult struct {
+	Transform  meshx.Matrix4
+	RMS        float64
+	Iterations int
+	Converged  bool
+}
+
+// Construct a PointCloud from the vertices of a HalfEdgeMesh.
+func NewPointCloudFromHalfEdgeMesh(mesh *halfedge.HalfEdgeMesh) *PointCloud {
+	points := make([]meshx.Vector, mesh.GetNumberOfVertices())
+
+	for i := range points {
+		points[i] = mesh.GetVertex(i).Point
+	}
+
+	return NewPointCloud(points)
+}
+
+// Compute the rigid transform best aligning the source onto the target by
+// iterative closest point (ICP) registration.
+func Register(source, target *PointCloud, options ICPOptions) (ICPResult, error) {
+	if options.MaxIterations == 0 {
+		options.MaxIterations = ICPMaxIterations
+	}
+
+	if options.Tolerance == 0 {
+		options.Tolerance = ICPTolerance
+	}
+
+	if options.PointToPlane && !target.HasNormals() {
+		return ICPResult{}, ErrICPTargetNormals
+	}
+
+	result := ICPResult{
+		Transform: options.InitialTransform,
+		RMS:       math.Inf(1),
+	}
+
+	if result.Transform == (meshx.Matrix4{}) {
+		result.Transform = meshx.NewIdentityMatrix4()
+	}
+
+	tree := target.BuildKDTree()
+	points := make([]meshx.Vector, 0, source.GetNumberOfPoints())
+	matches := make([]int, 0, source.GetNumberOfPoints())
+
+	for result.Iterations < options.MaxIterations {
+		var sumSquares float64
+
+		points = points[:0]
+		matches = matches[:0]
+
+		for _, point := range source.points {
+			point = result.Transform.MulPoint(point)
+			match, distance := tree.QueryNearest(point)
+
+			if match != -1 && (options.MaxCorrespondenceDistance == 0 || distance <= options.MaxCorrespondenceDistance) {
+				points = append(points, point)
+				matches = append(matches, match)
+				sumSquares += distance * distance
+			}
+		}
+
+		if len(points) < 3 {
+			return result, ErrICPNoCorrespondences
+		}
+
+		rms := math.Sqrt(sumSquares / float64(len(points)))
+
+		if math.Abs(result.RMS-rms) < options.Tolerance {
+			result.RMS = rms
+			result.Converged = true
+			break
+		}
+
+		result.RMS = rms
+
+		var increment meshx.Matrix4
+		var ok bool
+
+		if options.PointToPlane {
+			increment, ok = alignPointToPlane(points, matches, target)
+		} else {
+			increment, ok = alignPointToPoint(points, matches, target)
+		}
+
+		if !ok {
+			return result, ErrICPDegenerate
+		}
+
+		result.Transform = increment.Mul(result.Transform)
+		result.Iterations++
+	}
+
+	return result, nil
+}
+
+// Compute the rigid transform minimizing the point-to-point distance of the
+// correspondences (Horn's closed form with unit quaternions).
+func alignPointToPoint(points []meshx.Vector, matches []int, target *PointCloud) (meshx.Matrix4, bool) {
+	var p, q meshx.Vector
+	var s [3][3]float64
+
+	for i, point := range points {
+		p = p.Add(point)
+		q = q.Add(target.points[matches[i]])
+	}
+
+	p = p.DivScalar(float64(len(points)))
+	q = q.DivScalar(float64(len(points)))
+
+	for i, point := range points {
+		a := point.Sub(p)
+		b := target.points[matches[i]].Sub(q)
+
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				s[j][k] += a[j] * b[k]
+			}
+		}
+	}
+
+	n := [][]float64{
+		{s[0][0] + s[1][1] + s[2][2], s[1][2] - s[2][1], s[2][0] - s[0][2], s[0][1] - s[1][0]},
+		{s[1][2] - s[2][1], s[0][0] - s[1][1] - s[2][2], s[0][1] + s[1][0], s[2][0] + s[0][2]},
+		{s[2][0] - s[0][2], s[0][1] + s[1][0], -s[0][0] + s[1][1] - s[2][2], s[1][2] + s[2][1]},
+		{s[0][1] - s[1][0], s[2][0] + s[0][2], s[1][2] + s[2][1], -s[0][0] - s[1][1] + s[2][2]},
+	}
+
+	_, vectors := symmetricEigen(n)
+	quaternion := vectors[3]
+
+	rotation := meshx.NewRotationMatrix4FromQuaternion(quaternion[0], quaternion[1], quaternion[2], quaternion[3])
+	translation := meshx.NewTranslationMatrix4(q.Sub(rotation.MulPoint(p)))
+
+	return translation.Mul(rotation), true
+}
+
+// Compute the rigid transform minimizing the point-to-plane distance of the
+// correspondences (linearized for small rotations).
+func alignPointToPlane(points []meshx.Vector, matches []int, target *PointCloud) (meshx.Matrix4, bool) {
+	a := make([][]float64, 6)
+	b := make([]float64, 6)
+
+	for i := range a {
+		a[i] = make([]float64, 6)
+	}
+
+	for i, point := range points {
+		q := target.points[matches[i]]
+		n := target.normals[matches[i]]
+		c := point.Cross(n)
+		row := [6]float64{c[0], c[1], c[2], n[0], n[1], n[2]}
+		residual := q.Sub(point).Dot(n)
+
+		for j := 0; j < 6; j++ {
+			for k := 0; k < 6; k++ {
+				a[j][k] += row[j] * row[k]
+			}
+
+			b[j] += row[j] * residual
+		}
+	}
+
+	x, ok := meshx.SolveLinear(a, b)
+	if !ok {
+		return meshx.Matrix4{}, false
+	}
+
+	rotation := meshx.NewIdentityMatrix4()
+	angles := meshx.NewVector(x[0], x[1], x[2])
+
+	if angle := angles.Mag(); angle > 0 {
+		rotation = meshx.NewRotationMatrix4(angles, angle)
+	}
+
+	translation := meshx.NewTranslationMatrix4(meshx.NewVector(x[3], x[4], x[5]))
+
+	return translation.Mul(rotation), true
+}
@@ -0,0 +1,102 @@
+package pointcloud
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate points on the surface z = x^2 + y^2 / 2.
+func newICPTestSurface(n int) []meshx.Vector {
+	random := rand.New(rand.NewSource(0))
+	points := make([]meshx.Vector, n)
+
+	for i := range points {
+		x := 2*random.Float64() - 1
+		y := 2*random.Float64() - 1
+		points[i] = meshx.NewVector(x, y, x*x+0.5*y*y)
+	}
+
+	return points
+}
+
+// Apply a transform to a set of points.
+func transformICPTestPoints(points []meshx.Vector, transform meshx.Matrix4) []meshx.Vector {
+	transformed := make([]meshx.Vector, len(points))
+
+	for i, point := range points {
+		transformed[i] = transform.MulPoint(point)
+	}
+
+	return transformed
+}
+
+// Test the point-to-point registration of a displaced copy.
+func TestRegisterPointToPoint(t *testing.T) {
+	points := newICPTestSurface(2000)
+	transform := meshx.NewTranslationMatrix4(meshx.NewVector(0.05, -0.02, 0.03)).
+		Mul(meshx.NewRotationMatrix4(meshx.NewVector(1, 2, 3), 0.05))
+
+	source := NewPointCloud(transformICPTestPoints(points, transform))
+	target := NewPointCloud(points)
+
+	result, err := Register(source, target, ICPOptions{MaxIterations: 200})
+	assert.Empty(t, err)
+	assert.True(t, result.Converged)
+	assert.Less(t, result.RMS, 1e-6)
+
+	for _, point := range points[:10] {
+		aligned := result.Transform.MulPoint(transform.MulPoint(point))
+		assert.InDelta(t, 0.0, aligned.Sub(point).Mag(), 1e-5)
+	}
+}
+
+// Test the point-to-plane registration of a displaced copy.
+func TestRegisterPointToPlane(t *testing.T) {
+	points := newICPTestSurface(2000)
+	transform := meshx.NewTranslationMatrix4(meshx.NewVector(0.05, -0.02, 0.03)).
+		Mul(meshx.NewRotationMatrix4(meshx.NewVector(1, 2, 3), 0.05))
+
+	source := NewPointCloud(transformICPTestPoints(points, transform))
+	target := NewPointCloud(points)
+
+	_, err := Register(source, target, ICPOptions{PointToPlane: true})
+	assert.ErrorIs(t, err, ErrICPTargetNormals)
+
+	target.EstimateNormals(12)
+
+	result, err := Register(source, target, ICPOptions{PointToPlane: true})
+	assert.Empty(t, err)
+	assert.True(t, result.Converged)
+	assert.Less(t, result.RMS, 1e-6)
+}
+
+// Test the point-to-plane registration onto a cylinder is degenerate: it is
+// free to rotate about and translate along its axis.
+func TestRegisterPointToPlaneCylinder(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+	points := make([]meshx.Vector, 2000)
+	normals := make([]meshx.Vector, len(points))
+
+	// Unit cylinder along an oblique axis away from the origin.
+	frame := meshx.NewTranslationMatrix4(meshx.NewVector(10, -5, 3)).
+		Mul(meshx.NewRotationMatrix4(meshx.NewVector(1, 2, 3), 0.7))
+
+	for i := range points {
+		angle := 2 * math.Pi * random.Float64()
+		normal := meshx.NewVector(math.Cos(angle), math.Sin(angle), 0)
+		normals[i] = frame.MulVector(normal)
+		points[i] = frame.MulPoint(normal.Add(meshx.NewVector(0, 0, 4*random.Float64()-2)))
+	}
+
+	transform := meshx.NewTranslationMatrix4(meshx.NewVector(0.05, -0.02, 0.01))
+	source := NewPointCloud(transformICPTestPoints(points, transform))
+	target := NewPointCloud(points)
+	target.SetNormals(normals)
+
+	_, err := Register(source, target, ICPOptions{PointToPlane: true})
+	assert.ErrorIs(t, err, ErrICPDegenerate)
+}
@@ -0,0 +1,261 @@
+package pointcloud
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Unstructured set of points with optional normals.
+type PointCloud struct {
+	points  []meshx.Vector
+	normals []meshx.Vector
+}
+
+// Construct a PointCloud from its points.
+func NewPointCloud(points []meshx.Vector) *PointCloud {
+	return &PointCloud{
+		points:  points,
+		normals: make([]meshx.Vector, 0),
+	}
+}
+
+// Construct a PointCloud from the vertices of a MeshReader.
+func NewPointCloudFromMeshReader(source meshx.MeshReader) *PointCloud {
+	points := make([]meshx.Vector, source.GetNumberOfVertices())
+
+	for i := range points {
+		points[i] = source.GetVertex(i)
+	}
+
+	return NewPointCloud(points)
+}
+
+// Read a PointCloud from the vertices of an OBJ file path.
+func ReadOBJFromPath(path string) (*PointCloud, error) {
+	source, err := meshx.ReadOBJFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPointCloudFromMeshReader(source), nil
+}
+
+// Get the number of points.
+func (p *PointCloud) GetNumberOfPoints() int {
+	return len(p.points)
+}
+
+// Get a point by index.
+func (p *PointCloud) GetPoint(index int) meshx.Vector {
+	return p.points[index]
+}
+
+// Get the points.
+func (p *PointCloud) GetPoints() []meshx.Vector {
+	return p.points
+}
+
+// Return true if the normals are set.
+func (p *PointCloud) HasNormals() bool {
+	return len(p.normals) == len(p.points) && len(p.points) > 0
+}
+
+// Get a normal by index.
+func (p *PointCloud) GetNormal(index int) meshx.Vector {
+	return p.normals[index]
+}
+
+// Set the normals.
+func (p *PointCloud) SetNormals(normals []meshx.Vector) {
+	p.normals = normals
+}
+
+// Get the axis-aligned bounding box.
+func (p *PointCloud) GetAABB() meshx.AABB {
+	return meshx.NewAABBFromVectors(p.points)
+}
+
+// Build a KDTree of the points.
+func (p *PointCloud) BuildKDTree() *spatial.KDTree {
+	return spatial.NewKDTree(p.points)
+}
+
+// Estimate the unit normal of each point from the principal component
+// analysis of its k nearest neighbors. The normal is the direction of least
+// variance. The sign of the normals is arbitrary (see OrientNormalsTowards).
+func (p *PointCloud) EstimateNormals(k int) {
+	tree := p.BuildKDTree()
+	p.normals = make([]meshx.Vector, len(p.points))
+
+	for i, point := range p.points {
+		neighbors := tree.QueryKNearest(point, k)
+		p.normals[i] = p.estimateNormal(neighbors)
+	}
+}
+
+// Estimate the normal of a set of points by principal component analysis.
+func (p *PointCloud) estimateNormal(indices []int) meshx.Vector {
+	var centroid meshx.Vector
+	var covariance [3][3]float64
+
+	for _, index := range indices {
+		centroid = centroid.Add(p.points[index])
+	}
+
+	centroid = centroid.DivScalar(float64(len(indices)))
+
+	for _, index := range indices {
+		d := p.points[index].Sub(centroid)
+
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				covariance[i][j] += d[i] * d[j]
+			}
+		}
+	}
+
+	_, vectors := symmetricEigen3(covariance)
+
+	return vectors[0].Unit()
+}
+
+// Transform the points (in place) by an affine Matrix4. The normals are
+// transformed by its inverse transpose (see Matrix4.MulNormal) to remain
+// perpendicular to the surface under non-uniform scaling and shear.
+func (p *PointCloud) Transform(matrix meshx.Matrix4) {
+	for i, point := range p.points {
+		p.points[i] = matrix.MulPoint(point)
+	}
+
+	for i, normal := range p.normals {
+		p.normals[i] = matrix.MulNormal(normal)
+	}
+}
+
+// Flip the normals (in place) such that each points toward a viewpoint.
+func (p *PointCloud) OrientNormalsTowards(viewpoint meshx.Vector) {
+	for i, normal := range p.normals {
+		if normal.Dot(viewpoint.Sub(p.points[i])) < 0 {
+			p.normals[i] = normal.MulScalar(-1)
+		}
+	}
+}
+
+// Remove the statistical outliers (in place). A point is an outlier if the
+// mean distance to its k nearest neighbors exceeds the global mean of the
+// mean distances by more than the ratio of its standard deviation.
+func (p *PointCloud) RemoveOutliers(k int, stdRatio float64) {
+	if len(p.points) == 0 {
+		return
+	}
+
+	tree := p.BuildKDTree()
+	distances := make([]float64, len(p.points))
+
+	var sum, sumSquares float64
+
+	for i, point := range p.points {
+		neighbors := tree.QueryKNearest(point, k+1)
+
+		for _, neighbor := range neighbors {
+			distances[i] += p.points[neighbor].Sub(point).Mag()
+		}
+
+		if len(neighbors) > 1 {
+			distances[i] /= float64(len(neighbors) - 1)
+		}
+
+		sum += distances[i]
+		sumSquares += distances[i] * distances[i]
+	}
+
+	n := float64(len(p.points))
+	mean := sum / n
+	std := math.Sqrt(max(0, sumSquares/n-mean*mean))
+	threshold := mean + stdRatio*std
+
+	keep := make([]int, 0, len(p.points))
+
+	for i, distance := range distances {
+		if distance <= threshold {
+			keep = append(keep, i)
+		}
+	}
+
+	*p = *p.Extract(keep)
+}
+
+// Extract the points into a new PointCloud.
+func (p *PointCloud) Extract(indices []int) *PointCloud {
+	cloud := NewPointCloud(make([]meshx.Vector, len(indices)))
+
+	if p.HasNormals() {
+		cloud.normals = make([]meshx.Vector, len(indices))
+	}
+
+	for i, index := range indices {
+		cloud.points[i] = p.points[index]
+
+		if p.HasNormals() {
+			cloud.normals[i] = p.normals[index]
+		}
+	}
+
+	return cloud
+}
+
+// Downsample the points into a new PointCloud with a single point (the
+// centroid) per occupied voxel of a uniform grid. Normals are averaged.
+func (p *PointCloud) VoxelDownsample(size float64) *PointCloud {
+	if len(p.points) == 0 {
+		return NewPointCloud(make([]meshx.Vector, 0))
+	}
+
+	origin := p.GetAABB().GetMinBound()
+	indexVoxels := make(map[[3]int64]int)
+	counts := make([]int, 0)
+	points := make([]meshx.Vector, 0)
+	normals := make([]meshx.Vector, 0)
+
+	for i, point := range p.points {
+		var key [3]int64
+
+		for j := 0; j < 3; j++ {
+			key[j] = int64(math.Floor((point[j] - origin[j]) / size))
+		}
+
+		voxel, ok := indexVoxels[key]
+
+		if !ok {
+			voxel = len(points)
+			indexVoxels[key] = voxel
+			points = append(points, meshx.Vector{})
+			normals = append(normals, meshx.Vector{})
+			counts = append(counts, 0)
+		}
+
+		points[voxel] = points[voxel].Add(point)
+		counts[voxel]++
+
+		if p.HasNormals() {
+			normals[voxel] = normals[voxel].Add(p.normals[i])
+		}
+	}
+
+	for i := range points {
+		points[i] = points[i].DivScalar(float64(counts[i]))
+
+		if p.HasNormals() && normals[i].Mag() > 0 {
+			normals[i] = normals[i].Unit()
+		}
+	}
+
+	cloud := NewPointCloud(points)
+
+	if p.HasNormals() {
+		cloud.normals = normals
+	}
+
+	return cloud
+}
@@ -0,0 +1,94 @@
+package pointcloud
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate random points on the plane z = 0 within the unit square.
+func newPointCloudTestPlane(n int) []meshx.Vector {
+	random := rand.New(rand.NewSource(0))
+	points := make([]meshx.Vector, n)
+
+	for i := range points {
+		points[i] = meshx.NewVector(random.Float64(), random.Float64(), 0)
+	}
+
+	return points
+}
+
+// Test estimating the normals of planar points.
+func TestEstimateNormals(t *testing.T) {
+	cloud := NewPointCloud(newPointCloudTestPlane(500))
+	cloud.EstimateNormals(10)
+	cloud.OrientNormalsTowards(meshx.NewVector(0, 0, 10))
+
+	assert.True(t, cloud.HasNormals())
+
+	for i := range cloud.GetNumberOfPoints() {
+		assert.InDelta(t, 1.0, cloud.GetNormal(i)[2], 1e-9)
+	}
+}
+
+// Test removing outliers from planar points.
+func TestRemoveOutliers(t *testing.T) {
+	points := newPointCloudTestPlane(500)
+	points = append(points, meshx.NewVector(0.5, 0.5, 5), meshx.NewVector(-3, 0, 0))
+
+	cloud := NewPointCloud(points)
+	cloud.RemoveOutliers(8, 2)
+
+	assert.Equal(t, 500, cloud.GetNumberOfPoints())
+
+	for i := range cloud.GetNumberOfPoints() {
+		assert.Equal(t, 0.0, cloud.GetPoint(i)[2])
+	}
+}
+
+// Test downsampling points to a voxel grid.
+func TestVoxelDownsample(t *testing.T) {
+	points := make([]meshx.Vector, 0)
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			x := 0.25 * float64(i)
+			y := 0.25 * float64(j)
+			points = append(points, meshx.NewVector(x+0.1, y+0.1, 0))
+			points = append(points, meshx.NewVector(x+0.15, y+0.15, 0))
+		}
+	}
+
+	cloud := NewPointCloud(points)
+	downsampled := cloud.VoxelDownsample(0.25)
+
+	assert.Equal(t, 16, downsampled.GetNumberOfPoints())
+	assert.InDelta(t, 0.125, downsampled.GetPoint(0)[0], 1e-12)
+	assert.InDelta(t, 0.875, downsampled.GetPoint(15)[1], 1e-12)
+}
+
+// Test the eigen decomposition of a symmetric matrix.
+func TestSymmetricEigen3(t *testing.T) {
+	a := [3][3]float64{{2, 1, 0}, {1, 2, 0}, {0, 0, 5}}
+	values, vectors := symmetricEigen3(a)
+
+	assert.InDelta(t, 1.0, values[0], 1e-12)
+	assert.InDelta(t, 3.0, values[1], 1e-12)
+	assert.InDelta(t, 5.0, values[2], 1e-12)
+	assert.InDelta(t, 0.0, vectors[0][0]+vectors[0][1], 1e-12)
+	assert.InDelta(t, 1.0, math.Abs(vectors[2][2]), 1e-12)
+}
+
+// Test transforming the points and normals by a non-uniform scale.
+func TestTransform(t *testing.T) {
+	cloud := NewPointCloud([]meshx.Vector{{1, 0, 0}, {0, 1, 0}})
+	cloud.SetNormals([]meshx.Vector{meshx.NewVector(1, 1, 0).Unit(), {0, 0, 1}})
+	cloud.Transform(meshx.NewScaleMatrix4(meshx.NewVector(2, 1, 1)))
+
+	assert.Equal(t, meshx.NewVector(2, 0, 0), cloud.GetPoint(0))
+	assert.InDelta(t, 0, cloud.GetNormal(0).Sub(meshx.NewVector(1, 2, 0).Unit()).Mag(), 1e-12)
+	assert.Equal(t, meshx.NewVector(0, 0, 1), cloud.GetNormal(1))
+}
@@ -0,0 +1,141 @@
+package meshx
+
+import (
+	"math"
+)
+
+// Half-space bounded by a plane. A point is inside if its signed distance
+// along the unit outward normal is at most the offset.
+type HalfSpace struct {
+	Normal Vector
+	Offset float64
+}
+
+// Construct a HalfSpace from a point on its plane and its outward normal.
+func NewHalfSpace(point, normal Vector) HalfSpace {
+	normal = normal.Unit()
+	return HalfSpace{normal, normal.Dot(point)}
+}
+
+// Compute the signed distance of a point to the plane (positive outside).
+func (h HalfSpace) SignedDistance(point Vector) float64 {
+	return h.Normal.Dot(point) - h.Offset
+}
+
+// Solid convex polytope in three-dimensional Cartesian space defined by the
+// intersection of half-spaces (e.g. a view frustum). The polytope may be
+// unbounded.
+type Polytope struct {
+	HalfSpaces []HalfSpace
+}
+
+// Construct a Polytope from its half-spaces.
+func NewPolytope(halfSpaces ...HalfSpace) Polytope {
+	return Polytope{halfSpaces}
+}
+
+// Construct the Polytope of an AABB.
+func NewPolytopeFromAABB(aabb AABB) Polytope {
+	minBound := aabb.GetMinBound()
+	maxBound := aabb.GetMaxBound()
+	halfSpaces := make([]HalfSpace, 0, 6)
+
+	for i := 0; i < 3; i++ {
+		var normal Vector
+		normal[i] = 1
+		halfSpaces = append(halfSpaces, NewHalfSpace(maxBound, normal))
+		halfSpaces = append(halfSpaces, NewHalfSpace(minBound, normal.MulScalar(-1)))
+	}
+
+	return Polytope{halfSpaces}
+}
+
+// Check if the polytope contains a point (including its boundary).
+func (p Polytope) ContainsPoint(point Vector) bool {
+	for _, halfSpace := range p.HalfSpaces {
+		if halfSpace.SignedDistance(point) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Implement the IntersectsAABB interface. The test is conservative: the
+// AABB is only rejected if it is outside one of the half-spaces, so an AABB
+// near an edge or corner of the polytope may be reported as intersecting.
+// A relative tolerance keeps the AABBs touching the polytope.
+func (p Polytope) IntersectsAABB(query AABB) bool {
+	const epsilon = 1e-12
+
+	for _, halfSpace := range p.HalfSpaces {
+		radius := halfSpace.Normal.Abs().Dot(query.HalfSize)
+		tolerance := epsilon * (query.Center.Abs().Dot(halfSpace.Normal.Abs()) + radius + 1)
+
+		if halfSpace.SignedDistance(query.Center) > radius+tolerance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Implement the IntersectsTriangle interface. The triangle is clipped by
+// each half-space and intersects the polytope if any of it remains.
+func (p Polytope) IntersectsTriangle(query Triangle) bool {
+	polygon := []Vector{query.P, query.Q, query.R}
+
+	for _, halfSpace := range p.HalfSpaces {
+		polygon = halfSpace.clip(polygon)
+
+		if len(polygon) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Clip a convex polygon to the half-space (Sutherland-Hodgman). A polygon
+// touching the plane keeps the touching points.
+func (h HalfSpace) clip(polygon []Vector) []Vector {
+	clipped := make([]Vector, 0, len(polygon)+1)
+
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		dp := h.SignedDistance(p)
+		dq := h.SignedDistance(q)
+
+		if dp <= 0 {
+			clipped = append(clipped, p)
+		}
+
+		if (dp < 0 && dq > 0) || (dp > 0 && dq < 0) {
+			t := dp / (dp - dq)
+			clipped = append(clipped, p.Add(q.Sub(p).MulScalar(t)))
+		}
+	}
+
+	return clipped
+}
+
+// Construct the Polytope of the view frustum of a perspective camera at an
+// eye point looking at a target with a vertical field of view (radians) and
+// an aspect ratio (width over height). The frustum is bounded by the side
+// planes and the plane of the eye, and is unbounded in the view direction.
+func NewFrustum(eye, target, up Vector, fovY, aspect float64) Polytope {
+	forward := target.Sub(eye).Unit()
+	right := forward.Cross(up).Unit()
+	up = right.Cross(forward)
+
+	h := math.Tan(fovY / 2)
+	w := aspect * h
+
+	return NewPolytope(
+		NewHalfSpace(eye, forward.MulScalar(-1)),
+		NewHalfSpace(eye, right.Sub(forward.MulScalar(w))),
+		NewHalfSpace(eye, right.MulScalar(-1).Sub(forward.MulScalar(w))),
+		NewHalfSpace(eye, up.Sub(forward.MulScalar(h))),
+		NewHalfSpace(eye, up.MulScalar(-1).Sub(forward.MulScalar(h))),
+	)
+}
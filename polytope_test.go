@@ -0,0 +1,57 @@
+package meshx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the points contained by the polytope of an AABB.
+func TestPolytopeContainsPoint(t *testing.T) {
+	polytope := NewPolytopeFromAABB(NewAABBFromBounds(NewVector(0, 0, 0), NewVector(1, 2, 3)))
+
+	assert.Len(t, polytope.HalfSpaces, 6)
+	assert.True(t, polytope.ContainsPoint(NewVector(0.5, 1, 1.5)))
+	assert.True(t, polytope.ContainsPoint(NewVector(1, 2, 3)))
+	assert.False(t, polytope.ContainsPoint(NewVector(0.5, 2.1, 1.5)))
+	assert.False(t, polytope.ContainsPoint(NewVector(-0.1, 1, 1.5)))
+}
+
+// Test the intersection of a polytope with triangles.
+func TestPolytopeIntersectsTriangle(t *testing.T) {
+	polytope := NewPolytope(
+		NewHalfSpace(NewVector(1, 0, 0), NewVector(1, 0, 0)),
+		NewHalfSpace(NewVector(0, 1, 0), NewVector(0, 1, 0)),
+		NewHalfSpace(NewVector(0, 0, 0), NewVector(-1, -1, 0)),
+	)
+
+	// Inside, crossing a plane, touching a vertex, and outside.
+	assert.True(t, polytope.IntersectsTriangle(NewTriangle(NewVector(0.5, 0.5, 0), NewVector(0.8, 0.5, 1), NewVector(0.5, 0.8, -1))))
+	assert.True(t, polytope.IntersectsTriangle(NewTriangle(NewVector(0.5, 0.5, 0), NewVector(2, 0.5, 0), NewVector(2, 0.8, 0))))
+	assert.True(t, polytope.IntersectsTriangle(NewTriangle(NewVector(1, 1, 0), NewVector(2, 1, 0), NewVector(2, 2, 0))))
+	assert.False(t, polytope.IntersectsTriangle(NewTriangle(NewVector(1.5, 1.5, 0), NewVector(2, 1, 0), NewVector(1, 2, 0.5))))
+
+	// The triangle crosses each plane but not the polytope.
+	assert.False(t, polytope.IntersectsTriangle(NewTriangle(NewVector(-1, 0.5, 0), NewVector(0.5, -1, 0), NewVector(-1, -1, 0))))
+}
+
+// Test the conservative intersection of a polytope with AABBs.
+func TestPolytopeIntersectsAABB(t *testing.T) {
+	polytope := NewPolytope(NewHalfSpace(NewVector(1, 1, 0), NewVector(1, 1, 0)))
+
+	assert.True(t, polytope.IntersectsAABB(NewAABB(NewVector(0, 0, 0), NewVector(0.5, 0.5, 0.5))))
+	assert.True(t, polytope.IntersectsAABB(NewAABB(NewVector(1.5, 1.5, 0), NewVector(0.5, 0.5, 0.5))))
+	assert.False(t, polytope.IntersectsAABB(NewAABB(NewVector(2, 2, 0), NewVector(0.5, 0.5, 0.5))))
+}
+
+// Test the points contained by the frustum of a camera.
+func TestNewFrustum(t *testing.T) {
+	frustum := NewFrustum(NewVector(0, 0, 0), NewVector(0, 0, -1), NewVector(0, 1, 0), math.Pi/2, 2)
+
+	assert.True(t, frustum.ContainsPoint(NewVector(0, 0, -10)))
+	assert.True(t, frustum.ContainsPoint(NewVector(1.9, 0.9, -1)))
+	assert.False(t, frustum.ContainsPoint(NewVector(2.1, 0, -1)))
+	assert.False(t, frustum.ContainsPoint(NewVector(0, 1.1, -1)))
+	assert.False(t, frustum.ContainsPoint(NewVector(0, 0, 1)))
+}
@@ -0,0 +1,258 @@
+package meshx
+
+import (
+	"math"
+	"math/big"
+)
+
+// Geometric predicates with adaptive precision. Each predicate is first
+// evaluated in floating point and, if the result is within the (Shewchuk)
+// error bound of zero, evaluated again in exact arithmetic. The sign of the
+// result is therefore always correct.
+
+const (
+	epsilon        = 1.0 / (1 << 53)
+	orient2dBound  = (3 + 16*epsilon) * epsilon
+	orient3dBound  = (7 + 56*epsilon) * epsilon
+	inCircleBound  = (10 + 96*epsilon) * epsilon
+	inSphereBound  = (16 + 224*epsilon) * epsilon
+	exactPrecision = 1024
+)
+
+// Compute the orientation of the points in the XY-plane. The result is
+// positive if a, b, and c are in counterclockwise order, negative if in
+// clockwise order, and zero if collinear.
+func Orient2D(a, b, c Vector) float64 {
+	left := (a[0] - c[0]) * (b[1] - c[1])
+	right := (a[1] - c[1]) * (b[0] - c[0])
+	det := left - right
+
+	if math.Abs(det) > orient2dBound*(math.Abs(left)+math.Abs(right)) {
+		return det
+	}
+
+	return orient2dExact(a, b, c)
+}
+
+// Compute the orientation of a point relative to the plane through a, b,
+// and c. The result is positive if d lies below the plane (where a, b, and c
+// appear counterclockwise when viewed from above), negative if above, and
+// zero if coplanar.
+func Orient3D(a, b, c, d Vector) float64 {
+	ad := a.Sub(d)
+	bd := b.Sub(d)
+	cd := c.Sub(d)
+
+	bdxcdy := bd[0] * cd[1]
+	cdxbdy := cd[0] * bd[1]
+	cdxady := cd[0] * ad[1]
+	adxcdy := ad[0] * cd[1]
+	adxbdy := ad[0] * bd[1]
+	bdxady := bd[0] * ad[1]
+
+	det := ad[2]*(bdxcdy-cdxbdy) + bd[2]*(cdxady-adxcdy) + cd[2]*(adxbdy-bdxady)
+
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*math.Abs(ad[2]) +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*math.Abs(bd[2]) +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*math.Abs(cd[2])
+
+	if math.Abs(det) > orient3dBound*permanent {
+		return det
+	}
+
+	return orient3dExact(a, b, c, d)
+}
+
+// Compute the position of d relative to the circle through a, b, and c in
+// the XY-plane. The result is positive if d is inside the circle, negative
+// if outside, and zero if cocircular. The points a, b, and c must be in
+// counterclockwise order (otherwise the sign is reversed).
+func InCircle(a, b, c, d Vector) float64 {
+	adx, ady := a[0]-d[0], a[1]-d[1]
+	bdx, bdy := b[0]-d[0], b[1]-d[1]
+	cdx, cdy := c[0]-d[0], c[1]-d[1]
+
+	alift := adx*adx + ady*ady
+	blift := bdx*bdx + bdy*bdy
+	clift := cdx*cdx + cdy*cdy
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+
+	det := alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*clift
+
+	if math.Abs(det) > inCircleBound*permanent {
+		return det
+	}
+
+	return inCircleExact(a, b, c, d)
+}
+
+// Compute the position of e relative to the sphere through a, b, c, and d.
+// The result is positive if e is inside the sphere, negative if outside, and
+// zero if cospherical. The points a, b, c, and d must be positively oriented
+// (Orient3D > 0, otherwise the sign is reversed).
+func InSphere(a, b, c, d, e Vector) float64 {
+	ae := a.Sub(e)
+	be := b.Sub(e)
+	ce := c.Sub(e)
+	de := d.Sub(e)
+
+	ab := ae[0]*be[1] - be[0]*ae[1]
+	bc := be[0]*ce[1] - ce[0]*be[1]
+	cd := ce[0]*de[1] - de[0]*ce[1]
+	da := de[0]*ae[1] - ae[0]*de[1]
+	ac := ae[0]*ce[1] - ce[0]*ae[1]
+	bd := be[0]*de[1] - de[0]*be[1]
+
+	abc := ae[2]*bc - be[2]*ac + ce[2]*ab
+	bcd := be[2]*cd - ce[2]*bd + de[2]*bc
+	cda := ce[2]*da + de[2]*ac + ae[2]*cd
+	dab := de[2]*ab + ae[2]*bd + be[2]*da
+
+	alift := ae.Dot(ae)
+	blift := be.Dot(be)
+	clift := ce.Dot(ce)
+	dlift := de.Dot(de)
+
+	det := (dlift*abc - clift*dab) + (blift*cda - alift*bcd)
+
+	aexbey, bexaey := math.Abs(ae[0]*be[1]), math.Abs(be[0]*ae[1])
+	bexcey, cexbey := math.Abs(be[0]*ce[1]), math.Abs(ce[0]*be[1])
+	cexdey, dexcey := math.Abs(ce[0]*de[1]), math.Abs(de[0]*ce[1])
+	dexaey, aexdey := math.Abs(de[0]*ae[1]), math.Abs(ae[0]*de[1])
+	aexcey, cexaey := math.Abs(ae[0]*ce[1]), math.Abs(ce[0]*ae[1])
+	bexdey, dexbey := math.Abs(be[0]*de[1]), math.Abs(de[0]*be[1])
+
+	aez := math.Abs(ae[2])
+	bez := math.Abs(be[2])
+	cez := math.Abs(ce[2])
+	dez := math.Abs(de[2])
+
+	permanent := ((cexdey+dexcey)*bez+(dexbey+bexdey)*cez+(bexcey+cexbey)*dez)*alift +
+		((dexaey+aexdey)*cez+(aexcey+cexaey)*dez+(cexdey+dexcey)*aez)*blift +
+		((aexbey+bexaey)*dez+(bexdey+dexbey)*aez+(dexaey+aexdey)*bez)*clift +
+		((bexcey+cexbey)*aez+(cexaey+aexcey)*bez+(aexbey+bexaey)*cez)*dlift
+
+	if math.Abs(det) > inSphereBound*permanent {
+		return det
+	}
+
+	return inSphereExact(a, b, c, d, e)
+}
+
+// Construct an exact (arbitrary precision) number from a float.
+func newExact(value float64) *big.Float {
+	return new(big.Float).SetPrec(exactPrecision).SetFloat64(value)
+}
+
+// Compute the exact difference of the components of two vectors.
+func exactSub(a, b Vector) [3]*big.Float {
+	var r [3]*big.Float
+
+	for i := 0; i < 3; i++ {
+		r[i] = newExact(a[i])
+		r[i].Sub(r[i], newExact(b[i]))
+	}
+
+	return r
+}
+
+// Compute the exact determinant a * d - b * c.
+func exactDet2(a, b, c, d *big.Float) *big.Float {
+	left := newExact(0).Mul(a, d)
+	right := newExact(0).Mul(b, c)
+	return left.Sub(left, right)
+}
+
+// Compute the exact determinant of a 3x3 matrix by rows.
+func exactDet3(m [3][3]*big.Float) *big.Float {
+	det := newExact(0)
+
+	for j := 0; j < 3; j++ {
+		minor := exactDet2(m[1][(j+1)%3], m[1][(j+2)%3], m[2][(j+1)%3], m[2][(j+2)%3])
+		det.Add(det, minor.Mul(minor, m[0][j]))
+	}
+
+	return det
+}
+
+// Evaluate the orientation in the XY-plane in exact arithmetic.
+func orient2dExact(a, b, c Vector) float64 {
+	ac := exactSub(a, c)
+	bc := exactSub(b, c)
+	det := exactDet2(ac[0], ac[1], bc[0], bc[1])
+	value, _ := det.Float64()
+	return value
+}
+
+// Evaluate the orientation in three dimensions in exact arithmetic.
+func orient3dExact(a, b, c, d Vector) float64 {
+	ad := exactSub(a, d)
+	bd := exactSub(b, d)
+	cd := exactSub(c, d)
+	det := exactDet3([3][3]*big.Float{ad, bd, cd})
+	value, _ := det.Float64()
+	return value
+}
+
+// Evaluate the in circle predicate in exact arithmetic.
+func inCircleExact(a, b, c, d Vector) float64 {
+	var m [3][3]*big.Float
+
+	for i, p := range []Vector{a, b, c} {
+		r := exactSub(p, d)
+		lift := newExact(0).Mul(r[0], r[0])
+		lift.Add(lift, newExact(0).Mul(r[1], r[1]))
+		m[i] = [3]*big.Float{r[0], r[1], lift}
+	}
+
+	value, _ := exactDet3(m).Float64()
+	return value
+}
+
+// Evaluate the in sphere predicate in exact arithmetic by cofactor
+// expansion along the lifted column.
+func inSphereExact(a, b, c, d, e Vector) float64 {
+	var rows [4][3]*big.Float
+	var lifts [4]*big.Float
+
+	for i, p := range []Vector{a, b, c, d} {
+		rows[i] = exactSub(p, e)
+		lifts[i] = newExact(0)
+
+		for j := 0; j < 3; j++ {
+			lifts[i].Add(lifts[i], newExact(0).Mul(rows[i][j], rows[i][j]))
+		}
+	}
+
+	det := newExact(0)
+
+	for i := 0; i < 4; i++ {
+		var minor [3][3]*big.Float
+
+		for r, k := 0, 0; k < 4; k++ {
+			if k != i {
+				minor[r] = rows[k]
+				r++
+			}
+		}
+
+		term := exactDet3(minor)
+		term.Mul(term, lifts[i])
+
+		if i%2 == 0 {
+			det.Sub(det, term)
+		} else {
+			det.Add(det, term)
+		}
+	}
+
+	value, _ := det.Float64()
+	return value
+}
@@ -0,0 +1,82 @@
+package meshx
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate a random vector in the unit cube.
+func newPredicatesTestVector(random *rand.Rand) Vector {
+	return NewVector(random.Float64(), random.Float64(), random.Float64())
+}
+
+// Test the predicates agree in sign with their exact evaluation.
+func TestPredicatesExactSign(t *testing.T) {
+	random := rand.New(rand.NewSource(0))
+
+	for i := 0; i < 100; i++ {
+		a := newPredicatesTestVector(random)
+		b := newPredicatesTestVector(random)
+		c := newPredicatesTestVector(random)
+		d := newPredicatesTestVector(random)
+		e := newPredicatesTestVector(random)
+
+		assert.Equal(t, math.Signbit(Orient2D(a, b, c)), math.Signbit(orient2dExact(a, b, c)))
+		assert.Equal(t, math.Signbit(Orient3D(a, b, c, d)), math.Signbit(orient3dExact(a, b, c, d)))
+		assert.Equal(t, math.Signbit(InCircle(a, b, c, d)), math.Signbit(inCircleExact(a, b, c, d)))
+		assert.Equal(t, math.Signbit(InSphere(a, b, c, d, e)), math.Signbit(inSphereExact(a, b, c, d, e)))
+	}
+}
+
+// Test the orientation conventions.
+func TestOrient3D(t *testing.T) {
+	a := NewVector(0, 0, 0)
+	b := NewVector(1, 0, 0)
+	c := NewVector(0, 1, 0)
+
+	assert.Greater(t, Orient3D(a, b, c, NewVector(0, 0, -1)), 0.0)
+	assert.Less(t, Orient3D(a, b, c, NewVector(0, 0, 1)), 0.0)
+	assert.Equal(t, 0.0, Orient3D(a, b, c, NewVector(0.3, 0.7, 0)))
+	assert.Greater(t, Orient2D(a, b, c), 0.0)
+}
+
+// Test the orientation of nearly coplanar points where the floating point
+// evaluation is unreliable.
+func TestOrient3DNearlyCoplanar(t *testing.T) {
+	a := NewVector(0.1, 0.1, 0.1)
+	b := NewVector(0.7, 0.2, 0.3)
+	c := NewVector(0.3, 0.9, 0.5)
+
+	for i := 0; i < 100; i++ {
+		s := float64(i) / 100
+		t1 := float64(100-i) / 300
+		d := a.Add(b.Sub(a).MulScalar(s)).Add(c.Sub(a).MulScalar(t1))
+
+		assert.Equal(t, math.Signbit(orient3dExact(a, b, c, d)), math.Signbit(Orient3D(a, b, c, d)))
+		assert.Equal(t, orient3dExact(a, b, c, d) == 0, Orient3D(a, b, c, d) == 0)
+	}
+}
+
+// Test the in circle and in sphere conventions including cocircular and
+// cospherical points.
+func TestInSphere(t *testing.T) {
+	a := NewVector(1, 0, 0)
+	b := NewVector(0, 1, 0)
+	c := NewVector(-1, 0, 0)
+
+	assert.Greater(t, InCircle(a, b, c, NewVector(0, 0, 0)), 0.0)
+	assert.Less(t, InCircle(a, b, c, NewVector(2, 0, 0)), 0.0)
+	assert.Equal(t, 0.0, InCircle(a, b, c, NewVector(0, -1, 0)))
+
+	d := NewVector(0, 0, 1)
+	if Orient3D(a, b, c, d) < 0 {
+		a, b = b, a
+	}
+
+	assert.Greater(t, InSphere(a, b, c, d, NewVector(0, 0, 0)), 0.0)
+	assert.Less(t, InSphere(a, b, c, d, NewVector(0, 0, 2)), 0.0)
+	assert.Equal(t, 0.0, InSphere(a, b, c, d, NewVector(0, 0, -1)))
+}
@@ -0,0 +1,35 @@
+package meshx
+
+import (
+	"io"
+)
+
+const (
+	// Number of elements processed by a long-running operation between
+	// checks for cancellation and progress reports.
+	ProgressInterval = 1 << 12
+)
+
+// Callback reporting the progress of a long-running operation as the amount
+// of work completed out of the total. The total is -1 if unknown.
+type ProgressFunc func(completed, total int64)
+
+// Report the progress if the callback is not nil.
+func (f ProgressFunc) Report(completed, total int64) {
+	if f != nil {
+		f(completed, total)
+	}
+}
+
+// Reader counting the number of bytes read.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+// Implement the io.Reader interface.
+func (r *countingReader) Read(data []byte) (int, error) {
+	n, err := r.reader.Read(data)
+	r.count += int64(n)
+	return n, err
+}
@@ -1,5 +1,7 @@
 package meshx
 
+import "math"
+
 // Ray in three-dimensional Cartesian space.
 type Ray struct {
 	Origin    Vector
@@ -11,30 +13,41 @@ func NewRay(origin, direction Vector) Ray {
 	return Ray{origin, direction}
 }
 
-// Implement the IntersectsAABB interface.
+// Implement the IntersectsAABB interface. The AABB is closed, so rays
+// grazing a face or edge intersect. Direction components equal to zero are
+// handled explicitly rather than relying on infinite slab distances.
 func (r Ray) IntersectsAABB(query AABB) bool {
-	var t1, t2 float64
-
 	minBound := query.GetMinBound()
 	maxBound := query.GetMaxBound()
-	inv := r.Direction.Inv()
 
-	t1 = (minBound[0] - r.Origin[0]) * inv[0]
-	t2 = (maxBound[0] - r.Origin[0]) * inv[0]
-	tmin := min(t1, t2)
-	tmax := max(t1, t2)
+	tmin := 0.0
+	tmax := math.Inf(1)
+
+	for i := 0; i < 3; i++ {
+		if r.Direction[i] == 0 {
+			if r.Origin[i] < minBound[i] || r.Origin[i] > maxBound[i] {
+				return false
+			}
+			continue
+		}
 
-	t1 = (minBound[1] - r.Origin[1]) * inv[1]
-	t2 = (maxBound[1] - r.Origin[1]) * inv[1]
-	tmin = max(tmin, min(t1, t2))
-	tmax = min(tmax, max(t1, t2))
+		inv := 1 / r.Direction[i]
+		t1 := (minBound[i] - r.Origin[i]) * inv
+		t2 := (maxBound[i] - r.Origin[i]) * inv
 
-	t1 = (minBound[2] - r.Origin[2]) * inv[2]
-	t2 = (maxBound[2] - r.Origin[2]) * inv[2]
-	tmin = max(tmin, min(t1, t2))
-	tmax = min(tmax, max(t1, t2))
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+
+		tmin = max(tmin, t1)
+		tmax = min(tmax, t2)
+
+		if tmin > tmax {
+			return false
+		}
+	}
 
-	return tmax >= max(0, tmin)
+	return true
 }
 
 // Implement the IntersectsTriangle interface.
@@ -68,3 +81,176 @@ func (r Ray) IntersectsTriangle(query Triangle) bool {
 
 	return invDet*e2.Dot(q) > epsilon
 }
+
+// Ray with its inverse direction and direction signs precomputed for
+// repeated AABB tests, such as during tree traversal.
+type PrecomputedRay struct {
+	Ray
+	InvDirection Vector
+	Sign         [3]int
+}
+
+// Precompute the inverse direction and direction signs of the ray.
+func (r Ray) Precompute() PrecomputedRay {
+	p := PrecomputedRay{Ray: r, InvDirection: r.Direction.Inv()}
+
+	for i := 0; i < 3; i++ {
+		if r.Direction[i] < 0 {
+			p.Sign[i] = 1
+		}
+	}
+
+	return p
+}
+
+// Implement the IntersectsAABB interface. The result is identical to that of
+// the ray but avoids the divisions.
+func (r PrecomputedRay) IntersectsAABB(query AABB) bool {
+	bounds := [2]Vector{query.GetMinBound(), query.GetMaxBound()}
+
+	tmin := 0.0
+	tmax := math.Inf(1)
+
+	for i := 0; i < 3; i++ {
+		if r.Direction[i] == 0 {
+			if r.Origin[i] < bounds[0][i] || r.Origin[i] > bounds[1][i] {
+				return false
+			}
+			continue
+		}
+
+		t1 := (bounds[r.Sign[i]][i] - r.Origin[i]) * r.InvDirection[i]
+		t2 := (bounds[1-r.Sign[i]][i] - r.Origin[i]) * r.InvDirection[i]
+
+		tmin = max(tmin, t1)
+		tmax = min(tmax, t2)
+
+		if tmin > tmax {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Check for an intersection with a triangle regardless of its orientation.
+func (r Ray) IntersectsTriangleTwoSided(query Triangle) bool {
+	const epsilon float64 = 1e-8
+
+	e1 := query.Q.Sub(query.P)
+	e2 := query.R.Sub(query.P)
+
+	p := r.Direction.Cross(e2)
+	det := e1.Dot(p)
+
+	if math.Abs(det) < epsilon {
+		return false
+	}
+
+	invDet := 1.0 / det
+	s := r.Origin.Sub(query.P)
+	u := invDet * s.Dot(p)
+
+	if u < 0.0 || u > 1.0 {
+		return false
+	}
+
+	q := s.Cross(e1)
+	v := invDet * r.Direction.Dot(q)
+
+	if v < 0.0 || u+v > 1.0 {
+		return false
+	}
+
+	return invDet*e2.Dot(q) > epsilon
+}
+
+// Check for an intersection with a triangle regardless of its orientation
+// using the watertight algorithm of Woop et al. The edge functions are
+// evaluated with exact predicates and ties are broken by a consistent
+// symbolic perturbation of the ray, so a ray passing through an edge or
+// vertex shared by several triangles hits exactly one of them when it
+// crosses the surface.
+func (r Ray) IntersectsTriangleWatertight(query Triangle) bool {
+	_, ok := r.IntersectTriangleWatertight(query)
+	return ok
+}
+
+// Compute the distance along the ray to its watertight intersection with a
+// triangle. The distance is in units of the ray direction.
+func (r Ray) IntersectTriangleWatertight(query Triangle) (float64, bool) {
+	kz := 0
+
+	for i := 1; i < 3; i++ {
+		if math.Abs(r.Direction[i]) > math.Abs(r.Direction[kz]) {
+			kz = i
+		}
+	}
+
+	if r.Direction[kz] == 0 {
+		return 0, false
+	}
+
+	kx := (kz + 1) % 3
+	ky := (kx + 1) % 3
+
+	if r.Direction[kz] < 0 {
+		kx, ky = ky, kx
+	}
+
+	sx := r.Direction[kx] / r.Direction[kz]
+	sy := r.Direction[ky] / r.Direction[kz]
+	sz := 1 / r.Direction[kz]
+
+	transform := func(v Vector) Vector {
+		d := v.Sub(r.Origin)
+		return NewVector(d[kx]-sx*d[kz], d[ky]-sy*d[kz], sz*d[kz])
+	}
+
+	a := transform(query.P)
+	b := transform(query.Q)
+	c := transform(query.R)
+
+	u := watertightEdge(b, c)
+	v := watertightEdge(c, a)
+	w := watertightEdge(a, b)
+
+	if u == 0 || v == 0 || w == 0 {
+		return 0, false
+	}
+
+	if (u < 0 || v < 0 || w < 0) && (u > 0 || v > 0 || w > 0) {
+		return 0, false
+	}
+
+	eu := b[0]*c[1] - b[1]*c[0]
+	ev := c[0]*a[1] - c[1]*a[0]
+	ew := a[0]*b[1] - a[1]*b[0]
+	det := eu + ev + ew
+
+	if det == 0 {
+		return 0, false
+	}
+
+	t := (eu*a[2] + ev*b[2] + ew*c[2]) / det
+
+	return t, t > 0
+}
+
+// Compute the sign of the edge function of the projected edge (a, b) at the
+// projected ray origin. A zero value is resolved by perturbing the origin by
+// (ε, ε²), which is consistent across all triangles sharing the edge.
+func watertightEdge(a, b Vector) float64 {
+	origin := Vector{}
+	a[2], b[2] = 0, 0
+
+	if value := Orient2D(a, b, origin); value != 0 {
+		return value
+	}
+
+	if a[1] != b[1] {
+		return a[1] - b[1]
+	}
+
+	return b[0] - a[0]
+}
@@ -1,6 +1,7 @@
 package meshx
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -37,7 +38,7 @@ func TestRayIntersectsAABBOriginOutside(t *testing.T) {
 }
 
 // Test a ray/AABB intersection with the ray along the X-edge of the
-// AABB. This is an edge case that currently returns no hit.
+// AABB. Grazing rays intersect the closed AABB.
 func TestRayIntersectsAABBAlongX(t *testing.T) {
 	aabb := AABB{
 		Center:   NewVector(0.5, 0.5, 0.5),
@@ -49,11 +50,11 @@ func TestRayIntersectsAABBAlongX(t *testing.T) {
 		Direction: NewVector(1, 0, 0),
 	}
 
-	assert.False(t, ray.IntersectsAABB(aabb))
+	assert.True(t, ray.IntersectsAABB(aabb))
 }
 
 // Test a ray/AABB intersection with the ray along the Y-edge of the
-// AABB. This is an edge case that currently returns no hit.
+// AABB. Grazing rays intersect the closed AABB.
 func TestRayIntersectsAABBAlongY(t *testing.T) {
 	aabb := AABB{
 		Center:   NewVector(0.5, 0.5, 0.5),
@@ -65,11 +66,11 @@ func TestRayIntersectsAABBAlongY(t *testing.T) {
 		Direction: NewVector(0, 1, 0),
 	}
 
-	assert.False(t, ray.IntersectsAABB(aabb))
+	assert.True(t, ray.IntersectsAABB(aabb))
 }
 
 // Test a ray/AABB intersection with the ray along the Z-edge of the
-// AABB. This is an edge case that currently returns no hit.
+// AABB. Grazing rays intersect the closed AABB.
 func TestRayIntersectsAABBAlongZ(t *testing.T) {
 	aabb := AABB{
 		Center:   NewVector(0.5, 0.5, 0.5),
@@ -81,7 +82,7 @@ func TestRayIntersectsAABBAlongZ(t *testing.T) {
 		Direction: NewVector(0, 0, 1),
 	}
 
-	assert.False(t, ray.IntersectsAABB(aabb))
+	assert.True(t, ray.IntersectsAABB(aabb))
 }
 
 // Test a ray/AABB intersection miss reverse direction.
@@ -113,3 +114,173 @@ func TestRayIntersectsAABBMissBeside(t *testing.T) {
 
 	assert.False(t, ray.IntersectsAABB(aabb))
 }
+
+// Test a ray/AABB intersection with the ray grazing a face of the AABB.
+func TestRayIntersectsAABBAlongFace(t *testing.T) {
+	aabb := NewAABB(NewVector(0.5, 0.5, 0.5), NewVector(0.5, 0.5, 0.5))
+
+	assert.True(t, NewRay(NewVector(-1, 0.5, 1), NewVector(1, 0, 0)).IntersectsAABB(aabb))
+	assert.True(t, NewRay(NewVector(-1, -0.5, 1), NewVector(1, 1, 0)).IntersectsAABB(aabb))
+	assert.False(t, NewRay(NewVector(-1, -0.5, 1.5), NewVector(1, 1, 0)).IntersectsAABB(aabb))
+}
+
+// Test a ray/AABB intersection with the ray touching a corner of the AABB.
+func TestRayIntersectsAABBCorner(t *testing.T) {
+	aabb := NewAABB(NewVector(0.5, 0.5, 0.5), NewVector(0.5, 0.5, 0.5))
+
+	assert.True(t, NewRay(NewVector(-1, -1, 0), NewVector(1, 1, 0)).IntersectsAABB(aabb))
+	assert.True(t, NewRay(NewVector(2, 0, 0), NewVector(-1, 1, 0)).IntersectsAABB(aabb))
+	assert.False(t, NewRay(NewVector(2, 0, 0), NewVector(1, 1, 0)).IntersectsAABB(aabb))
+}
+
+// Test a ray/AABB intersection with a zero direction.
+func TestRayIntersectsAABBZeroDirection(t *testing.T) {
+	aabb := NewAABB(NewVector(0.5, 0.5, 0.5), NewVector(0.5, 0.5, 0.5))
+
+	assert.True(t, NewRay(NewVector(0.5, 0.5, 0.5), NewVector(0, 0, 0)).IntersectsAABB(aabb))
+	assert.False(t, NewRay(NewVector(2, 0.5, 0.5), NewVector(0, 0, 0)).IntersectsAABB(aabb))
+}
+
+// Test a two-sided ray/triangle intersection with a back-facing triangle.
+func TestRayIntersectsTriangleTwoSided(t *testing.T) {
+	ray := NewRay(NewVector(0.5, 0.5, 0), NewVector(0, 0, 1))
+	front := NewTriangle(NewVector(0, 0, 2), NewVector(0, 1, 2), NewVector(1, 1, 2))
+	back := NewTriangle(NewVector(0, 0, 2), NewVector(1, 0, 2), NewVector(1, 1, 2))
+	behind := NewTriangle(NewVector(0, 0, -2), NewVector(1, 0, -2), NewVector(1, 1, -2))
+
+	assert.True(t, ray.IntersectsTriangleTwoSided(front))
+	assert.True(t, ray.IntersectsTriangleTwoSided(back))
+	assert.False(t, ray.IntersectsTriangleTwoSided(behind))
+}
+
+// Test a watertight ray/triangle intersection through the shared edge of
+// two triangles.
+func TestRayIntersectsTriangleWatertightEdge(t *testing.T) {
+	a := NewTriangle(NewVector(0, 0, 2), NewVector(1, 0, 2), NewVector(1, 1, 2))
+	b := NewTriangle(NewVector(0, 0, 2), NewVector(1, 1, 2), NewVector(0, 1, 2))
+
+	for _, direction := range []Vector{NewVector(0, 0, 1), NewVector(0, 0.25, 1), NewVector(0.1, -0.3, 1)} {
+		origin := NewVector(0.5, 0.5, 2).Sub(direction.MulScalar(2))
+		ray := NewRay(origin, direction)
+
+		var hits int
+
+		for _, triangle := range []Triangle{a, b} {
+			if ray.IntersectsTriangleWatertight(triangle) {
+				hits++
+			}
+		}
+
+		assert.Equal(t, 1, hits)
+	}
+}
+
+// Test a watertight ray/triangle intersection through a vertex shared by a
+// fan of triangles.
+func TestRayIntersectsTriangleWatertightVertex(t *testing.T) {
+	center := NewVector(0, 0, 1)
+	corners := []Vector{
+		NewVector(1, 0, 1),
+		NewVector(0, 1, 1),
+		NewVector(-1, 0, 1),
+		NewVector(0, -1, 1),
+	}
+
+	ray := NewRay(NewVector(0, 0, 0), NewVector(0, 0, 1))
+
+	var hits int
+
+	for i := range corners {
+		triangle := NewTriangle(center, corners[i], corners[(i+1)%len(corners)])
+
+		if ray.IntersectsTriangleWatertight(triangle) {
+			hits++
+		}
+	}
+
+	assert.Equal(t, 1, hits)
+}
+
+// Test a watertight ray/triangle intersection miss.
+func TestRayIntersectsTriangleWatertightMiss(t *testing.T) {
+	triangle := NewTriangle(NewVector(0, 0, 2), NewVector(1, 0, 2), NewVector(1, 1, 2))
+
+	assert.False(t, NewRay(NewVector(2, 2, 0), NewVector(0, 0, 1)).IntersectsTriangleWatertight(triangle))
+	assert.False(t, NewRay(NewVector(0.75, 0.25, 0), NewVector(0, 0, -1)).IntersectsTriangleWatertight(triangle))
+	assert.True(t, NewRay(NewVector(0.75, 0.25, 0), NewVector(0, 0, 1)).IntersectsTriangleWatertight(triangle))
+	assert.True(t, NewRay(NewVector(0.75, 0.25, 4), NewVector(0, 0, -1)).IntersectsTriangleWatertight(triangle))
+}
+
+// Test that a precomputed ray matches the ray for AABB intersections.
+func TestPrecomputedRayIntersectsAABB(t *testing.T) {
+	aabb := NewAABB(NewVector(0.5, 0.5, 0.5), NewVector(0.5, 0.5, 0.5))
+	rng := rand.New(rand.NewSource(0))
+
+	for i := 0; i < 1000; i++ {
+		origin := NewVector(rng.Float64()*4-2, rng.Float64()*4-2, rng.Float64()*4-2)
+		direction := NewVector(rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64())
+
+		if i%2 == 0 {
+			direction[i%3] = 0
+		}
+
+		ray := NewRay(origin, direction)
+		assert.Equal(t, ray.IntersectsAABB(aabb), ray.Precompute().IntersectsAABB(aabb))
+	}
+
+	ray := NewRay(NewVector(-1, 0, 0), NewVector(1, 0, 0)).Precompute()
+	assert.True(t, ray.IntersectsAABB(aabb))
+	assert.Equal(t, [3]int{0, 0, 0}, ray.Sign)
+
+	ray = NewRay(NewVector(2, 0.5, 0.5), NewVector(-1, 0, 0)).Precompute()
+	assert.True(t, ray.IntersectsAABB(aabb))
+	assert.Equal(t, [3]int{1, 0, 0}, ray.Sign)
+}
+
+// Generate a workload of random rays and AABBs for benchmarking.
+func newRayBenchmarkWorkload() ([]Ray, []AABB) {
+	rng := rand.New(rand.NewSource(0))
+	rays := make([]Ray, 1<<20)
+	aabbs := make([]AABB, 8)
+
+	for i := range rays {
+		origin := NewVector(rng.Float64()*4-2, rng.Float64()*4-2, rng.Float64()*4-2)
+		direction := NewVector(rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64())
+		rays[i] = NewRay(origin, direction)
+	}
+
+	for i := range aabbs {
+		center := NewVector(rng.Float64(), rng.Float64(), rng.Float64())
+		aabbs[i] = NewAABB(center, NewVector(0.25, 0.25, 0.25))
+	}
+
+	return rays, aabbs
+}
+
+// Benchmark the ray/AABB intersection over a million rays.
+func BenchmarkRayIntersectsAABB(b *testing.B) {
+	rays, aabbs := newRayBenchmarkWorkload()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ray := rays[i%len(rays)]
+
+		for _, aabb := range aabbs {
+			ray.IntersectsAABB(aabb)
+		}
+	}
+}
+
+// Benchmark the precomputed ray/AABB intersection over a million rays.
+func BenchmarkPrecomputedRayIntersectsAABB(b *testing.B) {
+	rays, aabbs := newRayBenchmarkWorkload()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ray := rays[i%len(rays)].Precompute()
+
+		for _, aabb := range aabbs {
+			ray.IntersectsAABB(aabb)
+		}
+	}
+}
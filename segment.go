@@ -0,0 +1,160 @@
+package meshx
+
+// Line segment in three-dimensional Cartesian space.
+type Segment struct {
+	P Vector
+	Q Vector
+}
+
+// Construct a Segment from its end points.
+func NewSegment(p, q Vector) Segment {
+	return Segment{p, q}
+}
+
+// Compute the length.
+func (s Segment) Length() float64 {
+	return s.Q.Sub(s.P).Mag()
+}
+
+// Compute the direction (not normalized).
+func (s Segment) Direction() Vector {
+	return s.Q.Sub(s.P)
+}
+
+// Compute the point at a parameter t in [0, 1].
+func (s Segment) PointAt(t float64) Vector {
+	return s.P.Add(s.Q.Sub(s.P).MulScalar(t))
+}
+
+// Compute the closest point on the segment to a point.
+func (s Segment) ClosestPoint(point Vector) Vector {
+	d := s.Q.Sub(s.P)
+	dd := d.Dot(d)
+
+	if dd == 0 {
+		return s.P
+	}
+
+	t := point.Sub(s.P).Dot(d) / dd
+	return s.PointAt(max(0, min(1, t)))
+}
+
+// Compute the distance to a point.
+func (s Segment) DistanceTo(point Vector) float64 {
+	return s.ClosestPoint(point).Sub(point).Mag()
+}
+
+// Compute the closest points between the segment and another segment
+// (Ericson). The point on the segment is returned first.
+func (s Segment) ClosestPoints(other Segment) (Vector, Vector) {
+	d1 := s.Direction()
+	d2 := other.Direction()
+	r := s.P.Sub(other.P)
+	a := d1.Dot(d1)
+	e := d2.Dot(d2)
+	f := d2.Dot(r)
+
+	if a == 0 && e == 0 {
+		return s.P, other.P
+	}
+
+	if a == 0 {
+		return s.P, other.ClosestPoint(s.P)
+	}
+
+	if e == 0 {
+		return s.ClosestPoint(other.P), other.P
+	}
+
+	var t1, t2 float64
+
+	b := d1.Dot(d2)
+	c := d1.Dot(r)
+
+	if denom := a*e - b*b; denom > 0 {
+		t1 = max(0, min(1, (b*f-c*e)/denom))
+	}
+
+	t2 = (b*t1 + f) / e
+
+	if t2 < 0 {
+		t1, t2 = max(0, min(1, -c/a)), 0
+	} else if t2 > 1 {
+		t1, t2 = max(0, min(1, (b-c)/a)), 1
+	}
+
+	return s.PointAt(t1), other.PointAt(t2)
+}
+
+// Implement the IntersectsAABB interface.
+func (s Segment) IntersectsAABB(query AABB) bool {
+	tmin := 0.0
+	tmax := 1.0
+
+	minBound := query.GetMinBound()
+	maxBound := query.GetMaxBound()
+	d := s.Q.Sub(s.P)
+
+	for i := 0; i < 3; i++ {
+		if d[i] == 0 {
+			if s.P[i] < minBound[i] || s.P[i] > maxBound[i] {
+				return false
+			}
+			continue
+		}
+
+		t1 := (minBound[i] - s.P[i]) / d[i]
+		t2 := (maxBound[i] - s.P[i]) / d[i]
+		tmin = max(tmin, min(t1, t2))
+		tmax = min(tmax, max(t1, t2))
+
+		if tmin > tmax {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Implement the IntersectsTriangle interface. The test uses exact
+// predicates and includes touching (degenerate) intersections.
+func (s Segment) IntersectsTriangle(query Triangle) bool {
+	crosses, degenerate := s.CrossesTriangle(query)
+	return crosses || degenerate
+}
+
+// Classify the crossing of the segment through a triangle using exact
+// predicates. The crossing is degenerate if the segment touches the triangle
+// only at an edge or vertex, an end point lies in the plane of the triangle,
+// or the segment is coplanar with the triangle. A degenerate intersection is
+// not a crossing.
+func (s Segment) CrossesTriangle(t Triangle) (crosses bool, degenerate bool) {
+	sp := Orient3D(t.P, t.Q, t.R, s.P)
+	sq := Orient3D(t.P, t.Q, t.R, s.Q)
+
+	if (sp > 0 && sq > 0) || (sp < 0 && sq < 0) {
+		return false, false
+	}
+
+	o1 := Orient3D(s.P, s.Q, t.P, t.Q)
+	o2 := Orient3D(s.P, s.Q, t.Q, t.R)
+	o3 := Orient3D(s.P, s.Q, t.R, t.P)
+
+	if (o1 > 0 || o2 > 0 || o3 > 0) && (o1 < 0 || o2 < 0 || o3 < 0) {
+		return false, false
+	}
+
+	if sp == 0 || sq == 0 || o1 == 0 || o2 == 0 || o3 == 0 {
+		return false, true
+	}
+
+	return true, false
+}
+
+// Implement the support function of a segment.
+func (s Segment) support(d Vector) Vector {
+	if s.Q.Sub(s.P).Dot(d) > 0 {
+		return s.Q
+	}
+	return s.P
+}
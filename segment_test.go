@@ -0,0 +1,92 @@
+package meshx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the closest point of a segment before, after, and along it.
+func TestSegmentClosestPoint(t *testing.T) {
+	segment := NewSegment(NewVector(0, 0, 0), NewVector(2, 0, 0))
+
+	cases := [][2]Vector{
+		{NewVector(-1, 1, 0), NewVector(0, 0, 0)},
+		{NewVector(3, 0, 1), NewVector(2, 0, 0)},
+		{NewVector(1, 1, 1), NewVector(1, 0, 0)},
+		{NewVector(0.5, 0, 0), NewVector(0.5, 0, 0)},
+		{NewVector(2, 0, 0), NewVector(2, 0, 0)},
+	}
+
+	for _, c := range cases {
+		closest := segment.ClosestPoint(c[0])
+		assert.Equal(t, c[1], closest)
+		assert.InDelta(t, c[0].Sub(c[1]).Mag(), segment.DistanceTo(c[0]), 1e-12)
+	}
+}
+
+// Test the closest point of a degenerate (zero length) segment.
+func TestSegmentClosestPointDegenerate(t *testing.T) {
+	segment := NewSegment(NewVector(1, 1, 1), NewVector(1, 1, 1))
+
+	assert.Equal(t, NewVector(1, 1, 1), segment.ClosestPoint(NewVector(2, 1, 1)))
+	assert.Equal(t, 1.0, segment.DistanceTo(NewVector(2, 1, 1)))
+}
+
+// Test segment/triangle crossings including degenerate cases.
+func TestSegmentCrossesTriangle(t *testing.T) {
+	triangle := NewTriangle(NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(0, 1, 0))
+
+	cases := []struct {
+		segment    Segment
+		crosses    bool
+		degenerate bool
+	}{
+		{NewSegment(NewVector(0.2, 0.2, -1), NewVector(0.2, 0.2, 1)), true, false},
+		{NewSegment(NewVector(0.2, 0.2, 1), NewVector(0.2, 0.2, -1)), true, false},
+		{NewSegment(NewVector(0.8, 0.8, -1), NewVector(0.8, 0.8, 1)), false, false},
+		{NewSegment(NewVector(0.2, 0.2, 1), NewVector(0.2, 0.2, 2)), false, false},
+		{NewSegment(NewVector(0.5, 0.5, -1), NewVector(0.5, 0.5, 1)), false, true},
+		{NewSegment(NewVector(0, 0, -1), NewVector(0, 0, 1)), false, true},
+		{NewSegment(NewVector(0.2, 0.2, 0), NewVector(0.2, 0.2, 1)), false, true},
+		{NewSegment(NewVector(-1, 0.2, 0), NewVector(2, 0.2, 0)), false, true},
+	}
+
+	for _, c := range cases {
+		crosses, degenerate := c.segment.CrossesTriangle(triangle)
+		assert.Equal(t, c.crosses, crosses)
+		assert.Equal(t, c.degenerate, degenerate)
+		assert.Equal(t, c.crosses || c.degenerate, triangle.IntersectsSegment(c.segment))
+	}
+}
+
+// Test a segment/AABB intersection.
+func TestSegmentIntersectsAABB(t *testing.T) {
+	aabb := NewAABB(NewVector(0.5, 0.5, 0.5), NewVector(0.5, 0.5, 0.5))
+
+	assert.True(t, NewSegment(NewVector(-1, 0.5, 0.5), NewVector(0.5, 0.5, 0.5)).IntersectsAABB(aabb))
+	assert.True(t, NewSegment(NewVector(-1, 0, 0), NewVector(2, 0, 0)).IntersectsAABB(aabb))
+	assert.False(t, NewSegment(NewVector(-1, 0.5, 0.5), NewVector(-0.1, 0.5, 0.5)).IntersectsAABB(aabb))
+	assert.False(t, NewSegment(NewVector(-1, 2, 0.5), NewVector(2, 2, 0.5)).IntersectsAABB(aabb))
+}
+
+// Test the closest points between two segments.
+func TestSegmentClosestPoints(t *testing.T) {
+	segment := NewSegment(NewVector(0, 0, 0), NewVector(2, 0, 0))
+
+	cases := []struct {
+		other Segment
+		p, q  Vector
+	}{
+		{NewSegment(NewVector(1, -1, 1), NewVector(1, 1, 1)), NewVector(1, 0, 0), NewVector(1, 0, 1)},
+		{NewSegment(NewVector(3, 1, 0), NewVector(4, 2, 0)), NewVector(2, 0, 0), NewVector(3, 1, 0)},
+		{NewSegment(NewVector(0.5, 1, 0), NewVector(1.5, 1, 0)), NewVector(0.5, 0, 0), NewVector(0.5, 1, 0)},
+		{NewSegment(NewVector(-1, 2, 0), NewVector(-1, 2, 0)), NewVector(0, 0, 0), NewVector(-1, 2, 0)},
+	}
+
+	for _, c := range cases {
+		p, q := segment.ClosestPoints(c.other)
+		assert.InDelta(t, 0.0, p.Sub(c.p).Mag(), 1e-12)
+		assert.InDelta(t, 0.0, q.Sub(c.q).Mag(), 1e-12)
+	}
+}
@@ -0,0 +1,194 @@
+package soup
+
+import (
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+// Options for converting a TriangleSoup to a HalfEdgeMesh. Zero values use
+// the defaults.
+type SoupToHalfEdgeOptions struct {
+	// Weld the vertices within the tolerance of each other before the
+	// conversion.
+	Weld          bool
+	WeldTolerance float64
+
+	// Drop the triangles sharing an edge already shared by two triangles
+	// (in order of the triangles) rather than failing with
+	// meshx.ErrNonManifold.
+	DropNonManifold bool
+}
+
+// Elements of a TriangleSoup changed or skipped by the conversion to a
+// HalfEdgeMesh.
+type SoupToHalfEdgeReport struct {
+	// Number of vertices merged into another vertex by welding.
+	WeldedVertices int
+
+	// Triangles skipped for repeating a vertex (after welding).
+	DegenerateTriangles []int
+
+	// Triangles skipped for sharing a non-manifold edge.
+	NonManifoldTriangles []int
+}
+
+// Convert a TriangleSoup to a HalfEdgeMesh with options. Degenerate
+// triangles are always skipped and vertices not used by any converted
+// triangle are removed. The soup is unchanged.
+func SoupToHalfEdge(s *TriangleSoup, options SoupToHalfEdgeOptions) (*halfedge.HalfEdgeMesh, SoupToHalfEdgeReport, error) {
+	var report SoupToHalfEdgeReport
+
+	vertexMap := make([]int, len(s.vertices))
+
+	for i := range vertexMap {
+		vertexMap[i] = i
+	}
+
+	if options.Weld {
+		var err error
+
+		vertexMap, report.WeldedVertices, err = weldVertices(s.vertices, options.WeldTolerance)
+		if err != nil {
+			return nil, report, err
+		}
+	}
+
+	result := TriangleSoup{
+		vertices:        s.vertices,
+		triangles:       make([][3]int, 0, len(s.triangles)),
+		trianglePatches: make([]int, 0, len(s.triangles)),
+		patches:         s.patches,
+	}
+
+	edgeCounts := make(map[[2]int]int)
+
+	for i, triangle := range s.triangles {
+		for j := range triangle {
+			triangle[j] = vertexMap[triangle[j]]
+		}
+
+		if triangle[0] == triangle[1] || triangle[1] == triangle[2] || triangle[2] == triangle[0] {
+			report.DegenerateTriangles = append(report.DegenerateTriangles, i)
+			continue
+		}
+
+		if options.DropNonManifold {
+			nonManifold := false
+
+			for j := range triangle {
+				if edgeCounts[getEdgeKey(triangle[j], triangle[(j+1)%3])] >= 2 {
+					nonManifold = true
+				}
+			}
+
+			if nonManifold {
+				report.NonManifoldTriangles = append(report.NonManifoldTriangles, i)
+				continue
+			}
+
+			for j := range triangle {
+				edgeCounts[getEdgeKey(triangle[j], triangle[(j+1)%3])]++
+			}
+		}
+
+		result.triangles = append(result.triangles, triangle)
+		result.trianglePatches = append(result.trianglePatches, s.trianglePatches[i])
+	}
+
+	result.removeUnusedVertices()
+
+	mesh, err := halfedge.NewHalfEdgeMesh(&result)
+	if err != nil {
+		return nil, report, err
+	}
+
+	return mesh, report, nil
+}
+
+// Convert a HalfEdgeMesh to a TriangleSoup. Polygonal faces are triangulated
+// without adding vertices (see planar.TriangulateFace).
+func HalfEdgeToSoup(mesh *halfedge.HalfEdgeMesh) *TriangleSoup {
+	soup, _ := NewTriangleSoupFromMeshReader(mesh.GetMeshReader())
+	return soup
+}
+
+// Get the undirected edge between two vertices.
+func getEdgeKey(p, q int) [2]int {
+	return [2]int{min(p, q), max(p, q)}
+}
+
+// Weld the points within the tolerance of each other. Each point is mapped
+// to the first point (by index) it is welded to. The number of welded
+// points is returned.
+func weldVertices(points []meshx.Vector, tolerance float64) ([]int, int, error) {
+	var count int
+
+	vertexMap := make([]int, len(points))
+
+	for i := range vertexMap {
+		vertexMap[i] = i
+	}
+
+	if len(points) == 0 {
+		return vertexMap, 0, nil
+	}
+
+	halfSize := meshx.NewVector(tolerance, tolerance, tolerance)
+	aabb := meshx.NewAABBFromVectors(points)
+	cellSize := max(tolerance, 1e-9*aabb.HalfSize.Mag(), spatial.GetHashGridMinCellSize(aabb))
+
+	hashGrid, err := spatial.NewHashGrid(cellSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, point := range points {
+		hashGrid.InsertPoint(point)
+	}
+
+	for i, point := range points {
+		if vertexMap[i] != i {
+			continue
+		}
+
+		for _, j := range hashGrid.Query(meshx.NewAABB(point, halfSize)) {
+			if j > i && vertexMap[j] == j && points[j].Sub(point).Mag() <= tolerance {
+				vertexMap[j] = i
+				count++
+			}
+		}
+	}
+
+	return vertexMap, count, nil
+}
+
+// Remove the vertices not used by any triangle (in place). The order of the
+// remaining vertices is retained.
+func (s *TriangleSoup) removeUnusedVertices() {
+	vertexMap := make([]int, len(s.vertices))
+	vertices := make([]meshx.Vector, 0, len(s.vertices))
+
+	for _, triangle := range s.triangles {
+		for _, vertex := range triangle {
+			vertexMap[vertex] = 1
+		}
+	}
+
+	for i, used := range vertexMap {
+		vertexMap[i] = -1
+
+		if used == 1 {
+			vertexMap[i] = len(vertices)
+			vertices = append(vertices, s.vertices[i])
+		}
+	}
+
+	for i, triangle := range s.triangles {
+		for j, vertex := range triangle {
+			s.triangles[i][j] = vertexMap[vertex]
+		}
+	}
+
+	s.vertices = vertices
+}
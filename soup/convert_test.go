@@ -0,0 +1,77 @@
+package soup
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test converting a non-manifold triangle soup to a half edge mesh.
+func TestSoupToHalfEdgeNonManifold(t *testing.T) {
+	soup := newTestFan(t)
+
+	_, _, err := SoupToHalfEdge(soup, SoupToHalfEdgeOptions{})
+	assert.ErrorIs(t, err, meshx.ErrNonManifold)
+
+	mesh, report, err := SoupToHalfEdge(soup, SoupToHalfEdgeOptions{DropNonManifold: true})
+	assert.Empty(t, err)
+	assert.Equal(t, 2, mesh.GetNumberOfFaces())
+	assert.Equal(t, 4, mesh.GetNumberOfVertices())
+	assert.Equal(t, []int{2}, report.NonManifoldTriangles)
+	assert.Empty(t, report.DegenerateTriangles)
+}
+
+// Test converting an unwelded triangle soup to a closed half edge mesh.
+func TestSoupToHalfEdgeWeld(t *testing.T) {
+	source, err := ReadOBJFromPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	vertices := make([]meshx.Vector, 0)
+	triangles := make([][3]int, 0)
+
+	for i := range source.GetNumberOfTriangles() {
+		triangle := source.GetTriangle(i)
+		n := len(vertices)
+		vertices = append(vertices, triangle.P, triangle.Q, triangle.R.Add(meshx.NewVector(1e-9, 0, 0)))
+		triangles = append(triangles, [3]int{n, n + 1, n + 2})
+	}
+
+	triangles = append(triangles, [3]int{0, 0, 1})
+
+	soup, err := NewTriangleSoup(vertices, triangles)
+	assert.Empty(t, err)
+
+	mesh, report, err := SoupToHalfEdge(soup, SoupToHalfEdgeOptions{})
+	assert.Empty(t, err)
+	assert.False(t, mesh.IsClosed())
+	assert.Equal(t, []int{12}, report.DegenerateTriangles)
+
+	options := SoupToHalfEdgeOptions{Weld: true, WeldTolerance: 1e-6}
+	mesh, report, err = SoupToHalfEdge(soup, options)
+	assert.Empty(t, err)
+	assert.True(t, mesh.IsClosed())
+	assert.Equal(t, 8, mesh.GetNumberOfVertices())
+	assert.Equal(t, 12, mesh.GetNumberOfFaces())
+	assert.Equal(t, 36-8, report.WeldedVertices)
+	assert.Equal(t, []int{12}, report.DegenerateTriangles)
+	assert.Equal(t, 13, soup.GetNumberOfTriangles())
+}
+
+// Test converting a half edge mesh to a triangle soup.
+func TestHalfEdgeToSoup(t *testing.T) {
+	mesh, err := halfedge.NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	soup := HalfEdgeToSoup(mesh)
+	assert.Equal(t, mesh.GetNumberOfVertices(), soup.GetNumberOfVertices())
+	assert.Equal(t, 12, soup.GetNumberOfTriangles())
+	assert.Equal(t, mesh.GetNumberOfPatches(), soup.GetNumberOfPatches())
+
+	result, report, err := SoupToHalfEdge(soup, SoupToHalfEdgeOptions{})
+	assert.Empty(t, err)
+	assert.Empty(t, report.DegenerateTriangles)
+	assert.True(t, result.IsClosed())
+	assert.Equal(t, mesh.GetNumberOfPatches(), result.GetNumberOfPatches())
+}
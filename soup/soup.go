@@ -0,0 +1,314 @@
+// Package soup implements an indexed triangle mesh without connectivity
+// requirements for processing non-manifold data.
+package soup
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/planar"
+	"github.com/ajcurley/meshx-go/spatial"
+)
+
+var (
+	ErrInvalidTriangle = errors.New("invalid triangle")
+)
+
+// Indexed set of triangles with no connectivity requirements. Triangles may
+// share edges with any number of other triangles, be duplicated, or be
+// degenerate. Each triangle optionally belongs to a patch (-1 if none).
+type TriangleSoup struct {
+	vertices        []meshx.Vector
+	triangles       [][3]int
+	trianglePatches []int
+	patches         []string
+}
+
+// Construct a TriangleSoup from its vertices and triangles. The triangles do
+// not belong to any patch.
+func NewTriangleSoup(vertices []meshx.Vector, triangles [][3]int) (*TriangleSoup, error) {
+	trianglePatches := make([]int, len(triangles))
+
+	for i, triangle := range triangles {
+		for _, vertex := range triangle {
+			if vertex < 0 || vertex >= len(vertices) {
+				return nil, ErrInvalidTriangle
+			}
+		}
+
+		trianglePatches[i] = -1
+	}
+
+	soup := TriangleSoup{
+		vertices:        vertices,
+		triangles:       triangles,
+		trianglePatches: trianglePatches,
+		patches:         make([]string, 0),
+	}
+
+	return &soup, nil
+}
+
+// Construct a TriangleSoup from a MeshReader. Polygonal faces are
+// triangulated without adding vertices (see planar.TriangulateFace).
+func NewTriangleSoupFromMeshReader(source meshx.MeshReader) (*TriangleSoup, error) {
+	soup := TriangleSoup{
+		vertices:        make([]meshx.Vector, source.GetNumberOfVertices()),
+		triangles:       make([][3]int, 0, source.GetNumberOfFaces()),
+		trianglePatches: make([]int, 0, source.GetNumberOfFaces()),
+		patches:         make([]string, source.GetNumberOfPatches()),
+	}
+
+	for i := range soup.vertices {
+		soup.vertices[i] = source.GetVertex(i)
+	}
+
+	for i := range soup.patches {
+		soup.patches[i] = source.GetPatch(i)
+	}
+
+	for i := range source.GetNumberOfFaces() {
+		face := source.GetFace(i)
+		patch := source.GetFacePatch(i)
+
+		for _, vertex := range face {
+			if vertex < 0 || vertex >= len(soup.vertices) {
+				return nil, ErrInvalidTriangle
+			}
+		}
+
+		for _, triangle := range planar.TriangulateIndexedFace(soup.vertices, face) {
+			soup.triangles = append(soup.triangles, triangle)
+			soup.trianglePatches = append(soup.trianglePatches, patch)
+		}
+	}
+
+	return &soup, nil
+}
+
+// Read a TriangleSoup from an OBJ file path.
+func ReadOBJFromPath(path string) (*TriangleSoup, error) {
+	source, err := meshx.ReadOBJFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewTriangleSoupFromMeshReader(source)
+}
+
+// Read a TriangleSoup from a PLY file path.
+func ReadPLYFromPath(path string) (*TriangleSoup, error) {
+	source, err := meshx.ReadPLYFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewTriangleSoupFromMeshReader(source)
+}
+
+// Write the TriangleSoup with a MeshWriter.
+func (s *TriangleSoup) Write(writer meshx.MeshWriter) error {
+	faces := make([][]int, len(s.triangles))
+
+	for i, triangle := range s.triangles {
+		faces[i] = triangle[:]
+	}
+
+	writer.SetVertices(s.vertices)
+	writer.SetFaces(faces)
+	writer.SetFacePatches(s.trianglePatches)
+	writer.SetPatches(s.patches)
+
+	return writer.Write()
+}
+
+// Write the TriangleSoup to an OBJ file.
+func (s *TriangleSoup) WriteOBJ(writer io.Writer) error {
+	return s.Write(meshx.NewOBJWriter(writer))
+}
+
+// Write the TriangleSoup to an OBJ file path.
+func (s *TriangleSoup) WriteOBJToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return s.WriteOBJ(file)
+}
+
+// Write the TriangleSoup to a PLY file in the format.
+func (s *TriangleSoup) WritePLY(writer io.Writer, format meshx.PLYFormat) error {
+	return s.Write(meshx.NewPLYWriter(writer, format))
+}
+
+// Write the TriangleSoup to a PLY file path in the binary little endian
+// format.
+func (s *TriangleSoup) WritePLYToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return s.WritePLY(file, meshx.PLYFormatBinaryLittleEndian)
+}
+
+// Implement the MeshReader interface.
+func (s *TriangleSoup) Read() error {
+	return nil
+}
+
+// Implement the MeshReader interface.
+func (s *TriangleSoup) GetNumberOfVertices() int {
+	return len(s.vertices)
+}
+
+// Implement the MeshReader interface.
+func (s *TriangleSoup) GetNumberOfFaces() int {
+	return len(s.triangles)
+}
+
+// Implement the MeshReader interface.
+func (s *TriangleSoup) GetNumberOfFaceEdges() int {
+	return 3 * len(s.triangles)
+}
+
+// Implement the MeshReader interface.
+func (s *TriangleSoup) GetNumberOfPatches() int {
+	return len(s.patches)
+}
+
+// Implement the MeshReader interface.
+func (s *TriangleSoup) GetVertex(index int) meshx.Vector {
+	return s.vertices[index]
+}
+
+// Implement the MeshReader interface.
+func (s *TriangleSoup) GetFace(index int) []int {
+	triangle := s.triangles[index]
+	return triangle[:]
+}
+
+// Implement the MeshReader interface.
+func (s *TriangleSoup) GetFacePatch(index int) int {
+	return s.trianglePatches[index]
+}
+
+// Implement the MeshReader interface.
+func (s *TriangleSoup) GetPatch(index int) string {
+	return s.patches[index]
+}
+
+// Get the vertices.
+func (s *TriangleSoup) GetVertices() []meshx.Vector {
+	return s.vertices
+}
+
+// Get the number of triangles.
+func (s *TriangleSoup) GetNumberOfTriangles() int {
+	return len(s.triangles)
+}
+
+// Get the vertex indices of a triangle by index.
+func (s *TriangleSoup) GetTriangleVertices(index int) [3]int {
+	return s.triangles[index]
+}
+
+// Get a triangle by index.
+func (s *TriangleSoup) GetTriangle(index int) meshx.Triangle {
+	triangle := s.triangles[index]
+
+	return meshx.NewTriangle(
+		s.vertices[triangle[0]],
+		s.vertices[triangle[1]],
+		s.vertices[triangle[2]],
+	)
+}
+
+// Get the triangles.
+func (s *TriangleSoup) GetTriangles() []meshx.Triangle {
+	triangles := make([]meshx.Triangle, len(s.triangles))
+
+	for i := range triangles {
+		triangles[i] = s.GetTriangle(i)
+	}
+
+	return triangles
+}
+
+// Set the patch of each triangle by index (-1 if none) and the patch names.
+func (s *TriangleSoup) SetPatches(trianglePatches []int, patches []string) error {
+	if len(trianglePatches) != len(s.triangles) {
+		return ErrInvalidTriangle
+	}
+
+	for _, patch := range trianglePatches {
+		if patch < -1 || patch >= len(patches) {
+			return ErrInvalidTriangle
+		}
+	}
+
+	s.trianglePatches = trianglePatches
+	s.patches = patches
+
+	return nil
+}
+
+// Get the total surface area.
+func (s *TriangleSoup) GetArea() float64 {
+	var area float64
+
+	for i := range s.triangles {
+		area += s.GetTriangle(i).Area()
+	}
+
+	return area
+}
+
+// Get the axis-aligned bounding box.
+func (s *TriangleSoup) GetAABB() meshx.AABB {
+	return meshx.NewAABBFromVectors(s.vertices)
+}
+
+// Build an octree indexing the triangles. The item index of each triangle in
+// the octree is the triangle index.
+func (s *TriangleSoup) BuildOctree() *spatial.Octree {
+	return s.BuildOctreeWithOptions(spatial.OctreeOptions{})
+}
+
+// Build an octree indexing the triangles with options. The item index of
+// each triangle in the octree is the triangle index.
+func (s *TriangleSoup) BuildOctreeWithOptions(options spatial.OctreeOptions) *spatial.Octree {
+	aabb := s.GetAABB()
+	aabb.HalfSize = aabb.HalfSize.AddScalar(1e-6 * aabb.HalfSize.Mag())
+	octree := spatial.NewOctreeWithOptions(aabb.Buffer(0.01), options)
+
+	for i := range s.triangles {
+		octree.Insert(s.GetTriangle(i))
+	}
+
+	return octree
+}
+
+// Cast a ray against every triangle without an octree. The hits are sorted
+// by distance and the index of each hit is the triangle index. Build an
+// octree (see BuildOctree) for repeated queries.
+func (s *TriangleSoup) Raycast(ray meshx.Ray) []meshx.Hit {
+	hits := make([]meshx.Hit, 0)
+
+	for i := range s.triangles {
+		for _, hit := range s.GetTriangle(i).Raycast(ray) {
+			hit.Index = i
+			hits = append(hits, hit)
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Distance < hits[j].Distance
+	})
+
+	return hits
+}
@@ -0,0 +1,90 @@
+package soup
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate three triangles sharing an edge (non-manifold).
+func newTestFan(t *testing.T) *TriangleSoup {
+	vertices := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(0, 0, 1),
+		meshx.NewVector(1, 0, 0.5),
+		meshx.NewVector(-1, 0, 0.5),
+		meshx.NewVector(0, 1, 0.5),
+	}
+
+	triangles := [][3]int{{0, 1, 2}, {1, 0, 3}, {0, 1, 4}}
+
+	soup, err := NewTriangleSoup(vertices, triangles)
+	assert.Empty(t, err)
+
+	return soup
+}
+
+// Test constructing a non-manifold triangle soup.
+func TestNewTriangleSoup(t *testing.T) {
+	soup := newTestFan(t)
+	assert.Equal(t, 3, soup.GetNumberOfTriangles())
+	assert.Equal(t, -1, soup.GetFacePatch(0))
+	assert.InDelta(t, 1.5, soup.GetArea(), 1e-12)
+
+	_, err := halfedge.NewHalfEdgeMesh(soup)
+	assert.ErrorIs(t, err, meshx.ErrNonManifold)
+
+	_, err = NewTriangleSoup(soup.GetVertices(), [][3]int{{0, 1, 5}})
+	assert.ErrorIs(t, err, ErrInvalidTriangle)
+}
+
+// Test reading a triangle soup from an OBJ file path.
+func TestReadOBJFromPath(t *testing.T) {
+	soup, err := ReadOBJFromPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+	assert.Equal(t, 12, soup.GetNumberOfTriangles())
+	assert.Equal(t, 6, soup.GetNumberOfPatches())
+	assert.InDelta(t, 6, soup.GetArea(), 1e-12)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, soup.WriteOBJ(&buffer))
+
+	result, err := NewTriangleSoupFromMeshReader(readOBJ(t, &buffer))
+	assert.Empty(t, err)
+	assert.Equal(t, soup, result)
+}
+
+// Test casting a ray against a triangle soup with and without an octree.
+func TestRaycast(t *testing.T) {
+	soup := newTestFan(t)
+	ray := meshx.NewRay(meshx.NewVector(0.1, -1, 0.5), meshx.NewVector(0, 1, 0))
+
+	hits := soup.Raycast(ray)
+	assert.Equal(t, 1, len(hits))
+	assert.Equal(t, 0, hits[0].Index)
+	assert.InDelta(t, 1, hits[0].Distance, 1e-12)
+	assert.Equal(t, hits, soup.BuildOctree().RaycastAll(ray))
+
+	assert.Equal(t, soup.GetAABB(), meshx.NewAABBFromVectors(soup.GetVertices()))
+}
+
+// Test setting the patches of a triangle soup.
+func TestSetPatches(t *testing.T) {
+	soup := newTestFan(t)
+	assert.Empty(t, soup.SetPatches([]int{0, 1, -1}, []string{"a", "b"}))
+	assert.Equal(t, 2, soup.GetNumberOfPatches())
+	assert.Equal(t, "b", soup.GetPatch(soup.GetFacePatch(1)))
+
+	assert.ErrorIs(t, soup.SetPatches([]int{0, 2, -1}, []string{"a", "b"}), ErrInvalidTriangle)
+	assert.ErrorIs(t, soup.SetPatches([]int{0}, []string{"a"}), ErrInvalidTriangle)
+}
+
+// Read an OBJ file from a buffer.
+func readOBJ(t *testing.T, buffer *bytes.Buffer) meshx.MeshReader {
+	reader := meshx.NewOBJReader(buffer)
+	assert.Empty(t, reader.Read())
+	return reader
+}
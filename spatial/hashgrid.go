@@ -0,0 +1,250 @@
+package spatial
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrHashGridInvalidCellSize = errors.New("invalid cell size")
+)
+
+// Uniform grid of cubic cells indexing items by spatial hashing. The grid is
+// unbounded and only occupied cells are stored.
+type HashGrid struct {
+	cellSize float64
+	cells    map[[3]int][]int
+	items    []meshx.IntersectsAABB
+	minCell  [3]int
+	maxCell  [3]int
+}
+
+// Construct an empty hash grid with the cell size.
+func NewHashGrid(cellSize float64) (*HashGrid, error) {
+	if !(cellSize > 0) || math.IsInf(cellSize, 1) {
+		return nil, ErrHashGridInvalidCellSize
+	}
+
+	hashGrid := HashGrid{
+		cellSize: cellSize,
+		cells:    make(map[[3]int][]int),
+		items:    make([]meshx.IntersectsAABB, 0),
+	}
+
+	return &hashGrid, nil
+}
+
+// Get the smallest cell size of a grid over an AABB (relative to the
+// largest coordinate magnitude) so that the cell coordinates of its points
+// remain finite integers.
+func GetHashGridMinCellSize(aabb meshx.AABB) float64 {
+	minBound, maxBound := aabb.GetMinBound(), aabb.GetMaxBound()
+	var magnitude float64
+
+	for i := range 3 {
+		magnitude = max(magnitude, math.Abs(minBound[i]), math.Abs(maxBound[i]))
+	}
+
+	return 1e-12 * (magnitude + 1)
+}
+
+// Insert an item with its bounding box into the grid. The item is added to
+// each cell its bounding box overlaps that it intersects.
+func (h *HashGrid) Insert(item meshx.IntersectsAABB, aabb meshx.AABB) {
+	index := len(h.items)
+	h.items = append(h.items, item)
+
+	lo := h.getCell(aabb.GetMinBound())
+	hi := h.getCell(aabb.GetMaxBound())
+
+	for i := lo[0]; i <= hi[0]; i++ {
+		for j := lo[1]; j <= hi[1]; j++ {
+			for k := lo[2]; k <= hi[2]; k++ {
+				cell := [3]int{i, j, k}
+
+				if lo != hi && !item.IntersectsAABB(h.getCellAABB(cell)) {
+					continue
+				}
+
+				if len(h.cells) == 0 {
+					h.minCell = cell
+					h.maxCell = cell
+				}
+
+				for n := 0; n < 3; n++ {
+					h.minCell[n] = min(h.minCell[n], cell[n])
+					h.maxCell[n] = max(h.maxCell[n], cell[n])
+				}
+
+				h.cells[cell] = append(h.cells[cell], index)
+			}
+		}
+	}
+}
+
+// Insert a point into the grid.
+func (h *HashGrid) InsertPoint(point meshx.Vector) {
+	h.Insert(point, meshx.NewAABB(point, meshx.Vector{}))
+}
+
+// Query the grid for the items intersecting an AABB.
+func (h *HashGrid) Query(query meshx.AABB) []int {
+	items := make([]int, 0)
+	visited := make(map[int]bool)
+
+	lo := h.getCell(query.GetMinBound())
+	hi := h.getCell(query.GetMaxBound())
+
+	for i := max(lo[0], h.minCell[0]); i <= min(hi[0], h.maxCell[0]); i++ {
+		for j := max(lo[1], h.minCell[1]); j <= min(hi[1], h.maxCell[1]); j++ {
+			for k := max(lo[2], h.minCell[2]); k <= min(hi[2], h.maxCell[2]); k++ {
+				for _, index := range h.cells[[3]int{i, j, k}] {
+					if !visited[index] {
+						visited[index] = true
+
+						if h.items[index].IntersectsAABB(query) {
+							items = append(items, index)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return items
+}
+
+// Query the grid for the item closest to a point. Only items implementing
+// the ClosestPoint interface are considered. The index of the item and the
+// closest point on it are returned. The index is -1 if no item is found.
+func (h *HashGrid) QueryNearest(point meshx.Vector) (int, meshx.Vector) {
+	var closest meshx.Vector
+
+	nearest := -1
+	distance := math.Inf(1)
+
+	if len(h.cells) == 0 {
+		return nearest, closest
+	}
+
+	center := h.getCell(point)
+	visited := make(map[int]bool)
+
+	// Search rings of cells at increasing Chebyshev distance from the cell of
+	// the point, starting from the first ring with occupied cells. Items in
+	// ring r+1 are at least r cell sizes away.
+	var start int
+
+	for n := 0; n < 3; n++ {
+		start = max(start, h.minCell[n]-center[n], center[n]-h.maxCell[n])
+	}
+
+	for r := start; ; r++ {
+		if distance <= float64(r-1)*h.cellSize {
+			break
+		}
+
+		var remaining bool
+
+		for n := 0; n < 3; n++ {
+			if center[n]-r > h.minCell[n] || center[n]+r < h.maxCell[n] {
+				remaining = true
+			}
+		}
+
+		lo := [3]int{}
+		hi := [3]int{}
+
+		for n := 0; n < 3; n++ {
+			lo[n] = max(center[n]-r, h.minCell[n])
+			hi[n] = min(center[n]+r, h.maxCell[n])
+		}
+
+		for i := lo[0]; i <= hi[0]; i++ {
+			for j := lo[1]; j <= hi[1]; j++ {
+				for k := lo[2]; k <= hi[2]; k++ {
+					if max(abs(i-center[0]), abs(j-center[1]), abs(k-center[2])) != r {
+						continue
+					}
+
+					for _, index := range h.cells[[3]int{i, j, k}] {
+						if visited[index] {
+							continue
+						}
+
+						visited[index] = true
+
+						if item, ok := h.items[index].(meshx.ClosestPoint); ok {
+							candidate := item.ClosestPoint(point)
+
+							if d := candidate.Sub(point).Mag(); d < distance {
+								nearest = index
+								closest = candidate
+								distance = d
+							}
+						}
+					}
+				}
+			}
+		}
+
+		if !remaining {
+			break
+		}
+	}
+
+	return nearest, closest
+}
+
+// Get the item at an index.
+func (h *HashGrid) GetItem(index int) meshx.IntersectsAABB {
+	return h.items[index]
+}
+
+// Get the cell size.
+func (h *HashGrid) GetCellSize() float64 {
+	return h.cellSize
+}
+
+// Get the number of indexed items.
+func (h *HashGrid) GetNumberOfItems() int {
+	return len(h.items)
+}
+
+// Get the number of occupied cells.
+func (h *HashGrid) GetNumberOfCells() int {
+	return len(h.cells)
+}
+
+// Get the cell containing a point.
+func (h *HashGrid) getCell(point meshx.Vector) [3]int {
+	var cell [3]int
+
+	for i := 0; i < 3; i++ {
+		cell[i] = int(math.Floor(point[i] / h.cellSize))
+	}
+
+	return cell
+}
+
+// Get the bounding box of a cell.
+func (h *HashGrid) getCellAABB(cell [3]int) meshx.AABB {
+	halfSize := meshx.NewVector(h.cellSize/2, h.cellSize/2, h.cellSize/2)
+	center := meshx.NewVector(
+		(float64(cell[0])+0.5)*h.cellSize,
+		(float64(cell[1])+0.5)*h.cellSize,
+		(float64(cell[2])+0.5)*h.cellSize,
+	)
+
+	return meshx.NewAABB(center, halfSize)
+}
+
+// Compute the absolute value of an integer.
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,111 @@
+package spatial
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test constructing a hash grid with an invalid cell size.
+func TestNewHashGridInvalidCellSize(t *testing.T) {
+	for _, cellSize := range []float64{0, -1} {
+		_, err := NewHashGrid(cellSize)
+		assert.ErrorIs(t, err, ErrHashGridInvalidCellSize)
+	}
+}
+
+// Test the minimum cell size keeps the points of a degenerate AABB in
+// distinct cells.
+func TestGetHashGridMinCellSize(t *testing.T) {
+	point := meshx.NewVector(1e6, -2e6, 3)
+	cellSize := GetHashGridMinCellSize(meshx.NewAABB(point, meshx.Vector{}))
+	assert.InDelta(t, 1e-12*(2e6+1), cellSize, 1e-18)
+
+	hashGrid, err := NewHashGrid(cellSize)
+	assert.Empty(t, err)
+
+	hashGrid.InsertPoint(point)
+	hashGrid.InsertPoint(point.Add(meshx.NewVector(1e-3, 0, 0)))
+	assert.Equal(t, 2, hashGrid.GetNumberOfCells())
+	assert.Equal(t, []int{0}, hashGrid.Query(meshx.NewAABB(point, meshx.Vector{})))
+}
+
+// Test querying the hash grid with an AABB against a brute force search.
+func TestHashGridQuery(t *testing.T) {
+	triangles := newOctreeTestTriangles(16)
+	hashGrid, err := NewHashGrid(0.1)
+	assert.Empty(t, err)
+
+	for _, triangle := range triangles {
+		aabb := meshx.NewAABBFromVectors([]meshx.Vector{triangle.P, triangle.Q, triangle.R})
+		hashGrid.Insert(triangle, aabb)
+	}
+
+	assert.Equal(t, len(triangles), hashGrid.GetNumberOfItems())
+
+	query := meshx.NewAABB(meshx.NewVector(0.4, 0.3, 0), meshx.NewVector(0.12, 0.07, 0.1))
+	expected := make([]int, 0)
+
+	for i, triangle := range triangles {
+		if triangle.IntersectsAABB(query) {
+			expected = append(expected, i)
+		}
+	}
+
+	items := hashGrid.Query(query)
+	sort.Ints(items)
+
+	assert.NotEmpty(t, expected)
+	assert.Equal(t, expected, items)
+	assert.Empty(t, hashGrid.Query(meshx.NewAABB(meshx.NewVector(5, 5, 5), meshx.NewVector(1, 1, 1))))
+}
+
+// Test querying the nearest point of the hash grid against a brute force
+// search.
+func TestHashGridQueryNearest(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	points := make([]meshx.Vector, 500)
+	hashGrid, err := NewHashGrid(0.05)
+	assert.Empty(t, err)
+
+	for i := range points {
+		points[i] = meshx.NewVector(rng.Float64(), rng.Float64(), rng.Float64())
+		hashGrid.InsertPoint(points[i])
+	}
+
+	queries := []meshx.Vector{
+		meshx.NewVector(0.5, 0.5, 0.5),
+		meshx.NewVector(-0.3, 0.2, 0.9),
+		meshx.NewVector(4, -3, 2),
+	}
+
+	for i := 0; i < 50; i++ {
+		queries = append(queries, meshx.NewVector(rng.Float64(), rng.Float64(), rng.Float64()))
+	}
+
+	for _, query := range queries {
+		expected := 0
+
+		for i, point := range points {
+			if point.Sub(query).Mag() < points[expected].Sub(query).Mag() {
+				expected = i
+			}
+		}
+
+		index, closest := hashGrid.QueryNearest(query)
+		assert.Equal(t, expected, index)
+		assert.Equal(t, points[expected], closest)
+	}
+}
+
+// Test querying the nearest item of an empty hash grid.
+func TestHashGridQueryNearestEmpty(t *testing.T) {
+	hashGrid, err := NewHashGrid(1)
+	assert.Empty(t, err)
+
+	index, _ := hashGrid.QueryNearest(meshx.NewVector(0, 0, 0))
+	assert.Equal(t, -1, index)
+}
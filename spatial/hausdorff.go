@@ -0,0 +1,174 @@
+package spatial
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Options for sampling the distance between two surfaces.
+type HausdorffOptions struct {
+	// Number of samples per unit area in addition to the triangle vertices
+	// and centroids. If zero, only the vertices and centroids are sampled.
+	SampleDensity float64
+}
+
+// Deviation of one surface from another. The mean and RMS are weighted by
+// the area of the sampled triangles (shared among the samples of each), or
+// uniformly if the sampled triangles have no area.
+type Deviation struct {
+	Max         float64
+	Mean        float64
+	RMS         float64
+	MaxLocation meshx.Vector
+	Samples     int
+
+	// Total area of the sampled triangles.
+	Area float64
+}
+
+// Compute the (symmetric) Hausdorff distance between two triangulated
+// surfaces along with the mean and RMS deviation of the samples of both. The
+// deviation is infinite if exactly one of the surfaces is empty.
+func HausdorffDistance(a, b []meshx.Triangle, options HausdorffOptions) Deviation {
+	ab := DirectedHausdorffDistance(a, b, options)
+	ba := DirectedHausdorffDistance(b, a, options)
+
+	deviation := Deviation{
+		Max:         ab.Max,
+		MaxLocation: ab.MaxLocation,
+		Samples:     ab.Samples + ba.Samples,
+		Area:        ab.Area + ba.Area,
+	}
+
+	if ba.Max > ab.Max {
+		deviation.Max = ba.Max
+		deviation.MaxLocation = ba.MaxLocation
+	}
+
+	if math.IsInf(deviation.Max, 1) {
+		deviation.Mean = deviation.Max
+		deviation.RMS = deviation.Max
+		return deviation
+	}
+
+	weightAB, weightBA := ab.Area, ba.Area
+
+	if deviation.Area == 0 {
+		weightAB, weightBA = float64(ab.Samples), float64(ba.Samples)
+	}
+
+	if weight := weightAB + weightBA; weight > 0 {
+		sum := ab.Mean*weightAB + ba.Mean*weightBA
+		sumSquares := ab.RMS*ab.RMS*weightAB + ba.RMS*ba.RMS*weightBA
+		deviation.Mean = sum / weight
+		deviation.RMS = math.Sqrt(sumSquares / weight)
+	}
+
+	return deviation
+}
+
+// Compute the directed Hausdorff distance from surface a to surface b. The
+// location of the maximum deviation is a sample on surface a. The deviation
+// is zero if a is empty and infinite if only b is empty.
+func DirectedHausdorffDistance(a, b []meshx.Triangle, options HausdorffOptions) Deviation {
+	var deviation Deviation
+	var sum, sumSquares, sumWeights float64
+	var sumUniform, sumSquaresUniform float64
+
+	if len(a) == 0 {
+		return deviation
+	}
+
+	if len(b) == 0 {
+		deviation.Max = math.Inf(1)
+		deviation.Mean = deviation.Max
+		deviation.RMS = deviation.Max
+		deviation.MaxLocation = a[0].P
+		return deviation
+	}
+
+	octree := newTriangleOctree(b)
+
+	for _, triangle := range a {
+		samples := sampleTriangle(triangle, options.SampleDensity)
+		area := triangle.Area()
+		weight := area / float64(len(samples))
+
+		for _, sample := range samples {
+			_, closest := octree.QueryNearest(sample)
+			distance := closest.Sub(sample).Mag()
+
+			if distance > deviation.Max || deviation.Samples == 0 {
+				deviation.Max = distance
+				deviation.MaxLocation = sample
+			}
+
+			sum += weight * distance
+			sumSquares += weight * distance * distance
+			sumWeights += weight
+			sumUniform += distance
+			sumSquaresUniform += distance * distance
+			deviation.Samples++
+		}
+
+		deviation.Area += area
+	}
+
+	if sumWeights > 0 {
+		deviation.Mean = sum / sumWeights
+		deviation.RMS = math.Sqrt(sumSquares / sumWeights)
+	} else {
+		deviation.Mean = sumUniform / float64(deviation.Samples)
+		deviation.RMS = math.Sqrt(sumSquaresUniform / float64(deviation.Samples))
+	}
+
+	return deviation
+}
+
+// Construct an octree indexing triangles.
+func newTriangleOctree(triangles []meshx.Triangle) *Octree {
+	points := make([]meshx.Vector, 0, 3*len(triangles))
+
+	for _, triangle := range triangles {
+		points = append(points, triangle.P, triangle.Q, triangle.R)
+	}
+
+	aabb := meshx.NewAABBFromVectors(points)
+	aabb.HalfSize = aabb.HalfSize.AddScalar(1e-6 * aabb.HalfSize.Mag())
+	octree := NewOctree(aabb.Buffer(0.01))
+
+	for _, triangle := range triangles {
+		octree.Insert(triangle)
+	}
+
+	return octree
+}
+
+// Sample a triangle on a regular barycentric grid with approximately the
+// sample density (samples per unit area). The vertices and centroid are
+// always sampled.
+func sampleTriangle(triangle meshx.Triangle, density float64) []meshx.Vector {
+	samples := []meshx.Vector{triangle.P, triangle.Q, triangle.R, triangle.Centroid()}
+	n := int(math.Ceil(math.Sqrt(2 * triangle.Area() * density)))
+
+	if n <= 1 {
+		return samples
+	}
+
+	u := triangle.Q.Sub(triangle.P).DivScalar(float64(n))
+	v := triangle.R.Sub(triangle.P).DivScalar(float64(n))
+
+	for i := 0; i <= n; i++ {
+		for j := 0; i+j <= n; j++ {
+			if (i == 0 && j == 0) || (i == n && j == 0) || (i == 0 && j == n) {
+				continue
+			}
+
+			sample := triangle.P.Add(u.MulScalar(float64(i))).Add(v.MulScalar(float64(j)))
+			samples = append(samples, sample)
+		}
+	}
+
+	return samples
+}
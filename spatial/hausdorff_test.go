@@ -0,0 +1,80 @@
+package spatial
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate the triangles of a unit square at a height.
+func newHausdorffTestSquare(z float64) []meshx.Triangle {
+	return []meshx.Triangle{
+		meshx.NewTriangle(meshx.NewVector(0, 0, z), meshx.NewVector(1, 0, z), meshx.NewVector(1, 1, z)),
+		meshx.NewTriangle(meshx.NewVector(0, 0, z), meshx.NewVector(1, 1, z), meshx.NewVector(0, 1, z)),
+	}
+}
+
+// Test the Hausdorff distance between parallel surfaces.
+func TestHausdorffDistanceParallel(t *testing.T) {
+	a := newHausdorffTestSquare(0)
+	b := newHausdorffTestSquare(0.5)
+
+	deviation := HausdorffDistance(a, b, HausdorffOptions{SampleDensity: 100})
+	assert.InDelta(t, 0.5, deviation.Max, 1e-12)
+	assert.InDelta(t, 0.5, deviation.Mean, 1e-12)
+	assert.InDelta(t, 0.5, deviation.RMS, 1e-12)
+	assert.Greater(t, deviation.Samples, 100)
+}
+
+// Test the location of the maximum directed Hausdorff distance.
+func TestDirectedHausdorffDistance(t *testing.T) {
+	a := newHausdorffTestSquare(0)
+	b := a[:1]
+
+	deviation := DirectedHausdorffDistance(a, b, HausdorffOptions{})
+	assert.InDelta(t, 0.5*1.4142135623730951, deviation.Max, 1e-12)
+	assert.Equal(t, meshx.NewVector(0, 1, 0), deviation.MaxLocation)
+
+	deviation = DirectedHausdorffDistance(b, a, HausdorffOptions{})
+	assert.InDelta(t, 0.0, deviation.Max, 1e-12)
+}
+
+// Test the Hausdorff distance to and from an empty surface.
+func TestHausdorffDistanceEmpty(t *testing.T) {
+	a := newHausdorffTestSquare(0)
+
+	deviation := DirectedHausdorffDistance(a, nil, HausdorffOptions{})
+	assert.True(t, math.IsInf(deviation.Max, 1))
+	assert.True(t, math.IsInf(deviation.Mean, 1))
+
+	deviation = DirectedHausdorffDistance(nil, a, HausdorffOptions{})
+	assert.Equal(t, 0.0, deviation.Max)
+
+	for _, deviation := range []Deviation{HausdorffDistance(a, nil, HausdorffOptions{}), HausdorffDistance(nil, a, HausdorffOptions{})} {
+		assert.True(t, math.IsInf(deviation.Max, 1))
+		assert.True(t, math.IsInf(deviation.Mean, 1))
+		assert.True(t, math.IsInf(deviation.RMS, 1))
+	}
+
+	deviation = HausdorffDistance(nil, nil, HausdorffOptions{})
+	assert.Equal(t, 0.0, deviation.Max)
+}
+
+// Test weighting the mean and RMS deviation by the area of the triangles.
+func TestDirectedHausdorffDistanceWeighted(t *testing.T) {
+	b := newHausdorffTestSquare(0)
+
+	// Unit square at the surface and a small triangle of area 0.005 above it.
+	a := append(newHausdorffTestSquare(0), meshx.NewTriangle(
+		meshx.NewVector(0, 0, 1), meshx.NewVector(0.1, 0, 1), meshx.NewVector(0, 0.1, 1),
+	))
+
+	deviation := DirectedHausdorffDistance(a, b, HausdorffOptions{})
+	assert.Equal(t, 12, deviation.Samples)
+	assert.InDelta(t, 1.005, deviation.Area, 1e-12)
+	assert.InDelta(t, 1.0, deviation.Max, 1e-12)
+	assert.InDelta(t, 0.005/1.005, deviation.Mean, 1e-12)
+	assert.InDelta(t, math.Sqrt(0.005/1.005), deviation.RMS, 1e-12)
+}
@@ -0,0 +1,217 @@
+package spatial
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Static k-dimensional tree (k = 3) for nearest neighbor queries of points.
+// The tree is stored implicitly: the median of each index range is the node
+// splitting the range.
+type KDTree struct {
+	points  []meshx.Vector
+	indices []int
+	axes    []int
+}
+
+// Construct a KDTree from a set of points.
+func NewKDTree(points []meshx.Vector) *KDTree {
+	tree := &KDTree{
+		points:  points,
+		indices: make([]int, len(points)),
+		axes:    make([]int, len(points)),
+	}
+
+	for i := range tree.indices {
+		tree.indices[i] = i
+	}
+
+	tree.build(0, len(points))
+
+	return tree
+}
+
+// Build the subtree of the index range [lo, hi) split along the axis of
+// largest spread.
+func (t *KDTree) build(lo, hi int) {
+	if hi-lo <= 1 {
+		return
+	}
+
+	minBound := t.points[t.indices[lo]]
+	maxBound := minBound
+
+	for _, index := range t.indices[lo+1 : hi] {
+		for i := 0; i < 3; i++ {
+			minBound[i] = min(minBound[i], t.points[index][i])
+			maxBound[i] = max(maxBound[i], t.points[index][i])
+		}
+	}
+
+	axis := 0
+	extent := maxBound.Sub(minBound)
+
+	for i := 1; i < 3; i++ {
+		if extent[i] > extent[axis] {
+			axis = i
+		}
+	}
+
+	indices := t.indices[lo:hi]
+
+	sort.Slice(indices, func(i, j int) bool {
+		return t.points[indices[i]][axis] < t.points[indices[j]][axis]
+	})
+
+	mid := (lo + hi) / 2
+	t.axes[mid] = axis
+	t.build(lo, mid)
+	t.build(mid+1, hi)
+}
+
+// Get the number of indexed points.
+func (t *KDTree) GetNumberOfPoints() int {
+	return len(t.points)
+}
+
+// Get a point by index.
+func (t *KDTree) GetPoint(index int) meshx.Vector {
+	return t.points[index]
+}
+
+// Query the index of the point nearest to a point and its distance. The
+// index is -1 if the tree is empty.
+func (t *KDTree) QueryNearest(point meshx.Vector) (int, float64) {
+	neighbors := t.QueryKNearest(point, 1)
+
+	if len(neighbors) == 0 {
+		return -1, math.Inf(1)
+	}
+
+	return neighbors[0], t.points[neighbors[0]].Sub(point).Mag()
+}
+
+// Query the indices of the k points nearest to a point sorted by distance.
+func (t *KDTree) QueryKNearest(point meshx.Vector, k int) []int {
+	if k <= 0 {
+		return []int{}
+	}
+
+	queue := make(kdTreeQueue, 0, k+1)
+	t.queryKNearest(point, k, 0, len(t.points), &queue)
+
+	neighbors := make([]int, len(queue))
+
+	for i := len(queue) - 1; i >= 0; i-- {
+		neighbors[i] = heap.Pop(&queue).(kdTreeEntry).index
+	}
+
+	return neighbors
+}
+
+// Recursively query the k nearest points in the index range [lo, hi).
+func (t *KDTree) queryKNearest(point meshx.Vector, k, lo, hi int, queue *kdTreeQueue) {
+	if lo >= hi {
+		return
+	}
+
+	mid := (lo + hi) / 2
+	index := t.indices[mid]
+	d := t.points[index].Sub(point)
+	distance := d.Dot(d)
+
+	if len(*queue) < k {
+		heap.Push(queue, kdTreeEntry{index, distance})
+	} else if distance < (*queue)[0].distance {
+		(*queue)[0] = kdTreeEntry{index, distance}
+		heap.Fix(queue, 0)
+	}
+
+	axis := t.axes[mid]
+	delta := point[axis] - t.points[index][axis]
+
+	nearLo, nearHi, farLo, farHi := lo, mid, mid+1, hi
+
+	if delta > 0 {
+		nearLo, nearHi, farLo, farHi = mid+1, hi, lo, mid
+	}
+
+	t.queryKNearest(point, k, nearLo, nearHi, queue)
+
+	if len(*queue) < k || delta*delta < (*queue)[0].distance {
+		t.queryKNearest(point, k, farLo, farHi, queue)
+	}
+}
+
+// Query the indices of the points within a radius of a point.
+func (t *KDTree) QueryRadius(point meshx.Vector, radius float64) []int {
+	neighbors := make([]int, 0)
+	t.queryRadius(point, radius*radius, 0, len(t.points), &neighbors)
+	return neighbors
+}
+
+// Recursively query the points within a radius in the index range [lo, hi).
+func (t *KDTree) queryRadius(point meshx.Vector, radiusSquared float64, lo, hi int, neighbors *[]int) {
+	if lo >= hi {
+		return
+	}
+
+	mid := (lo + hi) / 2
+	index := t.indices[mid]
+	d := t.points[index].Sub(point)
+
+	if d.Dot(d) <= radiusSquared {
+		*neighbors = append(*neighbors, index)
+	}
+
+	axis := t.axes[mid]
+	delta := point[axis] - t.points[index][axis]
+
+	if delta <= 0 || delta*delta <= radiusSquared {
+		t.queryRadius(point, radiusSquared, lo, mid, neighbors)
+	}
+
+	if delta >= 0 || delta*delta <= radiusSquared {
+		t.queryRadius(point, radiusSquared, mid+1, hi, neighbors)
+	}
+}
+
+// Point index with its squared distance to a query point.
+type kdTreeEntry struct {
+	index    int
+	distance float64
+}
+
+// Max-heap of points ordered by distance.
+type kdTreeQueue []kdTreeEntry
+
+// Implement the heap.Interface interface.
+func (q kdTreeQueue) Len() int {
+	return len(q)
+}
+
+// Implement the heap.Interface interface.
+func (q kdTreeQueue) Less(i, j int) bool {
+	return q[i].distance > q[j].distance
+}
+
+// Implement the heap.Interface interface.
+func (q kdTreeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Implement the heap.Interface interface.
+func (q *kdTreeQueue) Push(x any) {
+	*q = append(*q, x.(kdTreeEntry))
+}
+
+// Implement the heap.Interface interface.
+func (q *kdTreeQueue) Pop() any {
+	n := len(*q)
+	entry := (*q)[n-1]
+	*q = (*q)[:n-1]
+	return entry
+}
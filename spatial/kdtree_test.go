@@ -0,0 +1,64 @@
+package spatial
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate random points in the unit cube.
+func newKDTreeTestPoints(n int) []meshx.Vector {
+	random := rand.New(rand.NewSource(0))
+	points := make([]meshx.Vector, n)
+
+	for i := range points {
+		points[i] = meshx.NewVector(random.Float64(), random.Float64(), random.Float64())
+	}
+
+	return points
+}
+
+// Test the k nearest points against a brute force search.
+func TestKDTreeQueryKNearest(t *testing.T) {
+	points := newKDTreeTestPoints(1000)
+	tree := NewKDTree(points)
+	query := meshx.NewVector(0.3, 0.6, 0.2)
+
+	expected := make([]int, len(points))
+
+	for i := range expected {
+		expected[i] = i
+	}
+
+	sort.Slice(expected, func(i, j int) bool {
+		return points[expected[i]].Sub(query).Mag() < points[expected[j]].Sub(query).Mag()
+	})
+
+	assert.Equal(t, expected[:10], tree.QueryKNearest(query, 10))
+
+	nearest, distance := tree.QueryNearest(query)
+	assert.Equal(t, expected[0], nearest)
+	assert.Equal(t, points[expected[0]].Sub(query).Mag(), distance)
+}
+
+// Test the points within a radius against a brute force search.
+func TestKDTreeQueryRadius(t *testing.T) {
+	points := newKDTreeTestPoints(1000)
+	tree := NewKDTree(points)
+	query := meshx.NewVector(0.5, 0.5, 0.5)
+
+	expected := make([]int, 0)
+
+	for i, point := range points {
+		if point.Sub(query).Mag() <= 0.2 {
+			expected = append(expected, i)
+		}
+	}
+
+	neighbors := tree.QueryRadius(query, 0.2)
+	sort.Ints(neighbors)
+	assert.Equal(t, expected, neighbors)
+}
@@ -1,7 +1,11 @@
 package spatial
 
 import (
+	"container/heap"
 	"errors"
+	"math"
+	"slices"
+	"sort"
 
 	"github.com/ajcurley/meshx-go"
 )
@@ -9,64 +13,191 @@ import (
 const (
 	OctreeMaxDepth     = 21
 	OctreeMaxLeafItems = 100
+
+	// Default ratio of the root node size to the minimum node size.
+	OctreeMinNodeSizeRatio = 64
 )
 
 var (
 	ErrOctreeItemNotInserted = errors.New("item not inserted")
 	ErrOctreeCannotSplitNode = errors.New("cannot split node")
+	ErrOctreeItemCount       = errors.New("number of items does not match")
+)
+
+// Heuristic deciding when a leaf node of an octree is split.
+type OctreeSplitHeuristic int
+
+const (
+	// Split a leaf with more than the maximum number of items.
+	OctreeSplitByItemCount OctreeSplitHeuristic = iota
+
+	// Split a leaf with any items until it is no larger than the minimum
+	// node size.
+	OctreeSplitByNodeSize
 )
 
+// Options for constructing an octree. Zero values use the defaults.
+type OctreeOptions struct {
+	// Maximum depth of a node (at most OctreeMaxDepth).
+	MaxDepth int
+
+	// Maximum number of items in a leaf for OctreeSplitByItemCount.
+	MaxLeafItems int
+
+	// Heuristic deciding when a leaf is split.
+	SplitHeuristic OctreeSplitHeuristic
+
+	// Minimum edge length of a node for OctreeSplitByNodeSize. The default is
+	// the root node size divided by OctreeMinNodeSizeRatio.
+	MinNodeSize float64
+}
+
 type Octree struct {
-	nodes map[uint64]*OctreeNode
-	items []meshx.IntersectsAABB
+	nodes   map[uint64]*OctreeNode
+	items   []meshx.IntersectsAABB
+	options OctreeOptions
 }
 
 // Construct a bounded octree.
 func NewOctree(aabb meshx.AABB) *Octree {
+	return NewOctreeWithOptions(aabb, OctreeOptions{})
+}
+
+// Construct a bounded octree with options.
+func NewOctreeWithOptions(aabb meshx.AABB, options OctreeOptions) *Octree {
+	if options.MaxDepth <= 0 || options.MaxDepth > OctreeMaxDepth {
+		options.MaxDepth = OctreeMaxDepth
+	}
+
+	if options.MaxLeafItems <= 0 {
+		options.MaxLeafItems = OctreeMaxLeafItems
+	}
+
+	if options.MinNodeSize <= 0 {
+		size := 2 * max(aabb.HalfSize[0], aabb.HalfSize[1], aabb.HalfSize[2])
+		options.MinNodeSize = size / OctreeMinNodeSizeRatio
+	}
+
 	return &Octree{
-		nodes: map[uint64]*OctreeNode{1: NewOctreeNode(1, aabb)},
-		items: make([]meshx.IntersectsAABB, 0),
+		nodes:   map[uint64]*OctreeNode{1: NewOctreeNode(1, aabb)},
+		items:   make([]meshx.IntersectsAABB, 0),
+		options: options,
 	}
 }
 
+// Get the options.
+func (o *Octree) GetOptions() OctreeOptions {
+	return o.options
+}
+
 // Insert an item into the octree.
 func (o *Octree) Insert(item meshx.IntersectsAABB) error {
 	var code uint64
 
-	codes := make([]uint64, 0, 8)
-	queue := make([]uint64, 1, 128)
-	queue[0] = 1
+	codes := o.getLeaves(item)
 
-	for len(queue) > 0 {
-		code, queue = queue[0], queue[1:]
+	if len(codes) == 0 {
+		return ErrOctreeItemNotInserted
+	}
+
+	index := len(o.items)
+	o.items = append(o.items, item)
+
+	for _, code := range codes {
 		node := o.nodes[code]
+		node.items = append(node.items, index)
+	}
 
-		if item.IntersectsAABB(node.aabb) {
-			if node.isLeaf {
-				codes = append(codes, code)
-			} else {
-				children := node.Children()
-				queue = append(queue, children...)
-			}
+	// Split the leaves recursively until no leaf should be split.
+	for len(codes) > 0 {
+		code, codes = codes[0], codes[1:]
+		node := o.nodes[code]
+
+		if o.shouldSplit(node) {
+			o.Split(code)
+			codes = append(codes, node.Children()...)
 		}
 	}
 
+	return nil
+}
+
+// Replace an indexed item (e.g. after it moved) and move it to the leaves it
+// intersects. The nodes are not split, so the queries slow down if many
+// items are moved into the same leaf. The octree is unchanged if the item is
+// outside of it.
+func (o *Octree) Update(index int, item meshx.IntersectsAABB) error {
+	codes := o.getLeaves(item)
+
 	if len(codes) == 0 {
 		return ErrOctreeItemNotInserted
 	}
 
-	index := len(o.items)
-	o.items = append(o.items, item)
+	o.removeFromLeaves(index)
+	o.items[index] = item
 
 	for _, code := range codes {
 		node := o.nodes[code]
 		node.items = append(node.items, index)
+	}
 
-		if node.shouldSplit() {
-			o.Split(code)
+	return nil
+}
+
+// Remove an indexed item. The last item is moved to its index (as for a
+// slice swap remove).
+func (o *Octree) Remove(index int) {
+	o.removeFromLeaves(index)
+	last := len(o.items) - 1
+
+	if index != last {
+		for _, code := range o.getLeaves(o.items[last]) {
+			node := o.nodes[code]
+
+			if i := slices.Index(node.items, last); i >= 0 {
+				node.items[i] = index
+			}
+		}
+
+		o.items[index] = o.items[last]
+	}
+
+	o.items[last] = nil
+	o.items = o.items[:last]
+}
+
+// Replace every item (e.g. after the vertices of a mesh moved) and
+// redistribute them into the leaves without rebuilding the nodes. The
+// octree is unchanged if an item is outside of it or the number of items
+// differs.
+func (o *Octree) Refit(items []meshx.IntersectsAABB) error {
+	if len(items) != len(o.items) {
+		return ErrOctreeItemCount
+	}
+
+	leaves := make([][]uint64, len(items))
+
+	for i, item := range items {
+		if leaves[i] = o.getLeaves(item); len(leaves[i]) == 0 {
+			return ErrOctreeItemNotInserted
+		}
+	}
+
+	for _, node := range o.nodes {
+		if node.isLeaf {
+			node.items = node.items[:0]
 		}
 	}
 
+	for i, codes := range leaves {
+		for _, code := range codes {
+			node := o.nodes[code]
+			node.items = append(node.items, i)
+		}
+	}
+
+	copy(o.items, items)
+
 	return nil
 }
 
@@ -74,7 +205,7 @@ func (o *Octree) Insert(item meshx.IntersectsAABB) error {
 func (o *Octree) Split(code uint64) error {
 	node := o.nodes[code]
 
-	if !node.canSplit() {
+	if !o.canSplit(node) {
 		return ErrOctreeCannotSplitNode
 	}
 
@@ -106,6 +237,10 @@ func (o *Octree) Query(query meshx.IntersectsAABB) []int {
 	queue := make([]uint64, 1, 128)
 	queue[0] = 1
 
+	if ray, ok := query.(meshx.Ray); ok {
+		query = ray.Precompute()
+	}
+
 	for len(queue) > 0 {
 		code, queue = queue[0], queue[1:]
 		node := o.nodes[code]
@@ -125,9 +260,29 @@ func (o *Octree) Query(query meshx.IntersectsAABB) []int {
 							if item, ok := o.items[index].(meshx.IntersectsTriangle); ok {
 								intersects = item.IntersectsTriangle(value)
 							}
-						case meshx.Ray:
+						case meshx.PrecomputedRay:
 							if item, ok := o.items[index].(meshx.IntersectsRay); ok {
-								intersects = item.IntersectsRay(value)
+								intersects = item.IntersectsRay(value.Ray)
+							}
+						case meshx.Segment:
+							if item, ok := o.items[index].(meshx.IntersectsSegment); ok {
+								intersects = item.IntersectsSegment(value)
+							}
+						case meshx.Cylinder:
+							if item, ok := o.items[index].(meshx.IntersectsCylinder); ok {
+								intersects = item.IntersectsCylinder(value)
+							}
+						case meshx.Capsule:
+							if item, ok := o.items[index].(meshx.IntersectsCapsule); ok {
+								intersects = item.IntersectsCapsule(value)
+							}
+						case meshx.Sphere:
+							if item, ok := o.items[index].(meshx.IntersectsSphere); ok {
+								intersects = item.IntersectsSphere(value)
+							}
+						case meshx.Polytope:
+							if item, ok := o.items[index].(meshx.IntersectsPolytope); ok {
+								intersects = item.IntersectsPolytope(value)
 							}
 						}
 
@@ -147,6 +302,107 @@ func (o *Octree) Query(query meshx.IntersectsAABB) []int {
 	return items
 }
 
+// Query the octree for the item closest to a point. Only items implementing
+// the ClosestPoint interface are considered. The index of the item and the
+// closest point on it are returned. The index is -1 if no item is found.
+func (o *Octree) QueryNearest(point meshx.Vector) (int, meshx.Vector) {
+	var closest meshx.Vector
+
+	nearest := -1
+	distance := math.Inf(1)
+
+	queue := octreeNodeQueue{{o.nodes[1], 0}}
+
+	for queue.Len() > 0 {
+		entry := heap.Pop(&queue).(octreeNodeEntry)
+
+		if entry.distance >= distance {
+			break
+		}
+
+		if entry.node.isLeaf {
+			for _, index := range entry.node.items {
+				if item, ok := o.items[index].(meshx.ClosestPoint); ok {
+					candidate := item.ClosestPoint(point)
+
+					if d := candidate.Sub(point).Mag(); d < distance {
+						nearest = index
+						closest = candidate
+						distance = d
+					}
+				}
+			}
+		} else {
+			for _, code := range entry.node.Children() {
+				node := o.nodes[code]
+				d := node.aabb.DistanceTo(point)
+
+				if d < distance {
+					heap.Push(&queue, octreeNodeEntry{node, d})
+				}
+			}
+		}
+	}
+
+	return nearest, closest
+}
+
+// Query the octree for every ray hit on the items sorted by distance. Only
+// items implementing the Raycast interface are considered. The index of each
+// hit is set to the index of the item.
+func (o *Octree) RaycastAll(ray meshx.Ray) []meshx.Hit {
+	var code uint64
+
+	query := ray.Precompute()
+	cache := make([]bool, o.GetNumberOfItems())
+	hits := make([]meshx.Hit, 0)
+	queue := make([]uint64, 1, 128)
+	queue[0] = 1
+
+	for len(queue) > 0 {
+		code, queue = queue[0], queue[1:]
+		node := o.nodes[code]
+
+		if query.IntersectsAABB(node.aabb) {
+			if node.isLeaf {
+				for _, index := range node.items {
+					if !cache[index] {
+						cache[index] = true
+
+						if item, ok := o.items[index].(meshx.Raycast); ok {
+							for _, hit := range item.Raycast(ray) {
+								hit.Index = index
+								hits = append(hits, hit)
+							}
+						}
+					}
+				}
+			} else {
+				queue = append(queue, node.Children()...)
+			}
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Distance == hits[j].Distance {
+			return hits[i].Index < hits[j].Index
+		}
+		return hits[i].Distance < hits[j].Distance
+	})
+
+	return hits
+}
+
+// Get an indexed item by index.
+func (o *Octree) GetItem(index int) meshx.IntersectsAABB {
+	return o.items[index]
+}
+
+// Get the bounding box of the root node.
+func (o *Octree) GetAABB() meshx.AABB {
+	return o.nodes[1].aabb
+}
+
 // Get the number of indexed items.
 func (o *Octree) GetNumberOfItems() int {
 	return len(o.items)
@@ -196,12 +452,94 @@ func (o *OctreeNode) Children() []uint64 {
 	return children
 }
 
+// Get the codes of the leaves an item intersects.
+func (o *Octree) getLeaves(item meshx.IntersectsAABB) []uint64 {
+	var code uint64
+
+	codes := make([]uint64, 0, 8)
+	queue := make([]uint64, 1, 128)
+	queue[0] = 1
+
+	for len(queue) > 0 {
+		code, queue = queue[0], queue[1:]
+		node := o.nodes[code]
+
+		if item.IntersectsAABB(node.aabb) {
+			if node.isLeaf {
+				codes = append(codes, code)
+			} else {
+				queue = append(queue, node.Children()...)
+			}
+		}
+	}
+
+	return codes
+}
+
+// Remove an indexed item from the leaves it was inserted into.
+func (o *Octree) removeFromLeaves(index int) {
+	for _, code := range o.getLeaves(o.items[index]) {
+		node := o.nodes[code]
+
+		if i := slices.Index(node.items, index); i >= 0 {
+			node.items = slices.Delete(node.items, i, i+1)
+		}
+	}
+}
+
 // Return true if the node can be split.
-func (o *OctreeNode) canSplit() bool {
-	return o.isLeaf && o.Depth() < OctreeMaxDepth
+func (o *Octree) canSplit(node *OctreeNode) bool {
+	return node.isLeaf && node.Depth() < o.options.MaxDepth
 }
 
 // Return true if the node should be split.
-func (o *OctreeNode) shouldSplit() bool {
-	return o.canSplit() && len(o.items) > OctreeMaxLeafItems
+func (o *Octree) shouldSplit(node *OctreeNode) bool {
+	if !o.canSplit(node) {
+		return false
+	}
+
+	switch o.options.SplitHeuristic {
+	case OctreeSplitByNodeSize:
+		size := 2 * max(node.aabb.HalfSize[0], node.aabb.HalfSize[1], node.aabb.HalfSize[2])
+		return len(node.items) > 0 && size > o.options.MinNodeSize
+	default:
+		return len(node.items) > o.options.MaxLeafItems
+	}
+}
+
+// Octree node with its distance to a query point.
+type octreeNodeEntry struct {
+	node     *OctreeNode
+	distance float64
+}
+
+// Priority queue of octree nodes ordered by distance.
+type octreeNodeQueue []octreeNodeEntry
+
+// Implement the heap.Interface interface.
+func (q octreeNodeQueue) Len() int {
+	return len(q)
+}
+
+// Implement the heap.Interface interface.
+func (q octreeNodeQueue) Less(i, j int) bool {
+	return q[i].distance < q[j].distance
+}
+
+// Implement the heap.Interface interface.
+func (q octreeNodeQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Implement the heap.Interface interface.
+func (q *octreeNodeQueue) Push(x any) {
+	*q = append(*q, x.(octreeNodeEntry))
+}
+
+// Implement the heap.Interface interface.
+func (q *octreeNodeQueue) Pop() any {
+	n := len(*q)
+	entry := (*q)[n-1]
+	*q = (*q)[:n-1]
+	return entry
 }
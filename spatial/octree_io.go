@@ -0,0 +1,271 @@
+package spatial
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Version 2 added the options to the header.
+const (
+	octreeMagic   = "MXOT"
+	octreeVersion = 2
+
+	// Size of the header and the smallest node record (without items) in
+	// bytes.
+	octreeHeaderSize = 44
+	octreeNodeSize   = 61
+
+	// Largest number of nodes allocated up front when the size of the input
+	// is unknown.
+	octreeMaxNodeHint = 1 << 16
+)
+
+var (
+	ErrOctreeInvalidFormat = errors.New("invalid octree format")
+	ErrOctreeItemMismatch  = errors.New("number of items does not match octree")
+)
+
+// Write the structure and options of the octree in a binary format. The
+// items are not written and must be provided in the same order when reading.
+func (o *Octree) Write(writer io.Writer) error {
+	buffer := bufio.NewWriter(writer)
+
+	codes := make([]uint64, 0, len(o.nodes))
+
+	for code := range o.nodes {
+		codes = append(codes, code)
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+
+	header := []any{
+		[4]byte([]byte(octreeMagic)),
+		uint32(octreeVersion),
+		uint64(len(o.items)),
+		uint64(len(o.nodes)),
+		uint32(o.options.MaxDepth),
+		uint32(o.options.MaxLeafItems),
+		uint32(o.options.SplitHeuristic),
+		o.options.MinNodeSize,
+	}
+
+	for _, value := range header {
+		if err := binary.Write(buffer, binary.LittleEndian, value); err != nil {
+			return err
+		}
+	}
+
+	for _, code := range codes {
+		node := o.nodes[code]
+		items := make([]uint32, len(node.items))
+
+		for i, index := range node.items {
+			items[i] = uint32(index)
+		}
+
+		var isLeaf uint8
+
+		if node.isLeaf {
+			isLeaf = 1
+		}
+
+		record := []any{
+			code,
+			[3]float64(node.aabb.Center),
+			[3]float64(node.aabb.HalfSize),
+			isLeaf,
+			uint32(len(items)),
+			items,
+		}
+
+		for _, value := range record {
+			if err := binary.Write(buffer, binary.LittleEndian, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return buffer.Flush()
+}
+
+// Write the structure of the octree to a file.
+func (o *Octree) WriteToPath(path string) error {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	if err := o.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Read the structure of an octree written by Octree.Write. The items must
+// be those indexed by the written octree, in the same order. A file of
+// another version or with invalid options is rejected.
+func ReadOctree(reader io.Reader, items []meshx.IntersectsAABB) (*Octree, error) {
+	size := int64(-1)
+
+	if sized, ok := reader.(interface{ Len() int }); ok {
+		size = int64(sized.Len())
+	}
+
+	return readOctree(reader, items, size)
+}
+
+// Read the structure of an octree from a file.
+func ReadOctreeFromPath(path string, items []meshx.IntersectsAABB) (*Octree, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return readOctree(file, items, info.Size())
+}
+
+// Read the structure of an octree of a size in bytes (negative if unknown).
+// The number of nodes of the header is bounded by the size before
+// allocating the nodes.
+func readOctree(reader io.Reader, items []meshx.IntersectsAABB, size int64) (*Octree, error) {
+	var magic [4]byte
+	var version uint32
+	var numberOfItems, numberOfNodes uint64
+	var maxDepth, maxLeafItems, splitHeuristic uint32
+	var minNodeSize float64
+
+	buffer := bufio.NewReader(reader)
+
+	header := []any{
+		&magic,
+		&version,
+		&numberOfItems,
+		&numberOfNodes,
+		&maxDepth,
+		&maxLeafItems,
+		&splitHeuristic,
+		&minNodeSize,
+	}
+
+	for _, value := range header {
+		if err := binary.Read(buffer, binary.LittleEndian, value); err != nil {
+			return nil, ErrOctreeInvalidFormat
+		}
+	}
+
+	if string(magic[:]) != octreeMagic || version != octreeVersion {
+		return nil, ErrOctreeInvalidFormat
+	}
+
+	if maxDepth == 0 || maxDepth > OctreeMaxDepth || maxLeafItems == 0 {
+		return nil, ErrOctreeInvalidFormat
+	}
+
+	switch OctreeSplitHeuristic(splitHeuristic) {
+	case OctreeSplitByItemCount, OctreeSplitByNodeSize:
+	default:
+		return nil, ErrOctreeInvalidFormat
+	}
+
+	if !(minNodeSize > 0) || math.IsInf(minNodeSize, 1) {
+		return nil, ErrOctreeInvalidFormat
+	}
+
+	if numberOfItems != uint64(len(items)) {
+		return nil, ErrOctreeItemMismatch
+	}
+
+	hint := min(numberOfNodes, octreeMaxNodeHint)
+
+	if size >= 0 {
+		if size < octreeHeaderSize || numberOfNodes > uint64(size-octreeHeaderSize)/octreeNodeSize {
+			return nil, ErrOctreeInvalidFormat
+		}
+
+		hint = numberOfNodes
+	}
+
+	octree := Octree{
+		nodes: make(map[uint64]*OctreeNode, hint),
+		items: items,
+		options: OctreeOptions{
+			MaxDepth:       int(maxDepth),
+			MaxLeafItems:   int(maxLeafItems),
+			SplitHeuristic: OctreeSplitHeuristic(splitHeuristic),
+			MinNodeSize:    minNodeSize,
+		},
+	}
+
+	for i := uint64(0); i < numberOfNodes; i++ {
+		var code uint64
+		var center, halfSize [3]float64
+		var isLeaf uint8
+		var numberOfNodeItems uint32
+
+		for _, value := range []any{&code, &center, &halfSize, &isLeaf, &numberOfNodeItems} {
+			if err := binary.Read(buffer, binary.LittleEndian, value); err != nil {
+				return nil, ErrOctreeInvalidFormat
+			}
+		}
+
+		if uint64(numberOfNodeItems) > numberOfItems {
+			return nil, ErrOctreeInvalidFormat
+		}
+
+		nodeItems := make([]uint32, numberOfNodeItems)
+
+		if err := binary.Read(buffer, binary.LittleEndian, nodeItems); err != nil {
+			return nil, ErrOctreeInvalidFormat
+		}
+
+		node := NewOctreeNode(code, meshx.NewAABB(center, halfSize))
+		node.isLeaf = isLeaf == 1
+
+		for _, index := range nodeItems {
+			if uint64(index) >= numberOfItems {
+				return nil, ErrOctreeInvalidFormat
+			}
+
+			node.items = append(node.items, int(index))
+		}
+
+		octree.nodes[code] = node
+	}
+
+	if octree.nodes[1] == nil {
+		return nil, ErrOctreeInvalidFormat
+	}
+
+	for _, node := range octree.nodes {
+		if !node.isLeaf {
+			for _, code := range node.Children() {
+				if octree.nodes[code] == nil {
+					return nil, ErrOctreeInvalidFormat
+				}
+			}
+		}
+	}
+
+	return &octree, nil
+}
@@ -0,0 +1,157 @@
+package spatial
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Get the triangles as octree items.
+func newOctreeTestItems(triangles []meshx.Triangle) []meshx.IntersectsAABB {
+	items := make([]meshx.IntersectsAABB, len(triangles))
+
+	for i, triangle := range triangles {
+		items[i] = triangle
+	}
+
+	return items
+}
+
+// Test writing and reading an octree in memory.
+func TestOctreeWriteRead(t *testing.T) {
+	triangles := newOctreeTestTriangles(32)
+	octree := newTriangleOctree(triangles)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, octree.Write(&buffer))
+
+	result, err := ReadOctree(&buffer, newOctreeTestItems(triangles))
+	assert.Empty(t, err)
+	assert.Equal(t, octree.GetNumberOfNodes(), result.GetNumberOfNodes())
+	assert.Equal(t, octree.GetNumberOfItems(), result.GetNumberOfItems())
+	assert.Equal(t, octree.GetAABB(), result.GetAABB())
+
+	query := meshx.NewAABB(meshx.NewVector(0.3, 0.6, 0), meshx.NewVector(0.1, 0.05, 0.1))
+	expected := octree.Query(query)
+	items := result.Query(query)
+	sort.Ints(expected)
+	sort.Ints(items)
+
+	assert.NotEmpty(t, expected)
+	assert.Equal(t, expected, items)
+}
+
+// Test building an octree is reproducible.
+func TestOctreeWriteReproducible(t *testing.T) {
+	triangles := newOctreeTestTriangles(32)
+
+	var expected bytes.Buffer
+	assert.Empty(t, newTriangleOctree(triangles).Write(&expected))
+
+	for range 3 {
+		var buffer bytes.Buffer
+		assert.Empty(t, newTriangleOctree(triangles).Write(&buffer))
+		assert.Equal(t, expected.Bytes(), buffer.Bytes())
+	}
+}
+
+// Test writing and reading an octree from a file.
+func TestOctreeWriteReadPath(t *testing.T) {
+	triangles := newOctreeTestTriangles(16)
+	octree := newTriangleOctree(triangles)
+	path := filepath.Join(t.TempDir(), "octree.bin")
+
+	assert.Empty(t, octree.WriteToPath(path))
+
+	result, err := ReadOctreeFromPath(path, newOctreeTestItems(triangles))
+	assert.Empty(t, err)
+	assert.Equal(t, octree.GetNumberOfNodes(), result.GetNumberOfNodes())
+
+	point := meshx.NewVector(0.4, 0.2, 0.3)
+	expected, _ := octree.QueryNearest(point)
+	index, _ := result.QueryNearest(point)
+	assert.Equal(t, expected, index)
+}
+
+// Test reading an invalid octree.
+func TestReadOctreeInvalid(t *testing.T) {
+	triangles := newOctreeTestTriangles(4)
+	octree := newTriangleOctree(triangles)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, octree.Write(&buffer))
+	data := buffer.Bytes()
+
+	_, err := ReadOctree(bytes.NewReader(data), newOctreeTestItems(triangles[1:]))
+	assert.ErrorIs(t, err, ErrOctreeItemMismatch)
+
+	_, err = ReadOctree(bytes.NewReader(data[:len(data)-4]), newOctreeTestItems(triangles))
+	assert.ErrorIs(t, err, ErrOctreeInvalidFormat)
+
+	_, err = ReadOctree(bytes.NewReader([]byte("MXOB")), newOctreeTestItems(triangles))
+	assert.ErrorIs(t, err, ErrOctreeInvalidFormat)
+
+	// Header fields of another version (1), with more nodes than the data
+	// holds or with invalid options: the maximum depth, maximum leaf items,
+	// split heuristic and minimum node size.
+	invalid := []struct {
+		offset int
+		value  any
+	}{
+		{4, uint32(1)},
+		{16, uint64(math.MaxUint64)},
+		{16, uint64(len(data))},
+		{24, uint32(0)},
+		{24, uint32(OctreeMaxDepth + 1)},
+		{28, uint32(0)},
+		{32, uint32(2)},
+		{36, 0.0},
+		{36, math.NaN()},
+		{36, math.Inf(1)},
+	}
+
+	for _, field := range invalid {
+		var value bytes.Buffer
+		assert.Empty(t, binary.Write(&value, binary.LittleEndian, field.value))
+
+		corrupt := append([]byte(nil), data...)
+		copy(corrupt[field.offset:], value.Bytes())
+
+		_, err = ReadOctree(bytes.NewReader(corrupt), newOctreeTestItems(triangles))
+		assert.ErrorIs(t, err, ErrOctreeInvalidFormat)
+
+		path := filepath.Join(t.TempDir(), "octree.bin")
+		assert.Empty(t, os.WriteFile(path, corrupt, 0644))
+
+		_, err = ReadOctreeFromPath(path, newOctreeTestItems(triangles))
+		assert.ErrorIs(t, err, ErrOctreeInvalidFormat)
+	}
+}
+
+// Test the octree options are preserved when writing and reading.
+func TestOctreeWriteReadOptions(t *testing.T) {
+	triangles := newOctreeTestTriangles(8)
+	options := OctreeOptions{
+		MaxDepth:       4,
+		MaxLeafItems:   8,
+		SplitHeuristic: OctreeSplitByNodeSize,
+		MinNodeSize:    0.3,
+	}
+
+	octree := newTriangleOctreeWithOptions(triangles, options)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, octree.Write(&buffer))
+
+	result, err := ReadOctree(&buffer, newOctreeTestItems(triangles))
+	assert.Empty(t, err)
+	assert.Equal(t, options, result.GetOptions())
+	assert.Equal(t, octree.GetNumberOfNodes(), result.GetNumberOfNodes())
+}
@@ -0,0 +1,260 @@
+package spatial
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate a grid of triangles on the plane z = 0 within the unit square.
+func newOctreeTestTriangles(n int) []meshx.Triangle {
+	triangles := make([]meshx.Triangle, 0, 2*n*n)
+	h := 1 / float64(n)
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			p := meshx.NewVector(float64(i)*h, float64(j)*h, 0)
+			q := p.Add(meshx.NewVector(h, 0, 0))
+			r := p.Add(meshx.NewVector(h, h, 0))
+			s := p.Add(meshx.NewVector(0, h, 0))
+			triangles = append(triangles, meshx.NewTriangle(p, q, r), meshx.NewTriangle(p, r, s))
+		}
+	}
+
+	return triangles
+}
+
+// Test querying the octree with a cylinder, capsule, sphere, and polytope.
+func TestOctreeQueryCylinder(t *testing.T) {
+	triangles := newOctreeTestTriangles(32)
+	octree := newTriangleOctree(triangles)
+
+	queries := []meshx.IntersectsAABB{
+		meshx.NewCylinder(meshx.NewVector(0.5, 0.5, -1), meshx.NewVector(0.5, 0.5, 1), 0.1),
+		meshx.NewCapsule(meshx.NewVector(0.2, 0.2, 0.05), meshx.NewVector(0.8, 0.3, 0.05), 0.1),
+		meshx.NewSphere(meshx.NewVector(0.3, 0.6, 0), 0.15),
+		meshx.NewPolytope(
+			meshx.NewHalfSpace(meshx.NewVector(0.5, 0.5, 0), meshx.NewVector(1, 1, 0)),
+			meshx.NewHalfSpace(meshx.NewVector(0.4, 0.4, 0), meshx.NewVector(-1, -1, 0)),
+		),
+	}
+
+	for _, query := range queries {
+		expected := make([]int, 0)
+
+		for i, triangle := range triangles {
+			if query.(meshx.IntersectsTriangle).IntersectsTriangle(triangle) {
+				expected = append(expected, i)
+			}
+		}
+
+		items := octree.Query(query)
+		sort.Ints(items)
+
+		assert.NotEmpty(t, expected)
+		assert.Equal(t, expected, items)
+	}
+}
+
+// Test querying the nearest item of the octree.
+func TestOctreeQueryNearest(t *testing.T) {
+	triangles := newOctreeTestTriangles(16)
+	octree := newTriangleOctree(triangles)
+
+	index, closest := octree.QueryNearest(meshx.NewVector(0.3, 0.7, 2))
+	assert.NotEqual(t, -1, index)
+	assert.InDelta(t, 0.0, closest.Sub(meshx.NewVector(0.3, 0.7, 0)).Mag(), 1e-12)
+}
+
+// Test casting a ray through stacked layers of triangles.
+func TestOctreeRaycastAll(t *testing.T) {
+	triangles := newOctreeTestTriangles(8)
+	layers := make([]meshx.Triangle, 0, 3*len(triangles))
+
+	for _, z := range []float64{0, 0.5, 0.25} {
+		offset := meshx.NewVector(0, 0, z)
+
+		for _, triangle := range triangles {
+			layers = append(layers, meshx.NewTriangle(
+				triangle.P.Add(offset),
+				triangle.Q.Add(offset),
+				triangle.R.Add(offset),
+			))
+		}
+	}
+
+	octree := newTriangleOctree(layers)
+
+	// The ray passes through the shared diagonal of two triangles in each
+	// layer and must hit only one of them.
+	ray := meshx.NewRay(meshx.NewVector(0.0625, 0.0625, 1), meshx.NewVector(0, 0, -1))
+	hits := octree.RaycastAll(ray)
+
+	assert.Equal(t, 3, len(hits))
+	assert.InDelta(t, 0.5, hits[0].Distance, 1e-12)
+	assert.InDelta(t, 0.75, hits[1].Distance, 1e-12)
+	assert.InDelta(t, 1, hits[2].Distance, 1e-12)
+
+	for i, z := range []float64{0.5, 0.25, 0} {
+		assert.InDelta(t, z, hits[i].Point[2], 1e-12)
+		assert.True(t, layers[hits[i].Index].Raycast(ray) != nil)
+		assert.True(t, hits[i].Entering)
+	}
+
+	ray = meshx.NewRay(meshx.NewVector(0.3, 0.7, -1), meshx.NewVector(0, 0, 1))
+	hits = octree.RaycastAll(ray)
+
+	assert.Equal(t, 3, len(hits))
+	assert.False(t, hits[0].Entering)
+
+	ray = meshx.NewRay(meshx.NewVector(2, 2, -1), meshx.NewVector(0, 0, 1))
+	assert.Empty(t, octree.RaycastAll(ray))
+}
+
+// Build an octree of triangles with options.
+func newTriangleOctreeWithOptions(triangles []meshx.Triangle, options OctreeOptions) *Octree {
+	aabb := meshx.NewAABB(meshx.NewVector(0.5, 0.5, 0), meshx.NewVector(0.5, 0.5, 0.5)).Buffer(0.01)
+	octree := NewOctreeWithOptions(aabb, options)
+
+	for _, triangle := range triangles {
+		octree.Insert(triangle)
+	}
+
+	return octree
+}
+
+// Test the default octree options.
+func TestNewOctreeDefaultOptions(t *testing.T) {
+	aabb := meshx.NewAABB(meshx.NewVector(0, 0, 0), meshx.NewVector(32, 1, 1))
+	options := NewOctree(aabb).GetOptions()
+
+	assert.Equal(t, OctreeMaxDepth, options.MaxDepth)
+	assert.Equal(t, OctreeMaxLeafItems, options.MaxLeafItems)
+	assert.Equal(t, OctreeSplitByItemCount, options.SplitHeuristic)
+	assert.Equal(t, 1.0, options.MinNodeSize)
+}
+
+// Test building octrees with options yields the same query results.
+func TestOctreeOptions(t *testing.T) {
+	triangles := newOctreeTestTriangles(16)
+	reference := newTriangleOctreeWithOptions(triangles, OctreeOptions{})
+
+	cases := []OctreeOptions{
+		{MaxLeafItems: 4},
+		{MaxDepth: 1, MaxLeafItems: 4},
+		{SplitHeuristic: OctreeSplitByNodeSize, MinNodeSize: 0.2},
+	}
+
+	query := meshx.NewAABB(meshx.NewVector(0.3, 0.6, 0), meshx.NewVector(0.1, 0.05, 0.1))
+	expected := reference.Query(query)
+	sort.Ints(expected)
+
+	for _, options := range cases {
+		octree := newTriangleOctreeWithOptions(triangles, options)
+		items := octree.Query(query)
+		sort.Ints(items)
+
+		assert.Equal(t, expected, items)
+
+		if options.MaxDepth == 0 {
+			assert.Greater(t, octree.GetNumberOfNodes(), reference.GetNumberOfNodes())
+		}
+
+		for _, node := range octree.nodes {
+			assert.LessOrEqual(t, node.Depth(), octree.GetOptions().MaxDepth)
+
+			if node.isLeaf && options.SplitHeuristic == OctreeSplitByItemCount && node.Depth() < octree.GetOptions().MaxDepth {
+				assert.LessOrEqual(t, len(node.items), options.MaxLeafItems)
+			}
+
+			if node.isLeaf && len(node.items) > 0 && options.SplitHeuristic == OctreeSplitByNodeSize {
+				assert.LessOrEqual(t, 2*node.aabb.HalfSize[0], options.MinNodeSize)
+			}
+		}
+	}
+
+	octree := newTriangleOctreeWithOptions(triangles, OctreeOptions{MaxDepth: 1, MaxLeafItems: 4})
+	assert.Equal(t, 9, octree.GetNumberOfNodes())
+}
+
+// Translate the triangles in place.
+func translateOctreeTestTriangles(triangles []meshx.Triangle, offset meshx.Vector) {
+	for i, triangle := range triangles {
+		triangles[i] = meshx.NewTriangle(triangle.P.Add(offset), triangle.Q.Add(offset), triangle.R.Add(offset))
+	}
+}
+
+// Query the items intersecting a box by brute force.
+func queryOctreeTestTriangles(triangles []meshx.Triangle, query meshx.AABB) []int {
+	items := make([]int, 0)
+
+	for i, triangle := range triangles {
+		if triangle.IntersectsAABB(query) {
+			items = append(items, i)
+		}
+	}
+
+	return items
+}
+
+// Test refitting the octree to moved items.
+func TestOctreeRefit(t *testing.T) {
+	triangles := newOctreeTestTriangles(16)
+	octree := newTriangleOctree(triangles)
+	nodes := octree.GetNumberOfNodes()
+
+	center := meshx.NewVector(0.5, 0.5, 0)
+
+	for i, triangle := range triangles {
+		p := triangle.P.Sub(center).MulScalar(0.8).Add(center)
+		q := triangle.Q.Sub(center).MulScalar(0.8).Add(center)
+		r := triangle.R.Sub(center).MulScalar(0.8).Add(center)
+		triangles[i] = meshx.NewTriangle(p, q, r)
+	}
+
+	assert.Empty(t, octree.Refit(newOctreeTestItems(triangles)))
+	assert.Equal(t, nodes, octree.GetNumberOfNodes())
+
+	query := meshx.NewAABB(meshx.NewVector(0.8, 0.5, 0), meshx.NewVector(0.1, 0.1, 0.1))
+	items := octree.Query(query)
+	sort.Ints(items)
+	assert.NotEmpty(t, items)
+	assert.Equal(t, queryOctreeTestTriangles(triangles, query), items)
+
+	translateOctreeTestTriangles(triangles, meshx.NewVector(10, 0, 0))
+	assert.ErrorIs(t, octree.Refit(newOctreeTestItems(triangles)), ErrOctreeItemNotInserted)
+	assert.ErrorIs(t, octree.Refit(newOctreeTestItems(triangles[1:])), ErrOctreeItemCount)
+}
+
+// Test updating and removing items of the octree.
+func TestOctreeUpdateRemove(t *testing.T) {
+	triangles := newOctreeTestTriangles(16)
+	octree := newTriangleOctree(triangles)
+	query := meshx.NewAABB(meshx.NewVector(0.5, 0.5, 0), meshx.NewVector(0.1, 0.1, 0.1))
+
+	moved := triangles[0]
+	translateOctreeTestTriangles(triangles[:1], meshx.NewVector(0.5, 0.5, 0))
+	assert.Empty(t, octree.Update(0, triangles[0]))
+	assert.Contains(t, octree.Query(query), 0)
+
+	outside := meshx.NewTriangle(moved.P.AddScalar(10), moved.Q.AddScalar(10), moved.R.AddScalar(10))
+	assert.ErrorIs(t, octree.Update(0, outside), ErrOctreeItemNotInserted)
+	assert.Equal(t, triangles[0], octree.GetItem(0))
+
+	for _, index := range []int{5, 0, 3} {
+		octree.Remove(index)
+		last := len(triangles) - 1
+		triangles[index] = triangles[last]
+		triangles = triangles[:last]
+	}
+
+	assert.Equal(t, len(triangles), octree.GetNumberOfItems())
+
+	for _, query := range []meshx.AABB{query, meshx.NewAABB(meshx.NewVector(0.95, 0.95, 0), meshx.NewVector(0.1, 0.1, 0.1))} {
+		items := octree.Query(query)
+		sort.Ints(items)
+		assert.Equal(t, queryOctreeTestTriangles(triangles, query), items)
+	}
+}
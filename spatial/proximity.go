@@ -0,0 +1,206 @@
+package spatial
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const ProximityTreeMaxLeafItems = 4
+
+// Closest pair of points between two triangulated surfaces.
+type Proximity struct {
+	// Distance between the points (zero if the surfaces intersect).
+	Distance float64
+
+	// Closest points on each surface.
+	PointA meshx.Vector
+	PointB meshx.Vector
+
+	// Indices of the triangles of the closest points (-1 if either surface
+	// is empty).
+	IndexA int
+	IndexB int
+}
+
+// Compute the closest pair of points between two triangulated surfaces by a
+// branch-and-bound search over a bounding volume hierarchy of each. The
+// distance is +Inf if either surface is empty. Intersecting surfaces have a
+// distance of zero at some (not necessarily every) point of intersection.
+func MinimumDistance(a, b []meshx.Triangle) Proximity {
+	proximity := Proximity{Distance: math.Inf(1), IndexA: -1, IndexB: -1}
+
+	if len(a) == 0 || len(b) == 0 {
+		return proximity
+	}
+
+	return minimumDistance(newProximityTree(a), newProximityTree(b))
+}
+
+// Compute the closest pair of points between the triangles of two proximity
+// trees.
+func minimumDistance(treeA, treeB *proximityTree) Proximity {
+	a, b := treeA.triangles, treeB.triangles
+	proximity := Proximity{Distance: math.Inf(1), IndexA: -1, IndexB: -1}
+	queue := proximityQueue{{0, 0, treeA.nodes[0].aabb.DistanceToAABB(treeB.nodes[0].aabb)}}
+
+	for queue.Len() > 0 {
+		entry := heap.Pop(&queue).(proximityEntry)
+
+		if entry.distance >= proximity.Distance {
+			break
+		}
+
+		nodeA := &treeA.nodes[entry.a]
+		nodeB := &treeB.nodes[entry.b]
+
+		if nodeA.isLeaf && nodeB.isLeaf {
+			for _, i := range treeA.order[nodeA.start:nodeA.end] {
+				for _, j := range treeB.order[nodeB.start:nodeB.end] {
+					p, q := a[i].ClosestPoints(b[j])
+
+					if d := p.Sub(q).Mag(); d < proximity.Distance {
+						proximity = Proximity{d, p, q, i, j}
+					}
+				}
+			}
+
+			continue
+		}
+
+		// Descend into the larger node (or the one that is not a leaf).
+		if nodeB.isLeaf || (!nodeA.isLeaf && nodeA.aabb.HalfSize.Mag() >= nodeB.aabb.HalfSize.Mag()) {
+			for _, child := range nodeA.children {
+				d := treeA.nodes[child].aabb.DistanceToAABB(nodeB.aabb)
+
+				if d < proximity.Distance {
+					heap.Push(&queue, proximityEntry{child, entry.b, d})
+				}
+			}
+		} else {
+			for _, child := range nodeB.children {
+				d := nodeA.aabb.DistanceToAABB(treeB.nodes[child].aabb)
+
+				if d < proximity.Distance {
+					heap.Push(&queue, proximityEntry{entry.a, child, d})
+				}
+			}
+		}
+	}
+
+	return proximity
+}
+
+// Bounding volume hierarchy of triangles split at the median centroid along
+// the longest axis of each node.
+type proximityTree struct {
+	triangles []meshx.Triangle
+	order     []int
+	nodes     []proximityNode
+}
+
+type proximityNode struct {
+	start    int
+	end      int
+	children [2]int
+	aabb     meshx.AABB
+	isLeaf   bool
+}
+
+// Construct a proximityTree from a (non-empty) set of triangles.
+func newProximityTree(triangles []meshx.Triangle) *proximityTree {
+	tree := &proximityTree{
+		triangles: triangles,
+		order:     make([]int, len(triangles)),
+		nodes:     make([]proximityNode, 0),
+	}
+
+	for i := range tree.order {
+		tree.order[i] = i
+	}
+
+	tree.build(0, len(triangles))
+
+	return tree
+}
+
+// Build the node for the triangles in the range [start, end) and return its
+// index.
+func (t *proximityTree) build(start, end int) int {
+	points := make([]meshx.Vector, 0, 3*(end-start))
+
+	for _, i := range t.order[start:end] {
+		points = append(points, t.triangles[i].P, t.triangles[i].Q, t.triangles[i].R)
+	}
+
+	aabb := meshx.NewAABBFromVectors(points)
+	index := len(t.nodes)
+	t.nodes = append(t.nodes, proximityNode{start: start, end: end, aabb: aabb})
+
+	if end-start <= ProximityTreeMaxLeafItems {
+		t.nodes[index].isLeaf = true
+		return index
+	}
+
+	axis := 0
+
+	for i := 1; i < 3; i++ {
+		if aabb.HalfSize[i] > aabb.HalfSize[axis] {
+			axis = i
+		}
+	}
+
+	items := t.order[start:end]
+
+	sort.Slice(items, func(i, j int) bool {
+		return t.triangles[items[i]].Centroid()[axis] < t.triangles[items[j]].Centroid()[axis]
+	})
+
+	mid := (start + end) / 2
+	left := t.build(start, mid)
+	right := t.build(mid, end)
+	t.nodes[index].children = [2]int{left, right}
+
+	return index
+}
+
+// Pair of nodes of two proximity trees with the distance between their
+// bounding boxes.
+type proximityEntry struct {
+	a        int
+	b        int
+	distance float64
+}
+
+// Priority queue of node pairs ordered by distance.
+type proximityQueue []proximityEntry
+
+// Implement the heap.Interface interface.
+func (q proximityQueue) Len() int {
+	return len(q)
+}
+
+// Implement the heap.Interface interface.
+func (q proximityQueue) Less(i, j int) bool {
+	return q[i].distance < q[j].distance
+}
+
+// Implement the heap.Interface interface.
+func (q proximityQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+// Implement the heap.Interface interface.
+func (q *proximityQueue) Push(x any) {
+	*q = append(*q, x.(proximityEntry))
+}
+
+// Implement the heap.Interface interface.
+func (q *proximityQueue) Pop() any {
+	n := len(*q)
+	entry := (*q)[n-1]
+	*q = (*q)[:n-1]
+	return entry
+}
@@ -0,0 +1,54 @@
+package spatial
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Translate triangles into a new slice.
+func translateTriangles(triangles []meshx.Triangle, offset meshx.Vector) []meshx.Triangle {
+	translated := make([]meshx.Triangle, len(triangles))
+
+	for i, triangle := range triangles {
+		translated[i] = meshx.NewTriangle(triangle.P.Add(offset), triangle.Q.Add(offset), triangle.R.Add(offset))
+	}
+
+	return translated
+}
+
+// Test the minimum distance between two spheres against brute force.
+func TestMinimumDistance(t *testing.T) {
+	a := newWindingTestSphere(12)
+	b := translateTriangles(newWindingTestSphere(8), meshx.NewVector(2.5, 0.3, -0.2))
+
+	expected := math.Inf(1)
+
+	for _, p := range a {
+		for _, q := range b {
+			expected = min(expected, p.DistanceToTriangle(q))
+		}
+	}
+
+	proximity := MinimumDistance(a, b)
+	assert.InDelta(t, expected, proximity.Distance, 1e-12)
+	assert.InDelta(t, proximity.Distance, proximity.PointA.Sub(proximity.PointB).Mag(), 1e-12)
+	assert.InDelta(t, 0.0, a[proximity.IndexA].DistanceTo(proximity.PointA), 1e-12)
+	assert.InDelta(t, 0.0, b[proximity.IndexB].DistanceTo(proximity.PointB), 1e-12)
+	assert.InDelta(t, 0.5, proximity.Distance, 0.1)
+}
+
+// Test the minimum distance of intersecting and empty surfaces.
+func TestMinimumDistanceIntersecting(t *testing.T) {
+	a := newWindingTestSphere(8)
+	b := translateTriangles(a, meshx.NewVector(1, 0, 0))
+
+	proximity := MinimumDistance(a, b)
+	assert.Equal(t, 0.0, proximity.Distance)
+
+	proximity = MinimumDistance(a, nil)
+	assert.True(t, math.IsInf(proximity.Distance, 1))
+	assert.Equal(t, -1, proximity.IndexA)
+}
@@ -0,0 +1,123 @@
+package spatial
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	// Default contact tolerance relative to the size of the surfaces.
+	SweepTolerance = 1e-6
+
+	SweepMaxIterations = 1000
+)
+
+var (
+	ErrSweepTriangleCount = errors.New("number of start and end triangles does not match")
+	ErrSweepNotConverged  = errors.New("sweep did not converge")
+)
+
+// Options for sweeping a surface. Zero values use the defaults.
+type SweepOptions struct {
+	// Distance at which the surfaces are in contact. The default is
+	// SweepTolerance times the diagonal of the bounding box of the surfaces.
+	Tolerance float64
+
+	// Maximum number of steps along the path.
+	MaxIterations int
+}
+
+// Contact of a moving surface with another surface.
+type Contact struct {
+	// True if the surfaces come into contact along the path.
+	Collides bool
+
+	// Earliest parameter of contact along the path in [0, 1] (1 if the
+	// surfaces do not collide).
+	T float64
+
+	// Closest points of the moving surface (a) at T and the other surface (b).
+	Proximity
+}
+
+// Find the earliest contact of a surface moving along a path with another
+// (static) surface. The vertices of the moving triangles move linearly from
+// the start to the end triangles (in correspondence), e.g. from one rigid
+// transform of a part to another. The path is advanced conservatively by
+// the clearance divided by the largest vertex speed, so no contact is missed.
+func SweepContact(start, end, obstacle []meshx.Triangle, options SweepOptions) (Contact, error) {
+	contact := Contact{T: 1, Proximity: Proximity{Distance: math.Inf(1), IndexA: -1, IndexB: -1}}
+
+	if len(start) != len(end) {
+		return contact, ErrSweepTriangleCount
+	}
+
+	if len(start) == 0 || len(obstacle) == 0 {
+		return contact, nil
+	}
+
+	if options.MaxIterations <= 0 {
+		options.MaxIterations = SweepMaxIterations
+	}
+
+	if options.Tolerance <= 0 {
+		points := make([]meshx.Vector, 0, 3*(2*len(start)+len(obstacle)))
+
+		for _, triangles := range [][]meshx.Triangle{start, end, obstacle} {
+			for _, triangle := range triangles {
+				points = append(points, triangle.P, triangle.Q, triangle.R)
+			}
+		}
+
+		aabb := meshx.NewAABBFromVectors(points)
+		options.Tolerance = SweepTolerance * 2 * aabb.HalfSize.Mag()
+	}
+
+	var speed float64
+
+	for i := range start {
+		speed = max(speed, end[i].P.Sub(start[i].P).Mag(), end[i].Q.Sub(start[i].Q).Mag(), end[i].R.Sub(start[i].R).Mag())
+	}
+
+	tree := newProximityTree(obstacle)
+	moving := make([]meshx.Triangle, len(start))
+	t := 0.0
+
+	for range options.MaxIterations {
+		for i := range moving {
+			moving[i] = interpolateTriangle(start[i], end[i], t)
+		}
+
+		contact.T = t
+		contact.Proximity = minimumDistance(newProximityTree(moving), tree)
+
+		if contact.Distance <= options.Tolerance {
+			contact.Collides = true
+			return contact, nil
+		}
+
+		if speed == 0 {
+			contact.T = 1
+			return contact, nil
+		}
+
+		if t == 1 {
+			return contact, nil
+		}
+
+		t = min(1, t+(contact.Distance-options.Tolerance/2)/speed)
+	}
+
+	return contact, ErrSweepNotConverged
+}
+
+// Interpolate the vertices of two triangles linearly.
+func interpolateTriangle(a, b meshx.Triangle, t float64) meshx.Triangle {
+	return meshx.NewTriangle(
+		a.P.Add(b.P.Sub(a.P).MulScalar(t)),
+		a.Q.Add(b.Q.Sub(a.Q).MulScalar(t)),
+		a.R.Add(b.R.Sub(a.R).MulScalar(t)),
+	)
+}
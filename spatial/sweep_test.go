@@ -0,0 +1,52 @@
+package spatial
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test sweeping a sphere into another sphere.
+func TestSweepContact(t *testing.T) {
+	obstacle := newWindingTestSphere(8)
+	start := translateTriangles(newWindingTestSphere(8), meshx.NewVector(-4, 0, 0))
+	end := translateTriangles(start, meshx.NewVector(8, 0, 0))
+
+	contact, err := SweepContact(start, end, obstacle, SweepOptions{})
+	assert.Empty(t, err)
+	assert.True(t, contact.Collides)
+	assert.InDelta(t, 0.25, contact.T, 0.01)
+	assert.Less(t, contact.Distance, 1e-5)
+
+	// The surfaces are apart just before the contact.
+	moving := make([]meshx.Triangle, len(start))
+
+	for i := range moving {
+		moving[i] = interpolateTriangle(start[i], end[i], contact.T-1e-3)
+	}
+
+	assert.Greater(t, MinimumDistance(moving, obstacle).Distance, 0.0)
+}
+
+// Test sweeping a sphere past another sphere.
+func TestSweepContactMiss(t *testing.T) {
+	obstacle := newWindingTestSphere(8)
+	start := translateTriangles(obstacle, meshx.NewVector(-4, 2.5, 0))
+	end := translateTriangles(start, meshx.NewVector(8, 0, 0))
+
+	contact, err := SweepContact(start, end, obstacle, SweepOptions{})
+	assert.Empty(t, err)
+	assert.False(t, contact.Collides)
+	assert.Equal(t, 1.0, contact.T)
+	assert.InDelta(t, math.Hypot(4, 2.5)-2, contact.Distance, 0.1)
+
+	_, err = SweepContact(start, end[1:], obstacle, SweepOptions{})
+	assert.ErrorIs(t, err, ErrSweepTriangleCount)
+
+	contact, err = SweepContact(start, start, obstacle, SweepOptions{})
+	assert.Empty(t, err)
+	assert.False(t, contact.Collides)
+	assert.Equal(t, 1.0, contact.T)
+}
@@ -0,0 +1,188 @@
+package spatial
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	WindingNumberTreeMaxLeafItems = 8
+	WindingNumberTreeAccuracy     = 2.0
+)
+
+var (
+	ErrWindingNumberTreeCount = errors.New("number of triangles does not match")
+)
+
+// Bounding volume hierarchy for the fast approximation of the generalized
+// winding number (Barill et al.). Distant clusters of triangles are
+// approximated by their area-weighted dipole.
+type WindingNumberTree struct {
+	triangles []meshx.Triangle
+	order     []int
+	nodes     []windingNumberNode
+	accuracy  float64
+}
+
+type windingNumberNode struct {
+	start    int
+	end      int
+	children [2]int
+	center   meshx.Vector
+	normal   meshx.Vector
+	radius   float64
+	isLeaf   bool
+}
+
+// Construct a WindingNumberTree from a set of triangles.
+func NewWindingNumberTree(triangles []meshx.Triangle) *WindingNumberTree {
+	tree := &WindingNumberTree{
+		triangles: make([]meshx.Triangle, len(triangles)),
+		order:     make([]int, len(triangles)),
+		nodes:     make([]windingNumberNode, 0),
+		accuracy:  WindingNumberTreeAccuracy,
+	}
+
+	copy(tree.triangles, triangles)
+
+	for i := range tree.order {
+		tree.order[i] = i
+	}
+
+	if len(triangles) > 0 {
+		tree.build(0, len(triangles))
+	}
+
+	return tree
+}
+
+// Set the accuracy parameter. A cluster is approximated when the query point
+// is further than accuracy times the cluster radius from its center. Larger
+// values are more accurate and slower.
+func (t *WindingNumberTree) SetAccuracy(accuracy float64) {
+	t.accuracy = accuracy
+}
+
+// Replace the triangles (e.g. after the vertices of a mesh moved) and update
+// the dipoles and radii of the nodes without rebuilding the hierarchy. The
+// approximation remains correct but slows down if the triangles are moved
+// far. The tree is unchanged if the number of triangles differs.
+func (t *WindingNumberTree) Refit(triangles []meshx.Triangle) error {
+	if len(triangles) != len(t.triangles) {
+		return ErrWindingNumberTreeCount
+	}
+
+	copy(t.triangles, triangles)
+
+	for i := range t.nodes {
+		t.fit(&t.nodes[i])
+	}
+
+	return nil
+}
+
+// Build the node for the triangles in the range [start, end) and return its
+// index.
+func (t *WindingNumberTree) build(start, end int) int {
+	node := windingNumberNode{start: start, end: end}
+	aabb := t.fit(&node)
+	index := len(t.nodes)
+	t.nodes = append(t.nodes, node)
+
+	if end-start <= WindingNumberTreeMaxLeafItems {
+		t.nodes[index].isLeaf = true
+		return index
+	}
+
+	axis := 0
+
+	for i := 1; i < 3; i++ {
+		if aabb.HalfSize[i] > aabb.HalfSize[axis] {
+			axis = i
+		}
+	}
+
+	items := t.order[start:end]
+
+	sort.Slice(items, func(i, j int) bool {
+		return t.triangles[items[i]].Centroid()[axis] < t.triangles[items[j]].Centroid()[axis]
+	})
+
+	mid := (start + end) / 2
+	left := t.build(start, mid)
+	right := t.build(mid, end)
+	t.nodes[index].children = [2]int{left, right}
+
+	return index
+}
+
+// Evaluate the approximate generalized winding number at a point.
+func (t *WindingNumberTree) Evaluate(point meshx.Vector) float64 {
+	var solidAngle float64
+
+	if len(t.nodes) == 0 {
+		return 0
+	}
+
+	stack := make([]int, 1, 64)
+	stack[0] = 0
+
+	for n := len(stack); n > 0; n = len(stack) {
+		var index int
+		index, stack = stack[n-1], stack[:n-1]
+		node := &t.nodes[index]
+
+		r := node.center.Sub(point)
+		distance := r.Mag()
+
+		if distance > t.accuracy*node.radius {
+			solidAngle += node.normal.Dot(r) / (distance * distance * distance)
+		} else if node.isLeaf {
+			for _, i := range t.order[node.start:node.end] {
+				solidAngle += t.triangles[i].SolidAngle(point)
+			}
+		} else {
+			stack = append(stack, node.children[0], node.children[1])
+		}
+	}
+
+	return solidAngle / (4 * math.Pi)
+}
+
+// Compute the dipole and radius of a node from its triangles. The bounding
+// box of the triangles is returned.
+func (t *WindingNumberTree) fit(node *windingNumberNode) meshx.AABB {
+	var area float64
+
+	node.center = meshx.Vector{}
+	node.normal = meshx.Vector{}
+	node.radius = 0
+
+	points := make([]meshx.Vector, 0, 3*(node.end-node.start))
+
+	for _, i := range t.order[node.start:node.end] {
+		triangle := t.triangles[i]
+		a := triangle.Area()
+		area += a
+		node.normal = node.normal.Add(triangle.Normal().MulScalar(0.5))
+		node.center = node.center.Add(triangle.Centroid().MulScalar(a))
+		points = append(points, triangle.P, triangle.Q, triangle.R)
+	}
+
+	aabb := meshx.NewAABBFromVectors(points)
+
+	if area > 0 {
+		node.center = node.center.DivScalar(area)
+	} else {
+		node.center = aabb.Center
+	}
+
+	for _, point := range points {
+		node.radius = max(node.radius, point.Sub(node.center).Mag())
+	}
+
+	return aabb
+}
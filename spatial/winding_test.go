@@ -0,0 +1,80 @@
+package spatial
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate the outward oriented triangles of a sphere.
+func newWindingTestSphere(n int) []meshx.Triangle {
+	var triangles []meshx.Triangle
+
+	point := func(i, j int) meshx.Vector {
+		theta := float64(i) / float64(n) * math.Pi
+		phi := float64(j) / float64(2*n) * 2 * math.Pi
+		return meshx.NewVector(math.Sin(theta)*math.Cos(phi), math.Sin(theta)*math.Sin(phi), math.Cos(theta))
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < 2*n; j++ {
+			p := point(i, j)
+			q := point(i+1, j)
+			r := point(i+1, j+1)
+			s := point(i, j+1)
+
+			if i != n-1 {
+				triangles = append(triangles, meshx.NewTriangle(p, q, r))
+			}
+
+			if i != 0 {
+				triangles = append(triangles, meshx.NewTriangle(p, r, s))
+			}
+		}
+	}
+
+	return triangles
+}
+
+// Test the approximate winding number against the exact evaluation.
+func TestWindingNumberTree(t *testing.T) {
+	triangles := newWindingTestSphere(32)
+	tree := NewWindingNumberTree(triangles)
+
+	points := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(0.5, 0.2, -0.3),
+		meshx.NewVector(1.5, 0, 0),
+		meshx.NewVector(3, 4, 5),
+	}
+
+	for _, point := range points {
+		exact := meshx.WindingNumber(triangles, point)
+		assert.InDelta(t, exact, tree.Evaluate(point), 5e-2)
+	}
+
+	assert.InDelta(t, 1.0, tree.Evaluate(points[0]), 5e-2)
+	assert.InDelta(t, 0.0, tree.Evaluate(points[3]), 5e-2)
+}
+
+// Test refitting the tree to moved triangles.
+func TestWindingNumberTreeRefit(t *testing.T) {
+	triangles := newWindingTestSphere(16)
+	tree := NewWindingNumberTree(triangles)
+	offset := meshx.NewVector(2, 0, 0)
+
+	for i, triangle := range triangles {
+		triangles[i] = meshx.NewTriangle(triangle.P.Add(offset), triangle.Q.Add(offset), triangle.R.Add(offset))
+	}
+
+	assert.Empty(t, tree.Refit(triangles))
+	assert.InDelta(t, 1.0, tree.Evaluate(offset), 5e-2)
+	assert.InDelta(t, 0.0, tree.Evaluate(meshx.NewVector(0, 0, 0)), 5e-2)
+
+	point := meshx.NewVector(2.5, 0.2, -0.3)
+	assert.InDelta(t, meshx.WindingNumber(triangles, point), tree.Evaluate(point), 5e-2)
+
+	assert.ErrorIs(t, tree.Refit(triangles[1:]), ErrWindingNumberTreeCount)
+}
@@ -0,0 +1,169 @@
+package meshx
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Solid sphere in three-dimensional Cartesian space.
+type Sphere struct {
+	Center Vector
+	Radius float64
+}
+
+// Construct a Sphere from its center and radius.
+func NewSphere(center Vector, radius float64) Sphere {
+	return Sphere{center, radius}
+}
+
+// Construct the minimal bounding Sphere of the vectors using Welzl's
+// algorithm. The vectors are visited in a fixed pseudorandom order so the
+// result is deterministic.
+func NewSphereFromVectors(vectors []Vector) Sphere {
+	points := make([]Vector, len(vectors))
+	copy(points, vectors)
+
+	rng := rand.New(rand.NewSource(int64(len(points))))
+	rng.Shuffle(len(points), func(i, j int) {
+		points[i], points[j] = points[j], points[i]
+	})
+
+	var support [4]Vector
+	return welzl(points, len(points), support, 0)
+}
+
+// Compute the minimal sphere enclosing the first n points with the support
+// points on its boundary.
+func welzl(points []Vector, n int, support [4]Vector, count int) Sphere {
+	sphere := newSphereFromSupport(support[:count])
+
+	if count == 4 {
+		return sphere
+	}
+
+	for i := 0; i < n; i++ {
+		if !sphere.containsPoint(points[i]) {
+			support[count] = points[i]
+			sphere = welzl(points, i, support, count+1)
+		}
+	}
+
+	return sphere
+}
+
+// Compute the smallest sphere with up to four points on its boundary. An
+// empty set of points has a negative radius.
+func newSphereFromSupport(support []Vector) Sphere {
+	switch len(support) {
+	case 0:
+		return Sphere{Radius: -1}
+	case 1:
+		return Sphere{support[0], 0}
+	case 2:
+		center := support[0].Add(support[1]).DivScalar(2)
+		return Sphere{center, support[1].Sub(support[0]).Mag() / 2}
+	case 3:
+		if sphere, ok := newCircumsphere3(support[0], support[1], support[2]); ok {
+			return sphere
+		}
+	case 4:
+		if sphere, ok := newCircumsphere4(support[0], support[1], support[2], support[3]); ok {
+			return sphere
+		}
+	}
+
+	// The support points are degenerate (collinear or coplanar). Use the
+	// smallest sphere through a subset that contains every support point.
+	best := Sphere{Radius: math.Inf(1)}
+
+	for i := range support {
+		subset := make([]Vector, 0, len(support)-1)
+		subset = append(subset, support[:i]...)
+		subset = append(subset, support[i+1:]...)
+		sphere := newSphereFromSupport(subset)
+
+		if sphere.Radius < best.Radius && sphere.containsPoint(support[i]) {
+			best = sphere
+		}
+	}
+
+	return best
+}
+
+// Compute the sphere through three points centered on their plane.
+func newCircumsphere3(a, b, c Vector) (Sphere, bool) {
+	u := b.Sub(a)
+	v := c.Sub(a)
+	w := u.Cross(v)
+	denom := 2 * w.Dot(w)
+
+	if denom == 0 {
+		return Sphere{}, false
+	}
+
+	offset := v.Cross(w).MulScalar(u.Dot(u)).Add(w.Cross(u).MulScalar(v.Dot(v))).DivScalar(denom)
+	return Sphere{a.Add(offset), offset.Mag()}, true
+}
+
+// Compute the sphere through four points.
+func newCircumsphere4(a, b, c, d Vector) (Sphere, bool) {
+	u := b.Sub(a)
+	v := c.Sub(a)
+	w := d.Sub(a)
+	denom := 2 * u.Dot(v.Cross(w))
+
+	if denom == 0 {
+		return Sphere{}, false
+	}
+
+	offset := v.Cross(w).MulScalar(u.Dot(u)).
+		Add(w.Cross(u).MulScalar(v.Dot(v))).
+		Add(u.Cross(v).MulScalar(w.Dot(w))).
+		DivScalar(denom)
+
+	return Sphere{a.Add(offset), offset.Mag()}, true
+}
+
+// Check if the sphere contains a point within a relative tolerance.
+func (s Sphere) containsPoint(point Vector) bool {
+	const epsilon = 1e-12
+	return point.Sub(s.Center).Mag() <= s.Radius*(1+epsilon)+epsilon
+}
+
+// Compute the bounding AABB.
+func (s Sphere) GetAABB() AABB {
+	return NewAABB(s.Center, NewVector(s.Radius, s.Radius, s.Radius))
+}
+
+// Compute the closest point in the sphere to a point. A point inside the
+// sphere is its own closest point.
+func (s Sphere) ClosestPoint(point Vector) Vector {
+	d := point.Sub(s.Center)
+	mag := d.Mag()
+
+	if mag <= s.Radius {
+		return point
+	}
+
+	return s.Center.Add(d.MulScalar(s.Radius / mag))
+}
+
+// Compute the distance to a point. The distance is zero inside the sphere.
+func (s Sphere) DistanceTo(point Vector) float64 {
+	return max(0, point.Sub(s.Center).Mag()-s.Radius)
+}
+
+// Implement the IntersectsAABB interface.
+func (s Sphere) IntersectsAABB(query AABB) bool {
+	return query.DistanceTo(s.Center) <= s.Radius
+}
+
+// Check for an intersection with another sphere.
+func (s Sphere) IntersectsSphere(query Sphere) bool {
+	return s.Center.Sub(query.Center).Mag() <= s.Radius+query.Radius
+}
+
+// Implement the IntersectsTriangle interface.
+func (s Sphere) IntersectsTriangle(query Triangle) bool {
+	return query.DistanceTo(s.Center) <= s.Radius
+}
@@ -0,0 +1,98 @@
+package meshx
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the closest point of a sphere inside, outside, and on its surface.
+func TestSphereClosestPoint(t *testing.T) {
+	sphere := NewSphere(NewVector(1, 1, 1), 2)
+
+	cases := [][2]Vector{
+		{NewVector(1, 1, 1), NewVector(1, 1, 1)},
+		{NewVector(2, 1, 1), NewVector(2, 1, 1)},
+		{NewVector(3, 1, 1), NewVector(3, 1, 1)},
+		{NewVector(1, 6, 1), NewVector(1, 3, 1)},
+		{NewVector(-4, 1, 1), NewVector(-1, 1, 1)},
+	}
+
+	for _, c := range cases {
+		closest := sphere.ClosestPoint(c[0])
+		assert.InDelta(t, 0.0, closest.Sub(c[1]).Mag(), 1e-12)
+		assert.InDelta(t, c[0].Sub(c[1]).Mag(), sphere.DistanceTo(c[0]), 1e-12)
+	}
+}
+
+// Test the minimal bounding sphere of the corners of a cube.
+func TestNewSphereFromVectorsCube(t *testing.T) {
+	vectors := make([]Vector, 0, 9)
+
+	for i := 0; i < 8; i++ {
+		vectors = append(vectors, NewVector(float64(i&1), float64(i>>1&1), float64(i>>2&1)))
+	}
+
+	vectors = append(vectors, NewVector(0.5, 0.5, 0.5))
+	sphere := NewSphereFromVectors(vectors)
+
+	assert.InDelta(t, 0, sphere.Center.Sub(NewVector(0.5, 0.5, 0.5)).Mag(), 1e-12)
+	assert.InDelta(t, math.Sqrt(3)/2, sphere.Radius, 1e-12)
+}
+
+// Test the minimal bounding sphere of degenerate sets of vectors.
+func TestNewSphereFromVectorsDegenerate(t *testing.T) {
+	sphere := NewSphereFromVectors([]Vector{NewVector(1, 2, 3)})
+	assert.Equal(t, NewSphere(NewVector(1, 2, 3), 0), sphere)
+
+	collinear := []Vector{NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(3, 0, 0), NewVector(2, 0, 0)}
+	sphere = NewSphereFromVectors(collinear)
+	assert.InDelta(t, 0, sphere.Center.Sub(NewVector(1.5, 0, 0)).Mag(), 1e-12)
+	assert.InDelta(t, 1.5, sphere.Radius, 1e-12)
+
+	coplanar := []Vector{NewVector(1, 0, 0), NewVector(0, 1, 0), NewVector(-1, 0, 0), NewVector(0, -1, 0), NewVector(0.5, 0.5, 0)}
+	sphere = NewSphereFromVectors(coplanar)
+	assert.InDelta(t, 0, sphere.Center.Mag(), 1e-12)
+	assert.InDelta(t, 1, sphere.Radius, 1e-12)
+}
+
+// Test the minimal bounding sphere of random vectors on and within a sphere.
+func TestNewSphereFromVectorsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	center := NewVector(1, -2, 3)
+	vectors := make([]Vector, 0, 1000)
+
+	for i := 0; i < 1000; i++ {
+		direction := NewVector(rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()).Unit()
+		scale := 2.0
+
+		if i%2 == 0 {
+			scale *= rng.Float64()
+		}
+
+		vectors = append(vectors, center.Add(direction.MulScalar(scale)))
+	}
+
+	sphere := NewSphereFromVectors(vectors)
+
+	for _, vector := range vectors {
+		assert.LessOrEqual(t, vector.Sub(sphere.Center).Mag(), sphere.Radius+1e-9)
+	}
+
+	assert.InDelta(t, 2, sphere.Radius, 1e-2)
+	assert.InDelta(t, 0, sphere.Center.Sub(center).Mag(), 5e-2)
+	assert.LessOrEqual(t, sphere.Radius, 2+1e-9)
+}
+
+// Test a sphere/AABB and sphere/sphere intersection.
+func TestSphereIntersects(t *testing.T) {
+	sphere := NewSphere(NewVector(0, 0, 0), 1)
+	aabb := NewAABB(NewVector(1.5, 0, 0), NewVector(0.5, 0.5, 0.5))
+
+	assert.True(t, sphere.IntersectsAABB(aabb))
+	assert.False(t, sphere.IntersectsAABB(NewAABB(NewVector(1.5, 1.5, 0), NewVector(0.5, 0.5, 0.5))))
+	assert.True(t, sphere.IntersectsSphere(NewSphere(NewVector(2, 0, 0), 1)))
+	assert.False(t, sphere.IntersectsSphere(NewSphere(NewVector(2, 2, 0), 1)))
+}
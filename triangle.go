@@ -1,5 +1,9 @@
 package meshx
 
+import (
+	"math"
+)
+
 // Triangle in three-dimension Cartesian space.
 type Triangle struct {
 	P Vector
@@ -160,3 +164,200 @@ func (t Triangle) IntersectsAABB(query AABB) bool {
 func (t Triangle) IntersectsRay(query Ray) bool {
 	return query.IntersectsTriangle(t)
 }
+
+// Implement the Raycast interface. The watertight intersection is used so a
+// ray through an edge shared with another triangle hits only one of them.
+func (t Triangle) Raycast(query Ray) []Hit {
+	distance, ok := query.IntersectTriangleWatertight(t)
+
+	if !ok {
+		return nil
+	}
+
+	normal := t.UnitNormal()
+
+	hit := Hit{
+		Index:    -1,
+		Distance: distance,
+		Point:    query.Origin.Add(query.Direction.MulScalar(distance)),
+		Normal:   normal,
+		Entering: query.Direction.Dot(normal) < 0,
+	}
+
+	return []Hit{hit}
+}
+
+// Compute the signed solid angle subtended by the triangle at a point. The
+// solid angle is positive when the point is behind the triangle relative to
+// its normal (Van Oosterom and Strackee).
+func (t Triangle) SolidAngle(point Vector) float64 {
+	a := t.P.Sub(point)
+	b := t.Q.Sub(point)
+	c := t.R.Sub(point)
+
+	la := a.Mag()
+	lb := b.Mag()
+	lc := c.Mag()
+
+	numerator := a.Dot(b.Cross(c))
+	denominator := la*lb*lc + a.Dot(b)*lc + a.Dot(c)*lb + b.Dot(c)*la
+
+	return 2 * math.Atan2(numerator, denominator)
+}
+
+// Compute the centroid.
+func (t Triangle) Centroid() Vector {
+	return t.P.Add(t.Q).Add(t.R).DivScalar(3)
+}
+
+// Compute the closest point on the triangle to a point (Ericson).
+func (t Triangle) ClosestPoint(point Vector) Vector {
+	ab := t.Q.Sub(t.P)
+	ac := t.R.Sub(t.P)
+	ap := point.Sub(t.P)
+
+	d1 := ab.Dot(ap)
+	d2 := ac.Dot(ap)
+
+	if d1 <= 0 && d2 <= 0 {
+		return t.P
+	}
+
+	bp := point.Sub(t.Q)
+	d3 := ab.Dot(bp)
+	d4 := ac.Dot(bp)
+
+	if d3 >= 0 && d4 <= d3 {
+		return t.Q
+	}
+
+	vc := d1*d4 - d3*d2
+
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		v := d1 / (d1 - d3)
+		return t.P.Add(ab.MulScalar(v))
+	}
+
+	cp := point.Sub(t.R)
+	d5 := ab.Dot(cp)
+	d6 := ac.Dot(cp)
+
+	if d6 >= 0 && d5 <= d6 {
+		return t.R
+	}
+
+	vb := d5*d2 - d1*d6
+
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		w := d2 / (d2 - d6)
+		return t.P.Add(ac.MulScalar(w))
+	}
+
+	va := d3*d6 - d5*d4
+
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		w := (d4 - d3) / ((d4 - d3) + (d5 - d6))
+		return t.Q.Add(t.R.Sub(t.Q).MulScalar(w))
+	}
+
+	denom := 1 / (va + vb + vc)
+	v := vb * denom
+	w := vc * denom
+
+	return t.P.Add(ab.MulScalar(v)).Add(ac.MulScalar(w))
+}
+
+// Compute the distance to a point.
+func (t Triangle) DistanceTo(point Vector) float64 {
+	return t.ClosestPoint(point).Sub(point).Mag()
+}
+
+// Compute the closest points between the triangle and another triangle. The
+// point on the triangle is returned first. If the triangles intersect, both
+// points are a point of the intersection.
+func (t Triangle) ClosestPoints(other Triangle) (Vector, Vector) {
+	if point, ok := t.crossingPoint(other); ok {
+		return point, point
+	}
+
+	if point, ok := other.crossingPoint(t); ok {
+		return point, point
+	}
+
+	var p, q Vector
+
+	distance := math.Inf(1)
+
+	update := func(a, b Vector) {
+		if d := a.Sub(b).Mag(); d < distance {
+			p, q, distance = a, b, d
+		}
+	}
+
+	for _, edge := range t.edges() {
+		for _, otherEdge := range other.edges() {
+			update(edge.ClosestPoints(otherEdge))
+		}
+	}
+
+	for _, point := range []Vector{t.P, t.Q, t.R} {
+		update(point, other.ClosestPoint(point))
+	}
+
+	for _, point := range []Vector{other.P, other.Q, other.R} {
+		update(t.ClosestPoint(point), point)
+	}
+
+	return p, q
+}
+
+// Compute the distance to another triangle (zero if they intersect).
+func (t Triangle) DistanceToTriangle(other Triangle) float64 {
+	p, q := t.ClosestPoints(other)
+	return p.Sub(q).Mag()
+}
+
+// Get the edges (PQ, QR, RP).
+func (t Triangle) edges() [3]Segment {
+	return [3]Segment{{t.P, t.Q}, {t.Q, t.R}, {t.R, t.P}}
+}
+
+// Find the point where an edge of the triangle crosses another triangle.
+func (t Triangle) crossingPoint(other Triangle) (Vector, bool) {
+	normal := other.Normal()
+
+	for _, edge := range t.edges() {
+		if crosses, _ := edge.CrossesTriangle(other); crosses {
+			sp := edge.P.Sub(other.P).Dot(normal)
+			sq := edge.Q.Sub(other.P).Dot(normal)
+			return edge.PointAt(sp / (sp - sq)), true
+		}
+	}
+
+	return Vector{}, false
+}
+
+// Implement the IntersectsSegment interface.
+func (t Triangle) IntersectsSegment(query Segment) bool {
+	return query.IntersectsTriangle(t)
+}
+
+// Implement the IntersectsCylinder interface.
+func (t Triangle) IntersectsCylinder(query Cylinder) bool {
+	return query.IntersectsTriangle(t)
+}
+
+// Implement the IntersectsCapsule interface.
+func (t Triangle) IntersectsCapsule(query Capsule) bool {
+	return query.IntersectsTriangle(t)
+}
+
+// Implement the IntersectsSphere interface.
+func (t Triangle) IntersectsSphere(query Sphere) bool {
+	return query.IntersectsTriangle(t)
+}
+
+// Implement the IntersectsPolytope interface.
+func (t Triangle) IntersectsPolytope(query Polytope) bool {
+	return query.IntersectsTriangle(t)
+}
@@ -1,6 +1,7 @@
 package meshx
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -156,3 +157,66 @@ func TestTriangleIntersectsRayBesideMiss(t *testing.T) {
 
 	assert.False(t, triangle.IntersectsRay(ray))
 }
+
+// Test the closest point on a triangle in each Voronoi region.
+func TestTriangleClosestPoint(t *testing.T) {
+	triangle := Triangle{
+		P: NewVector(0, 0, 0),
+		Q: NewVector(2, 0, 0),
+		R: NewVector(0, 2, 0),
+	}
+
+	cases := [][2]Vector{
+		{NewVector(0.5, 0.5, 1), NewVector(0.5, 0.5, 0)},
+		{NewVector(0.5, 0.5, -1), NewVector(0.5, 0.5, 0)},
+		{NewVector(-1, -1, 1), NewVector(0, 0, 0)},
+		{NewVector(3, -1, 0), NewVector(2, 0, 0)},
+		{NewVector(-1, 3, 0), NewVector(0, 2, 0)},
+		{NewVector(1, -1, 0), NewVector(1, 0, 0)},
+		{NewVector(-1, 1, 2), NewVector(0, 1, 0)},
+		{NewVector(2, 2, 0), NewVector(1, 1, 0)},
+		{NewVector(2, 0, 0), NewVector(2, 0, 0)},
+		{NewVector(1, 1, 0), NewVector(1, 1, 0)},
+	}
+
+	for _, c := range cases {
+		closest := triangle.ClosestPoint(c[0])
+		assert.InDelta(t, 0.0, closest.Sub(c[1]).Mag(), 1e-12)
+		assert.InDelta(t, c[0].Sub(c[1]).Mag(), triangle.DistanceTo(c[0]), 1e-12)
+	}
+}
+
+// Test the closest point on a degenerate (collinear) triangle.
+func TestTriangleClosestPointDegenerate(t *testing.T) {
+	triangle := Triangle{
+		P: NewVector(0, 0, 0),
+		Q: NewVector(1, 0, 0),
+		R: NewVector(2, 0, 0),
+	}
+
+	closest := triangle.ClosestPoint(NewVector(1.5, 1, 0))
+	assert.InDelta(t, 0.0, closest.Sub(NewVector(1.5, 0, 0)).Mag(), 1e-12)
+	assert.InDelta(t, 1.0, triangle.DistanceTo(NewVector(1.5, 1, 0)), 1e-12)
+}
+
+// Test the closest points between two triangles.
+func TestTriangleClosestPoints(t *testing.T) {
+	triangle := NewTriangle(NewVector(0, 0, 0), NewVector(2, 0, 0), NewVector(0, 2, 0))
+
+	// Vertex above the face.
+	other := NewTriangle(NewVector(0.5, 0.5, 1), NewVector(0.5, 0.5, 3), NewVector(1, 1, 2))
+	p, q := triangle.ClosestPoints(other)
+	assert.InDelta(t, 0.0, p.Sub(NewVector(0.5, 0.5, 0)).Mag(), 1e-12)
+	assert.InDelta(t, 0.0, q.Sub(NewVector(0.5, 0.5, 1)).Mag(), 1e-12)
+
+	// Skew edges.
+	other = NewTriangle(NewVector(2, 2, -1), NewVector(2, 2, 1), NewVector(4, 4, 0))
+	assert.InDelta(t, math.Sqrt(2), triangle.DistanceToTriangle(other), 1e-12)
+
+	// Crossing triangles.
+	other = NewTriangle(NewVector(0.5, 0.5, -1), NewVector(0.5, 0.5, 1), NewVector(3, 3, 0))
+	p, q = triangle.ClosestPoints(other)
+	assert.Equal(t, p, q)
+	assert.InDelta(t, 0.0, triangle.DistanceTo(p), 1e-12)
+	assert.InDelta(t, 0.0, other.DistanceTo(p), 1e-12)
+}
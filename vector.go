@@ -155,3 +155,8 @@ func (v Vector) IntersectsAABB(query AABB) bool {
 
 	return true
 }
+
+// Implement the ClosestPoint interface. A point is its own closest point.
+func (v Vector) ClosestPoint(point Vector) Vector {
+	return v
+}
@@ -0,0 +1,369 @@
+package volume
+
+import (
+	"errors"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/ajcurley/meshx-go/planar"
+)
+
+const (
+	// Default ratio of the heights of consecutive layers.
+	DefaultGrowthRatio = 1.2
+
+	// Default maximum fraction of the distance to the facing surface
+	// occupied by the layers. Half keeps the layers of two facing surfaces
+	// from meeting.
+	DefaultMaxGapFraction = 0.5
+
+	// Name of the patch of the top faces of the boundary layer.
+	BoundaryLayerTopPatch = "boundaryLayerTop"
+)
+
+var (
+	ErrInvalidBoundaryLayer = errors.New("invalid boundary layer")
+	ErrInvertedCell         = errors.New("inverted boundary layer cell")
+)
+
+// Options to extrude a boundary layer. The heights of the layers grow
+// geometrically from the first layer height.
+type BoundaryLayerOptions struct {
+	// Height of the first layer (at the surface).
+	FirstHeight float64
+
+	// Ratio of the heights of consecutive layers. The default is
+	// DefaultGrowthRatio.
+	GrowthRatio float64
+
+	// Number of layers.
+	Layers int
+
+	// Maximum fraction of the distance to the facing surface along the
+	// extrusion occupied by the layers. The layers of a vertex are
+	// compressed to fit. The default is DefaultMaxGapFraction.
+	MaxGapFraction float64
+
+	// Extrude against the face normals (e.g. into the solid) rather than
+	// along them.
+	Inward bool
+}
+
+// Prismatic boundary layer extruded from the faces of a surface. The
+// vertices of the surface are extruded along their normals into columns of
+// points, one per layer surface, and each triangle of the faces sweeps a
+// prism per layer.
+type BoundaryLayer struct {
+	// Points of each layer surface (the surface itself is layer 0) indexed
+	// by column.
+	Points [][]meshx.Vector
+
+	// Vertex of the surface of each column.
+	Vertices []int
+
+	// Triangles of the faces indexed by column and the patch of each.
+	Triangles       [][3]int
+	TrianglePatches []int
+
+	// Names of the patches of the surface.
+	Patches []string
+
+	// Vertices of the surface whose layers are compressed to avoid the
+	// facing surface.
+	Collisions []int
+
+	// Prisms inverted by the extrusion (e.g. folded at a concave corner).
+	// The index of the prism of triangle i in layer k (from layer surface k
+	// to k + 1) is k * len(Triangles) + i.
+	InvertedCells []int
+}
+
+// Extrude a boundary layer from the faces of the patches of a surface (e.g.
+// to resolve the near-wall flow of a CFD mesh). The faces are triangulated
+// and each vertex is extruded along its area-weighted normal. The distance
+// to the facing surface is found by casting a ray along the extrusion and
+// the layers of a vertex are compressed to fit within the maximum fraction
+// of it (see Collisions). The prisms inverted by the extrusion are reported
+// (see InvertedCells). An error is returned if the options are invalid, the
+// patches have no faces or a vertex has no normal.
+func ExtrudeBoundaryLayer(mesh *halfedge.HalfEdgeMesh, patches []int, options BoundaryLayerOptions) (*BoundaryLayer, error) {
+	if options.GrowthRatio == 0 {
+		options.GrowthRatio = DefaultGrowthRatio
+	}
+
+	if options.MaxGapFraction == 0 {
+		options.MaxGapFraction = DefaultMaxGapFraction
+	}
+
+	if !(options.FirstHeight > 0) || !(options.GrowthRatio > 0) || options.Layers <= 0 ||
+		!(options.MaxGapFraction > 0) || options.MaxGapFraction > 1 {
+		return nil, ErrInvalidBoundaryLayer
+	}
+
+	layer := BoundaryLayer{
+		Vertices:        make([]int, 0),
+		Triangles:       make([][3]int, 0),
+		TrianglePatches: make([]int, 0),
+		Patches:         make([]string, mesh.GetNumberOfPatches()),
+		Collisions:      make([]int, 0),
+		InvertedCells:   make([]int, 0),
+	}
+
+	for i := range layer.Patches {
+		layer.Patches[i] = mesh.GetPatch(i).Name
+	}
+
+	columns := make(map[int]int)
+	normals := make([]meshx.Vector, 0)
+
+	for _, patch := range patches {
+		for _, face := range mesh.GetPatchFaces(patch) {
+			vertices := mesh.GetFaceVertices(face)
+			points := make([]meshx.Vector, len(vertices))
+			normal := mesh.GetFaceNormal(face).MulScalar(mesh.GetFaceArea(face))
+
+			for i, vertex := range vertices {
+				points[i] = mesh.GetVertex(vertex).Point
+
+				if _, ok := columns[vertex]; !ok {
+					columns[vertex] = len(layer.Vertices)
+					layer.Vertices = append(layer.Vertices, vertex)
+					normals = append(normals, meshx.Vector{})
+				}
+
+				normals[columns[vertex]] = normals[columns[vertex]].Add(normal)
+			}
+
+			for _, triangle := range planar.TriangulateFace(points) {
+				var columnTriangle [3]int
+
+				for i, j := range triangle {
+					columnTriangle[i] = columns[vertices[j]]
+				}
+
+				layer.Triangles = append(layer.Triangles, columnTriangle)
+				layer.TrianglePatches = append(layer.TrianglePatches, patch)
+			}
+		}
+	}
+
+	if len(layer.Triangles) == 0 {
+		return nil, ErrInvalidBoundaryLayer
+	}
+
+	for i, normal := range normals {
+		if normal.Mag() == 0 {
+			return nil, ErrInvalidBoundaryLayer
+		}
+
+		normals[i] = normal.Unit()
+
+		if options.Inward {
+			normals[i] = normals[i].MulScalar(-1)
+		}
+	}
+
+	layer.extrude(mesh, normals, options)
+	layer.findInvertedCells(options.Inward)
+
+	return &layer, nil
+}
+
+// Extrude the columns along their normals by the heights of the layers,
+// compressed to fit within the maximum fraction of the distance to the
+// facing surface.
+func (l *BoundaryLayer) extrude(mesh *halfedge.HalfEdgeMesh, normals []meshx.Vector, options BoundaryLayerOptions) {
+	heights := make([]float64, options.Layers+1)
+	height := options.FirstHeight
+
+	for k := 1; k <= options.Layers; k++ {
+		heights[k] = heights[k-1] + height
+		height *= options.GrowthRatio
+	}
+
+	total := heights[options.Layers]
+	octree := mesh.BuildOctree()
+	offset := 1e-6 * mesh.GetAABB().HalfSize.Mag()
+	l.Points = make([][]meshx.Vector, options.Layers+1)
+
+	for k := range l.Points {
+		l.Points[k] = make([]meshx.Vector, len(l.Vertices))
+	}
+
+	for i, vertex := range l.Vertices {
+		point := mesh.GetVertex(vertex).Point
+		scale := 1.0
+		ray := meshx.NewRay(point.Add(normals[i].MulScalar(offset)), normals[i])
+
+		if hits := octree.RaycastAll(ray); len(hits) > 0 {
+			if limit := options.MaxGapFraction * (hits[0].Distance + offset); total > limit {
+				scale = limit / total
+				l.Collisions = append(l.Collisions, vertex)
+			}
+		}
+
+		for k, height := range heights {
+			l.Points[k][i] = point.Add(normals[i].MulScalar(height * scale))
+		}
+	}
+}
+
+// Find the prisms inverted by the extrusion: a prism is inverted if a
+// tetrahedron of its split (see GetTetMesh) is not oriented along the
+// extrusion, e.g. where the columns cross at a concave corner.
+func (l *BoundaryLayer) findInvertedCells(inward bool) {
+	sign := 1.0
+
+	if inward {
+		sign = -1
+	}
+
+	for k := range l.GetNumberOfLayers() {
+		for i, triangle := range l.Triangles {
+			for _, cell := range splitPrism(l.getPrism(k, triangle)) {
+				if sign*l.getTetrahedron(cell).SignedVolume() <= 0 {
+					l.InvertedCells = append(l.InvertedCells, k*len(l.Triangles)+i)
+					break
+				}
+			}
+		}
+	}
+}
+
+// Get the vertices of the prism of a triangle in a layer indexed as the
+// vertices of the TetMesh.
+func (l *BoundaryLayer) getPrism(k int, triangle [3]int) [6]int {
+	n := len(l.Vertices)
+
+	return [6]int{
+		k*n + triangle[0], k*n + triangle[1], k*n + triangle[2],
+		(k+1)*n + triangle[0], (k+1)*n + triangle[1], (k+1)*n + triangle[2],
+	}
+}
+
+// Get a tetrahedron of the vertices indexed as those of the TetMesh.
+func (l *BoundaryLayer) getTetrahedron(cell [4]int) Tetrahedron {
+	var points [4]meshx.Vector
+
+	for i, vertex := range cell {
+		points[i] = l.Points[vertex/len(l.Vertices)][vertex%len(l.Vertices)]
+	}
+
+	return NewTetrahedron(points[0], points[1], points[2], points[3])
+}
+
+// Get the number of layers (one less than the number of layer surfaces).
+func (l *BoundaryLayer) GetNumberOfLayers() int {
+	return len(l.Points) - 1
+}
+
+// Get a MeshReader of a layer surface (the surface itself is layer 0) with
+// the triangles oriented as the surface and their patches.
+func (l *BoundaryLayer) GetLayerMeshReader(k int) meshx.MeshReader {
+	source := surfaceSource{
+		vertices:    l.Points[k],
+		faces:       make([][]int, len(l.Triangles)),
+		facePatches: l.TrianglePatches,
+		patches:     l.Patches,
+	}
+
+	for i, triangle := range l.Triangles {
+		source.faces[i] = triangle[:]
+	}
+
+	return &source
+}
+
+// Construct a TetMesh of the prisms each split into three tetrahedra. The
+// prisms are split by the diagonals from the vertex of least index of each
+// side, so neighboring prisms conform. The vertex of column i in layer
+// surface k is k * len(Vertices) + i. The boundary faces of the surface are
+// assigned to its patches and the faces of the top layer surface to
+// BoundaryLayerTopPatch. An error is returned if a prism is inverted.
+func (l *BoundaryLayer) GetTetMesh() (*TetMesh, error) {
+	if len(l.InvertedCells) > 0 {
+		return nil, ErrInvertedCell
+	}
+
+	n := len(l.Vertices)
+	vertices := make([]meshx.Vector, 0, len(l.Points)*n)
+
+	for _, points := range l.Points {
+		vertices = append(vertices, points...)
+	}
+
+	cells := make([][4]int, 0, 3*l.GetNumberOfLayers()*len(l.Triangles))
+
+	for k := range l.GetNumberOfLayers() {
+		for _, triangle := range l.Triangles {
+			cells = append(cells, splitPrism(l.getPrism(k, triangle))...)
+		}
+	}
+
+	mesh, err := NewTetMesh(vertices, cells)
+	if err != nil {
+		return nil, err
+	}
+
+	top := len(l.Patches)
+	source := surfaceSource{
+		faces:       make([][]int, 0, 2*len(l.Triangles)),
+		facePatches: make([]int, 0, 2*len(l.Triangles)),
+		patches:     append(append([]string{}, l.Patches...), BoundaryLayerTopPatch),
+	}
+
+	for i, triangle := range l.Triangles {
+		offset := l.GetNumberOfLayers() * n
+		source.faces = append(source.faces, triangle[:])
+		source.faces = append(source.faces, []int{triangle[0] + offset, triangle[1] + offset, triangle[2] + offset})
+		source.facePatches = append(source.facePatches, l.TrianglePatches[i], top)
+	}
+
+	if err := mesh.SetBoundaryPatches(&source); err != nil {
+		return nil, err
+	}
+
+	return mesh, nil
+}
+
+// Permutations of the vertices of a prism moving each vertex to the first
+// while keeping the structure of the prism (vertex i + 3 above vertex i).
+var prismPermutations = [6][6]int{
+	{0, 1, 2, 3, 4, 5},
+	{1, 2, 0, 4, 5, 3},
+	{2, 0, 1, 5, 3, 4},
+	{3, 5, 4, 0, 2, 1},
+	{4, 3, 5, 1, 0, 2},
+	{5, 4, 3, 2, 1, 0},
+}
+
+// Split a prism (the bottom triangle and the top triangle above it) into
+// three tetrahedra by the diagonals of its sides from the vertex of least
+// index of each side (Dompierre et al.), so the split of a side is the same
+// for the prisms sharing it.
+func splitPrism(prism [6]int) [][4]int {
+	first := 0
+
+	for i, vertex := range prism {
+		if vertex < prism[first] {
+			first = i
+		}
+	}
+
+	var v [6]int
+
+	for i, j := range prismPermutations[first] {
+		v[i] = prism[j]
+	}
+
+	if min(v[1], v[5]) < min(v[2], v[4]) {
+		return [][4]int{{v[0], v[1], v[2], v[5]}, {v[0], v[1], v[5], v[4]}, {v[0], v[4], v[5], v[3]}}
+	}
+
+	return [][4]int{{v[0], v[1], v[2], v[4]}, {v[0], v[4], v[2], v[5]}, {v[0], v[4], v[5], v[3]}}
+}
+
+// Compute the total height of the layers at the surface vertex of a column.
+func (l *BoundaryLayer) GetColumnHeight(column int) float64 {
+	return l.Points[len(l.Points)-1][column].Sub(l.Points[0][column]).Mag()
+}
@@ -0,0 +1,250 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate a flat plate of quads over the unit square at a height with its
+// faces assigned to a patch.
+func newTestPlate(t *testing.T, z float64) *halfedge.HalfEdgeMesh {
+	grid := make([][]meshx.Vector, 5)
+
+	for i := range grid {
+		grid[i] = make([]meshx.Vector, 5)
+
+		for j := range grid[i] {
+			grid[i][j] = meshx.NewVector(float64(j)/4, float64(i)/4, z)
+		}
+	}
+
+	mesh, err := halfedge.NewStructuredGrid(grid, false)
+	assert.Empty(t, err)
+
+	patch := mesh.AddPatch("wall")
+
+	for i := range mesh.GetNumberOfFaces() {
+		mesh.SetFacePatch(i, patch)
+	}
+
+	return mesh
+}
+
+// Test extruding a boundary layer from a flat plate.
+func TestExtrudeBoundaryLayer(t *testing.T) {
+	mesh := newTestPlate(t, 0)
+	normal := mesh.GetFaceNormal(0)
+
+	options := BoundaryLayerOptions{FirstHeight: 0.01, GrowthRatio: 2, Layers: 3}
+	layer, err := ExtrudeBoundaryLayer(mesh, []int{0}, options)
+	assert.Empty(t, err)
+	assert.Equal(t, 3, layer.GetNumberOfLayers())
+	assert.Equal(t, 25, len(layer.Vertices))
+	assert.Equal(t, 32, len(layer.Triangles))
+	assert.Empty(t, layer.Collisions)
+	assert.Empty(t, layer.InvertedCells)
+
+	heights := []float64{0, 0.01, 0.03, 0.07}
+
+	for i, vertex := range layer.Vertices {
+		point := mesh.GetVertex(vertex).Point
+
+		for k, height := range heights {
+			assert.InDelta(t, 0, layer.Points[k][i].Sub(point.Add(normal.MulScalar(height))).Mag(), 1e-12)
+		}
+
+		assert.InDelta(t, 0.07, layer.GetColumnHeight(i), 1e-12)
+	}
+
+	options.Inward = true
+	layer, err = ExtrudeBoundaryLayer(mesh, []int{0}, options)
+	assert.Empty(t, err)
+	assert.InDelta(t, -0.07, layer.Points[3][0].Sub(layer.Points[0][0]).Dot(normal), 1e-12)
+	assert.Empty(t, layer.InvertedCells)
+}
+
+// Test extruding a boundary layer with invalid options.
+func TestExtrudeBoundaryLayerInvalid(t *testing.T) {
+	mesh := newTestPlate(t, 0)
+
+	invalid := []BoundaryLayerOptions{
+		{FirstHeight: 0, Layers: 3},
+		{FirstHeight: 0.01, Layers: 0},
+		{FirstHeight: 0.01, Layers: 3, GrowthRatio: -1},
+		{FirstHeight: 0.01, Layers: 3, MaxGapFraction: 2},
+	}
+
+	for _, options := range invalid {
+		_, err := ExtrudeBoundaryLayer(mesh, []int{0}, options)
+		assert.ErrorIs(t, err, ErrInvalidBoundaryLayer)
+	}
+
+	_, err := ExtrudeBoundaryLayer(mesh, []int{}, BoundaryLayerOptions{FirstHeight: 0.01, Layers: 3})
+	assert.ErrorIs(t, err, ErrInvalidBoundaryLayer)
+}
+
+// Test compressing the layers of facing plates to avoid a collision.
+func TestExtrudeBoundaryLayerCollision(t *testing.T) {
+	mesh := newTestPlate(t, 0)
+	normal := mesh.GetFaceNormal(0)
+	z := 0.1 * normal[2]
+
+	// Plate of a single quad facing the first and overhanging it.
+	grid := [][]meshx.Vector{
+		{meshx.NewVector(-1, -1, z), meshx.NewVector(-1, 2, z)},
+		{meshx.NewVector(2, -1, z), meshx.NewVector(2, 2, z)},
+	}
+
+	facing, err := halfedge.NewStructuredGrid(grid, false)
+	assert.Empty(t, err)
+	assert.Less(t, facing.GetFaceNormal(0).Dot(normal), 0.0)
+
+	mesh.Merge(facing)
+
+	options := BoundaryLayerOptions{FirstHeight: 0.02, GrowthRatio: 1, Layers: 5}
+	layer, err := ExtrudeBoundaryLayer(mesh, []int{0}, options)
+	assert.Empty(t, err)
+	assert.Equal(t, 25, len(layer.Collisions))
+	assert.Empty(t, layer.InvertedCells)
+
+	for i := range layer.Vertices {
+		assert.InDelta(t, 0.05, layer.GetColumnHeight(i), 1e-9)
+	}
+
+	options.MaxGapFraction = 1
+	options.FirstHeight = 0.01
+	layer, err = ExtrudeBoundaryLayer(mesh, []int{0}, options)
+	assert.Empty(t, err)
+	assert.Empty(t, layer.Collisions)
+}
+
+// Test finding the cells inverted by extruding into a concave corner.
+func TestExtrudeBoundaryLayerInverted(t *testing.T) {
+	profile := []meshx.Vector{
+		meshx.NewVector(0, 0, 1), meshx.NewVector(0, 0, 0.5), meshx.NewVector(0, 0, 0),
+		meshx.NewVector(0.5, 0, 0), meshx.NewVector(1, 0, 0),
+	}
+
+	grid := make([][]meshx.Vector, len(profile))
+
+	for i, point := range profile {
+		grid[i] = []meshx.Vector{point, point.Add(meshx.NewVector(0, 1, 0))}
+	}
+
+	mesh, err := halfedge.NewStructuredGrid(grid, false)
+	assert.Empty(t, err)
+
+	patch := mesh.AddPatch("corner")
+
+	for i := range mesh.GetNumberOfFaces() {
+		mesh.SetFacePatch(i, patch)
+	}
+
+	// Extrude into the corner (x > 0 and z > 0).
+	inward := mesh.GetFaceNormal(0)[0] < 0
+
+	options := BoundaryLayerOptions{FirstHeight: 0.05, Layers: 2, Inward: inward}
+	layer, err := ExtrudeBoundaryLayer(mesh, []int{patch}, options)
+	assert.Empty(t, err)
+	assert.Empty(t, layer.Collisions)
+	assert.Empty(t, layer.InvertedCells)
+
+	_, err = layer.GetTetMesh()
+	assert.Empty(t, err)
+
+	options = BoundaryLayerOptions{FirstHeight: 0.4, GrowthRatio: 1, Layers: 2, MaxGapFraction: 1, Inward: inward}
+	layer, err = ExtrudeBoundaryLayer(mesh, []int{patch}, options)
+	assert.Empty(t, err)
+	assert.Empty(t, layer.Collisions)
+	assert.NotEmpty(t, layer.InvertedCells)
+
+	for _, cell := range layer.InvertedCells {
+		assert.Equal(t, 1, cell/len(layer.Triangles))
+	}
+
+	_, err = layer.GetTetMesh()
+	assert.ErrorIs(t, err, ErrInvertedCell)
+}
+
+// Test getting the layer surfaces of a boundary layer.
+func TestBoundaryLayerGetLayerMeshReader(t *testing.T) {
+	mesh := newTestPlate(t, 0)
+
+	options := BoundaryLayerOptions{FirstHeight: 0.01, Layers: 2}
+	layer, err := ExtrudeBoundaryLayer(mesh, []int{0}, options)
+	assert.Empty(t, err)
+
+	for k := range layer.GetNumberOfLayers() + 1 {
+		surface, err := halfedge.NewHalfEdgeMesh(layer.GetLayerMeshReader(k))
+		assert.Empty(t, err)
+		assert.Equal(t, 25, surface.GetNumberOfVertices())
+		assert.Equal(t, 32, surface.GetNumberOfFaces())
+		assert.Equal(t, "wall", surface.GetPatch(0).Name)
+		assert.InDelta(t, 1.0/32, surface.GetFaceArea(0), 1e-12)
+		assert.Greater(t, surface.GetFaceNormal(0).Dot(mesh.GetFaceNormal(0)), 0.0)
+	}
+}
+
+// Test constructing the TetMesh of a boundary layer.
+func TestBoundaryLayerGetTetMesh(t *testing.T) {
+	mesh := newTestPlate(t, 0)
+
+	options := BoundaryLayerOptions{FirstHeight: 0.01, GrowthRatio: 2, Layers: 3}
+	layer, err := ExtrudeBoundaryLayer(mesh, []int{0}, options)
+	assert.Empty(t, err)
+
+	volume, err := layer.GetTetMesh()
+	assert.Empty(t, err)
+	assert.Equal(t, 4*25, volume.GetNumberOfVertices())
+	assert.Equal(t, 3*3*32, volume.GetNumberOfCells())
+	assert.InDelta(t, 0.07, volume.GetVolume(), 1e-12)
+	assert.Equal(t, 2*32+2*3*16, len(volume.GetBoundaryFaces()))
+	assert.Greater(t, volume.ComputeQualitySummary().MinVolume, 0.0)
+
+	assert.Equal(t, 2, volume.GetNumberOfPatches())
+	assert.Equal(t, "wall", volume.GetPatch(0))
+	assert.Equal(t, BoundaryLayerTopPatch, volume.GetPatch(1))
+	assert.Equal(t, 32, len(volume.GetPatchFaces(0)))
+	assert.Equal(t, 32, len(volume.GetPatchFaces(1)))
+}
+
+// Test splitting prisms into conforming tetrahedra.
+func TestSplitPrism(t *testing.T) {
+	vertices := []meshx.Vector{
+		meshx.NewVector(0, 0, 0), meshx.NewVector(1, 0, 0), meshx.NewVector(0, 1, 0),
+		meshx.NewVector(0, 0, 1), meshx.NewVector(1, 0, 1), meshx.NewVector(0, 1, 1),
+	}
+
+	orders := [][6]int{
+		{0, 1, 2, 3, 4, 5},
+		{3, 1, 2, 0, 4, 5},
+		{5, 4, 3, 2, 1, 0},
+		{4, 5, 0, 1, 2, 3},
+	}
+
+	for _, order := range orders {
+		points := make([]meshx.Vector, 6)
+		var prism [6]int
+
+		for i, j := range order {
+			points[j] = vertices[i]
+			prism[i] = j
+		}
+
+		cells := splitPrism(prism)
+
+		for _, cell := range cells {
+			tetrahedron := NewTetrahedron(points[cell[0]], points[cell[1]], points[cell[2]], points[cell[3]])
+			assert.Greater(t, tetrahedron.SignedVolume(), 0.0)
+		}
+
+		mesh, err := NewTetMesh(points, cells)
+		assert.Empty(t, err)
+		assert.Equal(t, 3, mesh.GetNumberOfCells())
+		assert.InDelta(t, 0.5, mesh.GetVolume(), 1e-12)
+		assert.Equal(t, 8, len(mesh.GetBoundaryFaces()))
+	}
+}
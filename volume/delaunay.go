@@ -0,0 +1,486 @@
+package volume
+
+import (
+	"errors"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Tolerance of the volume of a tetrahedron relative to the cube of its
+// longest edge below which it is considered flat.
+const flatTolerance = 1e-10
+
+var (
+	errOutsideHull   = errors.New("point outside the super tetrahedron")
+	errInvalidCavity = errors.New("cavity is not star-shaped")
+)
+
+// Incremental (Bowyer-Watson) Delaunay tetrahedralization of points
+// enclosed in a super tetrahedron. The cells are positively oriented and
+// neighbor i of a cell is across its face opposite vertex i (-1 if none).
+// The region of a cell classifies it relative to a constraining surface.
+type delaunay struct {
+	points    []meshx.Vector
+	cells     [][4]int
+	neighbors [][4]int
+	regions   []int
+	alive     []bool
+	free      []int
+	marks     []int
+	stamp     int
+	last      int
+	super     int
+}
+
+// Construct the Delaunay tetrahedralization of the points. The vertices of
+// the super tetrahedron follow the points.
+func newDelaunay(points []meshx.Vector) (*delaunay, error) {
+	d := delaunay{
+		points: make([]meshx.Vector, len(points), len(points)+4),
+		super:  len(points),
+	}
+
+	copy(d.points, points)
+
+	aabb := meshx.NewAABB(meshx.Vector{}, meshx.Vector{})
+
+	if len(points) > 0 {
+		aabb = meshx.NewAABBFromVectors(points)
+	}
+
+	// The insphere of a regular tetrahedron is a third of its circumsphere
+	// so the points are well inside.
+	radius := 100 * max(aabb.HalfSize.Mag(), 1)
+	directions := [4]meshx.Vector{
+		meshx.NewVector(1, 1, 1),
+		meshx.NewVector(1, -1, -1),
+		meshx.NewVector(-1, 1, -1),
+		meshx.NewVector(-1, -1, 1),
+	}
+
+	for _, direction := range directions {
+		vertex := aabb.Center.Add(direction.Unit().MulScalar(radius))
+		d.points = append(d.points, vertex)
+	}
+
+	cell := [4]int{d.super, d.super + 1, d.super + 2, d.super + 3}
+
+	if d.orientation(cell[0], cell[1], cell[2], d.points[cell[3]]) < 0 {
+		cell[2], cell[3] = cell[3], cell[2]
+	}
+
+	d.addCell(cell, 0)
+	d.neighbors[0] = [4]int{-1, -1, -1, -1}
+
+	for i := range points {
+		if _, _, err := d.insertVertex(i, insertion{seed: -1}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &d, nil
+}
+
+// Compute the orientation of a point relative to the triangle of vertices.
+// The result is positive if the point is on the side the normal of the
+// triangle points to.
+func (d *delaunay) orientation(a, b, c int, point meshx.Vector) float64 {
+	return -meshx.Orient3D(d.points[a], d.points[b], d.points[c], point)
+}
+
+// Return true if the point is strictly inside the circumsphere of a cell.
+func (d *delaunay) inSphere(cell int, point meshx.Vector) bool {
+	v := d.cells[cell]
+	p := d.points
+	return meshx.InSphere(p[v[0]], p[v[1]], p[v[2]], p[v[3]], point) < 0
+}
+
+// Return true if the cell has a vertex of the super tetrahedron.
+func (d *delaunay) isSuper(cell int) bool {
+	for _, vertex := range d.cells[cell] {
+		if vertex >= d.super && vertex < d.super+4 {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the tetrahedron of a cell.
+func (d *delaunay) getTetrahedron(cell int) Tetrahedron {
+	v := d.cells[cell]
+	return NewTetrahedron(d.points[v[0]], d.points[v[1]], d.points[v[2]], d.points[v[3]])
+}
+
+// Get the sorted vertices of the face of a cell opposite a vertex.
+func (d *delaunay) getFaceKey(cell, index int) [3]int {
+	return sortTriangle(getCellFaces(d.cells[cell])[index])
+}
+
+// Get the sorted vertices of the faces of the cells.
+func (d *delaunay) getFaceKeys() map[[3]int]bool {
+	faces := make(map[[3]int]bool)
+
+	for cell := range d.cells {
+		if d.alive[cell] {
+			for i := range 4 {
+				faces[d.getFaceKey(cell, i)] = true
+			}
+		}
+	}
+
+	return faces
+}
+
+// Add a cell (reusing a removed one if possible) and return its index.
+func (d *delaunay) addCell(cell [4]int, region int) int {
+	if n := len(d.free); n > 0 {
+		index := d.free[n-1]
+		d.free = d.free[:n-1]
+		d.cells[index] = cell
+		d.neighbors[index] = [4]int{-1, -1, -1, -1}
+		d.regions[index] = region
+		d.alive[index] = true
+		return index
+	}
+
+	d.cells = append(d.cells, cell)
+	d.neighbors = append(d.neighbors, [4]int{-1, -1, -1, -1})
+	d.regions = append(d.regions, region)
+	d.alive = append(d.alive, true)
+	d.marks = append(d.marks, 0)
+	return len(d.cells) - 1
+}
+
+// Remove a cell.
+func (d *delaunay) removeCell(cell int) {
+	d.alive[cell] = false
+	d.free = append(d.free, cell)
+}
+
+// Find the cell containing a point by walking towards it from the last
+// cell created.
+func (d *delaunay) locate(point meshx.Vector) (int, error) {
+	cell := d.last
+
+	if !d.alive[cell] {
+		for cell = range d.cells {
+			if d.alive[cell] {
+				break
+			}
+		}
+	}
+
+	for step := 0; step < len(d.cells)+16; step++ {
+		next := cell
+
+		// Rotate the order of the faces tested to avoid cycling.
+		for k := range 4 {
+			i := (k + step) % 4
+			face := getCellFaces(d.cells[cell])[i]
+
+			if d.orientation(face[0], face[1], face[2], point) > 0 {
+				next = d.neighbors[cell][i]
+				break
+			}
+		}
+
+		if next == -1 {
+			return -1, errOutsideHull
+		}
+
+		if next == cell {
+			return cell, nil
+		}
+
+		cell = next
+	}
+
+	for cell := range d.cells {
+		if d.alive[cell] && d.contains(cell, point) {
+			return cell, nil
+		}
+	}
+
+	return -1, errOutsideHull
+}
+
+// Return true if the point is inside or (nearly) on the boundary of a cell.
+func (d *delaunay) contains(cell int, point meshx.Vector) bool {
+	for _, face := range getCellFaces(d.cells[cell]) {
+		if d.isAbove(face, point) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return true if the point is above the triangle (on the side its normal
+// points to) and the tetrahedron they form is not nearly flat.
+func (d *delaunay) isAbove(triangle [3]int, point meshx.Vector) bool {
+	if d.orientation(triangle[0], triangle[1], triangle[2], point) <= 0 {
+		return false
+	}
+
+	a := d.points[triangle[0]]
+	b := d.points[triangle[1]]
+	c := d.points[triangle[2]]
+	tetrahedron := NewTetrahedron(a, b, c, point)
+	_, longest := getEdgeLengths(tetrahedron)
+
+	return tetrahedron.SignedVolume() > flatTolerance*longest*longest*longest
+}
+
+// Options to insert a vertex into a delaunay. The cavity grows from the
+// seed cell (or the cell containing the vertex if -1) without crossing the
+// constrained faces (indexed by their sorted vertices), so the constrained
+// faces are kept. If encroach is true, the vertex is not inserted if it
+// encroaches a constrained face (inside its diametral sphere or beyond it).
+// The vertex is not inserted either if closer than the spacing to a vertex
+// of the cavity.
+type insertion struct {
+	seed        int
+	constraints map[[3]int]int
+	encroach    bool
+	spacing     float64
+}
+
+// Insert a point into the tetrahedralization. See insertVertex.
+func (d *delaunay) insert(point meshx.Vector, options insertion) (int, int, error) {
+	d.points = append(d.points, point)
+	vertex, conflict, err := d.insertVertex(len(d.points)-1, options)
+
+	if vertex != len(d.points)-1 {
+		d.points = d.points[:len(d.points)-1]
+	}
+
+	return vertex, conflict, err
+}
+
+// Insert a vertex into the tetrahedralization and return its index (-1 if
+// not inserted) and the value of the constrained face it encroaches (-1 if
+// none). A vertex coinciding with another is not inserted and the other is
+// returned.
+func (d *delaunay) insertVertex(vertex int, options insertion) (int, int, error) {
+	point := d.points[vertex]
+	seed := options.seed
+
+	if seed == -1 {
+		cell, err := d.locate(point)
+		if err != nil {
+			return -1, -1, err
+		}
+		seed = cell
+	}
+
+	// Get the value of the constrained face of a cell (-1 if unconstrained).
+	constraint := func(cell, index int) int {
+		if value, ok := options.constraints[d.getFaceKey(cell, index)]; ok {
+			return value
+		}
+		return -1
+	}
+
+	d.stamp++
+	d.marks[seed] = d.stamp
+	cavity := []int{seed}
+
+	for i := 0; i < len(cavity); i++ {
+		cell := cavity[i]
+
+		for j, neighbor := range d.neighbors[cell] {
+			if value := constraint(cell, j); value != -1 {
+				if options.encroach && d.encroaches(getCellFaces(d.cells[cell])[j], point) {
+					return -1, value, nil
+				}
+				continue
+			}
+
+			if neighbor == -1 || d.marks[neighbor] == d.stamp || !d.inSphere(neighbor, point) {
+				continue
+			}
+
+			d.marks[neighbor] = d.stamp
+			cavity = append(cavity, neighbor)
+		}
+	}
+
+	for _, cell := range cavity {
+		for _, other := range d.cells[cell] {
+			if d.points[other] == point {
+				return other, -1, nil
+			}
+		}
+	}
+
+	// Make the cavity star-shaped from the point so the new cells are
+	// positively oriented. The cavity grows across the faces not visible
+	// from the point or, if a face cannot be crossed, excludes its cell. A
+	// constrained face is on the boundary of the cavity from both sides.
+	excluded := make(map[int]bool)
+
+	for changed := true; changed; {
+		changed = false
+
+		for i := 0; i < len(cavity) && !changed; i++ {
+			cell := cavity[i]
+
+			for j, neighbor := range d.neighbors[cell] {
+				inCavity := neighbor != -1 && d.marks[neighbor] == d.stamp
+				value := constraint(cell, j)
+
+				if inCavity && value == -1 {
+					continue
+				}
+
+				face := getCellFaces(d.cells[cell])[j]
+
+				if d.isAbove([3]int{face[0], face[2], face[1]}, point) {
+					continue
+				}
+
+				if neighbor == -1 {
+					return -1, -1, errOutsideHull
+				}
+
+				if value != -1 && options.encroach {
+					return -1, value, nil
+				}
+
+				if value == -1 && !excluded[neighbor] {
+					d.marks[neighbor] = d.stamp
+					cavity = append(cavity, neighbor)
+					changed = true
+					break
+				}
+
+				if d.contains(cell, point) {
+					if value != -1 {
+						return -1, value, nil
+					}
+					return -1, -1, errInvalidCavity
+				}
+
+				excluded[cell] = true
+				cavity = d.getCavity(seed, excluded)
+				changed = true
+				break
+			}
+		}
+	}
+
+	if options.spacing > 0 {
+		for _, cell := range cavity {
+			for _, other := range d.cells[cell] {
+				if d.points[other].Sub(point).Mag() < options.spacing {
+					return -1, -1, nil
+				}
+			}
+		}
+	}
+
+	region := d.regions[seed]
+	edges := make(map[[2]int][2]int)
+
+	for _, cell := range cavity {
+		for j, neighbor := range d.neighbors[cell] {
+			if neighbor != -1 && d.marks[neighbor] == d.stamp && constraint(cell, j) == -1 {
+				continue
+			}
+
+			face := getCellFaces(d.cells[cell])[j]
+			index := d.addCell([4]int{face[0], face[2], face[1], vertex}, region)
+			d.neighbors[index][3] = neighbor
+
+			if neighbor != -1 {
+				for k := range 4 {
+					if d.neighbors[neighbor][k] == cell {
+						d.neighbors[neighbor][k] = index
+					}
+				}
+			}
+
+			// The face opposite vertex k < 3 is shared with the new cell on
+			// the other side of the edge of the other two vertices.
+			for k := range 3 {
+				a := d.cells[index][(k+1)%3]
+				b := d.cells[index][(k+2)%3]
+				key := [2]int{min(a, b), max(a, b)}
+
+				if other, ok := edges[key]; ok {
+					d.neighbors[index][k] = other[0]
+					d.neighbors[other[0]][other[1]] = index
+					delete(edges, key)
+				} else {
+					edges[key] = [2]int{index, k}
+				}
+			}
+
+			d.last = index
+		}
+	}
+
+	for _, cell := range cavity {
+		d.removeCell(cell)
+	}
+
+	return vertex, -1, nil
+}
+
+// Return true if the point is strictly inside the diametral sphere of a
+// triangle.
+func (d *delaunay) encroaches(triangle [3]int, point meshx.Vector) bool {
+	a := d.points[triangle[0]]
+	b := d.points[triangle[1]]
+	c := d.points[triangle[2]]
+	center := getCircumcenter(a, b, c)
+	radius := a.Sub(center).Mag()
+	return point.Sub(center).Mag() < radius
+}
+
+// Compute the center of the circle through the vertices of a triangle.
+func getCircumcenter(a, b, c meshx.Vector) meshx.Vector {
+	u := b.Sub(a)
+	v := c.Sub(a)
+	n := u.Cross(v)
+	return a.Add(n.Cross(u).MulScalar(v.Dot(v)).
+		Add(v.Cross(n).MulScalar(u.Dot(u))).
+		DivScalar(2 * n.Dot(n)))
+}
+
+// Get the cells of the cavity (marked with the current stamp) connected to
+// the seed without the excluded cells. The cells are marked again with a
+// new stamp.
+func (d *delaunay) getCavity(seed int, excluded map[int]bool) []int {
+	stamp := d.stamp
+	d.stamp++
+	d.marks[seed] = d.stamp
+	cavity := []int{seed}
+
+	for i := 0; i < len(cavity); i++ {
+		for _, neighbor := range d.neighbors[cavity[i]] {
+			if neighbor != -1 && d.marks[neighbor] == stamp && !excluded[neighbor] {
+				d.marks[neighbor] = d.stamp
+				cavity = append(cavity, neighbor)
+			}
+		}
+	}
+
+	return cavity
+}
+
+// Sort the vertices of a triangle.
+func sortTriangle(triangle [3]int) [3]int {
+	if triangle[0] > triangle[1] {
+		triangle[0], triangle[1] = triangle[1], triangle[0]
+	}
+
+	if triangle[1] > triangle[2] {
+		triangle[1], triangle[2] = triangle[2], triangle[1]
+	}
+
+	if triangle[0] > triangle[1] {
+		triangle[0], triangle[1] = triangle[1], triangle[0]
+	}
+
+	return triangle
+}
@@ -0,0 +1,107 @@
+package volume
+
+import (
+	"io"
+	"os"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Write the TetMesh to a Gmsh MSH ASCII file. The boundary faces with a
+// patch are written as triangles in the two-dimensional physical group of
+// the patch (tagged by the patch index plus one).
+func (m *TetMesh) WriteMSH(writer io.Writer, version meshx.MSHVersion) error {
+	faces := make([][]int, 0)
+	facePatches := make([]int, 0)
+
+	for _, face := range m.GetBoundaryFaces() {
+		if patch := m.faces[face].Patch; patch != -1 {
+			faces = append(faces, m.faces[face].Vertices[:])
+			facePatches = append(facePatches, patch)
+		}
+	}
+
+	mshWriter := meshx.NewMSHWriter(writer, version)
+	mshWriter.SetVertices(m.vertices)
+	mshWriter.SetFaces(faces)
+	mshWriter.SetFacePatches(facePatches)
+	mshWriter.SetPatches(m.patches)
+	mshWriter.SetCells(m.cells)
+
+	return mshWriter.Write()
+}
+
+// Write the TetMesh to a Gmsh MSH 4.1 ASCII file path.
+func (m *TetMesh) WriteMSHToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.WriteMSH(file, meshx.MSHVersion4)
+}
+
+// Read a TetMesh from a Gmsh MSH (version 2.2 or 4.1) ASCII file. The
+// tetrahedra are the cells of the mesh. Triangles in a physical group assign
+// the patches of the boundary faces. Other faces are ignored.
+func ReadMSH(reader io.Reader) (*TetMesh, error) {
+	mshReader := meshx.NewMSHReader(reader)
+
+	if err := mshReader.Read(); err != nil {
+		return nil, err
+	}
+
+	return NewTetMeshFromMSHReader(mshReader)
+}
+
+// Read a TetMesh from a Gmsh MSH (version 2.2 or 4.1) ASCII file path.
+func ReadMSHFromPath(path string) (*TetMesh, error) {
+	mshReader, err := meshx.ReadMSHFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTetMeshFromMSHReader(mshReader)
+}
+
+// Construct a TetMesh from the cells and faces of an MSHReader. See ReadMSH.
+func NewTetMeshFromMSHReader(mshReader *meshx.MSHReader) (*TetMesh, error) {
+	vertices := make([]meshx.Vector, mshReader.GetNumberOfVertices())
+	cells := make([][4]int, mshReader.GetNumberOfCells())
+
+	for i := range vertices {
+		vertices[i] = mshReader.GetVertex(i)
+	}
+
+	for i := range cells {
+		cells[i] = mshReader.GetCell(i)
+	}
+
+	mesh, err := NewTetMesh(vertices, cells)
+	if err != nil {
+		return nil, err
+	}
+
+	surface := surfaceSource{}
+
+	for i := range mshReader.GetNumberOfPatches() {
+		surface.patches = append(surface.patches, mshReader.GetPatch(i))
+	}
+
+	for i := range mshReader.GetNumberOfFaces() {
+		face := mshReader.GetFace(i)
+		patch := mshReader.GetFacePatch(i)
+
+		if len(face) == 3 && patch != -1 {
+			surface.faces = append(surface.faces, face)
+			surface.facePatches = append(surface.facePatches, patch)
+		}
+	}
+
+	if err := mesh.SetBoundaryPatches(&surface); err != nil {
+		return nil, err
+	}
+
+	return mesh, nil
+}
@@ -0,0 +1,28 @@
+package volume
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test writing and reading a tetrahedral mesh as a Gmsh MSH file.
+func TestWriteMSH(t *testing.T) {
+	mesh := newTestCube(t)
+
+	for _, version := range []meshx.MSHVersion{meshx.MSHVersion2, meshx.MSHVersion4} {
+		var buffer bytes.Buffer
+		assert.Empty(t, mesh.WriteMSH(&buffer, version))
+		assert.Contains(t, buffer.String(), "2 6 \"zmax\"\n")
+
+		result, err := ReadMSH(&buffer)
+		assert.Empty(t, err)
+		assert.Equal(t, mesh, result)
+	}
+
+	_, err := ReadMSH(strings.NewReader("$MeshFormat\n3.0 0 8\n$EndMeshFormat\n"))
+	assert.ErrorIs(t, err, meshx.ErrMSHInvalidHeader)
+}
@@ -0,0 +1,350 @@
+// Package volume implements volume meshes built from tetrahedral cells.
+package volume
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+var (
+	ErrInvalidCell  = errors.New("invalid cell")
+	ErrInvalidPatch = errors.New("invalid patch")
+)
+
+// Triangular face of a TetMesh shared by one (boundary) or two cells. The
+// vertices are ordered so the normal points out of the owner cell. The
+// neighbor is -1 on the boundary. The patch of a boundary face is -1 if
+// unassigned.
+type Face struct {
+	Vertices [3]int
+	Owner    int
+	Neighbor int
+	Patch    int
+}
+
+// Return true if the face is on the boundary (no neighbor).
+func (f Face) IsBoundary() bool {
+	return f.Neighbor < 0
+}
+
+// Tetrahedral volume mesh. The faces are derived from the cells and the
+// boundary faces optionally belong to named patches.
+type TetMesh struct {
+	vertices []meshx.Vector
+	cells    [][4]int
+	faces    []Face
+	patches  []string
+}
+
+// Construct a TetMesh from its vertices and cells. Cells with a negative
+// orientation are reoriented. An error is returned if a face is shared by
+// more than two cells.
+func NewTetMesh(vertices []meshx.Vector, cells [][4]int) (*TetMesh, error) {
+	mesh := TetMesh{
+		vertices: vertices,
+		cells:    make([][4]int, len(cells)),
+		faces:    make([]Face, 0, 2*len(cells)),
+		patches:  make([]string, 0),
+	}
+
+	for i, cell := range cells {
+		for _, vertex := range cell {
+			if vertex < 0 || vertex >= len(vertices) {
+				return nil, ErrInvalidCell
+			}
+		}
+
+		if mesh.getTetrahedron(cell).SignedVolume() < 0 {
+			cell[2], cell[3] = cell[3], cell[2]
+		}
+
+		mesh.cells[i] = cell
+	}
+
+	if err := mesh.buildFaces(); err != nil {
+		return nil, err
+	}
+
+	return &mesh, nil
+}
+
+// Build the faces shared between the cells.
+func (m *TetMesh) buildFaces() error {
+	indexFaces := make(map[[3]int]int)
+
+	for i, cell := range m.cells {
+		for _, vertices := range getCellFaces(cell) {
+			key := vertices
+			sort.Ints(key[:])
+
+			index, ok := indexFaces[key]
+
+			if !ok {
+				indexFaces[key] = len(m.faces)
+				m.faces = append(m.faces, Face{vertices, i, -1, -1})
+				continue
+			}
+
+			if !m.faces[index].IsBoundary() {
+				return meshx.ErrNonManifold
+			}
+
+			m.faces[index].Neighbor = i
+		}
+	}
+
+	return nil
+}
+
+// Get the faces of a cell ordered so the normals point outward for a
+// positive orientation. Face i is opposite vertex i.
+func getCellFaces(cell [4]int) [4][3]int {
+	return [4][3]int{
+		{cell[1], cell[2], cell[3]},
+		{cell[0], cell[3], cell[2]},
+		{cell[0], cell[1], cell[3]},
+		{cell[0], cell[2], cell[1]},
+	}
+}
+
+// Get the tetrahedron of a cell.
+func (m *TetMesh) getTetrahedron(cell [4]int) Tetrahedron {
+	return NewTetrahedron(
+		m.vertices[cell[0]],
+		m.vertices[cell[1]],
+		m.vertices[cell[2]],
+		m.vertices[cell[3]],
+	)
+}
+
+// Get the number of vertices.
+func (m *TetMesh) GetNumberOfVertices() int {
+	return len(m.vertices)
+}
+
+// Get a vertex by index.
+func (m *TetMesh) GetVertex(index int) meshx.Vector {
+	return m.vertices[index]
+}
+
+// Get the number of cells.
+func (m *TetMesh) GetNumberOfCells() int {
+	return len(m.cells)
+}
+
+// Get the vertex indices of a cell by index.
+func (m *TetMesh) GetCell(index int) [4]int {
+	return m.cells[index]
+}
+
+// Get the tetrahedron of a cell by index.
+func (m *TetMesh) GetCellTetrahedron(index int) Tetrahedron {
+	return m.getTetrahedron(m.cells[index])
+}
+
+// Get the number of faces.
+func (m *TetMesh) GetNumberOfFaces() int {
+	return len(m.faces)
+}
+
+// Get a face by index.
+func (m *TetMesh) GetFace(index int) Face {
+	return m.faces[index]
+}
+
+// Get the boundary faces.
+func (m *TetMesh) GetBoundaryFaces() []int {
+	faces := make([]int, 0)
+
+	for i, face := range m.faces {
+		if face.IsBoundary() {
+			faces = append(faces, i)
+		}
+	}
+
+	return faces
+}
+
+// Get the number of patches.
+func (m *TetMesh) GetNumberOfPatches() int {
+	return len(m.patches)
+}
+
+// Get a patch name by index.
+func (m *TetMesh) GetPatch(index int) string {
+	return m.patches[index]
+}
+
+// Add a patch by name and return its index.
+func (m *TetMesh) AddPatch(name string) int {
+	m.patches = append(m.patches, name)
+	return len(m.patches) - 1
+}
+
+// Set the patch of a boundary face (-1 to unassign).
+func (m *TetMesh) SetFacePatch(index, patch int) error {
+	if !m.faces[index].IsBoundary() || patch < -1 || patch >= len(m.patches) {
+		return ErrInvalidPatch
+	}
+
+	m.faces[index].Patch = patch
+
+	return nil
+}
+
+// Get the boundary faces of a patch.
+func (m *TetMesh) GetPatchFaces(patch int) []int {
+	faces := make([]int, 0)
+
+	for i, face := range m.faces {
+		if face.IsBoundary() && face.Patch == patch {
+			faces = append(faces, i)
+		}
+	}
+
+	return faces
+}
+
+// Set the patches of the boundary faces from the triangles of a surface
+// indexed by the vertices of the volume mesh. The patches of the surface
+// are added to the volume mesh (or reused by name). An error is returned if
+// a triangle is not a boundary face.
+func (m *TetMesh) SetBoundaryPatches(source meshx.MeshReader) error {
+	index := m.indexBoundaryFaces()
+	patchMap := make([]int, source.GetNumberOfPatches())
+
+	for i := range patchMap {
+		patchMap[i] = -1
+		name := source.GetPatch(i)
+
+		for j, patch := range m.patches {
+			if patch == name {
+				patchMap[i] = j
+			}
+		}
+
+		if patchMap[i] == -1 {
+			patchMap[i] = m.AddPatch(name)
+		}
+	}
+
+	for i := range source.GetNumberOfFaces() {
+		vertices := source.GetFace(i)
+
+		if len(vertices) != 3 {
+			return ErrInvalidPatch
+		}
+
+		face, ok := m.findBoundaryFace([3]int{vertices[0], vertices[1], vertices[2]}, index)
+
+		if !ok {
+			return ErrInvalidPatch
+		}
+
+		if patch := source.GetFacePatch(i); patch != -1 {
+			m.faces[face].Patch = patchMap[patch]
+		}
+	}
+
+	return nil
+}
+
+// Get the boundary face with the vertices (in any order). The boolean is
+// false if there is no such boundary face.
+func (m *TetMesh) findBoundaryFace(vertices [3]int, index map[[3]int]int) (int, bool) {
+	sort.Ints(vertices[:])
+	face, ok := index[vertices]
+	return face, ok
+}
+
+// Index the boundary faces by their sorted vertices.
+func (m *TetMesh) indexBoundaryFaces() map[[3]int]int {
+	index := make(map[[3]int]int)
+
+	for i, face := range m.faces {
+		if face.IsBoundary() {
+			key := face.Vertices
+			sort.Ints(key[:])
+			index[key] = i
+		}
+	}
+
+	return index
+}
+
+// Compute the total volume.
+func (m *TetMesh) GetVolume() float64 {
+	var volume float64
+
+	for i := range m.cells {
+		volume += m.GetCellTetrahedron(i).Volume()
+	}
+
+	return volume
+}
+
+// Get the axis-aligned bounding box.
+func (m *TetMesh) GetAABB() meshx.AABB {
+	return meshx.NewAABBFromVectors(m.vertices)
+}
+
+// Get a MeshReader of the boundary faces (oriented outward) with their
+// patches. The vertices are those of the volume mesh.
+func (m *TetMesh) GetBoundaryMeshReader() meshx.MeshReader {
+	boundary := boundaryReader{mesh: m, faces: m.GetBoundaryFaces()}
+	return &boundary
+}
+
+// MeshReader of the boundary faces of a TetMesh.
+type boundaryReader struct {
+	mesh  *TetMesh
+	faces []int
+}
+
+// Implement the MeshReader interface.
+func (r *boundaryReader) Read() error {
+	return nil
+}
+
+// Implement the MeshReader interface.
+func (r *boundaryReader) GetNumberOfVertices() int {
+	return r.mesh.GetNumberOfVertices()
+}
+
+// Implement the MeshReader interface.
+func (r *boundaryReader) GetNumberOfFaces() int {
+	return len(r.faces)
+}
+
+// Implement the MeshReader interface.
+func (r *boundaryReader) GetNumberOfFaceEdges() int {
+	return 3 * len(r.faces)
+}
+
+// Implement the MeshReader interface.
+func (r *boundaryReader) GetNumberOfPatches() int {
+	return r.mesh.GetNumberOfPatches()
+}
+
+// Implement the MeshReader interface.
+func (r *boundaryReader) GetVertex(index int) meshx.Vector {
+	return r.mesh.GetVertex(index)
+}
+
+// Implement the MeshReader interface.
+func (r *boundaryReader) GetFace(index int) []int {
+	face := r.mesh.faces[r.faces[index]]
+	return face.Vertices[:]
+}
+
+// Implement the MeshReader interface.
+func (r *boundaryReader) GetFacePatch(index int) int {
+	return r.mesh.faces[r.faces[index]].Patch
+}
+
+// Implement the MeshReader interface.
+func (r *boundaryReader) GetPatch(index int) string {
+	return r.mesh.GetPatch(index)
+}
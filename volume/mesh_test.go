@@ -0,0 +1,111 @@
+package volume
+
+import (
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate the unit cube split into six tetrahedra about its diagonal with
+// the boundary faces assigned to a patch per side.
+func newTestCube(t *testing.T) *TetMesh {
+	vertices := make([]meshx.Vector, 8)
+
+	for i := range vertices {
+		vertices[i] = meshx.NewVector(float64(i&1), float64(i>>1&1), float64(i>>2&1))
+	}
+
+	cells := [][4]int{
+		{0, 1, 3, 7}, {0, 1, 5, 7}, {0, 2, 3, 7},
+		{0, 2, 6, 7}, {0, 4, 5, 7}, {0, 4, 6, 7},
+	}
+
+	mesh, err := NewTetMesh(vertices, cells)
+	assert.Empty(t, err)
+
+	names := []string{"xmin", "xmax", "ymin", "ymax", "zmin", "zmax"}
+
+	for _, name := range names {
+		mesh.AddPatch(name)
+	}
+
+	for _, index := range mesh.GetBoundaryFaces() {
+		face := mesh.GetFace(index)
+		p := mesh.GetVertex(face.Vertices[0])
+		q := mesh.GetVertex(face.Vertices[1])
+		r := mesh.GetVertex(face.Vertices[2])
+		normal := meshx.NewTriangle(p, q, r).UnitNormal()
+
+		for axis := range 3 {
+			if normal[axis] < -0.5 {
+				assert.Empty(t, mesh.SetFacePatch(index, 2*axis))
+			} else if normal[axis] > 0.5 {
+				assert.Empty(t, mesh.SetFacePatch(index, 2*axis+1))
+			}
+		}
+	}
+
+	return mesh
+}
+
+// Test constructing a tetrahedral mesh.
+func TestNewTetMesh(t *testing.T) {
+	mesh := newTestCube(t)
+	assert.Equal(t, 6, mesh.GetNumberOfCells())
+	assert.Equal(t, 18, mesh.GetNumberOfFaces())
+	assert.Equal(t, 12, len(mesh.GetBoundaryFaces()))
+	assert.InDelta(t, 1, mesh.GetVolume(), 1e-12)
+
+	for i := range mesh.GetNumberOfCells() {
+		assert.Greater(t, mesh.GetCellTetrahedron(i).SignedVolume(), 0.0)
+	}
+
+	for i := range mesh.GetNumberOfPatches() {
+		assert.Equal(t, 2, len(mesh.GetPatchFaces(i)))
+	}
+
+	for i := range mesh.GetNumberOfFaces() {
+		if face := mesh.GetFace(i); !face.IsBoundary() {
+			assert.NotEqual(t, face.Owner, face.Neighbor)
+			assert.Equal(t, -1, face.Patch)
+			assert.ErrorIs(t, mesh.SetFacePatch(i, 0), ErrInvalidPatch)
+		}
+	}
+
+	assert.ErrorIs(t, mesh.SetFacePatch(mesh.GetBoundaryFaces()[0], 6), ErrInvalidPatch)
+}
+
+// Test constructing an invalid tetrahedral mesh.
+func TestNewTetMeshInvalid(t *testing.T) {
+	vertices := []meshx.Vector{
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(0, 1, 0),
+		meshx.NewVector(0, 0, 1),
+		meshx.NewVector(0, 0, -1),
+		meshx.NewVector(1, 1, 1),
+	}
+
+	_, err := NewTetMesh(vertices, [][4]int{{0, 1, 2, 6}})
+	assert.ErrorIs(t, err, ErrInvalidCell)
+
+	_, err = NewTetMesh(vertices, [][4]int{{0, 1, 2, 3}, {0, 1, 2, 4}, {0, 1, 2, 5}})
+	assert.ErrorIs(t, err, meshx.ErrNonManifold)
+}
+
+// Test the boundary of a tetrahedral mesh as a surface.
+func TestGetBoundaryMeshReader(t *testing.T) {
+	mesh := newTestCube(t)
+	boundary := mesh.GetBoundaryMeshReader()
+	assert.Equal(t, 12, boundary.GetNumberOfFaces())
+	assert.Equal(t, 6, boundary.GetNumberOfPatches())
+
+	result, err := NewTetMesh(mesh.vertices, mesh.cells)
+	assert.Empty(t, err)
+	assert.Empty(t, result.SetBoundaryPatches(boundary))
+	assert.Equal(t, mesh, result)
+
+	surface := surfaceSource{faces: [][]int{{0, 1, 7}}, facePatches: []int{-1}}
+	assert.ErrorIs(t, result.SetBoundaryPatches(&surface), ErrInvalidPatch)
+}
@@ -0,0 +1,75 @@
+package volume
+
+import (
+	"math"
+)
+
+// Quality metrics of a tetrahedral cell. The angles are in radians.
+type CellQuality struct {
+	Volume           float64
+	MinDihedralAngle float64
+	MaxDihedralAngle float64
+	AspectRatio      float64
+}
+
+// Summary of the quality metrics over the cells of a TetMesh.
+type QualitySummary struct {
+	MinVolume        float64
+	MaxVolume        float64
+	MinDihedralAngle float64
+	MaxDihedralAngle float64
+	MaxAspectRatio   float64
+	MeanAspectRatio  float64
+}
+
+// Compute the quality metrics of a tetrahedron.
+func ComputeCellQuality(t Tetrahedron) CellQuality {
+	quality := CellQuality{
+		Volume:           t.SignedVolume(),
+		MinDihedralAngle: math.Inf(1),
+		MaxDihedralAngle: math.Inf(-1),
+		AspectRatio:      t.AspectRatio(),
+	}
+
+	for _, angle := range t.DihedralAngles() {
+		quality.MinDihedralAngle = min(quality.MinDihedralAngle, angle)
+		quality.MaxDihedralAngle = max(quality.MaxDihedralAngle, angle)
+	}
+
+	return quality
+}
+
+// Compute the quality metrics of each cell.
+func (m *TetMesh) ComputeQuality() []CellQuality {
+	qualities := make([]CellQuality, len(m.cells))
+
+	for i := range m.cells {
+		qualities[i] = ComputeCellQuality(m.GetCellTetrahedron(i))
+	}
+
+	return qualities
+}
+
+// Summarize the quality metrics over the cells.
+func (m *TetMesh) ComputeQualitySummary() QualitySummary {
+	summary := QualitySummary{
+		MinVolume:        math.Inf(1),
+		MaxVolume:        math.Inf(-1),
+		MinDihedralAngle: math.Inf(1),
+		MaxDihedralAngle: math.Inf(-1),
+		MaxAspectRatio:   math.Inf(-1),
+	}
+
+	qualities := m.ComputeQuality()
+
+	for _, quality := range qualities {
+		summary.MinVolume = min(summary.MinVolume, quality.Volume)
+		summary.MaxVolume = max(summary.MaxVolume, quality.Volume)
+		summary.MinDihedralAngle = min(summary.MinDihedralAngle, quality.MinDihedralAngle)
+		summary.MaxDihedralAngle = max(summary.MaxDihedralAngle, quality.MaxDihedralAngle)
+		summary.MaxAspectRatio = max(summary.MaxAspectRatio, quality.AspectRatio)
+		summary.MeanAspectRatio += quality.AspectRatio / float64(len(qualities))
+	}
+
+	return summary
+}
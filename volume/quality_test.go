@@ -0,0 +1,29 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test the quality metrics of the cells of a tetrahedral mesh.
+func TestComputeQuality(t *testing.T) {
+	mesh := newTestCube(t)
+	qualities := mesh.ComputeQuality()
+	assert.Equal(t, 6, len(qualities))
+
+	for _, quality := range qualities {
+		assert.InDelta(t, 1.0/6.0, quality.Volume, 1e-12)
+		assert.InDelta(t, math.Pi/4, quality.MinDihedralAngle, 1e-12)
+		assert.InDelta(t, math.Pi/2, quality.MaxDihedralAngle, 1e-12)
+		assert.Greater(t, quality.AspectRatio, 1.0)
+	}
+
+	summary := mesh.ComputeQualitySummary()
+	assert.InDelta(t, 1.0/6.0, summary.MinVolume, 1e-12)
+	assert.InDelta(t, qualities[0].AspectRatio, summary.MeanAspectRatio, 1e-12)
+
+	quality := ComputeCellQuality(newTestRegularTetrahedron())
+	assert.InDelta(t, 1, quality.AspectRatio, 1e-12)
+}
@@ -0,0 +1,60 @@
+package volume
+
+import (
+	"github.com/ajcurley/meshx-go"
+)
+
+// In-memory surface of triangles indexed by the vertices of a TetMesh used
+// to assign the patches of its boundary faces. The vertices are optional.
+type surfaceSource struct {
+	vertices    []meshx.Vector
+	faces       [][]int
+	facePatches []int
+	patches     []string
+}
+
+// Implement the MeshReader interface.
+func (s *surfaceSource) Read() error {
+	return nil
+}
+
+// Implement the MeshReader interface. The vertices are those of the volume
+// mesh and are not used unless given.
+func (s *surfaceSource) GetNumberOfVertices() int {
+	return len(s.vertices)
+}
+
+// Implement the MeshReader interface.
+func (s *surfaceSource) GetNumberOfFaces() int {
+	return len(s.faces)
+}
+
+// Implement the MeshReader interface.
+func (s *surfaceSource) GetNumberOfFaceEdges() int {
+	return 3 * len(s.faces)
+}
+
+// Implement the MeshReader interface.
+func (s *surfaceSource) GetNumberOfPatches() int {
+	return len(s.patches)
+}
+
+// Implement the MeshReader interface.
+func (s *surfaceSource) GetVertex(index int) meshx.Vector {
+	return s.vertices[index]
+}
+
+// Implement the MeshReader interface.
+func (s *surfaceSource) GetFace(index int) []int {
+	return s.faces[index]
+}
+
+// Implement the MeshReader interface.
+func (s *surfaceSource) GetFacePatch(index int) int {
+	return s.facePatches[index]
+}
+
+// Implement the MeshReader interface.
+func (s *surfaceSource) GetPatch(index int) string {
+	return s.patches[index]
+}
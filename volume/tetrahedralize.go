@@ -0,0 +1,560 @@
+package volume
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+)
+
+const (
+	defaultMaxRadiusEdgeRatio = 2
+	defaultMaxSteinerPoints   = 1 << 16
+	maxRecoveryRounds         = 32
+)
+
+var (
+	ErrOpenSurface      = errors.New("surface is not closed")
+	ErrBoundaryRecovery = errors.New("cannot recover the boundary")
+	ErrEmptyVolume      = errors.New("no cells inside the surface")
+)
+
+// Options to generate a TetMesh. The zero value refines the cells to the
+// default radius-edge ratio without size control.
+type TetrahedralizeOptions struct {
+	// Maximum ratio of the circumradius to the shortest edge of a cell
+	// (default 2). Larger values refine less.
+	MaxRadiusEdgeRatio float64
+
+	// Maximum edge length of a cell (zero for no limit).
+	MaxEdgeLength float64
+
+	// Maximum edge length of a cell evaluated at its centroid (nil for no
+	// limit). If given, MaxEdgeLength is ignored.
+	SizeFunc func(point meshx.Vector) float64
+
+	// Maximum number of points inserted to recover the boundary and refine
+	// the cells (default 65536). The refinement stops once reached.
+	MaxSteinerPoints int
+}
+
+// Generate a TetMesh filling the interior of a closed surface with the
+// default options. See TetrahedralizeWithOptions.
+func Tetrahedralize(mesh *halfedge.HalfEdgeMesh) (*TetMesh, error) {
+	return TetrahedralizeWithOptions(mesh, TetrahedralizeOptions{})
+}
+
+// Generate a TetMesh filling the interior of a closed surface by Delaunay
+// refinement. The surface faces are triangulated and recovered as boundary
+// faces by splitting their edges as needed (conforming Delaunay), so the
+// boundary may have more triangles than the surface. The boundary faces are
+// tagged by the patch of the surface face they lie on. Cells exceeding the
+// radius-edge ratio or size are refined by inserting their circumcenter; if
+// it encroaches a boundary face larger than the size, the boundary face is
+// split instead. Cells near boundary faces within the size may therefore
+// exceed the ratio or size. Nested surfaces bound cavities.
+func TetrahedralizeWithOptions(mesh *halfedge.HalfEdgeMesh, options TetrahedralizeOptions) (*TetMesh, error) {
+	if !mesh.IsClosed() {
+		return nil, ErrOpenSurface
+	}
+
+	if options.MaxRadiusEdgeRatio <= 0 {
+		options.MaxRadiusEdgeRatio = defaultMaxRadiusEdgeRatio
+	}
+
+	if options.MaxSteinerPoints <= 0 {
+		options.MaxSteinerPoints = defaultMaxSteinerPoints
+	}
+
+	points := make([]meshx.Vector, mesh.GetNumberOfVertices())
+
+	for i := range points {
+		points[i] = mesh.GetVertex(i).Point
+	}
+
+	boundary := newSurface()
+
+	for i := range mesh.GetNumberOfFaces() {
+		vertices := mesh.GetFaceVertices(i)
+
+		for j := 2; j < len(vertices); j++ {
+			boundary.add([3]int{vertices[0], vertices[j-1], vertices[j]}, mesh.GetFace(i).Patch)
+		}
+	}
+
+	d, err := newDelaunay(points)
+	if err != nil {
+		return nil, err
+	}
+
+	t := tetrahedralizer{d, boundary, options, 0}
+
+	if err := t.recover(); err != nil {
+		return nil, err
+	}
+
+	t.classify()
+
+	if err := t.refine(); err != nil {
+		return nil, err
+	}
+
+	patches := make([]string, mesh.GetNumberOfPatches())
+
+	for i := range patches {
+		patches[i] = mesh.GetPatch(i).Name
+	}
+
+	return t.build(patches)
+}
+
+// Delaunay tetrahedralization constrained to conform to a surface.
+type tetrahedralizer struct {
+	delaunay *delaunay
+	surface  *surface
+	options  TetrahedralizeOptions
+	inserted int
+}
+
+// Split the edges of the surface triangles missing from the
+// tetrahedralization until all are recovered.
+func (t *tetrahedralizer) recover() error {
+	d := t.delaunay
+	s := t.surface
+
+	for range maxRecoveryRounds {
+		faces := d.getFaceKeys()
+		edges := make([][2]int, 0)
+		split := make(map[[2]int]bool)
+
+		for _, triangle := range s.triangles {
+			if faces[sortTriangle(triangle)] {
+				continue
+			}
+
+			edge := t.getLongestEdge(triangle)
+			key := getEdgeKey(edge[0], edge[1])
+
+			if !split[key] {
+				split[key] = true
+				edges = append(edges, key)
+			}
+		}
+
+		if len(edges) == 0 {
+			return nil
+		}
+
+		for _, edge := range edges {
+			if err := t.splitEdge(edge[0], edge[1]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return ErrBoundaryRecovery
+}
+
+// Get the longest edge of a triangle.
+func (t *tetrahedralizer) getLongestEdge(triangle [3]int) [2]int {
+	points := t.delaunay.points
+	edge := [2]int{}
+	length := -1.0
+
+	for i := range 3 {
+		a, b := triangle[i], triangle[(i+1)%3]
+
+		if l := points[b].Sub(points[a]).Mag(); l > length {
+			edge = [2]int{a, b}
+			length = l
+		}
+	}
+
+	return edge
+}
+
+// Classify the cells as inside (region 1) or outside (region 0) the
+// surface by flooding from the super tetrahedron and alternating the
+// region across each surface face.
+func (t *tetrahedralizer) classify() {
+	d := t.delaunay
+	queue := make([]int, 0)
+
+	for cell := range d.cells {
+		d.regions[cell] = -1
+
+		if d.alive[cell] && d.isSuper(cell) {
+			d.regions[cell] = 0
+			queue = append(queue, cell)
+		}
+	}
+
+	for i := 0; i < len(queue); i++ {
+		cell := queue[i]
+
+		for j, neighbor := range d.neighbors[cell] {
+			if neighbor == -1 || d.regions[neighbor] != -1 {
+				continue
+			}
+
+			region := d.regions[cell]
+
+			if _, ok := t.surface.index[d.getFaceKey(cell, j)]; ok {
+				region = 1 - region
+			}
+
+			d.regions[neighbor] = region
+			queue = append(queue, neighbor)
+		}
+	}
+}
+
+// Return true if an inside cell exceeds the radius-edge ratio or size.
+func (t *tetrahedralizer) isBad(cell int) bool {
+	tetrahedron := t.delaunay.getTetrahedron(cell)
+	shortest, _ := getEdgeLengths(tetrahedron)
+
+	if tetrahedron.Circumradius() > t.options.MaxRadiusEdgeRatio*shortest {
+		return true
+	}
+
+	return t.isLargeCell(tetrahedron)
+}
+
+// Return true if a cell exceeds the size.
+func (t *tetrahedralizer) isLargeCell(tetrahedron Tetrahedron) bool {
+	_, longest := getEdgeLengths(tetrahedron)
+	size := t.getSize(tetrahedron.Centroid())
+	return size > 0 && longest > size
+}
+
+// Refine a cell by inserting its circumcenter. The spacing bounds the edge
+// lengths from below so the refinement terminates near the boundary, where
+// the cells are not necessarily Delaunay. The inserted vertex (-1 if none)
+// and the encroached surface triangle (-1 if none) are returned.
+func (t *tetrahedralizer) refineCell(cell int) (int, int, error) {
+	tetrahedron := t.delaunay.getTetrahedron(cell)
+	center, ok := tetrahedron.Circumcenter()
+
+	if !ok {
+		return -1, -1, nil
+	}
+
+	shortest, _ := getEdgeLengths(tetrahedron)
+	spacing := min(shortest, tetrahedron.Circumradius())
+	options := insertion{cell, t.surface.index, true, spacing}
+	return t.delaunay.insert(center, options)
+}
+
+// Get the shortest and longest edge lengths of a tetrahedron.
+func getEdgeLengths(tetrahedron Tetrahedron) (float64, float64) {
+	vertices := tetrahedron.getVertices()
+	shortest, longest := math.Inf(1), 0.0
+
+	for i := range 4 {
+		for j := i + 1; j < 4; j++ {
+			length := vertices[j].Sub(vertices[i]).Mag()
+			shortest = min(shortest, length)
+			longest = max(longest, length)
+		}
+	}
+
+	return shortest, longest
+}
+
+// Return true if a surface triangle exceeds the size.
+func (t *tetrahedralizer) isLarge(triangle [3]int) bool {
+	points := t.delaunay.points
+	edge := t.getLongestEdge(triangle)
+	length := points[edge[1]].Sub(points[edge[0]]).Mag()
+	centroid := points[triangle[0]].Add(points[triangle[1]]).Add(points[triangle[2]]).DivScalar(3)
+	size := t.getSize(centroid)
+	return size > 0 && length > size
+}
+
+// Get the maximum edge length at a point (zero for no limit).
+func (t *tetrahedralizer) getSize(point meshx.Vector) float64 {
+	if t.options.SizeFunc != nil {
+		return t.options.SizeFunc(point)
+	}
+	return t.options.MaxEdgeLength
+}
+
+// Refine the inside cells exceeding the radius-edge ratio or size. Points
+// encroaching the surface are not inserted; the encroached surface
+// triangles exceeding the size are split instead, after which the surface
+// is recovered and the cells reclassified.
+func (t *tetrahedralizer) refine() error {
+	d := t.delaunay
+	s := t.surface
+
+	for t.inserted < t.options.MaxSteinerPoints {
+		bad := make([]int, 0)
+		cells := make([][4]int, 0)
+
+		for cell := range d.cells {
+			if d.alive[cell] && d.regions[cell] == 1 && t.isBad(cell) {
+				bad = append(bad, cell)
+				cells = append(cells, d.cells[cell])
+			}
+		}
+
+		encroached := make([][3]int, 0)
+		progress := false
+
+		for i, cell := range bad {
+			if t.inserted >= t.options.MaxSteinerPoints {
+				break
+			}
+
+			// The cell may have been replaced by an earlier insertion.
+			if !d.alive[cell] || d.cells[cell] != cells[i] {
+				continue
+			}
+
+			vertex, conflict, err := t.refineCell(cell)
+			if err != nil {
+				return err
+			}
+
+			if conflict != -1 && t.isLarge(s.triangles[conflict]) {
+				encroached = append(encroached, sortTriangle(s.triangles[conflict]))
+			}
+
+			if vertex != -1 {
+				t.inserted++
+				progress = true
+			}
+		}
+
+		if len(encroached) == 0 {
+			if !progress {
+				break
+			}
+			continue
+		}
+
+		for _, key := range encroached {
+			if index, ok := s.index[key]; ok && t.inserted < t.options.MaxSteinerPoints {
+				edge := t.getLongestEdge(s.triangles[index])
+
+				if err := t.splitEdge(edge[0], edge[1]); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := t.recover(); err != nil {
+			return err
+		}
+
+		t.classify()
+	}
+
+	return nil
+}
+
+// Split the surface triangles sharing an edge at its midpoint.
+func (t *tetrahedralizer) splitEdge(a, b int) error {
+	d := t.delaunay
+	midpoint := d.points[a].Add(d.points[b]).MulScalar(0.5)
+	triangles := append([]int(nil), t.surface.edges[getEdgeKey(a, b)]...)
+
+	vertex, err := t.insertOnSurface(midpoint, triangles)
+	if err != nil {
+		return err
+	}
+
+	t.surface.splitEdge(a, b, vertex)
+	return nil
+}
+
+// Insert a point on the surface triangles to split. The other surface
+// triangles are kept.
+func (t *tetrahedralizer) insertOnSurface(point meshx.Vector, triangles []int) (int, error) {
+	s := t.surface
+	nPoints := len(t.delaunay.points)
+
+	for _, index := range triangles {
+		delete(s.index, sortTriangle(s.triangles[index]))
+	}
+
+	vertex, conflict, err := t.delaunay.insert(point, insertion{seed: -1, constraints: s.index})
+
+	for _, index := range triangles {
+		s.index[sortTriangle(s.triangles[index])] = index
+	}
+
+	if err != nil {
+		return -1, err
+	}
+
+	if conflict != -1 || vertex < nPoints {
+		return -1, ErrBoundaryRecovery
+	}
+
+	t.inserted++
+	return vertex, nil
+}
+
+// Build the TetMesh of the inside cells with the boundary faces tagged by
+// the patches of the surface.
+func (t *tetrahedralizer) build(patches []string) (*TetMesh, error) {
+	d := t.delaunay
+	s := t.surface
+	indexVertices := make(map[int]int)
+	vertices := make([]meshx.Vector, 0)
+	cells := make([][4]int, 0)
+
+	for cell := range d.cells {
+		if !d.alive[cell] || d.regions[cell] != 1 {
+			continue
+		}
+
+		var remapped [4]int
+
+		for i, vertex := range d.cells[cell] {
+			index, ok := indexVertices[vertex]
+
+			if !ok {
+				index = len(vertices)
+				indexVertices[vertex] = index
+				vertices = append(vertices, d.points[vertex])
+			}
+
+			remapped[i] = index
+		}
+
+		cells = append(cells, remapped)
+	}
+
+	if len(cells) == 0 {
+		return nil, ErrEmptyVolume
+	}
+
+	mesh, err := NewTetMesh(vertices, cells)
+	if err != nil {
+		return nil, err
+	}
+
+	source := surfaceSource{
+		faces:       make([][]int, len(s.triangles)),
+		facePatches: s.patches,
+		patches:     patches,
+	}
+
+	for i, triangle := range s.triangles {
+		source.faces[i] = make([]int, 3)
+
+		for j, vertex := range triangle {
+			index, ok := indexVertices[vertex]
+
+			if !ok {
+				return nil, ErrBoundaryRecovery
+			}
+
+			source.faces[i][j] = index
+		}
+	}
+
+	if len(mesh.GetBoundaryFaces()) != len(s.triangles) {
+		return nil, ErrBoundaryRecovery
+	}
+
+	if err := mesh.SetBoundaryPatches(&source); err != nil {
+		return nil, ErrBoundaryRecovery
+	}
+
+	return mesh, nil
+}
+
+// Triangulated surface constraining a tetrahedralization. The triangles
+// are indexed by their sorted vertices and their edges.
+type surface struct {
+	triangles [][3]int
+	patches   []int
+	index     map[[3]int]int
+	edges     map[[2]int][]int
+}
+
+// Construct an empty surface.
+func newSurface() *surface {
+	return &surface{
+		triangles: make([][3]int, 0),
+		patches:   make([]int, 0),
+		index:     make(map[[3]int]int),
+		edges:     make(map[[2]int][]int),
+	}
+}
+
+// Add a triangle with its patch.
+func (s *surface) add(triangle [3]int, patch int) {
+	s.triangles = append(s.triangles, triangle)
+	s.patches = append(s.patches, patch)
+	s.link(len(s.triangles) - 1)
+}
+
+// Index a triangle by its vertices and edges.
+func (s *surface) link(index int) {
+	triangle := s.triangles[index]
+	s.index[sortTriangle(triangle)] = index
+
+	for i := range 3 {
+		key := getEdgeKey(triangle[i], triangle[(i+1)%3])
+		s.edges[key] = append(s.edges[key], index)
+	}
+}
+
+// Remove the index of a triangle by its vertices and edges.
+func (s *surface) unlink(index int) {
+	triangle := s.triangles[index]
+	delete(s.index, sortTriangle(triangle))
+
+	for i := range 3 {
+		key := getEdgeKey(triangle[i], triangle[(i+1)%3])
+		triangles := s.edges[key]
+
+		for j, other := range triangles {
+			if other == index {
+				s.edges[key] = append(triangles[:j], triangles[j+1:]...)
+				break
+			}
+		}
+
+		if len(s.edges[key]) == 0 {
+			delete(s.edges, key)
+		}
+	}
+}
+
+// Replace a triangle.
+func (s *surface) set(index int, triangle [3]int) {
+	s.unlink(index)
+	s.triangles[index] = triangle
+	s.link(index)
+}
+
+// Split the triangles sharing an edge at a vertex on the edge.
+func (s *surface) splitEdge(a, b, vertex int) {
+	triangles := append([]int(nil), s.edges[getEdgeKey(a, b)]...)
+
+	for _, index := range triangles {
+		triangle := s.triangles[index]
+
+		for i := range 3 {
+			u, v, w := triangle[i], triangle[(i+1)%3], triangle[(i+2)%3]
+
+			if getEdgeKey(u, v) == getEdgeKey(a, b) {
+				s.set(index, [3]int{u, vertex, w})
+				s.add([3]int{vertex, v, w}, s.patches[index])
+				break
+			}
+		}
+	}
+}
+
+// Get the sorted vertices of an edge.
+func getEdgeKey(a, b int) [2]int {
+	return [2]int{min(a, b), max(a, b)}
+}
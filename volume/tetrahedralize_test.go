@@ -0,0 +1,121 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/ajcurley/meshx-go/halfedge"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate a sphere (UV) oriented outward (or inward if flipped).
+func newTestSphere(t *testing.T, radius float64, n int, flip bool) *halfedge.HalfEdgeMesh {
+	profile := make([]meshx.Vector, n+1)
+	sign := -1.0
+
+	if flip {
+		sign = 1
+	}
+
+	for i := range profile {
+		angle := math.Pi * float64(i) / float64(n)
+		profile[i] = meshx.NewVector(radius*math.Sin(angle), 0, sign*radius*math.Cos(angle))
+	}
+
+	mesh, err := halfedge.NewRevolve(profile, meshx.Vector{}, meshx.NewVector(0, 0, 1), 2*n)
+	assert.Empty(t, err)
+
+	return mesh
+}
+
+// Get the longest edge of the cells of a TetMesh.
+func getTestLongestEdge(mesh *TetMesh) float64 {
+	var longest float64
+
+	for i := range mesh.GetNumberOfCells() {
+		_, length := getEdgeLengths(mesh.GetCellTetrahedron(i))
+		longest = max(longest, length)
+	}
+
+	return longest
+}
+
+// Test tetrahedralizing a box with patches.
+func TestTetrahedralize(t *testing.T) {
+	surface, err := halfedge.NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	mesh, err := Tetrahedralize(surface)
+	assert.Empty(t, err)
+	assert.InDelta(t, 1, mesh.GetVolume(), 1e-12)
+	assert.Equal(t, surface.GetNumberOfPatches(), mesh.GetNumberOfPatches())
+	assert.Greater(t, mesh.ComputeQualitySummary().MinVolume, 0.0)
+
+	for i := range mesh.GetNumberOfPatches() {
+		assert.Equal(t, surface.GetPatch(i).Name, mesh.GetPatch(i))
+		assert.NotEmpty(t, mesh.GetPatchFaces(i))
+	}
+
+	for _, face := range mesh.GetBoundaryFaces() {
+		assert.NotEqual(t, -1, mesh.GetFace(face).Patch)
+	}
+}
+
+// Test tetrahedralizing a box with size control.
+func TestTetrahedralizeWithOptions(t *testing.T) {
+	surface, err := halfedge.NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	options := TetrahedralizeOptions{MaxEdgeLength: 0.25}
+	mesh, err := TetrahedralizeWithOptions(surface, options)
+	assert.Empty(t, err)
+	assert.InDelta(t, 1, mesh.GetVolume(), 1e-12)
+	assert.Less(t, getTestLongestEdge(mesh), 0.5)
+	assert.Less(t, mesh.ComputeQualitySummary().MeanAspectRatio, 2.0)
+
+	for _, face := range mesh.GetBoundaryFaces() {
+		assert.NotEqual(t, -1, mesh.GetFace(face).Patch)
+	}
+
+	options = TetrahedralizeOptions{
+		MaxEdgeLength: 0.01,
+		SizeFunc: func(point meshx.Vector) float64 {
+			return 0.2 + 0.3*point[0]
+		},
+	}
+
+	graded, err := TetrahedralizeWithOptions(surface, options)
+	assert.Empty(t, err)
+	assert.InDelta(t, 1, graded.GetVolume(), 1e-12)
+	assert.Less(t, graded.GetNumberOfCells(), mesh.GetNumberOfCells())
+}
+
+// Test tetrahedralizing a hollow sphere.
+func TestTetrahedralizeHollow(t *testing.T) {
+	surface := newTestSphere(t, 1, 12, false)
+	surface.Merge(newTestSphere(t, 0.5, 8, true))
+
+	options := TetrahedralizeOptions{MaxEdgeLength: 0.3}
+	mesh, err := TetrahedralizeWithOptions(surface, options)
+	assert.Empty(t, err)
+	assert.Greater(t, mesh.ComputeQualitySummary().MinVolume, 0.0)
+
+	outer, err := Tetrahedralize(newTestSphere(t, 1, 12, false))
+	assert.Empty(t, err)
+
+	inner, err := Tetrahedralize(newTestSphere(t, 0.5, 8, false))
+	assert.Empty(t, err)
+
+	assert.InDelta(t, outer.GetVolume()-inner.GetVolume(), mesh.GetVolume(), 1e-9)
+}
+
+// Test tetrahedralizing an open surface.
+func TestTetrahedralizeOpen(t *testing.T) {
+	surface, err := halfedge.NewHalfEdgeMeshFromOBJPath("../testdata/box.patches.obj")
+	assert.Empty(t, err)
+
+	open := surface.Extract([]int{0, 1, 2})
+	_, err = Tetrahedralize(open)
+	assert.ErrorIs(t, err, ErrOpenSurface)
+}
@@ -0,0 +1,159 @@
+package volume
+
+import (
+	"math"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+// Tetrahedron in three-dimensional Cartesian space. The orientation is
+// positive if S is on the side of the triangle PQR its normal points to.
+type Tetrahedron struct {
+	P meshx.Vector
+	Q meshx.Vector
+	R meshx.Vector
+	S meshx.Vector
+}
+
+// Construct a Tetrahedron from its vertices.
+func NewTetrahedron(p, q, r, s meshx.Vector) Tetrahedron {
+	return Tetrahedron{p, q, r, s}
+}
+
+// Get the vertices.
+func (t Tetrahedron) getVertices() [4]meshx.Vector {
+	return [4]meshx.Vector{t.P, t.Q, t.R, t.S}
+}
+
+// Compute the signed volume. The volume is positive if the orientation is
+// positive.
+func (t Tetrahedron) SignedVolume() float64 {
+	u := t.Q.Sub(t.P)
+	v := t.R.Sub(t.P)
+	w := t.S.Sub(t.P)
+	return u.Cross(v).Dot(w) / 6
+}
+
+// Compute the volume.
+func (t Tetrahedron) Volume() float64 {
+	return math.Abs(t.SignedVolume())
+}
+
+// Compute the centroid.
+func (t Tetrahedron) Centroid() meshx.Vector {
+	return t.P.Add(t.Q).Add(t.R).Add(t.S).MulScalar(0.25)
+}
+
+// Get the faces as triangles with normals pointing outward if the
+// orientation is positive. Face i is opposite vertex i.
+func (t Tetrahedron) GetFaces() [4]meshx.Triangle {
+	return [4]meshx.Triangle{
+		meshx.NewTriangle(t.Q, t.R, t.S),
+		meshx.NewTriangle(t.P, t.S, t.R),
+		meshx.NewTriangle(t.P, t.Q, t.S),
+		meshx.NewTriangle(t.P, t.R, t.Q),
+	}
+}
+
+// Compute the total area of the faces.
+func (t Tetrahedron) SurfaceArea() float64 {
+	var area float64
+
+	for _, face := range t.GetFaces() {
+		area += face.Area()
+	}
+
+	return area
+}
+
+// Compute the interior dihedral angles (radians) at the edges PQ, PR, PS,
+// QR, QS and RS.
+func (t Tetrahedron) DihedralAngles() [6]float64 {
+	var angles [6]float64
+
+	vertices := t.getVertices()
+	normals := [4]meshx.Vector{}
+
+	for i, face := range t.GetFaces() {
+		normals[i] = face.UnitNormal()
+	}
+
+	if t.SignedVolume() < 0 {
+		for i := range normals {
+			normals[i] = normals[i].MulScalar(-1)
+		}
+	}
+
+	edges := [6][2]int{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}
+
+	for i, edge := range edges {
+		// The faces adjacent to an edge are those opposite the other two
+		// vertices.
+		faces := make([]int, 0, 2)
+
+		for j := range vertices {
+			if j != edge[0] && j != edge[1] {
+				faces = append(faces, j)
+			}
+		}
+
+		angles[i] = math.Pi - normals[faces[0]].AngleTo(normals[faces[1]])
+	}
+
+	return angles
+}
+
+// Compute the radius of the circumscribed sphere.
+func (t Tetrahedron) Circumradius() float64 {
+	center, ok := t.Circumcenter()
+
+	if !ok {
+		return math.Inf(1)
+	}
+
+	return center.Sub(t.P).Mag()
+}
+
+// Compute the center of the circumscribed sphere. The boolean is false if
+// the tetrahedron is degenerate.
+func (t Tetrahedron) Circumcenter() (meshx.Vector, bool) {
+	a := t.Q.Sub(t.P)
+	b := t.R.Sub(t.P)
+	c := t.S.Sub(t.P)
+	denominator := 2 * a.Dot(b.Cross(c))
+
+	if denominator == 0 {
+		return meshx.Vector{}, false
+	}
+
+	offset := b.Cross(c).MulScalar(a.Dot(a)).
+		Add(c.Cross(a).MulScalar(b.Dot(b))).
+		Add(a.Cross(b).MulScalar(c.Dot(c))).
+		DivScalar(denominator)
+
+	return t.P.Add(offset), true
+}
+
+// Compute the radius of the inscribed sphere.
+func (t Tetrahedron) Inradius() float64 {
+	area := t.SurfaceArea()
+
+	if area == 0 {
+		return 0
+	}
+
+	return 3 * t.Volume() / area
+}
+
+// Compute the aspect ratio as the ratio of the circumradius to three times
+// the inradius. The aspect ratio is one for a regular tetrahedron and
+// infinite for a degenerate one.
+func (t Tetrahedron) AspectRatio() float64 {
+	inradius := t.Inradius()
+
+	if inradius == 0 {
+		return math.Inf(1)
+	}
+
+	return t.Circumradius() / (3 * inradius)
+}
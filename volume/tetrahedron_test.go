@@ -0,0 +1,66 @@
+package volume
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ajcurley/meshx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generate a regular tetrahedron with unit edges.
+func newTestRegularTetrahedron() Tetrahedron {
+	return NewTetrahedron(
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(0.5, math.Sqrt(3)/2, 0),
+		meshx.NewVector(0.5, math.Sqrt(3)/6, math.Sqrt(2.0/3.0)),
+	)
+}
+
+// Test the volume and orientation of a tetrahedron.
+func TestTetrahedronVolume(t *testing.T) {
+	tet := NewTetrahedron(
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(0, 1, 0),
+		meshx.NewVector(0, 0, 1),
+	)
+
+	assert.InDelta(t, 1.0/6.0, tet.SignedVolume(), 1e-15)
+	assert.InDelta(t, -1.0/6.0, NewTetrahedron(tet.P, tet.R, tet.Q, tet.S).SignedVolume(), 1e-15)
+	assert.Equal(t, meshx.NewVector(0.25, 0.25, 0.25), tet.Centroid())
+
+	for _, face := range tet.GetFaces() {
+		assert.Greater(t, face.Normal().Dot(face.Centroid().Sub(tet.Centroid())), 0.0)
+	}
+}
+
+// Test the quality metrics of a regular tetrahedron.
+func TestTetrahedronRegular(t *testing.T) {
+	tet := newTestRegularTetrahedron()
+	assert.InDelta(t, 1/(6*math.Sqrt(2)), tet.Volume(), 1e-12)
+	assert.InDelta(t, 1, tet.AspectRatio(), 1e-12)
+	assert.InDelta(t, math.Sqrt(6)/4, tet.Circumradius(), 1e-12)
+	assert.InDelta(t, math.Sqrt(6)/12, tet.Inradius(), 1e-12)
+
+	for _, angle := range tet.DihedralAngles() {
+		assert.InDelta(t, math.Acos(1.0/3.0), angle, 1e-12)
+	}
+}
+
+// Test the quality metrics of a degenerate tetrahedron.
+func TestTetrahedronDegenerate(t *testing.T) {
+	tet := NewTetrahedron(
+		meshx.NewVector(0, 0, 0),
+		meshx.NewVector(1, 0, 0),
+		meshx.NewVector(0, 1, 0),
+		meshx.NewVector(1, 1, 0),
+	)
+
+	assert.Equal(t, 0.0, tet.Volume())
+	assert.True(t, math.IsInf(tet.AspectRatio(), 1))
+
+	_, ok := tet.Circumcenter()
+	assert.False(t, ok)
+}
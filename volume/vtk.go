@@ -0,0 +1,287 @@
+package volume
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ajcurley/meshx-go"
+)
+
+const (
+	vtkTriangle    = 5
+	vtkTetrahedron = 10
+)
+
+var (
+	ErrInvalidVTK = errors.New("invalid VTK file")
+)
+
+// Write the TetMesh to a legacy VTK (.vtk) ASCII unstructured grid. The
+// boundary faces with a patch are written as triangle cells following the
+// tetrahedra with an integer "Patch" cell field (-1 for the tetrahedra).
+// The patch names are not retained by the format.
+func (m *TetMesh) WriteVTK(writer io.Writer) error {
+	buffer := bufio.NewWriter(writer)
+	faces := make([]int, 0)
+
+	for _, face := range m.GetBoundaryFaces() {
+		if m.faces[face].Patch != -1 {
+			faces = append(faces, face)
+		}
+	}
+
+	nCells := len(m.cells) + len(faces)
+
+	buffer.WriteString("# vtk DataFile Version 3.0\n")
+	buffer.WriteString("meshx\n")
+	buffer.WriteString("ASCII\n")
+	buffer.WriteString("DATASET UNSTRUCTURED_GRID\n")
+	buffer.WriteString(fmt.Sprintf("POINTS %d double\n", len(m.vertices)))
+
+	for _, vertex := range m.vertices {
+		buffer.WriteString(formatVector(vertex) + "\n")
+	}
+
+	buffer.WriteString(fmt.Sprintf("CELLS %d %d\n", nCells, 5*len(m.cells)+4*len(faces)))
+
+	for _, cell := range m.cells {
+		buffer.WriteString(fmt.Sprintf("4 %d %d %d %d\n", cell[0], cell[1], cell[2], cell[3]))
+	}
+
+	for _, face := range faces {
+		vertices := m.faces[face].Vertices
+		buffer.WriteString(fmt.Sprintf("3 %d %d %d\n", vertices[0], vertices[1], vertices[2]))
+	}
+
+	buffer.WriteString(fmt.Sprintf("CELL_TYPES %d\n", nCells))
+
+	for range m.cells {
+		buffer.WriteString(fmt.Sprintf("%d\n", vtkTetrahedron))
+	}
+
+	for range faces {
+		buffer.WriteString(fmt.Sprintf("%d\n", vtkTriangle))
+	}
+
+	buffer.WriteString(fmt.Sprintf("CELL_DATA %d\n", nCells))
+	buffer.WriteString("SCALARS Patch int 1\n")
+	buffer.WriteString("LOOKUP_TABLE default\n")
+
+	for range m.cells {
+		buffer.WriteString("-1\n")
+	}
+
+	for _, face := range faces {
+		buffer.WriteString(fmt.Sprintf("%d\n", m.faces[face].Patch))
+	}
+
+	return buffer.Flush()
+}
+
+// Write the TetMesh to a legacy VTK (.vtk) file path.
+func (m *TetMesh) WriteVTKToPath(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return m.WriteVTK(file)
+}
+
+// Read a TetMesh from a legacy VTK (.vtk) ASCII unstructured grid. The
+// tetrahedra are the cells of the mesh. Triangle cells with a "Patch" cell
+// field assign the patches of the boundary faces, named "patch<i>". Other
+// cell types are ignored.
+func ReadVTK(reader io.Reader) (*TetMesh, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 1<<16), 1<<24)
+	scanner.Split(bufio.ScanWords)
+
+	var vertices []meshx.Vector
+	var connectivity [][]int
+	var cellTypes []int
+	var patches []int
+	var dataSize int
+	var isCellData bool
+
+	next := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		return scanner.Text(), true
+	}
+
+	nextInt := func() (int, error) {
+		token, ok := next()
+		if !ok {
+			return 0, ErrInvalidVTK
+		}
+		return strconv.Atoi(token)
+	}
+
+	for {
+		token, ok := next()
+		if !ok {
+			break
+		}
+
+		switch strings.ToUpper(token) {
+		case "DATASET":
+			if kind, _ := next(); strings.ToUpper(kind) != "UNSTRUCTURED_GRID" {
+				return nil, ErrInvalidVTK
+			}
+		case "POINTS":
+			count, err := nextInt()
+			if err != nil {
+				return nil, ErrInvalidVTK
+			}
+
+			next()
+			vertices = make([]meshx.Vector, count)
+
+			for i := range vertices {
+				for j := range 3 {
+					token, _ := next()
+					value, err := strconv.ParseFloat(token, 64)
+					if err != nil {
+						return nil, ErrInvalidVTK
+					}
+					vertices[i][j] = value
+				}
+			}
+		case "CELLS":
+			count, err := nextInt()
+			if err != nil {
+				return nil, ErrInvalidVTK
+			}
+
+			next()
+			connectivity = make([][]int, count)
+
+			for i := range connectivity {
+				size, err := nextInt()
+				if err != nil {
+					return nil, ErrInvalidVTK
+				}
+
+				connectivity[i] = make([]int, size)
+
+				for j := range size {
+					if connectivity[i][j], err = nextInt(); err != nil {
+						return nil, ErrInvalidVTK
+					}
+				}
+			}
+		case "CELL_TYPES":
+			count, err := nextInt()
+			if err != nil {
+				return nil, ErrInvalidVTK
+			}
+
+			cellTypes = make([]int, count)
+
+			for i := range cellTypes {
+				if cellTypes[i], err = nextInt(); err != nil {
+					return nil, ErrInvalidVTK
+				}
+			}
+		case "CELL_DATA", "POINT_DATA":
+			count, err := nextInt()
+			if err != nil {
+				return nil, ErrInvalidVTK
+			}
+
+			dataSize = count
+			isCellData = strings.ToUpper(token) == "CELL_DATA"
+		case "SCALARS":
+			name, _ := next()
+			next()
+
+			if token, _ := next(); token != "LOOKUP_TABLE" {
+				// Skip the optional number of components.
+				next()
+			}
+
+			next()
+
+			values := make([]int, dataSize)
+
+			for i := range values {
+				token, _ := next()
+				value, err := strconv.ParseFloat(token, 64)
+				if err != nil {
+					return nil, ErrInvalidVTK
+				}
+				values[i] = int(value)
+			}
+
+			if isCellData && name == "Patch" {
+				patches = values
+			}
+		}
+	}
+
+	if len(connectivity) != len(cellTypes) {
+		return nil, ErrInvalidVTK
+	}
+
+	cells := make([][4]int, 0)
+	surface := surfaceSource{}
+	nPatches := 0
+
+	for i, cellType := range cellTypes {
+		switch cellType {
+		case vtkTetrahedron:
+			if len(connectivity[i]) != 4 {
+				return nil, ErrInvalidVTK
+			}
+			cells = append(cells, [4]int(connectivity[i]))
+		case vtkTriangle:
+			if len(connectivity[i]) != 3 || len(patches) != len(cellTypes) || patches[i] < 0 {
+				continue
+			}
+			surface.faces = append(surface.faces, connectivity[i])
+			surface.facePatches = append(surface.facePatches, patches[i])
+			nPatches = max(nPatches, patches[i]+1)
+		}
+	}
+
+	mesh, err := NewTetMesh(vertices, cells)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range nPatches {
+		surface.patches = append(surface.patches, fmt.Sprintf("patch%d", i))
+	}
+
+	if err := mesh.SetBoundaryPatches(&surface); err != nil {
+		return nil, err
+	}
+
+	return mesh, nil
+}
+
+// Read a TetMesh from a legacy VTK (.vtk) file path.
+func ReadVTKFromPath(path string) (*TetMesh, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadVTK(file)
+}
+
+// Format a vector with the shortest representation of each component.
+func formatVector(vector meshx.Vector) string {
+	return strconv.FormatFloat(vector[0], 'g', -1, 64) + " " +
+		strconv.FormatFloat(vector[1], 'g', -1, 64) + " " +
+		strconv.FormatFloat(vector[2], 'g', -1, 64)
+}
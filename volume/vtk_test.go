@@ -0,0 +1,27 @@
+package volume
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test writing and reading a tetrahedral mesh as a legacy VTK file.
+func TestWriteVTK(t *testing.T) {
+	mesh := newTestCube(t)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, mesh.WriteVTK(&buffer))
+	assert.Contains(t, buffer.String(), "CELLS 18 78\n")
+
+	result, err := ReadVTK(&buffer)
+	assert.Empty(t, err)
+	assert.Equal(t, mesh.cells, result.cells)
+	assert.Equal(t, mesh.faces, result.faces)
+	assert.Equal(t, []string{"patch0", "patch1", "patch2", "patch3", "patch4", "patch5"}, result.patches)
+
+	_, err = ReadVTK(strings.NewReader("DATASET POLYDATA\n"))
+	assert.ErrorIs(t, err, ErrInvalidVTK)
+}
@@ -0,0 +1,245 @@
+package meshx
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Scalar field of a mesh defined on either the vertices or faces.
+type ScalarField struct {
+	Name   string
+	Values []float64
+}
+
+// VTPWriter manages writing a VTK XML PolyData (.vtp) file in the ASCII
+// format. Colors are written as four component UInt8 arrays named "Colors"
+// and face patches as an Int32 cell array named "Patch".
+type VTPWriter struct {
+	writer       io.Writer
+	vertices     []Vector
+	vertexColors []Color
+	vertexFields []ScalarField
+	faces        [][]int
+	facePatches  []int
+	faceColors   []Color
+	faceFields   []ScalarField
+	patches      []string
+	conversion   Conversion
+}
+
+// Construct a VTPWriter from an io.Writer interface.
+func NewVTPWriter(writer io.Writer) *VTPWriter {
+	return &VTPWriter{
+		writer:       writer,
+		vertices:     make([]Vector, 0),
+		vertexColors: make([]Color, 0),
+		vertexFields: make([]ScalarField, 0),
+		faces:        make([][]int, 0),
+		facePatches:  make([]int, 0),
+		faceColors:   make([]Color, 0),
+		faceFields:   make([]ScalarField, 0),
+		patches:      make([]string, 0),
+	}
+}
+
+// Set the vertices to write.
+func (w *VTPWriter) SetVertices(vertices []Vector) {
+	w.vertices = vertices
+}
+
+// Set the vertex colors to write.
+func (w *VTPWriter) SetVertexColors(vertexColors []Color) {
+	w.vertexColors = vertexColors
+}
+
+// Add a scalar field on the vertices to write.
+func (w *VTPWriter) AddVertexField(field ScalarField) {
+	w.vertexFields = append(w.vertexFields, field)
+}
+
+// Set the faces to write.
+func (w *VTPWriter) SetFaces(faces [][]int) {
+	w.faces = faces
+}
+
+// Set the face patches to write.
+func (w *VTPWriter) SetFacePatches(facePatches []int) {
+	w.facePatches = facePatches
+}
+
+// Set the face colors to write.
+func (w *VTPWriter) SetFaceColors(faceColors []Color) {
+	w.faceColors = faceColors
+}
+
+// Add a scalar field on the faces to write.
+func (w *VTPWriter) AddFaceField(field ScalarField) {
+	w.faceFields = append(w.faceFields, field)
+}
+
+// Set the patches to write. The patch names are not retained by the format.
+func (w *VTPWriter) SetPatches(patches []string) {
+	w.patches = patches
+}
+
+// Set the conversion of the coordinates written (e.g. to millimeters in a
+// Y-up system). The faces are reversed if the conversion is mirrored.
+func (w *VTPWriter) SetConversion(conversion Conversion) {
+	w.conversion = conversion
+}
+
+// Write the data to the io.Writer interface.
+func (w *VTPWriter) Write() error {
+	converted := *w
+	converted.vertices = w.conversion.ConvertPoints(w.vertices)
+	converted.faces = w.conversion.ConvertFaces(w.faces)
+	return converted.write()
+}
+
+// Write the converted data.
+func (w *VTPWriter) write() error {
+	writer := bufio.NewWriter(w.writer)
+
+	writer.WriteString("<?xml version=\"1.0\"?>\n")
+	writer.WriteString("<VTKFile type=\"PolyData\" version=\"1.0\" byte_order=\"LittleEndian\">\n")
+	writer.WriteString("<PolyData>\n")
+	writer.WriteString(fmt.Sprintf("<Piece NumberOfPoints=\"%d\" NumberOfPolys=\"%d\">\n", len(w.vertices), len(w.faces)))
+
+	writer.WriteString("<PointData>\n")
+
+	for _, field := range w.vertexFields {
+		if len(field.Values) != len(w.vertices) {
+			return ErrInvalidVertex
+		}
+		w.writeFloats(writer, field.Name, 1, field.Values)
+	}
+
+	if len(w.vertexColors) != 0 {
+		w.writeColors(writer, w.vertexColors)
+	}
+
+	writer.WriteString("</PointData>\n")
+	writer.WriteString("<CellData>\n")
+
+	for _, field := range w.faceFields {
+		if len(field.Values) != len(w.faces) {
+			return ErrInvalidFace
+		}
+		w.writeFloats(writer, field.Name, 1, field.Values)
+	}
+
+	if len(w.faceColors) != 0 {
+		w.writeColors(writer, w.faceColors)
+	}
+
+	if len(w.facePatches) != 0 {
+		w.writeInts(writer, "Int32", "Patch", w.facePatches)
+	}
+
+	writer.WriteString("</CellData>\n")
+
+	points := make([]float64, 0, 3*len(w.vertices))
+
+	for _, vertex := range w.vertices {
+		points = append(points, vertex[:]...)
+	}
+
+	writer.WriteString("<Points>\n")
+	w.writeFloats(writer, "Points", 3, points)
+	writer.WriteString("</Points>\n")
+
+	connectivity := make([]int, 0)
+	offsets := make([]int, len(w.faces))
+
+	for i, face := range w.faces {
+		for _, vertex := range face {
+			if vertex < 0 || vertex >= len(w.vertices) {
+				return ErrInvalidFace
+			}
+		}
+
+		connectivity = append(connectivity, face...)
+		offsets[i] = len(connectivity)
+	}
+
+	writer.WriteString("<Polys>\n")
+	w.writeInts(writer, "Int64", "connectivity", connectivity)
+	w.writeInts(writer, "Int64", "offsets", offsets)
+	writer.WriteString("</Polys>\n")
+
+	writer.WriteString("</Piece>\n")
+	writer.WriteString("</PolyData>\n")
+	writer.WriteString("</VTKFile>\n")
+
+	return writer.Flush()
+}
+
+// Write the opening tag of a data array.
+func (w *VTPWriter) writeDataArrayTag(writer *bufio.Writer, dataType, name string, components int) {
+	writer.WriteString("<DataArray type=\"" + dataType + "\" Name=\"")
+	xml.EscapeText(writer, []byte(name))
+	writer.WriteString(fmt.Sprintf("\" NumberOfComponents=\"%d\" format=\"ascii\">\n", components))
+}
+
+// Write a Float64 data array.
+func (w *VTPWriter) writeFloats(writer *bufio.Writer, name string, components int, values []float64) {
+	w.writeDataArrayTag(writer, "Float64", name, components)
+	buffer := make([]byte, 0, 32)
+
+	for i, value := range values {
+		buffer = strconv.AppendFloat(buffer[:0], value, 'g', -1, 64)
+		buffer = append(buffer, vtkSeparator(i, len(values), components))
+		writer.Write(buffer)
+	}
+
+	writer.WriteString("</DataArray>\n")
+}
+
+// Write an integer data array.
+func (w *VTPWriter) writeInts(writer *bufio.Writer, dataType, name string, values []int) {
+	w.writeDataArrayTag(writer, dataType, name, 1)
+	buffer := make([]byte, 0, 32)
+
+	for i, value := range values {
+		buffer = strconv.AppendInt(buffer[:0], int64(value), 10)
+		buffer = append(buffer, vtkSeparator(i, len(values), 1))
+		writer.Write(buffer)
+	}
+
+	writer.WriteString("</DataArray>\n")
+}
+
+// Write a UInt8 RGBA color data array.
+func (w *VTPWriter) writeColors(writer *bufio.Writer, colors []Color) {
+	w.writeDataArrayTag(writer, "UInt8", "Colors", 4)
+	buffer := make([]byte, 0, 32)
+
+	for i, color := range colors {
+		buffer = buffer[:0]
+
+		for j, value := range color {
+			buffer = strconv.AppendUint(buffer, uint64(value), 10)
+
+			if j < 3 {
+				buffer = append(buffer, ' ')
+			}
+		}
+
+		buffer = append(buffer, vtkSeparator(i, len(colors), 1))
+		writer.Write(buffer)
+	}
+
+	writer.WriteString("</DataArray>\n")
+}
+
+// Get the separator following the value of a data array. Each tuple is
+// written on its own line.
+func vtkSeparator(index, count, components int) byte {
+	if (index+1)%components == 0 || index == count-1 {
+		return '\n'
+	}
+	return ' '
+}
@@ -0,0 +1,80 @@
+package meshx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Write a VTP file with fields and colors.
+func TestWriteVTP(t *testing.T) {
+	vertices := []Vector{
+		NewVector(0, 0, 0),
+		NewVector(1, 0, 0),
+		NewVector(1, 1, 0),
+		NewVector(0, 1, 0.5),
+	}
+
+	faces := [][]int{
+		[]int{0, 1, 2},
+		[]int{0, 2, 3},
+	}
+
+	var buffer bytes.Buffer
+	writer := NewVTPWriter(&buffer)
+	writer.SetVertices(vertices)
+	writer.SetFaces(faces)
+	writer.SetFacePatches([]int{0, -1})
+	writer.SetFaceColors([]Color{ColorWhite, ColorBlack})
+	writer.AddVertexField(ScalarField{"distance <mm>", []float64{0, 0.5, 1, 1.5}})
+	writer.AddFaceField(ScalarField{"quality", []float64{0.25, 1}})
+	assert.Empty(t, writer.Write())
+
+	type dataArray struct {
+		Type       string `xml:"type,attr"`
+		Name       string `xml:"Name,attr"`
+		Components int    `xml:"NumberOfComponents,attr"`
+		Values     string `xml:",chardata"`
+	}
+
+	var file struct {
+		Piece struct {
+			NumberOfPoints int         `xml:"NumberOfPoints,attr"`
+			NumberOfPolys  int         `xml:"NumberOfPolys,attr"`
+			PointData      []dataArray `xml:"PointData>DataArray"`
+			CellData       []dataArray `xml:"CellData>DataArray"`
+			Points         []dataArray `xml:"Points>DataArray"`
+			Polys          []dataArray `xml:"Polys>DataArray"`
+		} `xml:"PolyData>Piece"`
+	}
+
+	assert.Empty(t, xml.Unmarshal(buffer.Bytes(), &file))
+	assert.Equal(t, 4, file.Piece.NumberOfPoints)
+	assert.Equal(t, 2, file.Piece.NumberOfPolys)
+
+	assert.Equal(t, 1, len(file.Piece.PointData))
+	assert.Equal(t, "distance <mm>", file.Piece.PointData[0].Name)
+	assert.Equal(t, "\n0\n0.5\n1\n1.5\n", file.Piece.PointData[0].Values)
+
+	assert.Equal(t, 3, len(file.Piece.CellData))
+	assert.Equal(t, "quality", file.Piece.CellData[0].Name)
+	assert.Equal(t, "Colors", file.Piece.CellData[1].Name)
+	assert.Equal(t, 4, file.Piece.CellData[1].Components)
+	assert.Equal(t, "\n255 255 255 255\n0 0 0 255\n", file.Piece.CellData[1].Values)
+	assert.Equal(t, "\n0\n-1\n", file.Piece.CellData[2].Values)
+
+	assert.Equal(t, "\n0 0 0\n1 0 0\n1 1 0\n0 1 0.5\n", file.Piece.Points[0].Values)
+	assert.Equal(t, "\n0\n1\n2\n0\n2\n3\n", file.Piece.Polys[0].Values)
+	assert.Equal(t, "\n3\n6\n", file.Piece.Polys[1].Values)
+}
+
+// Write a VTP file with a field of the wrong size.
+func TestWriteVTPInvalidField(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewVTPWriter(&buffer)
+	writer.SetVertices([]Vector{NewVector(0, 0, 0)})
+	writer.AddVertexField(ScalarField{"scalar", []float64{0, 1}})
+	assert.ErrorIs(t, writer.Write(), ErrInvalidVertex)
+}
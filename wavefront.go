@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path"
+	"path/filepath"
 	"strconv"
 	"unicode"
 	"unicode/utf8"
@@ -17,38 +21,106 @@ const (
 	PrefixVertex = "v"
 	PrefixFace   = "f"
 	PrefixGroup  = "g"
+	PrefixObject = "o"
+	PrefixLine   = "l"
+
+	PrefixSmoothingGroup = "s"
+
+	PrefixMaterialLibrary = "mtllib"
+	PrefixUseMaterial     = "usemtl"
 )
 
 var (
 	ErrInvalidVertex = errors.New("invalid vertex")
 	ErrInvalidFace   = errors.New("invalid face")
+	ErrInvalidLine   = errors.New("invalid line")
+
+	ErrInvalidSmoothingGroup = errors.New("invalid smoothing group")
 )
 
 // OBJReader manages parsing an OBJ (WaveFront) file. This supports both ASCII
-// and GZIP ASCII files.
+// and GZIP ASCII files. Vertex colors are read from the non-standard
+// extension appending the RGB (or RGBA) channels in [0, 1] to a vertex.
 type OBJReader struct {
-	reader      io.Reader
-	vertices    []Vector
-	faces       []int
-	faceOffsets []int
-	facePatches []int
-	patches     []string
+	reader            io.Reader
+	vertices          []Vector
+	vertexColors      []Color
+	hasVertexColors   bool
+	faces             []int
+	faceOffsets       []int
+	faceLines         []int
+	facePatches       []int
+	patches           []string
+	faceMaterials     []int
+	materials         []Material
+	materialLibraries []string
+	material          int
+	smoothingGroups   []int
+	smoothingGroup    int
+	faceObjects       []int
+	objects           []string
+	objectsAsPatches  bool
+	lines             []int
+	lineOffsets       []int
+	size              int64
+	conversion        Conversion
+	transform         readTransform
+	skipInvalidFaces  bool
+	skippedFaces      []int
+	keepDirectives    bool
+	directives        []string
+	checkPrecision    bool
+	impreciseVertices []int
 }
 
 // Construct an OBJ reader from an io.Reader interface.
 func NewOBJReader(reader io.Reader) *OBJReader {
 	return &OBJReader{
-		reader:      reader,
-		vertices:    make([]Vector, 0),
-		faces:       make([]int, 0),
-		faceOffsets: make([]int, 0),
-		facePatches: make([]int, 0),
-		patches:     make([]string, 0),
+		reader:            reader,
+		vertices:          make([]Vector, 0),
+		vertexColors:      make([]Color, 0),
+		faces:             make([]int, 0),
+		faceOffsets:       make([]int, 0),
+		faceLines:         make([]int, 0),
+		facePatches:       make([]int, 0),
+		patches:           make([]string, 0),
+		faceMaterials:     make([]int, 0),
+		smoothingGroups:   make([]int, 0),
+		faceObjects:       make([]int, 0),
+		objects:           make([]string, 0),
+		materials:         make([]Material, 0),
+		materialLibraries: make([]string, 0),
+		lines:             make([]int, 0),
+		lineOffsets:       make([]int, 0),
+		skippedFaces:      make([]int, 0),
+		directives:        make([]string, 0),
+		impreciseVertices: make([]int, 0),
+		material:          -1,
+		size:              -1,
 	}
 }
 
-// Read an OBJ file from a file path.
+// Read an OBJ file from a file path. The material libraries referenced by the
+// file are read relative to its directory. Missing libraries are ignored.
 func ReadOBJFromPath(path string) (*OBJReader, error) {
+	return ReadOBJFromPathContext(context.Background(), path, nil)
+}
+
+// Read an OBJ file from a file path with cancellation and progress reported
+// as the number of bytes read out of the file size.
+func ReadOBJFromPathContext(ctx context.Context, path string, progress ProgressFunc) (*OBJReader, error) {
+	return readOBJFromPath(ctx, path, progress, readTransform{})
+}
+
+// Read an OBJ file from a file path transforming the vertices while reading
+// (e.g. to place a part in a scene). See SetTransform.
+func ReadOBJWithTransform(path string, matrix Matrix4) (*OBJReader, error) {
+	return readOBJFromPath(context.Background(), path, nil, newReadTransform(matrix))
+}
+
+// Read an OBJ file from a file path with cancellation, progress and the
+// transformation applied while reading.
+func readOBJFromPath(ctx context.Context, path string, progress ProgressFunc, transform readTransform) (*OBJReader, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -56,18 +128,91 @@ func ReadOBJFromPath(path string) (*OBJReader, error) {
 	defer file.Close()
 
 	objReader := NewOBJReader(file)
+	objReader.transform = transform
 
-	if err := objReader.Read(); err != nil {
+	if info, err := file.Stat(); err == nil {
+		objReader.size = info.Size()
+	}
+
+	if err := objReader.ReadContext(ctx, progress); err != nil {
+		return nil, err
+	}
+
+	if err := objReader.readMaterialLibraries(filepath.Dir(path)); err != nil {
 		return nil, err
 	}
 
 	return objReader, nil
 }
 
+// Read the material definitions from the material libraries relative to a
+// directory. Materials defined but never used are retained.
+func (r *OBJReader) readMaterialLibraries(dir string) error {
+	return r.mergeMaterialLibraries(func(library string) ([]Material, error) {
+		return ReadMTLFromPath(filepath.Join(dir, library))
+	})
+}
+
+// Read the material definitions from the material libraries relative to a
+// directory of a file system (e.g. an embed.FS) once the OBJ file is read.
+// Missing libraries are skipped as when reading from a file path.
+func (r *OBJReader) ReadMaterialLibrariesFS(fsys fs.FS, dir string) error {
+	return r.mergeMaterialLibraries(func(library string) ([]Material, error) {
+		file, err := fsys.Open(path.Join(dir, library))
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		return ReadMTL(file)
+	})
+}
+
+// Merge the material definitions of each material library read by a
+// function into the materials.
+func (r *OBJReader) mergeMaterialLibraries(readLibrary func(string) ([]Material, error)) error {
+	indexMaterials := make(map[string]int)
+
+	for i, material := range r.materials {
+		indexMaterials[material.Name] = i
+	}
+
+	for _, library := range r.materialLibraries {
+		materials, err := readLibrary(library)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("%s: %v", library, err)
+		}
+
+		for _, material := range materials {
+			if index, ok := indexMaterials[material.Name]; ok {
+				r.materials[index].Statements = material.Statements
+			} else {
+				indexMaterials[material.Name] = len(r.materials)
+				r.materials = append(r.materials, material)
+			}
+		}
+	}
+
+	return nil
+}
+
 // Read the OBJ file.
 func (r *OBJReader) Read() error {
+	return r.ReadContext(context.Background(), nil)
+}
+
+// Read the OBJ file with cancellation and progress reported as the number of
+// (compressed) bytes read. The total is unknown unless read from a path.
+func (r *OBJReader) ReadContext(ctx context.Context, progress ProgressFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	count := 1
-	reader := bufio.NewReader(r.reader)
+	counter := &countingReader{reader: r.reader}
+	reader := bufio.NewReader(counter)
 
 	testBytes, err := reader.Peek(2)
 	if err != nil {
@@ -97,18 +242,100 @@ func (r *OBJReader) Read() error {
 		case PrefixVertex:
 			err = r.parseVertex(data)
 		case PrefixFace:
-			err = r.parseFace(data)
+			err = r.parseFace(data, count)
 		case PrefixGroup:
 			r.parseGroup(data)
+		case PrefixObject:
+			r.parseObject(data)
+		case PrefixLine:
+			err = r.parseLine(data)
+		case PrefixMaterialLibrary:
+			r.parseMaterialLibrary(data)
+			r.parseDirective(prefix, data)
+		case PrefixUseMaterial:
+			err = r.parseUseMaterial(data)
+		case PrefixSmoothingGroup:
+			err = r.parseSmoothingGroup(data)
+		default:
+			r.parseDirective(prefix, data)
 		}
 
 		if err != nil {
-			return fmt.Errorf("line %d: %v", count, err)
+			return fmt.Errorf("line %d: %w", count, err)
+		}
+
+		if count%ProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			progress.Report(counter.count, r.size)
 		}
 
 		count++
 	}
 
+	progress.Report(counter.count, r.size)
+
+	return r.Validate()
+}
+
+// Validate the vertex indices of the faces. An error reports the line of the
+// first face referencing a vertex that does not exist unless invalid faces
+// are skipped, in which case they are removed (see GetSkippedFaces).
+func (r *OBJReader) Validate() error {
+	invalid := make([]bool, r.GetNumberOfFaces())
+	isValid := true
+
+	for i := range invalid {
+		for _, vertex := range r.getFace(i) {
+			if vertex >= len(r.vertices) {
+				invalid[i] = true
+			}
+		}
+
+		if invalid[i] && !r.skipInvalidFaces {
+			return fmt.Errorf("line %d: %w", r.faceLines[i], ErrInvalidFace)
+		}
+
+		isValid = isValid && !invalid[i]
+	}
+
+	if isValid {
+		return nil
+	}
+
+	faces := make([]int, 0, len(r.faces))
+	faceOffsets := make([]int, 0, len(r.faceOffsets))
+	faceLines := make([]int, 0, len(r.faceLines))
+	facePatches := make([]int, 0, len(r.facePatches))
+	faceMaterials := make([]int, 0, len(r.faceMaterials))
+	smoothingGroups := make([]int, 0, len(r.smoothingGroups))
+	faceObjects := make([]int, 0, len(r.faceObjects))
+
+	for i := range invalid {
+		if invalid[i] {
+			r.skippedFaces = append(r.skippedFaces, r.faceLines[i])
+			continue
+		}
+
+		faceOffsets = append(faceOffsets, len(faces))
+		faces = append(faces, r.getFace(i)...)
+		faceLines = append(faceLines, r.faceLines[i])
+		facePatches = append(facePatches, r.facePatches[i])
+		faceMaterials = append(faceMaterials, r.faceMaterials[i])
+		smoothingGroups = append(smoothingGroups, r.smoothingGroups[i])
+		faceObjects = append(faceObjects, r.faceObjects[i])
+	}
+
+	r.faces = faces
+	r.faceOffsets = faceOffsets
+	r.faceLines = faceLines
+	r.facePatches = facePatches
+	r.faceMaterials = faceMaterials
+	r.smoothingGroups = smoothingGroups
+	r.faceObjects = faceObjects
+
 	return nil
 }
 
@@ -128,29 +355,38 @@ func (r *OBJReader) parsePrefix(data []byte) []byte {
 func (r *OBJReader) parseVertex(data []byte) error {
 	fields := bytes.Fields(data[len(PrefixVertex):])
 
-	if len(fields) != 3 {
+	if len(fields) != 3 && len(fields) != 6 && len(fields) != 7 {
 		return ErrInvalidVertex
 	}
 
-	var values [3]float64
+	values := [7]float64{3: 1, 4: 1, 5: 1, 6: 1}
 
-	for i := 0; i < 3; i++ {
+	for i := range fields {
 		value, err := strconv.ParseFloat(string(fields[i]), 64)
 		if err != nil {
 			return ErrInvalidVertex
 		}
 
 		values[i] = value
+
+		if r.checkPrecision && i < 3 && isFloatPrecisionLost(string(fields[i]), value) {
+			if n := len(r.impreciseVertices); n == 0 || r.impreciseVertices[n-1] != len(r.vertices) {
+				r.impreciseVertices = append(r.impreciseVertices, len(r.vertices))
+			}
+		}
 	}
 
-	vertex := NewVectorFromArray(values)
+	vertex := r.transform.transformPoint(NewVector(values[0], values[1], values[2]))
+	color := NewColorFromFloats(values[3], values[4], values[5], values[6])
 	r.vertices = append(r.vertices, vertex)
+	r.vertexColors = append(r.vertexColors, color)
+	r.hasVertexColors = r.hasVertexColors || len(fields) != 3
 
 	return nil
 }
 
-// Parse a face from a line.
-func (r *OBJReader) parseFace(data []byte) error {
+// Parse a face from a line by number.
+func (r *OBJReader) parseFace(data []byte, line int) error {
 	fields := bytes.Fields(data[len(PrefixFace):])
 
 	if len(fields) <= 2 {
@@ -172,8 +408,41 @@ func (r *OBJReader) parseFace(data []byte) error {
 		r.faces = append(r.faces, value-1)
 	}
 
+	r.transform.transformFace(r.faces[faceOffset:])
 	r.faceOffsets = append(r.faceOffsets, faceOffset)
+	r.faceLines = append(r.faceLines, line)
 	r.facePatches = append(r.facePatches, len(r.patches)-1)
+	r.faceMaterials = append(r.faceMaterials, r.material)
+	r.smoothingGroups = append(r.smoothingGroups, r.smoothingGroup)
+	r.faceObjects = append(r.faceObjects, len(r.objects)-1)
+
+	return nil
+}
+
+// Parse a polyline from a line.
+func (r *OBJReader) parseLine(data []byte) error {
+	fields := bytes.Fields(data[len(PrefixLine):])
+
+	if len(fields) < 2 {
+		return ErrInvalidLine
+	}
+
+	lineOffset := len(r.lines)
+
+	for _, field := range fields {
+		if idx := bytes.IndexByte(field, byte('/')); idx != -1 {
+			field = field[:idx]
+		}
+
+		value, err := strconv.Atoi(string(field))
+		if err != nil || value <= 0 {
+			return ErrInvalidLine
+		}
+
+		r.lines = append(r.lines, value-1)
+	}
+
+	r.lineOffsets = append(r.lineOffsets, lineOffset)
 
 	return nil
 }
@@ -185,9 +454,128 @@ func (r *OBJReader) parseGroup(data []byte) {
 	r.patches = append(r.patches, patch)
 }
 
+// Parse an object from a line.
+func (r *OBJReader) parseObject(data []byte) {
+	object := bytes.TrimSpace(data[len(PrefixObject):])
+	r.objects = append(r.objects, string(object))
+}
+
+// Parse a material library from a line.
+func (r *OBJReader) parseMaterialLibrary(data []byte) {
+	for _, field := range bytes.Fields(data[len(PrefixMaterialLibrary):]) {
+		r.materialLibraries = append(r.materialLibraries, string(field))
+	}
+}
+
+// Parse a smoothing group from a line. The group is zero if off.
+func (r *OBJReader) parseSmoothingGroup(data []byte) error {
+	group := string(bytes.TrimSpace(data[len(PrefixSmoothingGroup):]))
+
+	if group == "off" {
+		r.smoothingGroup = 0
+		return nil
+	}
+
+	value, err := strconv.Atoi(group)
+	if err != nil || value < 0 {
+		return ErrInvalidSmoothingGroup
+	}
+
+	r.smoothingGroup = value
+
+	return nil
+}
+
+// Parse a directive not otherwise handled from a line, retained if the
+// directives are kept. The vertex data of textures and normals are not
+// retained since the faces do not reference them once read.
+func (r *OBJReader) parseDirective(prefix, data []byte) {
+	if !r.keepDirectives || len(data) == 0 {
+		return
+	}
+
+	switch string(prefix) {
+	case "vt", "vn", "vp":
+		return
+	}
+
+	r.directives = append(r.directives, string(data))
+}
+
+// Parse a material assignment from a line.
+func (r *OBJReader) parseUseMaterial(data []byte) error {
+	name := string(bytes.TrimSpace(data[len(PrefixUseMaterial):]))
+
+	if name == "" {
+		return ErrInvalidMaterial
+	}
+
+	for i, material := range r.materials {
+		if material.Name == name {
+			r.material = i
+			return nil
+		}
+	}
+
+	r.material = len(r.materials)
+	r.materials = append(r.materials, Material{Name: name})
+
+	return nil
+}
+
+// Set the transformation applied to the vertices while reading, saving a
+// pass over the vertices afterward. The faces are reversed if the
+// transformation is mirrored. It must be set before reading and is applied
+// before the conversion.
+func (r *OBJReader) SetTransform(matrix Matrix4) {
+	r.transform = newReadTransform(matrix)
+}
+
+// Set the conversion of the coordinates read (e.g. from millimeters in a
+// Y-up system). The faces are reversed if the conversion is mirrored.
+func (r *OBJReader) SetConversion(conversion Conversion) {
+	r.conversion = conversion
+}
+
+// Set whether faces referencing vertices that do not exist are skipped
+// instead of failing to read.
+func (r *OBJReader) SetSkipInvalidFaces(skip bool) {
+	r.skipInvalidFaces = skip
+}
+
+// Get the line numbers of the faces skipped for referencing vertices that do
+// not exist.
+func (r *OBJReader) GetSkippedFaces() []int {
+	return r.skippedFaces
+}
+
+// Set whether the directives not otherwise handled (e.g. comments and mtllib
+// statements) are kept to be written again (see GetDirectives).
+func (r *OBJReader) SetKeepDirectives(keep bool) {
+	r.keepDirectives = keep
+}
+
+// Get the directives kept in the order read (one line each).
+func (r *OBJReader) GetDirectives() []string {
+	return r.directives
+}
+
+// Set whether the coordinates are checked for digits lost reading them as
+// float64 (see GetImpreciseVertices). It must be set before reading.
+func (r *OBJReader) SetCheckPrecision(check bool) {
+	r.checkPrecision = check
+}
+
+// Get the vertices with a coordinate written with more significant digits
+// than a float64 holds, such that writing it again does not reproduce the
+// text. Only checked if set before reading (see SetCheckPrecision).
+func (r *OBJReader) GetImpreciseVertices() []int {
+	return r.impreciseVertices
+}
+
 // Get a vertex by index.
 func (r *OBJReader) GetVertex(index int) Vector {
-	return r.vertices[index]
+	return r.conversion.ConvertPoint(r.vertices[index])
 }
 
 // Get the number of vertices.
@@ -195,8 +583,33 @@ func (r *OBJReader) GetNumberOfVertices() int {
 	return len(r.vertices)
 }
 
+// Return true if any vertex has a color. A vertex without a color is white.
+func (r *OBJReader) HasVertexColors() bool {
+	return r.hasVertexColors
+}
+
+// Get a vertex color by index.
+func (r *OBJReader) GetVertexColor(index int) Color {
+	return r.vertexColors[index]
+}
+
+// Return true if any face has a color. Face colors are not supported.
+func (r *OBJReader) HasFaceColors() bool {
+	return false
+}
+
+// Get a face color by index.
+func (r *OBJReader) GetFaceColor(index int) Color {
+	return ColorWhite
+}
+
 // Get a face by index.
 func (r *OBJReader) GetFace(index int) []int {
+	return r.conversion.ConvertFace(r.getFace(index))
+}
+
+// Get a face by index without the conversion.
+func (r *OBJReader) getFace(index int) []int {
 	if index == r.GetNumberOfFaces()-1 {
 		faceStart := r.faceOffsets[index]
 		return r.faces[faceStart:]
@@ -207,8 +620,18 @@ func (r *OBJReader) GetFace(index int) []int {
 	return r.faces[faceStart:faceEnd]
 }
 
+// Set whether the objects (o statements) are read as the patches instead of
+// the groups (g statements).
+func (r *OBJReader) SetObjectsAsPatches(objectsAsPatches bool) {
+	r.objectsAsPatches = objectsAsPatches
+}
+
 // Get a face patch by index.
 func (r *OBJReader) GetFacePatch(index int) int {
+	if r.objectsAsPatches {
+		return r.faceObjects[index]
+	}
+
 	return r.facePatches[index]
 }
 
@@ -224,22 +647,118 @@ func (r *OBJReader) GetNumberOfFaceEdges() int {
 
 // Get a patch by index.
 func (r *OBJReader) GetPatch(index int) string {
+	if r.objectsAsPatches {
+		return r.objects[index]
+	}
+
 	return r.patches[index]
 }
 
 // Get the number of patches.
 func (r *OBJReader) GetNumberOfPatches() int {
+	if r.objectsAsPatches {
+		return len(r.objects)
+	}
+
 	return len(r.patches)
 }
 
+// Get a face object by index. The object is -1 if the face has none.
+func (r *OBJReader) GetFaceObject(index int) int {
+	return r.faceObjects[index]
+}
+
+// Get an object by index.
+func (r *OBJReader) GetObject(index int) string {
+	return r.objects[index]
+}
+
+// Get the number of objects.
+func (r *OBJReader) GetNumberOfObjects() int {
+	return len(r.objects)
+}
+
+// Get the faces of the objects by name in the order read (e.g. to extract
+// the objects from a HalfEdgeMesh constructed from the reader).
+func (r *OBJReader) GetObjectFaces(names ...string) []int {
+	indexNames := make(map[string]bool)
+	faces := make([]int, 0)
+
+	for _, name := range names {
+		indexNames[name] = true
+	}
+
+	for i, object := range r.faceObjects {
+		if object != -1 && indexNames[r.objects[object]] {
+			faces = append(faces, i)
+		}
+	}
+
+	return faces
+}
+
+// Get a face material by index. The material is -1 if the face has none.
+func (r *OBJReader) GetFaceMaterial(index int) int {
+	return r.faceMaterials[index]
+}
+
+// Get a material by index.
+func (r *OBJReader) GetMaterial(index int) Material {
+	return r.materials[index]
+}
+
+// Get the number of materials.
+func (r *OBJReader) GetNumberOfMaterials() int {
+	return len(r.materials)
+}
+
+// Get a face smoothing group by index. The group is zero if the face is not
+// smoothed.
+func (r *OBJReader) GetFaceSmoothingGroup(index int) int {
+	return r.smoothingGroups[index]
+}
+
+// Get the number of polylines (l records).
+func (r *OBJReader) GetNumberOfLines() int {
+	return len(r.lineOffsets)
+}
+
+// Get the vertex indices of a polyline (l record) by index.
+func (r *OBJReader) GetLine(index int) []int {
+	start := r.lineOffsets[index]
+	end := len(r.lines)
+
+	if index < len(r.lineOffsets)-1 {
+		end = r.lineOffsets[index+1]
+	}
+
+	return r.lines[start:end]
+}
+
+// Get the material libraries referenced by the file.
+func (r *OBJReader) GetMaterialLibraries() []string {
+	return r.materialLibraries
+}
+
 // OBJReader manages writing an OBJ (WaveFront) file.
 type OBJWriter struct {
-	writer      io.Writer
-	vertices    []Vector
-	faces       [][]int
-	facePatches []int
-	edges       [][2]int
-	patches     []string
+	writer          io.Writer
+	vertices        []Vector
+	vertexColors    []Color
+	faces           [][]int
+	facePatches     []int
+	edges           [][2]int
+	lines           [][]int
+	patches         []string
+	faceMaterials   []int
+	materials       []string
+	materialLibrary string
+	directives      []string
+	material        int
+	smoothingGroups []int
+	smoothingGroup  int
+	conversion      Conversion
+	floatFormat     FloatFormat
 }
 
 // Construct an OBJWriter from an io.Writer interface.
@@ -259,6 +778,12 @@ func (w *OBJWriter) SetVertices(vertices []Vector) {
 	w.vertices = vertices
 }
 
+// Set the vertex colors to write using the non-standard vertex color
+// extension. The alpha channel is written only if a color is translucent.
+func (w *OBJWriter) SetVertexColors(vertexColors []Color) {
+	w.vertexColors = vertexColors
+}
+
 // Set the faces to write.
 func (w *OBJWriter) SetFaces(faces [][]int) {
 	w.faces = faces
@@ -274,16 +799,86 @@ func (w *OBJWriter) SetEdges(edges [][2]int) {
 	w.edges = edges
 }
 
+// Set the polylines to write as l records.
+func (w *OBJWriter) SetLines(lines [][]int) {
+	w.lines = lines
+}
+
 // Set the patches to write.
 func (w *OBJWriter) SetPatches(patches []string) {
 	w.patches = patches
 }
 
-// Write the data to the io.Writer interface.
+// Set the face materials to write. A face without a material is -1.
+func (w *OBJWriter) SetFaceMaterials(faceMaterials []int) {
+	w.faceMaterials = faceMaterials
+}
+
+// Set the face smoothing groups to write. A face not smoothed is zero.
+func (w *OBJWriter) SetFaceSmoothingGroups(smoothingGroups []int) {
+	w.smoothingGroups = smoothingGroups
+}
+
+// Set the material names to write.
+func (w *OBJWriter) SetMaterials(materials []string) {
+	w.materials = materials
+}
+
+// Set the material library referenced by the file.
+func (w *OBJWriter) SetMaterialLibrary(library string) {
+	w.materialLibrary = library
+}
+
+// Set the directives (e.g. as kept by an OBJReader) to write in order at the
+// start of the file. Each is written as is on its own line.
+func (w *OBJWriter) SetDirectives(directives []string) {
+	w.directives = directives
+}
+
+// Set the conversion of the coordinates written (e.g. to millimeters in a
+// Y-up system). The faces are reversed if the conversion is mirrored.
+func (w *OBJWriter) SetConversion(conversion Conversion) {
+	w.conversion = conversion
+}
+
+// Set the formatting of the coordinates and colors written. The default is
+// fixed six decimal places; FloatRoundTrip writes the coordinates such that
+// they are read back bit-exactly.
+func (w *OBJWriter) SetFloatFormat(format FloatFormat) {
+	w.floatFormat = format
+}
+
+// Write the data to the io.Writer interface. A usemtl statement is written
+// whenever the material changes between consecutive faces. A face without a
+// material written after a face with one inherits it since OBJ files cannot
+// clear the current material.
 func (w *OBJWriter) Write() error {
+	converted := *w
+	converted.vertices = w.conversion.ConvertPoints(w.vertices)
+	converted.faces = w.conversion.ConvertFaces(w.faces)
+	return converted.write()
+}
+
+// Write the converted data.
+func (w *OBJWriter) write() error {
 	var line string
 	writer := bufio.NewWriter(w.writer)
 	patchFaces := make(map[int][]int)
+	w.material = -1
+	w.smoothingGroup = 0
+
+	for _, directive := range w.directives {
+		if _, err := writer.WriteString(directive + "\n"); err != nil {
+			return err
+		}
+	}
+
+	if w.materialLibrary != "" {
+		line = fmt.Sprintf("mtllib %s\n", w.materialLibrary)
+		if _, err := writer.WriteString(line); err != nil {
+			return err
+		}
+	}
 
 	for i, patch := range w.facePatches {
 		if faces, ok := patchFaces[patch]; ok {
@@ -293,9 +888,25 @@ func (w *OBJWriter) Write() error {
 		}
 	}
 
-	for _, vertex := range w.vertices {
-		line = fmt.Sprintf("v %f %f %f\n", vertex[0], vertex[1], vertex[2])
-		if _, err := writer.WriteString(line); err != nil {
+	hasAlpha := false
+
+	for _, color := range w.vertexColors {
+		hasAlpha = hasAlpha || !color.IsOpaque()
+	}
+
+	for i, vertex := range w.vertices {
+		line = "v " + w.floatFormat.FormatVector(vertex)
+
+		if len(w.vertexColors) != 0 {
+			color := w.vertexColors[i].Floats()
+			line += " " + w.floatFormat.FormatVector(Vector{color[0], color[1], color[2]})
+
+			if hasAlpha {
+				line += " " + w.floatFormat.Format(color[3])
+			}
+		}
+
+		if _, err := writer.WriteString(line + "\n"); err != nil {
 			return err
 		}
 	}
@@ -307,7 +918,25 @@ func (w *OBJWriter) Write() error {
 		}
 	}
 
+	for _, polyline := range w.lines {
+		writer.WriteString(PrefixLine)
+
+		for _, vertex := range polyline {
+			writer.WriteString(fmt.Sprintf(" %d", vertex+1))
+		}
+
+		if _, err := writer.WriteString("\n"); err != nil {
+			return err
+		}
+	}
+
 	if len(patchFaces) != 0 {
+		for _, face := range patchFaces[-1] {
+			if err := w.writeFace(writer, face); err != nil {
+				return err
+			}
+		}
+
 		for patch := range w.patches {
 			line = fmt.Sprintf("g %s\n", w.patches[patch])
 			if _, err := writer.WriteString(line); err != nil {
@@ -315,28 +944,56 @@ func (w *OBJWriter) Write() error {
 			}
 
 			for _, face := range patchFaces[patch] {
-				writer.WriteString("f")
-
-				for _, vertex := range w.faces[face] {
-					entry := fmt.Sprintf(" %d", vertex+1)
-					writer.WriteString(entry)
+				if err := w.writeFace(writer, face); err != nil {
+					return err
 				}
-
-				writer.WriteString("\n")
 			}
 		}
 	} else {
-		for _, face := range w.faces {
-			writer.WriteString("f")
+		for face := range w.faces {
+			if err := w.writeFace(writer, face); err != nil {
+				return err
+			}
+		}
+	}
 
-			for _, vertex := range face {
-				entry := fmt.Sprintf(" %d", vertex+1)
-				writer.WriteString(entry)
+	return writer.Flush()
+}
+
+// Write a face by index.
+func (w *OBJWriter) writeFace(writer *bufio.Writer, index int) error {
+	if len(w.faceMaterials) != 0 {
+		if material := w.faceMaterials[index]; material != -1 && material != w.material {
+			line := fmt.Sprintf("usemtl %s\n", w.materials[material])
+			if _, err := writer.WriteString(line); err != nil {
+				return err
+			}
+			w.material = material
+		}
+	}
+
+	if len(w.smoothingGroups) != 0 {
+		if group := w.smoothingGroups[index]; group != w.smoothingGroup {
+			line := fmt.Sprintf("s %d\n", group)
+
+			if group == 0 {
+				line = "s off\n"
 			}
 
-			writer.WriteString("\n")
+			if _, err := writer.WriteString(line); err != nil {
+				return err
+			}
+			w.smoothingGroup = group
 		}
 	}
 
-	return writer.Flush()
+	writer.WriteString("f")
+
+	for _, vertex := range w.faces[index] {
+		entry := fmt.Sprintf(" %d", vertex+1)
+		writer.WriteString(entry)
+	}
+
+	_, err := writer.WriteString("\n")
+	return err
 }
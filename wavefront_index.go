@@ -0,0 +1,433 @@
+package meshx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	// Extension of the index sidecar of an OBJ file (appended to its path).
+	OBJIndexExtension = ".idx"
+
+	// Number of vertices between the offsets of the vertex blocks.
+	OBJIndexVertexStride = 1 << 12
+)
+
+var (
+	ErrOBJIndexCompressed = errors.New("cannot index a compressed OBJ file")
+	ErrOBJIndexPatch      = errors.New("patch not indexed")
+	ErrOBJIndexInvalid    = errors.New("invalid OBJ index")
+)
+
+// Index of an OBJ file locating the faces of each patch (g statement) and
+// blocks of vertices, so a subset of the patches may be read without reading
+// the whole file (see ReadOBJPatches). Faces outside a group are not indexed.
+type OBJIndex struct {
+	Size              int64           `json:"size"`
+	ModTime           int64           `json:"modTime"`
+	NumberOfVertices  int             `json:"numberOfVertices"`
+	VertexStride      int             `json:"vertexStride"`
+	VertexBlocks      []int64         `json:"vertexBlocks"`
+	MaterialLibraries []string        `json:"materialLibraries"`
+	Patches           []OBJIndexPatch `json:"patches"`
+}
+
+// Patch of an OBJIndex. A group repeated in the file has a range for each.
+type OBJIndexPatch struct {
+	Name          string          `json:"name"`
+	NumberOfFaces int             `json:"numberOfFaces"`
+	Ranges        []OBJIndexRange `json:"ranges"`
+}
+
+// Byte range of the lines of a patch following a g statement with the line
+// number of its first line and the state (material, smoothing group and
+// object) at its start.
+type OBJIndexRange struct {
+	Start          int64  `json:"start"`
+	End            int64  `json:"end"`
+	Line           int    `json:"line"`
+	Material       string `json:"material,omitempty"`
+	SmoothingGroup int    `json:"smoothingGroup,omitempty"`
+	Object         string `json:"object,omitempty"`
+}
+
+// Build the OBJIndex of an uncompressed OBJ file.
+func BuildOBJIndex(reader io.Reader) (*OBJIndex, error) {
+	buffered := bufio.NewReader(reader)
+
+	if data, _ := buffered.Peek(2); len(data) == 2 && data[0] == 31 && data[1] == 139 {
+		return nil, ErrOBJIndexCompressed
+	}
+
+	index := &OBJIndex{
+		VertexStride:      OBJIndexVertexStride,
+		VertexBlocks:      make([]int64, 0),
+		MaterialLibraries: make([]string, 0),
+		Patches:           make([]OBJIndexPatch, 0),
+	}
+
+	indexPatches := make(map[string]int)
+	patch := -1
+	material, object := "", ""
+	smoothingGroup := 0
+	offset := int64(0)
+
+	closeRange := func(end int64) {
+		if patch != -1 {
+			ranges := index.Patches[patch].Ranges
+			ranges[len(ranges)-1].End = end
+		}
+	}
+
+	for line := 1; ; line++ {
+		data, err := buffered.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		} else if len(data) == 0 {
+			break
+		}
+
+		start := offset
+		offset += int64(len(data))
+		data = bytes.TrimSpace(data)
+		fields := bytes.Fields(data)
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch prefix := string(fields[0]); prefix {
+		case PrefixVertex:
+			if index.NumberOfVertices%index.VertexStride == 0 {
+				index.VertexBlocks = append(index.VertexBlocks, start)
+			}
+
+			index.NumberOfVertices++
+		case PrefixFace:
+			if patch != -1 {
+				index.Patches[patch].NumberOfFaces++
+			}
+		case PrefixGroup:
+			closeRange(start)
+			name := string(bytes.TrimSpace(data[len(prefix):]))
+
+			if i, ok := indexPatches[name]; ok {
+				patch = i
+			} else {
+				patch = len(index.Patches)
+				indexPatches[name] = patch
+				index.Patches = append(index.Patches, OBJIndexPatch{Name: name})
+			}
+
+			index.Patches[patch].Ranges = append(index.Patches[patch].Ranges, OBJIndexRange{
+				Start:          offset,
+				Line:           line + 1,
+				Material:       material,
+				SmoothingGroup: smoothingGroup,
+				Object:         object,
+			})
+		case PrefixObject:
+			object = string(bytes.TrimSpace(data[len(prefix):]))
+		case PrefixUseMaterial:
+			material = string(bytes.TrimSpace(data[len(prefix):]))
+		case PrefixSmoothingGroup:
+			var r OBJReader
+
+			if err := r.parseSmoothingGroup(data); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+
+			smoothingGroup = r.smoothingGroup
+		case PrefixMaterialLibrary:
+			for _, field := range fields[1:] {
+				index.MaterialLibraries = append(index.MaterialLibraries, string(field))
+			}
+		}
+	}
+
+	closeRange(offset)
+
+	return index, nil
+}
+
+// Read an OBJIndex written as JSON.
+func ReadOBJIndex(reader io.Reader) (*OBJIndex, error) {
+	var index OBJIndex
+
+	if err := json.NewDecoder(reader).Decode(&index); err != nil {
+		return nil, err
+	}
+
+	if index.VertexStride <= 0 || len(index.VertexBlocks) != (index.NumberOfVertices+index.VertexStride-1)/index.VertexStride {
+		return nil, ErrOBJIndexInvalid
+	}
+
+	return &index, nil
+}
+
+// Write the OBJIndex as JSON.
+func (i *OBJIndex) Write(writer io.Writer) error {
+	return json.NewEncoder(writer).Encode(i)
+}
+
+// Get the names of the patches indexed.
+func (i *OBJIndex) GetPatchNames() []string {
+	names := make([]string, len(i.Patches))
+
+	for j, patch := range i.Patches {
+		names[j] = patch.Name
+	}
+
+	return names
+}
+
+// Get the OBJIndex of an OBJ file path from its sidecar (the path with the
+// OBJIndexExtension). The index is built and the sidecar written if missing
+// or older than the file. Failing to write the sidecar is not an error.
+func IndexOBJFromPath(path string) (*OBJIndex, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if sidecar, err := os.Open(path + OBJIndexExtension); err == nil {
+		index, err := ReadOBJIndex(sidecar)
+		sidecar.Close()
+
+		if err == nil && index.Size == info.Size() && index.ModTime == info.ModTime().UnixNano() {
+			return index, nil
+		}
+	}
+
+	index, err := BuildOBJIndex(file)
+	if err != nil {
+		return nil, err
+	}
+
+	index.Size = info.Size()
+	index.ModTime = info.ModTime().UnixNano()
+
+	if sidecar, err := os.Create(path + OBJIndexExtension); err == nil {
+		err = index.Write(sidecar)
+		sidecar.Close()
+
+		if err != nil {
+			os.Remove(path + OBJIndexExtension)
+		}
+	}
+
+	return index, nil
+}
+
+// Read the faces of a subset of the patches (by name) of an OBJ file and
+// only the vertices they reference. The patches of the reader are the names
+// in the order given and the vertices are in the order of the file.
+// Polylines and directives are not read.
+func ReadOBJPatches(reader io.ReadSeeker, index *OBJIndex, names ...string) (*OBJReader, error) {
+	objReader := NewOBJReader(reader)
+
+	if err := objReader.readPatches(reader, index, names); err != nil {
+		return nil, err
+	}
+
+	return objReader, nil
+}
+
+// Read the faces of a subset of the patches (by name) of an OBJ file path
+// using its index sidecar (see IndexOBJFromPath and ReadOBJPatches). The
+// material libraries are read relative to its directory.
+func ReadOBJPatchesFromPath(path string, names ...string) (*OBJReader, error) {
+	index, err := IndexOBJFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	objReader, err := ReadOBJPatches(file, index, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	objReader.materialLibraries = index.MaterialLibraries
+
+	if err := objReader.readMaterialLibraries(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	return objReader, nil
+}
+
+// Read the faces of the patches by name followed by the vertices they
+// reference.
+func (r *OBJReader) readPatches(reader io.ReadSeeker, index *OBJIndex, names []string) error {
+	indexPatches := make(map[string]int)
+	indexObjects := make(map[string]int)
+
+	for i, patch := range index.Patches {
+		indexPatches[patch.Name] = i
+	}
+
+	for _, name := range names {
+		i, ok := indexPatches[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrOBJIndexPatch, name)
+		}
+
+		if slices.Contains(r.patches, name) {
+			continue
+		}
+
+		r.patches = append(r.patches, name)
+
+		for _, lines := range index.Patches[i].Ranges {
+			if err := r.readPatchRange(reader, lines, indexObjects); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range r.faceOffsets {
+		for _, vertex := range r.getFace(i) {
+			if vertex >= index.NumberOfVertices {
+				return fmt.Errorf("line %d: %w", r.faceLines[i], ErrInvalidFace)
+			}
+		}
+	}
+
+	return r.readIndexedVertices(reader, index)
+}
+
+// Read the faces of a range of lines of the last patch. The objects are
+// merged by name.
+func (r *OBJReader) readPatchRange(reader io.ReadSeeker, lines OBJIndexRange, indexObjects map[string]int) error {
+	if _, err := reader.Seek(lines.Start, io.SeekStart); err != nil {
+		return err
+	}
+
+	r.material = -1
+	r.smoothingGroup = lines.SmoothingGroup
+	object := r.getIndexedObject(lines.Object, indexObjects)
+
+	if lines.Material != "" {
+		r.parseUseMaterial([]byte(PrefixUseMaterial + " " + lines.Material))
+	}
+
+	buffered := bufio.NewReader(io.LimitReader(reader, lines.End-lines.Start))
+
+	for line := lines.Line; ; line++ {
+		data, err := buffered.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		} else if len(data) == 0 {
+			break
+		}
+
+		data = bytes.TrimSpace(data)
+		prefix := r.parsePrefix(data)
+
+		switch string(prefix) {
+		case PrefixFace:
+			if err = r.parseFace(data, line); err == nil {
+				r.faceObjects[len(r.faceObjects)-1] = object
+			}
+		case PrefixObject:
+			object = r.getIndexedObject(string(bytes.TrimSpace(data[len(prefix):])), indexObjects)
+		case PrefixUseMaterial:
+			err = r.parseUseMaterial(data)
+		case PrefixSmoothingGroup:
+			err = r.parseSmoothingGroup(data)
+		}
+
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	return nil
+}
+
+// Get an object by name, added if new. The object is -1 if unnamed.
+func (r *OBJReader) getIndexedObject(name string, indexObjects map[string]int) int {
+	if name == "" {
+		return -1
+	}
+
+	if object, ok := indexObjects[name]; ok {
+		return object
+	}
+
+	indexObjects[name] = len(r.objects)
+	r.objects = append(r.objects, name)
+
+	return len(r.objects) - 1
+}
+
+// Read the vertices referenced by the faces from the blocks of the index and
+// renumber the faces.
+func (r *OBJReader) readIndexedVertices(reader io.ReadSeeker, index *OBJIndex) error {
+	vertices := slices.Clone(r.faces)
+	slices.Sort(vertices)
+	vertices = slices.Compact(vertices)
+	indexVertices := make(map[int]int, len(vertices))
+
+	for k := 0; k < len(vertices); {
+		block := vertices[k] / index.VertexStride
+
+		if _, err := reader.Seek(index.VertexBlocks[block], io.SeekStart); err != nil {
+			return err
+		}
+
+		buffered := bufio.NewReader(reader)
+		vertex := block * index.VertexStride
+
+		for k < len(vertices) && vertices[k]/index.VertexStride == block {
+			data, err := buffered.ReadBytes('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return err
+			} else if len(data) == 0 {
+				return fmt.Errorf("vertex %d: %w", vertices[k]+1, ErrInvalidVertex)
+			}
+
+			data = bytes.TrimSpace(data)
+
+			if string(r.parsePrefix(data)) != PrefixVertex {
+				continue
+			}
+
+			if vertex == vertices[k] {
+				if err := r.parseVertex(data); err != nil {
+					return fmt.Errorf("vertex %d: %w", vertex+1, err)
+				}
+
+				indexVertices[vertex] = len(r.vertices) - 1
+				k++
+			}
+
+			vertex++
+		}
+	}
+
+	for i, vertex := range r.faces {
+		r.faces[i] = indexVertices[vertex]
+	}
+
+	return nil
+}
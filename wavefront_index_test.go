@@ -0,0 +1,146 @@
+package meshx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Write an OBJ grid of quads in patches with materials, smoothing groups and
+// objects, with more vertices than a block of the index.
+func writeTestIndexOBJ(t *testing.T) string {
+	var builder strings.Builder
+	n := 80
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			fmt.Fprintf(&builder, "v %d %d 0\n", i, j)
+		}
+	}
+
+	writeQuads := func(rows ...int) {
+		for _, i := range rows {
+			for j := 0; j < n-1; j++ {
+				v := i*n + j + 1
+				fmt.Fprintf(&builder, "f %d %d %d %d\n", v, v+n, v+n+1, v+1)
+			}
+		}
+	}
+
+	builder.WriteString("g bottom\nusemtl steel\ns 1\n")
+	writeQuads(0, 1)
+	builder.WriteString("o part\ng middle\n")
+	writeQuads(40)
+	builder.WriteString("usemtl paint\ns off\ng top\n")
+	writeQuads(77, 78)
+	builder.WriteString("g bottom\n")
+	builder.WriteString("f 1 2 3\n")
+
+	path := filepath.Join(t.TempDir(), "grid.obj")
+	assert.Empty(t, os.WriteFile(path, []byte(builder.String()), 0644))
+
+	return path
+}
+
+// Test building the index of an OBJ file.
+func TestBuildOBJIndex(t *testing.T) {
+	path := writeTestIndexOBJ(t)
+	file, err := os.Open(path)
+	assert.Empty(t, err)
+	defer file.Close()
+
+	index, err := BuildOBJIndex(file)
+	assert.Empty(t, err)
+	assert.Equal(t, 6400, index.NumberOfVertices)
+	assert.Equal(t, 2, len(index.VertexBlocks))
+	assert.Equal(t, []string{"bottom", "middle", "top"}, index.GetPatchNames())
+	assert.Equal(t, []int{2*79 + 1, 79, 2 * 79}, []int{
+		index.Patches[0].NumberOfFaces,
+		index.Patches[1].NumberOfFaces,
+		index.Patches[2].NumberOfFaces,
+	})
+
+	assert.Equal(t, 2, len(index.Patches[0].Ranges))
+	assert.Equal(t, "steel", index.Patches[1].Ranges[0].Material)
+	assert.Equal(t, 1, index.Patches[1].Ranges[0].SmoothingGroup)
+	assert.Equal(t, "part", index.Patches[1].Ranges[0].Object)
+	assert.Equal(t, "paint", index.Patches[0].Ranges[1].Material)
+	assert.Equal(t, 0, index.Patches[0].Ranges[1].SmoothingGroup)
+
+	var buffer bytes.Buffer
+	assert.Empty(t, index.Write(&buffer))
+
+	read, err := ReadOBJIndex(&buffer)
+	assert.Empty(t, err)
+	assert.Equal(t, index, read)
+}
+
+// Test reading a subset of the patches of an OBJ file.
+func TestReadOBJPatchesFromPath(t *testing.T) {
+	path := writeTestIndexOBJ(t)
+
+	full, err := ReadOBJFromPath(path)
+	assert.Empty(t, err)
+
+	reader, err := ReadOBJPatchesFromPath(path, "top", "bottom")
+	assert.Empty(t, err)
+	assert.FileExists(t, path+OBJIndexExtension)
+
+	assert.Equal(t, 2, reader.GetNumberOfPatches())
+	assert.Equal(t, "top", reader.GetPatch(0))
+	assert.Equal(t, "bottom", reader.GetPatch(1))
+	assert.Equal(t, 2*79+2*79+1, reader.GetNumberOfFaces())
+	assert.Equal(t, 6*80, reader.GetNumberOfVertices())
+
+	// Match the faces read against the faces of the full file by line.
+	indexLines := make(map[int]int)
+
+	for i := 0; i < full.GetNumberOfFaces(); i++ {
+		indexLines[full.faceLines[i]] = i
+	}
+
+	for i := 0; i < reader.GetNumberOfFaces(); i++ {
+		j, ok := indexLines[reader.faceLines[i]]
+		assert.True(t, ok)
+		assert.Equal(t, full.GetPatch(full.GetFacePatch(j)), reader.GetPatch(reader.GetFacePatch(i)))
+		assert.Equal(t, full.GetFaceSmoothingGroup(j), reader.GetFaceSmoothingGroup(i))
+		assert.Equal(t, full.GetMaterial(full.GetFaceMaterial(j)).Name, reader.GetMaterial(reader.GetFaceMaterial(i)).Name)
+		assert.Equal(t, full.GetFaceObject(j) == -1, reader.GetFaceObject(i) == -1)
+
+		face, fullFace := reader.GetFace(i), full.GetFace(j)
+		assert.Equal(t, len(fullFace), len(face))
+
+		for k := range face {
+			assert.Equal(t, full.GetVertex(fullFace[k]), reader.GetVertex(face[k]))
+		}
+	}
+
+	// The sidecar is reused and the objects merged by name.
+	reader, err = ReadOBJPatchesFromPath(path, "middle", "top")
+	assert.Empty(t, err)
+	assert.Equal(t, []string{"part"}, reader.objects)
+
+	for i := 0; i < reader.GetNumberOfFaces(); i++ {
+		assert.Equal(t, 0, reader.GetFaceObject(i))
+	}
+
+	_, err = ReadOBJPatchesFromPath(path, "side")
+	assert.ErrorIs(t, err, ErrOBJIndexPatch)
+}
+
+// Test the index of a compressed OBJ file is an error.
+func TestBuildOBJIndexCompressed(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	writer.Write([]byte("v 0 0 0\n"))
+	writer.Close()
+
+	_, err := BuildOBJIndex(&buffer)
+	assert.ErrorIs(t, err, ErrOBJIndexCompressed)
+}
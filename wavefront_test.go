@@ -3,6 +3,10 @@ package meshx
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -30,6 +34,140 @@ func TestReadOBJFromPathGZIP(t *testing.T) {
 	assert.Equal(t, mesh.GetNumberOfPatches(), 0)
 }
 
+// Read an OBJ file from path with progress and cancellation.
+func TestReadOBJFromPathContext(t *testing.T) {
+	path := "testdata/box.obj"
+	info, err := os.Stat(path)
+	assert.Empty(t, err)
+
+	var completed, total int64
+	progress := func(c, t int64) { completed, total = c, t }
+
+	mesh, err := ReadOBJFromPathContext(context.Background(), path, progress)
+	assert.Empty(t, err)
+	assert.Equal(t, mesh.GetNumberOfFaces(), 12)
+	assert.Equal(t, info.Size(), completed)
+	assert.Equal(t, info.Size(), total)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = ReadOBJFromPathContext(ctx, path, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// Read the polylines (l records) of an OBJ file.
+func TestReadOBJLines(t *testing.T) {
+	data := "v 0 0 0\nv 1 0 0\nv 1 1 0\nl 1 2 3 1\nl 2/2 3/3\n"
+	reader := NewOBJReader(bytes.NewBufferString(data))
+
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, 2, reader.GetNumberOfLines())
+	assert.Equal(t, []int{0, 1, 2, 0}, reader.GetLine(0))
+	assert.Equal(t, []int{1, 2}, reader.GetLine(1))
+	assert.Equal(t, 0, reader.GetNumberOfFaces())
+
+	reader = NewOBJReader(bytes.NewBufferString("v 0 0 0\nl 1\n"))
+	assert.EqualError(t, reader.Read(), "line 2: invalid line")
+}
+
+// Read and write an OBJ file keeping the directives not otherwise handled.
+func TestOBJKeepDirectives(t *testing.T) {
+	data := "# exported\nmtllib a.mtl\no part\nv 0 0 0\nv 1 0 0\nvt 0 0\nv 0 1 0\ns 1\nf 1 2 3\n"
+	directives := []string{"# exported", "mtllib a.mtl"}
+
+	reader := NewOBJReader(bytes.NewBufferString(data))
+	assert.Empty(t, reader.Read())
+	assert.Empty(t, reader.GetDirectives())
+
+	reader = NewOBJReader(bytes.NewBufferString(data))
+	reader.SetKeepDirectives(true)
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, directives, reader.GetDirectives())
+	assert.Equal(t, 1, reader.GetNumberOfFaces())
+
+	var buffer bytes.Buffer
+	writer := NewOBJWriter(&buffer)
+	writer.SetVertices([]Vector{reader.GetVertex(0), reader.GetVertex(1), reader.GetVertex(2)})
+	writer.SetFaces([][]int{reader.GetFace(0)})
+	writer.SetDirectives(reader.GetDirectives())
+	assert.Empty(t, writer.Write())
+
+	reader = NewOBJReader(&buffer)
+	reader.SetKeepDirectives(true)
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, directives, reader.GetDirectives())
+	assert.Equal(t, []string{"a.mtl"}, reader.GetMaterialLibraries())
+}
+
+// Read and write an OBJ file with smoothing groups.
+func TestOBJSmoothingGroups(t *testing.T) {
+	data := "v 0 0 0\nv 1 0 0\nv 0 1 0\nv 1 1 0\nf 1 2 3\ns 1\nf 2 4 3\ns 2\nf 1 3 4\ns off\nf 1 4 2\n"
+	reader := NewOBJReader(bytes.NewBufferString(data))
+	assert.Empty(t, reader.Read())
+
+	groups := make([]int, reader.GetNumberOfFaces())
+
+	for i := range groups {
+		groups[i] = reader.GetFaceSmoothingGroup(i)
+	}
+
+	assert.Equal(t, []int{0, 1, 2, 0}, groups)
+
+	var buffer bytes.Buffer
+	writer := NewOBJWriter(&buffer)
+	writer.SetVertices([]Vector{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0}})
+	writer.SetFaces([][]int{{0, 1, 2}, {1, 3, 2}, {0, 2, 3}, {0, 3, 1}})
+	writer.SetFaceSmoothingGroups(groups)
+	assert.Empty(t, writer.Write())
+	assert.Contains(t, buffer.String(), "f 1 2 3\ns 1\nf 2 4 3\ns 2\nf 1 3 4\ns off\nf 1 4 2\n")
+
+	reader = NewOBJReader(bytes.NewBufferString("v 0 0 0\ns x\n"))
+	assert.ErrorIs(t, reader.Read(), ErrInvalidSmoothingGroup)
+}
+
+// Read an OBJ file with objects.
+func TestReadOBJObjects(t *testing.T) {
+	data := "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\no a\ng x\nf 1 2 3\no b\nf 1 3 2\no a\ng y\nf 2 3 1\n"
+	reader := NewOBJReader(bytes.NewBufferString(data))
+	assert.Empty(t, reader.Read())
+
+	assert.Equal(t, 3, reader.GetNumberOfObjects())
+	assert.Equal(t, "b", reader.GetObject(1))
+	assert.Equal(t, -1, reader.GetFaceObject(0))
+	assert.Equal(t, 2, reader.GetFaceObject(3))
+	assert.Equal(t, []int{1, 3}, reader.GetObjectFaces("a"))
+	assert.Equal(t, []int{1, 2, 3}, reader.GetObjectFaces("a", "b"))
+	assert.Equal(t, 2, reader.GetNumberOfPatches())
+	assert.Equal(t, 0, reader.GetFacePatch(2))
+
+	reader.SetObjectsAsPatches(true)
+	assert.Equal(t, 3, reader.GetNumberOfPatches())
+	assert.Equal(t, "b", reader.GetPatch(reader.GetFacePatch(2)))
+	assert.Equal(t, -1, reader.GetFacePatch(0))
+}
+
+// Read an OBJ file with faces referencing vertices that do not exist.
+func TestReadOBJInvalidFaces(t *testing.T) {
+	data := "v 0 0 0\nv 1 0 0\nv 0 1 0\ng a\nf 1 2 4\nf 1 2 3\ng b\nf 3 2 9\n"
+	reader := NewOBJReader(bytes.NewBufferString(data))
+	err := reader.Read()
+
+	assert.ErrorIs(t, err, ErrInvalidFace)
+	assert.EqualError(t, err, "line 5: invalid face")
+
+	reader = NewOBJReader(bytes.NewBufferString(data))
+	reader.SetSkipInvalidFaces(true)
+
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, []int{5, 8}, reader.GetSkippedFaces())
+	assert.Equal(t, 1, reader.GetNumberOfFaces())
+	assert.Equal(t, 3, reader.GetNumberOfFaceEdges())
+	assert.Equal(t, []int{0, 1, 2}, reader.GetFace(0))
+	assert.Equal(t, 0, reader.GetFacePatch(0))
+	assert.Equal(t, -1, reader.GetFaceMaterial(0))
+}
+
 // Read an OBJ file from path with mixed elements and patches.
 func TestReadOBJFromPathPatches(t *testing.T) {
 	path := "testdata/box.patches.obj"
@@ -101,3 +239,333 @@ func TestWriteOBJGZIP(t *testing.T) {
 	assert.Empty(t, err)
 	assert.Equal(t, expectedBuf.String(), writer.String())
 }
+
+// Write an OBJ file with faces without a patch.
+func TestWriteOBJMixedPatches(t *testing.T) {
+	vertices := []Vector{
+		NewVector(0, 0, 0),
+		NewVector(0, 1, 0),
+		NewVector(1, 1, 0),
+		NewVector(1, 0, 0),
+	}
+
+	faces := [][]int{
+		[]int{0, 1, 2},
+		[]int{0, 2, 3},
+	}
+
+	var expected string
+	expected += "v 0.000000 0.000000 0.000000\n"
+	expected += "v 0.000000 1.000000 0.000000\n"
+	expected += "v 1.000000 1.000000 0.000000\n"
+	expected += "v 1.000000 0.000000 0.000000\n"
+	expected += "f 1 3 4\n"
+	expected += "g top\n"
+	expected += "f 1 2 3\n"
+
+	var writer bytes.Buffer
+	objWriter := NewOBJWriter(&writer)
+	objWriter.SetVertices(vertices)
+	objWriter.SetFaces(faces)
+	objWriter.SetFacePatches([]int{0, -1})
+	objWriter.SetPatches([]string{"top"})
+
+	err := objWriter.Write()
+	assert.Empty(t, err)
+	assert.Equal(t, expected, writer.String())
+}
+
+// Read an OBJ file from path with materials and a material library.
+func TestReadOBJFromPathMaterials(t *testing.T) {
+	path := "testdata/box.materials.obj"
+	mesh, err := ReadOBJFromPath(path)
+
+	assert.Empty(t, err)
+	assert.Equal(t, []string{"box.materials.mtl"}, mesh.GetMaterialLibraries())
+	assert.Equal(t, 3, mesh.GetNumberOfMaterials())
+	assert.Equal(t, "red", mesh.GetMaterial(0).Name)
+	assert.Equal(t, "blue", mesh.GetMaterial(1).Name)
+	assert.Equal(t, "unused", mesh.GetMaterial(2).Name)
+	assert.Equal(t, []int{0, 0, 1, 1, 0, 0, 0}, mesh.faceMaterials)
+
+	texture, ok := mesh.GetMaterial(0).GetStatement("map_Kd")
+	assert.True(t, ok)
+	assert.Equal(t, "textures/red.png", texture)
+}
+
+// Read an OBJ file with a material library that cannot be found.
+func TestReadOBJMaterialsMissingLibrary(t *testing.T) {
+	var data string
+	data += "mtllib missing.mtl\n"
+	data += "v 0 0 0\n"
+	data += "v 0 1 0\n"
+	data += "v 1 1 0\n"
+	data += "f 1 2 3\n"
+	data += "usemtl red\n"
+	data += "f 1 3 2\n"
+
+	mesh := NewOBJReader(bytes.NewBufferString(data))
+	assert.Empty(t, mesh.Read())
+	assert.Empty(t, mesh.readMaterialLibraries(t.TempDir()))
+
+	assert.Equal(t, 1, mesh.GetNumberOfMaterials())
+	assert.Equal(t, Material{Name: "red"}, mesh.GetMaterial(0))
+	assert.Equal(t, -1, mesh.GetFaceMaterial(0))
+	assert.Equal(t, 0, mesh.GetFaceMaterial(1))
+}
+
+// Read the material libraries of an OBJ file from a file system.
+func TestReadOBJMaterialLibrariesFS(t *testing.T) {
+	file, err := os.Open("testdata/box.materials.obj")
+	assert.Empty(t, err)
+	defer file.Close()
+
+	mesh := NewOBJReader(file)
+	assert.Empty(t, mesh.Read())
+	assert.Empty(t, mesh.ReadMaterialLibrariesFS(os.DirFS("."), "testdata"))
+
+	assert.Equal(t, 3, mesh.GetNumberOfMaterials())
+	assert.Equal(t, "red", mesh.GetMaterial(0).Name)
+	assert.Equal(t, "unused", mesh.GetMaterial(2).Name)
+
+	mesh = NewOBJReader(bytes.NewBufferString("mtllib missing.mtl\nusemtl red\n"))
+	assert.Empty(t, mesh.Read())
+	assert.Empty(t, mesh.ReadMaterialLibrariesFS(os.DirFS("."), "testdata"))
+	assert.Equal(t, []Material{{Name: "red"}}, mesh.materials)
+}
+
+// Write an OBJ file with materials.
+func TestWriteOBJMaterials(t *testing.T) {
+	vertices := []Vector{
+		NewVector(0, 0, 0),
+		NewVector(0, 1, 0),
+		NewVector(1, 1, 0),
+	}
+
+	faces := [][]int{
+		[]int{0, 1, 2},
+		[]int{0, 2, 1},
+		[]int{1, 2, 0},
+	}
+
+	var expected string
+	expected += "mtllib mesh.mtl\n"
+	expected += "v 0.000000 0.000000 0.000000\n"
+	expected += "v 0.000000 1.000000 0.000000\n"
+	expected += "v 1.000000 1.000000 0.000000\n"
+	expected += "f 1 2 3\n"
+	expected += "usemtl red\n"
+	expected += "f 1 3 2\n"
+	expected += "f 2 3 1\n"
+
+	var buffer bytes.Buffer
+	writer := NewOBJWriter(&buffer)
+	writer.SetVertices(vertices)
+	writer.SetFaces(faces)
+	writer.SetFaceMaterials([]int{-1, 0, 0})
+	writer.SetMaterials([]string{"red"})
+	writer.SetMaterialLibrary("mesh.mtl")
+
+	assert.Empty(t, writer.Write())
+	assert.Equal(t, expected, buffer.String())
+}
+
+// Write and read an OBJ file with vertex colors.
+func TestWriteOBJVertexColors(t *testing.T) {
+	vertices := []Vector{
+		NewVector(0, 0, 0),
+		NewVector(0, 1, 0),
+		NewVector(1, 1, 0),
+	}
+
+	colors := []Color{
+		NewColor(255, 0, 0, 255),
+		NewColor(0, 255, 0, 255),
+		NewColor(0, 0, 255, 255),
+	}
+
+	var expected string
+	expected += "v 0.000000 0.000000 0.000000 1.000000 0.000000 0.000000\n"
+	expected += "v 0.000000 1.000000 0.000000 0.000000 1.000000 0.000000\n"
+	expected += "v 1.000000 1.000000 0.000000 0.000000 0.000000 1.000000\n"
+	expected += "f 1 2 3\n"
+
+	var buffer bytes.Buffer
+	writer := NewOBJWriter(&buffer)
+	writer.SetVertices(vertices)
+	writer.SetVertexColors(colors)
+	writer.SetFaces([][]int{[]int{0, 1, 2}})
+
+	assert.Empty(t, writer.Write())
+	assert.Equal(t, expected, buffer.String())
+
+	reader := NewOBJReader(&buffer)
+	assert.Empty(t, reader.Read())
+	assert.True(t, reader.HasVertexColors())
+
+	for i, color := range colors {
+		assert.Equal(t, color, reader.GetVertexColor(i))
+	}
+}
+
+// Read an OBJ file with translucent vertex colors.
+func TestReadOBJVertexColorsAlpha(t *testing.T) {
+	var data string
+	data += "v 0 0 0 1 0 0 0.5\n"
+	data += "v 0 1 0\n"
+	data += "v 1 1 0 0 0 1\n"
+	data += "f 1 2 3\n"
+
+	reader := NewOBJReader(bytes.NewBufferString(data))
+	assert.Empty(t, reader.Read())
+	assert.True(t, reader.HasVertexColors())
+	assert.Equal(t, NewColor(255, 0, 0, 128), reader.GetVertexColor(0))
+	assert.Equal(t, ColorWhite, reader.GetVertexColor(1))
+	assert.Equal(t, NewColor(0, 0, 255, 255), reader.GetVertexColor(2))
+
+	reader = NewOBJReader(bytes.NewBufferString("v 0 0 0 1 0\n"))
+	assert.Error(t, reader.Read())
+}
+
+// Read an OBJ file in millimeters of a left-handed system.
+func TestReadOBJConversion(t *testing.T) {
+	data := "v 0 0 0\nv 1000 0 0\nv 0 1000 0\nf 1 2 3\n"
+	reader := NewOBJReader(bytes.NewBufferString(data))
+	from := CoordinateSystem{Unit: UnitMillimeter, LeftHanded: true}
+	reader.SetConversion(NewConversion(from, CoordinateSystem{}))
+
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, NewVector(0, -1, 0), reader.GetVertex(2))
+	assert.Equal(t, []int{0, 2, 1}, reader.GetFace(0))
+}
+
+// Write an OBJ file in inches of a Y-up system.
+func TestWriteOBJConversion(t *testing.T) {
+	vertices := []Vector{
+		NewVector(0, 0, 0),
+		NewVector(0.0254, 0, 0),
+		NewVector(0, 0, 0.0254),
+	}
+
+	var expected string
+	expected += "v 0.000000 0.000000 0.000000\n"
+	expected += "v 1.000000 0.000000 0.000000\n"
+	expected += "v 0.000000 1.000000 0.000000\n"
+	expected += "f 1 2 3\n"
+
+	var writer bytes.Buffer
+	objWriter := NewOBJWriter(&writer)
+	objWriter.SetVertices(vertices)
+	objWriter.SetFaces([][]int{{0, 1, 2}})
+	objWriter.SetConversion(NewConversion(CoordinateSystem{}, CoordinateSystem{Unit: UnitInch, UpAxis: UpAxisY}))
+
+	err := objWriter.Write()
+	assert.Empty(t, err)
+	assert.Equal(t, expected, writer.String())
+	assert.Equal(t, NewVector(0.0254, 0, 0), vertices[1])
+}
+
+// Test writing and reading random meshes preserves them.
+func TestWriteOBJRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for range 100 {
+		mesh := newRandomTestMesh(rng)
+
+		var buffer bytes.Buffer
+		writer := NewOBJWriter(&buffer)
+		mesh.setWriter(writer)
+		assert.Empty(t, writer.Write())
+
+		// The coordinates are written with six decimals.
+		reader := NewOBJReader(&buffer)
+		assert.Empty(t, reader.Read())
+		mesh.assertReader(t, reader, 1e-6)
+	}
+}
+
+// Test writing and reading the coordinates with the shortest round trip
+// formatting preserves them exactly.
+func TestWriteOBJRoundTripExact(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for range 10 {
+		mesh := newRandomTestMesh(rng)
+
+		var buffer bytes.Buffer
+		writer := NewOBJWriter(&buffer)
+		writer.SetFloatFormat(FloatRoundTrip)
+		mesh.setWriter(writer)
+		assert.Empty(t, writer.Write())
+
+		reader := NewOBJReader(&buffer)
+		reader.SetCheckPrecision(true)
+		assert.Empty(t, reader.Read())
+		assert.Empty(t, reader.GetImpreciseVertices())
+
+		for i := range reader.GetNumberOfVertices() {
+			assert.Equal(t, mesh.vertices[i], reader.GetVertex(i))
+		}
+	}
+}
+
+// Test reading an OBJ file with coordinates with more digits than a float64.
+func TestReadOBJCheckPrecision(t *testing.T) {
+	data := "v 0 0 0\nv 0.12345678901234567890 1 1.00000000000000000001\nv 0.100000 1 0\nf 1 2 3\n"
+
+	reader := NewOBJReader(bytes.NewBufferString(data))
+	reader.SetCheckPrecision(true)
+	assert.Empty(t, reader.Read())
+	assert.Equal(t, []int{1}, reader.GetImpreciseVertices())
+
+	reader = NewOBJReader(bytes.NewBufferString(data))
+	assert.Empty(t, reader.Read())
+	assert.Empty(t, reader.GetImpreciseVertices())
+}
+
+// Read an OBJ file transforming the vertices while reading.
+func TestReadOBJWithTransform(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mesh.obj")
+	mesh := newRandomTestMesh(rand.New(rand.NewSource(1)))
+
+	var buffer bytes.Buffer
+	writer := NewOBJWriter(&buffer)
+	mesh.setWriter(writer)
+	assert.Empty(t, writer.Write())
+	assert.Empty(t, os.WriteFile(path, buffer.Bytes(), 0644))
+
+	for _, matrix := range testTransforms {
+		reader, err := ReadOBJWithTransform(path, matrix)
+		assert.Empty(t, err)
+		mesh.transform(matrix).assertReader(t, reader, 1e-6)
+	}
+}
+
+// Fuzz reading an OBJ file. A file read without an error must be accessible
+// without a panic.
+func FuzzOBJReader(f *testing.F) {
+	for _, path := range []string{"testdata/box.obj", "testdata/box.patches.obj", "testdata/box.materials.obj"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(data)
+	}
+
+	f.Add([]byte("v 0 0 0 1 0 0\nv 1 0 0\nv 0 1 0\nl 1 2\ng a\nusemtl m\nf 1/1/1 2//2 3\n"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		reader := NewOBJReader(bytes.NewReader(data))
+
+		if reader.Read() != nil {
+			return
+		}
+
+		checkTestMeshReader(t, reader, true)
+
+		for i := range reader.GetNumberOfLines() {
+			reader.GetLine(i)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package meshx
+
+import (
+	"math"
+)
+
+// Compute the generalized winding number of a point with respect to a set of
+// triangles. The triangles need not be closed, manifold, or free of
+// self-intersections. For a closed and outward oriented surface, the winding
+// number is one inside and zero outside.
+func WindingNumber(triangles []Triangle, point Vector) float64 {
+	var solidAngle float64
+
+	for _, triangle := range triangles {
+		solidAngle += triangle.SolidAngle(point)
+	}
+
+	return solidAngle / (4 * math.Pi)
+}
@@ -0,0 +1,35 @@
+package meshx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Outward oriented unit tetrahedron.
+var windingTestTriangles = []Triangle{
+	NewTriangle(NewVector(0, 0, 0), NewVector(0, 1, 0), NewVector(1, 0, 0)),
+	NewTriangle(NewVector(0, 0, 0), NewVector(1, 0, 0), NewVector(0, 0, 1)),
+	NewTriangle(NewVector(0, 0, 0), NewVector(0, 0, 1), NewVector(0, 1, 0)),
+	NewTriangle(NewVector(1, 0, 0), NewVector(0, 1, 0), NewVector(0, 0, 1)),
+}
+
+// Test the winding number of a point inside a closed surface.
+func TestWindingNumberInside(t *testing.T) {
+	point := NewVector(0.1, 0.2, 0.3)
+	assert.InDelta(t, 1.0, WindingNumber(windingTestTriangles, point), 1e-12)
+}
+
+// Test the winding number of a point outside a closed surface.
+func TestWindingNumberOutside(t *testing.T) {
+	point := NewVector(1, 1, 1)
+	assert.InDelta(t, 0.0, WindingNumber(windingTestTriangles, point), 1e-12)
+}
+
+// Test the winding number of a point inside an open surface.
+func TestWindingNumberOpen(t *testing.T) {
+	point := NewVector(0.1, 0.2, 0.3)
+	value := WindingNumber(windingTestTriangles[:3], point)
+	assert.Greater(t, value, 0.5)
+	assert.Less(t, value, 1.0)
+}